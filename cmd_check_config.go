@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lonegunmanb/terraform-provider-azurerm-index/pkg"
+)
+
+// runCheckConfigCommand implements the `check-config` subcommand, returning the process exit code
+func runCheckConfigCommand(args []string) int {
+	flags := flag.NewFlagSet("check-config", flag.ExitOnError)
+	configDir := flags.String("config", ".", "Terraform module directory containing the .tf files to check")
+	indexDir := flags.String("index", "./index", "Index directory generated with the json format")
+	flags.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, `Usage of %s check-config:
+
+Statically checks the resource and data blocks of a Terraform module against
+the schemas extracted into an index, without running Terraform or the provider.
+Unknown resource types, unknown attributes and missing required attributes are
+errors and make the command exit non-zero, deprecated resources and attributes
+are warnings. Blocks of other providers and nested blocks are not checked.
+
+Flags:
+  -config string
+        Terraform module directory containing the .tf files to check (default ".")
+  -index string
+        Index directory generated with the json format (default "./index")
+
+Example:
+  %s check-config -config ./module -index ./index
+`, os.Args[0], os.Args[0])
+	}
+	_ = flags.Parse(args)
+
+	fmt.Printf("🔍 Checking %s against index %s\n", *configDir, *indexDir)
+	report, err := pkg.CheckConfigDir(*configDir, *indexDir)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Error checking configuration: %v\n", err)
+		return 1
+	}
+
+	for _, problem := range report.Problems {
+		icon := "❌"
+		if problem.Severity == pkg.ConfigSeverityWarning {
+			icon = "⚠️"
+		}
+		location := fmt.Sprintf("%s:%d", problem.File, problem.Line)
+		if problem.TerraformType != "" {
+			location = fmt.Sprintf("%s %s.%s", location, problem.TerraformType, problem.Name)
+		}
+		fmt.Printf("  %s %s: %s\n", icon, location, problem.Message)
+	}
+	errors := report.Errors()
+	warnings := len(report.Problems) - errors
+	if errors > 0 {
+		fmt.Printf("\n❌ %d errors and %d warnings in %d blocks\n", errors, warnings, report.Blocks)
+		return 1
+	}
+	fmt.Printf("\n🎉 %d blocks checked, %d warnings\n", report.Blocks, warnings)
+	return 0
+}
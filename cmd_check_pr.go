@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lonegunmanb/terraform-provider-azurerm-index/pkg"
+)
+
+// runCheckPRCommand implements the `check-pr` subcommand, returning the process exit code
+func runCheckPRCommand(args []string) int {
+	flags := flag.NewFlagSet("check-pr", flag.ExitOnError)
+	providerPath := flags.String("provider-path", ".", "Path to the terraform-provider-azurerm checkout of the pull request")
+	packagePath := flags.String("package-path", "github.com/hashicorp/terraform-provider-azurerm", "Base package path for the provider")
+	baseRef := flags.String("base-ref", "origin/main", "Git ref the pull request is compared against")
+	services := flags.String("services", "", "Comma separated services to check instead of the services touched by the diff")
+	flags.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, `Usage of %s check-pr:
+
+Runs inside the provider repository's CI: scans only the services touched by the
+pull request and validates their registrations (type names, duplicates, missing
+ResourceType methods). Problems are printed as GitHub Actions error annotations
+and make the command exit non-zero.
+
+Flags:
+  -provider-path string
+        Path to the terraform-provider-azurerm checkout of the pull request (default ".")
+  -package-path string
+        Base package path for the provider (default "github.com/hashicorp/terraform-provider-azurerm")
+  -base-ref string
+        Git ref the pull request is compared against, must be fetched (default "origin/main")
+  -services string
+        Comma separated services to check instead of the services touched by the diff
+
+Example:
+  %s check-pr -provider-path . -base-ref origin/main
+`, os.Args[0], os.Args[0])
+	}
+	_ = flags.Parse(args)
+
+	var serviceNames []string
+	if *services != "" {
+		serviceNames = strings.Split(*services, ",")
+	} else {
+		changed, err := pkg.ChangedServices(*providerPath, *baseRef)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Error detecting changed services: %v\n", err)
+			return 1
+		}
+		serviceNames = changed
+	}
+	if len(serviceNames) == 0 {
+		fmt.Printf("✅ No services touched, nothing to check\n")
+		return 0
+	}
+
+	fmt.Printf("🔍 Checking registrations of %s\n", strings.Join(serviceNames, ", "))
+	issues, err := pkg.RunRegistrationCheck(*providerPath, *packagePath, serviceNames, nil)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Error checking registrations: %v\n", err)
+		return 1
+	}
+
+	for _, issue := range issues {
+		fmt.Println(issue.Annotation())
+	}
+	if len(issues) > 0 {
+		fmt.Printf("\n❌ %d registration problems found\n", len(issues))
+		return 1
+	}
+	fmt.Printf("\n🎉 Registrations of %d services are consistent\n", len(serviceNames))
+	return 0
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lonegunmanb/terraform-provider-azurerm-index/pkg"
+)
+
+// runE2ECommand implements the `e2e` subcommand, returning the process exit code
+func runE2ECommand(args []string) int {
+	flags := flag.NewFlagSet("e2e", flag.ExitOnError)
+	providerPath := flags.String("provider-path", "", "Path to a terraform-provider-azurerm checkout (required)")
+	packagePath := flags.String("package-path", "github.com/hashicorp/terraform-provider-azurerm", "Base package path for the provider")
+	flags.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, `Usage of %s e2e:
+
+Scans a whitelist of well-known services in a real provider checkout and verifies
+the expected resources and data sources are extracted.
+
+Flags:
+  -provider-path string
+        Path to a terraform-provider-azurerm checkout (required)
+  -package-path string
+        Base package path for the provider (default "github.com/hashicorp/terraform-provider-azurerm")
+
+Example:
+  %s e2e -provider-path ./tmp/terraform-provider-azurerm
+`, os.Args[0], os.Args[0])
+	}
+	_ = flags.Parse(args)
+
+	if *providerPath == "" {
+		_, _ = fmt.Fprintf(os.Stderr, "Error: -provider-path is required\n\n")
+		flags.Usage()
+		return 1
+	}
+
+	fmt.Printf("🧪 Running e2e checks against %s\n", *providerPath)
+	result, err := pkg.RunE2E(*providerPath, *packagePath, pkg.DefaultE2EExpectations, nil)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Error running e2e checks: %v\n", err)
+		return 1
+	}
+
+	for _, check := range result.Checks {
+		status := "✅"
+		if !check.Found {
+			status = "❌"
+		}
+		fmt.Printf("  %s %s %s (service %s)\n", status, check.Kind, check.TerraformType, check.Service)
+	}
+
+	if failed := result.Failed(); len(failed) > 0 {
+		fmt.Printf("\n❌ %d of %d e2e checks failed\n", len(failed), len(result.Checks))
+		return 1
+	}
+	fmt.Printf("\n🎉 All %d e2e checks passed\n", len(result.Checks))
+	return 0
+}
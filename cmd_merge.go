@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lonegunmanb/terraform-provider-azurerm-index/pkg"
+)
+
+// runMergeCommand implements the `merge` subcommand, returning the process exit code
+func runMergeCommand(args []string) int {
+	flags := flag.NewFlagSet("merge", flag.ExitOnError)
+	outputDir := flags.String("output", "", "Output directory of the merged index (required)")
+	providerName := flags.String("provider-name", pkg.DefaultProviderName, "Provider name used to derive the main index file name")
+	indexName := flags.String("index-name", "", "Main index file name (default terraform-provider-<provider-name>-index.json)")
+	flags.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, `Usage of %s merge [flags] <index dir>...:
+
+Merges partial indexes generated with the json format, such as per-service shards
+generated on different CI workers, into one index. Indexes must have the same
+version. A service or Terraform type found in more than one index is a conflict,
+nothing is written then.
+
+Flags:
+  -output string
+        Output directory of the merged index (required)
+  -provider-name string
+        Provider name used to derive the main index file name (default "azurerm")
+  -index-name string
+        Main index file name of all indexes (default "terraform-provider-<provider-name>-index.json")
+
+Example:
+  %s merge -output ./index ./shards/compute ./shards/keyvault ./shards/network
+`, os.Args[0], os.Args[0])
+	}
+	_ = flags.Parse(args)
+
+	if *outputDir == "" || flags.NArg() == 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "Error: -output and at least one index directory are required\n\n")
+		flags.Usage()
+		return 1
+	}
+
+	fmt.Printf("🧩 Merging %d indexes into %s\n", flags.NArg(), *outputDir)
+	index, err := pkg.MergeIndexDirs(flags.Args(), *outputDir, pkg.OutputConfig{ProviderName: *providerName, IndexFileName: *indexName})
+	var conflictsErr *pkg.MergeConflictsError
+	if errors.As(err, &conflictsErr) {
+		for _, conflict := range conflictsErr.Conflicts {
+			fmt.Printf("  ❌ %s %s found in %s\n", conflict.Kind, conflict.Name, strings.Join(conflict.Dirs, ", "))
+		}
+		fmt.Printf("\n❌ %d conflicts between the indexes, nothing written\n", len(conflictsErr.Conflicts))
+		return 1
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Error merging indexes: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("\n🎉 Merged %d services: %d resources, %d data sources\n",
+		index.Statistics.ServiceCount, index.Statistics.TotalResources, index.Statistics.TotalDataSources)
+	return 0
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/lonegunmanb/terraform-provider-azurerm-index/pkg/indexproxy"
+)
+
+// runProxyCommand implements the `proxy` subcommand, returning the process exit code
+func runProxyCommand(args []string) int {
+	flags := flag.NewFlagSet("proxy", flag.ExitOnError)
+	upstream := flags.String("upstream", "", "Base URL of the published index, may contain {version} (required)")
+	version := flags.String("version", "", "Provider version replacing {version} in -upstream")
+	cacheDir := flags.String("cache-dir", "./index-cache", "Directory the fetched files are cached in")
+	listen := flags.String("listen", "127.0.0.1:8080", "Address to serve the index on")
+	maxAge := flags.Duration("max-age", 0, "Serve cached files younger than this without fetching them again")
+	retries := flags.Int("retries", indexproxy.DefaultRetries, "Retries of a failed fetch before falling back to the cache")
+	flags.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, `Usage of %s proxy:
+
+Serves a published index over localhost HTTP, so AI agents and other tools can
+be pointed at the proxy instead of the published URL. Fetched files are cached
+on disk, failed fetches are retried with backoff, and when the published index
+can't be reached the cached file is served instead. The X-Index-Cache response
+header reports HIT, MISS or STALE.
+
+Flags:
+  -upstream string
+        Base URL of the published index, may contain {version} (required)
+  -version string
+        Provider version replacing {version} in -upstream
+  -cache-dir string
+        Directory the fetched files are cached in (default "./index-cache")
+  -listen string
+        Address to serve the index on (default "127.0.0.1:8080")
+  -max-age duration
+        Serve cached files younger than this without fetching them again (default 0, always fetch)
+  -retries int
+        Retries of a failed fetch before falling back to the cache (default %d)
+
+Examples:
+  %s proxy -upstream https://example.com/index
+  %s proxy -upstream 'https://example.com/index/{version}' -version v4.20.0 -max-age 1h
+`, os.Args[0], indexproxy.DefaultRetries, os.Args[0], os.Args[0])
+	}
+	_ = flags.Parse(args)
+	if *upstream == "" {
+		flags.Usage()
+		return 1
+	}
+	if *retries == 0 {
+		*retries = -1
+	}
+
+	proxy, err := indexproxy.New(indexproxy.Options{
+		Upstream: *upstream,
+		Version:  *version,
+		CacheDir: *cacheDir,
+		MaxAge:   *maxAge,
+		Retries:  *retries,
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Error creating proxy: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("🌐 Serving %s on http://%s, caching in %s\n", *upstream, *listen, *cacheDir)
+	if err := http.ListenAndServe(*listen, proxy); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Error serving index: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lonegunmanb/terraform-provider-azurerm-index/pkg"
+)
+
+// runQueryCommand implements the `query` subcommand, returning the process exit code
+func runQueryCommand(args []string) int {
+	flags := flag.NewFlagSet("query", flag.ExitOnError)
+	indexDir := flags.String("index", "./index", "Index directory generated with the json format")
+	indexName := flags.String("index-name", "", "Main index file name (default terraform-provider-azurerm-index.json)")
+	flags.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, `Usage of %s query [flags] <name>:
+
+Prints the document of a Terraform type from an already generated index, with
+its namespace, CRUD function references and SDK type. The name can be a
+Terraform type, a struct type, a legacy registration function or an entry ID.
+When a resource and a data source share the Terraform type, both documents are
+printed as a JSON array of {"kind", "terraform_type", "document"} objects.
+
+Flags:
+  -index string
+        Index directory generated with the json format (default "./index")
+  -index-name string
+        Main index file name (default "terraform-provider-azurerm-index.json")
+
+Examples:
+  %s query azurerm_key_vault
+  %s query -index ./index KeyVaultSecretEphemeralResource
+`, os.Args[0], os.Args[0], os.Args[0])
+	}
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 1
+	}
+
+	results, err := pkg.QueryIndexDir(*indexDir, *indexName, flags.Arg(0))
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Error querying index: %v\n", err)
+		return 1
+	}
+
+	var output interface{} = results
+	if len(results) == 1 {
+		output = results[0].Document
+	}
+	data, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Error printing documents: %v\n", err)
+		return 1
+	}
+	fmt.Println(string(data))
+	return 0
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lonegunmanb/terraform-provider-azurerm-index/pkg"
+)
+
+// runValidateCommand implements the `validate` subcommand, returning the process exit code
+func runValidateCommand(args []string) int {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	indexDir := flags.String("index", "./index", "Index directory generated with the json format")
+	indexName := flags.String("index-name", "", "Main index file name (default terraform-provider-azurerm-index.json)")
+	flags.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, `Usage of %s validate:
+
+Loads an already generated index directory, verifies every JSON file parses into
+its expected structure, the main index and documents conform to their published
+JSON Schema (see -print-schema), and checks the index is consistent: statistics match the
+number of document files, and every global mapping has a document file. Problems
+are listed and make the command exit non-zero.
+
+Flags:
+  -index string
+        Index directory generated with the json format (default "./index")
+  -index-name string
+        Main index file name (default "terraform-provider-azurerm-index.json")
+
+Example:
+  %s validate -index ./index
+`, os.Args[0], os.Args[0])
+	}
+	_ = flags.Parse(args)
+
+	fmt.Printf("🔍 Validating index %s\n", *indexDir)
+	report, err := pkg.ValidateIndexDir(*indexDir, *indexName)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Error validating index: %v\n", err)
+		return 1
+	}
+
+	for _, problem := range report.Problems {
+		if problem.File != "" {
+			fmt.Printf("  ❌ %s: %s\n", problem.File, problem.Message)
+		} else {
+			fmt.Printf("  ❌ %s\n", problem.Message)
+		}
+	}
+	if !report.Valid() {
+		fmt.Printf("\n❌ %d problems found in %d files\n", len(report.Problems), report.Files)
+		return 1
+	}
+	fmt.Printf("\n🎉 %d files are valid and consistent\n", report.Files)
+	return 0
+}
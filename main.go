@@ -1,21 +1,90 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"syscall"
 
 	"github.com/lonegunmanb/terraform-provider-azurerm-index/pkg"
+	"github.com/spf13/afero"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "e2e":
+			os.Exit(runE2ECommand(os.Args[2:]))
+		case "check-pr":
+			os.Exit(runCheckPRCommand(os.Args[2:]))
+		case "validate":
+			os.Exit(runValidateCommand(os.Args[2:]))
+		case "merge":
+			os.Exit(runMergeCommand(os.Args[2:]))
+		case "check-config":
+			os.Exit(runCheckConfigCommand(os.Args[2:]))
+		case "query":
+			os.Exit(runQueryCommand(os.Args[2:]))
+		case "proxy":
+			os.Exit(runProxyCommand(os.Args[2:]))
+		}
+	}
+
 	var (
-		scanPath    = flag.String("scan-path", "", "Path to scan for Terraform provider services (required)")
-		packagePath = flag.String("package-path", "", "Base package path for the provider (required)")
-		version     = flag.String("version", "", "Version of the provider (required)")
-		outputDir   = flag.String("output", "./index", "Output directory for index files")
-		help        = flag.Bool("help", false, "Show help message")
+		scanPath       = flag.String("scan-path", "", "Path to scan for Terraform provider services (required)")
+		packagePath    = flag.String("package-path", "", "Base package path for the provider (required)")
+		version        = flag.String("version", "", "Version of the provider (required)")
+		outputDir      = flag.String("output", "./index", "Output directory, az://container/prefix or s3://bucket/prefix URL for index files")
+		providerName   = flag.String("provider-name", pkg.DefaultProviderName, "Provider name used to derive the main index file name")
+		indexName      = flag.String("index-name", "", "Main index file name (default terraform-provider-<provider-name>-index.json)")
+		goVersion      = flag.String("go-version", "", "Go version of the provider source, fails fast if the indexer can't parse it")
+		format         = flag.String("format", pkg.OutputFormatJSON, "Output format: json, template, esbulk, proto, parquet or csv")
+		templatePath   = flag.String("template", "", "Go template rendered for each resource/data source with -format template")
+		docsPath       = flag.String("docs-path", "", "Path to the provider's website/docs directory to link documentation")
+		repo           = flag.String("repo", "", "Provider git repository to clone and scan instead of an existing checkout")
+		ref            = flag.String("ref", "", "Tag, branch or commit of -repo to scan, also the default -version")
+		workers        = flag.Int("workers", 0, "Number of services scanned and files written in parallel (default one per CPU)")
+		parseWorkers   = flag.Int("parse-workers", 0, "Number of files of a service package parsed in parallel (default one per CPU)")
+		typeStrategy   = flag.String("type-strategies", "", "Comma separated Terraform type inference strategies, tried in order")
+		services       = flag.String("services", "", "Comma separated services to scan instead of all services")
+		packagePathMap = flag.String("package-path-map", "", "Comma separated dir=importpath prefixes mapping scanned directories to import paths")
+		typed          = flag.Bool("typed", false, "Type-check service packages with go/packages to resolve registrations the AST scan misses")
+		buildTags      = flag.String("tags", "", "Comma separated build tags selecting the scanned files, like go build -tags")
+		goos           = flag.String("goos", "", "Target operating system of the build constraints selecting the scanned files (default the host's)")
+		goarch         = flag.String("goarch", "", "Target architecture of the build constraints selecting the scanned files (default the host's)")
+		skipTests      = flag.Bool("skip-tests", false, "Leave _test.go files out of the scan, skipping the acceptance tests of the resources")
+		skipGenerated  = flag.Bool("skip-generated", false, "Leave zz_generated* files out of the scan")
+		exclude        = flag.String("exclude", "", "Comma separated globs of file names left out of the scan")
+		strict         = flag.Bool("strict", false, "Fail when a Terraform type is registered more than once")
+		sarif          = flag.String("sarif", "", "Also write the findings of the scan to a SARIF file for code scanning")
+		watch          = flag.Bool("watch", false, "Keep running and rescan services whose files change")
+		statsHistory   = flag.String("stats-history", "", "Statistics history file the statistics of the indexed version are appended to")
+		annotations    = flag.String("annotations", "", "Directory of YAML fragments merged into documents under x_annotations")
+		productNames   = flag.String("product-names", "", "YAML mapping of service package names to Azure product names")
+		goIndexDir     = flag.String("goindex-dir", "", "gophon output directory the goindex references of documents are verified against")
+		goIndex        = flag.Bool("goindex", false, "Also write the gophon .goindex files of the scanned packages to the output directory")
+		apiSpecs       = flag.String("api-specs", "", "Azure REST API specs directory the resource schemas are cross-referenced against")
+		providerPath   = flag.String("provider-path", "", "Provider package registering the services, compared with the scanned services")
+		providerSchema = flag.String("provider-schema", "", "Output of terraform providers schema -json, compared with the indexed Terraform types")
+		summaries      = flag.Bool("service-summaries", false, "Also write a services/<name>.json summary of every service")
+		report         = flag.Bool("report", false, "Also write a human-readable REPORT.md summary of the index")
+		metrics        = flag.String("metrics", "", "Also write generation metrics: json (metrics.json) or prometheus (metrics.prom)")
+		cpuProfile     = flag.String("cpuprofile", "", "Write a pprof CPU profile of the scan and the writing of the index to this file")
+		memProfile     = flag.String("memprofile", "", "Write a pprof heap profile taken after the index is written to this file")
+		singleFile     = flag.Bool("single-file", false, "Write only the main index file with every document embedded (json format only)")
+		stdout         = flag.Bool("stdout", false, "Print the main index JSON to standard output instead of writing index files")
+		stream         = flag.Bool("stream", false, "Write the files of each service as soon as it is scanned to cut memory usage (json format only)")
+		printSchema    = flag.String("print-schema", "", "Print the JSON Schema of the index, resource, datasource, ephemeral, listresource, action or singlefile files and exit")
+		help           = flag.Bool("help", false, "Show help message")
 	)
 
 	flag.Usage = func() {
@@ -34,26 +103,221 @@ Required flags:
 
 Optional flags:
   -output string
-        Output directory for index files (default "./index")
+        Output directory for index files (default "./index"), a Go template with the {{.Provider}} and
+        {{.Version}} variables for multi-version layouts (e.g., ./index/{{.Provider}}/{{.Version}}), or an
+        az://container/prefix or s3://bucket/prefix URL uploading the files to Azure Blob Storage with
+        AZURE_STORAGE_ACCOUNT and AZURE_STORAGE_SAS_TOKEN or to S3 with the AWS_ACCESS_KEY_ID,
+        AWS_SECRET_ACCESS_KEY and AWS_REGION environment variables
+  -provider-name string
+        Provider name used to derive the main index file name (default "azurerm")
+  -index-name string
+        Main index file name (default "terraform-provider-<provider-name>-index.json")
+  -go-version string
+        Go version of the provider source (e.g., 1.24), fails fast if the indexer can't parse it
+  -format string
+        Output format: json, template, esbulk, proto, parquet or csv (default "json"), esbulk writes
+        bulk.ndjson with OpenSearch/Elasticsearch bulk index actions, proto writes the whole index to index.pb,
+        a single Protocol Buffers message of pkg/schemas/terraform_provider_index.proto, parquet writes
+        resources.parquet, datasources.parquet and ephemeral.parquet tables for DuckDB or Spark, csv writes
+        the resource and data source mappings to resources.csv and datasources.csv
+  -template string
+        Go template rendered for each resource/data source document, required with -format template
+        (e.g., docs.md.tmpl renders resources/azurerm_key_vault.md)
+  -docs-path string
+        Path to the provider's website/docs directory, links each resource and data source to its
+        documentation and reports undocumented ones in audit/undocumented.json
+  -repo string
+        Provider git repository to shallow-clone into a temporary directory instead of using an existing
+        checkout (e.g., https://github.com/hashicorp/terraform-provider-azurerm). -scan-path and -docs-path
+        are relative to the clone, -scan-path defaults to "internal/services"
+  -ref string
+        Tag, branch or commit of -repo to check out (e.g., v4.20.0), required with -repo and the default -version
+  -workers int
+        Number of services scanned and files written in parallel, useful to throttle shared CI runners
+        (default one per CPU)
+  -parse-workers int
+        Number of files of each service package parsed in parallel, so that large services such as network
+        and compute don't leave the other workers idle at the end of the scan (default one per CPU)
+  -type-strategies string
+        Comma separated strategies inferring the Terraform type of typed resources, tried in order
+        (default "resource_type_literal,resource_type_constant,metadata,naming_convention")
+  -services string
+        Comma separated services to scan instead of all services (e.g., keyvault,storage), to generate shards
+        of the index on different workers that the merge subcommand combines
+  -package-path-map string
+        Comma separated dir=importpath pairs mapping directory prefixes of the scanned tree to import path
+        prefixes, for forks and vendored copies whose layout differs from their module path
+        (e.g., vendor/github.com/hashicorp/terraform-provider-azurerm=github.com/hashicorp/terraform-provider-azurerm),
+        by default package paths are derived from -package-path and -scan-path
+  -typed
+        Also type-check every service package with go/packages, resolving registrations through aliased imports,
+        dot-imports and local variables the AST scan misses; slower, and the provider module's dependencies must
+        be downloaded (go mod download) in the checkout
+  -tags string
+        Comma separated build tags (e.g., azurerm_preview), service files are scanned only when their build
+        constraints are satisfied as with go build -tags, so the index matches what compiles into a release
+  -goos string
+        Target operating system of the build constraints of service files (e.g., windows), default the host's
+  -goarch string
+        Target architecture of the build constraints of service files (e.g., arm64), default the host's
+  -skip-tests
+        Leave _test.go files out of the scan, the acceptance tests of the resources aren't indexed
+  -skip-generated
+        Leave zz_generated* files out of the scan
+  -exclude string
+        Comma separated globs matched against the names of service files left out of the scan
+        (e.g., *_mock.go,testdata_*.go), to cut parse time and ignore registrations of test harnesses
+  -strict
+        Exit with an error after writing the index when a Terraform type is registered more than once, by two
+        services or by two registrations of a service, where only the last registration is indexed; the
+        duplicates are always listed in audit/duplicates.json
+  -sarif string
+        Also write the findings of the scan to a SARIF 2.1.0 file (e.g., findings.sarif): unparsed and unresolved
+        registrations, duplicate registrations, orphaned implementations and, with -docs-path, undocumented
+        resources, located under -scan-path so code scanning can annotate provider pull requests
+  -watch
+        Keep running after the index is written, rescanning only the changed service package when files under
+        -scan-path change and rewriting its files, for a live index during provider development
+  -stats-history string
+        JSON Lines file the statistics of the indexed version are appended to, one line per version with
+        provider totals and per-service counts (e.g., ./index/stats-history.jsonl), re-indexing a version
+        replaces its line
+  -annotations string
+        Directory of user-maintained YAML fragments keyed by Terraform type or entry ID (e.g., ./annotations),
+        merged into the emitted documents under x_annotations
+  -product-names string
+        YAML or JSON mapping of service package names to Azure product display names (e.g., keyvault: Azure Key
+        Vault), set as product_name of the services of the main index, overriding the built-in mapping
+  -goindex-dir string
+        gophon output directory generated with the same base package (e.g., ./index), verifies the goindex
+        references of documents such as create_index, corrects references found under another symbol and
+        removes broken ones, see audit/goindex-references.json
+  -goindex
+        Also write the gophon .goindex files of the packages under -scan-path to the output directory in the
+        same run, producing a self-contained bundle; references are verified against them unless -goindex-dir
+        is set
+  -api-specs string
+        Directory of Azure REST API specs in OpenAPI 2.0 (e.g., ./azure-rest-api-specs/specification), flags
+        resource attributes missing from the API and API properties missing from the provider, see
+        audit/api-drift.json
+  -provider-path string
+        Path to the provider package (e.g., ./internal/provider), compares the services returned by
+        SupportedTypedServices and SupportedUntypedServices with the scanned services, see
+        audit/provider-coverage.json
+  -provider-schema string
+        Output of terraform providers schema -json (e.g., schema.json), reports Terraform types of the
+        compiled provider missing from the index and indexed types missing from the schema, see
+        audit/provider-schema.json
+  -service-summaries
+        Also write a services/<name>.json summary of every service with its registration counts and Terraform
+        types (json format only)
+  -report
+        Also write a human-readable REPORT.md with the provider totals, the legacy and modern SDK distribution
+        and a table of services, for release notes; with -stats-history the counts are compared with the
+        previously indexed version (json format only)
+  -metrics string
+        Also write metrics of the generation to the output directory for pipeline dashboards: scan and write
+        durations, services scanned and indexed, parse failures, warnings, files and bytes written;
+        json writes metrics.json, prometheus writes metrics.prom for the node exporter textfile collector
+  -cpuprofile string
+        Write a pprof CPU profile of the scan and the writing of the index files to this file (e.g., cpu.pprof),
+        read with go tool pprof
+  -memprofile string
+        Write a pprof heap profile to this file (e.g., mem.pprof) after the index files are written, with the
+        allocations of the whole run in its alloc_space and alloc_objects samples
+  -single-file
+        Write a self-contained main index file embedding every resource, data source, ephemeral resource, list
+        resource and action document under "documents", keyed by kind and Terraform type, instead of a file per
+        document and the summary and audit files (json format only); -print-schema singlefile prints its schema
+  -stdout
+        Print the main index JSON to standard output, with every document embedded with -single-file, instead
+        of writing the index files, and suppress progress and summary output, for pipelines such as
+        | jq '.statistics' that shouldn't touch the disk (json format only)
+  -stream
+        Write the resource, data source and acceptance test files of each service as soon as it is scanned and
+        drop its parsed source, so memory doesn't grow with the size of the provider (json format only); the
+        documents are written before the index is enriched, so it can't be combined with -docs-path,
+        -annotations, -goindex-dir or -goindex
+  -print-schema string
+        Print the published JSON Schema of the main index (index), of resource, data source, ephemeral,
+        list resource or action documents (resource, datasource, ephemeral, listresource, action) or of the
+        single-file index (singlefile) and exit, no other flags are needed; the validate subcommand checks generated files against these schemas
   -help
         Show this help message
 
+Subcommands:
+  e2e
+        Verify extraction of well-known resources against a provider checkout
+  check-pr
+        Validate the registrations of services touched by a provider pull request
+  validate
+        Check the files of an already generated index directory parse and are consistent
+  merge
+        Merge partial indexes, such as per-service shards, into one index
+  check-config
+        Check a Terraform module's azurerm blocks against the schemas of an index
+  query
+        Print the document of a Terraform type or struct type from an index
+  proxy
+        Serve a published index on localhost, caching files for offline use
+
 Example:
   %s -scan-path ./tmp/terraform-provider-azurerm/internal/services \
     -package-path github.com/hashicorp/terraform-provider-azurerm \
     -version v3.116.0 \
     -output ./output/index
-`, os.Args[0], os.Args[0])
+
+  %s -repo https://github.com/hashicorp/terraform-provider-azurerm -ref v4.20.0 \
+    -package-path github.com/hashicorp/terraform-provider-azurerm
+`, os.Args[0], os.Args[0], os.Args[0])
 		_, _ = fmt.Fprintf(os.Stderr, "%s", helpMessage)
 	}
 
 	flag.Parse()
 
+	// Progress and summaries are discarded when the index itself is printed to standard output
+	var out io.Writer = os.Stdout
+	if *stdout {
+		out = io.Discard
+	}
+	printf := func(format string, args ...interface{}) {
+		_, _ = fmt.Fprintf(out, format, args...)
+	}
+
 	if *help {
 		flag.Usage()
 		os.Exit(0)
 	}
 
+	if *printSchema != "" {
+		schema, err := pkg.JSONSchema(*printSchema)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: -print-schema: %v\n", err)
+			os.Exit(1)
+		}
+		_, _ = os.Stdout.Write(schema)
+		os.Exit(0)
+	}
+
+	if *repo != "" {
+		if *watch {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: -watch needs a local checkout, it can't be used with -repo\n\n")
+			flag.Usage()
+			os.Exit(1)
+		}
+		if *ref == "" {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: -ref is required with -repo\n\n")
+			flag.Usage()
+			os.Exit(1)
+		}
+		if *scanPath == "" {
+			*scanPath = filepath.Join("internal", "services")
+		}
+		if *version == "" {
+			*version = *ref
+		}
+	}
+
 	// Validate required arguments
 	if *scanPath == "" {
 		_, _ = fmt.Fprintf(os.Stderr, "Error: -scan-path is required\n\n")
@@ -73,45 +337,536 @@ Example:
 		os.Exit(1)
 	}
 
+	switch *format {
+	case pkg.OutputFormatJSON, pkg.OutputFormatESBulk, pkg.OutputFormatProto, pkg.OutputFormatParquet, pkg.OutputFormatCSV:
+	case pkg.OutputFormatTemplate:
+		if *templatePath == "" {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: -template is required with -format template\n\n")
+			flag.Usage()
+			os.Exit(1)
+		}
+		if _, err := pkg.LoadOutputTemplate(*templatePath); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	default:
+		_, _ = fmt.Fprintf(os.Stderr, "Error: unsupported -format %q\n\n", *format)
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	switch *metrics {
+	case "", pkg.MetricsFormatJSON, pkg.MetricsFormatPrometheus:
+	default:
+		_, _ = fmt.Fprintf(os.Stderr, "Error: unsupported -metrics %q\n\n", *metrics)
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if *singleFile {
+		if *format != pkg.OutputFormatJSON {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: -single-file only supports -format json\n\n")
+			flag.Usage()
+			os.Exit(1)
+		}
+		if *stream {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: -stream writes a file per document, it can't be used with -single-file\n\n")
+			flag.Usage()
+			os.Exit(1)
+		}
+	}
+
+	if *stdout {
+		if *format != pkg.OutputFormatJSON {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: -stdout only supports -format json\n\n")
+			flag.Usage()
+			os.Exit(1)
+		}
+		if *watch || *stream || *goIndex {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: -stdout writes no files, it can't be used with -watch, -stream or -goindex\n\n")
+			flag.Usage()
+			os.Exit(1)
+		}
+	}
+
+	if *stream {
+		if *format != pkg.OutputFormatJSON {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: -stream only supports -format json\n\n")
+			flag.Usage()
+			os.Exit(1)
+		}
+		if *docsPath != "" || *annotations != "" || *goIndexDir != "" || *goIndex {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: -stream writes documents before they are enriched, it can't be used with -docs-path, -annotations, -goindex-dir or -goindex\n\n")
+			flag.Usage()
+			os.Exit(1)
+		}
+	}
+
+	if *workers < 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "Error: -workers must not be negative\n\n")
+		flag.Usage()
+		os.Exit(1)
+	}
+	if *parseWorkers < 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "Error: -parse-workers must not be negative\n\n")
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	var typeStrategies []pkg.TerraformTypeStrategy
+	if *typeStrategy != "" {
+		strategies, err := pkg.TerraformTypeStrategiesByName(strings.Split(*typeStrategy, ","))
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: -type-strategies: %v\n\n", err)
+			flag.Usage()
+			os.Exit(1)
+		}
+		typeStrategies = strategies
+	}
+
+	var packagePaths pkg.PackagePathMapper
+	if *packagePathMap != "" {
+		mapper, err := pkg.ParsePathPrefixMapper(*packagePathMap)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: -package-path-map: %v\n\n", err)
+			flag.Usage()
+			os.Exit(1)
+		}
+		packagePaths = mapper
+	}
+
+	if *goVersion != "" {
+		if err := pkg.CheckGoVersionSupported(*goVersion); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	expandedOutputDir, err := pkg.OutputConfig{ProviderName: *providerName}.ExpandOutputDir(*outputDir, *version)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Error: -output: %v\n\n", err)
+		flag.Usage()
+		os.Exit(1)
+	}
+	*outputDir = expandedOutputDir
+
+	remoteOutput := pkg.IsRemoteOutput(*outputDir)
+	var remoteOutputFs afero.Fs
+	if remoteOutput {
+		if *goIndex {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: -goindex writes to the local file system, it can't be used with a remote -output\n\n")
+			flag.Usage()
+			os.Exit(1)
+		}
+		remoteOutputDir, fs, err := pkg.OpenRemoteOutput(*outputDir)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: -output: %v\n", err)
+			os.Exit(1)
+		}
+		*outputDir, remoteOutputFs = remoteOutputDir, fs
+	}
+
+	// Annotations and product names are loaded before a clone changes the working directory, and fail fast when invalid
+	var annotationSet pkg.Annotations
+	if *annotations != "" {
+		loaded, err := pkg.LoadAnnotations(*annotations)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: -annotations: %v\n", err)
+			os.Exit(1)
+		}
+		annotationSet = loaded
+	}
+	var productNameSet pkg.ProductNames
+	if *productNames != "" {
+		loaded, err := pkg.LoadProductNames(*productNames)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: -product-names: %v\n", err)
+			os.Exit(1)
+		}
+		productNameSet = loaded
+	}
+
+	cleanup := func() {}
+	if *repo != "" {
+		// Like a manual checkout, the scan runs from the root of the clone, so paths the outputs are
+		// written to must not depend on the working directory
+		if !remoteOutput {
+			*outputDir = absolutePath(*outputDir)
+		}
+		if *statsHistory != "" {
+			*statsHistory = absolutePath(*statsHistory)
+		}
+		if *templatePath != "" {
+			*templatePath = absolutePath(*templatePath)
+		}
+		if *goIndexDir != "" {
+			*goIndexDir = absolutePath(*goIndexDir)
+		}
+		if *apiSpecs != "" {
+			*apiSpecs = absolutePath(*apiSpecs)
+		}
+		if *sarif != "" {
+			*sarif = absolutePath(*sarif)
+		}
+		if *cpuProfile != "" {
+			*cpuProfile = absolutePath(*cpuProfile)
+		}
+		if *memProfile != "" {
+			*memProfile = absolutePath(*memProfile)
+		}
+
+		printf("📥 Cloning %s at %s...\n", *repo, *ref)
+		checkoutDir, removeCheckout, err := pkg.CloneProvider(*repo, *ref)
+		if err != nil {
+			log.Fatalf("Error cloning provider repository: %v", err)
+		}
+		wd, _ := os.Getwd()
+		if err := os.Chdir(checkoutDir); err != nil {
+			removeCheckout()
+			log.Fatalf("Error entering provider checkout: %v", err)
+		}
+		cleanup = func() {
+			_ = os.Chdir(wd)
+			removeCheckout()
+		}
+		defer cleanup()
+	}
+
 	// Check if scan path exists
 	if _, err := os.Stat(*scanPath); os.IsNotExist(err) {
+		cleanup()
 		log.Fatalf("Error: scan path does not exist: %s", *scanPath)
 	}
 
-	fmt.Printf("🚀 Starting Terraform Provider Indexing...\n")
-	fmt.Printf("  📁 Scan Path: %s\n", *scanPath)
-	fmt.Printf("  📦 Package Path: %s\n", *packagePath)
-	fmt.Printf("  🏷️  Version: %s\n", *version)
-	fmt.Printf("  📂 Output Directory: %s\n", *outputDir)
-	fmt.Printf("\n")
+	printf("🚀 Starting Terraform Provider Indexing...\n")
+	printf("  📁 Scan Path: %s\n", *scanPath)
+	printf("  📦 Package Path: %s\n", *packagePath)
+	printf("  🏷️  Version: %s\n", *version)
+	printf("  📂 Output Directory: %s\n", *outputDir)
+	printf("\n")
 
 	// Create progress callback for rich visual feedback
 	progressCallback := pkg.CreateRichProgressCallback()
+	if *stdout {
+		progressCallback = nil
+	}
+
+	stopProfiling, err := startProfiling(*cpuProfile, *memProfile)
+	if err != nil {
+		cleanup()
+		log.Fatalf("Error starting profiling: %v", err)
+	}
+	// Profiles are also written when the run fails, every fatal exit from here on calls cleanup first
+	removeCheckout := cleanup
+	cleanup = func() {
+		if err := stopProfiling(); err != nil {
+			log.Printf("Error writing profiles: %v", err)
+		}
+		removeCheckout()
+	}
 
 	// Scan the Terraform provider services
-	index, err := pkg.ScanTerraformProviderServices(*scanPath, *packagePath, *version, progressCallback)
+	scanner := pkg.Scanner{Workers: *workers, ParseWorkers: *parseWorkers, TerraformTypeStrategies: typeStrategies, PackagePaths: packagePaths, Typed: *typed}
+	if *services != "" {
+		scanner.Services = strings.Split(*services, ",")
+	}
+	scanner.Build = pkg.BuildConstraints{GOOS: *goos, GOARCH: *goarch}
+	if *buildTags != "" {
+		scanner.Build.Tags = strings.Split(*buildTags, ",")
+	}
+	scanner.Exclude = pkg.FileExclusions{TestFiles: *skipTests, GeneratedFiles: *skipGenerated}
+	if *exclude != "" {
+		scanner.Exclude.Patterns = strings.Split(*exclude, ",")
+	}
+	if *stream {
+		scanner.Stream = &pkg.StreamWriter{OutputDir: *outputDir, Fs: remoteOutputFs}
+	}
+	index, err := scanner.Scan(*scanPath, *packagePath, *version, progressCallback)
 	if err != nil {
+		cleanup()
 		log.Fatalf("Error scanning Terraform provider services: %v", err)
 	}
 
-	fmt.Printf("\n📊 Scan Results:\n")
-	fmt.Printf("  🏢 Services Found: %d\n", index.Statistics.ServiceCount)
-	fmt.Printf("  📋 Total Resources: %d\n", index.Statistics.TotalResources)
-	fmt.Printf("  📄 Total Data Sources: %d\n", index.Statistics.TotalDataSources)
-	fmt.Printf("  🔗 Legacy Resources: %d\n", index.Statistics.LegacyResources)
-	fmt.Printf("  ⚡ Modern Resources: %d\n", index.Statistics.ModernResources)
-	fmt.Printf("  🔄 Ephemeral Resources: %d\n", index.Statistics.EphemeralResources)
-	fmt.Printf("\n")
+	printf("\n📊 Scan Results:\n")
+	printf("  🏢 Services Found: %d\n", index.Statistics.ServiceCount)
+	printf("  📋 Total Resources: %d\n", index.Statistics.TotalResources)
+	printf("  📄 Total Data Sources: %d\n", index.Statistics.TotalDataSources)
+	printf("  🔗 Legacy Resources: %d\n", index.Statistics.LegacyResources)
+	printf("  ⚡ Modern Resources: %d\n", index.Statistics.ModernResources)
+	printf("  🔄 Ephemeral Resources: %d\n", index.Statistics.EphemeralResources)
+	printf("  📜 List Resources: %d\n", index.Statistics.ListResources)
+	printf("  🎬 Actions: %d\n", index.Statistics.Actions)
+	printf("  ⚠️  Deprecated Resources: %d\n", index.Statistics.DeprecatedResources)
+	for _, strategy := range pkg.DefaultTerraformTypeStrategies() {
+		if count := index.Statistics.TerraformTypeStrategies[strategy.Name()]; count > 0 {
+			printf("  🧭 Types Inferred by %s: %d\n", strategy.Name(), count)
+		}
+	}
+	printf("  🛠️  Go Toolchain: %s %s/%s\n", index.Toolchain.GoVersion, index.Toolchain.GOOS, index.Toolchain.GOARCH)
+	printf("\n")
+
+	if len(index.Warnings) > 0 {
+		printf("⚠️  %d problems while scanning, details in scan-report.json:\n", len(index.Warnings))
+		for _, warning := range index.Warnings {
+			// Unresolved registrations are routine on large providers, only list problems losing whole extractions
+			if warning.Kind == pkg.ScanWarningUnresolvedRegistration {
+				continue
+			}
+			location := warning.Service
+			if warning.File != "" {
+				location += "/" + warning.File
+			}
+			printf("  - %s: %s\n", location, warning.Message)
+		}
+		printf("\n")
+	}
+
+	index.Output = pkg.OutputConfig{
+		ProviderName:     *providerName,
+		IndexFileName:    *indexName,
+		Format:           *format,
+		TemplatePath:     *templatePath,
+		Workers:          *workers,
+		ServiceSummaries: *summaries,
+		MarkdownReport:   *report,
+		Metrics:          *metrics,
+		SingleFile:       *singleFile,
+		Fs:               remoteOutputFs,
+	}
+
+	if *report && *statsHistory != "" {
+		entries, err := pkg.ReadStatsHistory(*statsHistory)
+		if err != nil {
+			printf("⚠️  Skipping the comparison of REPORT.md with the previous version: %v\n\n", err)
+		}
+		index.ReportBaseline = pkg.PreviousStatsHistoryEntry(entries, index.Version)
+	}
+
+	if *docsPath != "" {
+		report, err := index.LinkDocumentation(*docsPath)
+		if err != nil {
+			printf("⚠️  Skipping documentation linking: %v\n\n", err)
+		} else if len(report.Undocumented) > 0 {
+			printf("⚠️  %d resources and data sources are undocumented, see audit/undocumented.json\n\n", len(report.Undocumented))
+		}
+	}
+
+	if annotationSet != nil {
+		if unmatched := index.ApplyAnnotations(annotationSet); len(unmatched) > 0 {
+			printf("⚠️  Annotations match no Terraform type or entry ID of the index: %s\n\n", strings.Join(unmatched, ", "))
+		}
+	}
+
+	if productNameSet != nil {
+		if unmatched := index.ApplyProductNames(productNameSet); len(unmatched) > 0 {
+			printf("⚠️  Product names match no service of the index: %s\n\n", strings.Join(unmatched, ", "))
+		}
+	}
+
+	if *goIndex {
+		printf("📚 Writing goindex files of %s...\n", *scanPath)
+		if err := pkg.WriteGoIndexFiles(*scanPath, *packagePath, *outputDir, progressCallback); err != nil {
+			cleanup()
+			log.Fatalf("Error writing goindex files: %v", err)
+		}
+		if *goIndexDir == "" {
+			*goIndexDir = *outputDir
+		}
+	}
+
+	if *goIndexDir != "" {
+		report, err := index.VerifyGoIndexReferences(*goIndexDir, *packagePath)
+		if err != nil {
+			printf("⚠️  Skipping goindex reference verification: %v\n\n", err)
+		} else if len(report.Corrected)+len(report.Broken) > 0 {
+			printf("⚠️  %d goindex references corrected and %d broken ones removed, see audit/goindex-references.json\n\n", len(report.Corrected), len(report.Broken))
+		}
+	}
+
+	if *apiSpecs != "" {
+		report, err := index.DetectAPIDrift(*apiSpecs)
+		if err != nil {
+			printf("⚠️  Skipping API drift detection: %v\n\n", err)
+		} else if len(report.Drifts) > 0 {
+			printf("⚠️  %d of %d resources drifted from the API specs, see audit/api-drift.json\n\n", len(report.Drifts), report.Checked)
+		}
+	}
+
+	if *providerPath != "" {
+		report, err := index.CheckProviderCoverage(*providerPath)
+		if err != nil {
+			printf("⚠️  Skipping provider coverage check: %v\n\n", err)
+		} else if len(report.MissingFromIndex)+len(report.NotRegistered) > 0 {
+			printf("⚠️  %d services registered by the provider are missing from the index and %d indexed services aren't registered, see audit/provider-coverage.json\n\n", len(report.MissingFromIndex), len(report.NotRegistered))
+		}
+	}
+
+	if *providerSchema != "" {
+		report, err := index.ReconcileProviderSchema(*providerSchema)
+		if err != nil {
+			printf("⚠️  Skipping provider schema reconciliation: %v\n\n", err)
+		} else if missing := report.Missing(); missing > 0 {
+			printf("⚠️  %d Terraform types differ between the provider schema and the index, see audit/provider-schema.json\n\n", missing)
+		}
+	}
+
+	if orphans := index.BuildOrphansReport(); len(orphans) > 0 && index.Output.Format == pkg.OutputFormatJSON {
+		printf("⚠️  %d resources and data sources are implemented but not registered, see audit/orphans.json\n\n", len(orphans))
+	}
+
+	duplicates := index.BuildDuplicatesReport()
+	if len(duplicates) > 0 {
+		printf("⚠️  %d Terraform types are registered more than once, only the last registration is indexed, see audit/duplicates.json\n\n", len(duplicates))
+	}
 
 	// Generate JSON output
-	err = index.WriteIndexFiles(*outputDir, progressCallback)
+	if *stdout {
+		err = index.WriteMainIndex(os.Stdout)
+	} else {
+		err = index.WriteIndexFiles(*outputDir, progressCallback)
+	}
 	if err != nil {
+		cleanup()
 		log.Fatalf("Error generating JSON output: %v", err)
 	}
 
-	fmt.Printf("\n🎉 Index files generated successfully!\n")
-	fmt.Printf("  📋 Main index: %s/terraform-provider-azurerm-index.json\n", *outputDir)
-	fmt.Printf("  🔧 Resources: %s/resources/\n", *outputDir)
-	fmt.Printf("  📊 Data Sources: %s/datasources/\n", *outputDir)
-	fmt.Printf("  ⚡ Ephemeral Resources: %s/ephemeral/\n", *outputDir)
+	if *statsHistory != "" {
+		if err := pkg.AppendStatsHistory(*statsHistory, index.StatsHistoryEntry()); err != nil {
+			cleanup()
+			log.Fatalf("Error updating statistics history: %v", err)
+		}
+		printf("\n📈 Statistics of %s recorded in %s\n", index.Version, *statsHistory)
+	}
+
+	if *sarif != "" {
+		if err := index.WriteSARIFFile(*sarif, *scanPath); err != nil {
+			cleanup()
+			log.Fatalf("Error writing SARIF file: %v", err)
+		}
+		printf("\n🔍 Findings written to %s\n", *sarif)
+	}
+
+	if err := stopProfiling(); err != nil {
+		cleanup()
+		log.Fatalf("Error writing profiles: %v", err)
+	}
+
+	if *strict && len(duplicates) > 0 {
+		for _, duplicate := range duplicates {
+			var locations []string
+			for _, registration := range duplicate.Registrations {
+				locations = append(locations, path.Join(registration.Service, registration.FileName))
+			}
+			printf("❌ %s %s is registered in %s\n", duplicate.Kind, duplicate.TerraformType, strings.Join(locations, ", "))
+		}
+		cleanup()
+		log.Fatalf("Error: %d Terraform types are registered more than once", len(duplicates))
+	}
+
+	if *stdout {
+		return
+	}
+
+	printf("\n🎉 Index files generated successfully!\n")
+	if index.Output.Format == pkg.OutputFormatESBulk {
+		printf("  🔎 Bulk file: %s/%s (index %s)\n", *outputDir, pkg.ESBulkFileName, index.Output.ESIndexName())
+	} else if index.Output.Format == pkg.OutputFormatProto {
+		printf("  📦 Proto index: %s/%s\n", *outputDir, pkg.ProtoFileName)
+	} else if index.Output.Format == pkg.OutputFormatParquet {
+		printf("  🧮 Parquet tables: %s/%s, %s, %s\n", *outputDir, pkg.ParquetResourcesFileName, pkg.ParquetDataSourcesFileName, pkg.ParquetEphemeralFileName)
+	} else if index.Output.Format == pkg.OutputFormatCSV {
+		printf("  📄 CSV mappings: %s/%s, %s\n", *outputDir, pkg.CSVResourcesFileName, pkg.CSVDataSourcesFileName)
+	} else if index.Output.SingleFile {
+		printf("  📦 Single-file index: %s/%s\n", *outputDir, index.Output.MainIndexFileName())
+	} else {
+		if index.Output.Format != pkg.OutputFormatTemplate {
+			printf("  📋 Main index: %s/%s\n", *outputDir, index.Output.MainIndexFileName())
+		}
+		printf("  🔧 Resources: %s/resources/\n", *outputDir)
+		printf("  📊 Data Sources: %s/datasources/\n", *outputDir)
+		printf("  ⚡ Ephemeral Resources: %s/ephemeral/\n", *outputDir)
+		printf("  📜 List Resources: %s/listresources/\n", *outputDir)
+		printf("  🎬 Actions: %s/actions/\n", *outputDir)
+	}
+	if index.Output.Metrics != "" {
+		printf("  📈 Metrics: %s/%s\n", *outputDir, pkg.MetricsFileName(index.Output.Metrics))
+	}
+
+	if *watch {
+		watchIndex(index, pkg.Watcher{
+			ScanPath:   *scanPath,
+			BasePkgUrl: *packagePath,
+			OutputDir:  *outputDir,
+			Scanner:    scanner,
+		})
+	}
+}
+
+// watchIndex keeps the index up to date with the checkout until interrupted
+func watchIndex(index *pkg.TerraformProviderIndex, watcher pkg.Watcher) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	watcher.OnRefresh = func(services []string, err error) {
+		if err != nil {
+			fmt.Printf("❌ Rescanning %s failed: %v\n", strings.Join(services, ", "), err)
+			return
+		}
+		fmt.Printf("🔁 Rescanned %s, %d resources and %d data sources indexed\n",
+			strings.Join(services, ", "), index.Statistics.TotalResources, index.Statistics.TotalDataSources)
+	}
+
+	fmt.Printf("\n👀 Watching %s for changes, press Ctrl+C to stop...\n", watcher.ScanPath)
+	if err := watcher.Watch(ctx, index); err != nil {
+		log.Fatalf("Error watching %s: %v", watcher.ScanPath, err)
+	}
+}
+
+// startProfiling starts the CPU profile written to cpuProfile, the returned function stops it and writes the heap
+// profile to memProfile, calls after the first do nothing. Profiles with an empty file name are skipped.
+func startProfiling(cpuProfile, memProfile string) (stop func() error, err error) {
+	var cpuFile *os.File
+	if cpuProfile != "" {
+		if cpuFile, err = os.Create(cpuProfile); err != nil {
+			return nil, fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(cpuFile); err != nil {
+			_ = cpuFile.Close()
+			return nil, fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+	}
+	stopped := false
+	return func() error {
+		if stopped {
+			return nil
+		}
+		stopped = true
+		if cpuFile != nil {
+			pprof.StopCPUProfile()
+			if err := cpuFile.Close(); err != nil {
+				return fmt.Errorf("failed to write CPU profile: %w", err)
+			}
+		}
+		if memProfile == "" {
+			return nil
+		}
+		memFile, err := os.Create(memProfile)
+		if err != nil {
+			return fmt.Errorf("failed to create heap profile: %w", err)
+		}
+		defer func() { _ = memFile.Close() }()
+		// Up-to-date statistics of the objects still in use
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(memFile); err != nil {
+			return fmt.Errorf("failed to write heap profile: %w", err)
+		}
+		return nil
+	}, nil
+}
+
+// absolutePath returns the absolute form of path, or path itself when it can't be resolved
+func absolutePath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
 }
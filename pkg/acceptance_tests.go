@@ -0,0 +1,122 @@
+package pkg
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lonegunmanb/terraform-provider-azurerm-index/pkg/internal/parallel"
+)
+
+// AcceptanceTest represents an acceptance test function exercising a resource or data source
+type AcceptanceTest struct {
+	Name     string `json:"name"`      // "TestAccKeyVault_basic"
+	FilePath string `json:"file_path"` // "internal/services/keyvault/key_vault_resource_test.go"
+}
+
+// TerraformAcceptanceTests is the content of the tests/ output files
+type TerraformAcceptanceTests struct {
+	ID            string           `json:"id"`             // "azurerm/resources/azurerm_key_vault/legacy_pluginsdk", see EntryID
+	TerraformType string           `json:"terraform_type"` // "azurerm_key_vault"
+	Tests         []AcceptanceTest `json:"tests"`
+}
+
+// extractAcceptanceTests scans the _test.go files of a service directory and maps Terraform types to the acceptance
+// tests building their test data with acceptance.BuildTestData(t, "azurerm_key_vault", "test"). Data source tests
+// use the "data." prefix and are returned separately. Test files left out by the exclusions are skipped.
+func extractAcceptanceTests(serviceDir string, exclusions FileExclusions) (resourceTests, dataSourceTests map[string][]AcceptanceTest) {
+	resourceTests = make(map[string][]AcceptanceTest)
+	dataSourceTests = make(map[string][]AcceptanceTest)
+
+	testFiles, _ := filepath.Glob(filepath.Join(serviceDir, "*_test.go"))
+	for _, testFile := range testFiles {
+		if exclusions.excludes(testFile) {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), testFile, nil, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || !strings.HasPrefix(fn.Name.Name, "TestAcc") {
+				continue
+			}
+			terraformType := buildTestDataType(fn)
+			if terraformType == "" {
+				continue
+			}
+			test := AcceptanceTest{Name: fn.Name.Name, FilePath: filepath.ToSlash(testFile)}
+			if dataSourceType, ok := strings.CutPrefix(terraformType, "data."); ok {
+				dataSourceTests[dataSourceType] = append(dataSourceTests[dataSourceType], test)
+			} else {
+				resourceTests[terraformType] = append(resourceTests[terraformType], test)
+			}
+		}
+	}
+
+	for _, tests := range []map[string][]AcceptanceTest{resourceTests, dataSourceTests} {
+		for _, t := range tests {
+			sort.Slice(t, func(i, j int) bool {
+				return t[i].Name < t[j].Name
+			})
+		}
+	}
+	return resourceTests, dataSourceTests
+}
+
+// buildTestDataType returns the Terraform type passed to the first acceptance.BuildTestData call of a test function
+func buildTestDataType(fn *ast.FuncDecl) string {
+	var terraformType string
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if terraformType != "" {
+			return false
+		}
+		callExpr, ok := n.(*ast.CallExpr)
+		if !ok || extractFunctionReference(callExpr.Fun) != "BuildTestData" || len(callExpr.Args) < 2 {
+			return true
+		}
+		terraformType = stringLiteralValue(callExpr.Args[1])
+		return false
+	})
+	return terraformType
+}
+
+// writeAcceptanceTestFiles writes tests/resources/<type>.json and tests/datasources/<type>.json for each Terraform type with acceptance tests
+func (index *TerraformProviderIndex) writeAcceptanceTestFiles(outputDir string, progressTracker *ProgressTracker) error {
+	var tasks []func() error
+
+	for _, service := range index.Services {
+		for kind, tests := range map[string]map[string][]AcceptanceTest{
+			"resources":   service.ResourceAcceptanceTests,
+			"datasources": service.DataSourceAcceptanceTests,
+		} {
+			for terraformType, acceptanceTests := range tests {
+				// Capture variables for closure
+				tfType := terraformType
+				testsDir := filepath.Join(outputDir, "tests", kind)
+				sdkType := service.resourceSDKType(tfType)
+				if kind == DocumentKindDataSource {
+					sdkType = service.dataSourceSDKType(tfType)
+				}
+				content := TerraformAcceptanceTests{ID: EntryID(kind, tfType, sdkType), TerraformType: tfType, Tests: acceptanceTests}
+
+				tasks = append(tasks, func() error {
+					fileName := fmt.Sprintf("%s.json", tfType)
+					if err := index.writeJSONFile(filepath.Join(testsDir, fileName), content); err != nil {
+						return fmt.Errorf("failed to write acceptance test file %s: %w", fileName, err)
+					}
+
+					progressTracker.UpdateProgress(fmt.Sprintf("tests %s", tfType))
+					return nil
+				})
+			}
+		}
+	}
+
+	return parallel.Run(tasks, index.Output.Workers)
+}
@@ -0,0 +1,102 @@
+package pkg
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractAcceptanceTests(t *testing.T) {
+	serviceDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(serviceDir, "key_vault_resource_test.go"), []byte(`package keyvault_test
+
+func TestAccKeyVault_complete(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_key_vault", "test")
+	r := KeyVaultResource{}
+	data.ResourceTest(t, r, []acceptance.TestStep{})
+}
+
+func TestAccKeyVault_basic(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_key_vault", "test")
+	r := KeyVaultResource{}
+	data.ResourceTest(t, r, []acceptance.TestStep{})
+}
+
+func TestKeyVaultName_validation(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_key_vault", "test")
+}
+
+func (KeyVaultResource) basic(data acceptance.TestData) string {
+	return ""
+}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(serviceDir, "key_vault_data_source_test.go"), []byte(`package keyvault_test
+
+func TestAccDataSourceKeyVault_basic(t *testing.T) {
+	data := acceptance.BuildTestData(t, "data.azurerm_key_vault", "test")
+	data.DataSourceTest(t, []acceptance.TestStep{})
+}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(serviceDir, "key_vault_resource.go"), []byte(`package keyvault
+
+func TestAccNotATest(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_key_vault", "test")
+}`), 0644))
+
+	resourceTests, dataSourceTests := extractAcceptanceTests(serviceDir, FileExclusions{})
+
+	resourceTestFile := filepath.ToSlash(filepath.Join(serviceDir, "key_vault_resource_test.go"))
+	assert.Equal(t, map[string][]AcceptanceTest{
+		"azurerm_key_vault": {
+			{Name: "TestAccKeyVault_basic", FilePath: resourceTestFile},
+			{Name: "TestAccKeyVault_complete", FilePath: resourceTestFile},
+		},
+	}, resourceTests)
+	assert.Equal(t, map[string][]AcceptanceTest{
+		"azurerm_key_vault": {
+			{Name: "TestAccDataSourceKeyVault_basic", FilePath: filepath.ToSlash(filepath.Join(serviceDir, "key_vault_data_source_test.go"))},
+		},
+	}, dataSourceTests)
+}
+
+func TestTerraformProviderIndex_WriteAcceptanceTestFiles(t *testing.T) {
+	// Setup
+	index := &TerraformProviderIndex{
+		Services: []ServiceRegistration{
+			{
+				ResourceAcceptanceTests: map[string][]AcceptanceTest{
+					"azurerm_key_vault": {{Name: "TestAccKeyVault_basic", FilePath: "internal/services/keyvault/key_vault_resource_test.go"}},
+				},
+				DataSourceAcceptanceTests: map[string][]AcceptanceTest{
+					"azurerm_key_vault": {{Name: "TestAccDataSourceKeyVault_basic", FilePath: "internal/services/keyvault/key_vault_data_source_test.go"}},
+				},
+			},
+		},
+	}
+	fs := afero.NewMemMapFs()
+	index.Output.Fs = fs
+	outputDir := "/test/output"
+	progressTracker := NewProgressTracker("test", 2, nil)
+
+	// Execute
+	err := index.writeAcceptanceTestFiles(outputDir, progressTracker)
+
+	// Verify
+	require.NoError(t, err)
+
+	data, err := afero.ReadFile(fs, filepath.Join(outputDir, "tests", "resources", "azurerm_key_vault.json"))
+	require.NoError(t, err)
+	var resourceTests TerraformAcceptanceTests
+	require.NoError(t, json.Unmarshal(data, &resourceTests))
+	assert.Equal(t, "azurerm_key_vault", resourceTests.TerraformType)
+	assert.Equal(t, "TestAccKeyVault_basic", resourceTests.Tests[0].Name)
+
+	data, err = afero.ReadFile(fs, filepath.Join(outputDir, "tests", "datasources", "azurerm_key_vault.json"))
+	require.NoError(t, err)
+	var dataSourceTests TerraformAcceptanceTests
+	require.NoError(t, json.Unmarshal(data, &dataSourceTests))
+	assert.Equal(t, "TestAccDataSourceKeyVault_basic", dataSourceTests.Tests[0].Name)
+}
@@ -0,0 +1,106 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Annotations are user-maintained fields keyed by Terraform type, applied to every document of the type, or by
+// EntryID, applied to that document only. A field set by an entry ID overrides the same field set by the type.
+type Annotations map[string]map[string]interface{} // "azurerm_key_vault" -> {"cost_tier": "high"}
+
+// LoadAnnotations reads the .yaml and .yml fragments of an annotations directory, for example annotations/keyvault.yaml:
+//
+//	azurerm_key_vault:
+//	  cost_tier: high
+//	  approval: approved
+//	azurerm/datasources/azurerm_key_vault/legacy_pluginsdk:
+//	  approval: pending
+//
+// Fragments are merged, a field of the same key set in two fragments is an error.
+func LoadAnnotations(dir string) (Annotations, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read annotations directory %s: %w", dir, err)
+	}
+
+	annotations := make(Annotations)
+	origins := make(map[string]string) // key and field -> fragment setting it
+	for _, entry := range entries {
+		if entry.IsDir() || (filepath.Ext(entry.Name()) != ".yaml" && filepath.Ext(entry.Name()) != ".yml") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+		var fragment Annotations
+		if err := yaml.Unmarshal(data, &fragment); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", entry.Name(), err)
+		}
+
+		for key, fields := range fragment {
+			// Fields are emitted as JSON, YAML maps with non-string keys have no JSON form
+			if _, err := json.Marshal(fields); err != nil {
+				return nil, fmt.Errorf("annotations of %s in %s can't be written as JSON: %w", key, entry.Name(), err)
+			}
+			if annotations[key] == nil {
+				annotations[key] = make(map[string]interface{})
+			}
+			for field, value := range fields {
+				origin := key + "." + field
+				if previous, exists := origins[origin]; exists {
+					return nil, fmt.Errorf("annotation %s of %s is set in both %s and %s", field, key, previous, entry.Name())
+				}
+				origins[origin] = entry.Name()
+				annotations[key][field] = value
+			}
+		}
+	}
+	return annotations, nil
+}
+
+// ApplyAnnotations sets the annotations of every document, emitted under x_annotations, and returns the sorted keys
+// matching no Terraform type or entry ID of the index, usually annotations of types removed from the provider
+func (index *TerraformProviderIndex) ApplyAnnotations(annotations Annotations) []string {
+	for i := range index.Services {
+		index.Services[i].Annotations = make(map[string]map[string]interface{})
+	}
+
+	matched := make(map[string]bool)
+	for _, document := range index.Documents() {
+		fields := make(map[string]interface{})
+		for _, key := range []string{document.TerraformType, document.ID} {
+			if _, exists := annotations[key]; !exists {
+				continue
+			}
+			matched[key] = true
+			for field, value := range annotations[key] {
+				fields[field] = value
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		for i := range index.Services {
+			if index.Services[i].ServiceName == document.Service {
+				index.Services[i].Annotations[document.ID] = fields
+			}
+		}
+	}
+	index.Annotations = annotations
+
+	var unmatched []string
+	for key := range annotations {
+		if !matched[key] {
+			unmatched = append(unmatched, key)
+		}
+	}
+	sort.Strings(unmatched)
+	return unmatched
+}
@@ -0,0 +1,93 @@
+package pkg
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeAnnotationFragment(t *testing.T, dir, name, content string) {
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+}
+
+func TestLoadAnnotations(t *testing.T) {
+	dir := t.TempDir()
+	writeAnnotationFragment(t, dir, "keyvault.yaml", `
+azurerm_key_vault:
+  cost_tier: high
+  owners: [platform, security]
+`)
+	writeAnnotationFragment(t, dir, "approvals.yml", `
+azurerm_key_vault:
+  approval: approved
+azurerm/datasources/azurerm_key_vault/legacy_pluginsdk:
+  approval: pending
+`)
+	writeAnnotationFragment(t, dir, "README.md", "not a fragment")
+
+	annotations, err := LoadAnnotations(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, Annotations{
+		"azurerm_key_vault": {
+			"cost_tier": "high",
+			"owners":    []interface{}{"platform", "security"},
+			"approval":  "approved",
+		},
+		"azurerm/datasources/azurerm_key_vault/legacy_pluginsdk": {"approval": "pending"},
+	}, annotations)
+}
+
+func TestLoadAnnotations_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeAnnotationFragment(t, dir, "a.yaml", "azurerm_key_vault:\n  cost_tier: high\n")
+	writeAnnotationFragment(t, dir, "b.yaml", "azurerm_key_vault:\n  cost_tier: low\n")
+	_, err := LoadAnnotations(dir)
+	assert.ErrorContains(t, err, "annotation cost_tier of azurerm_key_vault is set in both a.yaml and b.yaml")
+
+	dir = t.TempDir()
+	writeAnnotationFragment(t, dir, "invalid.yaml", "azurerm_key_vault: [not, a, map]\n")
+	_, err = LoadAnnotations(dir)
+	assert.ErrorContains(t, err, "failed to parse invalid.yaml")
+
+	dir = t.TempDir()
+	writeAnnotationFragment(t, dir, "keys.yaml", "azurerm_key_vault:\n  limits:\n    1: one\n")
+	_, err = LoadAnnotations(dir)
+	assert.ErrorContains(t, err, "can't be written as JSON")
+
+	_, err = LoadAnnotations(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestTerraformProviderIndex_ApplyAnnotations(t *testing.T) {
+	index, err := ScanTerraformProviderServices(filepath.Join("testharness", "internal", "services"), "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+	require.NoError(t, err)
+
+	unmatched := index.ApplyAnnotations(Annotations{
+		"azurerm_key_vault": {"cost_tier": "high", "approval": "approved"},
+		"azurerm/datasources/azurerm_key_vault/legacy_pluginsdk": {"approval": "pending"},
+		"azurerm_removed_resource":                               {"approval": "approved"},
+	})
+	assert.Equal(t, []string{"azurerm_removed_resource"}, unmatched)
+
+	annotated := make(map[string]map[string]interface{})
+	for _, document := range index.Documents() {
+		data, err := json.Marshal(document.Content)
+		require.NoError(t, err)
+		var content struct {
+			XAnnotations map[string]interface{} `json:"x_annotations"`
+		}
+		require.NoError(t, json.Unmarshal(data, &content))
+		if content.XAnnotations != nil {
+			annotated[document.ID] = content.XAnnotations
+		}
+	}
+	assert.Equal(t, map[string]map[string]interface{}{
+		"azurerm/resources/azurerm_key_vault/legacy_pluginsdk":   {"cost_tier": "high", "approval": "approved"},
+		"azurerm/datasources/azurerm_key_vault/legacy_pluginsdk": {"cost_tier": "high", "approval": "pending"},
+	}, annotated)
+}
@@ -0,0 +1,345 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxSpecRefDepth limits how many $ref and allOf levels are followed when collecting the properties of a definition
+const maxSpecRefDepth = 10
+
+// ignoredDriftAttributes are provider attributes that address a resource rather than set one of its API properties
+var ignoredDriftAttributes = map[string]bool{
+	"name":                true,
+	"resource_group_name": true,
+}
+
+// ignoredDriftProperties are API properties of every ARM resource that the provider exposes through its ID
+var ignoredDriftProperties = map[string]bool{
+	"id":         true,
+	"name":       true,
+	"type":       true,
+	"etag":       true,
+	"systemData": true,
+}
+
+// APIDrift compares the top level schema attributes of a resource with the properties of the API spec of its Azure
+// Resource Manager resource type. Attributes and properties are matched by name ignoring case and underscores, and
+// the properties of the ARM "properties" envelope count as top level properties, the way the provider flattens them.
+type APIDrift struct {
+	ID                  string   `json:"id"`                    // "azurerm/resources/azurerm_key_vault/legacy_pluginsdk", see EntryID
+	TerraformType       string   `json:"terraform_type"`        // "azurerm_key_vault"
+	AzureResourceType   string   `json:"azure_resource_type"`   // "Microsoft.KeyVault/vaults"
+	APIVersion          string   `json:"api_version"`           // "2023-07-01"
+	SpecFile            string   `json:"spec_file"`             // "keyvault/stable/2023-07-01/keyvault.json", relative to the specs directory
+	MissingFromAPI      []string `json:"missing_from_api"`      // Provider attributes without API property, ["legacy_setting"]
+	MissingFromProvider []string `json:"missing_from_provider"` // Writable API properties without provider attribute, ["publicNetworkAccess"]
+}
+
+// APIDriftReport lists the resources whose schema drifted from the API specs, written to audit/api-drift.json
+type APIDriftReport struct {
+	SpecsDir string     `json:"specs_dir"` // "./azure-rest-api-specs/specification"
+	Checked  int        `json:"checked"`   // Number of resources compared with an API spec
+	NoSpec   []string   `json:"no_spec"`   // Sorted Terraform types of resources with an ARM resource type but no spec
+	Drifts   []APIDrift `json:"drifts"`    // Sorted by Terraform type
+}
+
+// apiSpecProperties are the properties of the PUT request body of an ARM resource type in an API spec file
+type apiSpecProperties struct {
+	file       string          // Relative to the specs directory
+	apiVersion string          // "2023-07-01"
+	all        map[string]bool // Normalized names of all properties, read-only ones included
+	writable   []string        // Names of the properties that can be set
+}
+
+// DetectAPIDrift cross-references the schema of every resource with an Azure Resource Manager resource type against
+// the user-supplied Azure REST API specs in specsDir, OpenAPI 2.0 files such as the specification directory of
+// azure-rest-api-specs. The spec of the API version of the resource's go-azure-sdk packages is used, the latest
+// spec otherwise. It flags provider attributes missing from the API and API properties missing from the provider.
+// The report is also written to audit/api-drift.json.
+func (index *TerraformProviderIndex) DetectAPIDrift(specsDir string) (*APIDriftReport, error) {
+	if info, err := os.Stat(specsDir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("API specs directory %s is not a directory", specsDir)
+	}
+	specs, err := loadAPISpecs(specsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &APIDriftReport{SpecsDir: filepath.ToSlash(specsDir), NoSpec: []string{}, Drifts: []APIDrift{}}
+	for _, service := range index.Services {
+		for terraformType, armType := range service.ResourceArmTypes {
+			schema := service.ResourceSchemas[terraformType]
+			if len(schema) == 0 {
+				continue
+			}
+			spec := selectAPISpec(specs[strings.ToLower(armType)], service.ResourceSDKPackages[terraformType])
+			if spec == nil {
+				report.NoSpec = append(report.NoSpec, terraformType)
+				continue
+			}
+			report.Checked++
+
+			drift := APIDrift{
+				ID:                  EntryID(DocumentKindResource, terraformType, service.resourceSDKType(terraformType)),
+				TerraformType:       terraformType,
+				AzureResourceType:   armType,
+				APIVersion:          spec.apiVersion,
+				SpecFile:            spec.file,
+				MissingFromAPI:      []string{},
+				MissingFromProvider: []string{},
+			}
+			attributes := make(map[string]bool)
+			for _, attribute := range schema {
+				attributes[normalizeDriftName(attribute.Name)] = true
+				if !ignoredDriftAttributes[attribute.Name] && !spec.all[normalizeDriftName(attribute.Name)] {
+					drift.MissingFromAPI = append(drift.MissingFromAPI, attribute.Name)
+				}
+			}
+			for _, property := range spec.writable {
+				if !attributes[normalizeDriftName(property)] {
+					drift.MissingFromProvider = append(drift.MissingFromProvider, property)
+				}
+			}
+			if len(drift.MissingFromAPI)+len(drift.MissingFromProvider) > 0 {
+				sort.Strings(drift.MissingFromAPI)
+				sort.Strings(drift.MissingFromProvider)
+				report.Drifts = append(report.Drifts, drift)
+			}
+		}
+	}
+
+	sort.Strings(report.NoSpec)
+	sortAPIDrifts(report.Drifts)
+	index.APIDrift = report
+	return report, nil
+}
+
+// sortAPIDrifts sorts API drifts by Terraform type
+func sortAPIDrifts(drifts []APIDrift) {
+	sort.Slice(drifts, func(i, j int) bool {
+		return drifts[i].TerraformType < drifts[j].TerraformType
+	})
+}
+
+// normalizeDriftName normalizes attribute and property names, "public_network_access" and "publicNetworkAccess"
+// both result in "publicnetworkaccess"
+func normalizeDriftName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// selectAPISpec picks the spec of the API version of an SDK package of the resource, or the latest spec
+func selectAPISpec(specs []*apiSpecProperties, sdkPackages []string) *apiSpecProperties {
+	if len(specs) == 0 {
+		return nil
+	}
+	for _, importPath := range sdkPackages {
+		for _, segment := range strings.Split(importPath, "/") {
+			if !apiVersionSegmentPattern.MatchString(segment) {
+				continue
+			}
+			for _, spec := range specs {
+				if spec.apiVersion == segment {
+					return spec
+				}
+			}
+		}
+	}
+	latest := specs[0]
+	for _, spec := range specs[1:] {
+		if spec.apiVersion > latest.apiVersion || (spec.apiVersion == latest.apiVersion && spec.file < latest.file) {
+			latest = spec
+		}
+	}
+	return latest
+}
+
+// apiSpecDocument is the part of an OpenAPI 2.0 document the drift detection reads
+type apiSpecDocument struct {
+	Swagger string `json:"swagger"`
+	Info    struct {
+		Version string `json:"version"`
+	} `json:"info"`
+	Paths       map[string]map[string]json.RawMessage `json:"paths"`
+	Definitions map[string]*apiSpecSchema             `json:"definitions"`
+}
+
+type apiSpecOperation struct {
+	Parameters []struct {
+		In     string         `json:"in"`
+		Schema *apiSpecSchema `json:"schema"`
+	} `json:"parameters"`
+}
+
+type apiSpecSchema struct {
+	Ref        string                    `json:"$ref"`
+	Properties map[string]*apiSpecSchema `json:"properties"`
+	AllOf      []*apiSpecSchema          `json:"allOf"`
+	ReadOnly   bool                      `json:"readOnly"`
+}
+
+// apiSpecLoader reads spec files once, following $ref across the files of the specs directory
+type apiSpecLoader struct {
+	specsDir  string
+	documents map[string]*apiSpecDocument // Absolute path -> document, nil when the file isn't a spec
+}
+
+// loadAPISpecs collects the properties of the PUT request body of each ARM resource type by lower-cased type, for
+// every spec file in specsDir, examples excluded
+func loadAPISpecs(specsDir string) (map[string][]*apiSpecProperties, error) {
+	loader := &apiSpecLoader{specsDir: specsDir, documents: make(map[string]*apiSpecDocument)}
+	specs := make(map[string][]*apiSpecProperties)
+	err := filepath.WalkDir(specsDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if entry.Name() == "examples" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".json" {
+			return nil
+		}
+		document := loader.document(path)
+		if document == nil {
+			return nil
+		}
+		relPath, err := filepath.Rel(specsDir, path)
+		if err != nil {
+			return err
+		}
+
+		for specPath, pathItem := range document.Paths {
+			armType := armResourceTypeOfPath(specPath)
+			if armType == "" {
+				continue
+			}
+			var put apiSpecOperation
+			if pathItem["put"] == nil || json.Unmarshal(pathItem["put"], &put) != nil {
+				continue
+			}
+			for _, parameter := range put.Parameters {
+				if parameter.In != "body" || parameter.Schema == nil {
+					continue
+				}
+				properties := &apiSpecProperties{file: filepath.ToSlash(relPath), apiVersion: document.Info.Version, all: make(map[string]bool)}
+				loader.collectProperties(path, parameter.Schema, properties, 0)
+				sort.Strings(properties.writable)
+				key := strings.ToLower(armType)
+				specs[key] = append(specs[key], properties)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API specs directory %s: %w", specsDir, err)
+	}
+	return specs, nil
+}
+
+// document returns the parsed spec file, nil when it isn't an OpenAPI 2.0 document
+func (l *apiSpecLoader) document(path string) *apiSpecDocument {
+	if absPath, err := filepath.Abs(path); err == nil {
+		path = absPath
+	}
+	if document, loaded := l.documents[path]; loaded {
+		return document
+	}
+	l.documents[path] = nil
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var document apiSpecDocument
+	if json.Unmarshal(data, &document) != nil || document.Swagger == "" {
+		return nil
+	}
+	l.documents[path] = &document
+	return &document
+}
+
+// collectProperties adds the properties of a schema of the spec file at path, following $ref, allOf and the ARM
+// "properties" envelope
+func (l *apiSpecLoader) collectProperties(path string, schema *apiSpecSchema, properties *apiSpecProperties, depth int) {
+	if schema == nil || depth > maxSpecRefDepth {
+		return
+	}
+	if schema.Ref != "" {
+		refPath, resolved := l.resolveRef(path, schema.Ref)
+		l.collectProperties(refPath, resolved, properties, depth+1)
+		return
+	}
+	for _, part := range schema.AllOf {
+		l.collectProperties(path, part, properties, depth+1)
+	}
+	for name, property := range schema.Properties {
+		if name == "properties" {
+			l.collectProperties(path, property, properties, depth+1)
+			continue
+		}
+		if ignoredDriftProperties[name] || properties.all[normalizeDriftName(name)] {
+			continue
+		}
+		properties.all[normalizeDriftName(name)] = true
+		if !property.ReadOnly && !l.readOnly(path, property, depth+1) {
+			properties.writable = append(properties.writable, name)
+		}
+	}
+}
+
+// readOnly reports whether a property referencing a definition is read-only through that definition
+func (l *apiSpecLoader) readOnly(path string, schema *apiSpecSchema, depth int) bool {
+	if schema == nil || schema.Ref == "" || depth > maxSpecRefDepth {
+		return false
+	}
+	refPath, resolved := l.resolveRef(path, schema.Ref)
+	return resolved != nil && (resolved.ReadOnly || l.readOnly(refPath, resolved, depth+1))
+}
+
+// resolveRef resolves a definition reference such as #/definitions/Vault or ../common/types.json#/definitions/Resource
+// relative to the spec file at path, returning the file the definition is declared in
+func (l *apiSpecLoader) resolveRef(path, ref string) (string, *apiSpecSchema) {
+	file, pointer, found := strings.Cut(ref, "#/definitions/")
+	if !found {
+		return path, nil
+	}
+	if file != "" {
+		path = filepath.Join(filepath.Dir(path), filepath.FromSlash(file))
+	}
+	document := l.document(path)
+	if document == nil {
+		return path, nil
+	}
+	return path, document.Definitions[pointer]
+}
+
+// armResourceTypeOfPath returns the ARM resource type of a resource instance path, "Microsoft.KeyVault/vaults/secrets"
+// for /subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.KeyVault/vaults/{vaultName}/secrets/{secretName},
+// "" for collection paths and paths outside a resource provider
+func armResourceTypeOfPath(specPath string) string {
+	position := strings.LastIndex(strings.ToLower(specPath), "/providers/")
+	if position < 0 {
+		return ""
+	}
+	segments := strings.Split(strings.Trim(specPath[position+len("/providers/"):], "/"), "/")
+	if len(segments) < 3 || len(segments)%2 == 0 || strings.HasPrefix(segments[0], "{") {
+		return ""
+	}
+	types := []string{segments[0]}
+	for i := 1; i < len(segments); i += 2 {
+		types = append(types, segments[i])
+	}
+	return strings.Join(types, "/")
+}
+
+// writeAPIDriftReportFile writes audit/api-drift.json
+func (index *TerraformProviderIndex) writeAPIDriftReportFile(outputDir string) error {
+	return index.writeJSONFile(filepath.Join(outputDir, "audit", "api-drift.json"), index.APIDrift)
+}
@@ -0,0 +1,181 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKeyVaultSpec = `{
+  "swagger": "2.0",
+  "info": {"version": "%s"},
+  "paths": {
+    "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.KeyVault/vaults/{vaultName}": {
+      "parameters": [],
+      "put": {
+        "parameters": [
+          {"name": "vaultName", "in": "path"},
+          {"name": "parameters", "in": "body", "schema": {"$ref": "#/definitions/VaultCreateOrUpdateParameters"}}
+        ]
+      }
+    },
+    "/subscriptions/{subscriptionId}/providers/Microsoft.KeyVault/vaults": {
+      "get": {}
+    }
+  },
+  "definitions": {
+    "VaultCreateOrUpdateParameters": {
+      "allOf": [{"$ref": "../../../common/types.json#/definitions/TrackedResource"}],
+      "properties": {
+        "properties": {"$ref": "#/definitions/VaultProperties"}
+      }
+    },
+    "VaultProperties": {
+      "properties": {
+        "tenantId": {"type": "string"},
+        "sku": {"$ref": "#/definitions/Sku"},
+        "enableRbacAuthorization": {"type": "boolean"},
+        "%s": {"type": "string"},
+        "vaultUri": {"type": "string", "readOnly": true},
+        "hsmPoolResourceId": {"$ref": "#/definitions/ResourceId"}
+      }
+    },
+    "Sku": {"properties": {"name": {"type": "string"}}},
+    "ResourceId": {"type": "string", "readOnly": true}
+  }
+}`
+
+const testCommonTypesSpec = `{
+  "swagger": "2.0",
+  "info": {"version": "1.0"},
+  "paths": {},
+  "definitions": {
+    "TrackedResource": {
+      "properties": {
+        "id": {"type": "string", "readOnly": true},
+        "name": {"type": "string", "readOnly": true},
+        "location": {"type": "string"},
+        "tags": {"type": "object"}
+      }
+    }
+  }
+}`
+
+func writeTestAPISpecs(t *testing.T) string {
+	specsDir := t.TempDir()
+	for path, content := range map[string]string{
+		"keyvault/stable/2023-07-01/keyvault.json":             fmt.Sprintf(testKeyVaultSpec, "2023-07-01", "publicNetworkAccess"),
+		"keyvault/stable/2024-11-01/keyvault.json":             fmt.Sprintf(testKeyVaultSpec, "2024-11-01", "networkSecurityPerimeter"),
+		"keyvault/stable/2023-07-01/examples/createVault.json": `{"parameters": {}}`,
+		"common/types.json":                                    testCommonTypesSpec,
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(specsDir, filepath.Dir(path)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(specsDir, path), []byte(content), 0644))
+	}
+	return specsDir
+}
+
+func newAPIDriftTestIndex() *TerraformProviderIndex {
+	return &TerraformProviderIndex{
+		Services: []ServiceRegistration{
+			{
+				ServiceName:        "keyvault",
+				SupportedResources: map[string]string{"azurerm_key_vault": "resourceKeyVault", "azurerm_key_vault_key": "resourceKeyVaultKey"},
+				ResourceArmTypes: map[string]string{
+					"azurerm_key_vault":     "Microsoft.KeyVault/vaults",
+					"azurerm_key_vault_key": "Microsoft.KeyVault/vaults/keys",
+				},
+				ResourceSDKPackages: map[string][]string{
+					"azurerm_key_vault": {"github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2023-07-01/vaults"},
+				},
+				ResourceSchemas: map[string][]SchemaAttribute{
+					"azurerm_key_vault": {
+						{Name: "name"}, {Name: "resource_group_name"}, {Name: "location"}, {Name: "tags"},
+						{Name: "tenant_id"}, {Name: "sku"}, {Name: "enable_rbac_authorization"}, {Name: "vault_uri"},
+						{Name: "contact"},
+					},
+					"azurerm_key_vault_key": {{Name: "name"}},
+				},
+			},
+		},
+	}
+}
+
+func TestTerraformProviderIndex_DetectAPIDrift(t *testing.T) {
+	specsDir := writeTestAPISpecs(t)
+	index := newAPIDriftTestIndex()
+
+	report, err := index.DetectAPIDrift(specsDir)
+	require.NoError(t, err)
+
+	assert.Same(t, report, index.APIDrift)
+	assert.Equal(t, 1, report.Checked)
+	assert.Equal(t, []string{"azurerm_key_vault_key"}, report.NoSpec)
+	assert.Equal(t, []APIDrift{
+		{
+			ID:                  "azurerm/resources/azurerm_key_vault/legacy_pluginsdk",
+			TerraformType:       "azurerm_key_vault",
+			AzureResourceType:   "Microsoft.KeyVault/vaults",
+			APIVersion:          "2023-07-01",
+			SpecFile:            "keyvault/stable/2023-07-01/keyvault.json",
+			MissingFromAPI:      []string{"contact"},
+			MissingFromProvider: []string{"publicNetworkAccess"},
+		},
+	}, report.Drifts)
+}
+
+func TestTerraformProviderIndex_DetectAPIDrift_LatestSpec(t *testing.T) {
+	specsDir := writeTestAPISpecs(t)
+	index := newAPIDriftTestIndex()
+	index.Services[0].ResourceSDKPackages = nil
+
+	report, err := index.DetectAPIDrift(specsDir)
+	require.NoError(t, err)
+
+	require.Len(t, report.Drifts, 1)
+	assert.Equal(t, "2024-11-01", report.Drifts[0].APIVersion)
+	assert.Equal(t, []string{"networkSecurityPerimeter"}, report.Drifts[0].MissingFromProvider)
+}
+
+func TestTerraformProviderIndex_DetectAPIDrift_NotADirectory(t *testing.T) {
+	_, err := newAPIDriftTestIndex().DetectAPIDrift(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorContains(t, err, "is not a directory")
+}
+
+func TestTerraformProviderIndex_WriteAPIDriftReportFile(t *testing.T) {
+	specsDir := writeTestAPISpecs(t)
+	index := newAPIDriftTestIndex()
+	_, err := index.DetectAPIDrift(specsDir)
+	require.NoError(t, err)
+
+	fs := afero.NewMemMapFs()
+	index.Output.Fs = fs
+	require.NoError(t, index.writeAPIDriftReportFile("/index"))
+
+	data, err := afero.ReadFile(fs, filepath.Join("/index", "audit", "api-drift.json"))
+	require.NoError(t, err)
+	var report APIDriftReport
+	require.NoError(t, json.Unmarshal(data, &report))
+	assert.Equal(t, *index.APIDrift, report)
+}
+
+func TestArmResourceTypeOfPath(t *testing.T) {
+	testCases := map[string]string{
+		"/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.KeyVault/vaults/{vaultName}":                      "Microsoft.KeyVault/vaults",
+		"/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.KeyVault/vaults/{vaultName}/secrets/{secretName}": "Microsoft.KeyVault/vaults/secrets",
+		"/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Web/sites/{name}/config/web":                      "Microsoft.Web/sites/config",
+		"/{scope}/providers/Microsoft.Authorization/roleAssignments/{roleAssignmentName}":                                                         "Microsoft.Authorization/roleAssignments",
+		"/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.KeyVault/vaults":                                  "",
+		"/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}":                                                                      "",
+		"/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/{resourceProviderNamespace}/{resourceType}/{resourceName}":  "",
+	}
+	for specPath, expected := range testCases {
+		assert.Equal(t, expected, armResourceTypeOfPath(specPath), specPath)
+	}
+}
@@ -0,0 +1,348 @@
+package pkg
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// APIOperation is an Azure API operation called by a resource through go-azure-sdk
+type APIOperation struct {
+	SDKPackage string `json:"sdk_package" protobuf:"1"`    // "github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2023-07-01/vaults"
+	Operation  string `json:"operation" protobuf:"2"`      // "VaultsClient.CreateOrUpdate"
+	HTTPMethod string `json:"http_method" protobuf:"3"`    // "PUT"
+	Path       string `json:"path,omitempty" protobuf:"4"` // "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.KeyVault/vaults/{vaultName}"
+}
+
+// sdkOperationSuffixes are appended to operation names by the helpers go-azure-sdk generates around an operation,
+// CreateOrUpdateThenPoll and ListComplete send the requests of CreateOrUpdate and List
+var sdkOperationSuffixes = []string{"CompleteMatchingPredicate", "Complete", "ThenPoll"}
+
+// sdkPackageMetadata holds the operations and resource ID path templates declared by a go-azure-sdk package
+type sdkPackageMetadata struct {
+	operations  map[string][]APIOperation // "CreateOrUpdate" -> operations of the package's clients
+	idTemplates map[string]string         // "VaultId" -> "/subscriptions/{subscriptionId}/..."
+}
+
+// sdkOperationResolver maps calls of go-azure-sdk clients to the HTTP method and path of the operation, read from the
+// request options of the vendored SDK under rootDir/vendor
+type sdkOperationResolver struct {
+	rootDir string
+
+	mu    sync.Mutex
+	cache map[string]*sdkPackageMetadata // import path -> package metadata
+}
+
+func newSDKOperationResolver(rootDir string) *sdkOperationResolver {
+	return &sdkOperationResolver{
+		rootDir: rootDir,
+		cache:   make(map[string]*sdkPackageMetadata),
+	}
+}
+
+// extractLegacyAPIOperationsFromPackage lists the operations of the given SDK packages called by the CRUD functions of a legacy resource
+func (r *sdkOperationResolver) extractLegacyAPIOperationsFromPackage(crudMethods *LegacyResourceCRUDFunctions, sdkPackages []string, packageInfo *gophon.PackageInfo) []APIOperation {
+	if crudMethods == nil {
+		return nil
+	}
+	var functions []*ast.FuncDecl
+	for _, name := range []string{crudMethods.CreateMethod, crudMethods.ReadMethod, crudMethods.UpdateMethod, crudMethods.DeleteMethod} {
+		if name != "" {
+			functions = append(functions, findFunctionDecl(packageInfo, name))
+		}
+	}
+	return r.apiOperationsCalledBy(sdkPackages, functions...)
+}
+
+// extractTypedAPIOperationsFromPackage lists the operations of the given SDK packages called by the CRUD methods of a typed resource
+func (r *sdkOperationResolver) extractTypedAPIOperationsFromPackage(structName string, sdkPackages []string, packageInfo *gophon.PackageInfo) []APIOperation {
+	var functions []*ast.FuncDecl
+	for _, name := range []string{"Create", "Read", "Update", "Delete"} {
+		functions = append(functions, findMethodDecl(packageInfo, structName, name))
+	}
+	return r.apiOperationsCalledBy(sdkPackages, functions...)
+}
+
+// apiOperationsCalledBy matches the client calls of the functions against the operations of the SDK packages. The
+// client type isn't known from the AST, so a call matches the operations of the same name in any of the packages.
+func (r *sdkOperationResolver) apiOperationsCalledBy(sdkPackages []string, functions ...*ast.FuncDecl) []APIOperation {
+	if len(sdkPackages) == 0 {
+		return nil
+	}
+	called := make(map[string]bool)
+	for _, fn := range functions {
+		for _, name := range sdkClientCalls(fn) {
+			called[name] = true
+		}
+	}
+
+	seen := make(map[APIOperation]bool)
+	var result []APIOperation
+	for _, importPath := range sdkPackages {
+		metadata := r.packageMetadata(importPath)
+		for name := range called {
+			for _, operation := range metadata.operations[name] {
+				if !seen[operation] {
+					seen[operation] = true
+					result = append(result, operation)
+				}
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].SDKPackage != result[j].SDKPackage {
+			return result[i].SDKPackage < result[j].SDKPackage
+		}
+		return result[i].Operation < result[j].Operation
+	})
+	return result
+}
+
+// sdkClientCalls returns the operation names of method calls taking a context first, the signature of go-azure-sdk
+// client operations, such as client.CreateOrUpdateThenPoll(ctx, id, payload) resulting in "CreateOrUpdate"
+func sdkClientCalls(fn *ast.FuncDecl) []string {
+	if fn == nil || fn.Body == nil {
+		return nil
+	}
+	var names []string
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		callExpr, ok := n.(*ast.CallExpr)
+		if !ok || len(callExpr.Args) == 0 {
+			return true
+		}
+		selector, ok := callExpr.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		ctx, ok := callExpr.Args[0].(*ast.Ident)
+		if !ok || !strings.Contains(strings.ToLower(ctx.Name), "ctx") {
+			return true
+		}
+		name := selector.Sel.Name
+		for _, suffix := range sdkOperationSuffixes {
+			if trimmed, found := strings.CutSuffix(name, suffix); found && trimmed != "" {
+				name = trimmed
+				break
+			}
+		}
+		names = append(names, name)
+		return true
+	})
+	return names
+}
+
+// packageMetadata parses the vendored SDK package once and returns its operations and resource ID templates
+func (r *sdkOperationResolver) packageMetadata(importPath string) *sdkPackageMetadata {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.loadPackageMetadata(importPath)
+}
+
+// loadPackageMetadata parses a package, the caller holds the lock. Resource IDs of other packages, such as the
+// commonids of go-azure-helpers, are loaded recursively.
+func (r *sdkOperationResolver) loadPackageMetadata(importPath string) *sdkPackageMetadata {
+	if metadata, exists := r.cache[importPath]; exists {
+		return metadata
+	}
+	metadata := &sdkPackageMetadata{
+		operations:  make(map[string][]APIOperation),
+		idTemplates: make(map[string]string),
+	}
+	r.cache[importPath] = metadata
+
+	dir := filepath.Join(r.rootDir, "vendor", filepath.FromSlash(importPath))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return metadata
+	}
+	var files []*ast.File
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, entry.Name()), nil, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		files = append(files, file)
+	}
+
+	// ID templates first, operation paths are built from them
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == "ID" && receiverTypeName(fn) != "" {
+				if template := idPathTemplate(fn); template != "" {
+					metadata.idTemplates[receiverTypeName(fn)] = template
+				}
+			}
+		}
+	}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || fn.Body == nil {
+				continue
+			}
+			requestOptions := findRequestOptions(fn)
+			if requestOptions == nil {
+				continue
+			}
+			operation := APIOperation{
+				SDKPackage: importPath,
+				Operation:  receiverTypeName(fn) + "." + fn.Name.Name,
+				HTTPMethod: httpMethodName(compositeLitField(requestOptions, "HttpMethod")),
+				Path:       r.operationPath(compositeLitField(requestOptions, "Path"), fn, file, importPath, metadata),
+			}
+			metadata.operations[fn.Name.Name] = append(metadata.operations[fn.Name.Name], operation)
+		}
+	}
+	return metadata
+}
+
+// findRequestOptions returns the client.RequestOptions literal describing the request an operation sends
+func findRequestOptions(fn *ast.FuncDecl) *ast.CompositeLit {
+	var result *ast.CompositeLit
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if result != nil {
+			return false
+		}
+		if compLit, ok := n.(*ast.CompositeLit); ok {
+			if selector, ok := compLit.Type.(*ast.SelectorExpr); ok && selector.Sel.Name == "RequestOptions" {
+				result = compLit
+				return false
+			}
+		}
+		return true
+	})
+	return result
+}
+
+// httpMethodName converts http.MethodPut or "PUT" to "PUT"
+func httpMethodName(expr ast.Expr) string {
+	if selector, ok := expr.(*ast.SelectorExpr); ok {
+		return strings.ToUpper(strings.TrimPrefix(selector.Sel.Name, "Method"))
+	}
+	return strings.ToUpper(stringLiteralValue(expr))
+}
+
+// operationPath evaluates the Path of request options: id.ID() results in the path template of the ID type,
+// fmt.Sprintf("%s/listKeys", id.ID()) in the template followed by /listKeys
+func (r *sdkOperationResolver) operationPath(expr ast.Expr, fn *ast.FuncDecl, file *ast.File, importPath string, metadata *sdkPackageMetadata) string {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return stringLiteralValue(e)
+	case *ast.CallExpr:
+		selector, ok := e.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return ""
+		}
+		if selector.Sel.Name == "ID" {
+			idIdent, ok := selector.X.(*ast.Ident)
+			if !ok {
+				return ""
+			}
+			return r.idTemplate(parameterType(fn, idIdent.Name), file, importPath, metadata)
+		}
+		if pkgIdent, ok := selector.X.(*ast.Ident); ok && pkgIdent.Name == "fmt" && selector.Sel.Name == "Sprintf" && len(e.Args) > 0 {
+			format := stringLiteralValue(e.Args[0])
+			if format == "" {
+				return ""
+			}
+			for _, arg := range e.Args[1:] {
+				value := r.operationPath(arg, fn, file, importPath, metadata)
+				if value == "" {
+					value = "{" + exprName(arg) + "}"
+				}
+				format = strings.Replace(format, "%s", value, 1)
+			}
+			return format
+		}
+	}
+	return ""
+}
+
+// idTemplate returns the path template of an ID type declared in the SDK package or in an imported package
+func (r *sdkOperationResolver) idTemplate(typeExpr ast.Expr, file *ast.File, importPath string, metadata *sdkPackageMetadata) string {
+	switch t := typeExpr.(type) {
+	case *ast.Ident:
+		return metadata.idTemplates[t.Name]
+	case *ast.SelectorExpr:
+		alias, ok := t.X.(*ast.Ident)
+		if !ok {
+			return ""
+		}
+		if idImportPath := importPathOfAlias(file, alias.Name); idImportPath != "" && idImportPath != importPath {
+			return r.loadPackageMetadata(idImportPath).idTemplates[t.Sel.Name]
+		}
+	}
+	return ""
+}
+
+// parameterType returns the type expression of the named parameter of a function
+func parameterType(fn *ast.FuncDecl, name string) ast.Expr {
+	for _, field := range fn.Type.Params.List {
+		for _, ident := range field.Names {
+			if ident.Name == name {
+				return field.Type
+			}
+		}
+	}
+	return nil
+}
+
+// idPathTemplate converts the ID() method of a resource ID, formatting "/subscriptions/%s/resourceGroups/%s" with
+// id.SubscriptionId and id.ResourceGroupName, to "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}"
+func idPathTemplate(fn *ast.FuncDecl) string {
+	if fn.Body == nil {
+		return ""
+	}
+	var format string
+	var args []ast.Expr
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.BasicLit:
+			if value := stringLiteralValue(e); format == "" && strings.HasPrefix(value, "/") {
+				format = value
+			}
+		case *ast.CallExpr:
+			if selector, ok := e.Fun.(*ast.SelectorExpr); ok && selector.Sel.Name == "Sprintf" && len(e.Args) > 1 {
+				args = e.Args[1:]
+			}
+		}
+		return true
+	})
+	if format == "" {
+		return ""
+	}
+	for _, arg := range args {
+		format = strings.Replace(format, "%s", "{"+exprName(arg)+"}", 1)
+	}
+	return format
+}
+
+// exprName names a format argument after its field, id.ResourceGroupName results in "resourceGroupName"
+func exprName(expr ast.Expr) string {
+	var name string
+	switch e := expr.(type) {
+	case *ast.SelectorExpr:
+		name = e.Sel.Name
+	case *ast.Ident:
+		name = e.Name
+	case *ast.CallExpr:
+		// id.ID() of an unresolved ID type results in "id"
+		if selector, ok := e.Fun.(*ast.SelectorExpr); ok {
+			return exprName(selector.X)
+		}
+		return "value"
+	default:
+		return "value"
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
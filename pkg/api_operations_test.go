@@ -0,0 +1,136 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testVaultsPackage = "github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2023-07-01/vaults"
+
+// writeTestVaultsSDK writes a vendored go-azure-sdk package with operations addressed by its own and a common ID
+func writeTestVaultsSDK(t *testing.T, rootDir string) {
+	writeTestPackage(t, rootDir, "vendor/"+testVaultsPackage, "id_vault.go", `package vaults
+
+type VaultId struct{}
+
+func (id VaultId) ID() string {
+	fmtString := "/subscriptions/%s/resourceGroups/%s/providers/Microsoft.KeyVault/vaults/%s"
+	return fmt.Sprintf(fmtString, id.SubscriptionId, id.ResourceGroupName, id.VaultName)
+}`)
+	writeTestPackage(t, rootDir, "vendor/"+testVaultsPackage, "methods.go", `package vaults
+
+import (
+	"github.com/hashicorp/go-azure-helpers/resourcemanager/commonids"
+)
+
+func (c VaultsClient) CreateOrUpdate(ctx context.Context, id VaultId, input VaultCreateOrUpdateParameters) (result CreateOrUpdateOperationResponse, err error) {
+	opts := client.RequestOptions{
+		ContentType: "application/json; charset=utf-8",
+		HttpMethod:  http.MethodPut,
+		Path:        id.ID(),
+	}
+	return
+}
+
+func (c VaultsClient) CreateOrUpdateThenPoll(ctx context.Context, id VaultId, input VaultCreateOrUpdateParameters) error {
+	return c.CreateOrUpdate(ctx, id, input)
+}
+
+func (c VaultsClient) Get(ctx context.Context, id VaultId) (result GetOperationResponse, err error) {
+	opts := client.RequestOptions{
+		HttpMethod: http.MethodGet,
+		Path:       id.ID(),
+	}
+	return
+}
+
+func (c VaultsClient) ListByResourceGroup(ctx context.Context, id commonids.ResourceGroupId) (result ListByResourceGroupOperationResponse, err error) {
+	opts := client.RequestOptions{
+		HttpMethod: http.MethodGet,
+		Path:       fmt.Sprintf("%s/providers/Microsoft.KeyVault/vaults", id.ID()),
+	}
+	return
+}
+
+func (c VaultsClient) Delete(ctx context.Context, id VaultId) (result DeleteOperationResponse, err error) {
+	opts := client.RequestOptions{
+		HttpMethod: http.MethodDelete,
+		Path:       id.ID(),
+	}
+	return
+}`)
+	writeTestPackage(t, rootDir, "vendor/github.com/hashicorp/go-azure-helpers/resourcemanager/commonids", "resource_group.go", `package commonids
+
+type ResourceGroupId struct{}
+
+func (id ResourceGroupId) ID() string {
+	fmtString := "/subscriptions/%s/resourceGroups/%s"
+	return fmt.Sprintf(fmtString, id.SubscriptionId, id.ResourceGroupName)
+}`)
+}
+
+func TestExtractLegacyAPIOperationsFromPackage(t *testing.T) {
+	rootDir := t.TempDir()
+	writeTestVaultsSDK(t, rootDir)
+
+	source := `package keyvault
+
+func resourceKeyVaultCreate(d *pluginsdk.ResourceData, meta interface{}) error {
+	client := meta.(*clients.Client).KeyVault.VaultsClient
+	ctx, cancel := timeouts.ForCreate(meta.(*clients.Client).StopContext, d)
+	defer cancel()
+
+	if _, err := client.ListByResourceGroupComplete(ctx, commonids.NewResourceGroupID("sub", "rg")); err != nil {
+		return err
+	}
+	if err := client.CreateOrUpdateThenPoll(ctx, id, parameters); err != nil {
+		return err
+	}
+	return nil
+}
+
+func resourceKeyVaultRead(d *pluginsdk.ResourceData, meta interface{}) error {
+	name := d.Get("name").(string)
+	resp, err := client.Get(ctx, *id)
+	return err
+}`
+	packageInfo := parsePackageInfo(t, source)
+	resolver := newSDKOperationResolver(rootDir)
+
+	operations := resolver.extractLegacyAPIOperationsFromPackage(&LegacyResourceCRUDFunctions{
+		CreateMethod: "resourceKeyVaultCreate",
+		ReadMethod:   "resourceKeyVaultRead",
+	}, []string{testVaultsPackage}, packageInfo)
+
+	vaultPath := "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.KeyVault/vaults/{vaultName}"
+	assert.Equal(t, []APIOperation{
+		{SDKPackage: testVaultsPackage, Operation: "VaultsClient.CreateOrUpdate", HTTPMethod: "PUT", Path: vaultPath},
+		{SDKPackage: testVaultsPackage, Operation: "VaultsClient.Get", HTTPMethod: "GET", Path: vaultPath},
+		{SDKPackage: testVaultsPackage, Operation: "VaultsClient.ListByResourceGroup", HTTPMethod: "GET", Path: "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.KeyVault/vaults"},
+	}, operations)
+}
+
+func TestExtractTypedAPIOperationsFromPackage(t *testing.T) {
+	rootDir := t.TempDir()
+	writeTestVaultsSDK(t, rootDir)
+
+	source := `package keyvault
+
+type KeyVaultResource struct{}
+
+func (r KeyVaultResource) Delete() sdk.ResourceFunc {
+	return sdk.ResourceFunc{
+		Func: func(ctx context.Context, metadata sdk.ResourceMetaData) error {
+			return metadata.Client.KeyVault.VaultsClient.Delete(ctx, *id)
+		},
+	}
+}`
+	packageInfo := parsePackageInfo(t, source)
+	resolver := newSDKOperationResolver(rootDir)
+
+	assert.Equal(t, []APIOperation{
+		{SDKPackage: testVaultsPackage, Operation: "VaultsClient.Delete", HTTPMethod: "DELETE", Path: "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.KeyVault/vaults/{vaultName}"},
+	}, resolver.extractTypedAPIOperationsFromPackage("KeyVaultResource", []string{testVaultsPackage}, packageInfo))
+	assert.Nil(t, resolver.extractTypedAPIOperationsFromPackage("KeyVaultResource", nil, packageInfo))
+}
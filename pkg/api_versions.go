@@ -0,0 +1,92 @@
+package pkg
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// APIVersionUsage is an Azure API version the resources of the provider call through go-azure-sdk
+type APIVersionUsage struct {
+	API       string   `json:"api"`               // "resource-manager/keyvault", the go-azure-sdk path of the API
+	Version   string   `json:"version"`           // "2023-07-01"
+	Preview   bool     `json:"preview,omitempty"` // true for versions such as "2023-05-01-preview"
+	Resources []string `json:"resources"`         // ["azurerm_key_vault", "azurerm_key_vault_access_policy"], sorted
+}
+
+// APIVersionReport is the provider-wide usage of Azure API versions, written to api_version_usage.json
+type APIVersionReport struct {
+	Versions []APIVersionUsage `json:"versions"` // Ordered by API and version
+	// APIs whose resources call more than one version, with the versions in use, candidates for consolidation
+	MixedVersions map[string][]string `json:"mixed_versions"` // {"resource-manager/keyvault": ["2021-10-01", "2023-07-01"]}
+}
+
+// apiVersionsOf returns the sorted Azure API versions of go-azure-sdk packages, ["2023-07-01"] for
+// "github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2023-07-01/vaults"
+func apiVersionsOf(sdkPackages []string) []string {
+	var versions []string
+	for _, importPath := range sdkPackages {
+		if _, version, ok := splitSDKAPIVersion(sdkAPIVersion(importPath)); ok {
+			versions = append(versions, version)
+		}
+	}
+	if len(versions) == 0 {
+		return nil
+	}
+	return uniqueSortedStrings(versions)
+}
+
+// splitSDKAPIVersion splits an API version returned by sdkAPIVersion into the API and the version, false for
+// packages without an API version segment
+func splitSDKAPIVersion(apiVersion string) (api, version string, ok bool) {
+	api, version = path.Split(apiVersion)
+	if !apiVersionSegmentPattern.MatchString(version) {
+		return "", "", false
+	}
+	return strings.TrimSuffix(api, "/"), version, true
+}
+
+// BuildAPIVersionReport lists the Azure API versions the CRUD functions of the resources call
+func (index *TerraformProviderIndex) BuildAPIVersionReport() APIVersionReport {
+	return buildAPIVersionReport(index.BuildSDKIndex())
+}
+
+// buildAPIVersionReport builds the API version report from the reverse map of sdk_api_versions.json
+func buildAPIVersionReport(sdkConsumers map[string][]string) APIVersionReport {
+	report := APIVersionReport{Versions: []APIVersionUsage{}, MixedVersions: make(map[string][]string)}
+	for apiVersion, terraformTypes := range sdkConsumers {
+		api, version, ok := splitSDKAPIVersion(apiVersion)
+		if !ok {
+			continue
+		}
+		report.Versions = append(report.Versions, APIVersionUsage{
+			API:       api,
+			Version:   version,
+			Preview:   strings.HasSuffix(version, "-preview"),
+			Resources: terraformTypes,
+		})
+	}
+	sort.Slice(report.Versions, func(i, j int) bool {
+		if report.Versions[i].API != report.Versions[j].API {
+			return report.Versions[i].API < report.Versions[j].API
+		}
+		return report.Versions[i].Version < report.Versions[j].Version
+	})
+
+	versions := make(map[string][]string)
+	for _, usage := range report.Versions {
+		versions[usage.API] = append(versions[usage.API], usage.Version)
+	}
+	for api, apiVersions := range versions {
+		if len(apiVersions) > 1 {
+			report.MixedVersions[api] = apiVersions
+		}
+	}
+	return report
+}
+
+// writeAPIVersionReportFile writes api_version_usage.json, the Azure API versions used by the resources
+func (index *TerraformProviderIndex) writeAPIVersionReportFile(outputDir string) error {
+	return index.writeJSONFile(filepath.Join(outputDir, "api_version_usage.json"), index.BuildAPIVersionReport())
+}
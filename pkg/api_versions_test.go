@@ -0,0 +1,81 @@
+package pkg
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIVersionsOf(t *testing.T) {
+	assert.Equal(t, []string{"2021-10-01", "2023-07-01"}, apiVersionsOf([]string{
+		"github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2023-07-01/vaults",
+		"github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2023-07-01/managedhsms",
+		"github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2021-10-01/keys",
+		"github.com/hashicorp/go-azure-sdk/sdk/client/pollers",
+	}))
+	assert.Nil(t, apiVersionsOf([]string{"github.com/hashicorp/go-azure-sdk/sdk/client/pollers"}))
+	assert.Nil(t, apiVersionsOf(nil))
+}
+
+func TestTerraformProviderIndex_WriteAPIVersionReportFile(t *testing.T) {
+	// Setup
+	index := &TerraformProviderIndex{
+		Services: []ServiceRegistration{
+			{
+				ResourceSDKPackages: map[string][]string{
+					"azurerm_key_vault": {
+						"github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2023-07-01/vaults",
+						"github.com/hashicorp/go-azure-sdk/sdk/client/pollers",
+					},
+					"azurerm_key_vault_key": {
+						"github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2021-10-01/keys",
+					},
+					"azurerm_linux_web_app": {
+						"github.com/hashicorp/go-azure-sdk/resource-manager/web/2023-12-01-preview/webapps",
+					},
+				},
+			},
+		},
+	}
+	fs := afero.NewMemMapFs()
+	index.Output.Fs = fs
+	outputDir := "/test/output"
+
+	// Execute
+	err := index.writeAPIVersionReportFile(outputDir)
+
+	// Verify
+	require.NoError(t, err)
+
+	data, err := afero.ReadFile(fs, filepath.Join(outputDir, "api_version_usage.json"))
+	require.NoError(t, err)
+
+	var report APIVersionReport
+	require.NoError(t, json.Unmarshal(data, &report))
+	assert.Equal(t, APIVersionReport{
+		Versions: []APIVersionUsage{
+			{API: "resource-manager/keyvault", Version: "2021-10-01", Resources: []string{"azurerm_key_vault_key"}},
+			{API: "resource-manager/keyvault", Version: "2023-07-01", Resources: []string{"azurerm_key_vault"}},
+			{API: "resource-manager/web", Version: "2023-12-01-preview", Preview: true, Resources: []string{"azurerm_linux_web_app"}},
+		},
+		MixedVersions: map[string][]string{
+			"resource-manager/keyvault": {"2021-10-01", "2023-07-01"},
+		},
+	}, report)
+}
+
+func TestNewTerraformResourceInfo_APIVersions(t *testing.T) {
+	serviceReg := ServiceRegistration{
+		ResourceSDKPackages: map[string][]string{
+			"azurerm_key_vault": {"github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2023-07-01/vaults"},
+		},
+	}
+
+	resource := newTerraformResourceInfo("azurerm_key_vault", "", "resourceKeyVault", "legacy_pluginsdk", serviceReg)
+
+	assert.Equal(t, []string{"2023-07-01"}, resource.APIVersions)
+}
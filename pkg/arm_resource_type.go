@@ -0,0 +1,296 @@
+package pkg
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// resourceIDFunctionPattern matches resource ID helpers such as vaults.ParseVaultID, vaults.ValidateVaultID,
+// vaults.NewVaultID, commonids.ParseSubnetIDInsensitively and the legacy parse.VaultID, capturing the ID name
+var resourceIDFunctionPattern = regexp.MustCompile(`^(?:Parse|Validate|New)?([A-Z]\w*?)ID(?:Insensitively)?$`)
+
+// armResourceTypeResolver resolves resource ID types to ARM resource types by reading the ID() method of the
+// ID type, for example "/subscriptions/%s/resourceGroups/%s/providers/Microsoft.KeyVault/vaults/%s" results in
+// "Microsoft.KeyVault/vaults". Packages of the provider module are read from rootDir, any other package from rootDir/vendor,
+// unless packagePaths maps their import path to another directory.
+type armResourceTypeResolver struct {
+	rootDir      string
+	basePkgUrl   string
+	packagePaths PackagePathMapper // May be nil
+
+	mu    sync.Mutex
+	cache map[string]map[string]string // package directory -> ID type name -> ARM resource type
+}
+
+func newArmResourceTypeResolver(rootDir, basePkgUrl string) *armResourceTypeResolver {
+	return &armResourceTypeResolver{
+		rootDir:    rootDir,
+		basePkgUrl: basePkgUrl,
+		cache:      make(map[string]map[string]string),
+	}
+}
+
+// extractLegacyArmResourceTypeFromPackage detects the ARM resource type managed by a legacy resource from the ID parsed
+// in its Read function, falling back to the ID validated by the importer of the registration function
+func (r *armResourceTypeResolver) extractLegacyArmResourceTypeFromPackage(registrationMethod string, crudMethods *LegacyResourceCRUDFunctions, packageInfo *gophon.PackageInfo) string {
+	var candidates []*ast.FuncDecl
+	if crudMethods != nil && crudMethods.ReadMethod != "" {
+		candidates = append(candidates, findFunctionDecl(packageInfo, crudMethods.ReadMethod))
+	}
+	candidates = append(candidates, findFunctionDecl(packageInfo, registrationMethod))
+	return r.armResourceTypeFromFunctions(packageInfo, candidates...)
+}
+
+// extractTypedArmResourceTypeFromPackage detects the ARM resource type managed by a typed resource from the ID validated
+// by its IDValidationFunc method, falling back to the ID parsed in its Read method
+func (r *armResourceTypeResolver) extractTypedArmResourceTypeFromPackage(structName string, packageInfo *gophon.PackageInfo) string {
+	return r.armResourceTypeFromFunctions(packageInfo,
+		findMethodDecl(packageInfo, structName, "IDValidationFunc"),
+		findMethodDecl(packageInfo, structName, "Read"))
+}
+
+// armResourceTypeFromFunctions returns the ARM resource type of the first resource ID referenced by the given functions
+func (r *armResourceTypeResolver) armResourceTypeFromFunctions(packageInfo *gophon.PackageInfo, functions ...*ast.FuncDecl) string {
+	for _, fn := range functions {
+		file := findFunctionFile(packageInfo, fn)
+		if file == nil {
+			continue
+		}
+		for _, ref := range resourceIDReferences(fn) {
+			importPath := importPathOfAlias(file, ref.alias)
+			if importPath == "" {
+				continue
+			}
+			if armType := r.resolve(importPath, ref.idType); armType != "" {
+				return armType
+			}
+		}
+	}
+	return ""
+}
+
+// resourceIDReference is a reference to a resource ID helper, alias is the package alias used in the source file
+type resourceIDReference struct {
+	alias    string
+	function string // "ParseVaultID"
+	idType   string // "VaultId"
+}
+
+// resourceIDReferences collects resource ID helper references of a function in source order, references to IDs of the
+// resource itself (parsed from d.Id() or metadata.ResourceData.Id()) come first
+func resourceIDReferences(fn *ast.FuncDecl) []resourceIDReference {
+	if fn == nil || fn.Body == nil {
+		return nil
+	}
+
+	var ownIDs, otherIDs []resourceIDReference
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.CallExpr:
+			ref, ok := resourceIDHelper(e.Fun)
+			if !ok {
+				return true
+			}
+			if len(e.Args) > 0 && isIdCall(e.Args[0]) {
+				ownIDs = append(ownIDs, ref)
+			} else {
+				otherIDs = append(otherIDs, ref)
+			}
+			return false
+		case *ast.ReturnStmt:
+			// IDValidationFunc returns the validation function itself: return vaults.ValidateVaultID
+			for _, result := range e.Results {
+				if ref, ok := resourceIDHelper(result); ok {
+					otherIDs = append(otherIDs, ref)
+				}
+			}
+		}
+		return true
+	})
+	return append(ownIDs, otherIDs...)
+}
+
+// resourceIDHelper matches selector expressions referring to resource ID helpers of another package
+func resourceIDHelper(expr ast.Expr) (resourceIDReference, bool) {
+	selector, ok := unwrapTypeArguments(expr).(*ast.SelectorExpr)
+	if !ok {
+		return resourceIDReference{}, false
+	}
+	alias, ok := selector.X.(*ast.Ident)
+	if !ok {
+		return resourceIDReference{}, false
+	}
+	matches := resourceIDFunctionPattern.FindStringSubmatch(selector.Sel.Name)
+	if matches == nil {
+		return resourceIDReference{}, false
+	}
+	return resourceIDReference{alias: alias.Name, function: selector.Sel.Name, idType: matches[1] + "Id"}, true
+}
+
+// isIdCall reports whether the expression is a call to an Id method, such as d.Id() or metadata.ResourceData.Id()
+func isIdCall(expr ast.Expr) bool {
+	callExpr, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	selector, ok := callExpr.Fun.(*ast.SelectorExpr)
+	return ok && selector.Sel.Name == "Id"
+}
+
+// resolve returns the ARM resource type of the ID type declared in the package with the given import path
+func (r *armResourceTypeResolver) resolve(importPath, idType string) string {
+	var dir string
+	if r.packagePaths != nil {
+		if mapped := r.packagePaths.Dir(importPath); mapped != "" {
+			return r.packageResourceTypes(filepath.Join(r.rootDir, mapped))[idType]
+		}
+	}
+	if rel, ok := strings.CutPrefix(importPath, r.basePkgUrl+"/"); ok {
+		dir = filepath.Join(r.rootDir, filepath.FromSlash(rel))
+	} else {
+		dir = filepath.Join(r.rootDir, "vendor", filepath.FromSlash(importPath))
+	}
+	return r.packageResourceTypes(dir)[idType]
+}
+
+// packageResourceTypes parses the package in dir once and returns the ARM resource types of its ID types
+func (r *armResourceTypeResolver) packageResourceTypes(dir string) map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if resourceTypes, exists := r.cache[dir]; exists {
+		return resourceTypes
+	}
+
+	resourceTypes := make(map[string]string)
+	r.cache[dir] = resourceTypes
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return resourceTypes
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, entry.Name()), nil, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Name.Name != "ID" {
+				continue
+			}
+			receiver := receiverTypeName(fn)
+			if receiver == "" {
+				continue
+			}
+			if armType := armResourceTypeFromIDMethod(fn); armType != "" {
+				resourceTypes[receiver] = armType
+			}
+		}
+	}
+	return resourceTypes
+}
+
+// armResourceTypeFromIDMethod finds the resource ID format string used by an ID() method and converts it to an ARM resource type
+func armResourceTypeFromIDMethod(fn *ast.FuncDecl) string {
+	var armType string
+	ast.Inspect(fn, func(n ast.Node) bool {
+		if armType != "" {
+			return false
+		}
+		if basicLit, ok := n.(*ast.BasicLit); ok && basicLit.Kind == token.STRING {
+			if value, err := strconv.Unquote(basicLit.Value); err == nil {
+				armType = armResourceTypeFromIDFormat(value)
+			}
+		}
+		return true
+	})
+	return armType
+}
+
+// armResourceTypeFromIDFormat converts a resource ID format such as
+// "/subscriptions/%s/resourceGroups/%s/providers/Microsoft.KeyVault/vaults/%s/secrets/%s" to "Microsoft.KeyVault/vaults/secrets"
+func armResourceTypeFromIDFormat(format string) string {
+	idx := strings.LastIndex(format, "/providers/")
+	if idx < 0 {
+		return ""
+	}
+	segments := strings.Split(strings.Trim(format[idx+len("/providers/"):], "/"), "/")
+	if len(segments) < 2 || !strings.Contains(segments[0], ".") {
+		return ""
+	}
+
+	resourceType := []string{segments[0]}
+	for i := 1; i < len(segments); i += 2 {
+		resourceType = append(resourceType, segments[i])
+	}
+	return strings.Join(resourceType, "/")
+}
+
+// findFunctionFile returns the file declaring the given function
+func findFunctionFile(packageInfo *gophon.PackageInfo, fn *ast.FuncDecl) *ast.File {
+	if packageInfo == nil || fn == nil {
+		return nil
+	}
+	for _, funcInfo := range packageInfo.Functions {
+		if funcInfo.FuncDecl == fn && funcInfo.Range != nil && funcInfo.FileInfo != nil {
+			return funcInfo.FileInfo.File
+		}
+	}
+	return nil
+}
+
+// importPathOfAlias returns the import path of the package referred to by alias in the file
+func importPathOfAlias(file *ast.File, alias string) string {
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := importPath[strings.LastIndex(importPath, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if name == alias {
+			return importPath
+		}
+	}
+	return ""
+}
+
+// extractLegacyIDParserFromPackage returns the resource ID parser used by the Read function of a legacy resource
+func extractLegacyIDParserFromPackage(crudMethods *LegacyResourceCRUDFunctions, packageInfo *gophon.PackageInfo) string {
+	if crudMethods == nil || crudMethods.ReadMethod == "" {
+		return ""
+	}
+	return extractIDParser(findFunctionDecl(packageInfo, crudMethods.ReadMethod))
+}
+
+// extractTypedIDParserFromPackage returns the resource ID parser used by the Read method of a typed resource
+func extractTypedIDParserFromPackage(structName string, packageInfo *gophon.PackageInfo) string {
+	return extractIDParser(findMethodDecl(packageInfo, structName, "Read"))
+}
+
+// extractIDParser returns the first resource ID parser called by a function, such as "commonids.ParseKeyVaultID"
+// or the legacy "parse.VaultID", preferring parsers applied to the ID of the resource itself
+func extractIDParser(fn *ast.FuncDecl) string {
+	for _, ref := range resourceIDReferences(fn) {
+		if strings.HasPrefix(ref.function, "Validate") || strings.HasPrefix(ref.function, "New") {
+			continue
+		}
+		return ref.alias + "." + ref.function
+	}
+	return ""
+}
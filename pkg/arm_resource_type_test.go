@@ -0,0 +1,184 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testProviderModule = "github.com/hashicorp/terraform-provider-azurerm"
+
+// writeTestPackage writes a single file package under rootDir
+func writeTestPackage(t *testing.T, rootDir, pkgDir, fileName, source string) {
+	dir := filepath.Join(rootDir, filepath.FromSlash(pkgDir))
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, fileName), []byte(source), 0644))
+}
+
+func TestArmResourceTypeFromIDFormat(t *testing.T) {
+	testCases := []struct {
+		format   string
+		expected string
+	}{
+		{"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.KeyVault/vaults/%s", "Microsoft.KeyVault/vaults"},
+		{"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.KeyVault/vaults/%s/secrets/%s", "Microsoft.KeyVault/vaults/secrets"},
+		{"%s/providers/Microsoft.Authorization/locks/%s", "Microsoft.Authorization/locks"},
+		{"/subscriptions/%s/resourceGroups/%s", ""},
+		{"providers", ""},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, armResourceTypeFromIDFormat(tc.format), tc.format)
+	}
+}
+
+func TestExtractLegacyArmResourceTypeFromPackage_VendoredSdkID(t *testing.T) {
+	rootDir := t.TempDir()
+	writeTestPackage(t, rootDir, "vendor/github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2023-07-01/vaults", "id_vault.go", `package vaults
+
+type VaultId struct{}
+
+func (id VaultId) ID() string {
+	fmtString := "/subscriptions/%s/resourceGroups/%s/providers/Microsoft.KeyVault/vaults/%s"
+	return fmt.Sprintf(fmtString, id.SubscriptionId, id.ResourceGroupName, id.VaultName)
+}`)
+	writeTestPackage(t, rootDir, "vendor/github.com/hashicorp/go-azure-helpers/resourcemanager/commonids", "subnet.go", `package commonids
+
+type SubnetId struct{}
+
+func (id SubnetId) ID() string {
+	fmtString := "/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/virtualNetworks/%s/subnets/%s"
+	return fmt.Sprintf(fmtString, id.SubscriptionId, id.ResourceGroupName, id.VirtualNetworkName, id.SubnetName)
+}`)
+
+	source := `package keyvault
+
+import (
+	"github.com/hashicorp/go-azure-helpers/resourcemanager/commonids"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2023-07-01/vaults"
+)
+
+func resourceKeyVault() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Read: resourceKeyVaultRead,
+	}
+}
+
+func resourceKeyVaultRead(d *pluginsdk.ResourceData, meta interface{}) error {
+	subnetId, err := commonids.ParseSubnetID(d.Get("subnet_id").(string))
+	if err != nil {
+		return err
+	}
+	id, err := vaults.ParseVaultID(d.Id())
+	if err != nil {
+		return err
+	}
+	return nil
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+	resolver := newArmResourceTypeResolver(rootDir, testProviderModule)
+	crudMethods := &LegacyResourceCRUDFunctions{ReadMethod: "resourceKeyVaultRead"}
+
+	assert.Equal(t, "Microsoft.KeyVault/vaults", resolver.extractLegacyArmResourceTypeFromPackage("resourceKeyVault", crudMethods, packageInfo))
+}
+
+func TestExtractLegacyArmResourceTypeFromPackage_ImporterWithLocalParsePackage(t *testing.T) {
+	rootDir := t.TempDir()
+	writeTestPackage(t, rootDir, "internal/services/web/parse", "app_service.go", `package parse
+
+type AppServiceId struct{}
+
+func (id AppServiceId) ID() string {
+	fmtString := "/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Web/sites/%s"
+	return fmt.Sprintf(fmtString, id.SubscriptionId, id.ResourceGroup, id.SiteName)
+}`)
+
+	source := `package web
+
+import "github.com/hashicorp/terraform-provider-azurerm/internal/services/web/parse"
+
+func resourceAppService() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Importer: pluginsdk.ImporterValidatingResourceId(func(id string) error {
+			_, err := parse.AppServiceID(id)
+			return err
+		}),
+	}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+	resolver := newArmResourceTypeResolver(rootDir, testProviderModule)
+
+	assert.Equal(t, "Microsoft.Web/sites", resolver.extractLegacyArmResourceTypeFromPackage("resourceAppService", nil, packageInfo))
+}
+
+func TestExtractTypedArmResourceTypeFromPackage(t *testing.T) {
+	rootDir := t.TempDir()
+	writeTestPackage(t, rootDir, "vendor/github.com/hashicorp/go-azure-sdk/resource-manager/containerapps/2023-05-01/containerapps", "id_containerapp.go", `package containerapps
+
+type ContainerAppId struct{}
+
+func (id *ContainerAppId) ID() string {
+	fmtString := "/subscriptions/%s/resourceGroups/%s/providers/Microsoft.App/containerApps/%s"
+	return fmt.Sprintf(fmtString, id.SubscriptionId, id.ResourceGroupName, id.ContainerAppName)
+}`)
+
+	source := `package containerapps
+
+import "github.com/hashicorp/go-azure-sdk/resource-manager/containerapps/2023-05-01/containerapps"
+
+type ContainerAppResource struct{}
+
+func (r ContainerAppResource) IDValidationFunc() pluginsdk.SchemaValidateFunc {
+	return containerapps.ValidateContainerAppID
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+	resolver := newArmResourceTypeResolver(rootDir, testProviderModule)
+
+	assert.Equal(t, "Microsoft.App/containerApps", resolver.extractTypedArmResourceTypeFromPackage("ContainerAppResource", packageInfo))
+	assert.Equal(t, "", resolver.extractTypedArmResourceTypeFromPackage("UnknownResource", packageInfo))
+}
+
+func TestExtractIDParserFromPackage(t *testing.T) {
+	source := `package keyvault
+
+import (
+	"github.com/hashicorp/go-azure-helpers/resourcemanager/commonids"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault/parse"
+)
+
+func resourceKeyVaultRead(d *pluginsdk.ResourceData, meta interface{}) error {
+	subnetId, err := commonids.ParseSubnetID(d.Get("subnet_id").(string))
+	id, err := commonids.ParseKeyVaultID(d.Id())
+	return err
+}
+
+func resourceKeyVaultSecretRead(d *pluginsdk.ResourceData, meta interface{}) error {
+	keyVaultId := commonids.NewKeyVaultID(subscriptionId, resourceGroup, name)
+	id, err := parse.ParseNestedItemID(d.Get("id").(string))
+	return err
+}
+
+type KeyVaultManagedHardwareSecurityModuleResource struct{}
+
+func (r KeyVaultManagedHardwareSecurityModuleResource) Read() sdk.ResourceFunc {
+	return sdk.ResourceFunc{
+		Func: func(ctx context.Context, metadata sdk.ResourceMetaData) error {
+			id, err := managedhsms.ParseManagedHSMID(metadata.ResourceData.Id())
+			return err
+		},
+	}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+
+	assert.Equal(t, "commonids.ParseKeyVaultID", extractLegacyIDParserFromPackage(&LegacyResourceCRUDFunctions{ReadMethod: "resourceKeyVaultRead"}, packageInfo))
+	assert.Equal(t, "parse.ParseNestedItemID", extractLegacyIDParserFromPackage(&LegacyResourceCRUDFunctions{ReadMethod: "resourceKeyVaultSecretRead"}, packageInfo))
+	assert.Equal(t, "", extractLegacyIDParserFromPackage(nil, packageInfo))
+	assert.Equal(t, "managedhsms.ParseManagedHSMID", extractTypedIDParserFromPackage("KeyVaultManagedHardwareSecurityModuleResource", packageInfo))
+}
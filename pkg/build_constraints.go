@@ -0,0 +1,69 @@
+package pkg
+
+import (
+	"go/build"
+	"os"
+	"strings"
+)
+
+// BuildConstraints select the files of the service packages compiled into the indexed provider release. Files
+// excluded by their build constraints are not scanned, the go command's defaults for the host apply when empty.
+type BuildConstraints struct {
+	Tags   []string // ["azurerm_preview"], added to the build tags of -tags in GOFLAGS
+	GOOS   string   // "windows", the target operating system of the go command when empty
+	GOARCH string   // "arm64", the target architecture of the go command when empty
+}
+
+// IsZero reports whether the constraints leave the go command's defaults untouched
+func (c BuildConstraints) IsZero() bool {
+	return len(c.Tags) == 0 && c.GOOS == "" && c.GOARCH == ""
+}
+
+// environment returns the environment of the go command loading the service packages with the constraints: the
+// environment of the process with GOOS, GOARCH and GOFLAGS overridden, GOFLAGS keeps its other flags and the tags it
+// already sets. The go command uses the last value of variables set more than once.
+func (c BuildConstraints) environment() []string {
+	env := os.Environ()
+	if c.GOOS != "" {
+		env = append(env, "GOOS="+c.GOOS)
+	}
+	if c.GOARCH != "" {
+		env = append(env, "GOARCH="+c.GOARCH)
+	}
+	if len(c.Tags) > 0 {
+		goFlags := os.Getenv("GOFLAGS")
+		tags := append(goFlagsBuildTags(goFlags), c.Tags...)
+		env = append(env, "GOFLAGS="+strings.TrimSpace(goFlags+" -tags="+strings.Join(tags, ",")))
+	}
+	return env
+}
+
+// buildContext returns the build context selecting the files of the service packages, the default build context of
+// the process with the target platform and the build tags of the constraints and of GOFLAGS
+func (c BuildConstraints) buildContext() build.Context {
+	buildContext := build.Default
+	if c.GOOS != "" {
+		buildContext.GOOS = c.GOOS
+	}
+	if c.GOARCH != "" {
+		buildContext.GOARCH = c.GOARCH
+	}
+	buildContext.BuildTags = append(append(append([]string{}, build.Default.BuildTags...), goFlagsBuildTags(os.Getenv("GOFLAGS"))...), c.Tags...)
+	return buildContext
+}
+
+// toolchainInfo returns the toolchain information of the running process with the target platform and the build tags
+// of the constraints
+func (c BuildConstraints) toolchainInfo() ToolchainInfo {
+	info := currentToolchainInfo()
+	if c.GOOS != "" {
+		info.GOOS = c.GOOS
+	}
+	if c.GOARCH != "" {
+		info.GOARCH = c.GOARCH
+	}
+	if len(c.Tags) > 0 {
+		info.BuildTags = uniqueSortedStrings(append(info.BuildTags, c.Tags...))
+	}
+	return info
+}
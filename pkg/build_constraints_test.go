@@ -0,0 +1,102 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildConstraints_Environment(t *testing.T) {
+	t.Setenv("GOFLAGS", "-mod=mod -tags=integration")
+
+	assert.True(t, BuildConstraints{}.IsZero())
+	assert.Equal(t, os.Environ(), BuildConstraints{}.environment())
+	env := BuildConstraints{Tags: []string{"azurerm_preview"}, GOOS: "windows", GOARCH: "arm64"}.environment()
+	assert.Equal(t, os.Environ(), env[:len(env)-3])
+	assert.Equal(t, []string{
+		"GOOS=windows",
+		"GOARCH=arm64",
+		"GOFLAGS=-mod=mod -tags=integration -tags=integration,azurerm_preview",
+	}, env[len(env)-3:])
+	assert.Equal(t, "-mod=mod -tags=integration", os.Getenv("GOFLAGS"), "the environment of the process is left untouched")
+}
+
+func TestBuildConstraints_BuildContext(t *testing.T) {
+	t.Setenv("GOFLAGS", "-tags=integration")
+
+	buildContext := BuildConstraints{Tags: []string{"azurerm_preview"}, GOOS: "windows", GOARCH: "arm64"}.buildContext()
+
+	assert.Equal(t, "windows", buildContext.GOOS)
+	assert.Equal(t, "arm64", buildContext.GOARCH)
+	assert.Subset(t, buildContext.BuildTags, []string{"integration", "azurerm_preview"})
+	assert.Equal(t, currentToolchainInfo().GOOS, BuildConstraints{}.buildContext().GOOS)
+}
+
+func TestBuildConstraints_ToolchainInfo(t *testing.T) {
+	t.Setenv("GOFLAGS", "")
+
+	info := BuildConstraints{Tags: []string{"azurerm_preview"}, GOOS: "windows"}.toolchainInfo()
+
+	assert.Equal(t, "windows", info.GOOS)
+	assert.Equal(t, currentToolchainInfo().GOARCH, info.GOARCH)
+	assert.Contains(t, info.BuildTags, "azurerm_preview")
+}
+
+func TestScanner_BuildConstraintsSelectScannedFiles(t *testing.T) {
+	servicesDir, err := os.MkdirTemp(filepath.Join("testharness", "internal"), "buildtags")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(servicesDir) })
+	serviceDir := filepath.Join(servicesDir, "preview")
+	require.NoError(t, os.Mkdir(serviceDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(serviceDir, "registration.go"), []byte(`package preview
+
+import (
+	pluginsdk "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+type Registration struct{}
+
+func (r Registration) SupportedResources() map[string]*pluginsdk.Resource {
+	return map[string]*pluginsdk.Resource{
+		"azurerm_stable": resourceStable(),
+	}
+}
+
+func resourceStable() *pluginsdk.Resource { return nil }
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(serviceDir, "registration_preview.go"), []byte(`//go:build azurerm_preview
+
+package preview
+
+import (
+	pluginsdk "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+type previewRegistration struct{}
+
+func (r previewRegistration) SupportedResources() map[string]*pluginsdk.Resource {
+	return map[string]*pluginsdk.Resource{
+		"azurerm_preview": resourcePreview(),
+	}
+}
+
+func resourcePreview() *pluginsdk.Resource { return nil }
+`), 0644))
+
+	index, err := Scanner{}.Scan(servicesDir, "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+	require.NoError(t, err)
+	_, found := index.LookupResource("azurerm_stable")
+	assert.True(t, found)
+	_, found = index.LookupResource("azurerm_preview")
+	assert.False(t, found, "files excluded by their build constraints are not scanned")
+
+	index, err = Scanner{Build: BuildConstraints{Tags: []string{"azurerm_preview"}}}.Scan(servicesDir, "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+	require.NoError(t, err)
+	_, found = index.LookupResource("azurerm_preview")
+	assert.True(t, found)
+	assert.Contains(t, index.Toolchain.BuildTags, "azurerm_preview")
+	assert.NotContains(t, goFlagsBuildTags(os.Getenv("GOFLAGS")), "azurerm_preview", "the environment of the process is left untouched")
+}
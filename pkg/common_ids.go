@@ -0,0 +1,76 @@
+package pkg
+
+import (
+	"go/ast"
+	"regexp"
+	"sort"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// commonIDsImportPath is the import path of the go-azure-helpers package holding the canonical Azure resource IDs
+const commonIDsImportPath = "github.com/hashicorp/go-azure-helpers/resourcemanager/commonids"
+
+// commonIDTypePattern matches commonids ID types such as commonids.KeyVaultId
+var commonIDTypePattern = regexp.MustCompile(`^[A-Z]\w*Id$`)
+
+// extractLegacyCommonIDsFromPackage collects the commonids ID types parsed or built by the CRUD functions of a legacy resource
+func extractLegacyCommonIDsFromPackage(crudMethods *LegacyResourceCRUDFunctions, packageInfo *gophon.PackageInfo) []string {
+	if crudMethods == nil {
+		return nil
+	}
+	var functions []*ast.FuncDecl
+	for _, name := range []string{crudMethods.CreateMethod, crudMethods.ReadMethod, crudMethods.UpdateMethod, crudMethods.DeleteMethod} {
+		if name != "" {
+			functions = append(functions, findFunctionDecl(packageInfo, name))
+		}
+	}
+	return commonIDsReferencedBy(packageInfo, functions...)
+}
+
+// extractTypedCommonIDsFromPackage collects the commonids ID types parsed or built by the CRUD methods of a typed resource
+func extractTypedCommonIDsFromPackage(structName string, packageInfo *gophon.PackageInfo) []string {
+	var functions []*ast.FuncDecl
+	for _, name := range []string{"Create", "Read", "Update", "Delete"} {
+		functions = append(functions, findMethodDecl(packageInfo, structName, name))
+	}
+	return commonIDsReferencedBy(packageInfo, functions...)
+}
+
+// commonIDsReferencedBy returns the sorted commonids ID types the given functions parse, validate, build or declare,
+// "KeyVaultId" for commonids.ParseKeyVaultID, commonids.NewKeyVaultID or commonids.KeyVaultId
+func commonIDsReferencedBy(packageInfo *gophon.PackageInfo, functions ...*ast.FuncDecl) []string {
+	idTypes := make(map[string]bool)
+	for _, fn := range functions {
+		file := findFunctionFile(packageInfo, fn)
+		if file == nil || fn.Body == nil {
+			continue
+		}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			selector, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			alias, ok := selector.X.(*ast.Ident)
+			if !ok || importPathOfAlias(file, alias.Name) != commonIDsImportPath {
+				return true
+			}
+			if matches := resourceIDFunctionPattern.FindStringSubmatch(selector.Sel.Name); matches != nil {
+				idTypes[matches[1]+"Id"] = true
+			} else if commonIDTypePattern.MatchString(selector.Sel.Name) {
+				idTypes[selector.Sel.Name] = true
+			}
+			return true
+		})
+	}
+	if len(idTypes) == 0 {
+		return nil
+	}
+
+	result := make([]string, 0, len(idTypes))
+	for idType := range idTypes {
+		result = append(result, idType)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,66 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractLegacyCommonIDsFromPackage(t *testing.T) {
+	source := `package network
+
+import (
+	"github.com/hashicorp/go-azure-helpers/resourcemanager/commonids"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/network/2023-09-01/subnets"
+)
+
+func resourceSubnetCreate(d *pluginsdk.ResourceData, meta interface{}) error {
+	id := commonids.NewSubnetID(subscriptionId, d.Get("resource_group_name").(string), d.Get("virtual_network_name").(string), d.Get("name").(string))
+	return nil
+}
+
+func resourceSubnetRead(d *pluginsdk.ResourceData, meta interface{}) error {
+	id, err := commonids.ParseSubnetIDInsensitively(d.Id())
+	return err
+}
+
+func resourceSubnetDelete(d *pluginsdk.ResourceData, meta interface{}) error {
+	var vnet commonids.VirtualNetworkId
+	_, err := subnets.ParseSubnetID(d.Id())
+	return err
+}
+
+func resourceSubnetFlatten(input commonids.KeyVaultId) {}`
+
+	packageInfo := parsePackageInfo(t, source)
+	result := extractLegacyCommonIDsFromPackage(&LegacyResourceCRUDFunctions{
+		CreateMethod: "resourceSubnetCreate",
+		ReadMethod:   "resourceSubnetRead",
+		DeleteMethod: "resourceSubnetDelete",
+	}, packageInfo)
+
+	assert.Equal(t, []string{"SubnetId", "VirtualNetworkId"}, result)
+	assert.Nil(t, extractLegacyCommonIDsFromPackage(nil, packageInfo))
+}
+
+func TestExtractTypedCommonIDsFromPackage(t *testing.T) {
+	source := `package containerapps
+
+import ids "github.com/hashicorp/go-azure-helpers/resourcemanager/commonids"
+
+type ContainerAppResource struct{}
+
+func (r ContainerAppResource) Create() sdk.ResourceFunc {
+	return sdk.ResourceFunc{
+		Func: func(ctx context.Context, metadata sdk.ResourceMetaData) error {
+			id := ids.NewResourceGroupID(subscriptionId, "example")
+			return nil
+		},
+	}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+
+	assert.Equal(t, []string{"ResourceGroupId"}, extractTypedCommonIDsFromPackage("ContainerAppResource", packageInfo))
+	assert.Nil(t, extractTypedCommonIDsFromPackage("UnknownResource", packageInfo))
+}
@@ -0,0 +1,261 @@
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+const (
+	ConfigSeverityError   = "error"
+	ConfigSeverityWarning = "warning"
+)
+
+// terraformMetaArguments are the arguments and blocks Terraform accepts in every resource block
+var terraformMetaArguments = map[string]bool{
+	"count":       true,
+	"for_each":    true,
+	"provider":    true,
+	"depends_on":  true,
+	"lifecycle":   true,
+	"provisioner": true,
+	"connection":  true,
+	"timeouts":    true,
+}
+
+// ConfigProblem is an issue found in a resource or data block of a Terraform configuration
+type ConfigProblem struct {
+	File          string `json:"file"`                // "main.tf", relative to the configuration directory
+	Line          int    `json:"line"`                // 12
+	TerraformType string `json:"terraform_type"`      // "azurerm_key_vault"
+	Name          string `json:"name"`                // "example", the block label
+	Attribute     string `json:"attribute,omitempty"` // "sku_name"
+	Severity      string `json:"severity"`            // "error" or "warning"
+	Message       string `json:"message"`             // "required attribute sku_name is not set"
+}
+
+// ConfigCheckReport lists the problems found by CheckConfigDir
+type ConfigCheckReport struct {
+	ConfigDir string          `json:"config_dir"` // "./module"
+	Blocks    int             `json:"blocks"`     // Number of resource and data blocks of the provider checked
+	Problems  []ConfigProblem `json:"problems"`   // Sorted by file and line
+}
+
+// Errors returns the number of problems Terraform would reject the configuration for
+func (r *ConfigCheckReport) Errors() int {
+	count := 0
+	for _, problem := range r.Problems {
+		if problem.Severity == ConfigSeverityError {
+			count++
+		}
+	}
+	return count
+}
+
+// configCheckIndex holds the documents of a generated index a configuration is checked against
+type configCheckIndex struct {
+	resources   map[string]TerraformResource
+	dataSources map[string]TerraformDataSource
+	prefixes    map[string]bool // "azurerm", provider prefixes of the indexed Terraform types
+}
+
+// CheckConfigDir statically checks the .tf files of a Terraform module directory against an index directory generated
+// with the JSON format. Resource blocks of the indexed provider are checked for unknown resource types, unknown
+// attributes, missing required attributes and deprecated attributes, data blocks for unknown and deprecated data
+// sources. Blocks of other providers are ignored. Attributes are only known when their schema is declared with a
+// literal, so a schema built by a helper is never reported as missing. Nested blocks are not checked.
+func CheckConfigDir(configDir, indexDir string) (*ConfigCheckReport, error) {
+	index, err := loadConfigCheckIndex(indexDir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(configDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration directory %s: %w", configDir, err)
+	}
+
+	report := &ConfigCheckReport{ConfigDir: configDir, Problems: []ConfigProblem{}}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".tf" {
+			continue
+		}
+		if err := report.checkFile(configDir, entry.Name(), index); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(report.Problems, func(i, j int) bool {
+		if report.Problems[i].File != report.Problems[j].File {
+			return report.Problems[i].File < report.Problems[j].File
+		}
+		return report.Problems[i].Line < report.Problems[j].Line
+	})
+	return report, nil
+}
+
+// loadConfigCheckIndex reads the resource and data source documents of an index directory
+func loadConfigCheckIndex(indexDir string) (*configCheckIndex, error) {
+	index := &configCheckIndex{
+		resources:   make(map[string]TerraformResource),
+		dataSources: make(map[string]TerraformDataSource),
+		prefixes:    make(map[string]bool),
+	}
+	validation := &IndexValidationReport{}
+	for _, fileName := range validation.jsonFiles(indexDir, DocumentKindResource) {
+		var document TerraformResource
+		if err := readIndexJSONFile(filepath.Join(indexDir, DocumentKindResource, fileName), &document); err != nil {
+			return nil, err
+		}
+		index.resources[document.TerraformType] = document
+		index.prefixes[terraformTypePrefix(document.TerraformType)] = true
+	}
+	for _, fileName := range validation.jsonFiles(indexDir, DocumentKindDataSource) {
+		var document TerraformDataSource
+		if err := readIndexJSONFile(filepath.Join(indexDir, DocumentKindDataSource, fileName), &document); err != nil {
+			return nil, err
+		}
+		index.dataSources[document.TerraformType] = document
+		index.prefixes[terraformTypePrefix(document.TerraformType)] = true
+	}
+	if len(validation.Problems) > 0 {
+		return nil, fmt.Errorf("failed to read index %s: %s", indexDir, validation.Problems[0].Message)
+	}
+	if len(index.resources) == 0 && len(index.dataSources) == 0 {
+		return nil, fmt.Errorf("index %s has no resource or data source documents", indexDir)
+	}
+	return index, nil
+}
+
+// terraformTypePrefix returns the provider prefix of a Terraform type, "azurerm" for azurerm_key_vault
+func terraformTypePrefix(terraformType string) string {
+	prefix, _, _ := strings.Cut(terraformType, "_")
+	return prefix
+}
+
+// checkFile checks the resource and data blocks of a .tf file, syntax errors are reported as problems
+func (r *ConfigCheckReport) checkFile(configDir, fileName string, index *configCheckIndex) error {
+	src, err := os.ReadFile(filepath.Join(configDir, fileName))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", fileName, err)
+	}
+	file, diags := hclsyntax.ParseConfig(src, fileName, hcl.InitialPos)
+	if diags.HasErrors() {
+		for _, diag := range diags.Errs() {
+			problem := ConfigProblem{File: fileName, Severity: ConfigSeverityError, Message: diag.Error()}
+			if hclDiag, ok := diag.(*hcl.Diagnostic); ok && hclDiag.Subject != nil {
+				problem.Line = hclDiag.Subject.Start.Line
+				problem.Message = fmt.Sprintf("%s: %s", hclDiag.Summary, hclDiag.Detail)
+			}
+			r.Problems = append(r.Problems, problem)
+		}
+		return nil
+	}
+
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+	for _, block := range body.Blocks {
+		if len(block.Labels) != 2 || !index.prefixes[terraformTypePrefix(block.Labels[0])] {
+			continue
+		}
+		switch block.Type {
+		case "resource":
+			r.Blocks++
+			r.checkResourceBlock(fileName, block, index)
+		case "data":
+			r.Blocks++
+			r.checkDataBlock(fileName, block, index)
+		}
+	}
+	return nil
+}
+
+// checkResourceBlock checks a resource block against the schema of its resource document
+func (r *ConfigCheckReport) checkResourceBlock(fileName string, block *hclsyntax.Block, index *configCheckIndex) {
+	terraformType, name := block.Labels[0], block.Labels[1]
+	addProblem := func(line int, attribute, severity, format string, args ...interface{}) {
+		r.Problems = append(r.Problems, ConfigProblem{
+			File:          fileName,
+			Line:          line,
+			TerraformType: terraformType,
+			Name:          name,
+			Attribute:     attribute,
+			Severity:      severity,
+			Message:       fmt.Sprintf(format, args...),
+		})
+	}
+	line := block.DefRange().Start.Line
+
+	document, exists := index.resources[terraformType]
+	if !exists {
+		addProblem(line, "", ConfigSeverityError, "unknown resource type %s", terraformType)
+		return
+	}
+	if document.Deprecated {
+		addProblem(line, "", ConfigSeverityWarning, "resource %s is deprecated: %s", terraformType, document.DeprecationMessage)
+	}
+	if len(document.Schema) == 0 {
+		// The schema couldn't be extracted, there is nothing to check the attributes against
+		return
+	}
+
+	schema := make(map[string]SchemaAttribute, len(document.Schema))
+	for _, attribute := range document.Schema {
+		schema[attribute.Name] = attribute
+	}
+	set := make(map[string]bool)
+	checkArgument := func(argument string, line int) {
+		set[argument] = true
+		if terraformMetaArguments[argument] {
+			return
+		}
+		attribute, exists := schema[argument]
+		switch {
+		case !exists:
+			addProblem(line, argument, ConfigSeverityError, "unknown attribute %s", argument)
+		case attribute.Deprecated != "":
+			addProblem(line, argument, ConfigSeverityWarning, "attribute %s is deprecated: %s", argument, attribute.Deprecated)
+		}
+	}
+	for argument, attribute := range block.Body.Attributes {
+		checkArgument(argument, attribute.SrcRange.Start.Line)
+	}
+	for _, nested := range block.Body.Blocks {
+		// dynamic "ip_rules" { ... } generates ip_rules blocks
+		if nested.Type == "dynamic" && len(nested.Labels) == 1 {
+			checkArgument(nested.Labels[0], nested.DefRange().Start.Line)
+			continue
+		}
+		checkArgument(nested.Type, nested.DefRange().Start.Line)
+	}
+
+	for _, attribute := range document.Schema {
+		if attribute.Required && !set[attribute.Name] {
+			addProblem(line, attribute.Name, ConfigSeverityError, "required attribute %s is not set", attribute.Name)
+		}
+	}
+}
+
+// checkDataBlock checks a data block refers to a known data source, no data source schema is indexed
+func (r *ConfigCheckReport) checkDataBlock(fileName string, block *hclsyntax.Block, index *configCheckIndex) {
+	terraformType := block.Labels[0]
+	problem := ConfigProblem{File: fileName, Line: block.DefRange().Start.Line, TerraformType: terraformType, Name: block.Labels[1]}
+	document, exists := index.dataSources[terraformType]
+	switch {
+	case !exists:
+		problem.Severity = ConfigSeverityError
+		problem.Message = fmt.Sprintf("unknown data source %s", terraformType)
+	case document.Deprecated:
+		problem.Severity = ConfigSeverityWarning
+		problem.Message = fmt.Sprintf("data source %s is deprecated: %s", terraformType, document.DeprecationMessage)
+	default:
+		return
+	}
+	r.Problems = append(r.Problems, problem)
+}
@@ -0,0 +1,107 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigCheckIndex writes a minimal index with a resource schema and data sources to a temporary directory and
+// returns the directory
+func writeConfigCheckIndex(t *testing.T) string {
+	indexDir := t.TempDir()
+	index := &TerraformProviderIndex{}
+	require.NoError(t, index.writeJSONFile(filepath.Join(indexDir, DocumentKindResource, "azurerm_key_vault.json"), TerraformResource{
+		TerraformType: "azurerm_key_vault",
+		Schema: []SchemaAttribute{
+			{Name: "name", Type: "TypeString", Required: true},
+			{Name: "resource_group_name", SchemaFunc: "commonschema.ResourceGroupName"},
+			{Name: "sku_name", Type: "TypeString", Required: true},
+			{Name: "enable_rbac_authorization", Type: "TypeBool", Optional: true, Deprecated: "use rbac_authorization_enabled instead"},
+			{Name: "network_acls", Type: "TypeList", Optional: true},
+		},
+	}))
+	require.NoError(t, index.writeJSONFile(filepath.Join(indexDir, DocumentKindResource, "azurerm_resource_group.json"), TerraformResource{
+		TerraformType:      "azurerm_resource_group",
+		Deprecated:         true,
+		DeprecationMessage: "use azurerm_resource_group_v2",
+	}))
+	require.NoError(t, index.writeJSONFile(filepath.Join(indexDir, DocumentKindDataSource, "azurerm_client_config.json"), TerraformDataSource{
+		TerraformType: "azurerm_client_config",
+	}))
+	return indexDir
+}
+
+func TestCheckConfigDir(t *testing.T) {
+	indexDir := writeConfigCheckIndex(t)
+	configDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "main.tf"), []byte(`data "azurerm_client_config" "current" {}
+
+data "azurerm_unknown" "missing" {}
+
+resource "azurerm_resource_group" "example" {
+  name     = "example"
+  location = "westeurope"
+}
+
+resource "azurerm_key_vault" "example" {
+  count                     = 1
+  name                      = "example"
+  resource_group_name       = azurerm_resource_group.example.name
+  tenant_id                 = data.azurerm_client_config.current.tenant_id
+  enable_rbac_authorization = true
+
+  dynamic "network_acls" {
+    for_each = []
+    content {}
+  }
+
+  lifecycle {
+    ignore_changes = [tags]
+  }
+}
+
+resource "azurerm_key_vault_typo" "example" {}
+
+resource "random_string" "suffix" {
+  length = 6
+}
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "README.md"), []byte("not terraform"), 0644))
+
+	report, err := CheckConfigDir(configDir, indexDir)
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, report.Blocks)
+	assert.Equal(t, []ConfigProblem{
+		{File: "main.tf", Line: 3, TerraformType: "azurerm_unknown", Name: "missing", Severity: ConfigSeverityError, Message: "unknown data source azurerm_unknown"},
+		{File: "main.tf", Line: 5, TerraformType: "azurerm_resource_group", Name: "example", Severity: ConfigSeverityWarning, Message: "resource azurerm_resource_group is deprecated: use azurerm_resource_group_v2"},
+		{File: "main.tf", Line: 10, TerraformType: "azurerm_key_vault", Name: "example", Attribute: "sku_name", Severity: ConfigSeverityError, Message: "required attribute sku_name is not set"},
+		{File: "main.tf", Line: 14, TerraformType: "azurerm_key_vault", Name: "example", Attribute: "tenant_id", Severity: ConfigSeverityError, Message: "unknown attribute tenant_id"},
+		{File: "main.tf", Line: 15, TerraformType: "azurerm_key_vault", Name: "example", Attribute: "enable_rbac_authorization", Severity: ConfigSeverityWarning, Message: "attribute enable_rbac_authorization is deprecated: use rbac_authorization_enabled instead"},
+		{File: "main.tf", Line: 27, TerraformType: "azurerm_key_vault_typo", Name: "example", Severity: ConfigSeverityError, Message: "unknown resource type azurerm_key_vault_typo"},
+	}, report.Problems)
+	assert.Equal(t, 4, report.Errors())
+}
+
+func TestCheckConfigDir_SyntaxError(t *testing.T) {
+	indexDir := writeConfigCheckIndex(t)
+	configDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "broken.tf"), []byte("resource \"azurerm_key_vault\" \"example\" {\n  name = \n"), 0644))
+
+	report, err := CheckConfigDir(configDir, indexDir)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, report.Problems)
+	assert.Equal(t, "broken.tf", report.Problems[0].File)
+	assert.Equal(t, ConfigSeverityError, report.Problems[0].Severity)
+	assert.Equal(t, 0, report.Blocks)
+}
+
+func TestCheckConfigDir_EmptyIndex(t *testing.T) {
+	_, err := CheckConfigDir(t.TempDir(), t.TempDir())
+	assert.ErrorContains(t, err, "no resource or data source documents")
+}
@@ -0,0 +1,73 @@
+package pkg
+
+import (
+	"go/ast"
+)
+
+// constraintFields are the pluginsdk.Schema fields declaring inter-attribute constraints with the kind recorded for them
+var constraintFields = []struct{ field, kind string }{
+	{"ConflictsWith", "conflicts_with"}, // None of the attributes may be set together with the attribute
+	{"ExactlyOneOf", "exactly_one_of"},  // Exactly one of the attributes must be set
+	{"AtLeastOneOf", "at_least_one_of"}, // At least one of the attributes must be set
+	{"RequiredWith", "required_with"},   // The attributes must be set whenever the attribute is set
+}
+
+// SchemaConstraint is a constraint a schema attribute declares on other attributes of the resource
+type SchemaConstraint struct {
+	Kind       string   `json:"kind" protobuf:"1"`       // "conflicts_with", "exactly_one_of", "at_least_one_of" or "required_with"
+	Attributes []string `json:"attributes" protobuf:"2"` // ["key_vault_secret_id"], paths as declared such as "network_acls.0.bypass"
+}
+
+// AttributeConstraint is a SchemaConstraint with the path of the attribute declaring it
+type AttributeConstraint struct {
+	Attribute  string   `json:"attribute" protobuf:"1"`  // "key_vault_key_id", nested attributes are prefixed with their blocks
+	Kind       string   `json:"kind" protobuf:"2"`       // "conflicts_with"
+	Attributes []string `json:"attributes" protobuf:"3"` // ["key_vault_secret_id"]
+}
+
+// extractSchemaConstraints returns the constraints declared by a pluginsdk.Schema literal. Only string literals are
+// recorded, attribute lists built by expressions are skipped.
+func extractSchemaConstraints(compLit *ast.CompositeLit) []SchemaConstraint {
+	var constraints []SchemaConstraint
+	for _, constraintField := range constraintFields {
+		if attributes := stringSliceLiteralValues(compositeLitField(compLit, constraintField.field)); len(attributes) > 0 {
+			constraints = append(constraints, SchemaConstraint{Kind: constraintField.kind, Attributes: attributes})
+		}
+	}
+	return constraints
+}
+
+// stringSliceLiteralValues returns the string literals of a []string{...} literal
+func stringSliceLiteralValues(expr ast.Expr) []string {
+	compLit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	var values []string
+	for _, elt := range compLit.Elts {
+		if value := stringLiteralValue(elt); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// attributeConstraints flattens the constraints of a schema into a list in schema order, attributes of nested blocks
+// are prefixed with the names of their blocks
+func attributeConstraints(attributes []SchemaAttribute) []AttributeConstraint {
+	var constraints []AttributeConstraint
+	for _, attribute := range attributes {
+		for _, constraint := range attribute.Constraints {
+			constraints = append(constraints, AttributeConstraint{
+				Attribute:  attribute.Name,
+				Kind:       constraint.Kind,
+				Attributes: constraint.Attributes,
+			})
+		}
+		for _, constraint := range attributeConstraints(attribute.Block) {
+			constraint.Attribute = attribute.Name + "." + constraint.Attribute
+			constraints = append(constraints, constraint)
+		}
+	}
+	return constraints
+}
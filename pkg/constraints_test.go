@@ -0,0 +1,56 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractLegacyResourceSchemaFromPackage_Constraints(t *testing.T) {
+	source := `package keyvault
+
+func resourceKeyVaultCertificate() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Schema: map[string]*pluginsdk.Schema{
+			"key_vault_key_id": {
+				Type:          pluginsdk.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"key_vault_secret_id"},
+				ExactlyOneOf:  []string{"key_vault_key_id", "key_vault_secret_id"},
+			},
+			"key_vault_secret_id": {
+				Type:     pluginsdk.TypeString,
+				Optional: true,
+			},
+			"certificate": {
+				Type:     pluginsdk.TypeList,
+				Optional: true,
+				Elem: &pluginsdk.Resource{
+					Schema: map[string]*pluginsdk.Schema{
+						"contents": {
+							Type:         pluginsdk.TypeString,
+							Optional:     true,
+							RequiredWith: []string{"certificate.0.password"},
+							AtLeastOneOf: certificateSources,
+						},
+					},
+				},
+			},
+		},
+	}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+	schema := extractLegacyResourceSchemaFromPackage("resourceKeyVaultCertificate", packageInfo)
+
+	assert.Equal(t, []SchemaConstraint{
+		{Kind: "conflicts_with", Attributes: []string{"key_vault_secret_id"}},
+		{Kind: "exactly_one_of", Attributes: []string{"key_vault_key_id", "key_vault_secret_id"}},
+	}, schema[0].Constraints)
+	assert.Nil(t, schema[1].Constraints)
+	assert.Equal(t, []AttributeConstraint{
+		{Attribute: "key_vault_key_id", Kind: "conflicts_with", Attributes: []string{"key_vault_secret_id"}},
+		{Attribute: "key_vault_key_id", Kind: "exactly_one_of", Attributes: []string{"key_vault_key_id", "key_vault_secret_id"}},
+		{Attribute: "certificate.contents", Kind: "required_with", Attributes: []string{"certificate.0.password"}},
+	}, attributeConstraints(schema))
+}
@@ -0,0 +1,82 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/afero"
+)
+
+// Files the csv output format writes, the global mappings of resources and data sources
+const (
+	CSVResourcesFileName   = "resources.csv"
+	CSVDataSourcesFileName = "datasources.csv"
+)
+
+// csvMappingHeader is the header row of the csv files
+var csvMappingHeader = []string{"terraform_type", "service", "sdk_type", "namespace", "registration_method", "struct_type"}
+
+// writeCSVFiles writes the global mappings of resources and data sources as resources.csv and datasources.csv, one
+// row per Terraform type ordered by type, for spreadsheet-based audits
+func (index *TerraformProviderIndex) writeCSVFiles(outputDir string, progressCallback ProgressCallback) error {
+	mappings := index.BuildGlobalMappings()
+	namespaces := make(map[string]string, len(index.Services))
+	for _, service := range index.Services {
+		namespaces[service.ServiceName] = service.PackagePath
+	}
+
+	files := []struct {
+		name    string
+		entries map[string]GlobalMappingEntry
+	}{
+		{CSVResourcesFileName, mappings.AllResources},
+		{CSVDataSourcesFileName, mappings.AllDataSources},
+	}
+	progressTracker := NewProgressTracker("writing", len(files), progressCallback)
+	if err := index.Output.fs().MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", outputDir, err)
+	}
+	for _, file := range files {
+		content, err := mappingCSV(file.entries, namespaces)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", file.name, err)
+		}
+		filePath := filepath.Join(outputDir, file.name)
+		if err := afero.WriteFile(index.Output.fs(), filePath, content, 0644); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", filePath, err)
+		}
+		progressTracker.UpdateProgress(file.name)
+	}
+	progressTracker.Complete()
+	return nil
+}
+
+// mappingCSV renders mapping entries as CSV, namespaces maps service names to their package paths
+func mappingCSV(entries map[string]GlobalMappingEntry, namespaces map[string]string) ([]byte, error) {
+	terraformTypes := make([]string, 0, len(entries))
+	for terraformType := range entries {
+		terraformTypes = append(terraformTypes, terraformType)
+	}
+	sort.Strings(terraformTypes)
+
+	var content bytes.Buffer
+	writer := csv.NewWriter(&content)
+	if err := writer.Write(csvMappingHeader); err != nil {
+		return nil, err
+	}
+	for _, terraformType := range terraformTypes {
+		entry := entries[terraformType]
+		record := []string{terraformType, entry.Service, entry.SDKType, namespaces[entry.Service], entry.RegistrationMethod, entry.StructType}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return content.Bytes(), nil
+}
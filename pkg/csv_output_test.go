@@ -0,0 +1,48 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/csv"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformProviderIndex_WriteIndexFiles_CSVFormat(t *testing.T) {
+	index := createTestTerraformProviderIndex()
+	index.Output = OutputConfig{Format: OutputFormatCSV}
+	fs := afero.NewMemMapFs()
+	index.Output.Fs = fs
+	outputDir := "/test/output"
+
+	require.NoError(t, index.WriteIndexFiles(outputDir, nil))
+
+	mappings := index.BuildGlobalMappings()
+	for fileName, entries := range map[string]map[string]GlobalMappingEntry{
+		CSVResourcesFileName:   mappings.AllResources,
+		CSVDataSourcesFileName: mappings.AllDataSources,
+	} {
+		content, err := afero.ReadFile(fs, filepath.Join(outputDir, fileName))
+		require.NoError(t, err)
+		records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, len(entries)+1, fileName)
+		assert.Equal(t, csvMappingHeader, records[0])
+
+		for i, record := range records[1:] {
+			if i > 0 {
+				assert.Less(t, records[i][0], record[0], "rows are ordered by terraform type")
+			}
+			entry, ok := entries[record[0]]
+			require.True(t, ok, record[0])
+			assert.Equal(t, []string{entry.Service, entry.SDKType, index.Services[0].PackagePath, entry.RegistrationMethod, entry.StructType}, record[1:])
+		}
+	}
+
+	exists, err := afero.Exists(fs, filepath.Join(outputDir, index.Output.MainIndexFileName()))
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
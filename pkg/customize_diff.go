@@ -0,0 +1,63 @@
+package pkg
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// customizeDiffCompositions are the helpers that only combine or adapt other CustomizeDiff functions,
+// their arguments are followed instead of recording the helper itself
+var customizeDiffCompositions = map[string]bool{
+	"All":                  true,
+	"Sequence":             true,
+	"CustomDiffWithAll":    true,
+	"CustomDiffInSequence": true,
+	"CustomizeDiffShim":    true,
+}
+
+// extractLegacyCustomizeDiffFromPackage extracts the CustomizeDiff function references from the pluginsdk.Resource
+// returned by a legacy registration function
+func extractLegacyCustomizeDiffFromPackage(registrationMethod string, packageInfo *gophon.PackageInfo) []string {
+	for _, compLit := range findResourceLiterals(findFunctionDecl(packageInfo, registrationMethod)) {
+		if functions := extractCustomizeDiffFunctions(compositeLitField(compLit, "CustomizeDiff")); len(functions) > 0 {
+			return functions
+		}
+	}
+	return nil
+}
+
+// extractTypedCustomizeDiffFromPackage returns the CustomizeDiff method reference of a typed resource when it is implemented
+func extractTypedCustomizeDiffFromPackage(structName string, packageInfo *gophon.PackageInfo) []string {
+	if findMethodDecl(packageInfo, structName, "CustomizeDiff") == nil {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s.CustomizeDiff", structName)}
+}
+
+// extractCustomizeDiffFunctions flattens a CustomizeDiff expression into the functions it references, for example
+// pluginsdk.CustomDiffWithAll(pluginsdk.CustomizeDiffShim(resourceFooCustomizeDiff), pluginsdk.ForceNewIfChange("x", ...))
+// results in ["resourceFooCustomizeDiff", "pluginsdk.ForceNewIfChange"]
+func extractCustomizeDiffFunctions(expr ast.Expr) []string {
+	var functions []string
+
+	switch e := expr.(type) {
+	case nil:
+		return nil
+	case *ast.CallExpr:
+		if customizeDiffCompositions[extractFunctionReference(e.Fun)] {
+			for _, arg := range e.Args {
+				functions = append(functions, extractCustomizeDiffFunctions(arg)...)
+			}
+			return functions
+		}
+		// Any other call builds a CustomizeDiff function, e.g. customdiff.ForceNewIfChange(...)
+		return []string{types.ExprString(e.Fun)}
+	case *ast.Ident, *ast.SelectorExpr:
+		return []string{types.ExprString(e)}
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractLegacyCustomizeDiffFromPackage(t *testing.T) {
+	testCases := []struct {
+		name     string
+		src      string
+		expected []string
+	}{
+		{
+			name: "shim wrapped function",
+			src: `package compute
+
+func resourceLinuxVirtualMachine() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		CustomizeDiff: pluginsdk.CustomizeDiffShim(resourceLinuxVirtualMachineCustomizeDiff),
+	}
+}`,
+			expected: []string{"resourceLinuxVirtualMachineCustomizeDiff"},
+		},
+		{
+			name: "customdiff.All composition",
+			src: `package containers
+
+func resourceKubernetesCluster() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		CustomizeDiff: pluginsdk.CustomDiffInSequence(
+			customdiff.All(
+				pluginsdk.ForceNewIfChange("windows_profile.0.gmsa", func(ctx context.Context, old, new, meta interface{}) bool {
+					return len(old.([]interface{})) != 0
+				}),
+				customdiff.ValidateChange("dns_prefix", validateDnsPrefix),
+			),
+			pluginsdk.CustomizeDiffShim(resourceKubernetesClusterCustomizeDiff),
+		),
+	}
+}`,
+			expected: []string{"pluginsdk.ForceNewIfChange", "customdiff.ValidateChange", "resourceKubernetesClusterCustomizeDiff"},
+		},
+		{
+			name: "variable assignment with direct reference",
+			src: `package network
+
+func resourceVirtualNetwork() *pluginsdk.Resource {
+	resource := &pluginsdk.Resource{
+		CustomizeDiff: validate.VirtualNetworkCustomizeDiff,
+	}
+	return resource
+}`,
+			expected: []string{"validate.VirtualNetworkCustomizeDiff"},
+		},
+		{
+			name: "no customize diff",
+			src: `package resource
+
+func resourceResourceGroup() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Create: resourceResourceGroupCreate,
+	}
+}`,
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			packageInfo := parsePackageInfo(t, tc.src)
+			registrationMethod := packageInfo.Functions[0].Name
+
+			result := extractLegacyCustomizeDiffFromPackage(registrationMethod, packageInfo)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestExtractTypedCustomizeDiffFromPackage(t *testing.T) {
+	source := `package containerapps
+
+type ContainerAppResource struct{}
+
+func (r ContainerAppResource) CustomizeDiff() sdk.ResourceFunc {
+	return sdk.ResourceFunc{
+		Timeout: 5 * time.Minute,
+		Func: func(ctx context.Context, metadata sdk.ResourceMetaData) error {
+			return nil
+		},
+	}
+}
+
+type ContainerAppEnvironmentResource struct{}
+`
+
+	packageInfo := parsePackageInfo(t, source)
+
+	assert.Equal(t, []string{"ContainerAppResource.CustomizeDiff"}, extractTypedCustomizeDiffFromPackage("ContainerAppResource", packageInfo))
+	assert.Nil(t, extractTypedCustomizeDiffFromPackage("ContainerAppEnvironmentResource", packageInfo))
+}
+
+func TestNewTerraformResourceInfo_CustomizeDiff(t *testing.T) {
+	serviceReg := ServiceRegistration{
+		ResourceTerraformTypes: map[string]string{
+			"ContainerAppResource": "azurerm_container_app",
+		},
+		ResourceCustomizeDiff: map[string][]string{
+			"azurerm_container_app": {"ContainerAppResource.CustomizeDiff"},
+		},
+	}
+
+	result := newTerraformResourceInfo("azurerm_container_app", "ContainerAppResource", "", "modern_sdk", serviceReg)
+	assert.Equal(t, []string{"ContainerAppResource.CustomizeDiff"}, result.CustomizeDiff)
+}
@@ -0,0 +1,182 @@
+package pkg
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// UnreferencedFunction is an exported package level function of a service package that nothing in the scanned
+// services, including the tests of its own package, refers to
+type UnreferencedFunction struct {
+	Service  string `json:"service"`   // "keyvault"
+	Package  string `json:"package"`   // "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault"
+	Function string `json:"function"`  // "ExpandKeyVaultNetworkAcls"
+	Index    string `json:"index"`     // "func.ExpandKeyVaultNetworkAcls.goindex"
+	FileName string `json:"file_name"` // "key_vault_resource.go"
+}
+
+// BuildUnreferencedFunctionsReport lists the likely dead exported helpers of the scanned service packages. Functions
+// may still be used by packages outside of the scanned services, so the report is a starting point for a manual review.
+func (index *TerraformProviderIndex) BuildUnreferencedFunctionsReport() []UnreferencedFunction {
+	// Names referenced from other packages, keyed by the import path of the package declaring them
+	externalRefs := make(map[string]map[string]bool)
+	references := make([]*serviceReferences, len(index.Services))
+
+	for i, service := range index.Services {
+		references[i] = service.references
+		if references[i] == nil {
+			references[i] = service.collectServiceReferences()
+		}
+		for importPath, names := range references[i].external {
+			if externalRefs[importPath] == nil {
+				externalRefs[importPath] = make(map[string]bool)
+			}
+			for name := range names {
+				externalRefs[importPath][name] = true
+			}
+		}
+	}
+
+	report := []UnreferencedFunction{}
+	for i, service := range index.Services {
+		for _, function := range references[i].functions {
+			if references[i].local[function.Function] || externalRefs[service.PackagePath][function.Function] {
+				continue
+			}
+			report = append(report, function)
+		}
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Service != report[j].Service {
+			return report[i].Service < report[j].Service
+		}
+		return report[i].Function < report[j].Function
+	})
+	return report
+}
+
+// writeUnreferencedFunctionsFile writes audit/unreferenced-functions.json
+func (index *TerraformProviderIndex) writeUnreferencedFunctionsFile(outputDir string) error {
+	return index.writeJSONFile(filepath.Join(outputDir, "audit", "unreferenced-functions.json"), index.BuildUnreferencedFunctionsReport())
+}
+
+// serviceReferences are the identifiers the files and tests of a service package refer to and the exported functions
+// it declares, all the unreferenced functions report needs from the parsed package
+type serviceReferences struct {
+	local     map[string]bool            // Names referenced from the package itself
+	external  map[string]map[string]bool // Names referenced from other packages, keyed by their import path
+	functions []UnreferencedFunction     // Exported package level functions, unreferenced until proven otherwise
+}
+
+// collectServiceReferences collects the references and exported functions of the package of a service, nothing when
+// the package was dropped
+func (s ServiceRegistration) collectServiceReferences() *serviceReferences {
+	references := &serviceReferences{
+		local:    make(map[string]bool),
+		external: make(map[string]map[string]bool),
+	}
+	if s.Package == nil {
+		return references
+	}
+
+	for _, file := range serviceFilesWithTests(s.Package) {
+		collectReferences(file, references.local, references.external)
+	}
+	for _, funcInfo := range s.Package.Functions {
+		fn := funcInfo.FuncDecl
+		if fn == nil || fn.Recv != nil || !fn.Name.IsExported() {
+			continue
+		}
+		fileName := ""
+		if funcInfo.Range != nil && funcInfo.FileInfo != nil {
+			fileName = filepath.Base(funcInfo.FileInfo.FileName)
+		}
+		references.functions = append(references.functions, UnreferencedFunction{
+			Service:  s.ServiceName,
+			Package:  s.PackagePath,
+			Function: fn.Name.Name,
+			Index:    "func." + fn.Name.Name + ".goindex",
+			FileName: fileName,
+		})
+	}
+	return references
+}
+
+// serviceFilesWithTests returns the files of a service package together with the _test.go files next to them,
+// which gophon doesn't load
+func serviceFilesWithTests(packageInfo *gophon.PackageInfo) []*ast.File {
+	if packageInfo == nil {
+		return nil
+	}
+
+	var files []*ast.File
+	dirs := make(map[string]bool)
+	for _, fileInfo := range packageInfo.Files {
+		if fileInfo.File == nil {
+			continue
+		}
+		files = append(files, fileInfo.File)
+		dirs[filepath.Dir(fileInfo.FileName)] = true
+	}
+
+	for dir := range dirs {
+		testFiles, _ := filepath.Glob(filepath.Join(dir, "*_test.go"))
+		for _, testFile := range testFiles {
+			if file, err := parser.ParseFile(token.NewFileSet(), testFile, nil, parser.SkipObjectResolution); err == nil {
+				files = append(files, file)
+			}
+		}
+	}
+	return files
+}
+
+// collectReferences records the identifiers a file refers to: local names in localRefs, and pkg.Name selectors
+// in externalRefs keyed by the import path of pkg. Declaration names don't count as references.
+func collectReferences(file *ast.File, localRefs map[string]bool, externalRefs map[string]map[string]bool) {
+	imports := make(map[string]string)
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		alias := filepath.Base(importPath)
+		if spec.Name != nil {
+			alias = spec.Name.Name
+		}
+		imports[alias] = importPath
+	}
+
+	declNames := make(map[*ast.Ident]bool)
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			declNames[fn.Name] = true
+		}
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.SelectorExpr:
+			if alias, ok := e.X.(*ast.Ident); ok {
+				if importPath, exists := imports[alias.Name]; exists {
+					if externalRefs[importPath] == nil {
+						externalRefs[importPath] = make(map[string]bool)
+					}
+					externalRefs[importPath][e.Sel.Name] = true
+					return false
+				}
+			}
+		case *ast.Ident:
+			if !declNames[e] {
+				localRefs[e.Name] = true
+			}
+		}
+		return true
+	})
+}
@@ -0,0 +1,118 @@
+package pkg
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildUnreferencedFunctionsReport(t *testing.T) {
+	keyVaultSource := `package keyvault
+
+func resourceKeyVault() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Schema: map[string]*pluginsdk.Schema{
+			"network_acls": SchemaNetworkAcls(),
+		},
+	}
+}
+
+func SchemaNetworkAcls() *pluginsdk.Schema { return nil }
+
+func ExpandNetworkAcls() {}
+
+func FlattenNetworkAcls() {}
+
+func UsedByOtherService() {}
+
+func UsedByTests() {}
+
+func unexportedHelper() {}
+
+func (r KeyVaultResource) Exported() {}`
+
+	storageSource := `package storage
+
+import "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault"
+
+func resourceStorageAccount() *pluginsdk.Resource {
+	keyvault.UsedByOtherService()
+	return nil
+}`
+
+	keyVaultDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(keyVaultDir, "key_vault_test.go"), []byte(`package keyvault_test
+
+import kv "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault"
+
+func TestUsedByTests(t *testing.T) {
+	kv.UsedByTests()
+}`), 0644))
+
+	keyVaultPackage := parsePackageInfo(t, keyVaultSource)
+	// gophon records the absolute file path in FileName
+	keyVaultPackage.Files[0].FileName = filepath.Join(keyVaultDir, "key_vault_resource.go")
+
+	storagePackage := parsePackageInfo(t, storageSource)
+	storagePackage.Files[0].FileName = filepath.Join(t.TempDir(), "storage_account_resource.go")
+
+	index := &TerraformProviderIndex{
+		Services: []ServiceRegistration{
+			{
+				ServiceName: "keyvault",
+				PackagePath: "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault",
+				Package:     keyVaultPackage,
+			},
+			{
+				ServiceName: "storage",
+				PackagePath: "github.com/hashicorp/terraform-provider-azurerm/internal/services/storage",
+				Package:     storagePackage,
+			},
+		},
+	}
+
+	report := index.BuildUnreferencedFunctionsReport()
+
+	assert.Equal(t, []UnreferencedFunction{
+		{
+			Service:  "keyvault",
+			Package:  "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault",
+			Function: "ExpandNetworkAcls",
+			Index:    "func.ExpandNetworkAcls.goindex",
+			FileName: "key_vault_resource.go",
+		},
+		{
+			Service:  "keyvault",
+			Package:  "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault",
+			Function: "FlattenNetworkAcls",
+			Index:    "func.FlattenNetworkAcls.goindex",
+			FileName: "key_vault_resource.go",
+		},
+	}, report)
+}
+
+func TestTerraformProviderIndex_WriteUnreferencedFunctionsFile(t *testing.T) {
+	// Setup
+	index := &TerraformProviderIndex{}
+	fs := afero.NewMemMapFs()
+	index.Output.Fs = fs
+	outputDir := "/test/output"
+
+	// Execute
+	err := index.writeUnreferencedFunctionsFile(outputDir)
+
+	// Verify
+	require.NoError(t, err)
+
+	data, err := afero.ReadFile(fs, filepath.Join(outputDir, "audit", "unreferenced-functions.json"))
+	require.NoError(t, err)
+
+	var report []UnreferencedFunction
+	require.NoError(t, json.Unmarshal(data, &report))
+	assert.Empty(t, report)
+}
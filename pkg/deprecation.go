@@ -0,0 +1,92 @@
+package pkg
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// extractLegacyDeprecationFromPackage extracts the DeprecationMessage field from the pluginsdk.Resource returned by a
+// legacy registration function, it works for both resources and data sources. The field is set in the resource
+// literal, or assigned afterwards, such as resource.DeprecationMessage = "..." behind a feature flag.
+func extractLegacyDeprecationFromPackage(registrationMethod string, packageInfo *gophon.PackageInfo) string {
+	fn := findFunctionDecl(packageInfo, registrationMethod)
+	for _, compLit := range findResourceLiterals(fn) {
+		if message := stringExprValue(compositeLitField(compLit, "DeprecationMessage")); message != "" {
+			return message
+		}
+	}
+	return assignedDeprecationMessage(fn)
+}
+
+// assignedDeprecationMessage returns the first value assigned to a DeprecationMessage field in the body of fn, at any
+// depth, rendered by stringExprValue
+func assignedDeprecationMessage(fn *ast.FuncDecl) string {
+	if fn == nil || fn.Body == nil {
+		return ""
+	}
+	var message string
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if message != "" {
+			return false
+		}
+		assignStmt, ok := n.(*ast.AssignStmt)
+		if !ok || len(assignStmt.Lhs) != len(assignStmt.Rhs) {
+			return true
+		}
+		for i, lhs := range assignStmt.Lhs {
+			if selector, ok := lhs.(*ast.SelectorExpr); ok && selector.Sel.Name == "DeprecationMessage" {
+				message = stringExprValue(assignStmt.Rhs[i])
+				break
+			}
+		}
+		return true
+	})
+	return message
+}
+
+// extractTypedDeprecationFromPackage extracts the deprecation markers of a typed resource or data source:
+// - DeprecationMessage() string (sdk.ResourceWithDeprecationAndNoReplacement)
+// - DeprecatedInFavourOfResource() string (sdk.ResourceWithDeprecationReplacedBy)
+func extractTypedDeprecationFromPackage(structName string, packageInfo *gophon.PackageInfo) string {
+	if message := returnedStringValue(findMethodDecl(packageInfo, structName, "DeprecationMessage")); message != "" {
+		return message
+	}
+	if replacement := returnedStringValue(findMethodDecl(packageInfo, structName, "DeprecatedInFavourOfResource")); replacement != "" {
+		return fmt.Sprintf("deprecated in favour of %s", replacement)
+	}
+	return ""
+}
+
+// returnedStringValue returns the value of the first return statement of a function rendered by stringExprValue
+func returnedStringValue(fn *ast.FuncDecl) string {
+	if fn == nil || fn.Body == nil {
+		return ""
+	}
+	for _, stmt := range fn.Body.List {
+		retStmt, ok := stmt.(*ast.ReturnStmt)
+		if !ok || len(retStmt.Results) == 0 {
+			continue
+		}
+		return stringExprValue(retStmt.Results[0])
+	}
+	return ""
+}
+
+// stringExprValue returns the unquoted value of a string literal, or the source form of any other expression
+// such as a constant reference or a fmt.Sprintf call
+func stringExprValue(expr ast.Expr) string {
+	if expr == nil {
+		return ""
+	}
+	if basicLit, ok := expr.(*ast.BasicLit); ok && basicLit.Kind == token.STRING {
+		if value, err := strconv.Unquote(basicLit.Value); err == nil {
+			return value
+		}
+	}
+	return types.ExprString(expr)
+}
@@ -0,0 +1,104 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractLegacyDeprecationFromPackage(t *testing.T) {
+	source := `package network
+
+func resourceVirtualHubIP() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		DeprecationMessage: "The ` + "`azurerm_virtual_hub_ip`" + ` resource has been superseded by the ` + "`azurerm_virtual_hub_ip_configuration`" + ` resource",
+		Create:             resourceVirtualHubIPCreate,
+	}
+}
+
+func dataSourceNetworkWatcher() *pluginsdk.Resource {
+	resource := &pluginsdk.Resource{
+		DeprecationMessage: features.DeprecatedInFivePointOh("azurerm_network_watcher"),
+	}
+	return resource
+}
+
+func resourceVirtualHub() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Create: resourceVirtualHubCreate,
+	}
+}
+
+func resourceVirtualHubBgpConnection() *pluginsdk.Resource {
+	resource := &pluginsdk.Resource{
+		Create: resourceVirtualHubBgpConnectionCreate,
+	}
+	if !features.FivePointOh() {
+		resource.DeprecationMessage = "The ` + "`azurerm_virtual_hub_bgp_connection`" + ` resource will be removed in v5.0"
+	}
+	return resource
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+
+	assert.Equal(t, "The `azurerm_virtual_hub_ip` resource has been superseded by the `azurerm_virtual_hub_ip_configuration` resource",
+		extractLegacyDeprecationFromPackage("resourceVirtualHubIP", packageInfo))
+	assert.Equal(t, `features.DeprecatedInFivePointOh("azurerm_network_watcher")`,
+		extractLegacyDeprecationFromPackage("dataSourceNetworkWatcher", packageInfo))
+	assert.Empty(t, extractLegacyDeprecationFromPackage("resourceVirtualHub", packageInfo))
+	assert.Equal(t, "The `azurerm_virtual_hub_bgp_connection` resource will be removed in v5.0",
+		extractLegacyDeprecationFromPackage("resourceVirtualHubBgpConnection", packageInfo), "deprecations assigned behind a feature flag")
+}
+
+func TestExtractTypedDeprecationFromPackage(t *testing.T) {
+	source := `package appservice
+
+type AppServiceEnvironmentV2Resource struct{}
+
+func (r AppServiceEnvironmentV2Resource) DeprecationMessage() string {
+	return "The ` + "`azurerm_app_service_environment`" + ` resource has been superseded"
+}
+
+type FunctionAppResource struct{}
+
+func (r *FunctionAppResource) DeprecatedInFavourOfResource() string {
+	return "azurerm_linux_function_app"
+}
+
+type LinuxWebAppResource struct{}
+`
+
+	packageInfo := parsePackageInfo(t, source)
+
+	assert.Equal(t, "The `azurerm_app_service_environment` resource has been superseded",
+		extractTypedDeprecationFromPackage("AppServiceEnvironmentV2Resource", packageInfo))
+	assert.Equal(t, "deprecated in favour of azurerm_linux_function_app",
+		extractTypedDeprecationFromPackage("FunctionAppResource", packageInfo))
+	assert.Empty(t, extractTypedDeprecationFromPackage("LinuxWebAppResource", packageInfo))
+}
+
+func TestNewTerraformInfo_Deprecation(t *testing.T) {
+	serviceReg := ServiceRegistration{
+		ResourceDeprecations: map[string]string{
+			"azurerm_virtual_hub_ip": "superseded by azurerm_virtual_hub_ip_configuration",
+		},
+		DataSourceDeprecations: map[string]string{
+			"azurerm_network_watcher": "deprecated",
+		},
+		DataSourceMethods: map[string]*LegacyDataSourceMethods{
+			"azurerm_network_watcher": {ReadMethod: "dataSourceNetworkWatcherRead"},
+		},
+	}
+
+	resource := newTerraformResourceInfo("azurerm_virtual_hub_ip", "", "resourceVirtualHubIP", "legacy_pluginsdk", serviceReg)
+	assert.True(t, resource.Deprecated)
+	assert.Equal(t, "superseded by azurerm_virtual_hub_ip_configuration", resource.DeprecationMessage)
+
+	resource = newTerraformResourceInfo("azurerm_virtual_hub", "", "resourceVirtualHub", "legacy_pluginsdk", serviceReg)
+	assert.False(t, resource.Deprecated)
+	assert.Empty(t, resource.DeprecationMessage)
+
+	dataSource := newTerraformDataSourceInfo("azurerm_network_watcher", "", "dataSourceNetworkWatcher", "legacy_pluginsdk", serviceReg)
+	assert.True(t, dataSource.Deprecated)
+	assert.Equal(t, "deprecated", dataSource.DeprecationMessage)
+}
@@ -0,0 +1,25 @@
+// Package pkg scans the service packages of the Terraform AzureRM provider and writes the index of its resources,
+// data sources, ephemeral resources, list resources and actions.
+//
+// The package is usable as a library. Its public API is made of:
+//
+//   - Scanner and ScanTerraformProviderServices, which scan a provider source tree into a TerraformProviderIndex.
+//   - TerraformProviderIndex, the in-memory index, with the lookups LookupResource, LookupByStruct and
+//     ResourcesByService, the iterators Documents and DocumentsOfKind, and the reports built from it.
+//   - The writers of TerraformProviderIndex, WriteIndexFiles, WriteMainIndex, WriteDocumentFiles and WriteSARIFFile,
+//     and the DocumentEmitter extension point of the output formats.
+//   - LoadIndex and the per-file loaders, which read a written index back into the same types.
+//   - The document types, TerraformResource, TerraformDataSource, TerraformEphemeral, TerraformListResource and
+//     TerraformAction, which are also described by the JSON Schemas of the schemas directory.
+//
+// Extraction of the registrations, schemas and CRUD functions from the provider source, the writers of the single
+// index files and the statistics builder are unexported. The parsing of the service packages, the worker pool and
+// the JSON writer live in the internal/goparse, internal/parallel and internal/output packages.
+//
+// Every exported declaration is listed in api.txt. The API follows the semantic versions of the module: removing or
+// changing a declaration of api.txt is a breaking change and only happens with a new major version, new
+// declarations are added with minor versions. TestPublicAPI_UpToDate fails while api.txt is outdated,
+// go generate ./pkg regenerates it.
+package pkg
+
+//go:generate go test -run TestPublicAPI_UpToDate -update-api .
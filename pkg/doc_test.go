@@ -0,0 +1,131 @@
+package pkg
+
+import (
+	"bytes"
+	"flag"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// publicAPIFile lists every exported declaration of the package, see the package documentation
+const publicAPIFile = "api.txt"
+
+var updateAPI = flag.Bool("update-api", false, "Regenerate api.txt from the exported declarations")
+
+func TestPublicAPI_UpToDate(t *testing.T) {
+	generated := generatePublicAPI(t)
+	if *updateAPI {
+		require.NoError(t, os.WriteFile(publicAPIFile, []byte(generated), 0644))
+		return
+	}
+	listed, err := os.ReadFile(publicAPIFile)
+	require.NoError(t, err)
+	assert.Equal(t, string(listed), generated, "%s is outdated, run go generate ./pkg", publicAPIFile)
+}
+
+// generatePublicAPI lists the exported declarations of the non-test files of the package, one per line and sorted
+func generatePublicAPI(t *testing.T) string {
+	sourceFiles, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, sourceFile := range sourceFiles {
+		if strings.HasSuffix(sourceFile, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, sourceFile, nil, 0)
+		require.NoError(t, err)
+		files = append(files, file)
+	}
+	docPackage, err := doc.NewFromFiles(fset, files, "github.com/lonegunmanb/terraform-provider-azurerm-index/pkg")
+	require.NoError(t, err)
+
+	var lines []string
+	values := func(values []*doc.Value) {
+		for _, value := range values {
+			for _, name := range value.Names {
+				if ast.IsExported(name) {
+					lines = append(lines, value.Decl.Tok.String()+" "+name)
+				}
+			}
+		}
+	}
+	funcs := func(funcs []*doc.Func) {
+		for _, fn := range funcs {
+			decl := *fn.Decl
+			decl.Doc, decl.Body = nil, nil
+			lines = append(lines, printPublicAPINode(t, fset, &decl))
+		}
+	}
+
+	values(docPackage.Consts)
+	values(docPackage.Vars)
+	funcs(docPackage.Funcs)
+	for _, docType := range docPackage.Types {
+		values(docType.Consts)
+		values(docType.Vars)
+		funcs(docType.Funcs)
+		funcs(docType.Methods)
+		for _, spec := range docType.Decl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			if typeSpec.Name.Name != docType.Name {
+				continue
+			}
+			lines = append(lines, publicAPITypeLines(t, fset, typeSpec)...)
+		}
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// publicAPITypeLines lists a type with the exported fields of structs and the methods of interfaces, one per line
+func publicAPITypeLines(t *testing.T, fset *token.FileSet, typeSpec *ast.TypeSpec) []string {
+	name := typeSpec.Name.Name
+	switch typ := typeSpec.Type.(type) {
+	case *ast.StructType:
+		lines := []string{"type " + name + " struct"}
+		for _, field := range typ.Fields.List {
+			fieldType := printPublicAPINode(t, fset, field.Type)
+			if len(field.Names) == 0 {
+				lines = append(lines, "type "+name+" struct, embedded "+fieldType)
+			}
+			for _, fieldName := range field.Names {
+				if fieldName.IsExported() {
+					lines = append(lines, "type "+name+" struct, "+fieldName.Name+" "+fieldType)
+				}
+			}
+		}
+		return lines
+	case *ast.InterfaceType:
+		lines := []string{"type " + name + " interface"}
+		for _, method := range typ.Methods.List {
+			for _, methodName := range method.Names {
+				lines = append(lines, "type "+name+" interface, "+methodName.Name+strings.TrimPrefix(printPublicAPINode(t, fset, method.Type), "func"))
+			}
+		}
+		return lines
+	}
+	assign := " "
+	if typeSpec.Assign.IsValid() {
+		assign = " = "
+	}
+	return []string{"type " + name + assign + printPublicAPINode(t, fset, typeSpec.Type)}
+}
+
+// printPublicAPINode prints a declaration or type expression on a single line
+func printPublicAPINode(t *testing.T, fset *token.FileSet, node interface{}) string {
+	var buf bytes.Buffer
+	require.NoError(t, printer.Fprint(&buf, fset, node))
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
@@ -0,0 +1,106 @@
+package pkg
+
+import (
+	"fmt"
+	"iter"
+	"path/filepath"
+
+	"github.com/lonegunmanb/terraform-provider-azurerm-index/pkg/internal/output"
+	"github.com/lonegunmanb/terraform-provider-azurerm-index/pkg/internal/parallel"
+	"github.com/spf13/afero"
+)
+
+// DocumentEmitter writes the per-resource documents of one kind. WriteDocumentFiles hands each emitter an iterator of
+// the documents of its kind, so a new kind of document only needs a documentKinds entry and an emitter.
+type DocumentEmitter interface {
+	// Kind is the kind of the documents the emitter writes, "resources", "datasources" or "ephemeral"
+	Kind() string
+	// Emit writes the documents, all of the emitter's kind
+	Emit(documents iter.Seq[IndexDocument]) error
+}
+
+// JSONDocumentEmitter writes each document as indented JSON to <OutputDir>/<kind>/<terraform type>.json, with up to
+// Workers files written in parallel
+type JSONDocumentEmitter struct {
+	DocumentKind string           // "resources"
+	OutputDir    string           // "./index"
+	Workers      int              // Zero writes one file per CPU in parallel
+	Progress     *ProgressTracker // Reports every written file, may be nil
+	Events       *EventBus        // Receives a ScanEventDocumentWritten event for every written file, may be nil
+	Fs           afero.Fs         // File system the files are written to, the local file system when nil
+	// Called with every written document and the path of its file, may be nil
+	OnWritten func(document IndexDocument, path string)
+}
+
+// Kind returns the kind of the documents the emitter writes
+func (e JSONDocumentEmitter) Kind() string {
+	return e.DocumentKind
+}
+
+// Emit writes the documents to the directory of their kind
+func (e JSONDocumentEmitter) Emit(documents iter.Seq[IndexDocument]) error {
+	description := e.DocumentKind
+	if kind := findDocumentKind(e.DocumentKind); kind != nil {
+		description = kind.description
+	}
+	dir := filepath.Join(e.OutputDir, e.DocumentKind)
+	fs := e.Fs
+	if fs == nil {
+		fs = afero.NewOsFs()
+	}
+
+	var tasks []func() error
+	for document := range documents {
+		tasks = append(tasks, func() error {
+			fileName := fmt.Sprintf("%s.json", document.TerraformType)
+			filePath := filepath.Join(dir, fileName)
+			if err := output.WriteJSONFile(fs, filePath, document.Content); err != nil {
+				return fmt.Errorf("failed to write %s file %s: %w", description, fileName, err)
+			}
+			e.Progress.UpdateProgress(fmt.Sprintf("%s %s", description, document.TerraformType))
+			e.Events.Publish(ScanEvent{
+				Type:          ScanEventDocumentWritten,
+				Service:       document.Service,
+				Kind:          document.Kind,
+				TerraformType: document.TerraformType,
+				Path:          filePath,
+			})
+			if e.OnWritten != nil {
+				e.OnWritten(document, filePath)
+			}
+			return nil
+		})
+	}
+	return parallel.Run(tasks, e.Workers)
+}
+
+// documentEmitters returns the emitters writing every kind of document of the index to outputDir
+func (index *TerraformProviderIndex) documentEmitters(outputDir string, progressTracker *ProgressTracker) []DocumentEmitter {
+	emitters := make([]DocumentEmitter, 0, len(documentKinds))
+	for _, kind := range documentKinds {
+		emitters = append(emitters, JSONDocumentEmitter{
+			DocumentKind: kind.kind,
+			OutputDir:    outputDir,
+			Workers:      index.Output.Workers,
+			Progress:     progressTracker,
+			Events:       index.Events,
+			OnWritten:    index.Hooks.OnResourceWritten,
+			Fs:           index.Output.fs(),
+		})
+	}
+	return emitters
+}
+
+// WriteDocumentFiles writes the per-resource documents of every kind through the given emitters, the JSON files of
+// the resources/, datasources/ and ephemeral/ directories when no emitters are given
+func (index *TerraformProviderIndex) WriteDocumentFiles(outputDir string, progressTracker *ProgressTracker, emitters ...DocumentEmitter) error {
+	if len(emitters) == 0 {
+		emitters = index.documentEmitters(outputDir, progressTracker)
+	}
+	for _, emitter := range emitters {
+		if err := emitter.Emit(index.DocumentsOfKind(emitter.Kind())); err != nil {
+			return fmt.Errorf("failed to write %s files: %w", emitter.Kind(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package pkg
+
+import (
+	"encoding/json"
+	"iter"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingEmitter records the Terraform types of the documents it is handed
+type recordingEmitter struct {
+	kind           string
+	terraformTypes []string
+}
+
+func (e *recordingEmitter) Kind() string {
+	return e.kind
+}
+
+func (e *recordingEmitter) Emit(documents iter.Seq[IndexDocument]) error {
+	for document := range documents {
+		e.terraformTypes = append(e.terraformTypes, document.TerraformType)
+	}
+	return nil
+}
+
+func TestTerraformProviderIndex_WriteDocumentFiles_CustomEmitters(t *testing.T) {
+	index := createTestTerraformProviderIndex()
+	resources := &recordingEmitter{kind: DocumentKindResource}
+	ephemeral := &recordingEmitter{kind: DocumentKindEphemeral}
+
+	require.NoError(t, index.WriteDocumentFiles("/index", nil, resources, ephemeral))
+
+	var expectedResources, expectedEphemeral []string
+	for _, document := range index.Documents() {
+		switch document.Kind {
+		case DocumentKindResource:
+			expectedResources = append(expectedResources, document.TerraformType)
+		case DocumentKindEphemeral:
+			expectedEphemeral = append(expectedEphemeral, document.TerraformType)
+		}
+	}
+	assert.NotEmpty(t, resources.terraformTypes)
+	assert.ElementsMatch(t, expectedResources, resources.terraformTypes)
+	assert.ElementsMatch(t, expectedEphemeral, ephemeral.terraformTypes)
+}
+
+func TestTerraformProviderIndex_WriteDocumentFiles(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	index := createTestTerraformProviderIndex()
+	index.Output.Fs = fs
+
+	require.NoError(t, index.WriteDocumentFiles("/index", nil))
+
+	for _, document := range index.Documents() {
+		data, err := afero.ReadFile(fs, filepath.Join("/index", document.Kind, document.TerraformType+".json"))
+		require.NoError(t, err)
+		var decoded map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, document.ID, decoded["id"])
+	}
+}
+
+func TestTerraformProviderIndex_DocumentsOfKind(t *testing.T) {
+	index := createTestTerraformProviderIndex()
+
+	var first []IndexDocument
+	for document := range index.DocumentsOfKind(DocumentKindDataSource) {
+		assert.Equal(t, DocumentKindDataSource, document.Kind)
+		first = append(first, document)
+		break
+	}
+	assert.Len(t, first, 1)
+
+	for range index.DocumentsOfKind("functions") {
+		t.Fatal("unknown kinds must yield no documents")
+	}
+}
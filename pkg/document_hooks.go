@@ -0,0 +1,75 @@
+package pkg
+
+import "slices"
+
+// DocumentHooks are called with the individual documents of an index, resources as well as data sources, ephemeral
+// resources, list resources and actions, so embedders can enrich or veto them
+type DocumentHooks struct {
+	// OnResourceDiscovered is called with every document of the scanned services. Returning false vetoes the document:
+	// its registration is removed from the service, so it is left out of the main index, the statistics and every
+	// written file. Kept documents can be enriched by replacing their Content, for example with a TerraformResource
+	// whose XAnnotations carry pricing metadata. The hook is called again whenever a document is built for writing or
+	// lookups, so it must decide the same way every time.
+	OnResourceDiscovered func(document *IndexDocument) bool
+	// OnResourceWritten is called with every document written by the JSON emitters and the path of its file, from
+	// the writing goroutines
+	OnResourceWritten func(document IndexDocument, path string)
+}
+
+// discover runs OnResourceDiscovered on a document, keeping every document when the hook isn't set
+func (h DocumentHooks) discover(document *IndexDocument) bool {
+	return h.OnResourceDiscovered == nil || h.OnResourceDiscovered(document)
+}
+
+// removeVetoedDocuments removes the registrations of the documents OnResourceDiscovered vetoes from their services and
+// returns the number of vetoed documents
+func (index *TerraformProviderIndex) removeVetoedDocuments(hooks DocumentHooks) int {
+	if hooks.OnResourceDiscovered == nil {
+		return 0
+	}
+	services := make(map[string]*ServiceRegistration, len(index.Services))
+	for i := range index.Services {
+		services[index.Services[i].ServiceName] = &index.Services[i]
+	}
+	mappings := index.BuildGlobalMappings()
+	vetoed := 0
+	for _, document := range index.Documents() {
+		if hooks.discover(&document) {
+			continue
+		}
+		entry := mappings.mappingsOf(document.Kind)[document.TerraformType]
+		services[document.Service].removeRegistration(document.Kind, document.TerraformType, entry)
+		vetoed++
+	}
+	return vetoed
+}
+
+// removeRegistration removes the registration of a Terraform type of the given kind from the service
+func (s *ServiceRegistration) removeRegistration(kind, terraformType string, entry GlobalMappingEntry) {
+	without := func(names []string, name string) []string {
+		return slices.DeleteFunc(names, func(n string) bool { return n == name })
+	}
+	switch kind {
+	case DocumentKindResource:
+		if entry.StructType == "" {
+			delete(s.SupportedResources, terraformType)
+			return
+		}
+		s.Resources = without(s.Resources, entry.StructType)
+	case DocumentKindDataSource:
+		if entry.StructType == "" {
+			delete(s.SupportedDataSources, terraformType)
+			return
+		}
+		s.DataSources = without(s.DataSources, entry.StructType)
+	case DocumentKindEphemeral:
+		delete(s.EphemeralTerraformTypes, entry.StructType)
+		s.EphemeralFunctions = without(s.EphemeralFunctions, s.EphemeralConstructors[entry.StructType])
+	case DocumentKindListResource:
+		s.ListResources = without(s.ListResources, entry.StructType)
+		s.ListResourceFunctions = without(s.ListResourceFunctions, s.ListResourceConstructors[entry.StructType])
+	case DocumentKindAction:
+		s.Actions = without(s.Actions, entry.StructType)
+		s.ActionFunctions = without(s.ActionFunctions, s.ActionConstructors[entry.StructType])
+	}
+}
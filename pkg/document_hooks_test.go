@@ -0,0 +1,94 @@
+package pkg
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanner_Hooks(t *testing.T) {
+	servicesDir := filepath.Join("testharness", "internal", "services")
+	basePkgUrl := "github.com/lonegunmanb/terraform-provider-azurerm-index"
+	unhooked, err := Scanner{}.Scan(servicesDir, basePkgUrl, "test-version", nil)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	written := make(map[string]string)
+	hooks := DocumentHooks{
+		OnResourceDiscovered: func(document *IndexDocument) bool {
+			if document.TerraformType == "azurerm_key_vault_certificate" || document.TerraformType == "azurerm_account" {
+				return false
+			}
+			if resource, ok := document.Content.(TerraformResource); ok {
+				resource.XAnnotations = map[string]interface{}{"price": "per hour"}
+				document.Content = resource
+			}
+			return true
+		},
+		OnResourceWritten: func(document IndexDocument, path string) {
+			mu.Lock()
+			defer mu.Unlock()
+			written[document.ID] = path
+		},
+	}
+	index, err := Scanner{Hooks: hooks}.Scan(servicesDir, basePkgUrl, "test-version", nil)
+	require.NoError(t, err)
+
+	assert.NotContains(t, index.GlobalMaps.AllResources, "azurerm_key_vault_certificate")
+	assert.NotContains(t, index.GlobalMaps.AllDataSources, "azurerm_key_vault_certificate")
+	assert.NotContains(t, index.GlobalMaps.AllResources, "azurerm_account")
+	assert.Equal(t, unhooked.Statistics.LegacyResources-1, index.Statistics.LegacyResources)
+	assert.Equal(t, unhooked.Statistics.ModernResources-1, index.Statistics.ModernResources)
+	assert.Equal(t, unhooked.Statistics.TotalDataSources-1, index.Statistics.TotalDataSources)
+	assert.Equal(t, unhooked.Statistics.EphemeralResources-1, index.Statistics.EphemeralResources)
+	// The resource, data source and ephemeral resource azurerm_key_vault_certificate and the resource azurerm_account
+	assert.Len(t, index.Documents(), len(unhooked.Documents())-4)
+
+	_, ok := index.LookupResource("azurerm_key_vault_certificate")
+	assert.False(t, ok)
+	resource, ok := index.LookupResource("azurerm_key_vault")
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"price": "per hour"}, resource.XAnnotations)
+
+	outputDir := t.TempDir()
+	require.NoError(t, index.WriteIndexFiles(outputDir, nil))
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "resources", "azurerm_key_vault.json"))
+	require.NoError(t, err)
+	var document TerraformResource
+	require.NoError(t, json.Unmarshal(data, &document))
+	assert.Equal(t, map[string]interface{}{"price": "per hour"}, document.XAnnotations)
+
+	assert.NoFileExists(t, filepath.Join(outputDir, "resources", "azurerm_key_vault_certificate.json"))
+
+	assert.Len(t, written, len(index.Documents()))
+	assert.Equal(t, filepath.Join(outputDir, "resources", "azurerm_key_vault.json"), written[resource.ID])
+
+	report, err := ValidateIndexDir(outputDir, "")
+	require.NoError(t, err)
+	assert.True(t, report.Valid(), "%v", report.Problems)
+}
+
+func TestServiceRegistration_RemoveRegistration(t *testing.T) {
+	service := ServiceRegistration{
+		SupportedResources:      map[string]string{"azurerm_key_vault": "resourceKeyVault"},
+		Resources:               []string{"KeyVaultResource", "OtherResource"},
+		EphemeralFunctions:      []string{"NewKeyVaultSecretEphemeralResource"},
+		EphemeralTerraformTypes: map[string]string{"KeyVaultSecretEphemeralResource": "azurerm_key_vault_secret"},
+		EphemeralConstructors:   map[string]string{"KeyVaultSecretEphemeralResource": "NewKeyVaultSecretEphemeralResource"},
+	}
+
+	service.removeRegistration(DocumentKindResource, "azurerm_key_vault", GlobalMappingEntry{RegistrationMethod: "resourceKeyVault"})
+	service.removeRegistration(DocumentKindResource, "azurerm_other", GlobalMappingEntry{StructType: "OtherResource"})
+	service.removeRegistration(DocumentKindEphemeral, "azurerm_key_vault_secret", GlobalMappingEntry{StructType: "KeyVaultSecretEphemeralResource"})
+
+	assert.Empty(t, service.SupportedResources)
+	assert.Equal(t, []string{"KeyVaultResource"}, service.Resources)
+	assert.Empty(t, service.EphemeralFunctions)
+	assert.Empty(t, service.EphemeralTerraformTypes)
+}
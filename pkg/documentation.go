@@ -0,0 +1,113 @@
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// registryDocsBaseURL is the Terraform Registry documentation URL of a provider, formatted with the provider name
+const registryDocsBaseURL = "https://registry.terraform.io/providers/hashicorp/%s/latest/docs"
+
+// docFileSuffixes are the markdown file suffixes used by provider documentation, in lookup order
+var docFileSuffixes = []string{".html.markdown", ".markdown", ".md"}
+
+// DocumentationLink links a resource or data source to its website documentation
+type DocumentationLink struct {
+	DocFile      string `json:"doc_file" protobuf:"1"`      // "website/docs/r/key_vault.html.markdown"
+	RegistrySlug string `json:"registry_slug" protobuf:"2"` // "key_vault"
+	RegistryURL  string `json:"registry_url" protobuf:"3"`  // "https://registry.terraform.io/providers/hashicorp/azurerm/latest/docs/resources/key_vault"
+}
+
+// UndocumentedEntry is a resource or data source without a documentation file
+type UndocumentedEntry struct {
+	ID            string `json:"id"`             // "azurerm/resources/azurerm_key_vault/legacy_pluginsdk", see EntryID
+	Kind          string `json:"kind"`           // "resources" or "datasources"
+	TerraformType string `json:"terraform_type"` // "azurerm_key_vault"
+}
+
+// DocumentationReport lists the resources and data sources without documentation after linking
+type DocumentationReport struct {
+	DocsPath     string              `json:"docs_path"`    // "website/docs"
+	Undocumented []UndocumentedEntry `json:"undocumented"` // Sorted by kind and Terraform type
+}
+
+// LinkDocumentation links resources and data sources to their markdown documentation in the provider's website/docs
+// directory (r/<slug>.html.markdown and d/<slug>.html.markdown) and to their registry page. Missing documentation
+// doesn't fail the run, it is reported in the returned report, which is also written to audit/undocumented.json.
+func (index *TerraformProviderIndex) LinkDocumentation(docsPath string) (*DocumentationReport, error) {
+	if info, err := os.Stat(docsPath); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("docs path %s is not a directory", docsPath)
+	}
+
+	providerName := index.Output.ProviderName
+	if providerName == "" {
+		providerName = DefaultProviderName
+	}
+
+	report := &DocumentationReport{DocsPath: filepath.ToSlash(docsPath), Undocumented: []UndocumentedEntry{}}
+	for i := range index.Services {
+		service := &index.Services[i]
+		service.ResourceDocs = make(map[string]*DocumentationLink)
+		service.DataSourceDocs = make(map[string]*DocumentationLink)
+	}
+
+	for _, document := range index.Documents() {
+		var docsDir, registryCategory string
+		var links func(service *ServiceRegistration) map[string]*DocumentationLink
+		switch document.Kind {
+		case DocumentKindResource:
+			docsDir, registryCategory = "r", "resources"
+			links = func(service *ServiceRegistration) map[string]*DocumentationLink { return service.ResourceDocs }
+		case DocumentKindDataSource:
+			docsDir, registryCategory = "d", "data-sources"
+			links = func(service *ServiceRegistration) map[string]*DocumentationLink { return service.DataSourceDocs }
+		default:
+			continue
+		}
+
+		slug := strings.TrimPrefix(document.TerraformType, providerName+"_")
+		docFile := findDocFile(filepath.Join(docsPath, docsDir), slug)
+		if docFile == "" {
+			report.Undocumented = append(report.Undocumented, UndocumentedEntry{ID: document.ID, Kind: document.Kind, TerraformType: document.TerraformType})
+			continue
+		}
+
+		for i := range index.Services {
+			if index.Services[i].ServiceName == document.Service {
+				links(&index.Services[i])[document.TerraformType] = &DocumentationLink{
+					DocFile:      filepath.ToSlash(docFile),
+					RegistrySlug: slug,
+					RegistryURL:  fmt.Sprintf(registryDocsBaseURL, providerName) + "/" + registryCategory + "/" + slug,
+				}
+			}
+		}
+	}
+
+	sort.Slice(report.Undocumented, func(i, j int) bool {
+		if report.Undocumented[i].Kind != report.Undocumented[j].Kind {
+			return report.Undocumented[i].Kind < report.Undocumented[j].Kind
+		}
+		return report.Undocumented[i].TerraformType < report.Undocumented[j].TerraformType
+	})
+	index.Documentation = report
+	return report, nil
+}
+
+// findDocFile returns the documentation file of a slug in dir, or "" when there is none
+func findDocFile(dir, slug string) string {
+	for _, suffix := range docFileSuffixes {
+		docFile := filepath.Join(dir, slug+suffix)
+		if _, err := os.Stat(docFile); err == nil {
+			return docFile
+		}
+	}
+	return ""
+}
+
+// writeDocumentationReportFile writes audit/undocumented.json
+func (index *TerraformProviderIndex) writeDocumentationReportFile(outputDir string) error {
+	return index.writeJSONFile(filepath.Join(outputDir, "audit", "undocumented.json"), index.Documentation)
+}
@@ -0,0 +1,52 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformProviderIndex_LinkDocumentation(t *testing.T) {
+	docsPath := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(docsPath, "r"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(docsPath, "d"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(docsPath, "r", "key_vault.html.markdown"), []byte("---\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(docsPath, "d", "key_vault.markdown"), []byte("---\n"), 0644))
+
+	index := createTestTerraformProviderIndex()
+
+	report, err := index.LinkDocumentation(docsPath)
+	require.NoError(t, err)
+
+	assert.Contains(t, report.Undocumented, UndocumentedEntry{ID: "azurerm/resources/azurerm_key_vault_certificate/legacy_pluginsdk", Kind: DocumentKindResource, TerraformType: "azurerm_key_vault_certificate"})
+	assert.Contains(t, report.Undocumented, UndocumentedEntry{ID: "azurerm/datasources/azurerm_key_vault_key/legacy_pluginsdk", Kind: DocumentKindDataSource, TerraformType: "azurerm_key_vault_key"})
+	assert.NotContains(t, report.Undocumented, UndocumentedEntry{ID: "azurerm/resources/azurerm_key_vault/legacy_pluginsdk", Kind: DocumentKindResource, TerraformType: "azurerm_key_vault"})
+	for _, entry := range report.Undocumented {
+		assert.NotEqual(t, DocumentKindEphemeral, entry.Kind)
+	}
+	assert.Same(t, report, index.Documentation)
+
+	resource := newTerraformResourceInfo("azurerm_key_vault", "", "resourceKeyVault", "legacy_pluginsdk", index.Services[0])
+	require.NotNil(t, resource.Documentation)
+	assert.Equal(t, filepath.ToSlash(filepath.Join(docsPath, "r", "key_vault.html.markdown")), resource.Documentation.DocFile)
+	assert.Equal(t, "key_vault", resource.Documentation.RegistrySlug)
+	assert.Equal(t, "https://registry.terraform.io/providers/hashicorp/azurerm/latest/docs/resources/key_vault", resource.Documentation.RegistryURL)
+
+	dataSource := newTerraformDataSourceInfo("azurerm_key_vault", "", "dataSourceKeyVault", "legacy_pluginsdk", index.Services[0])
+	require.NotNil(t, dataSource.Documentation)
+	assert.Equal(t, "https://registry.terraform.io/providers/hashicorp/azurerm/latest/docs/data-sources/key_vault", dataSource.Documentation.RegistryURL)
+
+	assert.Nil(t, newTerraformResourceInfo("azurerm_key_vault_certificate", "", "resourceKeyVaultCertificate", "legacy_pluginsdk", index.Services[0]).Documentation)
+}
+
+func TestTerraformProviderIndex_LinkDocumentation_MissingDocsPath(t *testing.T) {
+	index := &TerraformProviderIndex{}
+
+	_, err := index.LinkDocumentation(filepath.Join(t.TempDir(), "missing"))
+
+	assert.Error(t, err)
+	assert.Nil(t, index.Documentation)
+}
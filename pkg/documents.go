@@ -0,0 +1,199 @@
+package pkg
+
+import (
+	"iter"
+	"sort"
+)
+
+// Document kinds, matching the output directory of each document
+const (
+	DocumentKindResource     = "resources"
+	DocumentKindDataSource   = "datasources"
+	DocumentKindEphemeral    = "ephemeral"
+	DocumentKindListResource = "listresources"
+	DocumentKindAction       = "actions"
+)
+
+// IndexDocument is a single resource, data source, ephemeral resource, list resource or action document of the index
+type IndexDocument struct {
+	ID            string      // "azurerm/resources/azurerm_key_vault/legacy_pluginsdk", see EntryID
+	Kind          string      // "resources", "datasources", "ephemeral", "listresources" or "actions"
+	TerraformType string      // "azurerm_key_vault"
+	Service       string      // "keyvault"
+	Content       interface{} // TerraformResource, TerraformDataSource, TerraformEphemeral, TerraformListResource or TerraformAction
+}
+
+// documentKind builds the documents of one kind from a service registration
+type documentKind struct {
+	kind        string // "resources", also the output directory of the documents
+	description string // "resource", names a document in progress and error messages
+	// documents yields the documents of the kind of a service, returning false once yield returned false
+	documents func(service ServiceRegistration, yield func(IndexDocument) bool) bool
+}
+
+// documentKinds are the kinds of per-resource documents of the index, a new kind of document is added here together
+// with the DocumentEmitter writing it
+var documentKinds = []documentKind{
+	{kind: DocumentKindResource, description: "resource", documents: resourceDocuments},
+	{kind: DocumentKindDataSource, description: "data source", documents: dataSourceDocuments},
+	{kind: DocumentKindEphemeral, description: "ephemeral", documents: ephemeralDocuments},
+	{kind: DocumentKindListResource, description: "list resource", documents: listResourceDocuments},
+	{kind: DocumentKindAction, description: "action", documents: actionDocuments},
+}
+
+// findDocumentKind returns the registered document kind of the given name, nil if there is none
+func findDocumentKind(kind string) *documentKind {
+	for i := range documentKinds {
+		if documentKinds[i].kind == kind {
+			return &documentKinds[i]
+		}
+	}
+	return nil
+}
+
+// Documents enumerates the per-resource documents of the index, the same documents written to the resources/,
+// datasources/, ephemeral/, listresources/ and actions/ directories, ordered by kind and Terraform type
+func (index *TerraformProviderIndex) Documents() []IndexDocument {
+	var documents []IndexDocument
+	for _, kind := range documentKinds {
+		for document := range index.DocumentsOfKind(kind.kind) {
+			documents = append(documents, document)
+		}
+	}
+
+	sort.SliceStable(documents, func(i, j int) bool {
+		if documents[i].Kind != documents[j].Kind {
+			return documents[i].Kind < documents[j].Kind
+		}
+		return documents[i].TerraformType < documents[j].TerraformType
+	})
+	return documents
+}
+
+// DocumentsOfKind iterates the documents of a kind service by service, building each document only when it is
+// reached, in no particular order. An unknown kind yields nothing.
+func (index *TerraformProviderIndex) DocumentsOfKind(kind string) iter.Seq[IndexDocument] {
+	return func(yield func(IndexDocument) bool) {
+		documentKind := findDocumentKind(kind)
+		if documentKind == nil {
+			return
+		}
+		discovered := func(document IndexDocument) bool {
+			if !index.Hooks.discover(&document) {
+				return true
+			}
+			return yield(document)
+		}
+		for _, service := range index.Services {
+			if !documentKind.documents(service, discovered) {
+				return
+			}
+		}
+	}
+}
+
+// resourceDocuments yields the legacy and modern resource documents of a service
+func resourceDocuments(service ServiceRegistration, yield func(IndexDocument) bool) bool {
+	for terraformType, registrationMethod := range service.SupportedResources {
+		if !yield(IndexDocument{
+			ID:            EntryID(DocumentKindResource, terraformType, "legacy_pluginsdk"),
+			Kind:          DocumentKindResource,
+			TerraformType: terraformType,
+			Service:       service.ServiceName,
+			Content:       newTerraformResourceInfo(terraformType, "", registrationMethod, "legacy_pluginsdk", service),
+		}) {
+			return false
+		}
+	}
+	for _, structType := range service.Resources {
+		terraformType := service.resourceTerraformType(structType)
+		if !yield(IndexDocument{
+			ID:            EntryID(DocumentKindResource, terraformType, "modern_sdk"),
+			Kind:          DocumentKindResource,
+			TerraformType: terraformType,
+			Service:       service.ServiceName,
+			Content:       newTerraformResourceInfo(terraformType, structType, "", "modern_sdk", service),
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// dataSourceDocuments yields the legacy and modern data source documents of a service
+func dataSourceDocuments(service ServiceRegistration, yield func(IndexDocument) bool) bool {
+	for terraformType, registrationMethod := range service.SupportedDataSources {
+		if !yield(IndexDocument{
+			ID:            EntryID(DocumentKindDataSource, terraformType, "legacy_pluginsdk"),
+			Kind:          DocumentKindDataSource,
+			TerraformType: terraformType,
+			Service:       service.ServiceName,
+			Content:       newTerraformDataSourceInfo(terraformType, "", registrationMethod, "legacy_pluginsdk", service),
+		}) {
+			return false
+		}
+	}
+	for _, structType := range service.DataSources {
+		terraformType := service.dataSourceTerraformType(structType)
+		if !yield(IndexDocument{
+			ID:            EntryID(DocumentKindDataSource, terraformType, "modern_sdk"),
+			Kind:          DocumentKindDataSource,
+			TerraformType: terraformType,
+			Service:       service.ServiceName,
+			Content:       newTerraformDataSourceInfo(terraformType, structType, "", "modern_sdk", service),
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// ephemeralDocuments yields the ephemeral resource documents of a service
+func ephemeralDocuments(service ServiceRegistration, yield func(IndexDocument) bool) bool {
+	for structType, terraformType := range service.EphemeralTerraformTypes {
+		if !yield(IndexDocument{
+			ID:            EntryID(DocumentKindEphemeral, terraformType, "ephemeral"),
+			Kind:          DocumentKindEphemeral,
+			TerraformType: terraformType,
+			Service:       service.ServiceName,
+			Content:       newTerraformEphemeralInfo(structType, service),
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// listResourceDocuments yields the list resource documents of a service
+func listResourceDocuments(service ServiceRegistration, yield func(IndexDocument) bool) bool {
+	for _, structType := range service.ListResources {
+		terraformType := service.listResourceTerraformType(structType)
+		if !yield(IndexDocument{
+			ID:            EntryID(DocumentKindListResource, terraformType, "list_resource"),
+			Kind:          DocumentKindListResource,
+			TerraformType: terraformType,
+			Service:       service.ServiceName,
+			Content:       newTerraformListResourceInfo(structType, service),
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// actionDocuments yields the action documents of a service
+func actionDocuments(service ServiceRegistration, yield func(IndexDocument) bool) bool {
+	for _, structType := range service.Actions {
+		terraformType := service.actionTerraformType(structType)
+		if !yield(IndexDocument{
+			ID:            EntryID(DocumentKindAction, terraformType, "action"),
+			Kind:          DocumentKindAction,
+			TerraformType: terraformType,
+			Service:       service.ServiceName,
+			Content:       newTerraformActionInfo(structType, service),
+		}) {
+			return false
+		}
+	}
+	return true
+}
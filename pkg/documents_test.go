@@ -0,0 +1,43 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformProviderIndex_Documents(t *testing.T) {
+	index := createTestTerraformProviderIndex()
+
+	documents := index.Documents()
+
+	var total int
+	for _, service := range index.Services {
+		total += len(service.SupportedResources) + len(service.Resources) + len(service.SupportedDataSources) +
+			len(service.DataSources) + len(service.EphemeralTerraformTypes)
+	}
+	require.Len(t, documents, total)
+
+	for i := 1; i < len(documents); i++ {
+		previous, current := documents[i-1], documents[i]
+		assert.True(t, previous.Kind < current.Kind || (previous.Kind == current.Kind && previous.TerraformType <= current.TerraformType),
+			"documents must be ordered by kind and terraform type")
+	}
+
+	for _, document := range documents {
+		switch content := document.Content.(type) {
+		case TerraformResource:
+			assert.Equal(t, DocumentKindResource, document.Kind)
+			assert.Equal(t, document.TerraformType, content.TerraformType)
+		case TerraformDataSource:
+			assert.Equal(t, DocumentKindDataSource, document.Kind)
+			assert.Equal(t, document.TerraformType, content.TerraformType)
+		case TerraformEphemeral:
+			assert.Equal(t, DocumentKindEphemeral, document.Kind)
+			assert.Equal(t, document.TerraformType, content.TerraformType)
+		default:
+			t.Fatalf("unexpected document content %T", content)
+		}
+	}
+}
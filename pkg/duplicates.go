@@ -0,0 +1,133 @@
+package pkg
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// DuplicateRegistration is a Terraform type registered more than once, by two services or by two files or
+// registrations of one service. Only the last registration is indexed, the others are silently lost.
+type DuplicateRegistration struct {
+	Kind          string                 `json:"kind"`           // "resources", "datasources", "ephemeral", "listresources" or "actions"
+	TerraformType string                 `json:"terraform_type"` // "azurerm_key_vault"
+	Registrations []RegistrationLocation `json:"registrations"`  // In registration order, the indexed registration last
+}
+
+// RegistrationLocation locates one registration of a duplicated Terraform type
+type RegistrationLocation struct {
+	Service            string `json:"service"`                       // "keyvault"
+	FileName           string `json:"file_name,omitempty"`           // "registration.go", the file of the registration method
+	RegistrationMethod string `json:"registration_method,omitempty"` // "resourceKeyVault" for legacy registrations
+	StructType         string `json:"struct_type,omitempty"`         // "KeyVaultResource" for typed registrations
+}
+
+// BuildDuplicatesReport lists the Terraform types registered more than once: legacy registrations of the same type
+// in different files of a service, found while merging the registrations of each file, and registrations of the same
+// type by different services or by both a legacy and a typed registration.
+func (index *TerraformProviderIndex) BuildDuplicatesReport() []DuplicateRegistration {
+	report := []DuplicateRegistration{}
+	registrations := make(map[string]map[string][]RegistrationLocation)
+	register := func(kind, terraformType string, location RegistrationLocation) {
+		if registrations[kind] == nil {
+			registrations[kind] = make(map[string][]RegistrationLocation)
+		}
+		registrations[kind][terraformType] = append(registrations[kind][terraformType], location)
+	}
+
+	for _, service := range index.Services {
+		report = append(report, service.DuplicateRegistrations...)
+		for _, terraformType := range sortedKeys(service.SupportedResources) {
+			register(DocumentKindResource, terraformType, service.legacyRegistrationLocation(service.SupportedResources[terraformType]))
+		}
+		for _, structType := range service.Resources {
+			register(DocumentKindResource, service.resourceTerraformType(structType), service.typedRegistrationLocation(structType))
+		}
+		for _, terraformType := range sortedKeys(service.SupportedDataSources) {
+			register(DocumentKindDataSource, terraformType, service.legacyRegistrationLocation(service.SupportedDataSources[terraformType]))
+		}
+		for _, structType := range service.DataSources {
+			register(DocumentKindDataSource, service.dataSourceTerraformType(structType), service.typedRegistrationLocation(structType))
+		}
+		for _, structType := range sortedKeys(service.EphemeralTerraformTypes) {
+			location := service.typedRegistrationLocation(structType)
+			if constructor := service.EphemeralConstructors[structType]; constructor != "" {
+				location.FileName = service.RegistrationFiles[constructor]
+			}
+			register(DocumentKindEphemeral, service.EphemeralTerraformTypes[structType], location)
+		}
+		for _, structType := range service.ListResources {
+			register(DocumentKindListResource, service.listResourceTerraformType(structType), service.typedRegistrationLocation(structType))
+		}
+		for _, structType := range service.Actions {
+			register(DocumentKindAction, service.actionTerraformType(structType), service.typedRegistrationLocation(structType))
+		}
+	}
+
+	for kind, locations := range registrations {
+		for terraformType, registered := range locations {
+			if len(registered) > 1 {
+				report = append(report, DuplicateRegistration{Kind: kind, TerraformType: terraformType, Registrations: registered})
+			}
+		}
+	}
+	sortDuplicateRegistrations(report)
+	return report
+}
+
+// sortDuplicateRegistrations orders duplicates by kind and Terraform type, keeping duplicates between files of a
+// service before the duplicates of the merged registrations
+func sortDuplicateRegistrations(duplicates []DuplicateRegistration) {
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		if duplicates[i].Kind != duplicates[j].Kind {
+			return duplicates[i].Kind < duplicates[j].Kind
+		}
+		return duplicates[i].TerraformType < duplicates[j].TerraformType
+	})
+}
+
+// writeDuplicatesFile writes audit/duplicates.json
+func (index *TerraformProviderIndex) writeDuplicatesFile(outputDir string) error {
+	return index.writeJSONFile(filepath.Join(outputDir, "audit", "duplicates.json"), index.BuildDuplicatesReport())
+}
+
+// mergeLegacyRegistrations merges the legacy registrations of a file into the registrations of the service, recording
+// the Terraform types another file already registers as duplicates before the later file wins
+func (s *ServiceRegistration) mergeLegacyRegistrations(kind string, registered, mappings map[string]string, fileName string) map[string]string {
+	for _, terraformType := range sortedKeys(mappings) {
+		previous, exists := registered[terraformType]
+		if !exists {
+			continue
+		}
+		s.DuplicateRegistrations = append(s.DuplicateRegistrations, DuplicateRegistration{
+			Kind:          kind,
+			TerraformType: terraformType,
+			Registrations: []RegistrationLocation{
+				s.legacyRegistrationLocation(previous),
+				{Service: s.ServiceName, FileName: fileName, RegistrationMethod: mappings[terraformType]},
+			},
+		})
+	}
+	for _, registrationMethod := range mappings {
+		s.recordRegistrationFile(registrationMethod, fileName)
+	}
+	return mergeMap(registered, mappings)
+}
+
+// recordRegistrationFile records the file registering a legacy registration function or a typed struct or
+// constructor, the first file wins like it does for typed registrations listed twice
+func (s *ServiceRegistration) recordRegistrationFile(registration, fileName string) {
+	if s.RegistrationFiles == nil {
+		s.RegistrationFiles = make(map[string]string)
+	}
+	if _, exists := s.RegistrationFiles[registration]; !exists {
+		s.RegistrationFiles[registration] = fileName
+	}
+}
+
+func (s ServiceRegistration) legacyRegistrationLocation(registrationMethod string) RegistrationLocation {
+	return RegistrationLocation{Service: s.ServiceName, FileName: s.RegistrationFiles[registrationMethod], RegistrationMethod: registrationMethod}
+}
+
+func (s ServiceRegistration) typedRegistrationLocation(structType string) RegistrationLocation {
+	return RegistrationLocation{Service: s.ServiceName, FileName: s.RegistrationFiles[structType], StructType: structType}
+}
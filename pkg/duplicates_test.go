@@ -0,0 +1,99 @@
+package pkg
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceRegistration_MergeLegacyRegistrations(t *testing.T) {
+	serviceReg := ServiceRegistration{ServiceName: "keyvault"}
+
+	registered := serviceReg.mergeLegacyRegistrations(DocumentKindResource, nil, map[string]string{"azurerm_key_vault": "resourceKeyVault"}, "registration.go")
+	registered = serviceReg.mergeLegacyRegistrations(DocumentKindResource, registered, map[string]string{
+		"azurerm_key_vault":        "resourceKeyVaultV2",
+		"azurerm_key_vault_secret": "resourceKeyVaultSecret",
+	}, "registration_v2.go")
+
+	assert.Equal(t, map[string]string{"azurerm_key_vault": "resourceKeyVaultV2", "azurerm_key_vault_secret": "resourceKeyVaultSecret"}, registered)
+	assert.Equal(t, []DuplicateRegistration{{
+		Kind:          DocumentKindResource,
+		TerraformType: "azurerm_key_vault",
+		Registrations: []RegistrationLocation{
+			{Service: "keyvault", FileName: "registration.go", RegistrationMethod: "resourceKeyVault"},
+			{Service: "keyvault", FileName: "registration_v2.go", RegistrationMethod: "resourceKeyVaultV2"},
+		},
+	}}, serviceReg.DuplicateRegistrations)
+}
+
+func duplicatesTestIndex() *TerraformProviderIndex {
+	return &TerraformProviderIndex{Services: []ServiceRegistration{
+		{
+			ServiceName:          "keyvault",
+			SupportedResources:   map[string]string{"azurerm_key_vault": "resourceKeyVault"},
+			SupportedDataSources: map[string]string{"azurerm_key_vault": "dataSourceKeyVault"},
+			RegistrationFiles:    map[string]string{"resourceKeyVault": "registration.go", "dataSourceKeyVault": "registration.go"},
+			DuplicateRegistrations: []DuplicateRegistration{{
+				Kind:          DocumentKindResource,
+				TerraformType: "azurerm_key_vault_secret",
+				Registrations: []RegistrationLocation{
+					{Service: "keyvault", FileName: "registration.go", RegistrationMethod: "resourceKeyVaultSecret"},
+					{Service: "keyvault", FileName: "registration_v2.go", RegistrationMethod: "resourceKeyVaultSecretV2"},
+				},
+			}},
+		},
+		{
+			ServiceName:            "managedhsm",
+			Resources:              []string{"KeyVaultResource", "ManagedHSMResource"},
+			ResourceTerraformTypes: map[string]string{"KeyVaultResource": "azurerm_key_vault", "ManagedHSMResource": "azurerm_key_vault_managed_hardware_security_module"},
+			RegistrationFiles:      map[string]string{"KeyVaultResource": "registration.go", "ManagedHSMResource": "registration.go"},
+		},
+	}}
+}
+
+func TestBuildDuplicatesReport(t *testing.T) {
+	report := duplicatesTestIndex().BuildDuplicatesReport()
+
+	assert.Equal(t, []DuplicateRegistration{
+		{
+			Kind:          DocumentKindResource,
+			TerraformType: "azurerm_key_vault",
+			Registrations: []RegistrationLocation{
+				{Service: "keyvault", FileName: "registration.go", RegistrationMethod: "resourceKeyVault"},
+				{Service: "managedhsm", FileName: "registration.go", StructType: "KeyVaultResource"},
+			},
+		},
+		{
+			Kind:          DocumentKindResource,
+			TerraformType: "azurerm_key_vault_secret",
+			Registrations: []RegistrationLocation{
+				{Service: "keyvault", FileName: "registration.go", RegistrationMethod: "resourceKeyVaultSecret"},
+				{Service: "keyvault", FileName: "registration_v2.go", RegistrationMethod: "resourceKeyVaultSecretV2"},
+			},
+		},
+	}, report)
+}
+
+func TestWriteDuplicatesFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	outputDir := "/test/output"
+	index := duplicatesTestIndex()
+	index.Output.Fs = fs
+	require.NoError(t, index.writeDuplicatesFile(outputDir))
+
+	data, err := afero.ReadFile(fs, filepath.Join(outputDir, "audit", "duplicates.json"))
+	require.NoError(t, err)
+	var duplicates []DuplicateRegistration
+	require.NoError(t, json.Unmarshal(data, &duplicates))
+	assert.Len(t, duplicates, 2)
+
+	empty := &TerraformProviderIndex{Output: OutputConfig{Fs: fs}}
+	require.NoError(t, empty.writeDuplicatesFile(outputDir))
+	data, err = afero.ReadFile(fs, filepath.Join(outputDir, "audit", "duplicates.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `[]`, string(data))
+}
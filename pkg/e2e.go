@@ -0,0 +1,111 @@
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// E2EExpectation describes well-known registrations that must be discovered in a real provider checkout
+type E2EExpectation struct {
+	Service     string   // "resource"
+	Resources   []string // ["azurerm_resource_group"]
+	DataSources []string // ["azurerm_resource_group"]
+}
+
+// E2ECheck is the outcome of a single expectation
+type E2ECheck struct {
+	Service       string `json:"service"`
+	Kind          string `json:"kind"` // "resource" or "data_source"
+	TerraformType string `json:"terraform_type"`
+	Found         bool   `json:"found"`
+}
+
+// E2EResult contains all checks performed by an e2e run
+type E2EResult struct {
+	Checks []E2ECheck `json:"checks"`
+}
+
+// DefaultE2EExpectations is the whitelist of services and well-known types verified by the e2e command
+var DefaultE2EExpectations = []E2EExpectation{
+	{
+		Service:     "resource",
+		Resources:   []string{"azurerm_resource_group"},
+		DataSources: []string{"azurerm_resource_group"},
+	},
+	{
+		Service:     "keyvault",
+		Resources:   []string{"azurerm_key_vault"},
+		DataSources: []string{"azurerm_key_vault"},
+	},
+}
+
+// Failed returns the checks whose Terraform type could not be found
+func (r *E2EResult) Failed() []E2ECheck {
+	var failed []E2ECheck
+	for _, check := range r.Checks {
+		if !check.Found {
+			failed = append(failed, check)
+		}
+	}
+	return failed
+}
+
+// RunE2E scans the whitelisted services of a provider checkout and verifies the expected registrations are extracted.
+// It acts as a smoke test that upstream refactors of the provider haven't broken extraction.
+// The provider services are expected under <providerPath>/internal/services.
+func RunE2E(providerPath, basePkgUrl string, expectations []E2EExpectation, progressCallback ProgressCallback) (*E2EResult, error) {
+	// gophon loads packages relative to the working directory, so the scan must run from the checkout root
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	if err := os.Chdir(providerPath); err != nil {
+		return nil, fmt.Errorf("failed to enter provider checkout %s: %w", providerPath, err)
+	}
+	defer func() {
+		_ = os.Chdir(wd)
+	}()
+
+	services := make(map[string]bool)
+	for _, expectation := range expectations {
+		services[expectation.Service] = true
+	}
+
+	index, err := scanTerraformProviderServices(filepath.Join("internal", "services"), basePkgUrl, "e2e", func(serviceName string) bool {
+		return services[serviceName]
+	}, Scanner{}, progressCallback)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan provider checkout: %w", err)
+	}
+
+	result := &E2EResult{}
+	for _, expectation := range expectations {
+		var service *ServiceRegistration
+		for i := range index.Services {
+			if index.Services[i].ServiceName == expectation.Service {
+				service = &index.Services[i]
+				break
+			}
+		}
+
+		for _, terraformType := range expectation.Resources {
+			result.Checks = append(result.Checks, E2ECheck{
+				Service:       expectation.Service,
+				Kind:          "resource",
+				TerraformType: terraformType,
+				Found:         service != nil && service.hasResource(terraformType),
+			})
+		}
+		for _, terraformType := range expectation.DataSources {
+			result.Checks = append(result.Checks, E2ECheck{
+				Service:       expectation.Service,
+				Kind:          "data_source",
+				TerraformType: terraformType,
+				Found:         service != nil && service.hasDataSource(terraformType),
+			})
+		}
+	}
+
+	return result, nil
+}
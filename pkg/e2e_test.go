@@ -0,0 +1,52 @@
+package pkg
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunE2E_TestHarness(t *testing.T) {
+	// The test harness mirrors the provider layout, so the default whitelist must pass against it
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	result, err := RunE2E("testharness", "github.com/lonegunmanb/terraform-provider-azurerm-index/testharness", DefaultE2EExpectations, nil)
+	require.NoError(t, err)
+
+	// The working directory must be restored after the run
+	current, err := os.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, wd, current)
+
+	assert.Len(t, result.Checks, 4)
+	assert.Empty(t, result.Failed())
+}
+
+func TestRunE2E_MissingRegistration(t *testing.T) {
+	expectations := []E2EExpectation{
+		{
+			Service:   "keyvault",
+			Resources: []string{"azurerm_key_vault", "azurerm_key_vault_managed_hardware_security_module"},
+		},
+		{
+			Service:     "notexist",
+			DataSources: []string{"azurerm_not_exist"},
+		},
+	}
+
+	result, err := RunE2E("testharness", "github.com/lonegunmanb/terraform-provider-azurerm-index/testharness", expectations, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []E2ECheck{
+		{Service: "keyvault", Kind: "resource", TerraformType: "azurerm_key_vault_managed_hardware_security_module", Found: false},
+		{Service: "notexist", Kind: "data_source", TerraformType: "azurerm_not_exist", Found: false},
+	}, result.Failed())
+}
+
+func TestRunE2E_InvalidProviderPath(t *testing.T) {
+	_, err := RunE2E("does-not-exist", "github.com/hashicorp/terraform-provider-azurerm", DefaultE2EExpectations, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,26 @@
+package pkg
+
+import "strings"
+
+// EntryID returns the stable ID of an index entry, "<provider>/<kind>/<terraform type>/<sdk type>", for example
+// "azurerm/resources/azurerm_key_vault/legacy_pluginsdk". The provider is the prefix of the Terraform type, and the kind
+// keeps a resource and a data source of the same type apart. IDs only depend on the registration, not on the output
+// format, so databases, annotations and vector stores can reference entries across format changes.
+func EntryID(kind, terraformType, sdkType string) string {
+	provider, _, found := strings.Cut(terraformType, "_")
+	if !found {
+		// Unresolved typed registrations are keyed by their struct type, which has no provider prefix
+		provider = DefaultProviderName
+	}
+	return strings.Join([]string{provider, kind, terraformType, sdkType}, "/")
+}
+
+// DocumentByID returns the resource, data source or ephemeral resource document with the given EntryID
+func (index *TerraformProviderIndex) DocumentByID(id string) (IndexDocument, bool) {
+	for _, document := range index.Documents() {
+		if document.ID == id {
+			return document, true
+		}
+	}
+	return IndexDocument{}, false
+}
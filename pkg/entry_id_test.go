@@ -0,0 +1,37 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntryID(t *testing.T) {
+	assert.Equal(t, "azurerm/resources/azurerm_key_vault/legacy_pluginsdk", EntryID(DocumentKindResource, "azurerm_key_vault", "legacy_pluginsdk"))
+	assert.Equal(t, "azuread/datasources/azuread_group/modern_sdk", EntryID(DocumentKindDataSource, "azuread_group", "modern_sdk"))
+	// Unresolved typed registrations fall back to the default provider
+	assert.Equal(t, "azurerm/resources/KeyVaultResource/modern_sdk", EntryID(DocumentKindResource, "KeyVaultResource", "modern_sdk"))
+}
+
+func TestTerraformProviderIndex_DocumentByID(t *testing.T) {
+	index := createTestTerraformProviderIndex()
+
+	for _, document := range index.Documents() {
+		found, exists := index.DocumentByID(document.ID)
+		require.True(t, exists, document.ID)
+		assert.Equal(t, document.Kind, found.Kind)
+		assert.Equal(t, document.TerraformType, found.TerraformType)
+	}
+
+	// A resource and a data source of the same type have distinct IDs
+	resource, exists := index.DocumentByID("azurerm/resources/azurerm_key_vault/legacy_pluginsdk")
+	require.True(t, exists)
+	assert.Equal(t, "azurerm/resources/azurerm_key_vault/legacy_pluginsdk", resource.Content.(TerraformResource).ID)
+	dataSource, exists := index.DocumentByID("azurerm/datasources/azurerm_key_vault/legacy_pluginsdk")
+	require.True(t, exists)
+	assert.Equal(t, "azurerm/datasources/azurerm_key_vault/legacy_pluginsdk", dataSource.Content.(TerraformDataSource).ID)
+
+	_, exists = index.DocumentByID("azurerm/resources/azurerm_missing/legacy_pluginsdk")
+	assert.False(t, exists)
+}
@@ -0,0 +1,200 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// ESBulkFileName is the file the esbulk output format writes, loadable with a single _bulk request
+const ESBulkFileName = "bulk.ndjson"
+
+// SearchDocument is the shape of an index document for full-text search over types, attributes and symbols
+type SearchDocument struct {
+	ID                string   `json:"id"`                            // "azurerm/resources/azurerm_key_vault/legacy_pluginsdk", see EntryID
+	Kind              string   `json:"kind"`                          // "resources", "datasources" or "ephemeral"
+	TerraformType     string   `json:"terraform_type"`                // "azurerm_key_vault"
+	Service           string   `json:"service"`                       // "keyvault"
+	Version           string   `json:"version"`                       // "v4.20.0"
+	SDKType           string   `json:"sdk_type"`                      // "legacy_pluginsdk", "modern_sdk" or "ephemeral"
+	Namespace         string   `json:"namespace"`                     // "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault"
+	StructType        string   `json:"struct_type,omitempty"`         // "KeyVaultResource"
+	AzureResourceType string   `json:"azure_resource_type,omitempty"` // "Microsoft.KeyVault/vaults"
+	Attributes        []string `json:"attributes,omitempty"`          // ["name", "location", "sku_name"]
+	Symbols           []string `json:"symbols,omitempty"`             // ["resourceKeyVault", "resourceKeyVaultCreate", "validate.VaultName"]
+	SDKPackages       []string `json:"sdk_packages,omitempty"`        // ["github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2023-07-01/vaults"]
+	Deprecated        bool     `json:"deprecated,omitempty"`          // true
+	DocumentationURL  string   `json:"documentation_url,omitempty"`   // "https://registry.terraform.io/providers/hashicorp/azurerm/latest/docs/resources/key_vault"
+}
+
+// esBulkAction is the action line preceding each document of a bulk request
+type esBulkAction struct {
+	Index esBulkActionMetadata `json:"index"`
+}
+
+type esBulkActionMetadata struct {
+	Index string `json:"_index"` // "terraform-provider-azurerm"
+	ID    string `json:"_id"`    // "v4.20.0/resources/azurerm_key_vault"
+}
+
+// ESIndexName returns the search index the bulk actions target, terraform-provider-<name>. Documents of several
+// provider versions share the index, their ids are prefixed with the version.
+func (c OutputConfig) ESIndexName() string {
+	providerName := c.ProviderName
+	if providerName == "" {
+		providerName = DefaultProviderName
+	}
+	return fmt.Sprintf("terraform-provider-%s", strings.ToLower(providerName))
+}
+
+// SearchDocuments converts the documents of the index into search documents
+func (index *TerraformProviderIndex) SearchDocuments() []SearchDocument {
+	documents := index.Documents()
+	searchDocuments := make([]SearchDocument, 0, len(documents))
+	for _, document := range documents {
+		searchDocuments = append(searchDocuments, newSearchDocument(document, index.Version))
+	}
+	return searchDocuments
+}
+
+func newSearchDocument(document IndexDocument, version string) SearchDocument {
+	result := SearchDocument{
+		ID:            document.ID,
+		Kind:          document.Kind,
+		TerraformType: document.TerraformType,
+		Service:       document.Service,
+		Version:       version,
+	}
+
+	var symbols []string
+	switch content := document.Content.(type) {
+	case TerraformResource:
+		result.SDKType = content.SDKType
+		result.Namespace = content.Namespace
+		result.StructType = content.StructType
+		result.AzureResourceType = content.AzureResourceType
+		result.SDKPackages = content.SDKPackages
+		result.Deprecated = content.Deprecated
+		if content.Documentation != nil {
+			result.DocumentationURL = content.Documentation.RegistryURL
+		}
+		for _, attribute := range content.Schema {
+			result.Attributes = append(result.Attributes, attribute.Name)
+			if attribute.SchemaFunc != "" {
+				symbols = append(symbols, attribute.SchemaFunc)
+			}
+			symbols = append(symbols, attribute.ValidateFuncs...)
+		}
+		symbols = append(symbols, indexSymbols(content.SchemaIndex, content.CreateIndex, content.ReadIndex, content.UpdateIndex, content.DeleteIndex)...)
+		symbols = append(symbols, content.StateUpgraders...)
+		symbols = append(symbols, content.CustomizeDiff...)
+		if content.IDParser != "" {
+			symbols = append(symbols, content.IDParser)
+		}
+	case TerraformDataSource:
+		result.SDKType = content.SDKType
+		result.Namespace = content.Namespace
+		result.StructType = content.StructType
+		result.Deprecated = content.Deprecated
+		if content.Documentation != nil {
+			result.DocumentationURL = content.Documentation.RegistryURL
+		}
+		symbols = indexSymbols(content.SchemaIndex, content.ReadIndex)
+	case TerraformEphemeral:
+		result.SDKType = content.SDKType
+		result.Namespace = content.Namespace
+		result.StructType = content.StructType
+		symbols = indexSymbols(content.SchemaIndex, content.OpenIndex, content.RenewIndex, content.CloseIndex)
+	case TerraformListResource:
+		result.SDKType = content.SDKType
+		result.Namespace = content.Namespace
+		result.StructType = content.StructType
+		symbols = indexSymbols(content.SchemaIndex, content.ListIndex)
+	case TerraformAction:
+		result.SDKType = content.SDKType
+		result.Namespace = content.Namespace
+		result.StructType = content.StructType
+		symbols = indexSymbols(content.SchemaIndex, content.InvokeIndex)
+	}
+	result.Symbols = uniqueSortedStrings(symbols)
+	return result
+}
+
+// indexSymbols converts gophon index file names such as "func.resourceKeyVault.goindex" and
+// "method.KeyVaultResource.Create.goindex" into the symbols they index, "resourceKeyVault" and "KeyVaultResource.Create"
+func indexSymbols(indexFiles ...string) []string {
+	var symbols []string
+	for _, indexFile := range indexFiles {
+		symbol := strings.TrimSuffix(indexFile, ".goindex")
+		if symbol == indexFile {
+			continue
+		}
+		for _, prefix := range []string{"func.", "method."} {
+			symbol = strings.TrimPrefix(symbol, prefix)
+		}
+		// Legacy resources without the CRUD function leave it empty, e.g. "func..goindex"
+		if symbol != "" {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols
+}
+
+// uniqueSortedStrings returns the distinct values sorted, nil for no values
+func uniqueSortedStrings(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var result []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// writeESBulkFile writes the search documents of the index as newline delimited bulk index actions, ready for
+// curl -H 'Content-Type: application/x-ndjson' -XPOST <cluster>/_bulk --data-binary @bulk.ndjson
+func (index *TerraformProviderIndex) writeESBulkFile(outputDir string, progressCallback ProgressCallback) error {
+	documents := index.SearchDocuments()
+	progressTracker := NewProgressTracker("exporting", len(documents), progressCallback)
+
+	if err := index.Output.fs().MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", outputDir, err)
+	}
+
+	indexName := index.Output.ESIndexName()
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, document := range documents {
+		action := esBulkAction{Index: esBulkActionMetadata{
+			Index: indexName,
+			ID:    fmt.Sprintf("%s/%s/%s", document.Version, document.Kind, document.TerraformType),
+		}}
+		// Encode terminates each line with the newline the bulk API requires, including the last one
+		if err := encoder.Encode(action); err != nil {
+			return fmt.Errorf("failed to encode bulk action for %s %s: %w", document.Kind, document.TerraformType, err)
+		}
+		if err := encoder.Encode(document); err != nil {
+			return fmt.Errorf("failed to encode search document for %s %s: %w", document.Kind, document.TerraformType, err)
+		}
+		progressTracker.UpdateProgress(fmt.Sprintf("%s %s", document.Kind, document.TerraformType))
+	}
+
+	filePath := filepath.Join(outputDir, ESBulkFileName)
+	if err := afero.WriteFile(index.Output.fs(), filePath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", filePath, err)
+	}
+
+	progressTracker.Complete()
+	return nil
+}
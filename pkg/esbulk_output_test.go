@@ -0,0 +1,68 @@
+package pkg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexSymbols(t *testing.T) {
+	assert.Equal(t, []string{"resourceKeyVault", "KeyVaultResource.Create"},
+		indexSymbols("func.resourceKeyVault.goindex", "method.KeyVaultResource.Create.goindex", "func..goindex", ""))
+}
+
+func TestTerraformProviderIndex_WriteIndexFiles_ESBulkFormat(t *testing.T) {
+	index := createTestTerraformProviderIndex()
+	index.Output = OutputConfig{Format: OutputFormatESBulk}
+	index.Services[0].ResourceSchemas = map[string][]SchemaAttribute{
+		"azurerm_key_vault": {{Name: "name", Type: "TypeString", ValidateFuncs: []string{"validate.VaultName"}}},
+	}
+	fs := afero.NewMemMapFs()
+	index.Output.Fs = fs
+	outputDir := "/test/output"
+
+	require.NoError(t, index.WriteIndexFiles(outputDir, nil))
+
+	content, err := afero.ReadFile(fs, filepath.Join(outputDir, ESBulkFileName))
+	require.NoError(t, err)
+	assert.True(t, bytes.HasSuffix(content, []byte("\n")))
+
+	var lines [][]byte
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		lines = append(lines, append([]byte(nil), scanner.Bytes()...))
+	}
+	require.Len(t, lines, 2*len(index.Documents()))
+
+	documents := make(map[string]SearchDocument)
+	for i := 0; i < len(lines); i += 2 {
+		var action esBulkAction
+		require.NoError(t, json.Unmarshal(lines[i], &action))
+		var document SearchDocument
+		require.NoError(t, json.Unmarshal(lines[i+1], &document))
+
+		assert.Equal(t, "terraform-provider-azurerm", action.Index.Index)
+		assert.Equal(t, "v3.0.0/"+document.Kind+"/"+document.TerraformType, action.Index.ID)
+		documents[action.Index.ID] = document
+	}
+
+	keyVault := documents["v3.0.0/resources/azurerm_key_vault"]
+	assert.Equal(t, "keyvault", keyVault.Service)
+	assert.Equal(t, "legacy_pluginsdk", keyVault.SDKType)
+	assert.Equal(t, []string{"name"}, keyVault.Attributes)
+	assert.Equal(t, []string{"keyVaultCreateFunc", "keyVaultDeleteFunc", "keyVaultReadFunc", "keyVaultUpdateFunc", "resourceKeyVault", "validate.VaultName"}, keyVault.Symbols)
+
+	modern := documents["v3.0.0/resources/azurerm_key_vault_modern"]
+	assert.Equal(t, "KeyVaultResource", modern.StructType)
+	assert.Contains(t, modern.Symbols, "KeyVaultResource.Create")
+
+	exists, err := afero.Exists(fs, filepath.Join(outputDir, index.Output.MainIndexFileName()))
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
@@ -0,0 +1,56 @@
+package pkg
+
+import (
+	"fmt"
+	"sync"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// Extractor is an additional analysis of every scanned service, run after the built-in extractions. Extractors record
+// their findings on the service registration, usually under ServiceRegistration.Extensions[Name()], which is written
+// to the main index.
+type Extractor interface {
+	// Name names the extractor in scan warnings and keys its findings in ServiceRegistration.Extensions, "cost_tiers"
+	Name() string
+	// Extract analyses the package of a service, a returned error is reported as a scan warning of the service
+	Extract(packageInfo *gophon.PackageInfo, service *ServiceRegistration) error
+}
+
+var (
+	extractorsMu sync.RWMutex
+	extractors   []Extractor
+)
+
+// RegisterExtractor registers an extractor run by every scan, usually from the init function of the package declaring
+// it. It panics when an extractor of the same name is already registered.
+func RegisterExtractor(extractor Extractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	for _, registered := range extractors {
+		if registered.Name() == extractor.Name() {
+			panic(fmt.Sprintf("extractor %s is already registered", extractor.Name()))
+		}
+	}
+	extractors = append(extractors, extractor)
+}
+
+// RegisteredExtractors returns the registered extractors, in registration order
+func RegisteredExtractors() []Extractor {
+	extractorsMu.RLock()
+	defer extractorsMu.RUnlock()
+	return append([]Extractor(nil), extractors...)
+}
+
+// runExtractors runs the extractors on a scanned service in order. An extractor returning an error or panicking only
+// loses its own findings.
+func runExtractors(serviceReg *ServiceRegistration, packageInfo *gophon.PackageInfo, extractors []Extractor, guard extractionGuard) {
+	for _, extractor := range extractors {
+		name := extractor.Name()
+		guard.run("", "extractor "+name, func() {
+			if err := extractor.Extract(packageInfo, serviceReg); err != nil {
+				guard.warn(ScanWarningExtractorError, "", fmt.Sprintf("extractor %s failed: %v", name, err))
+			}
+		})
+	}
+}
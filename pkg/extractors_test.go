@@ -0,0 +1,74 @@
+package pkg
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+	"github.com/prashantv/gostub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testExtractor is an Extractor calling extract
+type testExtractor struct {
+	name    string
+	extract func(packageInfo *gophon.PackageInfo, service *ServiceRegistration) error
+}
+
+func (e testExtractor) Name() string { return e.name }
+
+func (e testExtractor) Extract(packageInfo *gophon.PackageInfo, service *ServiceRegistration) error {
+	return e.extract(packageInfo, service)
+}
+
+func TestRegisterExtractor(t *testing.T) {
+	stub := gostub.Stub(&extractors, []Extractor(nil))
+	defer stub.Reset()
+
+	first := testExtractor{name: "first"}
+	second := testExtractor{name: "second"}
+	RegisterExtractor(first)
+	RegisterExtractor(second)
+
+	assert.Equal(t, []Extractor{first, second}, RegisteredExtractors())
+	assert.PanicsWithValue(t, "extractor first is already registered", func() {
+		RegisterExtractor(testExtractor{name: "first"})
+	})
+}
+
+func TestScanner_Extractors(t *testing.T) {
+	stub := gostub.Stub(&extractors, []Extractor(nil))
+	defer stub.Reset()
+
+	RegisterExtractor(testExtractor{name: "file_count", extract: func(packageInfo *gophon.PackageInfo, service *ServiceRegistration) error {
+		service.Extensions["file_count"] = len(packageInfo.Files)
+		return nil
+	}})
+	var order []string
+	scanner := Scanner{Services: []string{"keyvault"}, Extractors: []Extractor{
+		testExtractor{name: "order", extract: func(_ *gophon.PackageInfo, service *ServiceRegistration) error {
+			order = append(order, service.ServiceName)
+			service.Extensions["registered_first"] = service.Extensions["file_count"] != nil
+			return nil
+		}},
+		testExtractor{name: "failing", extract: func(*gophon.PackageInfo, *ServiceRegistration) error {
+			return errors.New("no cost tiers")
+		}},
+		testExtractor{name: "panicking", extract: func(*gophon.PackageInfo, *ServiceRegistration) error {
+			panic("unexpected source")
+		}},
+	}}
+
+	index, err := scanner.Scan(filepath.Join("testharness", "internal", "services"), "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+	require.NoError(t, err)
+	require.Len(t, index.Services, 1)
+
+	service := index.Services[0]
+	assert.Equal(t, []string{"keyvault"}, order)
+	assert.Positive(t, service.Extensions["file_count"])
+	assert.Equal(t, true, service.Extensions["registered_first"])
+	assert.Contains(t, index.Warnings, ScanWarning{Service: "keyvault", Kind: ScanWarningExtractorError, Message: "extractor failing failed: no cost tiers"})
+	assert.Contains(t, index.Warnings, ScanWarning{Service: "keyvault", Kind: ScanWarningPanic, Message: "panic during extractor panicking extraction: unexpected source"})
+}
@@ -0,0 +1,209 @@
+package pkg
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// featuresPackage is the package of the provider's feature flags, such as features.FivePointOh()
+const featuresPackage = "features"
+
+// extractFeatureFlags extracts the feature flags guarding the registrations of a scanned service, keyed by Terraform
+// type like the other per-resource extraction results. Registrations the registration methods only make inside
+// if features.FivePointOh() { ... } are gated by "features.FivePointOh", inside if !features.FivePointOh() { ... } or
+// its else branch by "!features.FivePointOh", so the surface of either major version can be computed from one scan.
+func extractFeatureFlags(serviceReg *ServiceRegistration, packageInfo *gophon.PackageInfo) {
+	ephemeralStructs := make(map[string]string, len(serviceReg.EphemeralConstructors))
+	for structType, function := range serviceReg.EphemeralConstructors {
+		ephemeralStructs[function] = structType
+	}
+
+	for _, fileInfo := range packageInfo.Files {
+		if fileInfo.File == nil {
+			continue
+		}
+		for terraformType, flag := range extractFeatureFlagGuards(fileInfo.File, "SupportedResources", mapLiteralKeys) {
+			serviceReg.ResourceFeatureFlags[terraformType] = flag
+		}
+		for terraformType, flag := range extractFeatureFlagGuards(fileInfo.File, "SupportedDataSources", mapLiteralKeys) {
+			serviceReg.DataSourceFeatureFlags[terraformType] = flag
+		}
+		for structType, flag := range extractFeatureFlagGuards(fileInfo.File, "Resources", compositeLiteralTypes) {
+			serviceReg.ResourceFeatureFlags[serviceReg.resourceTerraformType(structType)] = flag
+		}
+		for structType, flag := range extractFeatureFlagGuards(fileInfo.File, "DataSources", compositeLiteralTypes) {
+			serviceReg.DataSourceFeatureFlags[serviceReg.dataSourceTerraformType(structType)] = flag
+		}
+		for function, flag := range extractFeatureFlagGuards(fileInfo.File, "EphemeralResources", identifierNames) {
+			if structType, ok := ephemeralStructs[function]; ok {
+				serviceReg.EphemeralFeatureFlags[ephemeralTerraformType(*serviceReg, structType)] = flag
+			}
+		}
+	}
+}
+
+// ephemeralTerraformType returns the Terraform type of an ephemeral resource, its struct type when unresolved
+func ephemeralTerraformType(serviceReg ServiceRegistration, structType string) string {
+	if terraformType, ok := serviceReg.EphemeralTerraformTypes[structType]; ok {
+		return terraformType
+	}
+	return structType
+}
+
+// extractFeatureFlagGuards walks the statements of the registration method methodName and returns the feature flag
+// guarding the registrations collect finds in them. Registrations also made outside of a feature flag condition
+// aren't gated.
+func extractFeatureFlagGuards(node *ast.File, methodName string, collect func(ast.Node) []string) map[string]string {
+	guards := make(map[string]string)
+	unconditional := make(map[string]bool)
+
+	var walk func(stmts []ast.Stmt, flag string)
+	walkStmt := func(stmt ast.Stmt, flag string) {
+		switch s := stmt.(type) {
+		case *ast.BlockStmt:
+			walk(s.List, flag)
+		case *ast.IfStmt:
+			bodyFlag, elseFlag := flag, flag
+			if condition := featureFlagCondition(s.Cond); condition != "" {
+				bodyFlag, elseFlag = condition, negateFeatureFlag(condition)
+			}
+			walk(s.Body.List, bodyFlag)
+			if s.Else != nil {
+				walk([]ast.Stmt{s.Else}, elseFlag)
+			}
+		case *ast.ForStmt:
+			walk(s.Body.List, flag)
+		case *ast.RangeStmt:
+			walk(s.Body.List, flag)
+		case *ast.SwitchStmt, *ast.TypeSwitchStmt:
+			ast.Inspect(s, func(n ast.Node) bool {
+				if clause, ok := n.(*ast.CaseClause); ok {
+					walk(clause.Body, flag)
+					return false
+				}
+				return true
+			})
+		default:
+			for _, key := range collect(stmt) {
+				if flag == "" {
+					unconditional[key] = true
+				} else {
+					guards[key] = flag
+				}
+			}
+		}
+	}
+	walk = func(stmts []ast.Stmt, flag string) {
+		for _, stmt := range stmts {
+			walkStmt(stmt, flag)
+		}
+	}
+
+	for _, fn := range registrationMethodDecls(node, methodName) {
+		walk(fn.Body.List, "")
+	}
+	for key := range unconditional {
+		delete(guards, key)
+	}
+	return guards
+}
+
+// featureFlagCondition returns the feature flag an if condition tests, "features.FivePointOh" for
+// features.FivePointOh(), "!features.FivePointOh" for its negation and "" for other conditions. Either operand of &&
+// gates the body.
+func featureFlagCondition(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return featureFlagCondition(e.X)
+	case *ast.UnaryExpr:
+		if e.Op == token.NOT {
+			if flag := featureFlagCondition(e.X); flag != "" {
+				return negateFeatureFlag(flag)
+			}
+		}
+	case *ast.BinaryExpr:
+		if e.Op == token.LAND {
+			if flag := featureFlagCondition(e.X); flag != "" {
+				return flag
+			}
+			return featureFlagCondition(e.Y)
+		}
+	case *ast.CallExpr:
+		if selector, ok := e.Fun.(*ast.SelectorExpr); ok {
+			if pkg, ok := selector.X.(*ast.Ident); ok && pkg.Name == featuresPackage {
+				return featuresPackage + "." + selector.Sel.Name
+			}
+		}
+	}
+	return ""
+}
+
+// negateFeatureFlag toggles the leading ! of a feature flag condition
+func negateFeatureFlag(flag string) string {
+	if negated, ok := strings.CutPrefix(flag, "!"); ok {
+		return negated
+	}
+	return "!" + flag
+}
+
+// mapLiteralKeys collects the Terraform types a statement registers in a legacy registration map, as string keys of
+// map literals and index assignments
+func mapLiteralKeys(node ast.Node) []string {
+	var keys []string
+	addKey := func(expr ast.Expr) {
+		if lit, ok := expr.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			if key, err := strconv.Unquote(lit.Value); err == nil {
+				keys = append(keys, key)
+			}
+		}
+	}
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.KeyValueExpr:
+			addKey(e.Key)
+		case *ast.AssignStmt:
+			for _, lhs := range e.Lhs {
+				if index, ok := lhs.(*ast.IndexExpr); ok {
+					addKey(index.Index)
+				}
+			}
+		}
+		return true
+	})
+	return keys
+}
+
+// compositeLiteralTypes collects the struct types a statement registers in a typed registration slice
+func compositeLiteralTypes(node ast.Node) []string {
+	var types []string
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CompositeLit:
+			if name := typeName(e.Type); name != "" {
+				types = append(types, name)
+			}
+		}
+		return true
+	})
+	return types
+}
+
+// identifierNames collects the identifiers of a statement, among them the constructors of ephemeral resources
+func identifierNames(node ast.Node) []string {
+	var names []string
+	ast.Inspect(node, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok {
+			names = append(names, ident.Name)
+		}
+		return true
+	})
+	return names
+}
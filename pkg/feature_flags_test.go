@@ -0,0 +1,112 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractFeatureFlagGuards_Typed(t *testing.T) {
+	source := `package service
+
+func (r Registration) Resources() []sdk.Resource {
+	resources := []sdk.Resource{
+		ConfigResource{},
+	}
+	if !features.FivePointOh() {
+		resources = append(resources, LegacyConfigResource{}, ConfigResource{})
+	} else {
+		resources = append(resources, PreviewResource{})
+	}
+	if features.FivePointOh() && enabled {
+		if region != "" {
+			resources = append(resources, RegionalResource{})
+		}
+	}
+	if enabled {
+		resources = append(resources, FlaggedResource{})
+	}
+	return resources
+}`
+
+	node, err := parseSource(source)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"LegacyConfigResource": "!features.FivePointOh",
+		"PreviewResource":      "features.FivePointOh",
+		"RegionalResource":     "features.FivePointOh",
+	}, extractFeatureFlagGuards(node, "Resources", compositeLiteralTypes))
+}
+
+func TestExtractFeatureFlagGuards_Legacy(t *testing.T) {
+	source := `package service
+
+func (r Registration) SupportedResources() map[string]*pluginsdk.Resource {
+	resources := map[string]*pluginsdk.Resource{
+		"azurerm_config": resourceConfig(),
+	}
+	if !features.FivePointOh() {
+		resources["azurerm_legacy_config"] = resourceLegacyConfig()
+		maps.Copy(resources, map[string]*pluginsdk.Resource{
+			"azurerm_legacy_link": resourceLegacyLink(),
+		})
+	}
+	return resources
+}`
+
+	node, err := parseSource(source)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"azurerm_legacy_config": "!features.FivePointOh",
+		"azurerm_legacy_link":   "!features.FivePointOh",
+	}, extractFeatureFlagGuards(node, "SupportedResources", mapLiteralKeys))
+	assert.Empty(t, extractFeatureFlagGuards(node, "SupportedDataSources", mapLiteralKeys))
+}
+
+func TestExtractFeatureFlags(t *testing.T) {
+	source := `package service
+
+func (r Registration) Resources() []sdk.Resource {
+	if features.FivePointOh() {
+		return []sdk.Resource{PreviewResource{}}
+	}
+	return []sdk.Resource{}
+}
+
+func (r Registration) SupportedDataSources() map[string]*pluginsdk.Resource {
+	dataSources := map[string]*pluginsdk.Resource{}
+	if !features.FivePointOh() {
+		dataSources["azurerm_legacy_config"] = dataSourceLegacyConfig()
+	}
+	return dataSources
+}
+
+func (r Registration) EphemeralResources() []func() ephemeral.EphemeralResource {
+	if !features.FivePointOh() {
+		return []func() ephemeral.EphemeralResource{NewLegacySecretEphemeralResource}
+	}
+	return nil
+}`
+
+	serviceReg := ServiceRegistration{
+		ResourceTerraformTypes:  map[string]string{"PreviewResource": "azurerm_preview"},
+		EphemeralTerraformTypes: map[string]string{"LegacySecretEphemeralResource": "azurerm_legacy_secret"},
+		EphemeralConstructors:   map[string]string{"LegacySecretEphemeralResource": "NewLegacySecretEphemeralResource"},
+		DataSourceMethods:       map[string]*LegacyDataSourceMethods{"azurerm_legacy_config": {ReadMethod: "dataSourceLegacyConfigRead"}},
+		ResourceFeatureFlags:    make(map[string]string),
+		DataSourceFeatureFlags:  make(map[string]string),
+		EphemeralFeatureFlags:   make(map[string]string),
+	}
+	extractFeatureFlags(&serviceReg, parsePackageInfo(t, source))
+
+	assert.Equal(t, map[string]string{"azurerm_preview": "features.FivePointOh"}, serviceReg.ResourceFeatureFlags)
+	assert.Equal(t, map[string]string{"azurerm_legacy_config": "!features.FivePointOh"}, serviceReg.DataSourceFeatureFlags)
+	assert.Equal(t, map[string]string{"azurerm_legacy_secret": "!features.FivePointOh"}, serviceReg.EphemeralFeatureFlags)
+
+	assert.Equal(t, "features.FivePointOh", newTerraformResourceInfo("azurerm_preview", "PreviewResource", "Resources", "modern_sdk", serviceReg).FeatureFlag)
+	assert.Equal(t, "!features.FivePointOh", newTerraformDataSourceInfo("azurerm_legacy_config", "", "dataSourceLegacyConfig", "legacy_pluginsdk", serviceReg).FeatureFlag)
+	assert.Equal(t, "!features.FivePointOh", newTerraformEphemeralInfo("LegacySecretEphemeralResource", serviceReg).FeatureFlag)
+}
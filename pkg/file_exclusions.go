@@ -0,0 +1,78 @@
+package pkg
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// FileExclusions select the files of the service packages left out of the scan, e.g. test harnesses registering
+// resources of their own
+type FileExclusions struct {
+	TestFiles      bool     // _test.go files, otherwise read for the acceptance tests of the resources
+	GeneratedFiles bool     // zz_generated* files
+	Patterns       []string // ["*_mock.go"], globs matched against the base names of the files, see filepath.Match
+}
+
+// Validate checks the syntax of the glob patterns
+func (e FileExclusions) Validate() error {
+	for _, pattern := range e.Patterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// excludes reports whether the file is left out of the scan
+func (e FileExclusions) excludes(fileName string) bool {
+	baseName := filepath.Base(fileName)
+	if e.TestFiles && strings.HasSuffix(baseName, "_test.go") {
+		return true
+	}
+	if e.GeneratedFiles && strings.HasPrefix(baseName, "zz_generated") {
+		return true
+	}
+	for _, pattern := range e.Patterns {
+		if matched, _ := filepath.Match(pattern, baseName); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// filterPackage drops the excluded files of a scanned package and the declarations they hold, the package is
+// returned as is when no file is excluded
+func (e FileExclusions) filterPackage(packageInfo *gophon.PackageInfo) *gophon.PackageInfo {
+	excluded := make(map[*gophon.FileInfo]bool)
+	filtered := &gophon.PackageInfo{}
+	for _, fileInfo := range packageInfo.Files {
+		if e.excludes(fileInfo.FileName) {
+			excluded[fileInfo] = true
+			continue
+		}
+		filtered.Files = append(filtered.Files, fileInfo)
+	}
+	if len(excluded) == 0 {
+		return packageInfo
+	}
+	filtered.Constants = excludeDeclarations(packageInfo.Constants, func(c *gophon.ConstantInfo) *gophon.Range { return c.Range }, excluded)
+	filtered.Variables = excludeDeclarations(packageInfo.Variables, func(v *gophon.VariableInfo) *gophon.Range { return v.Range }, excluded)
+	filtered.Types = excludeDeclarations(packageInfo.Types, func(t *gophon.TypeInfo) *gophon.Range { return t.Range }, excluded)
+	filtered.Functions = excludeDeclarations(packageInfo.Functions, func(f *gophon.FunctionInfo) *gophon.Range { return f.Range }, excluded)
+	return filtered
+}
+
+// excludeDeclarations drops the declarations of the excluded files
+func excludeDeclarations[T any](declarations []*T, rangeOf func(*T) *gophon.Range, excluded map[*gophon.FileInfo]bool) []*T {
+	var kept []*T
+	for _, declaration := range declarations {
+		if r := rangeOf(declaration); r != nil && excluded[r.FileInfo] {
+			continue
+		}
+		kept = append(kept, declaration)
+	}
+	return kept
+}
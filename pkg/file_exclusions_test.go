@@ -0,0 +1,53 @@
+package pkg
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileExclusions_Excludes(t *testing.T) {
+	exclusions := FileExclusions{TestFiles: true, GeneratedFiles: true, Patterns: []string{"*_mock.go"}}
+
+	assert.True(t, exclusions.excludes(filepath.Join("keyvault", "key_vault_resource_test.go")))
+	assert.True(t, exclusions.excludes(filepath.Join("keyvault", "zz_generated_registration.go")))
+	assert.True(t, exclusions.excludes(filepath.Join("keyvault", "client_mock.go")))
+	assert.False(t, exclusions.excludes(filepath.Join("keyvault", "key_vault_resource.go")))
+	assert.False(t, FileExclusions{}.excludes("key_vault_resource_test.go"))
+}
+
+func TestFileExclusions_Validate(t *testing.T) {
+	assert.NoError(t, FileExclusions{Patterns: []string{"*_mock.go"}}.Validate())
+	assert.ErrorContains(t, FileExclusions{Patterns: []string{"[mock"}}.Validate(), `invalid exclude pattern "[mock"`)
+}
+
+func TestFileExclusions_FilterPackage(t *testing.T) {
+	packageInfo := parsePackageInfo(t, `package keyvault
+func resourceKeyVault() {}
+`, `package keyvault
+func resourceKeyVaultMock() {}
+`)
+	packageInfo.Files[1].FileName = "zz_generated_mock.go"
+
+	assert.Same(t, packageInfo, FileExclusions{}.filterPackage(packageInfo))
+	filtered := FileExclusions{GeneratedFiles: true}.filterPackage(packageInfo)
+	require.Len(t, filtered.Files, 1)
+	assert.Equal(t, "file0.go", filtered.Files[0].FileName)
+	require.Len(t, filtered.Functions, 1)
+	assert.Equal(t, "resourceKeyVault", filtered.Functions[0].Name)
+	assert.Len(t, packageInfo.Files, 2, "the scanned package is left untouched")
+}
+
+func TestScanner_ExcludedFilesAreNotScanned(t *testing.T) {
+	servicesDir := filepath.Join("testharness", "internal", "services")
+	_, err := Scanner{Exclude: FileExclusions{Patterns: []string{"["}}}.Scan(servicesDir, "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+	assert.ErrorContains(t, err, "invalid exclude pattern")
+
+	index, err := Scanner{Exclude: FileExclusions{Patterns: []string{"registration.go"}}}.Scan(servicesDir, "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+	require.NoError(t, err)
+	_, found := index.LookupResource("azurerm_key_vault")
+	assert.False(t, found)
+	assert.Empty(t, index.Services, "the services of the harness are declared in registration.go only")
+}
@@ -0,0 +1,56 @@
+package pkg
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// ForceNewReference lists the attributes of a resource whose change replaces the resource
+type ForceNewReference struct {
+	ID            string   `json:"id"`             // "azurerm/resources/azurerm_key_vault/legacy_pluginsdk", see EntryID
+	TerraformType string   `json:"terraform_type"` // "azurerm_key_vault"
+	Attributes    []string `json:"attributes"`     // ["location", "name", "resource_group_name", "tenant_id"]
+}
+
+// forceNewAttributePaths returns the paths of the ForceNew attributes of a schema, attributes of nested blocks are
+// prefixed with the names of their blocks: "network_acls.bypass"
+func forceNewAttributePaths(attributes []SchemaAttribute) []string {
+	var paths []string
+	for _, attribute := range attributes {
+		if attribute.ForceNew {
+			paths = append(paths, attribute.Name)
+		}
+		for _, path := range forceNewAttributePaths(attribute.Block) {
+			paths = append(paths, attribute.Name+"."+path)
+		}
+	}
+	return paths
+}
+
+// BuildForceNewIndex lists the resources declaring ForceNew attributes, sorted by Terraform type
+func (index *TerraformProviderIndex) BuildForceNewIndex() []ForceNewReference {
+	references := []ForceNewReference{}
+	for _, service := range index.Services {
+		for terraformType, attributes := range service.ResourceSchemas {
+			paths := forceNewAttributePaths(attributes)
+			if len(paths) == 0 {
+				continue
+			}
+			references = append(references, ForceNewReference{
+				ID:            EntryID(DocumentKindResource, terraformType, service.resourceSDKType(terraformType)),
+				TerraformType: terraformType,
+				Attributes:    paths,
+			})
+		}
+	}
+
+	sort.Slice(references, func(i, j int) bool {
+		return references[i].TerraformType < references[j].TerraformType
+	})
+	return references
+}
+
+// writeForceNewIndexFile writes force_new.json, the ForceNew attributes of all resources
+func (index *TerraformProviderIndex) writeForceNewIndexFile(outputDir string) error {
+	return index.writeJSONFile(filepath.Join(outputDir, "force_new.json"), index.BuildForceNewIndex())
+}
@@ -0,0 +1,106 @@
+package pkg
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractLegacyResourceSchemaFromPackage_ForceNew(t *testing.T) {
+	source := `package keyvault
+
+func resourceKeyVault() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Schema: map[string]*pluginsdk.Schema{
+			"name": {
+				Type:     pluginsdk.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"location": commonschema.LocationWithoutForceNew(),
+			"tags":     commonschema.TagsForceNew(),
+			"sku_name": {
+				Type:     pluginsdk.TypeString,
+				Required: true,
+			},
+		},
+	}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+	result := extractLegacyResourceSchemaFromPackage("resourceKeyVault", packageInfo)
+
+	assert.Equal(t, []string{"name", "tags"}, forceNewAttributePaths(result))
+}
+
+func TestExtractTypedResourceSchemaFromPackage_RequiresReplace(t *testing.T) {
+	source := `package resource
+
+type ResourceGroupFrameworkResource struct{}
+
+func (r ResourceGroupFrameworkResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"managed_by": schema.StringAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+	result := extractTypedResourceSchemaFromPackage("ResourceGroupFrameworkResource", packageInfo)
+
+	assert.Equal(t, []SchemaAttribute{
+		{Name: "name", Required: true, ForceNew: true},
+		{Name: "managed_by", Optional: true},
+	}, result)
+}
+
+func TestTerraformProviderIndex_WriteForceNewIndexFile(t *testing.T) {
+	index := &TerraformProviderIndex{
+		Services: []ServiceRegistration{
+			{
+				SupportedResources: map[string]string{"azurerm_key_vault": "resourceKeyVault"},
+				ResourceSchemas: map[string][]SchemaAttribute{
+					"azurerm_key_vault": {
+						{Name: "name", ForceNew: true},
+						{Name: "network_acls", Block: []SchemaAttribute{
+							{Name: "bypass", Depth: 1, ForceNew: true},
+						}},
+					},
+					"azurerm_key_vault_secret": {
+						{Name: "value"},
+					},
+				},
+			},
+		},
+	}
+	fs := afero.NewMemMapFs()
+	index.Output.Fs = fs
+	outputDir := "/test/output"
+
+	require.NoError(t, index.writeForceNewIndexFile(outputDir))
+
+	data, err := afero.ReadFile(fs, filepath.Join(outputDir, "force_new.json"))
+	require.NoError(t, err)
+	var references []ForceNewReference
+	require.NoError(t, json.Unmarshal(data, &references))
+
+	assert.Equal(t, []ForceNewReference{
+		{ID: "azurerm/resources/azurerm_key_vault/legacy_pluginsdk", TerraformType: "azurerm_key_vault", Attributes: []string{"name", "network_acls.bypass"}},
+	}, references)
+}
@@ -0,0 +1,47 @@
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// CloneProvider shallow-clones a single ref (tag, branch or commit) of a provider repository into a temporary
+// directory. The returned cleanup function removes the checkout and must be called once scanning is done.
+func CloneProvider(repo, ref string) (dir string, cleanup func(), err error) {
+	// Arguments starting with a dash would be parsed as options by git
+	if strings.HasPrefix(repo, "-") {
+		return "", nil, fmt.Errorf("invalid repository %q: must not start with -", repo)
+	}
+	if ref == "" || strings.HasPrefix(ref, "-") {
+		return "", nil, fmt.Errorf("invalid ref %q: must be a tag, branch or commit not starting with -", ref)
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", nil, fmt.Errorf("git is required to clone %s: %w", repo, err)
+	}
+
+	dir, err = os.MkdirTemp("", "terraform-provider-checkout-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create checkout directory: %w", err)
+	}
+	cleanup = func() {
+		_ = os.RemoveAll(dir)
+	}
+
+	// Fetching the ref directly supports commits as well as tags and branches, unlike git clone --branch
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"remote", "add", "origin", repo},
+		{"fetch", "--quiet", "--depth", "1", "origin", ref},
+		{"checkout", "--quiet", "FETCH_HEAD"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			cleanup()
+			return "", nil, fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(string(output)))
+		}
+	}
+	return dir, cleanup, nil
+}
@@ -0,0 +1,57 @@
+package pkg
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneProvider_LocalRepositoryTag(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	git("init", "--quiet")
+	writeTestPackage(t, repo, "internal/services/keyvault", "registration.go", "package keyvault\n")
+	git("add", "-A")
+	git("commit", "--quiet", "-m", "v1")
+	git("tag", "v1.0.0")
+	require.NoError(t, os.Remove(filepath.Join(repo, "internal", "services", "keyvault", "registration.go")))
+	git("commit", "--quiet", "-a", "-m", "v2")
+
+	dir, cleanup, err := CloneProvider(repo, "v1.0.0")
+	require.NoError(t, err)
+
+	assert.FileExists(t, filepath.Join(dir, "internal", "services", "keyvault", "registration.go"))
+	cleanup()
+	assert.NoDirExists(t, dir)
+}
+
+func TestCloneProvider_UnknownRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	_, _, err := CloneProvider(t.TempDir(), "v0.0.0-missing")
+	assert.Error(t, err)
+}
+
+func TestCloneProvider_OptionLikeArguments(t *testing.T) {
+	_, _, err := CloneProvider(t.TempDir(), "--upload-pack=touch /tmp/pwned")
+	assert.ErrorContains(t, err, `invalid ref "--upload-pack=touch /tmp/pwned"`)
+	_, _, err = CloneProvider(t.TempDir(), "")
+	assert.ErrorContains(t, err, "invalid ref")
+	_, _, err = CloneProvider("--upload-pack=touch /tmp/pwned", "v4.20.0")
+	assert.ErrorContains(t, err, "invalid repository")
+}
@@ -0,0 +1,64 @@
+package pkg
+
+// GlobalMappingEntry locates the registration of a Terraform type
+type GlobalMappingEntry struct {
+	ID                 string `json:"id"`                            // "azurerm/resources/azurerm_key_vault/legacy_pluginsdk", see EntryID
+	Service            string `json:"service"`                       // "keyvault"
+	SDKType            string `json:"sdk_type"`                      // "legacy_pluginsdk", "modern_sdk", "ephemeral", "list_resource" or "action"
+	RegistrationMethod string `json:"registration_method,omitempty"` // "resourceKeyVault" for legacy registrations
+	StructType         string `json:"struct_type,omitempty"`         // "KeyVaultSecretEphemeralResource" for typed registrations
+}
+
+// GlobalMappings maps every Terraform type of the provider to its registration, across all services
+type GlobalMappings struct {
+	AllResources     map[string]GlobalMappingEntry `json:"all_resources"`      // "azurerm_key_vault" -> registration
+	AllDataSources   map[string]GlobalMappingEntry `json:"all_data_sources"`   // "azurerm_key_vault" -> registration
+	AllEphemeral     map[string]GlobalMappingEntry `json:"all_ephemeral"`      // "azurerm_key_vault_secret" -> registration
+	AllListResources map[string]GlobalMappingEntry `json:"all_list_resources"` // "azurerm_resource_group" -> registration
+	AllActions       map[string]GlobalMappingEntry `json:"all_actions"`        // "azurerm_virtual_machine_power" -> registration
+}
+
+func newGlobalMappings() GlobalMappings {
+	return GlobalMappings{
+		AllResources:     make(map[string]GlobalMappingEntry),
+		AllDataSources:   make(map[string]GlobalMappingEntry),
+		AllEphemeral:     make(map[string]GlobalMappingEntry),
+		AllListResources: make(map[string]GlobalMappingEntry),
+		AllActions:       make(map[string]GlobalMappingEntry),
+	}
+}
+
+// BuildGlobalMappings collects the registrations of all services. Unresolved typed registrations are keyed by their
+// struct type, the same key their documents are written with.
+func (index *TerraformProviderIndex) BuildGlobalMappings() GlobalMappings {
+	mappings := newGlobalMappings()
+
+	for _, service := range index.Services {
+		for terraformType, registrationMethod := range service.SupportedResources {
+			mappings.AllResources[terraformType] = GlobalMappingEntry{ID: EntryID(DocumentKindResource, terraformType, "legacy_pluginsdk"), Service: service.ServiceName, SDKType: "legacy_pluginsdk", RegistrationMethod: registrationMethod}
+		}
+		for _, structType := range service.Resources {
+			terraformType := service.resourceTerraformType(structType)
+			mappings.AllResources[terraformType] = GlobalMappingEntry{ID: EntryID(DocumentKindResource, terraformType, "modern_sdk"), Service: service.ServiceName, SDKType: "modern_sdk", StructType: structType}
+		}
+		for terraformType, registrationMethod := range service.SupportedDataSources {
+			mappings.AllDataSources[terraformType] = GlobalMappingEntry{ID: EntryID(DocumentKindDataSource, terraformType, "legacy_pluginsdk"), Service: service.ServiceName, SDKType: "legacy_pluginsdk", RegistrationMethod: registrationMethod}
+		}
+		for _, structType := range service.DataSources {
+			terraformType := service.dataSourceTerraformType(structType)
+			mappings.AllDataSources[terraformType] = GlobalMappingEntry{ID: EntryID(DocumentKindDataSource, terraformType, "modern_sdk"), Service: service.ServiceName, SDKType: "modern_sdk", StructType: structType}
+		}
+		for structType, terraformType := range service.EphemeralTerraformTypes {
+			mappings.AllEphemeral[terraformType] = GlobalMappingEntry{ID: EntryID(DocumentKindEphemeral, terraformType, "ephemeral"), Service: service.ServiceName, SDKType: "ephemeral", StructType: structType}
+		}
+		for _, structType := range service.ListResources {
+			terraformType := service.listResourceTerraformType(structType)
+			mappings.AllListResources[terraformType] = GlobalMappingEntry{ID: EntryID(DocumentKindListResource, terraformType, "list_resource"), Service: service.ServiceName, SDKType: "list_resource", StructType: structType}
+		}
+		for _, structType := range service.Actions {
+			terraformType := service.actionTerraformType(structType)
+			mappings.AllActions[terraformType] = GlobalMappingEntry{ID: EntryID(DocumentKindAction, terraformType, "action"), Service: service.ServiceName, SDKType: "action", StructType: structType}
+		}
+	}
+	return mappings
+}
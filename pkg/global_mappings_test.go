@@ -0,0 +1,48 @@
+package pkg
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildGlobalMappings(t *testing.T) {
+	index := createTestTerraformProviderIndex()
+
+	mappings := index.BuildGlobalMappings()
+
+	assert.Equal(t, map[string]GlobalMappingEntry{
+		"azurerm_key_vault":                    {ID: "azurerm/resources/azurerm_key_vault/legacy_pluginsdk", Service: "keyvault", SDKType: "legacy_pluginsdk", RegistrationMethod: "resourceKeyVault"},
+		"azurerm_key_vault_certificate":        {ID: "azurerm/resources/azurerm_key_vault_certificate/legacy_pluginsdk", Service: "keyvault", SDKType: "legacy_pluginsdk", RegistrationMethod: "resourceKeyVaultCertificate"},
+		"azurerm_key_vault_modern":             {ID: "azurerm/resources/azurerm_key_vault_modern/modern_sdk", Service: "keyvault", SDKType: "modern_sdk", StructType: "KeyVaultResource"},
+		"azurerm_key_vault_certificate_modern": {ID: "azurerm/resources/azurerm_key_vault_certificate_modern/modern_sdk", Service: "keyvault", SDKType: "modern_sdk", StructType: "KeyVaultCertificateResource"},
+	}, mappings.AllResources)
+	assert.Equal(t, map[string]GlobalMappingEntry{
+		"azurerm_key_vault":             {ID: "azurerm/datasources/azurerm_key_vault/legacy_pluginsdk", Service: "keyvault", SDKType: "legacy_pluginsdk", RegistrationMethod: "dataSourceKeyVault"},
+		"azurerm_key_vault_key":         {ID: "azurerm/datasources/azurerm_key_vault_key/legacy_pluginsdk", Service: "keyvault", SDKType: "legacy_pluginsdk", RegistrationMethod: "dataSourceKeyVaultKey"},
+		"azurerm_key_vault_data_modern": {ID: "azurerm/datasources/azurerm_key_vault_data_modern/modern_sdk", Service: "keyvault", SDKType: "modern_sdk", StructType: "KeyVaultDataSource"},
+	}, mappings.AllDataSources)
+	assert.Equal(t, map[string]GlobalMappingEntry{
+		"azurerm_key_vault_certificate_ephemeral": {ID: "azurerm/ephemeral/azurerm_key_vault_certificate_ephemeral/ephemeral", Service: "keyvault", SDKType: "ephemeral", StructType: "NewKeyVaultCertificateEphemeralResource"},
+	}, mappings.AllEphemeral)
+}
+
+func TestWriteMainIndexFile_GlobalMaps(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	index := createTestTerraformProviderIndex()
+	index.Output.Fs = fs
+	index.GlobalMaps = index.BuildGlobalMappings()
+	require.NoError(t, index.writeMainIndexFile("/output"))
+
+	content, err := afero.ReadFile(fs, filepath.Join("/output", index.Output.MainIndexFileName()))
+	require.NoError(t, err)
+	var written struct {
+		GlobalMaps GlobalMappings `json:"global_maps"`
+	}
+	require.NoError(t, json.Unmarshal(content, &written))
+	assert.Equal(t, index.GlobalMaps, written.GlobalMaps)
+}
@@ -0,0 +1,103 @@
+package pkg
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// GoDoc holds the doc comments of the Go declarations implementing a resource or data source, which often note
+// API quirks the code works around
+type GoDoc struct {
+	Registration string `json:"registration,omitempty" protobuf:"1"` // Doc comment of the legacy registration function, "resourceKeyVault"
+	Struct       string `json:"struct,omitempty" protobuf:"2"`       // Doc comment of the typed resource struct, "KeyVaultResource"
+	Create       string `json:"create,omitempty" protobuf:"3"`       // Doc comment of the Create function or method
+	Read         string `json:"read,omitempty" protobuf:"4"`         // Doc comment of the Read function or method
+	Update       string `json:"update,omitempty" protobuf:"5"`       // Doc comment of the Update function or method
+	Delete       string `json:"delete,omitempty" protobuf:"6"`       // Doc comment of the Delete function or method
+}
+
+// isEmpty reports whether none of the declarations has a doc comment
+func (d GoDoc) isEmpty() bool {
+	return d == GoDoc{}
+}
+
+// extractLegacyResourceGoDoc collects the doc comments of a legacy registration function and its CRUD functions
+func extractLegacyResourceGoDoc(registrationMethod string, crud *LegacyResourceCRUDFunctions, packageInfo *gophon.PackageInfo) *GoDoc {
+	doc := GoDoc{Registration: funcDocText(findFunctionDecl(packageInfo, registrationMethod))}
+	if crud != nil {
+		doc.Create = funcDocText(findFunctionDecl(packageInfo, crud.CreateMethod))
+		doc.Read = funcDocText(findFunctionDecl(packageInfo, crud.ReadMethod))
+		doc.Update = funcDocText(findFunctionDecl(packageInfo, crud.UpdateMethod))
+		doc.Delete = funcDocText(findFunctionDecl(packageInfo, crud.DeleteMethod))
+	}
+	return nonEmptyGoDoc(doc)
+}
+
+// extractLegacyDataSourceGoDoc collects the doc comments of a legacy data source registration function and its Read function
+func extractLegacyDataSourceGoDoc(registrationMethod string, methods *LegacyDataSourceMethods, packageInfo *gophon.PackageInfo) *GoDoc {
+	doc := GoDoc{Registration: funcDocText(findFunctionDecl(packageInfo, registrationMethod))}
+	if methods != nil {
+		doc.Read = funcDocText(findFunctionDecl(packageInfo, methods.ReadMethod))
+	}
+	return nonEmptyGoDoc(doc)
+}
+
+// extractTypedGoDoc collects the doc comments of a typed resource or data source struct and its CRUD methods, data
+// sources only implement Read
+func extractTypedGoDoc(structType string, packageInfo *gophon.PackageInfo) *GoDoc {
+	return nonEmptyGoDoc(GoDoc{
+		Struct: typeDocText(structType, packageInfo),
+		Create: funcDocText(findMethodDecl(packageInfo, structType, "Create")),
+		Read:   funcDocText(findMethodDecl(packageInfo, structType, "Read")),
+		Update: funcDocText(findMethodDecl(packageInfo, structType, "Update")),
+		Delete: funcDocText(findMethodDecl(packageInfo, structType, "Delete")),
+	})
+}
+
+func nonEmptyGoDoc(doc GoDoc) *GoDoc {
+	if doc.isEmpty() {
+		return nil
+	}
+	return &doc
+}
+
+// funcDocText returns the doc comment of a function declaration without comment markers, "" when it has none
+func funcDocText(fn *ast.FuncDecl) string {
+	if fn == nil {
+		return ""
+	}
+	return strings.TrimSpace(fn.Doc.Text())
+}
+
+// typeDocText returns the doc comment of a type declared in the package, either on its spec inside a type group or
+// on the declaration itself
+func typeDocText(typeName string, packageInfo *gophon.PackageInfo) string {
+	if packageInfo == nil {
+		return ""
+	}
+	for _, fileInfo := range packageInfo.Files {
+		if fileInfo.File == nil {
+			continue
+		}
+		for _, decl := range fileInfo.File.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if !ok || typeSpec.Name.Name != typeName {
+					continue
+				}
+				if typeSpec.Doc != nil {
+					return strings.TrimSpace(typeSpec.Doc.Text())
+				}
+				return strings.TrimSpace(genDecl.Doc.Text())
+			}
+		}
+	}
+	return ""
+}
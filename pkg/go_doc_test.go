@@ -0,0 +1,85 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractLegacyResourceGoDoc(t *testing.T) {
+	source := `package keyvault
+
+// resourceKeyVault manages a Key Vault.
+// Soft deleted vaults are recovered on create.
+func resourceKeyVault() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Create: resourceKeyVaultCreate,
+		Read:   resourceKeyVaultRead,
+		Delete: resourceKeyVaultDelete,
+	}
+}
+
+// NOTE: the API returns 404 until the DNS record propagated
+func resourceKeyVaultCreate(d *pluginsdk.ResourceData, meta interface{}) error {
+	return nil
+}
+
+func resourceKeyVaultRead(d *pluginsdk.ResourceData, meta interface{}) error {
+	return nil
+}
+
+func resourceKeyVaultDelete(d *pluginsdk.ResourceData, meta interface{}) error {
+	return nil
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+	crud := &LegacyResourceCRUDFunctions{CreateMethod: "resourceKeyVaultCreate", ReadMethod: "resourceKeyVaultRead", DeleteMethod: "resourceKeyVaultDelete"}
+
+	assert.Equal(t, &GoDoc{
+		Registration: "resourceKeyVault manages a Key Vault.\nSoft deleted vaults are recovered on create.",
+		Create:       "NOTE: the API returns 404 until the DNS record propagated",
+	}, extractLegacyResourceGoDoc("resourceKeyVault", crud, packageInfo))
+	assert.Nil(t, extractLegacyResourceGoDoc("resourceKeyVaultRead", nil, packageInfo))
+}
+
+func TestExtractTypedGoDoc(t *testing.T) {
+	source := `package containers
+
+// KubernetesFleetManagerResource manages a fleet of clusters, the hub is created with the fleet.
+type KubernetesFleetManagerResource struct{}
+
+type (
+	// KubernetesFleetMemberResource joins a cluster to a fleet
+	KubernetesFleetMemberResource struct{}
+)
+
+// Update only patches tags, other changes recreate the fleet
+func (r KubernetesFleetManagerResource) Update() sdk.ResourceFunc {
+	return sdk.ResourceFunc{}
+}
+
+func (r KubernetesFleetManagerResource) Read() sdk.ResourceFunc {
+	return sdk.ResourceFunc{}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+
+	assert.Equal(t, &GoDoc{
+		Struct: "KubernetesFleetManagerResource manages a fleet of clusters, the hub is created with the fleet.",
+		Update: "Update only patches tags, other changes recreate the fleet",
+	}, extractTypedGoDoc("KubernetesFleetManagerResource", packageInfo))
+	assert.Equal(t, &GoDoc{Struct: "KubernetesFleetMemberResource joins a cluster to a fleet"}, extractTypedGoDoc("KubernetesFleetMemberResource", packageInfo))
+	assert.Nil(t, extractTypedGoDoc("UnknownResource", packageInfo))
+}
+
+func TestNewTerraformResourceInfo_GoDoc(t *testing.T) {
+	doc := &GoDoc{Registration: "resourceKeyVault manages a Key Vault."}
+	service := ServiceRegistration{
+		SupportedResources: map[string]string{"azurerm_key_vault": "resourceKeyVault"},
+		ResourceGoDocs:     map[string]*GoDoc{"azurerm_key_vault": doc},
+	}
+
+	resource := newTerraformResourceInfo("azurerm_key_vault", "", "resourceKeyVault", "legacy_pluginsdk", service)
+
+	assert.Equal(t, doc, resource.Doc)
+}
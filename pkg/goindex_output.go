@@ -0,0 +1,34 @@
+package pkg
+
+import (
+	"fmt"
+	"time"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// WriteGoIndexFiles writes the gophon .goindex files of every package under scanPath, subpackages included, to
+// outputDir, in the directory of each package path relative to basePkgUrl. This is the layout goindex references of
+// documents resolve against, so the index and the source it references form one bundle. Unlike the index files, the
+// .goindex files are always written to the OS filesystem.
+func WriteGoIndexFiles(scanPath, basePkgUrl, outputDir string, progressCallback ProgressCallback) error {
+	var gophonProgress func(gophon.ProgressInfo)
+	if progressCallback != nil {
+		startTime := time.Now()
+		gophonProgress = func(progress gophon.ProgressInfo) {
+			progressCallback(ProgressInfo{
+				Phase:      "goindex",
+				Current:    progress.Current,
+				Completed:  progress.Completed,
+				Total:      progress.Total,
+				Percentage: progress.Percentage,
+				StartTime:  startTime,
+			})
+		}
+	}
+
+	if err := gophon.IndexSourceCode(scanPath, basePkgUrl, outputDir, gophonProgress); err != nil {
+		return fmt.Errorf("failed to write goindex files of %s: %w", scanPath, err)
+	}
+	return nil
+}
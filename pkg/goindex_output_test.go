@@ -0,0 +1,37 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteGoIndexFiles(t *testing.T) {
+	basePkgUrl := "github.com/lonegunmanb/terraform-provider-azurerm-index"
+	outputDir := t.TempDir()
+	var phases []string
+	progress := func(info ProgressInfo) {
+		phases = append(phases, info.Phase)
+	}
+
+	require.NoError(t, WriteGoIndexFiles(filepath.Join("testharness", "internal", "services"), basePkgUrl, outputDir, progress))
+
+	_, err := os.Stat(filepath.Join(outputDir, "testharness", "internal", "services", "keyvault", "func.resourceKeyVault.goindex"))
+	assert.NoError(t, err)
+	require.NotEmpty(t, phases)
+	assert.Equal(t, "goindex", phases[0])
+
+	// References of documents scanned with the same base package resolve against the written files
+	index, err := ScanTerraformProviderServices(filepath.Join("testharness", "internal", "services"), basePkgUrl, "test-version", nil)
+	require.NoError(t, err)
+	report, err := index.VerifyGoIndexReferences(outputDir, basePkgUrl)
+	require.NoError(t, err)
+	assert.NotContains(t, report.Broken, GoIndexReference{
+		ID:        "azurerm/resources/azurerm_key_vault/legacy_pluginsdk",
+		Field:     "schema_index",
+		Reference: "func.resourceKeyVault.goindex",
+	})
+}
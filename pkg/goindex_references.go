@@ -0,0 +1,231 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// GoIndexReference is a goindex reference of a document that doesn't exist in the gophon output directory
+type GoIndexReference struct {
+	ID        string `json:"id"`                  // "azurerm/resources/azurerm_key_vault/legacy_pluginsdk", see EntryID
+	Field     string `json:"field"`               // "update_index"
+	Reference string `json:"reference"`           // "func.resourceKeyVaultUpdate.goindex", as derived by the scan
+	Corrected string `json:"corrected,omitempty"` // "method.KeyVaultResource.Update.goindex", empty when the reference is broken
+}
+
+// GoIndexReport lists the goindex references corrected or removed by VerifyGoIndexReferences
+type GoIndexReport struct {
+	GoIndexDir string             `json:"goindex_dir"` // "./index"
+	Checked    int                `json:"checked"`     // Number of references checked
+	Corrected  []GoIndexReference `json:"corrected"`   // Sorted by ID and field
+	Broken     []GoIndexReference `json:"broken"`      // Sorted by ID and field
+}
+
+// VerifyGoIndexReferences cross-checks the goindex references of every document, such as schema_index and
+// create_index, against the files gophon generated in goIndexDir, where the files of a package are in the directory
+// of its path relative to basePkgUrl, for example internal/services/keyvault/func.resourceKeyVault.goindex.
+// A missing reference is corrected when the package has exactly one goindex file of the same symbol name, such as a
+// method found instead of a function, otherwise it is broken and removed from the documents. The report is also
+// written to audit/goindex-references.json.
+func (index *TerraformProviderIndex) VerifyGoIndexReferences(goIndexDir, basePkgUrl string) (*GoIndexReport, error) {
+	if info, err := os.Stat(goIndexDir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("goindex directory %s is not a directory", goIndexDir)
+	}
+
+	report := &GoIndexReport{GoIndexDir: filepath.ToSlash(goIndexDir), Corrected: []GoIndexReference{}, Broken: []GoIndexReference{}}
+	for i := range index.Services {
+		index.Services[i].GoIndexReferences = nil
+	}
+	resolved := make(map[string]map[string]string)   // service -> derived reference -> corrected reference, empty when broken
+	packageFiles := make(map[string]map[string]bool) // service -> goindex files of its package
+
+	for _, document := range index.Documents() {
+		service := index.service(document.Service)
+		if service == nil {
+			continue
+		}
+		if _, listed := packageFiles[service.ServiceName]; !listed {
+			files, err := goIndexFiles(filepath.Join(goIndexDir, filepath.FromSlash(strings.TrimPrefix(strings.TrimPrefix(service.PackagePath, basePkgUrl), "/"))))
+			if err != nil {
+				return nil, err
+			}
+			packageFiles[service.ServiceName] = files
+			resolved[service.ServiceName] = make(map[string]string)
+		}
+		files := packageFiles[service.ServiceName]
+
+		references := documentGoIndexReferences(document.Content)
+		fields := make([]string, 0, len(references))
+		for field := range references {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			reference := *references[field]
+			if reference == "" {
+				continue
+			}
+			report.Checked++
+			if files[reference] {
+				continue
+			}
+
+			entry := GoIndexReference{ID: document.ID, Field: field, Reference: reference, Corrected: correctGoIndexReference(reference, files)}
+			resolved[service.ServiceName][reference] = entry.Corrected
+			if entry.Corrected != "" {
+				report.Corrected = append(report.Corrected, entry)
+			} else {
+				report.Broken = append(report.Broken, entry)
+			}
+		}
+	}
+
+	sortGoIndexReferences(report.Corrected)
+	sortGoIndexReferences(report.Broken)
+	for i := range index.Services {
+		if references := resolved[index.Services[i].ServiceName]; len(references) > 0 {
+			index.Services[i].GoIndexReferences = references
+		}
+	}
+	index.GoIndex = report
+	return report, nil
+}
+
+// sortGoIndexReferences sorts goindex references by ID and field
+func sortGoIndexReferences(references []GoIndexReference) {
+	sort.Slice(references, func(i, j int) bool {
+		if references[i].ID != references[j].ID {
+			return references[i].ID < references[j].ID
+		}
+		return references[i].Field < references[j].Field
+	})
+}
+
+// service returns the registration of a service of the index, nil when there is none
+func (index *TerraformProviderIndex) service(serviceName string) *ServiceRegistration {
+	for i := range index.Services {
+		if index.Services[i].ServiceName == serviceName {
+			return &index.Services[i]
+		}
+	}
+	return nil
+}
+
+// goIndexFiles returns the goindex files of a package directory, none when gophon generated nothing for the package
+func goIndexFiles(dir string) (map[string]bool, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read goindex directory %s: %w", dir, err)
+	}
+
+	files := make(map[string]bool)
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".goindex") {
+			files[entry.Name()] = true
+		}
+	}
+	return files, nil
+}
+
+// goIndexSymbolName returns the function or method name of a goindex file name, "Update" for
+// method.KeyVaultResource.Update.goindex and "resourceKeyVaultUpdate" for func.resourceKeyVaultUpdate.goindex
+func goIndexSymbolName(fileName string) string {
+	parts := strings.Split(strings.TrimSuffix(fileName, ".goindex"), ".")
+	return parts[len(parts)-1]
+}
+
+// correctGoIndexReference returns the only other goindex file of the package declaring the symbol of a missing
+// reference, or "" when there is none or more than one
+func correctGoIndexReference(reference string, files map[string]bool) string {
+	symbol := goIndexSymbolName(reference)
+	corrected := ""
+	for file := range files {
+		if (strings.HasPrefix(file, "func.") || strings.HasPrefix(file, "method.")) && goIndexSymbolName(file) == symbol {
+			if corrected != "" {
+				return ""
+			}
+			corrected = file
+		}
+	}
+	return corrected
+}
+
+// documentGoIndexReferences returns the goindex reference fields of a document content by JSON field name
+func documentGoIndexReferences(content interface{}) map[string]*string {
+	switch c := content.(type) {
+	case TerraformResource:
+		return c.goIndexReferences()
+	case TerraformDataSource:
+		return c.goIndexReferences()
+	case TerraformEphemeral:
+		return c.goIndexReferences()
+	case TerraformListResource:
+		return c.goIndexReferences()
+	case TerraformAction:
+		return c.goIndexReferences()
+	}
+	return nil
+}
+
+func (r *TerraformResource) goIndexReferences() map[string]*string {
+	return map[string]*string{
+		"schema_index":    &r.SchemaIndex,
+		"create_index":    &r.CreateIndex,
+		"read_index":      &r.ReadIndex,
+		"update_index":    &r.UpdateIndex,
+		"delete_index":    &r.DeleteIndex,
+		"attribute_index": &r.AttributeIndex,
+	}
+}
+
+func (d *TerraformDataSource) goIndexReferences() map[string]*string {
+	return map[string]*string{
+		"schema_index":    &d.SchemaIndex,
+		"read_index":      &d.ReadIndex,
+		"attribute_index": &d.AttributeIndex,
+	}
+}
+
+func (e *TerraformEphemeral) goIndexReferences() map[string]*string {
+	return map[string]*string{
+		"schema_index": &e.SchemaIndex,
+		"open_index":   &e.OpenIndex,
+		"renew_index":  &e.RenewIndex,
+		"close_index":  &e.CloseIndex,
+	}
+}
+
+func (l *TerraformListResource) goIndexReferences() map[string]*string {
+	return map[string]*string{
+		"schema_index": &l.SchemaIndex,
+		"list_index":   &l.ListIndex,
+	}
+}
+
+func (a *TerraformAction) goIndexReferences() map[string]*string {
+	return map[string]*string{
+		"schema_index": &a.SchemaIndex,
+		"invoke_index": &a.InvokeIndex,
+	}
+}
+
+// resolveGoIndexReferences replaces the references corrected or removed by VerifyGoIndexReferences
+func (s ServiceRegistration) resolveGoIndexReferences(references map[string]*string) {
+	for _, reference := range references {
+		if corrected, exists := s.GoIndexReferences[*reference]; exists {
+			*reference = corrected
+		}
+	}
+}
+
+// writeGoIndexReportFile writes audit/goindex-references.json
+func (index *TerraformProviderIndex) writeGoIndexReportFile(outputDir string) error {
+	return index.writeJSONFile(filepath.Join(outputDir, "audit", "goindex-references.json"), index.GoIndex)
+}
@@ -0,0 +1,72 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformProviderIndex_VerifyGoIndexReferences(t *testing.T) {
+	basePkgUrl := "github.com/lonegunmanb/terraform-provider-azurerm-index"
+	goIndexDir := t.TempDir()
+	require.NoError(t, gophon.IndexSourceCode(filepath.Join("testharness", "internal", "services"), basePkgUrl, goIndexDir, nil))
+	// Attributes declared on an embedded struct are indexed under the embedded type
+	storageDir := filepath.Join(goIndexDir, "testharness", "internal", "services", "storage")
+	require.NoError(t, os.WriteFile(filepath.Join(storageDir, "method.accountBase.Attributes.goindex"), []byte("package storage\n"), 0644))
+
+	index, err := ScanTerraformProviderServices(filepath.Join("testharness", "internal", "services"), basePkgUrl, "test-version", nil)
+	require.NoError(t, err)
+
+	report, err := index.VerifyGoIndexReferences(goIndexDir, basePkgUrl)
+	require.NoError(t, err)
+
+	assert.Greater(t, report.Checked, len(report.Corrected)+len(report.Broken))
+	assert.Contains(t, report.Corrected, GoIndexReference{
+		ID:        "azurerm/resources/azurerm_account/modern_sdk",
+		Field:     "attribute_index",
+		Reference: "method.AccountResource.Attributes.goindex",
+		Corrected: "method.accountBase.Attributes.goindex",
+	})
+	assert.Contains(t, report.Broken, GoIndexReference{
+		ID:        "azurerm/resources/azurerm_account/modern_sdk",
+		Field:     "schema_index",
+		Reference: "method.AccountResource.Arguments.goindex",
+	})
+	for _, reference := range append(report.Corrected, report.Broken...) {
+		assert.NotEqual(t, "func.resourceKeyVault.goindex", reference.Reference, "existing references are left alone")
+	}
+
+	// Documents carry the corrected references and no broken ones
+	for _, document := range index.Documents() {
+		switch document.ID {
+		case "azurerm/resources/azurerm_account/modern_sdk":
+			resource := document.Content.(TerraformResource)
+			assert.Equal(t, "method.accountBase.Attributes.goindex", resource.AttributeIndex)
+			assert.Empty(t, resource.SchemaIndex)
+		case "azurerm/resources/azurerm_key_vault/legacy_pluginsdk":
+			resource := document.Content.(TerraformResource)
+			assert.Equal(t, "func.resourceKeyVault.goindex", resource.SchemaIndex)
+		}
+	}
+
+	_, err = index.VerifyGoIndexReferences(filepath.Join(goIndexDir, "missing"), basePkgUrl)
+	assert.Error(t, err)
+}
+
+func TestCorrectGoIndexReference(t *testing.T) {
+	files := map[string]bool{
+		"func.resourceKeyVaultCreate.goindex":    true,
+		"method.KeyVaultResource.Create.goindex": true,
+		"method.KeyVaultResource.Read.goindex":   true,
+		"method.SecretResource.Read.goindex":     true,
+		"type.Read.goindex":                      true,
+	}
+
+	assert.Equal(t, "method.KeyVaultResource.Create.goindex", correctGoIndexReference("func.Create.goindex", files))
+	assert.Equal(t, "", correctGoIndexReference("method.VaultResource.Read.goindex", files), "ambiguous")
+	assert.Equal(t, "", correctGoIndexReference("func.resourceKeyVaultDelete.goindex", files))
+}
@@ -0,0 +1,85 @@
+package pkg
+
+import (
+	"path/filepath"
+	"strconv"
+)
+
+// FeatureHeatmap aggregates how often the provider relies on each SDK facility, across all resources and data sources
+type FeatureHeatmap struct {
+	AttributeTypes map[string]int            `json:"attribute_types"` // {"TypeString": 5210, "TypeList": 1402}
+	Validators     map[string]int            `json:"validators"`      // {"validation.StringIsNotEmpty": 812}
+	SchemaFuncs    map[string]int            `json:"schema_funcs"`    // {"commonschema.Location": 640}
+	Timeouts       map[string]map[string]int `json:"timeouts"`        // {"create": {"30": 701, "60": 122}}, minutes -> resources
+	SDKFeatures    map[string]int            `json:"sdk_features"`    // {"customize_diff": 120, "modern_sdk_resources": 410}
+}
+
+// SDK features counted by the heatmap, besides the resource capabilities
+const (
+	heatmapLegacyResources   = "legacy_pluginsdk_resources"
+	heatmapModernResources   = "modern_sdk_resources"
+	heatmapLegacyDataSources = "legacy_pluginsdk_data_sources"
+	heatmapModernDataSources = "modern_sdk_data_sources"
+	heatmapEphemerals        = "ephemeral_resources"
+	heatmapCustomizeDiff     = "customize_diff"
+	heatmapStateUpgraders    = "state_upgraders"
+	heatmapDeprecations      = "deprecation_message"
+	heatmapTimeouts          = "timeouts"
+)
+
+// BuildFeatureHeatmap counts attribute types, validators, timeouts and SDK features used by the scanned services
+func (index *TerraformProviderIndex) BuildFeatureHeatmap() FeatureHeatmap {
+	heatmap := FeatureHeatmap{
+		AttributeTypes: make(map[string]int),
+		Validators:     make(map[string]int),
+		SchemaFuncs:    make(map[string]int),
+		Timeouts:       make(map[string]map[string]int),
+		SDKFeatures:    make(map[string]int),
+	}
+
+	for _, service := range index.Services {
+		heatmap.SDKFeatures[heatmapLegacyResources] += len(service.SupportedResources)
+		heatmap.SDKFeatures[heatmapModernResources] += len(service.Resources)
+		heatmap.SDKFeatures[heatmapLegacyDataSources] += len(service.SupportedDataSources)
+		heatmap.SDKFeatures[heatmapModernDataSources] += len(service.DataSources)
+		heatmap.SDKFeatures[heatmapEphemerals] += len(service.EphemeralFunctions)
+		heatmap.SDKFeatures[heatmapCustomizeDiff] += len(service.ResourceCustomizeDiff)
+		heatmap.SDKFeatures[heatmapStateUpgraders] += len(service.ResourceStateUpgrades)
+		heatmap.SDKFeatures[heatmapDeprecations] += len(service.ResourceDeprecations) + len(service.DataSourceDeprecations)
+		heatmap.SDKFeatures[heatmapTimeouts] += len(service.ResourceTimeouts)
+		for _, capabilities := range service.ResourceCapabilities {
+			for _, capability := range capabilities {
+				heatmap.SDKFeatures[capability]++
+			}
+		}
+
+		for _, attributes := range service.ResourceSchemas {
+			for _, attribute := range attributes {
+				if attribute.Type != "" {
+					heatmap.AttributeTypes[attribute.Type]++
+				}
+				if attribute.SchemaFunc != "" {
+					heatmap.SchemaFuncs[attribute.SchemaFunc]++
+				}
+				for _, validateFunc := range attribute.ValidateFuncs {
+					heatmap.Validators[validateFunc]++
+				}
+			}
+		}
+
+		for _, timeouts := range service.ResourceTimeouts {
+			for operation, minutes := range timeouts {
+				if heatmap.Timeouts[operation] == nil {
+					heatmap.Timeouts[operation] = make(map[string]int)
+				}
+				heatmap.Timeouts[operation][strconv.Itoa(minutes)]++
+			}
+		}
+	}
+	return heatmap
+}
+
+// writeFeatureHeatmapFile writes heatmap.json
+func (index *TerraformProviderIndex) writeFeatureHeatmapFile(outputDir string) error {
+	return index.writeJSONFile(filepath.Join(outputDir, "heatmap.json"), index.BuildFeatureHeatmap())
+}
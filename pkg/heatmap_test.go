@@ -0,0 +1,65 @@
+package pkg
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFeatureHeatmapFile(t *testing.T) {
+	index := &TerraformProviderIndex{
+		Services: []ServiceRegistration{
+			{
+				ServiceName:          "keyvault",
+				SupportedResources:   map[string]string{"azurerm_key_vault": "resourceKeyVault"},
+				Resources:            []string{"KeyVaultManagedHardwareSecurityModuleResource"},
+				SupportedDataSources: map[string]string{"azurerm_key_vault": "dataSourceKeyVault"},
+				ResourceCustomizeDiff: map[string][]string{
+					"azurerm_key_vault": {"resourceKeyVaultCustomizeDiff"},
+				},
+				ResourceCapabilities: map[string][]string{
+					"azurerm_key_vault_managed_hardware_security_module": {CapabilityUpdateUnsupported},
+				},
+				ResourceSchemas: map[string][]SchemaAttribute{
+					"azurerm_key_vault": {
+						{Name: "name", Type: "TypeString", ValidateFuncs: []string{"validate.VaultName"}},
+						{Name: "location", SchemaFunc: "commonschema.Location"},
+						{Name: "sku_name", Type: "TypeString", ValidateFuncs: []string{"validation.StringInSlice"}},
+					},
+					"azurerm_key_vault_managed_hardware_security_module": {
+						{Name: "sku_name", Type: "TypeString", ValidateFuncs: []string{"validation.StringInSlice"}},
+					},
+				},
+				ResourceTimeouts: map[string]map[string]int{
+					"azurerm_key_vault": {"create": 30, "delete": 30},
+					"azurerm_key_vault_managed_hardware_security_module": {"create": 60},
+				},
+			},
+		},
+	}
+	fs := afero.NewMemMapFs()
+	index.Output.Fs = fs
+	outputDir := "/test/output"
+
+	require.NoError(t, index.writeFeatureHeatmapFile(outputDir))
+
+	data, err := afero.ReadFile(fs, filepath.Join(outputDir, "heatmap.json"))
+	require.NoError(t, err)
+	var heatmap FeatureHeatmap
+	require.NoError(t, json.Unmarshal(data, &heatmap))
+
+	assert.Equal(t, map[string]int{"TypeString": 3}, heatmap.AttributeTypes)
+	assert.Equal(t, map[string]int{"validate.VaultName": 1, "validation.StringInSlice": 2}, heatmap.Validators)
+	assert.Equal(t, map[string]int{"commonschema.Location": 1}, heatmap.SchemaFuncs)
+	assert.Equal(t, map[string]map[string]int{"create": {"30": 1, "60": 1}, "delete": {"30": 1}}, heatmap.Timeouts)
+	assert.Equal(t, 1, heatmap.SDKFeatures[heatmapLegacyResources])
+	assert.Equal(t, 1, heatmap.SDKFeatures[heatmapModernResources])
+	assert.Equal(t, 1, heatmap.SDKFeatures[heatmapLegacyDataSources])
+	assert.Equal(t, 1, heatmap.SDKFeatures[heatmapCustomizeDiff])
+	assert.Equal(t, 2, heatmap.SDKFeatures[heatmapTimeouts])
+	assert.Equal(t, 1, heatmap.SDKFeatures[CapabilityUpdateUnsupported])
+}
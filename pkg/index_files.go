@@ -0,0 +1,32 @@
+package pkg
+
+import (
+	"path"
+	"path/filepath"
+)
+
+// IndexFile is a per-resource document of a generated index
+type IndexFile struct {
+	Kind          string `json:"kind"`           // "resources", "datasources" or "ephemeral"
+	TerraformType string `json:"terraform_type"` // "azurerm_key_vault"
+	Path          string `json:"path"`           // "resources/azurerm_key_vault.json", relative to the index directory
+}
+
+// BuildFileList lists the documents written to the resources/, datasources/ and ephemeral/ directories, ordered by
+// kind and Terraform type, so the index can be enumerated where it is hosted without directory listings
+func (index *TerraformProviderIndex) BuildFileList() []IndexFile {
+	files := []IndexFile{}
+	for _, document := range index.Documents() {
+		files = append(files, IndexFile{
+			Kind:          document.Kind,
+			TerraformType: document.TerraformType,
+			Path:          path.Join(document.Kind, document.TerraformType+".json"),
+		})
+	}
+	return files
+}
+
+// writeFileListFile writes files.json, the list of the documents of the index
+func (index *TerraformProviderIndex) writeFileListFile(outputDir string) error {
+	return index.writeJSONFile(filepath.Join(outputDir, "files.json"), index.BuildFileList())
+}
@@ -0,0 +1,27 @@
+package pkg
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformProviderIndex_WriteFileListFile(t *testing.T) {
+	dir := writeTestHarnessIndex(t)
+
+	data, err := os.ReadFile(filepath.Join(dir, "files.json"))
+	require.NoError(t, err)
+	var files []IndexFile
+	require.NoError(t, json.Unmarshal(data, &files))
+
+	assert.Contains(t, files, IndexFile{Kind: DocumentKindResource, TerraformType: "azurerm_key_vault", Path: "resources/azurerm_key_vault.json"})
+	assert.Contains(t, files, IndexFile{Kind: DocumentKindDataSource, TerraformType: "azurerm_key_vault", Path: "datasources/azurerm_key_vault.json"})
+	assert.Contains(t, files, IndexFile{Kind: DocumentKindEphemeral, TerraformType: "azurerm_key_vault_secret", Path: "ephemeral/azurerm_key_vault_secret.json"})
+	for _, file := range files {
+		assert.FileExists(t, filepath.Join(dir, filepath.FromSlash(file.Path)))
+	}
+}
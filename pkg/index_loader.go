@@ -0,0 +1,171 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// LoadIndex reads an index directory written with the json format back into a TerraformProviderIndex. The main index
+// file is terraform-provider-azurerm-index.json, or the only terraform-provider-*-index.json file of the directory
+// when written with another provider or index file name. The scan warnings of scan-report.json and the reports of
+// audit/ are loaded when the directory has them. Per-resource documents are loaded on demand with LoadResource and
+// the other per-file loaders.
+func LoadIndex(dir string) (*TerraformProviderIndex, error) {
+	indexFileName, err := mainIndexFileNameOf(dir)
+	if err != nil {
+		return nil, err
+	}
+	index, err := LoadIndexFile(filepath.Join(dir, indexFileName))
+	if err != nil {
+		return nil, err
+	}
+	index.Output.IndexFileName = indexFileName
+
+	var scanReport ScanReport
+	found, err := loadOptionalIndexFile(filepath.Join(dir, "scan-report.json"), &scanReport)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		index.Warnings = scanReport.warnings()
+	}
+
+	audits := []struct {
+		fileName string
+		load     func(path string) error
+	}{
+		{"undocumented.json", loadOptionalReport(&index.Documentation)},
+		{"goindex-references.json", loadOptionalReport(&index.GoIndex)},
+		{"api-drift.json", loadOptionalReport(&index.APIDrift)},
+		{"provider-coverage.json", loadOptionalReport(&index.ProviderCoverage)},
+		{"provider-schema.json", loadOptionalReport(&index.ProviderSchema)},
+	}
+	for _, audit := range audits {
+		if err := audit.load(filepath.Join(dir, "audit", audit.fileName)); err != nil {
+			return nil, err
+		}
+	}
+	return index, nil
+}
+
+// LoadIndexFile reads a main index file, without the reports LoadIndex loads from the rest of its directory
+func LoadIndexFile(filePath string) (*TerraformProviderIndex, error) {
+	index := &TerraformProviderIndex{}
+	if err := readIndexJSONFile(filePath, index); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// LoadResource reads resources/<terraform type>.json of an index directory
+func LoadResource(dir, terraformType string) (*TerraformResource, error) {
+	return loadIndexDocument[TerraformResource](dir, DocumentKindResource, terraformType)
+}
+
+// LoadDataSource reads datasources/<terraform type>.json of an index directory
+func LoadDataSource(dir, terraformType string) (*TerraformDataSource, error) {
+	return loadIndexDocument[TerraformDataSource](dir, DocumentKindDataSource, terraformType)
+}
+
+// LoadEphemeral reads ephemeral/<terraform type>.json of an index directory
+func LoadEphemeral(dir, terraformType string) (*TerraformEphemeral, error) {
+	return loadIndexDocument[TerraformEphemeral](dir, DocumentKindEphemeral, terraformType)
+}
+
+// LoadListResource reads listresources/<terraform type>.json of an index directory
+func LoadListResource(dir, terraformType string) (*TerraformListResource, error) {
+	return loadIndexDocument[TerraformListResource](dir, DocumentKindListResource, terraformType)
+}
+
+// LoadAction reads actions/<terraform type>.json of an index directory
+func LoadAction(dir, terraformType string) (*TerraformAction, error) {
+	return loadIndexDocument[TerraformAction](dir, DocumentKindAction, terraformType)
+}
+
+// LoadAcceptanceTests reads tests/<kind>/<terraform type>.json of an index directory, kind is DocumentKindResource
+// or DocumentKindDataSource
+func LoadAcceptanceTests(dir, kind, terraformType string) (*TerraformAcceptanceTests, error) {
+	return loadIndexDocument[TerraformAcceptanceTests](filepath.Join(dir, "tests"), kind, terraformType)
+}
+
+// LoadServiceSummary reads services/<service name>.json of an index directory written with OutputConfig.ServiceSummaries
+func LoadServiceSummary(dir, serviceName string) (*ServiceSummary, error) {
+	return loadIndexDocument[ServiceSummary](dir, "services", serviceName)
+}
+
+// LoadServiceClients reads clients/<service name>.json of an index directory
+func LoadServiceClients(dir, serviceName string) (*TerraformServiceClients, error) {
+	return loadIndexDocument[TerraformServiceClients](dir, "clients", serviceName)
+}
+
+// loadIndexDocument reads <subDir>/<name>.json of an index directory
+func loadIndexDocument[T any](dir, subDir, name string) (*T, error) {
+	document := new(T)
+	if err := readIndexJSONFile(filepath.Join(dir, subDir, name+".json"), document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// loadOptionalIndexFile reads a JSON file of an index directory like readIndexJSONFile, returning false without an
+// error when the file doesn't exist
+func loadOptionalIndexFile(filePath string, target interface{}) (bool, error) {
+	if err := readIndexJSONFile(filePath, target); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// loadOptionalReport returns a loader setting report to the content of an optional report file, report stays nil when
+// the file doesn't exist
+func loadOptionalReport[T any](report **T) func(path string) error {
+	return func(path string) error {
+		content := new(T)
+		found, err := loadOptionalIndexFile(path, content)
+		if found {
+			*report = content
+		}
+		return err
+	}
+}
+
+// mainIndexFileNameOf returns the name of the main index file of an index directory, the default name when it exists
+// and otherwise the only terraform-provider-*-index.json file of the directory
+func mainIndexFileNameOf(dir string) (string, error) {
+	defaultName := OutputConfig{}.MainIndexFileName()
+	if _, err := os.Stat(filepath.Join(dir, defaultName)); !errors.Is(err, os.ErrNotExist) {
+		return defaultName, err
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, "terraform-provider-*-index.json"))
+	if err != nil {
+		return "", fmt.Errorf("failed to find main index file of %s: %w", dir, err)
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no main index file found in %s", dir)
+	case 1:
+		return filepath.Base(matches[0]), nil
+	}
+	return "", fmt.Errorf("%s has %d main index files, load one with LoadIndexFile", dir, len(matches))
+}
+
+// warnings returns the warnings of the report, sorted by service
+func (r ScanReport) warnings() []ScanWarning {
+	services := make([]string, 0, len(r.Services))
+	for service := range r.Services {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	var warnings []ScanWarning
+	for _, service := range services {
+		warnings = append(warnings, r.Services[service]...)
+	}
+	return warnings
+}
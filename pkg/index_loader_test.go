@@ -0,0 +1,90 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadIndex_GeneratedIndex(t *testing.T) {
+	dir := writeTestHarnessIndex(t)
+
+	index, err := LoadIndex(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "test-version", index.Version)
+	assert.NotEmpty(t, index.Services)
+	assert.Contains(t, index.GlobalMaps.AllResources, "azurerm_key_vault")
+	assert.Equal(t, OutputConfig{}.MainIndexFileName(), index.Output.IndexFileName)
+	assert.Nil(t, index.Documentation)
+
+	resource, err := LoadResource(dir, "azurerm_key_vault")
+	require.NoError(t, err)
+	assert.Equal(t, "azurerm_key_vault", resource.TerraformType)
+	assert.Equal(t, index.GlobalMaps.AllResources["azurerm_key_vault"].ID, resource.ID)
+
+	dataSource, err := LoadDataSource(dir, "azurerm_key_vault")
+	require.NoError(t, err)
+	assert.Equal(t, "azurerm_key_vault", dataSource.TerraformType)
+
+	_, err = LoadResource(dir, "azurerm_nothing")
+	assert.ErrorContains(t, err, "failed to read")
+}
+
+func TestLoadIndex_Reports(t *testing.T) {
+	dir := t.TempDir()
+	index := &TerraformProviderIndex{
+		Version:       "v4.25.0",
+		Output:        OutputConfig{ProviderName: "azapi"},
+		Documentation: &DocumentationReport{DocsPath: "website/docs", Undocumented: []UndocumentedEntry{{Kind: DocumentKindResource, TerraformType: "azurerm_key_vault"}}},
+		Warnings: []ScanWarning{
+			{Service: "storage", File: "registration.go", Kind: ScanWarningUnresolvedRegistration, Message: "unresolved"},
+			{Service: "keyvault", Kind: ScanWarningPanic, Message: "panicked"},
+		},
+	}
+	require.NoError(t, index.writeMainIndexFile(dir))
+	require.NoError(t, index.writeScanReportFile(dir))
+	require.NoError(t, index.writeDocumentationReportFile(dir))
+
+	loaded, err := LoadIndex(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "v4.25.0", loaded.Version)
+	assert.Equal(t, "terraform-provider-azapi-index.json", loaded.Output.IndexFileName)
+	assert.Equal(t, index.Documentation, loaded.Documentation)
+	assert.Equal(t, []ScanWarning{index.Warnings[1], index.Warnings[0]}, loaded.Warnings)
+	assert.Nil(t, loaded.APIDrift)
+}
+
+func TestLoadIndex_NoMainIndexFile(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := LoadIndex(dir)
+	assert.ErrorContains(t, err, "no main index file found in "+dir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "terraform-provider-a-index.json"), []byte(`{}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "terraform-provider-b-index.json"), []byte(`{}`), 0644))
+	_, err = LoadIndex(dir)
+	assert.ErrorContains(t, err, "has 2 main index files")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "terraform-provider-azurerm-index.json"), []byte(`{`), 0644))
+	_, err = LoadIndex(dir)
+	assert.ErrorContains(t, err, "failed to parse")
+}
+
+func TestLoadServiceClients(t *testing.T) {
+	dir := t.TempDir()
+	index := &TerraformProviderIndex{Services: []ServiceRegistration{{
+		ServiceName: "keyvault",
+		ClientFile:  "internal/services/keyvault/client/client.go",
+		Clients:     []ServiceClient{{Field: "VaultsClient", Type: "*vaults.VaultsClient"}},
+	}}}
+	require.NoError(t, index.writeServiceClientFiles(dir, NewProgressTracker("test", 1, nil)))
+
+	clients, err := LoadServiceClients(dir, "keyvault")
+	require.NoError(t, err)
+	assert.Equal(t, &index.BuildServiceClients()[0], clients)
+}
@@ -0,0 +1,149 @@
+package pkg
+
+import "sort"
+
+// indexLookup maps Terraform types, struct types and service names to the registrations of an index, so lookups
+// don't walk every service
+type indexLookup struct {
+	services map[string]int         // "keyvault" -> position in Services
+	mappings GlobalMappings         // Terraform type -> registration, per kind
+	structs  map[string][]lookupKey // "KeyVaultResource" or "resourceKeyVault" -> documents registered with it
+}
+
+// lookupKey identifies a document of the index
+type lookupKey struct {
+	kind          string // "resources"
+	terraformType string // "azurerm_key_vault"
+}
+
+// buildLookup rebuilds the lookup maps from the services of the index, it is called whenever the services change
+func (index *TerraformProviderIndex) buildLookup() {
+	lookup := &indexLookup{
+		services: make(map[string]int, len(index.Services)),
+		mappings: index.BuildGlobalMappings(),
+		structs:  make(map[string][]lookupKey),
+	}
+	for i, service := range index.Services {
+		lookup.services[service.ServiceName] = i
+	}
+	for _, kind := range documentKinds {
+		for terraformType, entry := range lookup.mappings.mappingsOf(kind.kind) {
+			name := entry.StructType
+			if name == "" {
+				name = entry.RegistrationMethod
+			}
+			lookup.structs[name] = append(lookup.structs[name], lookupKey{kind: kind.kind, terraformType: terraformType})
+		}
+	}
+	for _, keys := range lookup.structs {
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].kind != keys[j].kind {
+				return keys[i].kind < keys[j].kind
+			}
+			return keys[i].terraformType < keys[j].terraformType
+		})
+	}
+	index.lookup = lookup
+}
+
+// lookupMaps returns the lookup maps of the index, building them on first use for indexes that weren't returned by
+// a scan
+func (index *TerraformProviderIndex) lookupMaps() *indexLookup {
+	if index.lookup == nil {
+		index.buildLookup()
+	}
+	return index.lookup
+}
+
+// LookupService returns the registration of a service by name
+func (index *TerraformProviderIndex) LookupService(serviceName string) (*ServiceRegistration, bool) {
+	i, ok := index.lookupMaps().services[serviceName]
+	if !ok {
+		return nil, false
+	}
+	return &index.Services[i], true
+}
+
+// LookupDocument builds the document of a kind and Terraform type, the same document written to
+// <kind>/<terraform type>.json
+func (index *TerraformProviderIndex) LookupDocument(kind, terraformType string) (IndexDocument, bool) {
+	lookup := index.lookupMaps()
+	entry, ok := lookup.mappings.mappingsOf(kind)[terraformType]
+	if !ok {
+		return IndexDocument{}, false
+	}
+	i, ok := lookup.services[entry.Service]
+	if !ok {
+		return IndexDocument{}, false
+	}
+	service := index.Services[i]
+
+	document := IndexDocument{ID: entry.ID, Kind: kind, TerraformType: terraformType, Service: entry.Service}
+	switch kind {
+	case DocumentKindResource:
+		document.Content = newTerraformResourceInfo(terraformType, entry.StructType, entry.RegistrationMethod, entry.SDKType, service)
+	case DocumentKindDataSource:
+		document.Content = newTerraformDataSourceInfo(terraformType, entry.StructType, entry.RegistrationMethod, entry.SDKType, service)
+	case DocumentKindEphemeral:
+		document.Content = newTerraformEphemeralInfo(entry.StructType, service)
+	case DocumentKindListResource:
+		document.Content = newTerraformListResourceInfo(entry.StructType, service)
+	case DocumentKindAction:
+		document.Content = newTerraformActionInfo(entry.StructType, service)
+	}
+	if !index.Hooks.discover(&document) {
+		return IndexDocument{}, false
+	}
+	return document, true
+}
+
+// LookupResource builds the document of a resource by Terraform type
+func (index *TerraformProviderIndex) LookupResource(terraformType string) (*TerraformResource, bool) {
+	document, ok := index.LookupDocument(DocumentKindResource, terraformType)
+	if !ok {
+		return nil, false
+	}
+	resource := document.Content.(TerraformResource)
+	return &resource, true
+}
+
+// LookupDataSource builds the document of a data source by Terraform type
+func (index *TerraformProviderIndex) LookupDataSource(terraformType string) (*TerraformDataSource, bool) {
+	document, ok := index.LookupDocument(DocumentKindDataSource, terraformType)
+	if !ok {
+		return nil, false
+	}
+	dataSource := document.Content.(TerraformDataSource)
+	return &dataSource, true
+}
+
+// LookupByStruct builds the documents registered with a struct type, or with a registration function for legacy
+// registrations, ordered by kind and Terraform type. Services declaring structs of the same name all match.
+func (index *TerraformProviderIndex) LookupByStruct(structName string) []IndexDocument {
+	var documents []IndexDocument
+	for _, key := range index.lookupMaps().structs[structName] {
+		if document, ok := index.LookupDocument(key.kind, key.terraformType); ok {
+			documents = append(documents, document)
+		}
+	}
+	return documents
+}
+
+// ResourcesByService builds the resource documents of a service, ordered by Terraform type
+func (index *TerraformProviderIndex) ResourcesByService(serviceName string) []TerraformResource {
+	service, ok := index.LookupService(serviceName)
+	if !ok {
+		return nil
+	}
+	var resources []TerraformResource
+	resourceDocuments(*service, func(document IndexDocument) bool {
+		if index.Hooks.discover(&document) {
+			resources = append(resources, document.Content.(TerraformResource))
+		}
+		return true
+	})
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].TerraformType < resources[j].TerraformType
+	})
+	return resources
+}
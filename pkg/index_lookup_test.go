@@ -0,0 +1,90 @@
+package pkg
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scanLookupTestHarness(t *testing.T) *TerraformProviderIndex {
+	index, err := ScanTerraformProviderServices(filepath.Join("testharness", "internal", "services"), "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+	require.NoError(t, err)
+	require.NotNil(t, index.lookup)
+	return index
+}
+
+func TestLookupResource(t *testing.T) {
+	index := scanLookupTestHarness(t)
+
+	resource, ok := index.LookupResource("azurerm_key_vault")
+	require.True(t, ok)
+	assert.Equal(t, "azurerm_key_vault", resource.TerraformType)
+	assert.Equal(t, "resourceKeyVault", resource.RegistrationMethod)
+
+	resource, ok = index.LookupResource("azurerm_account")
+	require.True(t, ok)
+	assert.Equal(t, "AccountResource", resource.StructType)
+
+	dataSource, ok := index.LookupDataSource("azurerm_key_vault")
+	require.True(t, ok)
+	assert.Equal(t, "dataSourceKeyVault", dataSource.RegistrationMethod)
+
+	_, ok = index.LookupResource("azurerm_nothing")
+	assert.False(t, ok)
+}
+
+func TestLookupDocument_MatchesDocuments(t *testing.T) {
+	index := scanLookupTestHarness(t)
+
+	for _, document := range index.Documents() {
+		looked, ok := index.LookupDocument(document.Kind, document.TerraformType)
+		require.True(t, ok, document.ID)
+		assert.Equal(t, document, looked)
+	}
+}
+
+func TestLookupByStruct(t *testing.T) {
+	index := scanLookupTestHarness(t)
+
+	documents := index.LookupByStruct("AccountResource")
+	require.Len(t, documents, 1)
+	assert.Equal(t, DocumentKindResource, documents[0].Kind)
+	assert.Equal(t, "azurerm_account", documents[0].TerraformType)
+
+	documents = index.LookupByStruct("resourceKeyVault")
+	require.Len(t, documents, 1)
+	assert.Equal(t, "azurerm_key_vault", documents[0].TerraformType)
+
+	assert.Empty(t, index.LookupByStruct("MissingResource"))
+}
+
+func TestResourcesByService(t *testing.T) {
+	index := scanLookupTestHarness(t)
+
+	var terraformTypes []string
+	for _, resource := range index.ResourcesByService("keyvault") {
+		terraformTypes = append(terraformTypes, resource.TerraformType)
+	}
+	assert.Contains(t, terraformTypes, "azurerm_key_vault")
+	assert.IsIncreasing(t, terraformTypes)
+
+	assert.Nil(t, index.ResourcesByService("missing"))
+}
+
+func TestLookupResource_BuildsLookupOfUnscannedIndex(t *testing.T) {
+	index := &TerraformProviderIndex{Services: []ServiceRegistration{{
+		ServiceName:        "keyvault",
+		PackagePath:        "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault",
+		SupportedResources: map[string]string{"azurerm_key_vault": "resourceKeyVault"},
+	}}}
+
+	resource, ok := index.LookupResource("azurerm_key_vault")
+	require.True(t, ok)
+	assert.Equal(t, "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault", resource.Namespace)
+
+	service, ok := index.LookupService("keyvault")
+	require.True(t, ok)
+	assert.Same(t, &index.Services[0], service)
+}
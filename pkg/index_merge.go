@@ -0,0 +1,444 @@
+package pkg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// MergeConflict is a Terraform type or service found in more than one index being merged
+type MergeConflict struct {
+	Kind string   // "resources", "datasources", "ephemeral" or "services"
+	Name string   // "azurerm_key_vault" or "keyvault"
+	Dirs []string // Index directories containing it, in merge order
+}
+
+// MergeConflictsError is returned by MergeIndexDirs when the indexes overlap, nothing is written then
+type MergeConflictsError struct {
+	Conflicts []MergeConflict // Sorted by kind and name
+}
+
+func (e *MergeConflictsError) Error() string {
+	descriptions := make([]string, 0, len(e.Conflicts))
+	for _, conflict := range e.Conflicts {
+		descriptions = append(descriptions, fmt.Sprintf("%s %s in %s", conflict.Kind, conflict.Name, strings.Join(conflict.Dirs, ", ")))
+	}
+	return fmt.Sprintf("%d conflicts between merged indexes: %s", len(e.Conflicts), strings.Join(descriptions, "; "))
+}
+
+// MergeIndexDirs merges partial indexes generated with the JSON format, for example per-service shards generated on
+// different CI workers, into one index in outputDir. All indexes must have the same version and main index file name,
+// set by output. A service or Terraform type found in more than one index is a conflict, reported as a
+// *MergeConflictsError before anything is written. Documents are copied as is and the summary files are combined.
+func MergeIndexDirs(dirs []string, outputDir string, output OutputConfig) (*TerraformProviderIndex, error) {
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no index directories to merge")
+	}
+
+	merged := &TerraformProviderIndex{Services: []ServiceRegistration{}, GlobalMaps: newGlobalMappings(), Output: output}
+	owners := make(map[string]map[string][]string) // kind -> Terraform type or service -> directories
+	claim := func(kind, name, dir string) {
+		if owners[kind] == nil {
+			owners[kind] = make(map[string][]string)
+		}
+		owners[kind][name] = append(owners[kind][name], dir)
+	}
+
+	for i, dir := range dirs {
+		index := &TerraformProviderIndex{}
+		if err := readIndexJSONFile(filepath.Join(dir, output.MainIndexFileName()), index); err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			merged.Version = index.Version
+			merged.Toolchain = index.Toolchain
+		} else if index.Version != merged.Version {
+			return nil, fmt.Errorf("index %s has version %s, %s has version %s", dir, index.Version, dirs[0], merged.Version)
+		}
+
+		for _, service := range index.Services {
+			claim("services", service.ServiceName, dir)
+		}
+		merged.Services = append(merged.Services, index.Services...)
+		for kind, mappings := range map[string]struct{ from, to map[string]GlobalMappingEntry }{
+			DocumentKindResource:     {index.GlobalMaps.AllResources, merged.GlobalMaps.AllResources},
+			DocumentKindDataSource:   {index.GlobalMaps.AllDataSources, merged.GlobalMaps.AllDataSources},
+			DocumentKindEphemeral:    {index.GlobalMaps.AllEphemeral, merged.GlobalMaps.AllEphemeral},
+			DocumentKindListResource: {index.GlobalMaps.AllListResources, merged.GlobalMaps.AllListResources},
+			DocumentKindAction:       {index.GlobalMaps.AllActions, merged.GlobalMaps.AllActions},
+		} {
+			for terraformType, entry := range mappings.from {
+				claim(kind, terraformType, dir)
+				mappings.to[terraformType] = entry
+			}
+		}
+	}
+
+	var conflicts []MergeConflict
+	for kind, names := range owners {
+		for name, owningDirs := range names {
+			if len(owningDirs) > 1 {
+				conflicts = append(conflicts, MergeConflict{Kind: kind, Name: name, Dirs: owningDirs})
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Slice(conflicts, func(i, j int) bool {
+			if conflicts[i].Kind != conflicts[j].Kind {
+				return conflicts[i].Kind < conflicts[j].Kind
+			}
+			return conflicts[i].Name < conflicts[j].Name
+		})
+		return nil, &MergeConflictsError{Conflicts: conflicts}
+	}
+
+	sort.Slice(merged.Services, func(i, j int) bool {
+		return merged.Services[i].ServiceName < merged.Services[j].ServiceName
+	})
+	merged.Statistics = buildProviderStatistics(merged.Services)
+	merged.RemovedInNextMajor = merged.BuildRemovedInNextMajor()
+
+	if err := merged.createDirectoryStructure(outputDir); err != nil {
+		return nil, fmt.Errorf("failed to create directory structure: %w", err)
+	}
+	if err := merged.writeMainIndexFile(outputDir); err != nil {
+		return nil, fmt.Errorf("failed to write main index file: %w", err)
+	}
+	for _, subDir := range []string{
+		DocumentKindResource,
+		DocumentKindDataSource,
+		DocumentKindEphemeral,
+		DocumentKindListResource,
+		DocumentKindAction,
+		filepath.Join("tests", DocumentKindResource),
+		filepath.Join("tests", DocumentKindDataSource),
+		"services",
+		"clients",
+	} {
+		for _, dir := range dirs {
+			if err := copyIndexFiles(output.fs(), filepath.Join(dir, subDir), filepath.Join(outputDir, subDir)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := merged.writeMergedSummaryFiles(dirs, outputDir); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// writeMergedSummaryFiles combines the cross-reference, audit and report files of the merged indexes
+func (index *TerraformProviderIndex) writeMergedSummaryFiles(dirs []string, outputDir string) error {
+	validations := make(map[string][]ValidationReference)
+	writeOnly := []WriteOnlyReference{}
+	forceNew := []ForceNewReference{}
+	sensitive := []SensitiveReference{}
+	sdkConsumers := make(map[string][]string)
+	heatmap := FeatureHeatmap{
+		AttributeTypes: make(map[string]int),
+		Validators:     make(map[string]int),
+		SchemaFuncs:    make(map[string]int),
+		Timeouts:       make(map[string]map[string]int),
+		SDKFeatures:    make(map[string]int),
+	}
+	unreferenced := []UnreferencedFunction{}
+	orphans := []OrphanedImplementation{}
+	duplicates := []DuplicateRegistration{}
+	scanReport := ScanReport{Services: make(map[string][]ScanWarning)}
+	var documentation *DocumentationReport
+	var goIndex *GoIndexReport
+	var apiDrift *APIDriftReport
+	var providerCoverage *ProviderCoverageReport
+	var providerSchemas []ProviderSchemaReport
+
+	for _, dir := range dirs {
+		var shardValidations map[string][]ValidationReference
+		if err := readIndexJSONFile(filepath.Join(dir, "validations.json"), &shardValidations); err != nil {
+			return err
+		}
+		for validateFunc, references := range shardValidations {
+			validations[validateFunc] = append(validations[validateFunc], references...)
+		}
+
+		var shardWriteOnly []WriteOnlyReference
+		if err := readIndexJSONFile(filepath.Join(dir, "write_only_attributes.json"), &shardWriteOnly); err != nil {
+			return err
+		}
+		writeOnly = append(writeOnly, shardWriteOnly...)
+
+		var shardForceNew []ForceNewReference
+		if err := readIndexJSONFile(filepath.Join(dir, "force_new.json"), &shardForceNew); err != nil {
+			return err
+		}
+		forceNew = append(forceNew, shardForceNew...)
+
+		var shardSensitive []SensitiveReference
+		if err := readIndexJSONFile(filepath.Join(dir, "sensitive_attributes.json"), &shardSensitive); err != nil {
+			return err
+		}
+		sensitive = append(sensitive, shardSensitive...)
+
+		var shardSDKConsumers map[string][]string
+		if err := readIndexJSONFile(filepath.Join(dir, "sdk_api_versions.json"), &shardSDKConsumers); err != nil {
+			return err
+		}
+		for apiVersion, terraformTypes := range shardSDKConsumers {
+			sdkConsumers[apiVersion] = append(sdkConsumers[apiVersion], terraformTypes...)
+		}
+
+		var shardHeatmap FeatureHeatmap
+		if err := readIndexJSONFile(filepath.Join(dir, "heatmap.json"), &shardHeatmap); err != nil {
+			return err
+		}
+		addCounts(heatmap.AttributeTypes, shardHeatmap.AttributeTypes)
+		addCounts(heatmap.Validators, shardHeatmap.Validators)
+		addCounts(heatmap.SchemaFuncs, shardHeatmap.SchemaFuncs)
+		addCounts(heatmap.SDKFeatures, shardHeatmap.SDKFeatures)
+		for operation, counts := range shardHeatmap.Timeouts {
+			if heatmap.Timeouts[operation] == nil {
+				heatmap.Timeouts[operation] = make(map[string]int)
+			}
+			addCounts(heatmap.Timeouts[operation], counts)
+		}
+
+		var shardUnreferenced []UnreferencedFunction
+		if err := readIndexJSONFile(filepath.Join(dir, "audit", "unreferenced-functions.json"), &shardUnreferenced); err != nil {
+			return err
+		}
+		unreferenced = append(unreferenced, shardUnreferenced...)
+
+		var shardOrphans []OrphanedImplementation
+		if err := readIndexJSONFile(filepath.Join(dir, "audit", "orphans.json"), &shardOrphans); err != nil {
+			return err
+		}
+		orphans = append(orphans, shardOrphans...)
+
+		var shardDuplicates []DuplicateRegistration
+		if err := readIndexJSONFile(filepath.Join(dir, "audit", "duplicates.json"), &shardDuplicates); err != nil {
+			return err
+		}
+		duplicates = append(duplicates, shardDuplicates...)
+
+		var shardScanReport ScanReport
+		if err := readIndexJSONFile(filepath.Join(dir, "scan-report.json"), &shardScanReport); err != nil {
+			return err
+		}
+		scanReport.WarningCount += shardScanReport.WarningCount
+		for service, warnings := range shardScanReport.Services {
+			scanReport.Services[service] = append(scanReport.Services[service], warnings...)
+		}
+
+		// Documentation is only linked when the shard was generated with -docs-path
+		undocumentedPath := filepath.Join(dir, "audit", "undocumented.json")
+		if fileExists(undocumentedPath) {
+			var shardDocumentation DocumentationReport
+			if err := readIndexJSONFile(undocumentedPath, &shardDocumentation); err != nil {
+				return err
+			}
+			if documentation == nil {
+				documentation = &DocumentationReport{DocsPath: shardDocumentation.DocsPath, Undocumented: []UndocumentedEntry{}}
+			}
+			documentation.Undocumented = append(documentation.Undocumented, shardDocumentation.Undocumented...)
+		}
+
+		// goindex references are only verified when the shard was generated with -goindex-dir
+		goIndexPath := filepath.Join(dir, "audit", "goindex-references.json")
+		if fileExists(goIndexPath) {
+			var shardGoIndex GoIndexReport
+			if err := readIndexJSONFile(goIndexPath, &shardGoIndex); err != nil {
+				return err
+			}
+			if goIndex == nil {
+				goIndex = &GoIndexReport{GoIndexDir: shardGoIndex.GoIndexDir, Corrected: []GoIndexReference{}, Broken: []GoIndexReference{}}
+			}
+			goIndex.Checked += shardGoIndex.Checked
+			goIndex.Corrected = append(goIndex.Corrected, shardGoIndex.Corrected...)
+			goIndex.Broken = append(goIndex.Broken, shardGoIndex.Broken...)
+		}
+
+		// API drift is only detected when the shard was generated with -api-specs
+		apiDriftPath := filepath.Join(dir, "audit", "api-drift.json")
+		if fileExists(apiDriftPath) {
+			var shardAPIDrift APIDriftReport
+			if err := readIndexJSONFile(apiDriftPath, &shardAPIDrift); err != nil {
+				return err
+			}
+			if apiDrift == nil {
+				apiDrift = &APIDriftReport{SpecsDir: shardAPIDrift.SpecsDir, NoSpec: []string{}, Drifts: []APIDrift{}}
+			}
+			apiDrift.Checked += shardAPIDrift.Checked
+			apiDrift.NoSpec = append(apiDrift.NoSpec, shardAPIDrift.NoSpec...)
+			apiDrift.Drifts = append(apiDrift.Drifts, shardAPIDrift.Drifts...)
+		}
+
+		// Provider coverage is only checked when the shard was generated with -provider-path
+		providerCoveragePath := filepath.Join(dir, "audit", "provider-coverage.json")
+		if fileExists(providerCoveragePath) {
+			var shardProviderCoverage ProviderCoverageReport
+			if err := readIndexJSONFile(providerCoveragePath, &shardProviderCoverage); err != nil {
+				return err
+			}
+			if providerCoverage == nil {
+				providerCoverage = &ProviderCoverageReport{ProviderPath: shardProviderCoverage.ProviderPath}
+			}
+			providerCoverage.Typed = append(providerCoverage.Typed, shardProviderCoverage.Typed...)
+			providerCoverage.Untyped = append(providerCoverage.Untyped, shardProviderCoverage.Untyped...)
+		}
+
+		// The provider schema is only reconciled when the shard was generated with -provider-schema
+		providerSchemaPath := filepath.Join(dir, "audit", "provider-schema.json")
+		if fileExists(providerSchemaPath) {
+			var shardProviderSchema ProviderSchemaReport
+			if err := readIndexJSONFile(providerSchemaPath, &shardProviderSchema); err != nil {
+				return err
+			}
+			providerSchemas = append(providerSchemas, shardProviderSchema)
+		}
+	}
+
+	// Combined entries are sorted the way the index writes them
+	for _, references := range validations {
+		sort.Slice(references, func(i, j int) bool {
+			if references[i].TerraformType != references[j].TerraformType {
+				return references[i].TerraformType < references[j].TerraformType
+			}
+			return references[i].Attribute < references[j].Attribute
+		})
+	}
+	sort.Slice(writeOnly, func(i, j int) bool {
+		return writeOnly[i].TerraformType < writeOnly[j].TerraformType
+	})
+	sort.Slice(forceNew, func(i, j int) bool {
+		return forceNew[i].TerraformType < forceNew[j].TerraformType
+	})
+	sort.Slice(sensitive, func(i, j int) bool {
+		return sensitive[i].TerraformType < sensitive[j].TerraformType
+	})
+	for apiVersion, terraformTypes := range sdkConsumers {
+		sdkConsumers[apiVersion] = uniqueSortedStrings(terraformTypes)
+	}
+	sort.Slice(unreferenced, func(i, j int) bool {
+		if unreferenced[i].Service != unreferenced[j].Service {
+			return unreferenced[i].Service < unreferenced[j].Service
+		}
+		return unreferenced[i].Function < unreferenced[j].Function
+	})
+	sortOrphanedImplementations(orphans)
+	sortDuplicateRegistrations(duplicates)
+
+	files := map[string]interface{}{
+		"validations.json":           validations,
+		"write_only_attributes.json": writeOnly,
+		"force_new.json":             forceNew,
+		"sensitive_attributes.json":  sensitive,
+		"sdk_api_versions.json":      sdkConsumers,
+		"api_version_usage.json":     buildAPIVersionReport(sdkConsumers),
+		"heatmap.json":               heatmap,
+		filepath.Join("audit", "unreferenced-functions.json"): unreferenced,
+		filepath.Join("audit", "orphans.json"):                orphans,
+		filepath.Join("audit", "duplicates.json"):             duplicates,
+		"scan-report.json":                                    scanReport,
+		"files.json":                                          index.BuildFileList(),
+	}
+	if documentation != nil {
+		sort.Slice(documentation.Undocumented, func(i, j int) bool {
+			if documentation.Undocumented[i].Kind != documentation.Undocumented[j].Kind {
+				return documentation.Undocumented[i].Kind < documentation.Undocumented[j].Kind
+			}
+			return documentation.Undocumented[i].TerraformType < documentation.Undocumented[j].TerraformType
+		})
+		files[filepath.Join("audit", "undocumented.json")] = documentation
+	}
+	if goIndex != nil {
+		sortGoIndexReferences(goIndex.Corrected)
+		sortGoIndexReferences(goIndex.Broken)
+		files[filepath.Join("audit", "goindex-references.json")] = goIndex
+	}
+	if apiDrift != nil {
+		sort.Strings(apiDrift.NoSpec)
+		sortAPIDrifts(apiDrift.Drifts)
+		files[filepath.Join("audit", "api-drift.json")] = apiDrift
+	}
+	if providerCoverage != nil {
+		// Each shard compared the provider with its own services only, so the merged services are compared again
+		providerCoverage.Typed = uniqueSortedStrings(providerCoverage.Typed)
+		providerCoverage.Untyped = uniqueSortedStrings(providerCoverage.Untyped)
+		index.compareProviderCoverage(providerCoverage)
+		files[filepath.Join("audit", "provider-coverage.json")] = providerCoverage
+	}
+	if providerSchema := mergeProviderSchemaReports(providerSchemas); providerSchema != nil {
+		files[filepath.Join("audit", "provider-schema.json")] = providerSchema
+	}
+	for fileName, content := range files {
+		if err := index.writeJSONFile(filepath.Join(outputDir, fileName), content); err != nil {
+			return err
+		}
+	}
+
+	// The report is only written when a shard was generated with -report, without comparing with an earlier version
+	for _, dir := range dirs {
+		if fileExists(filepath.Join(dir, MarkdownReportFileName)) {
+			return index.writeMarkdownReportFile(outputDir)
+		}
+	}
+	return nil
+}
+
+// addCounts adds the counts of from to to
+func addCounts(to, from map[string]int) {
+	for key, count := range from {
+		to[key] += count
+	}
+}
+
+// readIndexJSONFile decodes a JSON file of a generated index
+func readIndexJSONFile(filePath string, target interface{}) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+	return nil
+}
+
+// fileExists reports whether a file of a generated index exists
+func fileExists(filePath string) bool {
+	_, err := os.Stat(filePath)
+	return err == nil
+}
+
+// copyIndexFiles copies the JSON files of an index subdirectory to fs, a missing source directory has nothing to copy
+func copyIndexFiles(fs afero.Fs, sourceDir, targetDir string) error {
+	entries, err := os.ReadDir(sourceDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read directory %s: %w", sourceDir, err)
+	}
+	if err := fs.MkdirAll(targetDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", targetDir, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(sourceDir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+		if err := afero.WriteFile(fs, filepath.Join(targetDir, entry.Name()), data, 0644); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
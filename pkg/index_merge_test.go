@@ -0,0 +1,81 @@
+package pkg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestHarnessShard writes the index of some test harness services, as a CI worker generating a shard would
+func writeTestHarnessShard(t *testing.T, outputDir string, services ...string) *TerraformProviderIndex {
+	index, err := Scanner{Services: services}.Scan(filepath.Join("testharness", "internal", "services"), "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+	require.NoError(t, err)
+	require.NoError(t, index.WriteIndexFiles(outputDir, nil))
+	return index
+}
+
+func TestMergeIndexDirs(t *testing.T) {
+	dir := t.TempDir()
+	shard1, shard2, mergedDir := filepath.Join(dir, "shard-1"), filepath.Join(dir, "shard-2"), filepath.Join(dir, "merged")
+	full := writeTestHarnessShard(t, filepath.Join(dir, "full"))
+	writeTestHarnessShard(t, shard1, "compute", "keyvault")
+	writeTestHarnessShard(t, shard2, "resource", "storage")
+
+	merged, err := MergeIndexDirs([]string{shard1, shard2}, mergedDir, OutputConfig{})
+	require.NoError(t, err)
+
+	assert.Equal(t, full.Statistics, merged.Statistics)
+	assert.Equal(t, full.GlobalMaps, merged.GlobalMaps)
+	report, err := ValidateIndexDir(mergedDir, "")
+	require.NoError(t, err)
+	assert.True(t, report.Valid(), "%v", report.Problems)
+
+	// Documents are copied and summaries combined into the files a single run writes
+	for _, file := range []string{
+		"resources/azurerm_key_vault.json",
+		"resources/azurerm_storage_account.json",
+		"validations.json",
+		"write_only_attributes.json",
+		"sdk_api_versions.json",
+		"heatmap.json",
+		"scan-report.json",
+		"files.json",
+	} {
+		expected, err := os.ReadFile(filepath.Join(dir, "full", file))
+		require.NoError(t, err)
+		actual, err := os.ReadFile(filepath.Join(mergedDir, file))
+		require.NoError(t, err)
+		assert.JSONEq(t, string(expected), string(actual), file)
+	}
+}
+
+func TestMergeIndexDirs_Conflicts(t *testing.T) {
+	dir := t.TempDir()
+	shard1, shard2, mergedDir := filepath.Join(dir, "shard-1"), filepath.Join(dir, "shard-2"), filepath.Join(dir, "merged")
+	writeTestHarnessShard(t, shard1, "keyvault")
+	writeTestHarnessShard(t, shard2, "keyvault")
+
+	_, err := MergeIndexDirs([]string{shard1, shard2}, mergedDir, OutputConfig{})
+
+	var conflictsErr *MergeConflictsError
+	require.True(t, errors.As(err, &conflictsErr), "%v", err)
+	assert.Contains(t, conflictsErr.Conflicts, MergeConflict{Kind: DocumentKindResource, Name: "azurerm_key_vault", Dirs: []string{shard1, shard2}})
+	assert.Contains(t, conflictsErr.Conflicts, MergeConflict{Kind: "services", Name: "keyvault", Dirs: []string{shard1, shard2}})
+	assert.NoDirExists(t, mergedDir)
+}
+
+func TestMergeIndexDirs_VersionMismatch(t *testing.T) {
+	dir := t.TempDir()
+	shard1, shard2, mergedDir := filepath.Join(dir, "shard-1"), filepath.Join(dir, "shard-2"), filepath.Join(dir, "merged")
+	writeTestHarnessShard(t, shard1, "keyvault")
+	other := writeTestHarnessShard(t, shard2, "storage")
+	other.Version = "other-version"
+	require.NoError(t, other.writeMainIndexFile(shard2))
+
+	_, err := MergeIndexDirs([]string{shard1, shard2}, mergedDir, OutputConfig{})
+	assert.ErrorContains(t, err, "other-version")
+}
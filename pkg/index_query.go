@@ -0,0 +1,93 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxQuerySuggestions limits the Terraform types suggested when a query matches nothing
+const maxQuerySuggestions = 10
+
+// QueryResult is a document of a generated index matching a query
+type QueryResult struct {
+	Kind          string          `json:"kind"`           // "resources", "datasources" or "ephemeral"
+	TerraformType string          `json:"terraform_type"` // "azurerm_key_vault"
+	Document      json.RawMessage `json:"document"`       // Content of resources/azurerm_key_vault.json
+}
+
+// QueryIndexDir looks up the documents of an index directory generated with the JSON format by Terraform type, struct
+// type, legacy registration function or entry ID, using the global maps of the main index. A resource and a data
+// source of the same Terraform type both match, ordered by kind. indexFileName defaults to the azurerm main index
+// file name. When nothing matches, the error suggests Terraform types containing the name.
+func QueryIndexDir(dir, indexFileName, name string) ([]QueryResult, error) {
+	if indexFileName == "" {
+		indexFileName = OutputConfig{}.MainIndexFileName()
+	}
+	index := &TerraformProviderIndex{}
+	if err := readIndexJSONFile(filepath.Join(dir, indexFileName), index); err != nil {
+		return nil, err
+	}
+	globalMaps := index.GlobalMaps
+	if len(globalMaps.AllResources)+len(globalMaps.AllDataSources)+len(globalMaps.AllEphemeral)+len(globalMaps.AllListResources)+len(globalMaps.AllActions) == 0 {
+		// Indexes generated before the global maps were written still list the registrations of every service
+		globalMaps = index.BuildGlobalMappings()
+	}
+
+	var results []QueryResult
+	var suggestions []string
+	for _, kind := range []string{DocumentKindResource, DocumentKindDataSource, DocumentKindEphemeral, DocumentKindListResource, DocumentKindAction} {
+		mappings := globalMaps.mappingsOf(kind)
+		terraformTypes := make([]string, 0, len(mappings))
+		for terraformType := range mappings {
+			terraformTypes = append(terraformTypes, terraformType)
+		}
+		sort.Strings(terraformTypes)
+
+		for _, terraformType := range terraformTypes {
+			entry := mappings[terraformType]
+			if name != terraformType && name != entry.StructType && name != entry.RegistrationMethod && name != entry.ID {
+				if strings.Contains(terraformType, name) {
+					suggestions = append(suggestions, terraformType)
+				}
+				continue
+			}
+			document, err := os.ReadFile(filepath.Join(dir, kind, terraformType+".json"))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read document of %s: %w", terraformType, err)
+			}
+			results = append(results, QueryResult{Kind: kind, TerraformType: terraformType, Document: document})
+		}
+	}
+
+	if len(results) == 0 {
+		suggestions = uniqueSortedStrings(suggestions)
+		if len(suggestions) == 0 {
+			return nil, fmt.Errorf("%s is not a Terraform type, struct type, registration function or entry ID of the index", name)
+		}
+		if len(suggestions) > maxQuerySuggestions {
+			suggestions = suggestions[:maxQuerySuggestions]
+		}
+		return nil, fmt.Errorf("%s not found in the index, did you mean: %s", name, strings.Join(suggestions, ", "))
+	}
+	return results, nil
+}
+
+// mappingsOf returns the global map of a document kind
+func (m GlobalMappings) mappingsOf(kind string) map[string]GlobalMappingEntry {
+	switch kind {
+	case DocumentKindResource:
+		return m.AllResources
+	case DocumentKindDataSource:
+		return m.AllDataSources
+	case DocumentKindListResource:
+		return m.AllListResources
+	case DocumentKindAction:
+		return m.AllActions
+	default:
+		return m.AllEphemeral
+	}
+}
@@ -0,0 +1,59 @@
+package pkg
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryIndexDir(t *testing.T) {
+	dir := writeTestHarnessIndex(t)
+
+	cases := []struct {
+		name          string
+		query         string
+		expectedKinds []string
+		expectedType  string
+	}{
+		{"terraform type of a resource and a data source", "azurerm_key_vault", []string{DocumentKindResource, DocumentKindDataSource}, "azurerm_key_vault"},
+		{"struct type", "AccountResource", []string{DocumentKindResource}, "azurerm_account"},
+		{"legacy registration function", "resourceKeyVault", []string{DocumentKindResource}, "azurerm_key_vault"},
+		{"entry ID", "azurerm/datasources/azurerm_key_vault/legacy_pluginsdk", []string{DocumentKindDataSource}, "azurerm_key_vault"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			results, err := QueryIndexDir(dir, "", c.query)
+			require.NoError(t, err)
+
+			var kinds []string
+			for _, result := range results {
+				kinds = append(kinds, result.Kind)
+				assert.Equal(t, c.expectedType, result.TerraformType)
+				var document struct {
+					TerraformType string `json:"terraform_type"`
+					Namespace     string `json:"namespace"`
+				}
+				require.NoError(t, json.Unmarshal(result.Document, &document))
+				assert.Equal(t, c.expectedType, document.TerraformType)
+				assert.NotEmpty(t, document.Namespace)
+			}
+			assert.Equal(t, c.expectedKinds, kinds)
+		})
+	}
+}
+
+func TestQueryIndexDir_NotFound(t *testing.T) {
+	dir := writeTestHarnessIndex(t)
+
+	_, err := QueryIndexDir(dir, "", "key_vault_c")
+	assert.ErrorContains(t, err, "did you mean: azurerm_key_vault_certificate")
+
+	_, err = QueryIndexDir(dir, "", "azurerm_nothing")
+	assert.ErrorContains(t, err, "azurerm_nothing is not a Terraform type")
+
+	_, err = QueryIndexDir(filepath.Join(dir, "missing"), "", "azurerm_key_vault")
+	assert.Error(t, err)
+}
@@ -0,0 +1,299 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// IndexProblem is an inconsistency found in a generated index directory
+type IndexProblem struct {
+	File    string `json:"file,omitempty"` // "resources/azurerm_key_vault.json", relative to the index directory
+	Message string `json:"message"`        // "terraform_type is azurerm_key_vault_key, expected azurerm_key_vault"
+}
+
+// IndexValidationReport lists the problems found by ValidateIndexDir
+type IndexValidationReport struct {
+	Dir      string         `json:"dir"`      // "./index"
+	Files    int            `json:"files"`    // Number of JSON files checked
+	Problems []IndexProblem `json:"problems"` // Sorted by file and message
+}
+
+// Valid reports whether the index directory has no problems
+func (r *IndexValidationReport) Valid() bool {
+	return len(r.Problems) == 0
+}
+
+func (r *IndexValidationReport) addProblem(file, format string, args ...interface{}) {
+	r.Problems = append(r.Problems, IndexProblem{File: filepath.ToSlash(file), Message: fmt.Sprintf(format, args...)})
+}
+
+// ValidateIndexDir checks an index directory generated with the JSON format: every JSON file must parse into its
+// struct without unknown fields, the main index and documents must conform to their published JSON Schema,
+// documents must match their file names, the statistics must match the number of
+// document files, every global mapping must have a document file and the other way around, and files.json must list
+// exactly the document files. indexFileName
+// defaults to the azurerm main index file name. Problems are reported, only unreadable directories fail.
+func ValidateIndexDir(dir, indexFileName string) (*IndexValidationReport, error) {
+	if indexFileName == "" {
+		indexFileName = OutputConfig{}.MainIndexFileName()
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("index directory %s is not a directory", dir)
+	}
+	report := &IndexValidationReport{Dir: dir, Problems: []IndexProblem{}}
+
+	index := &TerraformProviderIndex{}
+	if !report.decodeSchemaFile(dir, indexFileName, JSONSchemaIndex, index) {
+		// Nothing to check the other files against
+		return report, nil
+	}
+
+	documents := map[string]map[string]string{} // kind -> Terraform type -> document ID
+	for _, kind := range documentKinds {
+		documents[kind.kind] = report.validateDocuments(dir, kind.kind)
+	}
+	for _, kind := range []string{DocumentKindResource, DocumentKindDataSource} {
+		report.validateAcceptanceTests(dir, kind)
+	}
+
+	report.decodeFile(dir, "validations.json", &map[string][]ValidationReference{})
+	report.decodeFile(dir, "write_only_attributes.json", &[]WriteOnlyReference{})
+	report.decodeFile(dir, "force_new.json", &[]ForceNewReference{})
+	report.decodeFile(dir, "sensitive_attributes.json", &[]SensitiveReference{})
+	report.decodeFile(dir, "sdk_api_versions.json", &map[string][]string{})
+	report.decodeFile(dir, "api_version_usage.json", &APIVersionReport{})
+	report.decodeFile(dir, "heatmap.json", &FeatureHeatmap{})
+	report.decodeFile(dir, filepath.Join("audit", "unreferenced-functions.json"), &[]UnreferencedFunction{})
+	report.decodeFile(dir, filepath.Join("audit", "orphans.json"), &[]OrphanedImplementation{})
+	report.decodeFile(dir, filepath.Join("audit", "duplicates.json"), &[]DuplicateRegistration{})
+	report.decodeFile(dir, "scan-report.json", &ScanReport{})
+	var files []IndexFile
+	if report.decodeFile(dir, "files.json", &files) {
+		report.validateFileList(files, documents)
+	}
+	if fileExists(filepath.Join(dir, "audit", "undocumented.json")) {
+		report.decodeFile(dir, filepath.Join("audit", "undocumented.json"), &DocumentationReport{})
+	}
+	if fileExists(filepath.Join(dir, "audit", "goindex-references.json")) {
+		report.decodeFile(dir, filepath.Join("audit", "goindex-references.json"), &GoIndexReport{})
+	}
+	if fileExists(filepath.Join(dir, "audit", "api-drift.json")) {
+		report.decodeFile(dir, filepath.Join("audit", "api-drift.json"), &APIDriftReport{})
+	}
+	if fileExists(filepath.Join(dir, "audit", "provider-coverage.json")) {
+		report.decodeFile(dir, filepath.Join("audit", "provider-coverage.json"), &ProviderCoverageReport{})
+	}
+	if fileExists(filepath.Join(dir, "audit", "provider-schema.json")) {
+		report.decodeFile(dir, filepath.Join("audit", "provider-schema.json"), &ProviderSchemaReport{})
+	}
+	for _, fileName := range report.jsonFiles(dir, "services") {
+		report.decodeFile(dir, filepath.Join("services", fileName), &ServiceSummary{})
+	}
+	for _, fileName := range report.jsonFiles(dir, "clients") {
+		report.decodeFile(dir, filepath.Join("clients", fileName), &TerraformServiceClients{})
+	}
+
+	stats := index.Statistics
+	for _, count := range []struct {
+		name     string
+		expected int
+		actual   int
+	}{
+		{"service_count", stats.ServiceCount, len(index.Services)},
+		{"total_resources", stats.TotalResources, stats.LegacyResources + stats.ModernResources + stats.EphemeralResources},
+		{"legacy_resources + modern_resources", stats.LegacyResources + stats.ModernResources, len(documents[DocumentKindResource])},
+		{"total_data_sources", stats.TotalDataSources, len(documents[DocumentKindDataSource])},
+		{"ephemeral_resources", stats.EphemeralResources, len(documents[DocumentKindEphemeral])},
+		{"list_resources", stats.ListResources, len(documents[DocumentKindListResource])},
+		{"actions", stats.Actions, len(documents[DocumentKindAction])},
+	} {
+		if count.expected != count.actual {
+			report.addProblem(indexFileName, "statistics %s is %d, found %d", count.name, count.expected, count.actual)
+		}
+	}
+
+	for kind, mappings := range map[string]map[string]GlobalMappingEntry{
+		DocumentKindResource:     index.GlobalMaps.AllResources,
+		DocumentKindDataSource:   index.GlobalMaps.AllDataSources,
+		DocumentKindEphemeral:    index.GlobalMaps.AllEphemeral,
+		DocumentKindListResource: index.GlobalMaps.AllListResources,
+		DocumentKindAction:       index.GlobalMaps.AllActions,
+	} {
+		for terraformType, entry := range mappings {
+			id, exists := documents[kind][terraformType]
+			switch {
+			case !exists:
+				report.addProblem(indexFileName, "global map of %s has %s, but %s/%s.json is missing or invalid", kind, terraformType, kind, terraformType)
+			case entry.ID != id:
+				report.addProblem(indexFileName, "global map of %s has id %s for %s, the document has %s", kind, entry.ID, terraformType, id)
+			}
+		}
+		for terraformType := range documents[kind] {
+			if _, exists := mappings[terraformType]; !exists {
+				report.addProblem(filepath.Join(kind, terraformType+".json"), "%s is missing from the global map of %s", terraformType, kind)
+			}
+		}
+	}
+
+	sort.SliceStable(report.Problems, func(i, j int) bool {
+		if report.Problems[i].File != report.Problems[j].File {
+			return report.Problems[i].File < report.Problems[j].File
+		}
+		return report.Problems[i].Message < report.Problems[j].Message
+	})
+	return report, nil
+}
+
+// validateDocuments decodes the document files of a kind and returns their IDs by Terraform type
+func (r *IndexValidationReport) validateDocuments(dir, kind string) map[string]string {
+	ids := make(map[string]string)
+	for _, fileName := range r.jsonFiles(dir, kind) {
+		file := filepath.Join(kind, fileName)
+		var terraformType, id string
+		switch kind {
+		case DocumentKindResource:
+			var document TerraformResource
+			if !r.decodeSchemaFile(dir, file, JSONSchemaResource, &document) {
+				continue
+			}
+			terraformType, id = document.TerraformType, document.ID
+		case DocumentKindDataSource:
+			var document TerraformDataSource
+			if !r.decodeSchemaFile(dir, file, JSONSchemaDataSource, &document) {
+				continue
+			}
+			terraformType, id = document.TerraformType, document.ID
+		case DocumentKindEphemeral:
+			var document TerraformEphemeral
+			if !r.decodeSchemaFile(dir, file, JSONSchemaEphemeral, &document) {
+				continue
+			}
+			terraformType, id = document.TerraformType, document.ID
+		case DocumentKindListResource:
+			var document TerraformListResource
+			if !r.decodeSchemaFile(dir, file, JSONSchemaListResource, &document) {
+				continue
+			}
+			terraformType, id = document.TerraformType, document.ID
+		case DocumentKindAction:
+			var document TerraformAction
+			if !r.decodeSchemaFile(dir, file, JSONSchemaAction, &document) {
+				continue
+			}
+			terraformType, id = document.TerraformType, document.ID
+		}
+
+		expected := strings.TrimSuffix(fileName, ".json")
+		if terraformType != expected {
+			r.addProblem(file, "terraform_type is %s, expected %s", terraformType, expected)
+		}
+		ids[expected] = id
+	}
+	return ids
+}
+
+// validateFileList checks files.json lists exactly the document files of the index
+func (r *IndexValidationReport) validateFileList(files []IndexFile, documents map[string]map[string]string) {
+	listed := make(map[string]bool)
+	for _, file := range files {
+		expected := path.Join(file.Kind, file.TerraformType+".json")
+		if file.Path != expected {
+			r.addProblem("files.json", "path of %s %s is %s, expected %s", file.Kind, file.TerraformType, file.Path, expected)
+		}
+		if _, exists := documents[file.Kind][file.TerraformType]; !exists {
+			r.addProblem("files.json", "lists %s, but it is missing or invalid", expected)
+		}
+		listed[expected] = true
+	}
+	for kind, ids := range documents {
+		for terraformType := range ids {
+			if file := path.Join(kind, terraformType+".json"); !listed[file] {
+				r.addProblem("files.json", "%s is not listed", file)
+			}
+		}
+	}
+}
+
+// validateAcceptanceTests decodes the acceptance test files of a kind
+func (r *IndexValidationReport) validateAcceptanceTests(dir, kind string) {
+	testsDir := filepath.Join("tests", kind)
+	for _, fileName := range r.jsonFiles(dir, testsDir) {
+		file := filepath.Join(testsDir, fileName)
+		var tests TerraformAcceptanceTests
+		if !r.decodeFile(dir, file, &tests) {
+			continue
+		}
+		if expected := strings.TrimSuffix(fileName, ".json"); tests.TerraformType != expected {
+			r.addProblem(file, "terraform_type is %s, expected %s", tests.TerraformType, expected)
+		}
+	}
+}
+
+// jsonFiles returns the sorted names of the JSON files in a subdirectory of the index, none when it doesn't exist
+func (r *IndexValidationReport) jsonFiles(dir, subDir string) []string {
+	entries, err := os.ReadDir(filepath.Join(dir, subDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		r.addProblem(subDir, "failed to read directory: %v", err)
+		return nil
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// decodeFile strictly decodes a JSON file of the index into target, reporting a problem when it can't
+func (r *IndexValidationReport) decodeFile(dir, file string, target interface{}) bool {
+	return r.decodeSchemaFile(dir, file, "", target)
+}
+
+// decodeSchemaFile decodes a JSON file like decodeFile, first reporting every violation of the named JSON Schema,
+// none when schemaName is empty
+func (r *IndexValidationReport) decodeSchemaFile(dir, file, schemaName string, target interface{}) bool {
+	data, err := os.ReadFile(filepath.Join(dir, file))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			r.addProblem(file, "file is missing")
+		} else {
+			r.addProblem(file, "failed to read file: %v", err)
+		}
+		return false
+	}
+	r.Files++
+
+	if schemaName != "" {
+		schema, err := JSONSchema(schemaName)
+		if err != nil {
+			r.addProblem(file, "%v", err)
+			return false
+		}
+		// Invalid JSON is reported by the decoder
+		violations, _ := validateJSONSchema(schema, data)
+		for _, violation := range violations {
+			r.addProblem(file, "does not match the %s schema: %s", schemaName, violation)
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		r.addProblem(file, "invalid JSON: %v", err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestHarnessIndex writes the index of the test harness to a temporary directory and returns the directory
+func writeTestHarnessIndex(t *testing.T) string {
+	index, err := ScanTerraformProviderServices(filepath.Join("testharness", "internal", "services"), "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+	require.NoError(t, err)
+
+	outputDir := t.TempDir()
+	require.NoError(t, index.WriteIndexFiles(outputDir, nil))
+	return outputDir
+}
+
+func TestValidateIndexDir_GeneratedIndex(t *testing.T) {
+	dir := writeTestHarnessIndex(t)
+
+	report, err := ValidateIndexDir(dir, "")
+	require.NoError(t, err)
+
+	assert.True(t, report.Valid(), "%v", report.Problems)
+	assert.Greater(t, report.Files, 6)
+}
+
+func TestValidateIndexDir_Inconsistencies(t *testing.T) {
+	dir := writeTestHarnessIndex(t)
+	require.NoError(t, os.Remove(filepath.Join(dir, "resources", "azurerm_key_vault.json")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "datasources", "azurerm_key_vault.json"), []byte(`{"terraform_type": "azurerm_key_vault", "unexpected": true}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "resources", "azurerm_extra.json"), []byte(`{"terraform_type": "azurerm_other"}`), 0644))
+	require.NoError(t, os.Remove(filepath.Join(dir, "heatmap.json")))
+
+	report, err := ValidateIndexDir(dir, "")
+	require.NoError(t, err)
+
+	assert.False(t, report.Valid())
+	messages := make(map[string][]string)
+	for _, problem := range report.Problems {
+		messages[problem.File] = append(messages[problem.File], problem.Message)
+	}
+	assert.Equal(t, []string{"file is missing"}, messages["heatmap.json"])
+	assert.Contains(t, messages["resources/azurerm_extra.json"], "azurerm_extra is missing from the global map of resources")
+	assert.Contains(t, messages["resources/azurerm_extra.json"], "terraform_type is azurerm_other, expected azurerm_extra")
+	assert.Contains(t, messages["resources/azurerm_extra.json"], "does not match the resource schema: /: missing required property id")
+	assert.Contains(t, messages["datasources/azurerm_key_vault.json"], "does not match the datasource schema: /unexpected: property is not allowed")
+	assert.Contains(t, messages["datasources/azurerm_key_vault.json"], `invalid JSON: json: unknown field "unexpected"`)
+	assert.Contains(t, messages[OutputConfig{}.MainIndexFileName()], "global map of resources has azurerm_key_vault, but resources/azurerm_key_vault.json is missing or invalid")
+	assert.Contains(t, messages[OutputConfig{}.MainIndexFileName()], "global map of datasources has azurerm_key_vault, but datasources/azurerm_key_vault.json is missing or invalid")
+	assert.Contains(t, messages["files.json"], "lists resources/azurerm_key_vault.json, but it is missing or invalid")
+	assert.Contains(t, messages["files.json"], "resources/azurerm_extra.json is not listed")
+}
+
+func TestValidateIndexDir_StatisticsMismatch(t *testing.T) {
+	dir := writeTestHarnessIndex(t)
+	require.NoError(t, os.Remove(filepath.Join(dir, "ephemeral", "azurerm_key_vault_secret.json")))
+
+	report, err := ValidateIndexDir(dir, "")
+	require.NoError(t, err)
+
+	assert.Contains(t, report.Problems, IndexProblem{File: OutputConfig{}.MainIndexFileName(), Message: "statistics ephemeral_resources is 2, found 1"})
+}
+
+func TestValidateIndexDir_InvalidMainIndex(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "custom-index.json"), []byte(`{"version": 1}`), 0644))
+
+	report, err := ValidateIndexDir(dir, "custom-index.json")
+	require.NoError(t, err)
+	var messages []string
+	for _, problem := range report.Problems {
+		assert.Equal(t, "custom-index.json", problem.File)
+		messages = append(messages, problem.Message)
+	}
+	assert.Contains(t, messages, "does not match the index schema: /version: expected string, got integer")
+	assert.Contains(t, messages, "invalid JSON: json: cannot unmarshal number into Go struct field TerraformProviderIndex.version of type string")
+
+	_, err = ValidateIndexDir(filepath.Join(dir, "missing"), "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,181 @@
+// Package indexclient reads a generated provider index for Go tools embedding it, such as policy engines and code
+// generators. The main index is loaded once, per-resource documents are fetched on demand and kept in an LRU cache.
+package indexclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/lonegunmanb/terraform-provider-azurerm-index/pkg"
+)
+
+// DefaultCacheSize is the number of documents kept in memory when Options.CacheSize is not set
+const DefaultCacheSize = 256
+
+// VersionPlaceholder is replaced by the pinned version in the base URL passed to Fetch,
+// e.g. "https://example.com/index/{version}"
+const VersionPlaceholder = "{version}"
+
+// ErrNotFound is returned when the index has no document for the requested Terraform type
+var ErrNotFound = errors.New("not found in index")
+
+// ErrVersionMismatch is returned when the loaded index doesn't match the pinned version
+var ErrVersionMismatch = errors.New("index version mismatch")
+
+// Options configures a Client
+type Options struct {
+	Version       string       // "v4.20.0", pins the provider version of the index, empty accepts any version
+	CacheSize     int          // Number of documents kept in memory, DefaultCacheSize when zero
+	IndexFileName string       // "terraform-provider-azurerm-index.json", defaults to the azurerm main index file name
+	HTTPClient    *http.Client // Client used by Fetch, http.DefaultClient when nil
+}
+
+// Client gives typed access to the documents of a generated index
+type Client struct {
+	read  func(relPath string) ([]byte, error)
+	index *pkg.TerraformProviderIndex
+	cache *lruCache
+}
+
+// Load opens the index generated in dir
+func Load(dir string, options Options) (*Client, error) {
+	return newClient(func(relPath string) ([]byte, error) {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(relPath)))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%s: %w", relPath, ErrNotFound)
+		}
+		return data, err
+	}, options)
+}
+
+// Fetch opens an index published over HTTP at baseURL, the VersionPlaceholder in baseURL is replaced by the pinned version
+func Fetch(baseURL string, options Options) (*Client, error) {
+	if strings.Contains(baseURL, VersionPlaceholder) {
+		if options.Version == "" {
+			return nil, fmt.Errorf("base URL %s requires a pinned version", baseURL)
+		}
+		baseURL = strings.ReplaceAll(baseURL, VersionPlaceholder, options.Version)
+	}
+	httpClient := options.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return newClient(func(relPath string) ([]byte, error) {
+		url := strings.TrimSuffix(baseURL, "/") + "/" + relPath
+		resp, err := httpClient.Get(url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+		}
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		switch {
+		case resp.StatusCode == http.StatusNotFound:
+			return nil, fmt.Errorf("%s: %w", relPath, ErrNotFound)
+		case resp.StatusCode != http.StatusOK:
+			return nil, fmt.Errorf("failed to fetch %s: %s", url, resp.Status)
+		}
+		return io.ReadAll(resp.Body)
+	}, options)
+}
+
+func newClient(read func(relPath string) ([]byte, error), options Options) (*Client, error) {
+	indexFileName := options.IndexFileName
+	if indexFileName == "" {
+		indexFileName = pkg.OutputConfig{}.MainIndexFileName()
+	}
+	cacheSize := options.CacheSize
+	if cacheSize <= 0 {
+		cacheSize = DefaultCacheSize
+	}
+
+	client := &Client{read: read, cache: newLRUCache(cacheSize)}
+	index := &pkg.TerraformProviderIndex{}
+	if err := client.decode(indexFileName, index); err != nil {
+		return nil, err
+	}
+	if options.Version != "" && index.Version != options.Version {
+		return nil, fmt.Errorf("%w: pinned %s, found %s", ErrVersionMismatch, options.Version, index.Version)
+	}
+	client.index = index
+	return client, nil
+}
+
+// Version returns the provider version of the index
+func (c *Client) Version() string {
+	return c.index.Version
+}
+
+// Index returns the main index with services and statistics
+func (c *Client) Index() *pkg.TerraformProviderIndex {
+	return c.index
+}
+
+// Resource returns the document of a resource, e.g. resources/azurerm_key_vault.json
+func (c *Client) Resource(terraformType string) (*pkg.TerraformResource, error) {
+	return getDocument[pkg.TerraformResource](c, pkg.DocumentKindResource, terraformType)
+}
+
+// DataSource returns the document of a data source, e.g. datasources/azurerm_key_vault.json
+func (c *Client) DataSource(terraformType string) (*pkg.TerraformDataSource, error) {
+	return getDocument[pkg.TerraformDataSource](c, pkg.DocumentKindDataSource, terraformType)
+}
+
+// Ephemeral returns the document of an ephemeral resource, e.g. ephemeral/azurerm_key_vault_certificate.json
+func (c *Client) Ephemeral(terraformType string) (*pkg.TerraformEphemeral, error) {
+	return getDocument[pkg.TerraformEphemeral](c, pkg.DocumentKindEphemeral, terraformType)
+}
+
+// ListResource returns the document of a list resource, e.g. listresources/azurerm_resource_group.json
+func (c *Client) ListResource(terraformType string) (*pkg.TerraformListResource, error) {
+	return getDocument[pkg.TerraformListResource](c, pkg.DocumentKindListResource, terraformType)
+}
+
+// Action returns the document of an action, e.g. actions/azurerm_virtual_machine_power.json
+func (c *Client) Action(terraformType string) (*pkg.TerraformAction, error) {
+	return getDocument[pkg.TerraformAction](c, pkg.DocumentKindAction, terraformType)
+}
+
+// SchemaFor returns the top level schema attributes of a resource
+func (c *Client) SchemaFor(terraformType string) ([]pkg.SchemaAttribute, error) {
+	resource, err := c.Resource(terraformType)
+	if err != nil {
+		return nil, err
+	}
+	return resource.Schema, nil
+}
+
+// getDocument returns the cached document of a Terraform type, reading and caching it on a cache miss
+func getDocument[T any](c *Client, kind, terraformType string) (*T, error) {
+	relPath := path.Join(kind, terraformType+".json")
+	if cached, ok := c.cache.get(relPath); ok {
+		return cached.(*T), nil
+	}
+
+	document := new(T)
+	if err := c.decode(relPath, document); err != nil {
+		return nil, err
+	}
+	c.cache.add(relPath, document)
+	return document, nil
+}
+
+// decode reads a file of the index and decodes it into v
+func (c *Client) decode(relPath string, v interface{}) error {
+	data, err := c.read(relPath)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", relPath, err)
+	}
+	return nil
+}
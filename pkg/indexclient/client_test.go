@@ -0,0 +1,110 @@
+package indexclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lonegunmanb/terraform-provider-azurerm-index/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestIndex writes a main index and a single resource document under dir
+func writeTestIndex(t *testing.T, dir string) {
+	files := map[string]interface{}{
+		"terraform-provider-azurerm-index.json": pkg.TerraformProviderIndex{
+			Version:  "v4.20.0",
+			Services: []pkg.ServiceRegistration{{ServiceName: "keyvault"}},
+		},
+		"resources/azurerm_key_vault.json": pkg.TerraformResource{
+			TerraformType: "azurerm_key_vault",
+			Schema:        []pkg.SchemaAttribute{{Name: "name", Type: "TypeString"}},
+		},
+	}
+	for relPath, content := range files {
+		data, err := json.Marshal(content)
+		require.NoError(t, err)
+		filePath := filepath.Join(dir, filepath.FromSlash(relPath))
+		require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0755))
+		require.NoError(t, os.WriteFile(filePath, data, 0644))
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeTestIndex(t, dir)
+
+	client, err := Load(dir, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "v4.20.0", client.Version())
+	assert.Len(t, client.Index().Services, 1)
+
+	resource, err := client.Resource("azurerm_key_vault")
+	require.NoError(t, err)
+	assert.Equal(t, "azurerm_key_vault", resource.TerraformType)
+
+	schema, err := client.SchemaFor("azurerm_key_vault")
+	require.NoError(t, err)
+	assert.Equal(t, []pkg.SchemaAttribute{{Name: "name", Type: "TypeString"}}, schema)
+
+	_, err = client.DataSource("azurerm_key_vault")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLoad_VersionPinning(t *testing.T) {
+	dir := t.TempDir()
+	writeTestIndex(t, dir)
+
+	_, err := Load(dir, Options{Version: "v4.20.0"})
+	assert.NoError(t, err)
+
+	_, err = Load(dir, Options{Version: "v4.21.0"})
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+}
+
+func TestFetch_CachesDocuments(t *testing.T) {
+	dir := t.TempDir()
+	writeTestIndex(t, dir)
+
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.StripPrefix("/v4.20.0", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		http.FileServer(http.Dir(dir)).ServeHTTP(w, r)
+	})))
+	defer server.Close()
+
+	client, err := Fetch(server.URL+"/"+VersionPlaceholder, Options{Version: "v4.20.0"})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		resource, err := client.Resource("azurerm_key_vault")
+		require.NoError(t, err)
+		assert.Equal(t, "azurerm_key_vault", resource.TerraformType)
+	}
+	assert.Equal(t, 1, requests["/resources/azurerm_key_vault.json"])
+
+	_, err = client.Ephemeral("azurerm_key_vault")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = Fetch(server.URL+"/"+VersionPlaceholder, Options{})
+	assert.Error(t, err)
+}
+
+func TestLRUCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newLRUCache(2)
+	cache.add("a", 1)
+	cache.add("b", 2)
+	_, _ = cache.get("a")
+	cache.add("c", 3)
+
+	_, exists := cache.get("b")
+	assert.False(t, exists)
+	value, exists := cache.get("a")
+	assert.True(t, exists)
+	assert.Equal(t, 1, value)
+	assert.Equal(t, 2, cache.len())
+}
@@ -0,0 +1,227 @@
+// Package indexproxy serves a published provider index over localhost HTTP for AI agents and other tools configured
+// against the published URL. Fetched files are cached on disk, failed fetches are retried, and cached files are
+// served when the published index can't be reached, so consumers keep working during network hiccups.
+package indexproxy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lonegunmanb/terraform-provider-azurerm-index/pkg/indexclient"
+)
+
+// DefaultRetries is the number of retries of a failed fetch when Options.Retries is not set
+const DefaultRetries = 3
+
+// DefaultRetryDelay is the delay before the first retry when Options.RetryDelay is not set, doubled for each retry
+const DefaultRetryDelay = 500 * time.Millisecond
+
+// CacheHeader reports how a response was served: CacheHit, CacheMiss or CacheStale
+const CacheHeader = "X-Index-Cache"
+
+const (
+	CacheHit   = "HIT"   // Served from the cache without contacting the published index
+	CacheMiss  = "MISS"  // Fetched from the published index and cached
+	CacheStale = "STALE" // Served from the cache because the published index couldn't be reached
+)
+
+// Options configures a Proxy
+type Options struct {
+	Upstream   string        // "https://example.com/index/{version}", base URL of the published index
+	Version    string        // "v4.20.0", replaces indexclient.VersionPlaceholder in Upstream
+	CacheDir   string        // "./index-cache", directory the fetched files are cached in
+	MaxAge     time.Duration // Cached files younger than MaxAge are served without fetching, zero always fetches
+	Retries    int           // Retries of a failed fetch, DefaultRetries when zero, none when negative
+	RetryDelay time.Duration // Delay before the first retry, DefaultRetryDelay when zero
+	HTTPClient *http.Client  // Client fetching the published index, http.DefaultClient when nil
+}
+
+// Proxy is an http.Handler serving the files of a published index through a local cache
+type Proxy struct {
+	upstream   string
+	cacheDir   string
+	maxAge     time.Duration
+	retries    int
+	retryDelay time.Duration
+	httpClient *http.Client
+}
+
+// errUpstreamNotFound is returned when the published index has no such file
+var errUpstreamNotFound = errors.New("not found in the published index")
+
+// New creates a Proxy fronting the published index at options.Upstream
+func New(options Options) (*Proxy, error) {
+	if options.Upstream == "" {
+		return nil, fmt.Errorf("upstream URL is required")
+	}
+	if options.CacheDir == "" {
+		return nil, fmt.Errorf("cache directory is required")
+	}
+	upstream := options.Upstream
+	if strings.Contains(upstream, indexclient.VersionPlaceholder) {
+		if options.Version == "" {
+			return nil, fmt.Errorf("upstream URL %s requires a pinned version", upstream)
+		}
+		upstream = strings.ReplaceAll(upstream, indexclient.VersionPlaceholder, options.Version)
+	}
+
+	retries := options.Retries
+	if retries == 0 {
+		retries = DefaultRetries
+	} else if retries < 0 {
+		retries = 0
+	}
+	retryDelay := options.RetryDelay
+	if retryDelay <= 0 {
+		retryDelay = DefaultRetryDelay
+	}
+	httpClient := options.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Proxy{
+		upstream:   strings.TrimSuffix(upstream, "/"),
+		cacheDir:   options.CacheDir,
+		maxAge:     options.MaxAge,
+		retries:    retries,
+		retryDelay: retryDelay,
+		httpClient: httpClient,
+	}, nil
+}
+
+// ServeHTTP serves a file of the published index, e.g. GET /resources/azurerm_key_vault.json
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	relPath := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	if relPath == "" || strings.HasSuffix(r.URL.Path, "/") {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	data, cacheStatus, err := p.get(relPath)
+	if errors.Is(err, errUpstreamNotFound) {
+		http.Error(w, fmt.Sprintf("%s is not in the published index", relPath), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set(CacheHeader, cacheStatus)
+	if path.Ext(relPath) == ".json" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(data)
+	}
+}
+
+// get returns a file of the index from the cache while it is fresh, from the published index otherwise, falling back
+// to a stale cached file when the published index can't be reached
+func (p *Proxy) get(relPath string) ([]byte, string, error) {
+	cachePath := filepath.Join(p.cacheDir, filepath.FromSlash(relPath))
+	info, statErr := os.Stat(cachePath)
+	cached := statErr == nil && !info.IsDir()
+	if cached && p.maxAge > 0 && time.Since(info.ModTime()) < p.maxAge {
+		if data, err := os.ReadFile(cachePath); err == nil {
+			return data, CacheHit, nil
+		}
+	}
+
+	data, err := p.fetch(relPath)
+	if err == nil {
+		if err := writeCacheFile(cachePath, data); err != nil {
+			return nil, "", err
+		}
+		return data, CacheMiss, nil
+	}
+	if errors.Is(err, errUpstreamNotFound) || !cached {
+		return nil, "", err
+	}
+	data, readErr := os.ReadFile(cachePath)
+	if readErr != nil {
+		return nil, "", err
+	}
+	return data, CacheStale, nil
+}
+
+// fetch reads a file of the published index, retrying network errors and server side failures
+func (p *Proxy) fetch(relPath string) ([]byte, error) {
+	url := p.upstream + "/" + relPath
+	delay := p.retryDelay
+	var lastErr error
+	for attempt := 0; attempt <= p.retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+		data, retry, err := p.fetchOnce(url)
+		if err == nil || !retry {
+			return data, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// fetchOnce reads a file of the published index, reporting whether a failure is worth retrying
+func (p *Proxy) fetchOnce(url string) ([]byte, bool, error) {
+	resp, err := p.httpClient.Get(url)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, false, fmt.Errorf("%s: %w", url, errUpstreamNotFound)
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+		return nil, true, fmt.Errorf("failed to fetch %s: %s", url, resp.Status)
+	case resp.StatusCode != http.StatusOK:
+		return nil, false, fmt.Errorf("failed to fetch %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	return data, false, nil
+}
+
+// writeCacheFile replaces a cached file through a temporary file, so concurrent requests never read a partial file
+func writeCacheFile(cachePath string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(cachePath), ".download-*")
+	if err != nil {
+		return fmt.Errorf("failed to cache %s: %w", cachePath, err)
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr == nil {
+		writeErr = os.Rename(tmp.Name(), cachePath)
+	}
+	if writeErr != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to cache %s: %w", cachePath, writeErr)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package indexproxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDocument = `{"terraform_type":"azurerm_key_vault"}`
+
+// newTestUpstream serves testDocument at /v4.20.0/resources/azurerm_key_vault.json, answering with failures first
+// status codes before succeeding, and counts the requests it receives
+func newTestUpstream(t *testing.T, failures ...int) (*httptest.Server, *int32) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		if int(n) <= len(failures) {
+			w.WriteHeader(failures[n-1])
+			return
+		}
+		if r.URL.Path != "/v4.20.0/resources/azurerm_key_vault.json" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(testDocument))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func newTestProxy(t *testing.T, upstream string, maxAge time.Duration) (*Proxy, string) {
+	cacheDir := t.TempDir()
+	proxy, err := New(Options{
+		Upstream:   upstream + "/{version}",
+		Version:    "v4.20.0",
+		CacheDir:   cacheDir,
+		MaxAge:     maxAge,
+		RetryDelay: time.Millisecond,
+	})
+	require.NoError(t, err)
+	return proxy, cacheDir
+}
+
+func get(proxy *Proxy, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	proxy.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	return recorder
+}
+
+func TestProxy_FetchesAndCaches(t *testing.T) {
+	upstream, _ := newTestUpstream(t)
+	proxy, cacheDir := newTestProxy(t, upstream.URL, 0)
+
+	response := get(proxy, "/resources/azurerm_key_vault.json")
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, CacheMiss, response.Header().Get(CacheHeader))
+	assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+	assert.Equal(t, testDocument, response.Body.String())
+	cached, err := os.ReadFile(filepath.Join(cacheDir, "resources", "azurerm_key_vault.json"))
+	require.NoError(t, err)
+	assert.Equal(t, testDocument, string(cached))
+}
+
+func TestProxy_ServesFreshCacheWithoutFetching(t *testing.T) {
+	upstream, requests := newTestUpstream(t)
+	proxy, _ := newTestProxy(t, upstream.URL, time.Hour)
+
+	get(proxy, "/resources/azurerm_key_vault.json")
+	response := get(proxy, "/resources/azurerm_key_vault.json")
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, CacheHit, response.Header().Get(CacheHeader))
+	assert.Equal(t, testDocument, response.Body.String())
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
+
+func TestProxy_ServesStaleCacheWhenUpstreamIsDown(t *testing.T) {
+	upstream, _ := newTestUpstream(t)
+	proxy, _ := newTestProxy(t, upstream.URL, 0)
+	get(proxy, "/resources/azurerm_key_vault.json")
+	upstream.Close()
+
+	response := get(proxy, "/resources/azurerm_key_vault.json")
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, CacheStale, response.Header().Get(CacheHeader))
+	assert.Equal(t, testDocument, response.Body.String())
+}
+
+func TestProxy_BadGatewayWithoutCache(t *testing.T) {
+	upstream, requests := newTestUpstream(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable,
+		http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	proxy, _ := newTestProxy(t, upstream.URL, 0)
+
+	response := get(proxy, "/resources/azurerm_key_vault.json")
+
+	assert.Equal(t, http.StatusBadGateway, response.Code)
+	assert.Equal(t, int32(DefaultRetries+1), atomic.LoadInt32(requests))
+}
+
+func TestProxy_RetriesServerErrors(t *testing.T) {
+	upstream, requests := newTestUpstream(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	proxy, _ := newTestProxy(t, upstream.URL, 0)
+
+	response := get(proxy, "/resources/azurerm_key_vault.json")
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, testDocument, response.Body.String())
+	assert.Equal(t, int32(3), atomic.LoadInt32(requests))
+}
+
+func TestProxy_PassesNotFoundThrough(t *testing.T) {
+	upstream, requests := newTestUpstream(t)
+	proxy, cacheDir := newTestProxy(t, upstream.URL, 0)
+
+	response := get(proxy, "/resources/azurerm_missing.json")
+
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+	assert.NoFileExists(t, filepath.Join(cacheDir, "resources", "azurerm_missing.json"))
+}
+
+func TestProxy_KeepsRequestsInsideCacheDir(t *testing.T) {
+	upstream, _ := newTestUpstream(t)
+	proxy, cacheDir := newTestProxy(t, upstream.URL, 0)
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.URL.Path = "/../../resources/azurerm_key_vault.json"
+	recorder := httptest.NewRecorder()
+	proxy.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.FileExists(t, filepath.Join(cacheDir, "resources", "azurerm_key_vault.json"))
+	assert.Equal(t, http.StatusNotFound, get(proxy, "/").Code)
+}
+
+func TestProxy_RejectsOtherMethods(t *testing.T) {
+	upstream, _ := newTestUpstream(t)
+	proxy, _ := newTestProxy(t, upstream.URL, 0)
+
+	recorder := httptest.NewRecorder()
+	proxy.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/resources/azurerm_key_vault.json", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+func TestNew_RequiresPinnedVersion(t *testing.T) {
+	_, err := New(Options{Upstream: "https://example.com/{version}", CacheDir: t.TempDir()})
+	assert.ErrorContains(t, err, "requires a pinned version")
+}
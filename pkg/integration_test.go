@@ -1,10 +1,12 @@
 package pkg
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"path/filepath"
+	"sort"
 	"testing"
 )
 
@@ -69,6 +71,19 @@ func TestScanTerraformProviderServices(t *testing.T) {
 	assert.Contains(t, keyvaultService.DataSources, "EncryptedValueDataSource")
 	assert.Contains(t, keyvaultService.EphemeralFunctions, "NewKeyVaultCertificateEphemeralResource")
 	assert.Contains(t, keyvaultService.EphemeralFunctions, "NewKeyVaultSecretEphemeralResource")
+	assert.Equal(t, map[string]string{
+		"KeyVaultCertificateEphemeralResource": "NewKeyVaultCertificateEphemeralResource",
+		"KeyVaultSecretEphemeralResource":      "NewKeyVaultSecretEphemeralResource",
+	}, keyvaultService.EphemeralConstructors)
+	ephemeral := newTerraformEphemeralInfo("KeyVaultSecretEphemeralResource", *keyvaultService)
+	assert.Equal(t, "NewKeyVaultSecretEphemeralResource", ephemeral.ConstructorFunction)
+	assert.Equal(t, "KeyVault", keyvaultService.DisplayName)
+	assert.Equal(t, []string{"Key Vault"}, keyvaultService.WebsiteCategories)
+	resource := newTerraformResourceInfo("azurerm_key_vault", "", "resourceKeyVault", "legacy_pluginsdk", *keyvaultService)
+	assert.Equal(t, "KeyVault", resource.DisplayName)
+	assert.Equal(t, []string{"Key Vault"}, resource.WebsiteCategories)
+	assert.Equal(t, "service/key-vault", keyvaultService.GitHubLabel)
+	assert.Equal(t, "service/key-vault", resource.GitHubLabel)
 
 	// Validate statistics make sense
 	assert.Greater(t, index.Statistics.TotalResources, 0)
@@ -149,3 +164,22 @@ func TestWriteIndexFilesWithProgress(t *testing.T) {
 	assert.Equal(t, 100.0, lastUpdate.Percentage)
 	assert.Equal(t, "Completed", lastUpdate.Current)
 }
+
+func TestScanTerraformProviderServices_DeterministicOutput(t *testing.T) {
+	testHarnessPath := filepath.Join("testharness", "internal", "services")
+
+	var outputs [][]byte
+	for i := 0; i < 3; i++ {
+		index, err := ScanTerraformProviderServices(testHarnessPath, "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+		require.NoError(t, err)
+		assert.True(t, sort.SliceIsSorted(index.Services, func(i, j int) bool {
+			return index.Services[i].ServiceName < index.Services[j].ServiceName
+		}))
+		data, err := json.Marshal(index)
+		require.NoError(t, err)
+		outputs = append(outputs, data)
+	}
+
+	assert.Equal(t, string(outputs[0]), string(outputs[1]))
+	assert.Equal(t, string(outputs[0]), string(outputs[2]))
+}
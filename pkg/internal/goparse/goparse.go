@@ -0,0 +1,133 @@
+// Package goparse parses the service packages of the provider into the package information gophon returns.
+package goparse
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+	"github.com/lonegunmanb/terraform-provider-azurerm-index/pkg/internal/parallel"
+)
+
+// ParsePackage parses the Go files of the package in dir the build context selects on up to workers
+// goroutines, one per CPU when zero, into a shared FileSet. The package is the one gophon.ScanSinglePackage returns,
+// parsed file by file instead of loaded whole by the go command, so the files of the largest services don't leave
+// the other workers idle at the end of the scan. TestParsePackage_MatchesGophon catches drifts from gophon.
+func ParsePackage(dir, basePkgUrl string, buildContext build.Context, workers int) (*gophon.PackageInfo, error) {
+	buildPackage, err := buildContext.ImportDir(dir, 0)
+	var noGoError *build.NoGoError
+	if errors.As(err, &noGoError) {
+		return &gophon.PackageInfo{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Go files of %s: %w", dir, err)
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fileNames := append(append([]string{}, buildPackage.GoFiles...), buildPackage.CgoFiles...)
+	fset := token.NewFileSet()
+	files := make([]*ast.File, len(fileNames))
+	tasks := make([]func() error, len(fileNames))
+	for i, fileName := range fileNames {
+		tasks[i] = func() error {
+			file, err := parser.ParseFile(fset, filepath.Join(absDir, fileName), nil, parser.ParseComments)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %w", fileName, err)
+			}
+			files[i] = file
+			return nil
+		}
+	}
+	if err := parallel.Run(tasks, workers); err != nil {
+		return nil, err
+	}
+
+	packagePath := gophonPackagePath(dir, basePkgUrl, buildPackage.Name)
+	packageInfo := &gophon.PackageInfo{}
+	for i, file := range files {
+		fileInfo := &gophon.FileInfo{File: file, FileName: filepath.Join(absDir, fileNames[i]), Package: packagePath}
+		packageInfo.Files = append(packageInfo.Files, fileInfo)
+		appendDeclarations(packageInfo, fset, fileInfo)
+	}
+	return packageInfo, nil
+}
+
+// gophonPackagePath returns the package path gophon records for the package in dir: the base package path joined
+// with the parent directories of dir and the declared package name
+func gophonPackagePath(dir, basePkgUrl, packageName string) string {
+	pathParts := []string{basePkgUrl}
+	if dir != "" {
+		sep := string(filepath.Separator)
+		parts := strings.Split(dir, sep)
+		if !strings.Contains(dir, sep) && strings.Contains(dir, "/") {
+			parts = strings.Split(dir, "/")
+		}
+		if len(parts) > 1 {
+			pathParts = append(pathParts, parts[:len(parts)-1]...)
+		}
+	}
+	return strings.Join(append(pathParts, packageName), "/")
+}
+
+// appendDeclarations adds the constants, variables, types, functions and methods of a file to the package, in
+// declaration order
+func appendDeclarations(packageInfo *gophon.PackageInfo, fset *token.FileSet, fileInfo *gophon.FileInfo) {
+	rangeOf := func(node ast.Node) *gophon.Range {
+		return &gophon.Range{FileInfo: fileInfo, StartLine: fset.Position(node.Pos()).Line, EndLine: fset.Position(node.End()).Line}
+	}
+	for _, decl := range fileInfo.File.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						if name.Name == "_" {
+							continue
+						}
+						if decl.Tok == token.CONST {
+							packageInfo.Constants = append(packageInfo.Constants, &gophon.ConstantInfo{Range: rangeOf(spec), GenDecl: decl, Name: name.Name})
+						} else {
+							packageInfo.Variables = append(packageInfo.Variables, &gophon.VariableInfo{Range: rangeOf(spec), GenDecl: decl, Name: name.Name})
+						}
+					}
+				case *ast.TypeSpec:
+					packageInfo.Types = append(packageInfo.Types, &gophon.TypeInfo{Range: rangeOf(spec), GenDecl: decl, Name: spec.Name.Name})
+				}
+			}
+		case *ast.FuncDecl:
+			packageInfo.Functions = append(packageInfo.Functions, &gophon.FunctionInfo{
+				Range:        rangeOf(decl),
+				FuncDecl:     decl,
+				Name:         decl.Name.Name,
+				ReceiverType: gophonReceiverType(decl),
+			})
+		}
+	}
+}
+
+// gophonReceiverType returns the receiver type of a method as gophon records it, "*KeyVaultResource" for pointer
+// receivers and empty for functions and generic receivers
+func gophonReceiverType(funcDecl *ast.FuncDecl) string {
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+		return ""
+	}
+	switch recv := funcDecl.Recv.List[0].Type.(type) {
+	case *ast.StarExpr:
+		if ident, ok := recv.X.(*ast.Ident); ok {
+			return "*" + ident.Name
+		}
+	case *ast.Ident:
+		return recv.Name
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package goparse
+
+import (
+	"fmt"
+	"go/build"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// declarationSummary lists the declarations of a package with their files and lines
+func declarationSummary(packageInfo *gophon.PackageInfo) []string {
+	var summary []string
+	describe := func(kind, name string, r *gophon.Range) {
+		summary = append(summary, fmt.Sprintf("%s %s %s:%d-%d", kind, name, filepath.Base(r.FileName), r.StartLine, r.EndLine))
+	}
+	for _, fileInfo := range packageInfo.Files {
+		summary = append(summary, fmt.Sprintf("file %s %s %s", fileInfo.FileName, fileInfo.FilePath, fileInfo.Package))
+	}
+	for _, constant := range packageInfo.Constants {
+		describe("const", constant.Name, constant.Range)
+	}
+	for _, variable := range packageInfo.Variables {
+		describe("var", variable.Name, variable.Range)
+	}
+	for _, typeInfo := range packageInfo.Types {
+		describe("type", typeInfo.Name, typeInfo.Range)
+	}
+	for _, function := range packageInfo.Functions {
+		describe("func", function.ReceiverType+"."+function.Name, function.Range)
+	}
+	return summary
+}
+
+func TestParsePackage_MatchesGophon(t *testing.T) {
+	for _, service := range []string{"compute", "keyvault", "resource", "storage"} {
+		t.Run(service, func(t *testing.T) {
+			servicePath := filepath.Join("..", "..", "testharness", "internal", "services", service)
+			expected, err := gophon.ScanSinglePackage(servicePath, "github.com/lonegunmanb/terraform-provider-azurerm-index")
+			require.NoError(t, err)
+
+			actual, err := ParsePackage(servicePath, "github.com/lonegunmanb/terraform-provider-azurerm-index", build.Default, 2)
+			require.NoError(t, err)
+
+			assert.Equal(t, declarationSummary(expected), declarationSummary(actual))
+			require.NotEmpty(t, actual.Functions)
+			assert.NotEmpty(t, actual.Functions[0].Range.String(), "the source of declarations is read from their file")
+		})
+	}
+}
+
+func TestParsePackage_Errors(t *testing.T) {
+	dir := t.TempDir()
+	packageInfo, err := ParsePackage(dir, "github.com/hashicorp/terraform-provider-azurerm", build.Default, 0)
+	require.NoError(t, err)
+	assert.Empty(t, packageInfo.Files, "directories without Go files result in an empty package")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.go"), []byte("package broken\nfunc {"), 0644))
+	_, err = ParsePackage(dir, "github.com/hashicorp/terraform-provider-azurerm", build.Default, 0)
+	assert.ErrorContains(t, err, "failed to parse broken.go")
+}
+
+func TestGophonPackagePath(t *testing.T) {
+	assert.Equal(t, "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault",
+		gophonPackagePath(filepath.Join("internal", "services", "keyvault"), "github.com/hashicorp/terraform-provider-azurerm", "keyvault"))
+	assert.Equal(t, "github.com/hashicorp/terraform-provider-azurerm/internal/services/kv",
+		gophonPackagePath("internal/services/keyvault", "github.com/hashicorp/terraform-provider-azurerm", "kv"))
+	assert.Equal(t, "github.com/hashicorp/terraform-provider-azurerm/keyvault",
+		gophonPackagePath("", "github.com/hashicorp/terraform-provider-azurerm", "keyvault"))
+}
@@ -0,0 +1,32 @@
+// Package output writes the files of the index to the file system of the output.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// WriteJSONFile writes data as indented JSON to filePath on fs, creating its parent directory
+func WriteJSONFile(fs afero.Fs, filePath string, data interface{}) error {
+	// Ensure parent directory exists
+	parentDir := filepath.Dir(filePath)
+	if err := fs.MkdirAll(parentDir, 0755); err != nil {
+		return fmt.Errorf("failed to create parent directory %s: %w", parentDir, err)
+	}
+
+	// Marshal data to JSON with indentation
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal data to JSON: %w", err)
+	}
+
+	// Write to file
+	if err := afero.WriteFile(fs, filePath, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", filePath, err)
+	}
+
+	return nil
+}
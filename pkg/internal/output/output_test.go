@@ -0,0 +1,51 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJSONFile(t *testing.T) {
+	// Setup
+	fs := afero.NewMemMapFs()
+	filePath := "/test/data.json"
+	testData := map[string]interface{}{
+		"key1": "value1",
+		"key2": 42,
+		"key3": []string{"a", "b", "c"},
+	}
+
+	// Execute
+	err := WriteJSONFile(fs, filePath, testData)
+
+	// Verify
+	require.NoError(t, err)
+
+	exists, err := afero.Exists(fs, filePath)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	// Read and verify content
+	data, err := afero.ReadFile(fs, filePath)
+	require.NoError(t, err)
+
+	var readData map[string]interface{}
+	err = json.Unmarshal(data, &readData)
+	require.NoError(t, err)
+
+	assert.Equal(t, "value1", readData["key1"])
+	assert.Equal(t, float64(42), readData["key2"]) // JSON numbers are float64
+	assert.Len(t, readData["key3"], 3)
+}
+
+func TestWriteJSONFile_Error(t *testing.T) {
+	err := WriteJSONFile(afero.NewReadOnlyFs(afero.NewMemMapFs()), "/test/data.json", "data")
+	assert.ErrorContains(t, err, "failed to create parent directory /test")
+
+	err = WriteJSONFile(afero.NewMemMapFs(), "/test/data.json", func() {})
+	assert.ErrorContains(t, err, "failed to marshal data to JSON")
+}
@@ -0,0 +1,67 @@
+// Package parallel runs the tasks of the scan and of the writers of the index on a bounded number of goroutines.
+package parallel
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Workers returns the number of workers processing the given number of tasks, zero or negative workers
+// means one worker per CPU
+func Workers(workers, tasks int) int {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > tasks {
+		workers = tasks
+	}
+	return workers
+}
+
+// Run runs a slice of callbacks in parallel on up to workers goroutines
+func Run(tasks []func() error, workers int) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	numWorkers := Workers(workers, len(tasks))
+
+	callbackChan := make(chan func() error, len(tasks))
+	errorChan := make(chan error, len(tasks))
+	var wg sync.WaitGroup
+
+	// Send all callbacks to the channel
+	for _, callback := range tasks {
+		callbackChan <- callback
+	}
+	close(callbackChan)
+
+	// Start worker goroutines
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for callback := range callbackChan {
+				if err := callback(); err != nil {
+					errorChan <- err
+					return
+				}
+			}
+		}()
+	}
+
+	// Wait for all workers to complete
+	go func() {
+		wg.Wait()
+		close(errorChan)
+	}()
+
+	// Check for errors
+	for err := range errorChan {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package parallel
+
+import (
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkers(t *testing.T) {
+	assert.Equal(t, 2, Workers(2, 10))
+	assert.Equal(t, 3, Workers(8, 3))
+	assert.Equal(t, min(runtime.NumCPU(), 1000), Workers(0, 1000))
+	assert.Equal(t, min(runtime.NumCPU(), 1000), Workers(-1, 1000))
+}
+
+func TestRun(t *testing.T) {
+	var ran atomic.Int32
+	tasks := make([]func() error, 10)
+	for i := range tasks {
+		tasks[i] = func() error {
+			ran.Add(1)
+			return nil
+		}
+	}
+	assert.NoError(t, Run(tasks, 3))
+	assert.Equal(t, int32(10), ran.Load())
+
+	failed := errors.New("failed")
+	assert.ErrorIs(t, Run(append(tasks, func() error { return failed }), 0), failed)
+	assert.NoError(t, Run(nil, 0))
+}
@@ -0,0 +1,359 @@
+package pkg
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+//go:generate go test -run TestJSONSchemas_UpToDate -update-schemas .
+
+// Names of the JSON Schemas of the index files
+const (
+	JSONSchemaIndex      = "index"      // The main index file
+	JSONSchemaResource   = "resource"   // Documents of resources/
+	JSONSchemaDataSource = "datasource" // Documents of datasources/
+	JSONSchemaEphemeral  = "ephemeral"  // Documents of ephemeral/
+
+	JSONSchemaListResource = "listresource" // Documents of listresources/
+	JSONSchemaAction       = "action"       // Documents of actions/
+
+	JSONSchemaSingleFile = "singlefile" // The main index file written in single-file mode
+)
+
+// jsonSchemaBaseURL is the base of the $id of the published schemas
+const jsonSchemaBaseURL = "https://github.com/lonegunmanb/terraform-provider-azurerm-index/blob/main/pkg/schemas/"
+
+//go:embed schemas/*.schema.json
+var embeddedJSONSchemas embed.FS
+
+// jsonSchemaTypes are the Go types the published schemas are generated from
+var jsonSchemaTypes = []struct {
+	name string
+	typ  reflect.Type
+}{
+	{JSONSchemaIndex, reflect.TypeOf(TerraformProviderIndex{})},
+	{JSONSchemaResource, reflect.TypeOf(TerraformResource{})},
+	{JSONSchemaDataSource, reflect.TypeOf(TerraformDataSource{})},
+	{JSONSchemaEphemeral, reflect.TypeOf(TerraformEphemeral{})},
+	{JSONSchemaListResource, reflect.TypeOf(TerraformListResource{})},
+	{JSONSchemaAction, reflect.TypeOf(TerraformAction{})},
+	{JSONSchemaSingleFile, reflect.TypeOf(SingleFileIndex{})},
+}
+
+// JSONSchemaNames lists the names of the published JSON Schemas
+func JSONSchemaNames() []string {
+	names := make([]string, 0, len(jsonSchemaTypes))
+	for _, schemaType := range jsonSchemaTypes {
+		names = append(names, schemaType.name)
+	}
+	return names
+}
+
+// JSONSchema returns the published JSON Schema (draft 2020-12) of the index files of the given name, the contract
+// consumers of the index code against
+func JSONSchema(name string) ([]byte, error) {
+	content, err := embeddedJSONSchemas.ReadFile("schemas/" + name + ".schema.json")
+	if err != nil {
+		return nil, fmt.Errorf("unknown JSON schema %q, expected one of %s", name, strings.Join(JSONSchemaNames(), ", "))
+	}
+	return content, nil
+}
+
+// generateJSONSchema generates the JSON Schema of the given name from its Go type, following the encoding/json
+// rules: fields without omitempty are required, nil slices, maps and pointers of such fields may be null, and
+// properties the type doesn't declare are rejected. Named struct types are shared through $defs.
+func generateJSONSchema(name string) ([]byte, error) {
+	for _, schemaType := range jsonSchemaTypes {
+		if schemaType.name != name {
+			continue
+		}
+		generator := &jsonSchemaGenerator{defs: make(map[string]interface{})}
+		schema := generator.structSchema(schemaType.typ)
+		schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+		schema["$id"] = jsonSchemaBaseURL + name + ".schema.json"
+		schema["title"] = schemaType.typ.Name()
+		if len(generator.defs) > 0 {
+			schema["$defs"] = generator.defs
+		}
+
+		var content bytes.Buffer
+		encoder := json.NewEncoder(&content)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(schema); err != nil {
+			return nil, fmt.Errorf("failed to marshal JSON schema %s: %w", name, err)
+		}
+		return content.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unknown JSON schema %q, expected one of %s", name, strings.Join(JSONSchemaNames(), ", "))
+}
+
+// jsonSchemaGenerator collects the $defs of the named struct types of a schema
+type jsonSchemaGenerator struct {
+	defs map[string]interface{}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaOf returns the schema of a value of type t
+func (g *jsonSchemaGenerator) schemaOf(t reflect.Type) map[string]interface{} {
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullableSchema(g.schemaOf(t.Elem()))
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		if _, defined := g.defs[t.Name()]; !defined {
+			// Reserve the name first, recursive types refer to themselves
+			g.defs[t.Name()] = nil
+			g.defs[t.Name()] = g.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + t.Name()}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.elementSchema(t.Elem())}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string"}
+		}
+		return map[string]interface{}{"type": "array", "items": g.elementSchema(t.Elem())}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	default:
+		// interface{} holds any value
+		return map[string]interface{}{}
+	}
+}
+
+// elementSchema returns the schema of a slice element or map value, nil slices, maps and pointers encode as null
+func (g *jsonSchemaGenerator) elementSchema(t reflect.Type) map[string]interface{} {
+	schema := g.schemaOf(t)
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+		return nullableSchema(schema)
+	}
+	return schema
+}
+
+// structSchema returns the object schema of a struct type, fields of embedded structs are promoted
+func (g *jsonSchemaGenerator) structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := []string{}
+	g.addStructFields(t, properties, &required)
+	sort.Strings(required)
+
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (g *jsonSchemaGenerator) addStructFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			g.addStructFields(field.Type, properties, required)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		omitEmpty := strings.Contains(","+options+",", ",omitempty,")
+		schema := g.schemaOf(field.Type)
+		if !omitEmpty {
+			*required = append(*required, name)
+			if kind := field.Type.Kind(); kind == reflect.Slice || kind == reflect.Map {
+				schema = nullableSchema(schema)
+			}
+		}
+		properties[name] = schema
+	}
+}
+
+// nullableSchema allows null in addition to the values of schema
+func nullableSchema(schema map[string]interface{}) map[string]interface{} {
+	if typeName, ok := schema["type"].(string); ok {
+		nullable := make(map[string]interface{}, len(schema))
+		for key, value := range schema {
+			nullable[key] = value
+		}
+		nullable["type"] = []string{typeName, "null"}
+		return nullable
+	}
+	if len(schema) == 0 {
+		return schema
+	}
+	return map[string]interface{}{"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}}}
+}
+
+// validateJSONSchema validates a JSON document against a JSON Schema, returning the violations as
+// "/services/0/service_name: expected string, got number". It supports the keywords generateJSONSchema emits: type,
+// properties, required, additionalProperties, items, anyOf and $ref to $defs.
+func validateJSONSchema(schema, document []byte) ([]string, error) {
+	var root interface{}
+	if err := json.Unmarshal(schema, &root); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	validator := jsonSchemaValidator{root: root.(map[string]interface{})}
+	validator.validate(validator.root, value, "")
+	return validator.violations, nil
+}
+
+type jsonSchemaValidator struct {
+	root       map[string]interface{}
+	violations []string
+}
+
+func (v *jsonSchemaValidator) fail(path, format string, args ...interface{}) {
+	if path == "" {
+		path = "/"
+	}
+	v.violations = append(v.violations, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *jsonSchemaValidator) validate(schema map[string]interface{}, value interface{}, path string) {
+	if ref, ok := schema["$ref"].(string); ok {
+		defs, _ := v.root["$defs"].(map[string]interface{})
+		def, ok := defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{})
+		if !ok {
+			v.fail(path, "unresolved schema reference %s", ref)
+			return
+		}
+		v.validate(def, value, path)
+		return
+	}
+
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		var first []string
+		for i, option := range anyOf {
+			candidate := jsonSchemaValidator{root: v.root}
+			candidate.validate(option.(map[string]interface{}), value, path)
+			if len(candidate.violations) == 0 {
+				return
+			}
+			if i == 0 {
+				first = candidate.violations
+			}
+		}
+		v.violations = append(v.violations, first...)
+		return
+	}
+
+	if types, ok := schema["type"]; ok {
+		actual := jsonValueType(value)
+		matched := false
+		var expected []string
+		switch types := types.(type) {
+		case string:
+			expected = []string{types}
+		case []interface{}:
+			for _, t := range types {
+				expected = append(expected, t.(string))
+			}
+		}
+		for _, t := range expected {
+			if t == actual || (t == "number" && actual == "integer") {
+				matched = true
+			}
+		}
+		if !matched {
+			v.fail(path, "expected %s, got %s", strings.Join(expected, " or "), actual)
+			return
+		}
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if _, present := value[name.(string)]; !present {
+					v.fail(path, "missing required property %s", name)
+				}
+			}
+		}
+		names := make([]string, 0, len(value))
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			propertyPath := path + "/" + name
+			if property, ok := properties[name].(map[string]interface{}); ok {
+				v.validate(property, value[name], propertyPath)
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					v.fail(propertyPath, "property is not allowed")
+				}
+			case map[string]interface{}:
+				v.validate(additional, value[name], propertyPath)
+			}
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range value {
+				v.validate(items, item, fmt.Sprintf("%s/%d", path, i))
+			}
+		}
+	}
+}
+
+// jsonValueType returns the JSON Schema type of a value decoded with UseNumber
+func jsonValueType(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
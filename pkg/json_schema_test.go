@@ -0,0 +1,72 @@
+package pkg
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateSchemas = flag.Bool("update-schemas", false, "Regenerate the embedded JSON schemas from the Go types")
+
+func TestJSONSchemas_UpToDate(t *testing.T) {
+	for _, name := range JSONSchemaNames() {
+		generated, err := generateJSONSchema(name)
+		require.NoError(t, err)
+		if *updateSchemas {
+			require.NoError(t, os.WriteFile(filepath.Join("schemas", name+".schema.json"), generated, 0644))
+			continue
+		}
+		embedded, err := JSONSchema(name)
+		require.NoError(t, err)
+		assert.Equal(t, string(generated), string(embedded), "schemas/%s.schema.json is outdated, run go generate ./pkg", name)
+	}
+}
+
+func TestJSONSchema_Unknown(t *testing.T) {
+	_, err := JSONSchema("provider")
+	assert.ErrorContains(t, err, `unknown JSON schema "provider", expected one of index, resource, datasource, ephemeral`)
+}
+
+func TestValidateJSONSchema(t *testing.T) {
+	schema, err := JSONSchema(JSONSchemaResource)
+	require.NoError(t, err)
+
+	violations, err := validateJSONSchema(schema, []byte(`{
+		"id": "azurerm/resources/azurerm_key_vault/legacy_pluginsdk",
+		"terraform_type": "azurerm_key_vault",
+		"struct_type": "",
+		"namespace": "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault",
+		"registration_method": "SupportedResources",
+		"sdk_type": "legacy_pluginsdk",
+		"schema_version": 2,
+		"timeouts": {"create": 30},
+		"schema": [{"name": "name", "type": "TypeString"}]
+	}`))
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+
+	violations, err = validateJSONSchema(schema, []byte(`{
+		"id": "azurerm/resources/azurerm_key_vault/legacy_pluginsdk",
+		"terraform_type": 1,
+		"namespace": "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault",
+		"registration_method": "SupportedResources",
+		"sdk_type": "legacy_pluginsdk",
+		"schema_version": 2.5,
+		"timeouts": {"create": "30m"},
+		"schema": [{"name": "name", "type": "TypeString", "computed_only": true}],
+		"owner": "team-a"
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"/: missing required property struct_type",
+		"/owner: property is not allowed",
+		"/schema/0/computed_only: property is not allowed",
+		"/schema_version: expected integer, got number",
+		"/terraform_type: expected string, got integer",
+		"/timeouts/create: expected integer, got string",
+	}, violations)
+}
@@ -7,7 +7,8 @@ import (
 )
 
 type LegacyDataSourceMethods struct {
-	ReadMethod string `json:"read_method,omitempty"` // "dataSourceReadFunc"
+	ReadMethod string            `json:"read_method,omitempty"` // "dataSourceReadFunc"
+	Schema     []SchemaAttribute `json:"-"`                     // Written to the data source files
 }
 
 func extractDataSourceMethodsFromPackage(registrationMethod string, packageInfo *gophon.PackageInfo) *LegacyDataSourceMethods {
@@ -19,7 +20,7 @@ func extractDataSourceMethodsFromPackage(registrationMethod string, packageInfo
 	for _, funcInfo := range packageInfo.Functions {
 		if funcInfo.Name == registrationMethod && funcInfo.FuncDecl != nil {
 			// Extract data source methods from the function declaration
-			return extractDataSourceMethodsFromFunction(funcInfo.FuncDecl)
+			return extractDataSourceMethodsFromFunction(funcInfo.FuncDecl, packageInfo)
 		}
 	}
 
@@ -27,7 +28,7 @@ func extractDataSourceMethodsFromPackage(registrationMethod string, packageInfo
 }
 
 // extractDataSourceMethodsFromFunction extracts data source methods from a data source function body
-func extractDataSourceMethodsFromFunction(fn *ast.FuncDecl) *LegacyDataSourceMethods {
+func extractDataSourceMethodsFromFunction(fn *ast.FuncDecl, packageInfo *gophon.PackageInfo) *LegacyDataSourceMethods {
 	methods := &LegacyDataSourceMethods{}
 
 	if fn.Body == nil {
@@ -49,7 +50,7 @@ func extractDataSourceMethodsFromFunction(fn *ast.FuncDecl) *LegacyDataSourceMet
 				return true
 			}
 			if compLit, ok := unaryExpr.X.(*ast.CompositeLit); ok {
-				extractFromDataSourceLiteral(compLit, methods)
+				extractFromDataSourceLiteral(compLit, fn, packageInfo, methods)
 			}
 		}
 
@@ -69,7 +70,7 @@ func extractDataSourceMethodsFromFunction(fn *ast.FuncDecl) *LegacyDataSourceMet
 				return true
 			}
 			if compLit, ok := unaryExpr.X.(*ast.CompositeLit); ok {
-				extractFromDataSourceLiteral(compLit, methods)
+				extractFromDataSourceLiteral(compLit, fn, packageInfo, methods)
 			}
 		}
 
@@ -80,8 +81,8 @@ func extractDataSourceMethodsFromFunction(fn *ast.FuncDecl) *LegacyDataSourceMet
 }
 
 // extractFromDataSourceLiteral parses a pluginsdk.Resource composite literal for data sources
-// and extracts only read method names
-func extractFromDataSourceLiteral(compLit *ast.CompositeLit, methods *LegacyDataSourceMethods) {
+// and extracts the read method name and the attributes of the Schema map, resolved in the scope of fn
+func extractFromDataSourceLiteral(compLit *ast.CompositeLit, fn *ast.FuncDecl, packageInfo *gophon.PackageInfo, methods *LegacyDataSourceMethods) {
 	for _, elt := range compLit.Elts {
 		kv, ok := elt.(*ast.KeyValueExpr)
 		if !ok {
@@ -94,6 +95,12 @@ func extractFromDataSourceLiteral(compLit *ast.CompositeLit, methods *LegacyData
 			fieldName = ident.Name
 		}
 
+		// Arguments and exported attributes are declared together in the Schema map
+		if fieldName == "Schema" {
+			methods.Schema = schemaAttributesFromEntries(resolveSchemaEntries(kv.Value, fn, packageInfo, 0), packageInfo, 0)
+			continue
+		}
+
 		// Extract function reference from the value
 		funcName := extractFunctionReference(kv.Value)
 		if funcName == "" {
@@ -0,0 +1,65 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractDataSourceMethodsFromPackage_Schema(t *testing.T) {
+	source := `package keyvault
+
+func dataSourceKeyVault() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Read: dataSourceKeyVaultRead,
+
+		Schema: map[string]*pluginsdk.Schema{
+			"name": {
+				Type:         pluginsdk.TypeString,
+				Required:     true,
+				ValidateFunc: validate.VaultName,
+			},
+
+			"resource_group_name": commonschema.ResourceGroupNameForDataSource(),
+
+			"network_acls": {
+				Type:     pluginsdk.TypeList,
+				Computed: true,
+				Elem:     networkAclsDataSourceBlock(),
+			},
+		},
+	}
+}
+
+func networkAclsDataSourceBlock() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Schema: map[string]*pluginsdk.Schema{
+			"default_action": {
+				Type:     pluginsdk.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+	methods := extractDataSourceMethodsFromPackage("dataSourceKeyVault", packageInfo)
+	require.NotNil(t, methods)
+
+	assert.Equal(t, "dataSourceKeyVaultRead", methods.ReadMethod)
+	assert.Equal(t, []SchemaAttribute{
+		{Name: "name", Type: "TypeString", ValidateFuncs: []string{"validate.VaultName"}, Required: true},
+		{Name: "resource_group_name", SchemaFunc: "commonschema.ResourceGroupNameForDataSource", Required: true},
+		{Name: "network_acls", Type: "TypeList", Computed: true, Block: []SchemaAttribute{
+			{Name: "default_action", Type: "TypeString", Computed: true, Depth: 1},
+		}},
+	}, methods.Schema)
+
+	service := ServiceRegistration{
+		SupportedDataSources: map[string]string{"azurerm_key_vault": "dataSourceKeyVault"},
+		DataSourceMethods:    map[string]*LegacyDataSourceMethods{"azurerm_key_vault": methods},
+	}
+	dataSource := newTerraformDataSourceInfo("azurerm_key_vault", "", "dataSourceKeyVault", "legacy_pluginsdk", service)
+	assert.Equal(t, methods.Schema, dataSource.Schema)
+}
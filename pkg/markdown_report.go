@@ -0,0 +1,144 @@
+package pkg
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// MarkdownReportFileName is the file name of the human-readable report written with OutputConfig.MarkdownReport
+const MarkdownReportFileName = "REPORT.md"
+
+// BuildMarkdownReport renders a human-readable summary of the index for release notes: the provider totals, the
+// distribution of legacy and modern SDK registrations and a table of services. With a ReportBaseline the totals and
+// services are compared with the previously indexed version, newly indexed services are marked as new.
+func (index *TerraformProviderIndex) BuildMarkdownReport() string {
+	providerName := index.Output.ProviderName
+	if providerName == "" {
+		providerName = DefaultProviderName
+	}
+	statistics := buildProviderStatistics(index.Services)
+	baseline := index.ReportBaseline
+
+	var report strings.Builder
+	fmt.Fprintf(&report, "# terraform-provider-%s %s\n\n", providerName, index.Version)
+
+	totals := []struct {
+		name  string
+		count func(ProviderStatistics) int
+	}{
+		{"Services", func(s ProviderStatistics) int { return s.ServiceCount }},
+		{"Resources", func(s ProviderStatistics) int { return s.TotalResources }},
+		{"Data sources", func(s ProviderStatistics) int { return s.TotalDataSources }},
+		{"Ephemeral resources", func(s ProviderStatistics) int { return s.EphemeralResources }},
+		{"Deprecated resources", func(s ProviderStatistics) int { return s.DeprecatedResources }},
+	}
+	if baseline != nil {
+		fmt.Fprintf(&report, "| | Total | %s | Change |\n|---|---:|---:|---:|\n", baseline.Version)
+	} else {
+		report.WriteString("| | Total |\n|---|---:|\n")
+	}
+	for _, total := range totals {
+		count := total.count(statistics)
+		if baseline != nil {
+			previous := total.count(baseline.Statistics)
+			fmt.Fprintf(&report, "| %s | %d | %d | %s |\n", total.name, count, previous, formatChange(count-previous))
+		} else {
+			fmt.Fprintf(&report, "| %s | %d |\n", total.name, count)
+		}
+	}
+
+	var legacyDataSources int
+	for _, service := range statistics.Services {
+		legacyDataSources += service.LegacyDataSources
+	}
+	report.WriteString("\n## SDK Types\n\n| | Legacy (Plugin SDK) | Modern (typed SDK) | Modern share |\n|---|---:|---:|---:|\n")
+	fmt.Fprintf(&report, "| Resources | %d | %d | %s |\n", statistics.LegacyResources, statistics.ModernResources,
+		formatShare(statistics.ModernResources, statistics.LegacyResources+statistics.ModernResources))
+	fmt.Fprintf(&report, "| Data sources | %d | %d | %s |\n", legacyDataSources, statistics.TotalDataSources-legacyDataSources,
+		formatShare(statistics.TotalDataSources-legacyDataSources, statistics.TotalDataSources))
+
+	report.WriteString("\n## Services\n\n| Service | Legacy resources | Modern resources | Data sources | Ephemeral resources | Deprecated resources |")
+	if baseline != nil {
+		fmt.Fprintf(&report, " Change since %s |\n|---|---:|---:|---:|---:|---:|---|\n", baseline.Version)
+	} else {
+		report.WriteString("\n|---|---:|---:|---:|---:|---:|\n")
+	}
+	serviceNames := make([]string, 0, len(statistics.Services))
+	for serviceName := range statistics.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		service := statistics.Services[serviceName]
+		fmt.Fprintf(&report, "| %s | %d | %d | %d | %d | %d |", serviceName, service.LegacyResources, service.ModernResources,
+			service.DataSources, service.EphemeralResources, service.DeprecatedResources)
+		if baseline != nil {
+			fmt.Fprintf(&report, " %s |", serviceChange(service, baseline.Services, serviceName))
+		}
+		report.WriteString("\n")
+	}
+	if baseline != nil {
+		var removed []string
+		for serviceName := range baseline.Services {
+			if _, ok := statistics.Services[serviceName]; !ok {
+				removed = append(removed, serviceName)
+			}
+		}
+		if len(removed) > 0 {
+			sort.Strings(removed)
+			fmt.Fprintf(&report, "\nRemoved since %s: %s\n", baseline.Version, strings.Join(removed, ", "))
+		}
+	}
+	return report.String()
+}
+
+// serviceChange describes how the counts of a service changed since the baseline, "new" for a service it lacks
+func serviceChange(service ServiceStatistics, baseline map[string]ServiceStatistics, serviceName string) string {
+	previous, ok := baseline[serviceName]
+	if !ok {
+		return "new"
+	}
+	var changes []string
+	for _, change := range []struct {
+		name            string
+		count, previous int
+	}{
+		{"resources", service.LegacyResources + service.ModernResources, previous.LegacyResources + previous.ModernResources},
+		{"data sources", service.DataSources, previous.DataSources},
+		{"ephemeral resources", service.EphemeralResources, previous.EphemeralResources},
+	} {
+		if change.count != change.previous {
+			changes = append(changes, fmt.Sprintf("%s %s", formatChange(change.count-change.previous), change.name))
+		}
+	}
+	return strings.Join(changes, ", ")
+}
+
+// formatChange formats a count difference with its sign, "+3", "-1" or "0"
+func formatChange(difference int) string {
+	if difference > 0 {
+		return fmt.Sprintf("+%d", difference)
+	}
+	return fmt.Sprintf("%d", difference)
+}
+
+// formatShare formats count as a percentage of total, "-" for an empty total
+func formatShare(count, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", float64(count)*100/float64(total))
+}
+
+// writeMarkdownReportFile writes REPORT.md
+func (index *TerraformProviderIndex) writeMarkdownReportFile(outputDir string) error {
+	filePath := filepath.Join(outputDir, MarkdownReportFileName)
+	if err := afero.WriteFile(index.Output.fs(), filePath, []byte(index.BuildMarkdownReport()), 0644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", filePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformProviderIndex_BuildMarkdownReport(t *testing.T) {
+	index := createTestTerraformProviderIndex()
+
+	report := index.BuildMarkdownReport()
+
+	assert.Contains(t, report, "# terraform-provider-azurerm v3.0.0\n")
+	assert.Contains(t, report, "| Resources | 5 |\n")
+	assert.Contains(t, report, "| Resources | 2 | 2 | 50.0% |\n")
+	assert.Contains(t, report, "| Data sources | 2 | 1 | 33.3% |\n")
+	assert.Contains(t, report, "| keyvault | 2 | 2 | 3 | 1 | 0 |\n")
+	assert.NotContains(t, report, "Change")
+}
+
+func TestTerraformProviderIndex_BuildMarkdownReport_Baseline(t *testing.T) {
+	index := createTestTerraformProviderIndex()
+	index.ReportBaseline = &StatsHistoryEntry{
+		Version:    "v2.99.0",
+		Statistics: ProviderStatistics{ServiceCount: 2, TotalResources: 3, TotalDataSources: 3},
+		Services: map[string]ServiceStatistics{
+			"keyvault": {LegacyResources: 2, ModernResources: 1, DataSources: 3},
+			"legacy":   {LegacyResources: 1},
+		},
+	}
+
+	report := index.BuildMarkdownReport()
+
+	assert.Contains(t, report, "| | Total | v2.99.0 | Change |\n")
+	assert.Contains(t, report, "| Services | 1 | 2 | -1 |\n")
+	assert.Contains(t, report, "| Resources | 5 | 3 | +2 |\n")
+	assert.Contains(t, report, "| Data sources | 3 | 3 | 0 |\n")
+	assert.Contains(t, report, "| keyvault | 2 | 2 | 3 | 1 | 0 | +1 resources, +1 ephemeral resources |\n")
+	assert.Contains(t, report, "Removed since v2.99.0: legacy\n")
+
+	delete(index.ReportBaseline.Services, "keyvault")
+	assert.Contains(t, index.BuildMarkdownReport(), "| keyvault | 2 | 2 | 3 | 1 | 0 | new |\n")
+}
+
+func TestTerraformProviderIndex_WriteIndexFiles_MarkdownReport(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	index := createTestTerraformProviderIndex()
+	index.Output.Fs = fs
+
+	require.NoError(t, index.WriteIndexFiles("/index", nil))
+	exists, err := afero.Exists(fs, "/index/REPORT.md")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	index.Output.MarkdownReport = true
+	require.NoError(t, index.WriteIndexFiles("/index", nil))
+	content, err := afero.ReadFile(fs, "/index/REPORT.md")
+	require.NoError(t, err)
+	assert.Equal(t, index.BuildMarkdownReport(), string(content))
+}
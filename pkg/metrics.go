@@ -0,0 +1,155 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// Metrics file formats
+const (
+	MetricsFormatJSON       = "json"       // metrics.json
+	MetricsFormatPrometheus = "prometheus" // metrics.prom, for the textfile collector of the Prometheus node exporter
+)
+
+// IndexMetrics measures the health of an index generation, written to metrics.json or metrics.prom with
+// OutputConfig.Metrics so pipeline dashboards can plot it over provider versions
+type IndexMetrics struct {
+	Version              string  `json:"version"`                // "v4.20.0"
+	ScanDurationSeconds  float64 `json:"scan_duration_seconds"`  // 42.7
+	WriteDurationSeconds float64 `json:"write_duration_seconds"` // 3.1, writing every file but the metrics file
+	ServicesScanned      int     `json:"services_scanned"`       // Service directories scanned, 132
+	ServicesIndexed      int     `json:"services_indexed"`       // Scanned services with registrations, 130
+	ParseFailures        int     `json:"parse_failures"`         // Packages that couldn't be loaded and extractions that panicked
+	Warnings             int     `json:"warnings"`               // All problems of the scan report, parse failures included
+	FilesWritten         int64   `json:"files_written"`          // 2716
+	BytesWritten         int64   `json:"bytes_written"`          // 48213344
+}
+
+// MetricsFileName returns the file name of the metrics file of a metrics format
+func MetricsFileName(format string) string {
+	if format == MetricsFormatPrometheus {
+		return "metrics.prom"
+	}
+	return "metrics.json"
+}
+
+// BuildMetrics measures the scan of the index and a generation writing files and bytes in writeDuration
+func (index *TerraformProviderIndex) BuildMetrics(writeDuration time.Duration, files, bytes int64) IndexMetrics {
+	metrics := IndexMetrics{
+		Version:              index.Version,
+		ScanDurationSeconds:  index.ScanDuration.Seconds(),
+		WriteDurationSeconds: writeDuration.Seconds(),
+		ServicesScanned:      index.ScannedServices,
+		ServicesIndexed:      len(index.Services),
+		Warnings:             len(index.Warnings),
+		FilesWritten:         files,
+		BytesWritten:         bytes,
+	}
+	for _, warning := range index.Warnings {
+		if warning.Kind == ScanWarningParseError || warning.Kind == ScanWarningPanic {
+			metrics.ParseFailures++
+		}
+	}
+	return metrics
+}
+
+// writeMetricsFile writes the metrics in the configured metrics format
+func (index *TerraformProviderIndex) writeMetricsFile(outputDir string, metrics IndexMetrics) error {
+	var content []byte
+	switch index.Output.Metrics {
+	case MetricsFormatJSON:
+		var err error
+		if content, err = json.MarshalIndent(metrics, "", "  "); err != nil {
+			return fmt.Errorf("failed to marshal metrics: %w", err)
+		}
+	case MetricsFormatPrometheus:
+		content = prometheusMetrics(index.Output.ProviderName, metrics)
+	default:
+		return fmt.Errorf("unknown metrics format %q, expected %s or %s", index.Output.Metrics, MetricsFormatJSON, MetricsFormatPrometheus)
+	}
+
+	filePath := filepath.Join(outputDir, MetricsFileName(index.Output.Metrics))
+	if err := afero.WriteFile(index.Output.fs(), filePath, content, 0644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", filePath, err)
+	}
+	return nil
+}
+
+// prometheusMetrics renders the metrics as gauges in the Prometheus text exposition format, labelled with the
+// provider and version
+func prometheusMetrics(providerName string, metrics IndexMetrics) []byte {
+	if providerName == "" {
+		providerName = DefaultProviderName
+	}
+	labels := fmt.Sprintf("{provider=%q,version=%q}", providerName, metrics.Version)
+
+	var content bytes.Buffer
+	for _, gauge := range []struct {
+		name, help string
+		value      interface{}
+	}{
+		{"scan_duration_seconds", "Time spent scanning the service packages.", metrics.ScanDurationSeconds},
+		{"write_duration_seconds", "Time spent writing the index files.", metrics.WriteDurationSeconds},
+		{"services_scanned", "Service directories scanned.", metrics.ServicesScanned},
+		{"services_indexed", "Scanned services with registrations.", metrics.ServicesIndexed},
+		{"parse_failures", "Packages that couldn't be loaded and extractions that panicked.", metrics.ParseFailures},
+		{"warnings", "Problems found while scanning.", metrics.Warnings},
+		{"files_written", "Index files written.", metrics.FilesWritten},
+		{"bytes_written", "Bytes of index files written.", metrics.BytesWritten},
+	} {
+		name := "terraform_provider_index_" + gauge.name
+		fmt.Fprintf(&content, "# HELP %s %s\n# TYPE %s gauge\n%s%s %v\n", name, gauge.help, name, name, labels, gauge.value)
+	}
+	return content.Bytes()
+}
+
+// countingFs counts the files opened for writing and the bytes written through it, safe for the parallel writers
+type countingFs struct {
+	afero.Fs
+	files atomic.Int64
+	bytes atomic.Int64
+}
+
+func (fs *countingFs) Create(name string) (afero.File, error) {
+	return fs.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+}
+
+func (fs *countingFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	file, err := fs.Fs.OpenFile(name, flag, perm)
+	if err != nil || flag&(os.O_WRONLY|os.O_RDWR) == 0 {
+		return file, err
+	}
+	fs.files.Add(1)
+	return countingFile{File: file, bytes: &fs.bytes}, nil
+}
+
+// countingFile adds the bytes written to it to the counter of its countingFs
+type countingFile struct {
+	afero.File
+	bytes *atomic.Int64
+}
+
+func (f countingFile) Write(p []byte) (int, error) {
+	n, err := f.File.Write(p)
+	f.bytes.Add(int64(n))
+	return n, err
+}
+
+func (f countingFile) WriteAt(p []byte, off int64) (int, error) {
+	n, err := f.File.WriteAt(p, off)
+	f.bytes.Add(int64(n))
+	return n, err
+}
+
+func (f countingFile) WriteString(s string) (int, error) {
+	n, err := f.File.WriteString(s)
+	f.bytes.Add(int64(n))
+	return n, err
+}
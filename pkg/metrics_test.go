@@ -0,0 +1,116 @@
+package pkg
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformProviderIndex_BuildMetrics(t *testing.T) {
+	index := createTestTerraformProviderIndex()
+	index.ScanDuration = 1500 * time.Millisecond
+	index.ScannedServices = 3
+	index.Warnings = []ScanWarning{
+		{Service: "broken", Kind: ScanWarningParseError},
+		{Service: "keyvault", Kind: ScanWarningPanic},
+		{Service: "keyvault", Kind: ScanWarningUnresolvedRegistration},
+	}
+
+	metrics := index.BuildMetrics(2*time.Second, 10, 2048)
+
+	assert.Equal(t, IndexMetrics{
+		Version:              "v3.0.0",
+		ScanDurationSeconds:  1.5,
+		WriteDurationSeconds: 2,
+		ServicesScanned:      3,
+		ServicesIndexed:      1,
+		ParseFailures:        2,
+		Warnings:             3,
+		FilesWritten:         10,
+		BytesWritten:         2048,
+	}, metrics)
+}
+
+func TestTerraformProviderIndex_WriteIndexFiles_Metrics(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	index := createTestTerraformProviderIndex()
+	index.Output.Fs = fs
+	index.Output.Metrics = MetricsFormatJSON
+
+	require.NoError(t, index.WriteIndexFiles("/index", nil))
+
+	data, err := afero.ReadFile(fs, "/index/metrics.json")
+	require.NoError(t, err)
+	var metrics IndexMetrics
+	require.NoError(t, json.Unmarshal(data, &metrics))
+
+	var files, bytes int64
+	require.NoError(t, afero.Walk(fs, "/index", func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || path == "/index/metrics.json" {
+			return err
+		}
+		files++
+		bytes += info.Size()
+		return nil
+	}))
+	assert.Equal(t, files, metrics.FilesWritten)
+	assert.Equal(t, bytes, metrics.BytesWritten)
+	assert.Same(t, fs, index.Output.Fs, "the output file system is left untouched by counting")
+}
+
+func TestTerraformProviderIndex_WriteIndexFiles_MetricsOfConcurrentWriters(t *testing.T) {
+	var wg sync.WaitGroup
+	filesystems := []afero.Fs{afero.NewMemMapFs(), afero.NewMemMapFs()}
+	indexes := make([]*TerraformProviderIndex, len(filesystems))
+	for i, fs := range filesystems {
+		indexes[i] = createTestTerraformProviderIndex()
+		indexes[i].Output.Metrics = MetricsFormatJSON
+		indexes[i].Output.Fs = fs
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, indexes[i].WriteIndexFiles("/index", nil))
+		}()
+	}
+	wg.Wait()
+
+	var counts []int64
+	for i, fs := range filesystems {
+		data, err := afero.ReadFile(fs, "/index/metrics.json")
+		require.NoError(t, err)
+		var metrics IndexMetrics
+		require.NoError(t, json.Unmarshal(data, &metrics))
+		counts = append(counts, metrics.FilesWritten)
+		assert.Same(t, fs, indexes[i].Output.Fs)
+	}
+	assert.Positive(t, counts[0])
+	assert.Equal(t, counts[0], counts[1], "each writer counts its own files only")
+}
+
+func TestTerraformProviderIndex_WriteMetricsFile_Prometheus(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	index := createTestTerraformProviderIndex()
+	index.Output.Fs = fs
+	index.Output.Metrics = MetricsFormatPrometheus
+
+	require.NoError(t, index.writeMetricsFile("/index", IndexMetrics{Version: "v3.0.0", ScanDurationSeconds: 1.5, FilesWritten: 10}))
+
+	data, err := afero.ReadFile(fs, "/index/metrics.prom")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# TYPE terraform_provider_index_scan_duration_seconds gauge\n")
+	assert.Contains(t, string(data), `terraform_provider_index_scan_duration_seconds{provider="azurerm",version="v3.0.0"} 1.5`+"\n")
+	assert.Contains(t, string(data), `terraform_provider_index_files_written{provider="azurerm",version="v3.0.0"} 10`+"\n")
+}
+
+func TestTerraformProviderIndex_WriteMetricsFile_UnknownFormat(t *testing.T) {
+	index := createTestTerraformProviderIndex()
+	index.Output.Metrics = "csv"
+
+	assert.ErrorContains(t, index.writeMetricsFile("/index", IndexMetrics{}), `unknown metrics format "csv"`)
+}
@@ -0,0 +1,58 @@
+package pkg
+
+import (
+	"sort"
+	"strings"
+)
+
+// RemovedEntry is a registration slated for removal in the next major version of the provider, either because it's
+// only registered while the next major's feature flag is disabled or because it's deprecated
+type RemovedEntry struct {
+	Kind               string `json:"kind"`                          // "resources", "datasources" or "ephemeral"
+	TerraformType      string `json:"terraform_type"`                // "azurerm_key_vault_access_policy"
+	Service            string `json:"service"`                       // "keyvault"
+	FeatureFlag        string `json:"feature_flag,omitempty"`        // "!features.FivePointOh" (optional)
+	DeprecationMessage string `json:"deprecation_message,omitempty"` // "The `azurerm_foo` resource has been superseded by the `azurerm_bar` resource" (optional)
+}
+
+// BuildRemovedInNextMajor lists the resources, data sources and ephemeral resources gated by a negated feature flag,
+// such as "!features.FivePointOh", or deprecated, ordered by kind and Terraform type
+func (index *TerraformProviderIndex) BuildRemovedInNextMajor() []RemovedEntry {
+	removed := []RemovedEntry{}
+	for _, service := range index.Services {
+		removed = appendRemovedEntries(removed, DocumentKindResource, service.ServiceName, service.ResourceFeatureFlags, service.ResourceDeprecations)
+		removed = appendRemovedEntries(removed, DocumentKindDataSource, service.ServiceName, service.DataSourceFeatureFlags, service.DataSourceDeprecations)
+		removed = appendRemovedEntries(removed, DocumentKindEphemeral, service.ServiceName, service.EphemeralFeatureFlags, nil)
+	}
+	sort.Slice(removed, func(i, j int) bool {
+		if removed[i].Kind != removed[j].Kind {
+			return removed[i].Kind < removed[j].Kind
+		}
+		return removed[i].TerraformType < removed[j].TerraformType
+	})
+	return removed
+}
+
+// appendRemovedEntries appends the entries of one kind of a service whose feature flag is negated or which carry a
+// deprecation message
+func appendRemovedEntries(removed []RemovedEntry, kind, service string, featureFlags, deprecations map[string]string) []RemovedEntry {
+	entries := make(map[string]*RemovedEntry)
+	entry := func(terraformType string) *RemovedEntry {
+		if entries[terraformType] == nil {
+			entries[terraformType] = &RemovedEntry{Kind: kind, TerraformType: terraformType, Service: service}
+		}
+		return entries[terraformType]
+	}
+	for terraformType, flag := range featureFlags {
+		if strings.HasPrefix(flag, "!") {
+			entry(terraformType).FeatureFlag = flag
+		}
+	}
+	for terraformType, message := range deprecations {
+		entry(terraformType).DeprecationMessage = message
+	}
+	for _, e := range entries {
+		removed = append(removed, *e)
+	}
+	return removed
+}
@@ -0,0 +1,50 @@
+package pkg
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformProviderIndex_BuildRemovedInNextMajor(t *testing.T) {
+	index := &TerraformProviderIndex{
+		Services: []ServiceRegistration{
+			{
+				ServiceName: "keyvault",
+				ResourceFeatureFlags: map[string]string{
+					"azurerm_key_vault_access_policy": "!features.FivePointOh",
+					"azurerm_key_vault_managed_key":   "features.FivePointOh",
+				},
+				ResourceDeprecations: map[string]string{
+					"azurerm_key_vault_access_policy": "The `azurerm_key_vault_access_policy` resource will be removed in v5.0",
+				},
+				DataSourceDeprecations: map[string]string{
+					"azurerm_key_vault_access_policy": "This data source will be removed in v5.0",
+				},
+			},
+			{
+				ServiceName:           "compute",
+				EphemeralFeatureFlags: map[string]string{"azurerm_ssh_key": "!features.FivePointOh"},
+				ResourceDeprecations:  map[string]string{"azurerm_virtual_machine": "superseded by azurerm_linux_virtual_machine"},
+			},
+		},
+	}
+
+	assert.Equal(t, []RemovedEntry{
+		{Kind: DocumentKindDataSource, TerraformType: "azurerm_key_vault_access_policy", Service: "keyvault", DeprecationMessage: "This data source will be removed in v5.0"},
+		{Kind: DocumentKindEphemeral, TerraformType: "azurerm_ssh_key", Service: "compute", FeatureFlag: "!features.FivePointOh"},
+		{Kind: DocumentKindResource, TerraformType: "azurerm_key_vault_access_policy", Service: "keyvault", FeatureFlag: "!features.FivePointOh", DeprecationMessage: "The `azurerm_key_vault_access_policy` resource will be removed in v5.0"},
+		{Kind: DocumentKindResource, TerraformType: "azurerm_virtual_machine", Service: "compute", DeprecationMessage: "superseded by azurerm_linux_virtual_machine"},
+	}, index.BuildRemovedInNextMajor())
+	assert.Equal(t, []RemovedEntry{}, (&TerraformProviderIndex{}).BuildRemovedInNextMajor())
+}
+
+func TestScanTerraformProviderServices_RemovedInNextMajor(t *testing.T) {
+	index, err := ScanTerraformProviderServices(filepath.Join("testharness", "internal", "services"), "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, index.BuildRemovedInNextMajor(), index.RemovedInNextMajor)
+	assert.NotNil(t, index.RemovedInNextMajor)
+}
@@ -0,0 +1,164 @@
+package pkg
+
+import (
+	"go/ast"
+	"path/filepath"
+	"sort"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// OrphanedImplementation is a resource or data source implemented in a service package that no registration method
+// of the service registers, likely forgotten when it was added or left behind when it was removed
+type OrphanedImplementation struct {
+	Service  string `json:"service"`        // "keyvault"
+	Kind     string `json:"kind"`           // "resources" or "datasources"
+	SDKType  string `json:"sdk_type"`       // "legacy_pluginsdk" or "modern_sdk"
+	Name     string `json:"name"`           // "resourceKeyVaultLegacy" for legacy functions, "KeyVaultLegacyResource" for structs
+	FileName string `json:"file_name"`      // "key_vault_legacy_resource.go"
+	Line     int    `json:"line,omitempty"` // 42, line of the function or struct declaration
+}
+
+// BuildOrphansReport lists the legacy resource functions, functions returning a *pluginsdk.Resource with a Read
+// function, and the typed structs implementing sdk.Resource or sdk.DataSource that the registration methods of their
+// service don't reference. Functions returning nested schema blocks have no Read function and aren't reported.
+func (index *TerraformProviderIndex) BuildOrphansReport() []OrphanedImplementation {
+	report := []OrphanedImplementation{}
+	for _, service := range index.Services {
+		report = append(report, service.orphanedImplementations()...)
+	}
+	sortOrphanedImplementations(report)
+	return report
+}
+
+// sortOrphanedImplementations orders orphans by service, kind and name
+func sortOrphanedImplementations(orphans []OrphanedImplementation) {
+	sort.Slice(orphans, func(i, j int) bool {
+		if orphans[i].Service != orphans[j].Service {
+			return orphans[i].Service < orphans[j].Service
+		}
+		if orphans[i].Kind != orphans[j].Kind {
+			return orphans[i].Kind < orphans[j].Kind
+		}
+		return orphans[i].Name < orphans[j].Name
+	})
+}
+
+// writeOrphansFile writes audit/orphans.json
+func (index *TerraformProviderIndex) writeOrphansFile(outputDir string) error {
+	return index.writeJSONFile(filepath.Join(outputDir, "audit", "orphans.json"), index.BuildOrphansReport())
+}
+
+// orphanedImplementations finds the resources and data sources of the service package its registrations miss, the
+// orphans found before the package was dropped when it was streamed
+func (s ServiceRegistration) orphanedImplementations() []OrphanedImplementation {
+	if s.Package == nil {
+		return s.orphans
+	}
+
+	registered := make(map[string]bool)
+	for _, registrationMethod := range s.SupportedResources {
+		registered[registrationMethod] = true
+	}
+	for _, registrationMethod := range s.SupportedDataSources {
+		registered[registrationMethod] = true
+	}
+	for _, structTypes := range [][]string{s.Resources, s.DataSources, s.ConditionalResources, s.ConditionalDataSources} {
+		for _, structType := range structTypes {
+			registered[structType] = true
+		}
+	}
+
+	var orphans []OrphanedImplementation
+	for _, funcInfo := range s.Package.Functions {
+		fn := funcInfo.FuncDecl
+		if fn == nil || fn.Recv != nil || registered[fn.Name.Name] {
+			continue
+		}
+		kind := legacyImplementationKind(fn)
+		if kind == "" {
+			continue
+		}
+		orphans = append(orphans, OrphanedImplementation{
+			Service:  s.ServiceName,
+			Kind:     kind,
+			SDKType:  "legacy_pluginsdk",
+			Name:     fn.Name.Name,
+			FileName: rangeFileName(funcInfo.Range),
+			Line:     rangeLine(funcInfo.Range),
+		})
+	}
+	// Typed implementations are found by their methods, the package may not declare the struct type itself
+	receivers := make(map[string]bool)
+	for _, funcInfo := range s.Package.Functions {
+		structType := receiverTypeName(funcInfo.FuncDecl)
+		if structType == "" || receivers[structType] || registered[structType] {
+			continue
+		}
+		receivers[structType] = true
+		kind := typedImplementationKind(structType, s.Package)
+		if kind == "" {
+			continue
+		}
+		declaration := typeRange(s.Package, structType)
+		if declaration == nil {
+			declaration = funcInfo.Range
+		}
+		orphans = append(orphans, OrphanedImplementation{
+			Service:  s.ServiceName,
+			Kind:     kind,
+			SDKType:  "modern_sdk",
+			Name:     structType,
+			FileName: rangeFileName(declaration),
+			Line:     rangeLine(declaration),
+		})
+	}
+	return orphans
+}
+
+// legacyImplementationKind returns whether a function builds a legacy resource or data source, a *pluginsdk.Resource
+// with a Read function and with or without a Create function, "" for any other function
+func legacyImplementationKind(fn *ast.FuncDecl) string {
+	for _, literal := range findResourceLiterals(fn) {
+		methods := &LegacyResourceCRUDFunctions{}
+		extractFromResourceLiteral(literal, methods)
+		if methods.ReadMethod == "" {
+			continue
+		}
+		if methods.CreateMethod == "" {
+			return DocumentKindDataSource
+		}
+		return DocumentKindResource
+	}
+	return ""
+}
+
+// typedImplementationKind returns whether a struct implements sdk.Resource or sdk.DataSource, both declaring
+// Arguments, Attributes, ModelObject and ResourceType methods while only resources implement Create, "" otherwise
+func typedImplementationKind(structType string, packageInfo *gophon.PackageInfo) string {
+	for _, method := range []string{"Arguments", "Attributes", "ModelObject", "ResourceType", "Read"} {
+		if findMethodDecl(packageInfo, structType, method) == nil {
+			return ""
+		}
+	}
+	if findMethodDecl(packageInfo, structType, "Create") == nil {
+		return DocumentKindDataSource
+	}
+	return DocumentKindResource
+}
+
+// rangeFileName returns the base name of the file of a gophon range, "" when unknown
+func rangeFileName(r *gophon.Range) string {
+	if r == nil || r.FileInfo == nil {
+		return ""
+	}
+	return filepath.Base(r.FileInfo.FileName)
+}
+
+// rangeLine returns the first line of a gophon range, 0 when unknown
+func rangeLine(r *gophon.Range) int {
+	if r == nil {
+		return 0
+	}
+	return r.StartLine
+}
@@ -0,0 +1,124 @@
+package pkg
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const orphansSource = `package keyvault
+
+func (r Registration) SupportedResources() map[string]*pluginsdk.Resource {
+	return map[string]*pluginsdk.Resource{
+		"azurerm_key_vault": resourceKeyVault(),
+	}
+}
+
+func (r Registration) Resources() []sdk.Resource {
+	return []sdk.Resource{
+		KeyVaultCertificateContactsResource{},
+	}
+}
+
+func resourceKeyVault() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Create: resourceKeyVaultCreate,
+		Read:   resourceKeyVaultRead,
+		Delete: resourceKeyVaultDelete,
+	}
+}
+
+func resourceKeyVaultLegacy() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Create: resourceKeyVaultCreate,
+		Read:   resourceKeyVaultRead,
+		Delete: resourceKeyVaultDelete,
+	}
+}
+
+func dataSourceKeyVaultForgotten() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Read: dataSourceKeyVaultRead,
+	}
+}
+
+func schemaNetworkAcls() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Schema: map[string]*pluginsdk.Schema{},
+	}
+}
+
+func (r KeyVaultCertificateContactsResource) Arguments() map[string]*pluginsdk.Schema { return nil }
+func (r KeyVaultCertificateContactsResource) Attributes() map[string]*pluginsdk.Schema { return nil }
+func (r KeyVaultCertificateContactsResource) ModelObject() interface{} { return nil }
+func (r KeyVaultCertificateContactsResource) ResourceType() string { return "azurerm_key_vault_certificate_contacts" }
+func (r KeyVaultCertificateContactsResource) Create() sdk.ResourceFunc { return sdk.ResourceFunc{} }
+func (r KeyVaultCertificateContactsResource) Read() sdk.ResourceFunc { return sdk.ResourceFunc{} }
+
+func (r KeyVaultEncryptedValueDataSource) Arguments() map[string]*pluginsdk.Schema { return nil }
+func (r KeyVaultEncryptedValueDataSource) Attributes() map[string]*pluginsdk.Schema { return nil }
+func (r KeyVaultEncryptedValueDataSource) ModelObject() interface{} { return nil }
+func (r KeyVaultEncryptedValueDataSource) ResourceType() string { return "azurerm_key_vault_encrypted_value" }
+func (r KeyVaultEncryptedValueDataSource) Read() sdk.ResourceFunc { return sdk.ResourceFunc{} }
+
+func (r *KeyVaultOrphanResource) Arguments() map[string]*pluginsdk.Schema { return nil }
+func (r *KeyVaultOrphanResource) Attributes() map[string]*pluginsdk.Schema { return nil }
+func (r *KeyVaultOrphanResource) ModelObject() interface{} { return nil }
+func (r *KeyVaultOrphanResource) ResourceType() string { return "azurerm_key_vault_orphan" }
+func (r *KeyVaultOrphanResource) Create() sdk.ResourceFunc { return sdk.ResourceFunc{} }
+func (r *KeyVaultOrphanResource) Read() sdk.ResourceFunc { return sdk.ResourceFunc{} }
+
+func (c Client) Read() {}`
+
+func orphansTestIndex(t *testing.T) *TerraformProviderIndex {
+	packageInfo := parsePackageInfo(t, orphansSource)
+	packageInfo.Files[0].FileName = filepath.Join("internal", "services", "keyvault", "key_vault_resource.go")
+
+	var supportedResources map[string]string
+	var resources []string
+	for _, fileInfo := range packageInfo.Files {
+		supportedResources = mergeMap(supportedResources, extractSupportedResourcesMappings(fileInfo.File))
+		resources = append(resources, extractResourcesStructTypes(fileInfo.File)...)
+	}
+	return &TerraformProviderIndex{Services: []ServiceRegistration{{
+		Package:            packageInfo,
+		ServiceName:        "keyvault",
+		SupportedResources: supportedResources,
+		Resources:          resources,
+	}}}
+}
+
+func TestBuildOrphansReport(t *testing.T) {
+	report := orphansTestIndex(t).BuildOrphansReport()
+
+	assert.Equal(t, []OrphanedImplementation{
+		{Service: "keyvault", Kind: DocumentKindDataSource, SDKType: "modern_sdk", Name: "KeyVaultEncryptedValueDataSource", FileName: "key_vault_resource.go", Line: 50},
+		{Service: "keyvault", Kind: DocumentKindDataSource, SDKType: "legacy_pluginsdk", Name: "dataSourceKeyVaultForgotten", FileName: "key_vault_resource.go", Line: 31},
+		{Service: "keyvault", Kind: DocumentKindResource, SDKType: "modern_sdk", Name: "KeyVaultOrphanResource", FileName: "key_vault_resource.go", Line: 56},
+		{Service: "keyvault", Kind: DocumentKindResource, SDKType: "legacy_pluginsdk", Name: "resourceKeyVaultLegacy", FileName: "key_vault_resource.go", Line: 23},
+	}, report)
+}
+
+func TestWriteOrphansFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	outputDir := "/test/output"
+	index := orphansTestIndex(t)
+	index.Output.Fs = fs
+	require.NoError(t, index.writeOrphansFile(outputDir))
+
+	data, err := afero.ReadFile(fs, filepath.Join(outputDir, "audit", "orphans.json"))
+	require.NoError(t, err)
+	var orphans []OrphanedImplementation
+	require.NoError(t, json.Unmarshal(data, &orphans))
+	assert.Len(t, orphans, 4)
+
+	empty := &TerraformProviderIndex{Output: OutputConfig{Fs: fs}}
+	require.NoError(t, empty.writeOrphansFile(outputDir))
+	data, err = afero.ReadFile(fs, filepath.Join(outputDir, "audit", "orphans.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `[]`, string(data))
+}
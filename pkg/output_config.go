@@ -0,0 +1,82 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/afero"
+)
+
+// DefaultProviderName is the provider name used to derive the main index file name when none is configured
+const DefaultProviderName = "azurerm"
+
+// OutputConfig controls how index files are written
+type OutputConfig struct {
+	ProviderName  string // "azurerm", used to derive the default main index file name
+	IndexFileName string // "terraform-provider-azurerm-index.json", overrides the derived main index file name
+	Format        string // "json" (default), "template" or "esbulk"
+	TemplatePath  string // "docs.md.tmpl", Go template rendered for each document with the template format
+	Workers       int    // 4, number of files written in parallel, one per CPU when zero
+	// Also write a services/<service name>.json summary of every service with the json format
+	ServiceSummaries bool
+	// Also write a human-readable REPORT.md summary with the json format
+	MarkdownReport bool
+	// Also write generation metrics in this format, "json" (metrics.json) or "prometheus" (metrics.prom), none when empty
+	Metrics string
+	// Write only the main index file with every document embedded, see SingleFileIndex, instead of the files of the
+	// json format
+	SingleFile bool
+	// File system the index files are written to, such as the one OpenRemoteOutput returns, the local file system when
+	// nil. Index files are always read from the local file system.
+	Fs afero.Fs
+}
+
+// fs returns the file system the index files are written to
+func (c OutputConfig) fs() afero.Fs {
+	if c.Fs != nil {
+		return c.Fs
+	}
+	return afero.NewOsFs()
+}
+
+// MainIndexFileName returns the configured main index file name, defaulting to terraform-provider-<name>-index.json
+func (c OutputConfig) MainIndexFileName() string {
+	if c.IndexFileName != "" {
+		return c.IndexFileName
+	}
+	providerName := c.ProviderName
+	if providerName == "" {
+		providerName = DefaultProviderName
+	}
+	return fmt.Sprintf("terraform-provider-%s-index.json", providerName)
+}
+
+// OutputDirVariables are the variables of an output directory template
+type OutputDirVariables struct {
+	Provider string // "azurerm"
+	Version  string // "v4.20.0"
+}
+
+// ExpandOutputDir renders an output directory template such as ./index/{{.Provider}}/{{.Version}} with the provider
+// name and version being indexed, so multi-version layouts don't need wrapper scripts. A directory without template
+// actions is returned as is.
+func (c OutputConfig) ExpandOutputDir(outputDir, version string) (string, error) {
+	if !strings.Contains(outputDir, "{{") {
+		return outputDir, nil
+	}
+	tmpl, err := template.New("output").Option("missingkey=error").Parse(outputDir)
+	if err != nil {
+		return "", fmt.Errorf("invalid output directory template %s: %w", outputDir, err)
+	}
+
+	providerName := c.ProviderName
+	if providerName == "" {
+		providerName = DefaultProviderName
+	}
+	var expanded strings.Builder
+	if err := tmpl.Execute(&expanded, OutputDirVariables{Provider: providerName, Version: version}); err != nil {
+		return "", fmt.Errorf("failed to expand output directory template %s: %w", outputDir, err)
+	}
+	return expanded.String(), nil
+}
@@ -0,0 +1,82 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputConfig_MainIndexFileName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   OutputConfig
+		expected string
+	}{
+		{
+			name:     "default",
+			config:   OutputConfig{},
+			expected: "terraform-provider-azurerm-index.json",
+		},
+		{
+			name:     "provider name",
+			config:   OutputConfig{ProviderName: "azapi"},
+			expected: "terraform-provider-azapi-index.json",
+		},
+		{
+			name:     "explicit index name",
+			config:   OutputConfig{ProviderName: "azapi", IndexFileName: "index.json"},
+			expected: "index.json",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.config.MainIndexFileName())
+		})
+	}
+}
+
+func TestOutputConfig_ExpandOutputDir(t *testing.T) {
+	testCases := []struct {
+		name      string
+		config    OutputConfig
+		outputDir string
+		expected  string
+	}{
+		{
+			name:      "no template",
+			config:    OutputConfig{},
+			outputDir: "./index",
+			expected:  "./index",
+		},
+		{
+			name:      "default provider",
+			config:    OutputConfig{},
+			outputDir: "./index/{{.Provider}}/{{.Version}}",
+			expected:  "./index/azurerm/v4.20.0",
+		},
+		{
+			name:      "provider name",
+			config:    OutputConfig{ProviderName: "azapi"},
+			outputDir: "/srv/{{.Provider}}-{{.Version}}",
+			expected:  "/srv/azapi-v4.20.0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expanded, err := tc.config.ExpandOutputDir(tc.outputDir, "v4.20.0")
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, expanded)
+		})
+	}
+}
+
+func TestOutputConfig_ExpandOutputDir_Errors(t *testing.T) {
+	_, err := OutputConfig{}.ExpandOutputDir("./index/{{.Provider", "v4.20.0")
+	assert.ErrorContains(t, err, "invalid output directory template")
+
+	_, err = OutputConfig{}.ExpandOutputDir("./index/{{.Commit}}", "v4.20.0")
+	assert.ErrorContains(t, err, "failed to expand output directory template")
+}
@@ -0,0 +1,96 @@
+package pkg
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PackagePathMapper maps between the directories of a scanned source tree and the Go import paths of their packages,
+// for trees whose layout differs from their module path, such as forks and vendored copies of a provider
+type PackagePathMapper interface {
+	// PackagePath returns the import path of the package in dir, a directory relative to the working directory
+	// the scan runs from, or "" when the mapper doesn't cover dir
+	PackagePath(dir string) string
+	// Dir returns the directory of the package with the given import path, or "" when the mapper doesn't cover it
+	Dir(importPath string) string
+}
+
+// PathPrefixMapper maps directory prefixes to import path prefixes, the longest matching prefix wins:
+//
+//	PathPrefixMapper{"vendor/github.com/hashicorp/terraform-provider-azurerm": "github.com/hashicorp/terraform-provider-azurerm"}
+//
+// maps vendor/github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault to
+// github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault and back. Unlike the default mapping,
+// which names the package after its package clause, import paths follow the directories like the go command.
+type PathPrefixMapper map[string]string
+
+// ParsePathPrefixMapper parses comma separated dir=importpath pairs, e.g.
+// "providers/azurerm=github.com/example/terraform-provider-azurerm"
+func ParsePathPrefixMapper(spec string) (PathPrefixMapper, error) {
+	mapper := make(PathPrefixMapper)
+	for _, pair := range strings.Split(spec, ",") {
+		dir, importPath, found := strings.Cut(strings.TrimSpace(pair), "=")
+		dir, importPath = strings.TrimSpace(dir), strings.TrimSpace(importPath)
+		if !found || dir == "" || importPath == "" {
+			return nil, fmt.Errorf("invalid package path mapping %q, expected dir=importpath", pair)
+		}
+		mapper[filepath.ToSlash(filepath.Clean(dir))] = strings.TrimSuffix(importPath, "/")
+	}
+	return mapper, nil
+}
+
+// PackagePath returns the import path of the package in dir, "" when no directory prefix matches
+func (m PathPrefixMapper) PackagePath(dir string) string {
+	prefix, rest, ok := longestPrefix(m.dirs(), filepath.ToSlash(filepath.Clean(dir)))
+	if !ok {
+		return ""
+	}
+	return path.Join(m[prefix], rest)
+}
+
+// Dir returns the directory of the package with the given import path, "" when no import path prefix matches
+func (m PathPrefixMapper) Dir(importPath string) string {
+	importPrefixes := make(map[string]string) // import path prefix -> directory prefix
+	for dir, prefix := range m {
+		importPrefixes[prefix] = dir
+	}
+	prefixes := make([]string, 0, len(importPrefixes))
+	for prefix := range importPrefixes {
+		prefixes = append(prefixes, prefix)
+	}
+	prefix, rest, ok := longestPrefix(prefixes, importPath)
+	if !ok {
+		return ""
+	}
+	return filepath.FromSlash(path.Join(importPrefixes[prefix], rest))
+}
+
+func (m PathPrefixMapper) dirs() []string {
+	dirs := make([]string, 0, len(m))
+	for dir := range m {
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// longestPrefix returns the longest of the slash separated prefixes of p and the rest of p after it
+func longestPrefix(prefixes []string, p string) (string, string, bool) {
+	sort.Slice(prefixes, func(i, j int) bool {
+		return len(prefixes[i]) > len(prefixes[j])
+	})
+	for _, prefix := range prefixes {
+		if p == prefix {
+			return prefix, "", true
+		}
+		if prefix == "." {
+			return prefix, p, true
+		}
+		if rest, ok := strings.CutPrefix(p, prefix+"/"); ok {
+			return prefix, rest, true
+		}
+	}
+	return "", "", false
+}
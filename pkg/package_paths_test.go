@@ -0,0 +1,61 @@
+package pkg
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePathPrefixMapper(t *testing.T) {
+	mapper, err := ParsePathPrefixMapper("vendor/github.com/hashicorp/terraform-provider-azurerm/=github.com/hashicorp/terraform-provider-azurerm, providers/azurerm/services=github.com/example/terraform-provider-azurerm/internal/services/")
+	require.NoError(t, err)
+
+	assert.Equal(t, PathPrefixMapper{
+		"vendor/github.com/hashicorp/terraform-provider-azurerm": "github.com/hashicorp/terraform-provider-azurerm",
+		"providers/azurerm/services":                             "github.com/example/terraform-provider-azurerm/internal/services",
+	}, mapper)
+
+	for _, spec := range []string{"internal/services", "=github.com/example", "internal/services="} {
+		_, err := ParsePathPrefixMapper(spec)
+		assert.ErrorContains(t, err, "expected dir=importpath", spec)
+	}
+}
+
+func TestPathPrefixMapper(t *testing.T) {
+	mapper := PathPrefixMapper{
+		"vendor/github.com/hashicorp/terraform-provider-azurerm":                          "github.com/hashicorp/terraform-provider-azurerm",
+		"vendor/github.com/hashicorp/terraform-provider-azurerm/internal/services/legacy": "github.com/hashicorp/terraform-provider-azurerm/internal/legacy",
+	}
+
+	assert.Equal(t, "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault", mapper.PackagePath(filepath.Join("vendor", "github.com", "hashicorp", "terraform-provider-azurerm", "internal", "services", "keyvault")))
+	assert.Equal(t, "github.com/hashicorp/terraform-provider-azurerm/internal/legacy/compute", mapper.PackagePath("vendor/github.com/hashicorp/terraform-provider-azurerm/internal/services/legacy/compute"))
+	assert.Equal(t, "github.com/hashicorp/terraform-provider-azurerm", mapper.PackagePath("./vendor/github.com/hashicorp/terraform-provider-azurerm"))
+	assert.Empty(t, mapper.PackagePath("internal/services/keyvault"))
+	assert.Empty(t, mapper.PackagePath("vendor/github.com/hashicorp/terraform-provider-azurerm-fork/internal"))
+
+	assert.Equal(t, filepath.Join("vendor", "github.com", "hashicorp", "terraform-provider-azurerm", "internal", "services", "keyvault", "parse"), mapper.Dir("github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault/parse"))
+	assert.Empty(t, mapper.Dir("github.com/hashicorp/go-azure-sdk/resource-manager/keyvault"))
+}
+
+func TestPathPrefixMapper_WorkingDirectory(t *testing.T) {
+	mapper := PathPrefixMapper{".": "github.com/example/terraform-provider-azurerm"}
+
+	assert.Equal(t, "github.com/example/terraform-provider-azurerm/internal/services/keyvault", mapper.PackagePath("internal/services/keyvault"))
+	assert.Equal(t, filepath.Join("internal", "services", "keyvault"), mapper.Dir("github.com/example/terraform-provider-azurerm/internal/services/keyvault"))
+}
+
+func TestScanner_PackagePaths(t *testing.T) {
+	scanner := Scanner{PackagePaths: PathPrefixMapper{filepath.Join("testharness", "internal", "services"): "github.com/example/terraform-provider-azurerm/internal/services"}}
+
+	index, err := scanner.Scan(filepath.Join("testharness", "internal", "services"), "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+	require.NoError(t, err)
+
+	packagePaths := make(map[string]string)
+	for _, service := range index.Services {
+		packagePaths[service.ServiceName] = service.PackagePath
+	}
+	assert.Equal(t, "github.com/example/terraform-provider-azurerm/internal/services/keyvault", packagePaths["keyvault"])
+	assert.Equal(t, "github.com/example/terraform-provider-azurerm/internal/services/storage", packagePaths["storage"])
+}
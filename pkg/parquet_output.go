@@ -0,0 +1,212 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"reflect"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/spf13/afero"
+)
+
+// Files the parquet output format writes, one table per document kind
+const (
+	ParquetResourcesFileName   = "resources.parquet"
+	ParquetDataSourcesFileName = "datasources.parquet"
+	ParquetEphemeralFileName   = "ephemeral.parquet"
+)
+
+// The tables are written with parquet-go, one row group per file. Strings are optional UTF8 columns with empty strings
+// written as null, booleans and integers are required BOOLEAN and INT64 columns.
+
+// ResourceRow is a row of resources.parquet, a resource flattened for analytics in DuckDB or Spark
+type ResourceRow struct {
+	ID                 string `parquet:"id,optional"`                  // "azurerm/resources/azurerm_key_vault/legacy_pluginsdk", see EntryID
+	TerraformType      string `parquet:"terraform_type,optional"`      // "azurerm_key_vault"
+	Service            string `parquet:"service,optional"`             // "keyvault"
+	Version            string `parquet:"version,optional"`             // "v4.20.0"
+	SDKType            string `parquet:"sdk_type,optional"`            // "legacy_pluginsdk" or "modern_sdk"
+	Namespace          string `parquet:"namespace,optional"`           // "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault"
+	StructType         string `parquet:"struct_type,optional"`         // "KeyVaultResource"
+	RegistrationMethod string `parquet:"registration_method,optional"` // "SupportedResources"
+	SchemaFunction     string `parquet:"schema_function,optional"`     // "resourceKeyVault" or "KeyVaultResource.Arguments"
+	CreateFunction     string `parquet:"create_function,optional"`     // "resourceKeyVaultCreate" or "KeyVaultResource.Create"
+	ReadFunction       string `parquet:"read_function,optional"`       // "resourceKeyVaultRead" or "KeyVaultResource.Read"
+	UpdateFunction     string `parquet:"update_function,optional"`     // "resourceKeyVaultUpdate" or "KeyVaultResource.Update"
+	DeleteFunction     string `parquet:"delete_function,optional"`     // "resourceKeyVaultDelete" or "KeyVaultResource.Delete"
+	AzureResourceType  string `parquet:"azure_resource_type,optional"` // "Microsoft.KeyVault/vaults"
+	IDParser           string `parquet:"id_parser,optional"`           // "commonids.ParseKeyVaultID"
+	SchemaVersion      int    `parquet:"schema_version"`               // 2
+	Deprecated         bool   `parquet:"deprecated"`                   // true
+	Conditional        bool   `parquet:"conditional"`                  // true
+	DisplayName        string `parquet:"display_name,optional"`        // "Key Vault"
+	Immutable          bool   `parquet:"immutable"`                    // true
+	FeatureFlag        string `parquet:"feature_flag,optional"`        // "!features.FivePointOh"
+}
+
+// DataSourceRow is a row of datasources.parquet
+type DataSourceRow struct {
+	ID                 string `parquet:"id,optional"`                  // "azurerm/datasources/azurerm_key_vault/legacy_pluginsdk", see EntryID
+	TerraformType      string `parquet:"terraform_type,optional"`      // "azurerm_key_vault"
+	Service            string `parquet:"service,optional"`             // "keyvault"
+	Version            string `parquet:"version,optional"`             // "v4.20.0"
+	SDKType            string `parquet:"sdk_type,optional"`            // "legacy_pluginsdk" or "modern_sdk"
+	Namespace          string `parquet:"namespace,optional"`           // "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault"
+	StructType         string `parquet:"struct_type,optional"`         // "KeyVaultDataSource"
+	RegistrationMethod string `parquet:"registration_method,optional"` // "SupportedDataSources"
+	SchemaFunction     string `parquet:"schema_function,optional"`     // "dataSourceKeyVault" or "KeyVaultDataSource.Arguments"
+	ReadFunction       string `parquet:"read_function,optional"`       // "dataSourceKeyVaultRead" or "KeyVaultDataSource.Read"
+	Deprecated         bool   `parquet:"deprecated"`                   // true
+	Conditional        bool   `parquet:"conditional"`                  // true
+	DisplayName        string `parquet:"display_name,optional"`        // "Key Vault"
+	FeatureFlag        string `parquet:"feature_flag,optional"`        // "features.FivePointOh"
+}
+
+// EphemeralRow is a row of ephemeral.parquet
+type EphemeralRow struct {
+	ID             string `parquet:"id,optional"`              // "azurerm/ephemeral/azurerm_key_vault_secret/ephemeral", see EntryID
+	TerraformType  string `parquet:"terraform_type,optional"`  // "azurerm_key_vault_secret"
+	Service        string `parquet:"service,optional"`         // "keyvault"
+	Version        string `parquet:"version,optional"`         // "v4.20.0"
+	SDKType        string `parquet:"sdk_type,optional"`        // "ephemeral"
+	Namespace      string `parquet:"namespace,optional"`       // "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault"
+	StructType     string `parquet:"struct_type,optional"`     // "KeyVaultSecretEphemeralResource"
+	SchemaFunction string `parquet:"schema_function,optional"` // "KeyVaultSecretEphemeralResource.Schema"
+	OpenFunction   string `parquet:"open_function,optional"`   // "KeyVaultSecretEphemeralResource.Open"
+	RenewFunction  string `parquet:"renew_function,optional"`  // "KeyVaultSecretEphemeralResource.Renew"
+	CloseFunction  string `parquet:"close_function,optional"`  // "KeyVaultSecretEphemeralResource.Close"
+	DisplayName    string `parquet:"display_name,optional"`    // "Key Vault"
+	FeatureFlag    string `parquet:"feature_flag,optional"`    // "features.FivePointOh"
+}
+
+// ParquetTables holds the rows of the tables of the parquet output format
+type ParquetTables struct {
+	Resources   []ResourceRow
+	DataSources []DataSourceRow
+	Ephemeral   []EphemeralRow
+}
+
+// BuildParquetTables flattens the documents of the index into one row per resource, data source and ephemeral
+// resource
+func (index *TerraformProviderIndex) BuildParquetTables() ParquetTables {
+	tables := ParquetTables{Resources: []ResourceRow{}, DataSources: []DataSourceRow{}, Ephemeral: []EphemeralRow{}}
+	for _, document := range index.Documents() {
+		switch content := document.Content.(type) {
+		case TerraformResource:
+			tables.Resources = append(tables.Resources, ResourceRow{
+				ID:                 document.ID,
+				TerraformType:      document.TerraformType,
+				Service:            document.Service,
+				Version:            index.Version,
+				SDKType:            content.SDKType,
+				Namespace:          content.Namespace,
+				StructType:         content.StructType,
+				RegistrationMethod: content.RegistrationMethod,
+				SchemaFunction:     indexSymbol(content.SchemaIndex),
+				CreateFunction:     indexSymbol(content.CreateIndex),
+				ReadFunction:       indexSymbol(content.ReadIndex),
+				UpdateFunction:     indexSymbol(content.UpdateIndex),
+				DeleteFunction:     indexSymbol(content.DeleteIndex),
+				AzureResourceType:  content.AzureResourceType,
+				IDParser:           content.IDParser,
+				SchemaVersion:      content.SchemaVersion,
+				Deprecated:         content.Deprecated,
+				Conditional:        content.Conditional,
+				DisplayName:        content.DisplayName,
+				Immutable:          content.Immutable,
+				FeatureFlag:        content.FeatureFlag,
+			})
+		case TerraformDataSource:
+			tables.DataSources = append(tables.DataSources, DataSourceRow{
+				ID:                 document.ID,
+				TerraformType:      document.TerraformType,
+				Service:            document.Service,
+				Version:            index.Version,
+				SDKType:            content.SDKType,
+				Namespace:          content.Namespace,
+				StructType:         content.StructType,
+				RegistrationMethod: content.RegistrationMethod,
+				SchemaFunction:     indexSymbol(content.SchemaIndex),
+				ReadFunction:       indexSymbol(content.ReadIndex),
+				Deprecated:         content.Deprecated,
+				Conditional:        content.Conditional,
+				DisplayName:        content.DisplayName,
+				FeatureFlag:        content.FeatureFlag,
+			})
+		case TerraformEphemeral:
+			tables.Ephemeral = append(tables.Ephemeral, EphemeralRow{
+				ID:             document.ID,
+				TerraformType:  document.TerraformType,
+				Service:        document.Service,
+				Version:        index.Version,
+				SDKType:        content.SDKType,
+				Namespace:      content.Namespace,
+				StructType:     content.StructType,
+				SchemaFunction: indexSymbol(content.SchemaIndex),
+				OpenFunction:   indexSymbol(content.OpenIndex),
+				RenewFunction:  indexSymbol(content.RenewIndex),
+				CloseFunction:  indexSymbol(content.CloseIndex),
+				DisplayName:    content.DisplayName,
+				FeatureFlag:    content.FeatureFlag,
+			})
+		}
+	}
+	return tables
+}
+
+// indexSymbol returns the function or method of a gophon index file name, "" when there is none
+func indexSymbol(indexFile string) string {
+	if symbols := indexSymbols(indexFile); len(symbols) > 0 {
+		return symbols[0]
+	}
+	return ""
+}
+
+// marshalParquet encodes a slice of row structs as a Parquet file, the fields become the columns named by their
+// parquet tags
+func marshalParquet(rows interface{}) ([]byte, error) {
+	rowsValue := reflect.ValueOf(rows)
+	var file bytes.Buffer
+	writer := parquet.NewWriter(&file, parquet.SchemaOf(reflect.New(rowsValue.Type().Elem()).Interface()))
+	for i := 0; i < rowsValue.Len(); i++ {
+		if err := writer.Write(rowsValue.Index(i).Interface()); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return file.Bytes(), nil
+}
+
+// writeParquetFiles writes resources.parquet, datasources.parquet and ephemeral.parquet
+func (index *TerraformProviderIndex) writeParquetFiles(outputDir string, progressCallback ProgressCallback) error {
+	tables := index.BuildParquetTables()
+	files := []struct {
+		name string
+		rows interface{}
+	}{
+		{ParquetResourcesFileName, tables.Resources},
+		{ParquetDataSourcesFileName, tables.DataSources},
+		{ParquetEphemeralFileName, tables.Ephemeral},
+	}
+
+	progressTracker := NewProgressTracker("encoding", len(files), progressCallback)
+	if err := index.Output.fs().MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", outputDir, err)
+	}
+	for _, file := range files {
+		content, err := marshalParquet(file.rows)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", file.name, err)
+		}
+		filePath := filepath.Join(outputDir, file.name)
+		if err := afero.WriteFile(index.Output.fs(), filePath, content, 0644); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", filePath, err)
+		}
+		progressTracker.UpdateProgress(file.name)
+	}
+	progressTracker.Complete()
+	return nil
+}
@@ -0,0 +1,110 @@
+package pkg
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformProviderIndex_WriteIndexFiles_ParquetFormat(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	index, err := ScanTerraformProviderServices(filepath.Join("testharness", "internal", "services"), "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+	require.NoError(t, err)
+	index.Output.Format = OutputFormatParquet
+	index.Output.Fs = fs
+
+	require.NoError(t, index.WriteIndexFiles("/index", nil))
+
+	tables := index.BuildParquetTables()
+	require.NotEmpty(t, tables.Resources)
+	require.NotEmpty(t, tables.DataSources)
+	require.NotEmpty(t, tables.Ephemeral)
+
+	assert.Equal(t, tables.Resources, readTestParquetFile[ResourceRow](t, fs, "/index/"+ParquetResourcesFileName))
+	assert.Equal(t, tables.DataSources, readTestParquetFile[DataSourceRow](t, fs, "/index/"+ParquetDataSourcesFileName))
+	assert.Equal(t, tables.Ephemeral, readTestParquetFile[EphemeralRow](t, fs, "/index/"+ParquetEphemeralFileName))
+
+	exists, err := afero.Exists(fs, "/index/"+index.Output.MainIndexFileName())
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestBuildParquetTables_FunctionNames(t *testing.T) {
+	index := createTestTerraformProviderIndex()
+	tables := index.BuildParquetTables()
+
+	rows := make(map[string]ResourceRow)
+	for _, row := range tables.Resources {
+		rows[row.TerraformType] = row
+	}
+	keyVault := rows["azurerm_key_vault"]
+	assert.Equal(t, "keyvault", keyVault.Service)
+	assert.Equal(t, "v3.0.0", keyVault.Version)
+	assert.Equal(t, "legacy_pluginsdk", keyVault.SDKType)
+	assert.NotEmpty(t, keyVault.CreateFunction)
+	assert.NotContains(t, keyVault.CreateFunction, ".goindex")
+}
+
+func TestMarshalParquet_EncodesNullsAndBooleans(t *testing.T) {
+	type row struct {
+		Name    string `parquet:"name,optional"`
+		Count   int    `parquet:"count"`
+		Enabled bool   `parquet:"enabled"`
+		Ignored string `parquet:"-"`
+	}
+	var rows []row
+	nulls := 0
+	for i := 0; i < 20; i++ {
+		r := row{Count: i * 1000, Enabled: i%3 == 0, Ignored: "not a column"}
+		if i%4 != 1 {
+			r.Name = string(rune('a' + i))
+		} else {
+			nulls++
+		}
+		rows = append(rows, r)
+	}
+
+	content, err := marshalParquet(rows)
+	require.NoError(t, err)
+	decoded, err := parquet.Read[row](bytes.NewReader(content), int64(len(content)))
+	require.NoError(t, err)
+	for i := range rows {
+		rows[i].Ignored = ""
+	}
+	assert.Equal(t, rows, decoded)
+
+	file, err := parquet.OpenFile(bytes.NewReader(content), int64(len(content)))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name", "count", "enabled"}, parquetColumnNames(file))
+	name, _ := file.Schema().Lookup("name")
+	assert.True(t, name.Node.Optional())
+	assert.Equal(t, parquet.ByteArray, name.Node.Type().Kind())
+	assert.NotNil(t, name.Node.Type().LogicalType().UTF8, "strings are UTF8 columns")
+	assert.Equal(t, int64(nulls), file.Metadata().RowGroups[0].Columns[0].MetaData.Statistics.NullCount, "empty strings are written as null")
+	count, _ := file.Schema().Lookup("count")
+	assert.Equal(t, parquet.Int64, count.Node.Type().Kind())
+	assert.True(t, count.Node.Required())
+}
+
+// readTestParquetFile reads the rows of a Parquet file with parquet-go's reader
+func readTestParquetFile[T any](t *testing.T, fs afero.Fs, filePath string) []T {
+	content, err := afero.ReadFile(fs, filePath)
+	require.NoError(t, err)
+	rows, err := parquet.Read[T](bytes.NewReader(content), int64(len(content)))
+	require.NoError(t, err)
+	return rows
+}
+
+// parquetColumnNames returns the names of the columns of a Parquet file
+func parquetColumnNames(file *parquet.File) []string {
+	var names []string
+	for _, column := range file.Schema().Columns() {
+		names = append(names, column[0])
+	}
+	return names
+}
@@ -27,142 +27,516 @@ func extractResourcesStructTypes(node *ast.File) []string {
 	return extractStructTypesFromMethod(node, "Resources")
 }
 
+// extractConditionalResourcesStructTypes extracts struct type names the Resources method only registers under a
+// condition, such as a feature flag
+func extractConditionalResourcesStructTypes(node *ast.File) []string {
+	_, conditional := extractRegisteredStructTypes(node, "Resources")
+	return conditional
+}
+
+// extractConditionalDataSourcesStructTypes extracts struct type names the DataSources method only registers under a
+// condition, such as a feature flag
+func extractConditionalDataSourcesStructTypes(node *ast.File) []string {
+	_, conditional := extractRegisteredStructTypes(node, "DataSources")
+	return conditional
+}
+
 // extractEphemeralResourcesFunctions extracts function names from EphemeralResources method in the AST
 func extractEphemeralResourcesFunctions(node *ast.File) []string {
 	return extractFunctionNamesFromMethod(node, "EphemeralResources")
 }
 
-// extractMappingsFromMethod extracts mappings from any method that returns map[string]*pluginsdk.Resource
+// extractListResourcesRegistrations extracts the struct types and constructor functions registered by the
+// ListResources method in the AST, list resources enumerating existing infrastructure for terraform query
+func extractListResourcesRegistrations(node *ast.File) ([]string, []string) {
+	return extractStructTypesFromMethod(node, "ListResources"), extractFunctionNamesFromMethod(node, "ListResources")
+}
+
+// extractActionsRegistrations extracts the struct types and constructor functions registered by the Actions method
+// in the AST, actions invoked by Terraform outside of the resource lifecycle
+func extractActionsRegistrations(node *ast.File) ([]string, []string) {
+	return extractStructTypesFromMethod(node, "Actions"), extractFunctionNamesFromMethod(node, "Actions")
+}
+
+// registrationMethodNames are the methods a service registration type implements to register its resources
+var registrationMethodNames = map[string]bool{
+	"SupportedResources":   true,
+	"SupportedDataSources": true,
+	"Resources":            true,
+	"DataSources":          true,
+	"EphemeralResources":   true,
+	"ListResources":        true,
+	"Actions":              true,
+}
+
+// extractServiceDisplayName extracts the literal returned by the Name method of the service registration type,
+// "Key Vault" for func (r Registration) Name() string { return "Key Vault" }
+func extractServiceDisplayName(node *ast.File) string {
+	return extractRegistrationStringMethod(node, "Name")
+}
+
+// extractServiceGitHubLabel extracts the literal returned by the AssociatedGitHubLabel method of the service
+// registration type, the label issues about the service's resources are triaged under such as "service/key-vault"
+func extractServiceGitHubLabel(node *ast.File) string {
+	return extractRegistrationStringMethod(node, "AssociatedGitHubLabel")
+}
+
+// extractRegistrationStringMethod extracts the string literal returned by a method of the service registration type
+func extractRegistrationStringMethod(node *ast.File, methodName string) string {
+	fn := findRegistrationTypeMethod(node, methodName)
+	if fn == nil {
+		return ""
+	}
+	for _, stmt := range fn.Body.List {
+		if returnStmt, ok := stmt.(*ast.ReturnStmt); ok && len(returnStmt.Results) == 1 {
+			if basicLit, ok := returnStmt.Results[0].(*ast.BasicLit); ok && basicLit.Kind == token.STRING {
+				return stringExprValue(basicLit)
+			}
+		}
+	}
+	return ""
+}
+
+// extractServiceWebsiteCategories extracts the literals returned by the WebsiteCategories method of the service
+// registration type, the website documentation categories of its resources such as ["Key Vault"]
+func extractServiceWebsiteCategories(node *ast.File) []string {
+	fn := findRegistrationTypeMethod(node, "WebsiteCategories")
+	if fn == nil {
+		return nil
+	}
+	var categories []string
+	for _, stmt := range fn.Body.List {
+		returnStmt, ok := stmt.(*ast.ReturnStmt)
+		if !ok || len(returnStmt.Results) != 1 {
+			continue
+		}
+		sliceLit, ok := returnStmt.Results[0].(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		for _, elt := range sliceLit.Elts {
+			if basicLit, ok := elt.(*ast.BasicLit); ok && basicLit.Kind == token.STRING {
+				categories = append(categories, stringExprValue(basicLit))
+			}
+		}
+	}
+	return categories
+}
+
+// registrationInterfaceMethodNames are the methods describing a service the service registration types implement,
+// Name isn't one of them as typed resources implement it too
+var registrationInterfaceMethodNames = map[string]bool{
+	"WebsiteCategories":     true,
+	"AssociatedGitHubLabel": true,
+}
+
+// registrationMethodDecls returns the declarations of a registration method in a file, the methods named methodName
+// of its service registration types, with value or pointer receivers. A file may declare several registration types,
+// such as a hand-written Registration and an autogenerated autoRegistration, their registrations are combined.
+func registrationMethodDecls(node *ast.File, methodName string) []*ast.FuncDecl {
+	registrationTypes := registrationTypeNames(node)
+	var decls []*ast.FuncDecl
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if ok && fn.Name.Name == methodName && fn.Body != nil && registrationTypes[receiverTypeName(fn)] {
+			decls = append(decls, fn)
+		}
+	}
+	return decls
+}
+
+// registrationTypeNames returns the service registration types of a file: the receiver types named like a
+// registration, such as Registration or autoRegistration, and the types implementing the WebsiteCategories or
+// AssociatedGitHubLabel methods of the service registration interfaces
+func registrationTypeNames(node *ast.File) map[string]bool {
+	registrationTypes := make(map[string]bool)
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		receiver := receiverTypeName(fn)
+		if receiver == "" {
+			continue
+		}
+		if strings.Contains(strings.ToLower(receiver), "registration") || registrationInterfaceMethodNames[fn.Name.Name] {
+			registrationTypes[receiver] = true
+		}
+	}
+	return registrationTypes
+}
+
+// findRegistrationTypeMethod finds a method of the service registration types declared in a file
+func findRegistrationTypeMethod(node *ast.File, methodName string) *ast.FuncDecl {
+	if decls := registrationMethodDecls(node, methodName); len(decls) > 0 {
+		return decls[0]
+	}
+	return nil
+}
+
+// extractMappingsFromMethod extracts mappings from any method that returns map[string]*pluginsdk.Resource. Besides a
+// returned map literal, it follows the ways services build the map across statements: a variable assigned a literal
+// or a helper call, entries added with resources["azurerm_x"] = resourceX(), maps.Copy or a range loop copying another
+// map, and helper functions or methods declared in the same file, either returning a map or filling one passed in.
 func extractMappingsFromMethod(node *ast.File, methodName string) map[string]string {
 	mappings := make(map[string]string)
+	helpers := fileFuncDecls(node)
 
-	ast.Inspect(node, func(n ast.Node) bool {
-		// Look for function declarations
-		fn, ok := n.(*ast.FuncDecl)
-		if !ok || fn.Name.Name != methodName {
+	for _, fn := range registrationMethodDecls(node, methodName) {
+		mappings = mergeMap(mappings, extractMappingsFromFunc(fn, helpers, 0))
+	}
+
+	return mappings
+}
+
+// fileFuncDecls indexes the function declarations of a file by name, methods by "." and the method name
+func fileFuncDecls(node *ast.File) map[string]*ast.FuncDecl {
+	funcs := make(map[string]*ast.FuncDecl)
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		if fn.Recv != nil {
+			funcs["."+fn.Name.Name] = fn
+		} else {
+			funcs[fn.Name.Name] = fn
+		}
+	}
+	return funcs
+}
+
+// extractMappingsFromFunc extracts the mappings of the maps a function returns
+func extractMappingsFromFunc(fn *ast.FuncDecl, helpers map[string]*ast.FuncDecl, depth int) map[string]string {
+	mappings := make(map[string]string)
+	if fn.Body == nil || depth > maxSchemaResolveDepth {
+		return mappings
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		// Return statements of closures don't return the map
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		returnStmt, ok := n.(*ast.ReturnStmt)
+		if !ok {
 			return true
 		}
+		for _, result := range returnStmt.Results {
+			mappings = mergeMap(mappings, extractMappingsFromExpr(result, fn.Body, helpers, depth))
+		}
+		return true
+	})
 
-		// Look for return statements in the function body
-		ast.Inspect(fn.Body, func(inner ast.Node) bool {
-			returnStmt, ok := inner.(*ast.ReturnStmt)
-			if !ok {
-				return true
-			}
+	return mappings
+}
 
-			// Process each return expression
-			for _, result := range returnStmt.Results {
-				// Handle direct map literal return
-				if mapLit, ok := result.(*ast.CompositeLit); ok {
-					mappings = mergeMap(mappings, extractFromMapLiteral(mapLit))
-				}
+// extractMappingsFromExpr extracts the mappings of a map expression: a literal, a variable of the function body or a
+// call to a helper returning a map
+func extractMappingsFromExpr(expr ast.Expr, body *ast.BlockStmt, helpers map[string]*ast.FuncDecl, depth int) map[string]string {
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		return extractFromMapLiteral(e)
+	case *ast.Ident:
+		// Variables assigned from each other count as a level, so resources = resources can't loop
+		return extractMappingsFromVariable(e.Name, body, helpers, depth+1)
+	case *ast.CallExpr:
+		if helper := calledHelper(e, helpers); helper != nil {
+			return extractMappingsFromFunc(helper, helpers, depth+1)
+		}
+	}
+	return nil
+}
 
-				// Handle variable reference (like "resources" or "dataSources" variable)
-				ident, ok := result.(*ast.Ident)
-				if !ok {
-					continue
+// extractMappingsFromVariable extracts the mappings of a map variable built across the statements of a function body
+func extractMappingsFromVariable(name string, body *ast.BlockStmt, helpers map[string]*ast.FuncDecl, depth int) map[string]string {
+	mappings := make(map[string]string)
+	if body == nil || depth > maxSchemaResolveDepth {
+		return mappings
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.ValueSpec:
+			// var resources = map[string]*pluginsdk.Resource{...}
+			for i, ident := range stmt.Names {
+				if ident.Name == name && i < len(stmt.Values) {
+					mappings = mergeMap(mappings, extractMappingsFromExpr(stmt.Values[i], body, helpers, depth))
 				}
-				// Find the variable definition in the function
-				ast.Inspect(fn.Body, func(varNode ast.Node) bool {
-					assignStmt, ok := varNode.(*ast.AssignStmt)
-					if !ok {
-						return true
+			}
+		case *ast.AssignStmt:
+			for i, lhs := range stmt.Lhs {
+				if i >= len(stmt.Rhs) {
+					break
+				}
+				switch l := lhs.(type) {
+				case *ast.Ident:
+					// resources := map[string]*pluginsdk.Resource{...} or resources := commonResources()
+					if l.Name == name {
+						mappings = mergeMap(mappings, extractMappingsFromExpr(stmt.Rhs[i], body, helpers, depth))
 					}
-					for i, lhs := range assignStmt.Lhs {
-						lhsIdent, ok := lhs.(*ast.Ident)
-						if !ok || lhsIdent.Name != ident.Name {
-							return true
-						}
-						if i >= len(assignStmt.Rhs) {
-							return true
-						}
-						if mapLit, ok := assignStmt.Rhs[i].(*ast.CompositeLit); ok {
-							mappings = mergeMap(mappings, extractFromMapLiteral(mapLit))
+				case *ast.IndexExpr:
+					// resources["azurerm_key_vault"] = resourceKeyVault()
+					if ident, ok := l.X.(*ast.Ident); ok && ident.Name == name {
+						if key, value := extractMappingEntry(l.Index, stmt.Rhs[i]); key != "" && value != "" {
+							mappings[key] = value
 						}
 					}
-					return true
-				})
+				}
 			}
-			return true
-		})
-
+		case *ast.RangeStmt:
+			// for k, v := range commonResources() { resources[k] = v }
+			if rangeAssignsTo(stmt, name) {
+				mappings = mergeMap(mappings, extractMappingsFromExpr(stmt.X, body, helpers, depth))
+			}
+		case *ast.CallExpr:
+			mappings = mergeMap(mappings, extractMappingsFromCallArgument(stmt, name, body, helpers, depth))
+		}
 		return true
 	})
 
 	return mappings
 }
 
-// extractStructTypesFromMethod extracts struct type names from any method that returns []sdk.DataSource or []sdk.Resource
-func extractStructTypesFromMethod(node *ast.File, methodName string) []string {
-	var types []string
+// extractMappingsFromCallArgument extracts the mappings a call adds to a map variable passed as argument, either
+// maps.Copy(resources, other) or a helper filling the map
+func extractMappingsFromCallArgument(call *ast.CallExpr, name string, body *ast.BlockStmt, helpers map[string]*ast.FuncDecl, depth int) map[string]string {
+	if selector, ok := call.Fun.(*ast.SelectorExpr); ok {
+		if pkg, ok := selector.X.(*ast.Ident); ok && pkg.Name == "maps" && selector.Sel.Name == "Copy" && len(call.Args) == 2 {
+			if dst, ok := call.Args[0].(*ast.Ident); ok && dst.Name == name {
+				return extractMappingsFromExpr(call.Args[1], body, helpers, depth)
+			}
+			return nil
+		}
+	}
 
-	ast.Inspect(node, func(n ast.Node) bool {
-		// Look for function declarations
-		fn, ok := n.(*ast.FuncDecl)
-		if !ok || fn.Name.Name != methodName {
-			return true
+	helper := calledHelper(call, helpers)
+	if helper == nil || helper.Type.Params == nil {
+		return nil
+	}
+	var params []string
+	for _, field := range helper.Type.Params.List {
+		for _, paramName := range field.Names {
+			params = append(params, paramName.Name)
+		}
+	}
+	mappings := make(map[string]string)
+	for i, arg := range call.Args {
+		if ident, ok := arg.(*ast.Ident); ok && ident.Name == name && i < len(params) {
+			mappings = mergeMap(mappings, extractMappingsFromVariable(params[i], helper.Body, helpers, depth+1))
 		}
+	}
+	return mappings
+}
 
-		// Look for return statements in the function body
-		ast.Inspect(fn.Body, func(inner ast.Node) bool {
-			returnStmt, ok := inner.(*ast.ReturnStmt)
-			if !ok {
-				return true
-			}
+// calledHelper returns the declaration of the function or method of the file a call invokes, nil for other calls
+func calledHelper(call *ast.CallExpr, helpers map[string]*ast.FuncDecl) *ast.FuncDecl {
+	switch fun := unwrapTypeArguments(call.Fun).(type) {
+	case *ast.Ident:
+		return helpers[fun.Name]
+	case *ast.SelectorExpr:
+		return helpers["."+fun.Sel.Name]
+	}
+	return nil
+}
 
-			// Process each return expression
-			for _, result := range returnStmt.Results {
-				// Handle direct slice literal return
-				if sliceLit, ok := result.(*ast.CompositeLit); ok {
-					types = append(types, extractFromSliceLiteral(sliceLit)...)
+// rangeAssignsTo reports whether the body of a range loop copies entries into a map variable
+func rangeAssignsTo(rangeStmt *ast.RangeStmt, name string) bool {
+	assigns := false
+	ast.Inspect(rangeStmt.Body, func(n ast.Node) bool {
+		assignStmt, ok := n.(*ast.AssignStmt)
+		if !ok {
+			return !assigns
+		}
+		for _, lhs := range assignStmt.Lhs {
+			if indexExpr, ok := lhs.(*ast.IndexExpr); ok {
+				if ident, ok := indexExpr.X.(*ast.Ident); ok && ident.Name == name {
+					assigns = true
 				}
+			}
+		}
+		return !assigns
+	})
+	return assigns
+}
 
-				// Handle variable reference (like "dataSources" variable)
-				ident, ok := result.(*ast.Ident)
-				if !ok {
-					continue
-				}
-				// Find the variable definition in the function
-				ast.Inspect(fn.Body, func(varNode ast.Node) bool {
-					assignStmt, ok := varNode.(*ast.AssignStmt)
-					if !ok {
-						return true
-					}
-					for i, lhs := range assignStmt.Lhs {
-						lhsIdent, ok := lhs.(*ast.Ident)
-						if !ok || lhsIdent.Name != ident.Name {
-							return true
-						}
-						if i >= len(assignStmt.Rhs) {
-							return true
-						}
-						if sliceLit, ok := assignStmt.Rhs[i].(*ast.CompositeLit); ok {
-							types = append(types, extractFromSliceLiteral(sliceLit)...)
-						}
+// extractStructTypesFromMethod extracts struct type names from any method that returns []sdk.DataSource or []sdk.Resource
+func extractStructTypesFromMethod(node *ast.File, methodName string) []string {
+	types, _ := extractRegisteredStructTypes(node, methodName)
+	return types
+}
+
+// extractRegisteredStructTypes extracts the struct type names registered by any method that returns []sdk.DataSource
+// or []sdk.Resource, and the ones only registered under a condition. Besides a returned slice literal, it follows a
+// variable assigned a literal, grown with append, filled in a range loop over a slice literal, or inside if, switch
+// and loop bodies such as if !features.FivePointOh() { ... }, which make the registration conditional.
+func extractRegisteredStructTypes(node *ast.File, methodName string) ([]string, []string) {
+	collector := &structTypeCollector{
+		variables:   make(map[string]bool),
+		seen:        make(map[string]bool),
+		conditional: make(map[string]bool),
+		rangeValues: make(map[string][]string),
+	}
+
+	for _, fn := range registrationMethodDecls(node, methodName) {
+		// Variables holding the returned slice, like "dataSources"
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if _, ok := n.(*ast.FuncLit); ok {
+				return false
+			}
+			if returnStmt, ok := n.(*ast.ReturnStmt); ok {
+				for _, result := range returnStmt.Results {
+					if ident, ok := result.(*ast.Ident); ok {
+						collector.variables[ident.Name] = true
 					}
-					return true
-				})
+				}
 			}
 			return true
 		})
+		collector.walk(fn.Body.List, false)
+	}
 
-		return true
-	})
+	var conditional []string
+	for _, structType := range collector.types {
+		if collector.conditional[structType] {
+			conditional = append(conditional, structType)
+		}
+	}
+	return collector.types, conditional
+}
 
-	return types
+// structTypeCollector collects the struct types added to the slice a registration method returns
+type structTypeCollector struct {
+	variables   map[string]bool     // Variables holding the returned slice
+	types       []string            // Struct types in registration order
+	seen        map[string]bool     // Struct types collected so far
+	conditional map[string]bool     // Struct type -> only registered under a condition
+	rangeValues map[string][]string // Range value variable -> struct types of the ranged slice literal
+}
+
+// add collects a struct type, a registration outside of any condition makes it unconditional
+func (c *structTypeCollector) add(structType string, conditional bool) {
+	if structType == "" {
+		return
+	}
+	if !c.seen[structType] {
+		c.seen[structType] = true
+		c.types = append(c.types, structType)
+		c.conditional[structType] = conditional
+		return
+	}
+	if !conditional {
+		c.conditional[structType] = false
+	}
+}
+
+// walk collects the struct types registered by a list of statements
+func (c *structTypeCollector) walk(stmts []ast.Stmt, conditional bool) {
+	for _, stmt := range stmts {
+		c.walkStmt(stmt, conditional)
+	}
+}
+
+func (c *structTypeCollector) walkStmt(stmt ast.Stmt, conditional bool) {
+	switch s := stmt.(type) {
+	case *ast.ReturnStmt:
+		for _, result := range s.Results {
+			c.addExpr(result, conditional)
+		}
+	case *ast.AssignStmt:
+		for i, lhs := range s.Lhs {
+			if ident, ok := lhs.(*ast.Ident); ok && c.variables[ident.Name] && i < len(s.Rhs) {
+				c.addExpr(s.Rhs[i], conditional)
+			}
+		}
+	case *ast.DeclStmt:
+		genDecl, ok := s.Decl.(*ast.GenDecl)
+		if !ok {
+			return
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for i, name := range valueSpec.Names {
+				if c.variables[name.Name] && i < len(valueSpec.Values) {
+					c.addExpr(valueSpec.Values[i], conditional)
+				}
+			}
+		}
+	case *ast.BlockStmt:
+		c.walk(s.List, conditional)
+	case *ast.IfStmt:
+		c.walk(s.Body.List, true)
+		if s.Else != nil {
+			c.walkStmt(s.Else, true)
+		}
+	case *ast.SwitchStmt:
+		c.walkCaseClauses(s.Body)
+	case *ast.TypeSwitchStmt:
+		c.walkCaseClauses(s.Body)
+	case *ast.ForStmt:
+		// The number of iterations depends on the loop condition, like a loop over feature flags
+		c.walk(s.Body.List, true)
+	case *ast.RangeStmt:
+		// Ranging over a slice literal registers each element, ranging over anything else depends on its content
+		sliceLit, ok := s.X.(*ast.CompositeLit)
+		if !ok {
+			c.walk(s.Body.List, true)
+			return
+		}
+		if value, ok := s.Value.(*ast.Ident); ok {
+			c.rangeValues[value.Name] = extractFromSliceLiteral(sliceLit)
+		}
+		c.walk(s.Body.List, conditional)
+	}
+}
+
+// walkCaseClauses collects the struct types registered by the clauses of a switch, which are all conditional
+func (c *structTypeCollector) walkCaseClauses(body *ast.BlockStmt) {
+	for _, stmt := range body.List {
+		if clause, ok := stmt.(*ast.CaseClause); ok {
+			c.walk(clause.Body, true)
+		}
+	}
+}
+
+// addExpr collects the struct types of a slice literal or of an append to the returned slice
+func (c *structTypeCollector) addExpr(expr ast.Expr, conditional bool) {
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		for _, structType := range extractFromSliceLiteral(e) {
+			c.add(structType, conditional)
+		}
+	case *ast.CallExpr:
+		// resources = append(resources, KeyVaultResource{}) or append(resources, []sdk.Resource{...}...)
+		fun, ok := e.Fun.(*ast.Ident)
+		if !ok || fun.Name != "append" || len(e.Args) < 2 {
+			return
+		}
+		for i, arg := range e.Args[1:] {
+			if e.Ellipsis.IsValid() && i == len(e.Args)-2 {
+				c.addExpr(arg, conditional)
+				continue
+			}
+			switch a := arg.(type) {
+			case *ast.CompositeLit:
+				c.add(typeName(a.Type), conditional)
+			case *ast.Ident:
+				for _, structType := range c.rangeValues[a.Name] {
+					c.add(structType, conditional)
+				}
+			}
+		}
+	}
 }
 
 // extractFunctionNamesFromMethod extracts function names from any method that returns []func() ephemeral.EphemeralResource
 func extractFunctionNamesFromMethod(node *ast.File, methodName string) []string {
 	var functions []string
 
-	ast.Inspect(node, func(n ast.Node) bool {
-		// Look for function declarations
-		fn, ok := n.(*ast.FuncDecl)
-		if !ok || fn.Name.Name != methodName {
-			return true
-		}
-
+	for _, fn := range registrationMethodDecls(node, methodName) {
 		// Look for return statements in the function body
 		ast.Inspect(fn.Body, func(inner ast.Node) bool {
 			returnStmt, ok := inner.(*ast.ReturnStmt)
@@ -205,9 +579,7 @@ func extractFunctionNamesFromMethod(node *ast.File, methodName string) []string
 			}
 			return true
 		})
-
-		return true
-	})
+	}
 
 	return functions
 }
@@ -220,29 +592,34 @@ func extractFromMapLiteral(mapLit *ast.CompositeLit) map[string]string {
 		if !ok {
 			continue
 		}
-		// Extract the key (terraform resource type)
-		var key string
-		if keyLit, ok := kv.Key.(*ast.BasicLit); ok && keyLit.Kind == token.STRING {
-			key = strings.Trim(keyLit.Value, `"`)
-		}
-
-		// Extract the value (function call name)
-		var value string
-		callExpr, ok := kv.Value.(*ast.CallExpr)
-		if !ok {
-			continue
-		}
-		if fnIdent, ok := callExpr.Fun.(*ast.Ident); ok {
-			value = fnIdent.Name
-		}
-
-		if key != "" && value != "" {
+		if key, value := extractMappingEntry(kv.Key, kv.Value); key != "" && value != "" {
 			mappings[key] = value
 		}
 	}
 	return mappings
 }
 
+// extractMappingEntry extracts the terraform resource type and the registration function name of a map entry like
+// "azurerm_key_vault": resourceKeyVault(), empty when the entry isn't a string key set to a function call
+func extractMappingEntry(keyExpr, valueExpr ast.Expr) (string, string) {
+	// Extract the key (terraform resource type)
+	var key string
+	if keyLit, ok := keyExpr.(*ast.BasicLit); ok && keyLit.Kind == token.STRING {
+		key = strings.Trim(keyLit.Value, `"`)
+	}
+
+	// Extract the value (function call name)
+	var value string
+	callExpr, ok := valueExpr.(*ast.CallExpr)
+	if !ok {
+		return "", ""
+	}
+	if fnIdent, ok := unwrapTypeArguments(callExpr.Fun).(*ast.Ident); ok {
+		value = fnIdent.Name
+	}
+	return key, value
+}
+
 // extractFromSliceLiteral extracts struct type names from a slice literal
 func extractFromSliceLiteral(sliceLit *ast.CompositeLit) []string {
 	var types []string
@@ -253,9 +630,9 @@ func extractFromSliceLiteral(sliceLit *ast.CompositeLit) []string {
 			continue
 		}
 
-		// Extract the struct type name
-		if ident, ok := compLit.Type.(*ast.Ident); ok {
-			types = append(types, ident.Name)
+		// Extract the struct type name, dropping type arguments of generic structs like StructName[T]{}
+		if name := typeName(compLit.Type); name != "" {
+			types = append(types, name)
 		}
 	}
 	return types
@@ -372,6 +749,9 @@ func extractFunctionReference(expr ast.Expr) string {
 	case *ast.SelectorExpr:
 		// Selector expression: package.FuncName
 		return e.Sel.Name
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		// Generic instantiation: funcName[T] or package.FuncName[K, V]
+		return extractFunctionReference(unwrapTypeArguments(e))
 	default:
 		return ""
 	}
@@ -388,45 +768,6 @@ func mergeMap[TK comparable, TV any](m1, m2 map[TK]TV) map[TK]TV {
 	return m
 }
 
-// extractResourceTerraformTypes extracts Terraform types from ResourceType methods for each resource struct
-func extractResourceTerraformTypes(packageInfo *gophon.PackageInfo, resourceStructs []string) map[string]string {
-	terraformTypes := make(map[string]string)
-
-	for _, structName := range resourceStructs {
-		if terraformType := extractTerraformTypeFromResourceTypeMethod(packageInfo, structName); terraformType != "" {
-			terraformTypes[structName] = terraformType
-		}
-	}
-
-	return terraformTypes
-}
-
-// extractDataSourceTerraformTypes extracts Terraform types from ResourceType methods for each data source struct
-func extractDataSourceTerraformTypes(packageInfo *gophon.PackageInfo, dataSourceStructs []string) map[string]string {
-	terraformTypes := make(map[string]string)
-
-	for _, structName := range dataSourceStructs {
-		if terraformType := extractTerraformTypeFromResourceTypeMethod(packageInfo, structName); terraformType != "" {
-			terraformTypes[structName] = terraformType
-		}
-	}
-
-	return terraformTypes
-}
-
-// extractEphemeralTerraformTypes extracts Terraform types from Metadata methods for each ephemeral struct
-func extractEphemeralTerraformTypes(packageInfo *gophon.PackageInfo, ephemeralStructs []string) map[string]string {
-	terraformTypes := make(map[string]string)
-
-	for _, structName := range ephemeralStructs {
-		if terraformType := extractTerraformTypeFromMetadataMethod(packageInfo, structName); terraformType != "" {
-			terraformTypes[structName] = terraformType
-		}
-	}
-
-	return terraformTypes
-}
-
 // extractTerraformTypeFromResourceTypeMethod extracts terraform type from ResourceType method of a struct
 func extractTerraformTypeFromResourceTypeMethod(packageInfo *gophon.PackageInfo, structName string) string {
 	for _, fileInfo := range packageInfo.Files {
@@ -442,23 +783,9 @@ func extractTerraformTypeFromResourceTypeMethod(packageInfo *gophon.PackageInfo,
 				return true
 			}
 
-			// Check if this method belongs to our struct
+			// Check if this method belongs to our struct, handling pointer, value and generic receivers
 			if fn.Recv != nil && len(fn.Recv.List) > 0 {
-				var receiverTypeName string
-
-				// Handle both pointer receiver (*StructName) and value receiver (StructName)
-				switch recvType := fn.Recv.List[0].Type.(type) {
-				case *ast.StarExpr:
-					// Pointer receiver: *StructName
-					if ident, ok := recvType.X.(*ast.Ident); ok {
-						receiverTypeName = ident.Name
-					}
-				case *ast.Ident:
-					// Value receiver: StructName
-					receiverTypeName = recvType.Name
-				}
-
-				if receiverTypeName == structName {
+				if receiverTypeName(fn) == structName {
 					// Found the ResourceType method for our struct
 					result = extractStringReturnValue(fn)
 					return false // Stop traversing
@@ -488,23 +815,9 @@ func extractTerraformTypeFromMetadataMethod(packageInfo *gophon.PackageInfo, str
 				return true
 			}
 
-			// Check if this method belongs to our struct
+			// Check if this method belongs to our struct, handling pointer, value and generic receivers
 			if fn.Recv != nil && len(fn.Recv.List) > 0 {
-				var receiverTypeName string
-
-				// Handle both pointer receiver (*StructName) and value receiver (StructName)
-				switch recvType := fn.Recv.List[0].Type.(type) {
-				case *ast.StarExpr:
-					// Pointer receiver: *StructName
-					if ident, ok := recvType.X.(*ast.Ident); ok {
-						receiverTypeName = ident.Name
-					}
-				case *ast.Ident:
-					// Value receiver: StructName
-					receiverTypeName = recvType.Name
-				}
-
-				if receiverTypeName == structName {
+				if receiverTypeName(fn) == structName {
 					// Found the Metadata method for our struct
 					result = extractTypeNameFromMetadataMethod(fn)
 					return false // Stop traversing
@@ -567,6 +880,15 @@ func extractTypeNameFromMetadataMethod(fn *ast.FuncDecl) string {
 		if selectorExpr.Sel.Name != "TypeName" {
 			continue
 		}
+		// Look for resp.TypeName = req.ProviderTypeName + "_something"
+		if binaryExpr, ok := assignStmt.Rhs[0].(*ast.BinaryExpr); ok && binaryExpr.Op == token.ADD {
+			if providerSelector, ok := binaryExpr.X.(*ast.SelectorExpr); ok && providerSelector.Sel.Name == "ProviderTypeName" {
+				if suffix := stringLiteralValue(binaryExpr.Y); suffix != "" {
+					return DefaultProviderName + suffix
+				}
+			}
+			continue
+		}
 		basicLit, ok := assignStmt.Rhs[0].(*ast.BasicLit)
 		if !ok {
 			continue
@@ -578,3 +900,171 @@ func extractTypeNameFromMetadataMethod(fn *ast.FuncDecl) string {
 	}
 	return ""
 }
+
+// findFunctionDecl locates a package level function declaration by name in gophon function data
+func findFunctionDecl(packageInfo *gophon.PackageInfo, funcName string) *ast.FuncDecl {
+	if packageInfo == nil {
+		return nil
+	}
+	for _, funcInfo := range packageInfo.Functions {
+		// gophon leaves ReceiverType empty for generic receivers, so check the declaration itself
+		if funcInfo.Name == funcName && funcInfo.FuncDecl != nil && funcInfo.FuncDecl.Recv == nil {
+			return funcInfo.FuncDecl
+		}
+	}
+	return nil
+}
+
+// findMethodDecl locates a method declaration on the given struct, accepting both value and pointer receivers
+func findMethodDecl(packageInfo *gophon.PackageInfo, structName, methodName string) *ast.FuncDecl {
+	if packageInfo == nil {
+		return nil
+	}
+	for _, funcInfo := range packageInfo.Functions {
+		if funcInfo.Name != methodName || funcInfo.FuncDecl == nil {
+			continue
+		}
+		if receiverTypeName(funcInfo.FuncDecl) == structName {
+			return funcInfo.FuncDecl
+		}
+	}
+	return nil
+}
+
+// receiverTypeName returns the type name of a method receiver, handling pointer and generic receivers such as
+// *StructName, StructName[T] and *StructName[K, V]. It returns "" for functions without receiver.
+func receiverTypeName(fn *ast.FuncDecl) string {
+	if fn == nil || fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	expr := fn.Recv.List[0].Type
+	if starExpr, ok := expr.(*ast.StarExpr); ok {
+		expr = starExpr.X
+	}
+	return typeName(expr)
+}
+
+// typeName returns the name of a local type expression, dropping the type arguments of generic instantiations
+func typeName(expr ast.Expr) string {
+	if ident, ok := unwrapTypeArguments(expr).(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// unwrapTypeArguments strips the type arguments of a generic instantiation, Name[T] and pkg.Name[K, V] result in
+// Name and pkg.Name, any other expression is returned as is
+func unwrapTypeArguments(expr ast.Expr) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.IndexExpr:
+		return e.X
+	case *ast.IndexListExpr:
+		return e.X
+	}
+	return expr
+}
+
+// findResourceLiterals collects the top level pluginsdk.Resource composite literals built by a legacy
+// registration function, either returned directly or assigned to a variable first
+func findResourceLiterals(fn *ast.FuncDecl) []*ast.CompositeLit {
+	if fn == nil || fn.Body == nil {
+		return nil
+	}
+
+	var literals []*ast.CompositeLit
+	collect := func(expr ast.Expr) {
+		unaryExpr, ok := expr.(*ast.UnaryExpr)
+		if !ok || unaryExpr.Op != token.AND {
+			return
+		}
+		compLit, ok := unaryExpr.X.(*ast.CompositeLit)
+		if !ok || !isResourceType(compLit.Type) {
+			return
+		}
+		literals = append(literals, compLit)
+	}
+
+	for _, stmt := range fn.Body.List {
+		switch s := stmt.(type) {
+		case *ast.ReturnStmt:
+			for _, result := range s.Results {
+				collect(result)
+			}
+		case *ast.AssignStmt:
+			for _, rhs := range s.Rhs {
+				collect(rhs)
+			}
+		case *ast.DeclStmt:
+			genDecl, ok := s.Decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				if valueSpec, ok := spec.(*ast.ValueSpec); ok {
+					for _, value := range valueSpec.Values {
+						collect(value)
+					}
+				}
+			}
+		}
+	}
+	return literals
+}
+
+// isResourceType reports whether a composite literal type is pluginsdk.Resource (or an unqualified Resource)
+func isResourceType(expr ast.Expr) bool {
+	switch t := expr.(type) {
+	case *ast.SelectorExpr:
+		return t.Sel.Name == "Resource"
+	case *ast.Ident:
+		return t.Name == "Resource"
+	}
+	return false
+}
+
+// compositeLitField returns the value of the named field in a keyed composite literal
+func compositeLitField(compLit *ast.CompositeLit, fieldName string) ast.Expr {
+	if compLit == nil {
+		return nil
+	}
+	for _, elt := range compLit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if ident, ok := kv.Key.(*ast.Ident); ok && ident.Name == fieldName {
+			return kv.Value
+		}
+	}
+	return nil
+}
+
+// returnedCompositeLit returns the first composite literal returned by a function, dereferencing &T{} results
+func returnedCompositeLit(fn *ast.FuncDecl) *ast.CompositeLit {
+	if fn == nil || fn.Body == nil {
+		return nil
+	}
+	var result *ast.CompositeLit
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if result != nil {
+			return false
+		}
+		// Do not descend into closures, their return statements belong to another function
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		returnStmt, ok := n.(*ast.ReturnStmt)
+		if !ok || len(returnStmt.Results) == 0 {
+			return true
+		}
+		expr := returnStmt.Results[0]
+		if unaryExpr, ok := expr.(*ast.UnaryExpr); ok && unaryExpr.Op == token.AND {
+			expr = unaryExpr.X
+		}
+		if compLit, ok := expr.(*ast.CompositeLit); ok {
+			result = compLit
+		}
+		return false
+	})
+	return result
+}
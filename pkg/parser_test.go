@@ -1,6 +1,7 @@
 package pkg
 
 import (
+	"fmt"
 	gophon "github.com/lonegunmanb/gophon/pkg"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,6 +16,48 @@ func parseSource(source string) (*ast.File, error) {
 	return parser.ParseFile(fset, "", source, parser.ParseComments)
 }
 
+// parsePackageInfo builds a gophon PackageInfo with files and functions (including receiver types) from source code
+func parsePackageInfo(t *testing.T, sources ...string) *gophon.PackageInfo {
+	packageInfo := &gophon.PackageInfo{}
+	for i, source := range sources {
+		fset := token.NewFileSet()
+		fileName := fmt.Sprintf("file%d.go", i)
+		file, err := parser.ParseFile(fset, fileName, source, parser.ParseComments)
+		require.NoError(t, err)
+
+		fileInfo := &gophon.FileInfo{File: file, FileName: fileName, FilePath: fileName}
+		packageInfo.Files = append(packageInfo.Files, fileInfo)
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			receiverType := ""
+			if fn.Recv != nil && len(fn.Recv.List) > 0 {
+				switch recv := fn.Recv.List[0].Type.(type) {
+				case *ast.StarExpr:
+					if ident, ok := recv.X.(*ast.Ident); ok {
+						receiverType = "*" + ident.Name
+					}
+				case *ast.Ident:
+					receiverType = recv.Name
+				}
+			}
+			packageInfo.Functions = append(packageInfo.Functions, &gophon.FunctionInfo{
+				Range: &gophon.Range{
+					FileInfo:  fileInfo,
+					StartLine: fset.Position(fn.Pos()).Line,
+					EndLine:   fset.Position(fn.End()).Line,
+				},
+				FuncDecl:     fn,
+				Name:         fn.Name.Name,
+				ReceiverType: receiverType,
+			})
+		}
+	}
+	return packageInfo
+}
+
 func TestExtractSupportedResourcesMappings(t *testing.T) {
 	// Test case with the exact example provided
 	source := `package resource
@@ -73,6 +116,108 @@ func (r Registration) SupportedResources() map[string]*pluginsdk.Resource {
 	assert.Equal(t, expected, result)
 }
 
+func TestExtractSupportedResourcesPointerReceiver(t *testing.T) {
+	source := `package keyvault
+
+func (r *Registration) SupportedResources() map[string]*pluginsdk.Resource {
+	return map[string]*pluginsdk.Resource{
+		"azurerm_key_vault": resourceKeyVault(),
+	}
+}
+
+func (r *Registration) Resources() []sdk.Resource {
+	return []sdk.Resource{
+		KeyVaultManagedHSMResource{},
+	}
+}`
+
+	node, err := parseSource(source)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"azurerm_key_vault": "resourceKeyVault"}, extractSupportedResourcesMappings(node))
+	assert.Equal(t, []string{"KeyVaultManagedHSMResource"}, extractResourcesStructTypes(node))
+}
+
+func TestExtractSupportedResourcesMultipleRegistrationTypes(t *testing.T) {
+	source := `package network
+
+type Registration struct{}
+
+type autoRegistration struct{}
+
+func (r Registration) SupportedResources() map[string]*pluginsdk.Resource {
+	return map[string]*pluginsdk.Resource{
+		"azurerm_subnet": resourceSubnet(),
+	}
+}
+
+func (autoRegistration) Resources() []sdk.Resource {
+	return []sdk.Resource{
+		VirtualNetworkResource{},
+	}
+}
+
+func (r *autoRegistration) DataSources() []sdk.DataSource {
+	return []sdk.DataSource{
+		VirtualNetworkDataSource{},
+	}
+}
+
+func (r Registration) Resources() []sdk.Resource {
+	return []sdk.Resource{
+		NetworkManagerResource{},
+	}
+}`
+
+	node, err := parseSource(source)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"azurerm_subnet": "resourceSubnet"}, extractSupportedResourcesMappings(node))
+	assert.ElementsMatch(t, []string{"VirtualNetworkResource", "NetworkManagerResource"}, extractResourcesStructTypes(node))
+	assert.Equal(t, []string{"VirtualNetworkDataSource"}, extractDataSourcesStructTypes(node))
+}
+
+func TestExtractSupportedResourcesRegistrationInterfaceType(t *testing.T) {
+	source := `package legacy
+
+type Service struct{}
+
+func (Service) Name() string {
+	return "Legacy"
+}
+
+func (Service) WebsiteCategories() []string {
+	return []string{"Legacy"}
+}
+
+func (Service) SupportedResources() map[string]*pluginsdk.Resource {
+	return map[string]*pluginsdk.Resource{
+		"azurerm_legacy": resourceLegacy(),
+	}
+}
+
+// Types other than the registration types may declare methods with the names of registration methods
+type Client struct{}
+
+func (c *Client) SupportedResources() map[string]*pluginsdk.Resource {
+	return map[string]*pluginsdk.Resource{
+		"azurerm_not_registered": resourceNotRegistered(),
+	}
+}
+
+func SupportedResources() map[string]*pluginsdk.Resource {
+	return map[string]*pluginsdk.Resource{
+		"azurerm_function": resourceFunction(),
+	}
+}`
+
+	node, err := parseSource(source)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"azurerm_legacy": "resourceLegacy"}, extractSupportedResourcesMappings(node))
+	assert.Equal(t, "Legacy", extractServiceDisplayName(node))
+}
+
 func TestExtractSupportedResourcesEmptyMethod(t *testing.T) {
 	// Test case with empty method
 	source := `package resource
@@ -393,6 +538,65 @@ func (r Registration) DataSources() []sdk.DataSource {
 	assert.Empty(t, result)
 }
 
+func TestExtractResourcesStructTypesConditional(t *testing.T) {
+	// Test case with append, feature flag conditions and loops
+	source := `package service
+
+func (r Registration) Resources() []sdk.Resource {
+	resources := []sdk.Resource{
+		ConfigResource{},
+	}
+	resources = append(resources, InfoResource{})
+	for _, resource := range []sdk.Resource{LinkResource{}, TagResource{}} {
+		resources = append(resources, resource)
+	}
+	if !features.FivePointOh() {
+		resources = append(resources, LegacyConfigResource{}, ConfigResource{})
+	} else {
+		resources = append(resources, []sdk.Resource{PreviewResource{}}...)
+	}
+	for _, enabled := range features.Flags() {
+		if enabled {
+			resources = append(resources, FlaggedResource{})
+		}
+	}
+	switch features.Channel() {
+	case "beta":
+		resources = append(resources, BetaResource{})
+	}
+	return resources
+}`
+
+	node, err := parseSource(source)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"ConfigResource", "InfoResource", "LinkResource", "TagResource", "LegacyConfigResource", "PreviewResource", "FlaggedResource", "BetaResource"}, extractResourcesStructTypes(node))
+	assert.Equal(t, []string{"LegacyConfigResource", "PreviewResource", "FlaggedResource", "BetaResource"}, extractConditionalResourcesStructTypes(node))
+}
+
+func TestExtractDataSourcesStructTypesConditionalReturn(t *testing.T) {
+	// Test case with a literal returned only when a feature flag is set
+	source := `package service
+
+func (r Registration) DataSources() []sdk.DataSource {
+	if features.FivePointOh() {
+		return []sdk.DataSource{
+			ConfigDataSource{},
+			PreviewDataSource{},
+		}
+	}
+	return []sdk.DataSource{
+		ConfigDataSource{},
+	}
+}`
+
+	node, err := parseSource(source)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"ConfigDataSource", "PreviewDataSource"}, extractDataSourcesStructTypes(node))
+	assert.Equal(t, []string{"PreviewDataSource"}, extractConditionalDataSourcesStructTypes(node))
+}
+
 func TestExtractEphemeralResourcesFunctions(t *testing.T) {
 	// Test case based on the actual keyvault service example
 	source := `package keyvault
@@ -867,3 +1071,164 @@ func (e *KeyVaultCertificateEphemeralResource) Metadata(_ context.Context, _ eph
 		})
 	}
 }
+
+func TestExtractSupportedResourcesIncrementalConstruction(t *testing.T) {
+	source := `package keyvault
+
+import "maps"
+
+func (r Registration) SupportedResources() map[string]*pluginsdk.Resource {
+	resources := map[string]*pluginsdk.Resource{
+		"azurerm_key_vault": resourceKeyVault(),
+	}
+	resources["azurerm_key_vault_secret"] = resourceKeyVaultSecret()
+	maps.Copy(resources, keyResources())
+	for name, resource := range r.certificateResources() {
+		resources[name] = resource
+	}
+	addAccessPolicyResources(resources)
+	if features.FivePointOh() {
+		resources["azurerm_key_vault_managed_hsm"] = resourceKeyVaultManagedHardwareSecurityModule()
+	}
+	return resources
+}
+
+func keyResources() map[string]*pluginsdk.Resource {
+	return map[string]*pluginsdk.Resource{
+		"azurerm_key_vault_key": resourceKeyVaultKey(),
+	}
+}
+
+func (r Registration) certificateResources() map[string]*pluginsdk.Resource {
+	var certificates = map[string]*pluginsdk.Resource{}
+	certificates["azurerm_key_vault_certificate"] = resourceKeyVaultCertificate()
+	return certificates
+}
+
+func addAccessPolicyResources(into map[string]*pluginsdk.Resource) {
+	into["azurerm_key_vault_access_policy"] = resourceKeyVaultAccessPolicy()
+}`
+
+	node, err := parseSource(source)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"azurerm_key_vault":               "resourceKeyVault",
+		"azurerm_key_vault_secret":        "resourceKeyVaultSecret",
+		"azurerm_key_vault_key":           "resourceKeyVaultKey",
+		"azurerm_key_vault_certificate":   "resourceKeyVaultCertificate",
+		"azurerm_key_vault_access_policy": "resourceKeyVaultAccessPolicy",
+		"azurerm_key_vault_managed_hsm":   "resourceKeyVaultManagedHardwareSecurityModule",
+	}, extractSupportedResourcesMappings(node))
+}
+
+func TestExtractSupportedDataSourcesReturnedHelperCall(t *testing.T) {
+	source := `package keyvault
+
+func (r Registration) SupportedDataSources() map[string]*pluginsdk.Resource {
+	return dataSources()
+}
+
+func dataSources() map[string]*pluginsdk.Resource {
+	dataSources := dataSources()
+	dataSources["azurerm_key_vault"] = dataSourceKeyVault()
+	return dataSources
+}`
+
+	node, err := parseSource(source)
+	require.NoError(t, err)
+
+	// The recursive helper stops at the resolve depth instead of looping
+	assert.Equal(t, map[string]string{
+		"azurerm_key_vault": "dataSourceKeyVault",
+	}, extractSupportedDataSourcesMappings(node))
+}
+
+func TestNewTerraformInfo_Conditional(t *testing.T) {
+	serviceReg := ServiceRegistration{
+		ResourceTerraformTypes:   map[string]string{"PreviewResource": "azurerm_preview", "ConfigResource": "azurerm_config"},
+		DataSourceTerraformTypes: map[string]string{"PreviewDataSource": "azurerm_preview"},
+		ConditionalResources:     []string{"PreviewResource"},
+		ConditionalDataSources:   []string{"PreviewDataSource"},
+	}
+
+	assert.True(t, newTerraformResourceInfo("azurerm_preview", "PreviewResource", "", "modern_sdk", serviceReg).Conditional)
+	assert.False(t, newTerraformResourceInfo("azurerm_config", "ConfigResource", "", "modern_sdk", serviceReg).Conditional)
+	assert.True(t, newTerraformDataSourceInfo("azurerm_preview", "PreviewDataSource", "", "modern_sdk", serviceReg).Conditional)
+}
+
+func TestExtractServiceDisplayNameAndWebsiteCategories(t *testing.T) {
+	source := `package keyvault
+
+type Registration struct{}
+
+func (r Registration) Name() string {
+	return "Key Vault"
+}
+
+func (r Registration) WebsiteCategories() []string {
+	return []string{
+		"Key Vault",
+		"Security",
+	}
+}
+
+func (r Registration) SupportedResources() map[string]*pluginsdk.Resource {
+	return map[string]*pluginsdk.Resource{}
+}
+
+func (r KeyVaultResource) Name() string {
+	return "not a service"
+}`
+
+	node, err := parseSource(source)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Key Vault", extractServiceDisplayName(node))
+	assert.Equal(t, []string{"Key Vault", "Security"}, extractServiceWebsiteCategories(node))
+}
+
+func TestExtractServiceDisplayNameWithoutRegistration(t *testing.T) {
+	source := `package keyvault
+
+func (r KeyVaultResource) Name() string {
+	return "not a service"
+}`
+
+	node, err := parseSource(source)
+	require.NoError(t, err)
+
+	assert.Empty(t, extractServiceDisplayName(node))
+	assert.Empty(t, extractServiceWebsiteCategories(node))
+}
+
+func TestExtractServiceGitHubLabel(t *testing.T) {
+	source := `package keyvault
+
+type Registration struct{}
+
+func (r Registration) AssociatedGitHubLabel() string {
+	return "service/key-vault"
+}
+
+func (r Registration) Resources() []sdk.Resource {
+	return []sdk.Resource{}
+}
+
+func (r KeyVaultResource) AssociatedGitHubLabel() string {
+	return "not a service"
+}`
+
+	node, err := parseSource(source)
+	require.NoError(t, err)
+
+	assert.Equal(t, "service/key-vault", extractServiceGitHubLabel(node))
+}
+
+func TestNewTerraformInfo_GitHubLabel(t *testing.T) {
+	serviceReg := ServiceRegistration{ServiceName: "keyvault", GitHubLabel: "service/key-vault"}
+
+	assert.Equal(t, "service/key-vault", newTerraformResourceInfo("azurerm_key_vault", "", "resourceKeyVault", "legacy_pluginsdk", serviceReg).GitHubLabel)
+	assert.Equal(t, "service/key-vault", newTerraformDataSourceInfo("azurerm_key_vault", "KeyVaultDataSource", "", "modern_sdk", serviceReg).GitHubLabel)
+	assert.Equal(t, "service/key-vault", newTerraformEphemeralInfo("KeyVaultSecretEphemeralResource", serviceReg).GitHubLabel)
+}
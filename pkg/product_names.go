@@ -0,0 +1,170 @@
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProductNames maps service package names to Azure product display names, "keyvault" -> "Azure Key Vault"
+type ProductNames map[string]string
+
+// DefaultProductNames are the Azure product names of the services of the azurerm provider, set on every scanned
+// service. Services missing here keep an empty product name until a mapping is loaded with LoadProductNames.
+var DefaultProductNames = ProductNames{
+	"aadb2c":                            "Azure Active Directory B2C",
+	"advisor":                           "Azure Advisor",
+	"analysisservices":                  "Azure Analysis Services",
+	"apimanagement":                     "Azure API Management",
+	"appconfiguration":                  "Azure App Configuration",
+	"applicationinsights":               "Azure Application Insights",
+	"appservice":                        "Azure App Service",
+	"arckubernetes":                     "Azure Arc-enabled Kubernetes",
+	"attestation":                       "Azure Attestation",
+	"authorization":                     "Azure Role-Based Access Control",
+	"automation":                        "Azure Automation",
+	"azurestackhci":                     "Azure Stack HCI",
+	"batch":                             "Azure Batch",
+	"blueprints":                        "Azure Blueprints",
+	"bot":                               "Azure AI Bot Service",
+	"cdn":                               "Azure Content Delivery Network",
+	"chaosstudio":                       "Azure Chaos Studio",
+	"cognitive":                         "Azure AI Services",
+	"communication":                     "Azure Communication Services",
+	"compute":                           "Azure Virtual Machines",
+	"confidentialledger":                "Azure Confidential Ledger",
+	"connections":                       "Azure API Connections",
+	"consumption":                       "Azure Consumption",
+	"containerapps":                     "Azure Container Apps",
+	"containers":                        "Azure Kubernetes Service",
+	"cosmos":                            "Azure Cosmos DB",
+	"costmanagement":                    "Azure Cost Management",
+	"dashboard":                         "Azure Managed Grafana",
+	"databasemigration":                 "Azure Database Migration Service",
+	"databoxedge":                       "Azure Stack Edge",
+	"databricks":                        "Azure Databricks",
+	"datafactory":                       "Azure Data Factory",
+	"dataprotection":                    "Azure Backup",
+	"datashare":                         "Azure Data Share",
+	"desktopvirtualization":             "Azure Virtual Desktop",
+	"devcenter":                         "Microsoft Dev Box",
+	"devtestlabs":                       "Azure DevTest Labs",
+	"digitaltwins":                      "Azure Digital Twins",
+	"dns":                               "Azure DNS",
+	"elastic":                           "Elastic Cloud (Elasticsearch) - An Azure Native ISV Service",
+	"elasticsan":                        "Azure Elastic SAN",
+	"eventgrid":                         "Azure Event Grid",
+	"eventhub":                          "Azure Event Hubs",
+	"firewall":                          "Azure Firewall",
+	"frontdoor":                         "Azure Front Door",
+	"graphservices":                     "Microsoft Graph Services",
+	"hdinsight":                         "Azure HDInsight",
+	"healthcare":                        "Azure Health Data Services",
+	"hsm":                               "Azure Dedicated HSM",
+	"iotcentral":                        "Azure IoT Central",
+	"iothub":                            "Azure IoT Hub",
+	"keyvault":                          "Azure Key Vault",
+	"kusto":                             "Azure Data Explorer",
+	"lighthouse":                        "Azure Lighthouse",
+	"loadbalancer":                      "Azure Load Balancer",
+	"loadtestservice":                   "Azure Load Testing",
+	"loganalytics":                      "Azure Log Analytics",
+	"logic":                             "Azure Logic Apps",
+	"machinelearning":                   "Azure Machine Learning",
+	"maintenance":                       "Azure Maintenance Configurations",
+	"managedapplications":               "Azure Managed Applications",
+	"managedhsm":                        "Azure Key Vault Managed HSM",
+	"managedidentity":                   "Managed Identities for Azure Resources",
+	"managementgroup":                   "Azure Management Groups",
+	"maps":                              "Azure Maps",
+	"mariadb":                           "Azure Database for MariaDB",
+	"mixedreality":                      "Azure Spatial Anchors",
+	"mobilenetwork":                     "Azure Private 5G Core",
+	"monitor":                           "Azure Monitor",
+	"mssql":                             "Azure SQL Database",
+	"mssqlmanagedinstance":              "Azure SQL Managed Instance",
+	"mysql":                             "Azure Database for MySQL",
+	"netapp":                            "Azure NetApp Files",
+	"network":                           "Azure Virtual Network",
+	"networkfunction":                   "Azure Network Function Manager",
+	"newrelic":                          "Azure Native New Relic Service",
+	"nginx":                             "NGINXaaS for Azure",
+	"notificationhub":                   "Azure Notification Hubs",
+	"orbital":                           "Azure Orbital",
+	"paloalto":                          "Cloud NGFW by Palo Alto Networks",
+	"policy":                            "Azure Policy",
+	"portal":                            "Azure Portal Dashboards",
+	"postgres":                          "Azure Database for PostgreSQL",
+	"powerbi":                           "Power BI Embedded",
+	"privatedns":                        "Azure Private DNS",
+	"privatednsresolver":                "Azure DNS Private Resolver",
+	"purview":                           "Microsoft Purview",
+	"recoveryservices":                  "Azure Site Recovery",
+	"redis":                             "Azure Cache for Redis",
+	"relay":                             "Azure Relay",
+	"resource":                          "Azure Resource Manager",
+	"search":                            "Azure AI Search",
+	"securitycenter":                    "Microsoft Defender for Cloud",
+	"sentinel":                          "Microsoft Sentinel",
+	"servicebus":                        "Azure Service Bus",
+	"servicefabric":                     "Azure Service Fabric",
+	"signalr":                           "Azure SignalR Service",
+	"springcloud":                       "Azure Spring Apps",
+	"storage":                           "Azure Storage",
+	"storagemover":                      "Azure Storage Mover",
+	"streamanalytics":                   "Azure Stream Analytics",
+	"subscription":                      "Azure Subscriptions",
+	"synapse":                           "Azure Synapse Analytics",
+	"systemcentervirtualmachinemanager": "System Center Virtual Machine Manager enabled by Azure Arc",
+	"trafficmanager":                    "Azure Traffic Manager",
+	"voiceservices":                     "Azure Communications Gateway",
+	"workloads":                         "Azure Center for SAP Solutions",
+}
+
+// LoadProductNames reads a YAML or JSON mapping of service package names to product names, for example:
+//
+//	keyvault: Azure Key Vault
+//	containers: Azure Kubernetes Service (AKS)
+func LoadProductNames(path string) (ProductNames, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read product names %s: %w", path, err)
+	}
+	var names ProductNames
+	if err := yaml.Unmarshal(data, &names); err != nil {
+		return nil, fmt.Errorf("failed to parse product names %s: %w", path, err)
+	}
+	for service, name := range names {
+		if name == "" {
+			return nil, fmt.Errorf("product name of service %s in %s is empty", service, path)
+		}
+	}
+	return names, nil
+}
+
+// ApplyProductNames sets the product name of every service, from the given names or else DefaultProductNames, and
+// returns the sorted services of the given names matching no service of the index
+func (index *TerraformProviderIndex) ApplyProductNames(names ProductNames) []string {
+	matched := make(map[string]bool)
+	for i := range index.Services {
+		service := index.Services[i].ServiceName
+		if name, exists := names[service]; exists {
+			index.Services[i].ProductName = name
+			matched[service] = true
+			continue
+		}
+		index.Services[i].ProductName = DefaultProductNames[service]
+	}
+	index.ProductNames = names
+
+	var unmatched []string
+	for service := range names {
+		if !matched[service] {
+			unmatched = append(unmatched, service)
+		}
+	}
+	sort.Strings(unmatched)
+	return unmatched
+}
@@ -0,0 +1,61 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadProductNames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "product-names.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+keyvault: Azure Key Vault
+containers: Azure Kubernetes Service (AKS)
+`), 0644))
+
+	names, err := LoadProductNames(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, ProductNames{"keyvault": "Azure Key Vault", "containers": "Azure Kubernetes Service (AKS)"}, names)
+}
+
+func TestLoadProductNames_EmptyName(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "product-names.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("keyvault: \"\"\n"), 0644))
+
+	_, err := LoadProductNames(path)
+
+	assert.ErrorContains(t, err, "product name of service keyvault")
+}
+
+func TestTerraformProviderIndex_ApplyProductNames(t *testing.T) {
+	index := &TerraformProviderIndex{
+		Services: []ServiceRegistration{
+			{ServiceName: "containers"},
+			{ServiceName: "keyvault"},
+			{ServiceName: "internalonly", ProductName: "stale"},
+		},
+	}
+
+	unmatched := index.ApplyProductNames(ProductNames{"containers": "Azure Kubernetes Service (AKS)", "removed": "Azure Removed"})
+
+	assert.Equal(t, []string{"removed"}, unmatched)
+	assert.Equal(t, "Azure Kubernetes Service (AKS)", index.Services[0].ProductName)
+	assert.Equal(t, "Azure Key Vault", index.Services[1].ProductName)
+	assert.Empty(t, index.Services[2].ProductName)
+}
+
+func TestScanTerraformProviderServices_DefaultProductNames(t *testing.T) {
+	index, err := ScanTerraformProviderServices(filepath.Join("testharness", "internal", "services"), "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+	require.NoError(t, err)
+
+	productNames := make(map[string]string)
+	for _, service := range index.Services {
+		productNames[service.ServiceName] = service.ProductName
+	}
+	assert.Equal(t, "Azure Key Vault", productNames["keyvault"])
+	assert.Equal(t, "Azure Storage", productNames["storage"])
+}
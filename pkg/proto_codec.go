@@ -0,0 +1,416 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The proto format encodes Go structs by reflection with the rules of the messages generateProtoDefinition derives
+// from them: the fields with a json name are encoded with the field number of their protobuf tag, `protobuf:"3"`, so
+// fields can be reordered freely while the numbers of released fields must never change or be reused. Strings, booleans, integers and floats map to string, bool, int64 and double, structs
+// and pointers to structs to messages, slices to repeated fields and maps with string keys to proto maps. Values of
+// interface{} type, the user-maintained annotations, are encoded as JSON strings.
+
+// protoPackage is the package of the generated proto definition
+const protoPackage = "terraform_provider_index.v1"
+
+// protoMessageNames renames Go types whose names don't suit the proto definition
+var protoMessageNames = map[string]string{"ProtoIndex": "Index"}
+
+// protoField is a field of a Go struct encoded in the proto format
+type protoField struct {
+	index  int    // Index of the field in the Go struct
+	number int    // Proto field number
+	name   string // Proto field name, the json name of the Go field
+}
+
+// protoFields returns the encoded fields of a struct type with the field numbers of their protobuf tags, in
+// declaration order
+func protoFields(t reflect.Type) ([]protoField, error) {
+	var fields []protoField
+	numbers := make(map[int]string)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		number, err := strconv.Atoi(field.Tag.Get("protobuf"))
+		if err != nil || number < 1 || number > int(protowire.MaxValidNumber) {
+			return nil, fmt.Errorf("%s.%s has no valid protobuf field number tag", t.Name(), field.Name)
+		}
+		if other, ok := numbers[number]; ok {
+			return nil, fmt.Errorf("%s.%s and %s.%s share the protobuf field number %d", t.Name(), other, t.Name(), field.Name, number)
+		}
+		numbers[number] = field.Name
+		fields = append(fields, protoField{index: i, number: number, name: name})
+	}
+	return fields, nil
+}
+
+// protoMessageName returns the message name of a struct type
+func protoMessageName(t reflect.Type) string {
+	if name, ok := protoMessageNames[t.Name()]; ok {
+		return name
+	}
+	return t.Name()
+}
+
+// marshalProto encodes a struct in the proto format
+func marshalProto(message interface{}) ([]byte, error) {
+	return appendProtoMessage(nil, reflect.ValueOf(message))
+}
+
+func appendProtoMessage(b []byte, message reflect.Value) ([]byte, error) {
+	fields, err := protoFields(message.Type())
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		if b, err = appendProtoField(b, protowire.Number(field.number), message.Field(field.index)); err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", protoMessageName(message.Type()), field.name, err)
+		}
+	}
+	return b, nil
+}
+
+// appendProtoField appends a field value, zero scalars and nil pointers are omitted like proto3 does
+func appendProtoField(b []byte, number protowire.Number, value reflect.Value) ([]byte, error) {
+	switch value.Kind() {
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			var err error
+			if b, err = appendProtoValue(b, number, value.Index(i), true); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case reflect.Map:
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+		for _, key := range keys {
+			entry := protowire.AppendTag(nil, 1, protowire.BytesType)
+			entry = protowire.AppendString(entry, key.String())
+			entry, err := appendProtoValue(entry, 2, value.MapIndex(key), true)
+			if err != nil {
+				return nil, err
+			}
+			b = protowire.AppendTag(b, number, protowire.BytesType)
+			b = protowire.AppendBytes(b, entry)
+		}
+		return b, nil
+	}
+	return appendProtoValue(b, number, value, false)
+}
+
+// appendProtoValue appends a single value, always when it is an element of a repeated field or map
+func appendProtoValue(b []byte, number protowire.Number, value reflect.Value, element bool) ([]byte, error) {
+	switch value.Kind() {
+	case reflect.String:
+		if value.Len() > 0 || element {
+			b = protowire.AppendTag(b, number, protowire.BytesType)
+			b = protowire.AppendString(b, value.String())
+		}
+	case reflect.Bool:
+		if value.Bool() || element {
+			b = protowire.AppendTag(b, number, protowire.VarintType)
+			b = protowire.AppendVarint(b, protowire.EncodeBool(value.Bool()))
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value.Int() != 0 || element {
+			b = protowire.AppendTag(b, number, protowire.VarintType)
+			b = protowire.AppendVarint(b, uint64(value.Int()))
+		}
+	case reflect.Float32, reflect.Float64:
+		if value.Float() != 0 || element {
+			b = protowire.AppendTag(b, number, protowire.Fixed64Type)
+			b = protowire.AppendFixed64(b, math.Float64bits(value.Float()))
+		}
+	case reflect.Pointer:
+		if !value.IsNil() {
+			return appendProtoValue(b, number, value.Elem(), element)
+		}
+	case reflect.Struct:
+		message, err := appendProtoMessage(nil, value)
+		if err != nil {
+			return nil, err
+		}
+		b = protowire.AppendTag(b, number, protowire.BytesType)
+		b = protowire.AppendBytes(b, message)
+	case reflect.Interface:
+		encoded, err := json.Marshal(value.Interface())
+		if err != nil {
+			return nil, err
+		}
+		b = protowire.AppendTag(b, number, protowire.BytesType)
+		b = protowire.AppendBytes(b, encoded)
+	default:
+		return nil, fmt.Errorf("unsupported type %s", value.Type())
+	}
+	return b, nil
+}
+
+// unmarshalProto decodes the proto format into the struct message points to, skipping unknown fields
+func unmarshalProto(data []byte, message interface{}) error {
+	return consumeProtoMessage(data, reflect.ValueOf(message).Elem())
+}
+
+func consumeProtoMessage(data []byte, message reflect.Value) error {
+	messageFields, err := protoFields(message.Type())
+	if err != nil {
+		return err
+	}
+	fields := make(map[protowire.Number]protoField)
+	for _, field := range messageFields {
+		fields[protowire.Number(field.number)] = field
+	}
+
+	for len(data) > 0 {
+		number, wireType, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+		field, known := fields[number]
+		if !known {
+			n = protowire.ConsumeFieldValue(number, wireType, data)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			data = data[n:]
+			continue
+		}
+
+		n, err := consumeProtoField(data, wireType, message.Field(field.index))
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", protoMessageName(message.Type()), field.name, err)
+		}
+		data = data[n:]
+	}
+	return nil
+}
+
+// consumeProtoField decodes one record of a field, appending to repeated fields and adding to maps
+func consumeProtoField(data []byte, wireType protowire.Type, value reflect.Value) (int, error) {
+	switch value.Kind() {
+	case reflect.Slice:
+		element := reflect.New(value.Type().Elem()).Elem()
+		n, err := consumeProtoValue(data, wireType, element)
+		if err != nil {
+			return 0, err
+		}
+		value.Set(reflect.Append(value, element))
+		return n, nil
+	case reflect.Map:
+		entry, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return 0, protowire.ParseError(n)
+		}
+		key := reflect.New(value.Type().Key()).Elem()
+		element := reflect.New(value.Type().Elem()).Elem()
+		for len(entry) > 0 {
+			number, entryType, m := protowire.ConsumeTag(entry)
+			if m < 0 {
+				return 0, protowire.ParseError(m)
+			}
+			entry = entry[m:]
+			switch number {
+			case 1:
+				m, err := consumeProtoValue(entry, entryType, key)
+				if err != nil {
+					return 0, err
+				}
+				entry = entry[m:]
+			case 2:
+				m, err := consumeProtoValue(entry, entryType, element)
+				if err != nil {
+					return 0, err
+				}
+				entry = entry[m:]
+			default:
+				if m = protowire.ConsumeFieldValue(number, entryType, entry); m < 0 {
+					return 0, protowire.ParseError(m)
+				}
+				entry = entry[m:]
+			}
+		}
+		if value.IsNil() {
+			value.Set(reflect.MakeMap(value.Type()))
+		}
+		value.SetMapIndex(key, element)
+		return n, nil
+	}
+	return consumeProtoValue(data, wireType, value)
+}
+
+func consumeProtoValue(data []byte, wireType protowire.Type, value reflect.Value) (int, error) {
+	switch value.Kind() {
+	case reflect.String, reflect.Struct, reflect.Interface:
+		if wireType != protowire.BytesType {
+			return 0, fmt.Errorf("wire type %d of a length-delimited field", wireType)
+		}
+	}
+	switch value.Kind() {
+	case reflect.String:
+		s, n := protowire.ConsumeString(data)
+		if n < 0 {
+			return 0, protowire.ParseError(n)
+		}
+		value.SetString(s)
+		return n, nil
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if wireType != protowire.VarintType {
+			return 0, fmt.Errorf("wire type %d of a varint field", wireType)
+		}
+		v, n := protowire.ConsumeVarint(data)
+		if n < 0 {
+			return 0, protowire.ParseError(n)
+		}
+		if value.Kind() == reflect.Bool {
+			value.SetBool(protowire.DecodeBool(v))
+		} else {
+			value.SetInt(int64(v))
+		}
+		return n, nil
+	case reflect.Float32, reflect.Float64:
+		if wireType != protowire.Fixed64Type {
+			return 0, fmt.Errorf("wire type %d of a double field", wireType)
+		}
+		v, n := protowire.ConsumeFixed64(data)
+		if n < 0 {
+			return 0, protowire.ParseError(n)
+		}
+		value.SetFloat(math.Float64frombits(v))
+		return n, nil
+	case reflect.Pointer:
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		return consumeProtoValue(data, wireType, value.Elem())
+	case reflect.Struct:
+		message, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return 0, protowire.ParseError(n)
+		}
+		return n, consumeProtoMessage(message, value)
+	case reflect.Interface:
+		encoded, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return 0, protowire.ParseError(n)
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			return 0, err
+		}
+		if decoded != nil {
+			value.Set(reflect.ValueOf(decoded))
+		}
+		return n, nil
+	}
+	return 0, fmt.Errorf("unsupported type %s", value.Type())
+}
+
+// generateProtoDefinition generates the proto3 definition of the messages of the root struct type and the struct
+// types it refers to, in the order they are first referred to
+func generateProtoDefinition(root reflect.Type) (string, error) {
+	var definition strings.Builder
+	definition.WriteString("// Code generated by go generate ./pkg, DO NOT EDIT.\n")
+	definition.WriteString("// Field numbers are the protobuf tags of the Go fields, they never change across versions.\n\n")
+	definition.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&definition, "package %s;\n", protoPackage)
+
+	queue := []reflect.Type{root}
+	seen := map[reflect.Type]bool{root: true}
+	for len(queue) > 0 {
+		message := queue[0]
+		queue = queue[1:]
+
+		fmt.Fprintf(&definition, "\nmessage %s {\n", protoMessageName(message))
+		fields, err := protoFields(message)
+		if err != nil {
+			return "", err
+		}
+		for _, field := range fields {
+			fieldType, references, err := protoFieldType(message.Field(field.index).Type)
+			if err != nil {
+				return "", fmt.Errorf("%s.%s: %w", message.Name(), field.name, err)
+			}
+			comment := ""
+			if holdsInterface(message.Field(field.index).Type) {
+				comment = " // JSON encoded"
+			}
+			fmt.Fprintf(&definition, "  %s %s = %d;%s\n", fieldType, field.name, field.number, comment)
+			for _, reference := range references {
+				if !seen[reference] {
+					seen[reference] = true
+					queue = append(queue, reference)
+				}
+			}
+		}
+		definition.WriteString("}\n")
+	}
+	return definition.String(), nil
+}
+
+// holdsInterface reports whether a field holds interface{} values, directly or as elements of a slice or map
+func holdsInterface(t reflect.Type) bool {
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Interface
+}
+
+// protoFieldType returns the proto type of a Go field type and the struct types it refers to
+func protoFieldType(t reflect.Type) (string, []reflect.Type, error) {
+	switch t.Kind() {
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Slice || t.Elem().Kind() == reflect.Map {
+			return "", nil, fmt.Errorf("unsupported nested type %s", t)
+		}
+		elementType, references, err := protoScalarType(t.Elem())
+		return "repeated " + elementType, references, err
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return "", nil, fmt.Errorf("unsupported map key type %s", t.Key())
+		}
+		if t.Elem().Kind() == reflect.Slice || t.Elem().Kind() == reflect.Map {
+			return "", nil, fmt.Errorf("unsupported nested type %s", t)
+		}
+		valueType, references, err := protoScalarType(t.Elem())
+		return "map<string, " + valueType + ">", references, err
+	}
+	return protoScalarType(t)
+}
+
+func protoScalarType(t reflect.Type) (string, []reflect.Type, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return "string", nil, nil
+	case reflect.Bool:
+		return "bool", nil, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int64", nil, nil
+	case reflect.Float32, reflect.Float64:
+		return "double", nil, nil
+	case reflect.Interface:
+		return "string", nil, nil
+	case reflect.Pointer:
+		return protoScalarType(t.Elem())
+	case reflect.Struct:
+		return protoMessageName(t), []reflect.Type{t}, nil
+	}
+	return "", nil, fmt.Errorf("unsupported type %s", t)
+}
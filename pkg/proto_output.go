@@ -0,0 +1,83 @@
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+//go:generate go test -run TestProtoDefinition_UpToDate -update-schemas .
+
+// ProtoFileName is the file the proto output format writes, an Index message of schemas/terraform_provider_index.proto
+const ProtoFileName = "index.pb"
+
+// ProtoIndex is the content of the proto format file, the whole index in a single message for consumers that find
+// reading every JSON document too slow. Fields of the encoded types carry their proto field number in a protobuf tag,
+// new fields take an unused number.
+type ProtoIndex struct {
+	Version     string                `json:"version" protobuf:"1"`      // "v4.20.0"
+	Statistics  ProviderStatistics    `json:"statistics" protobuf:"2"`   // Provider totals and per-service counts
+	Toolchain   ToolchainInfo         `json:"toolchain" protobuf:"3"`    // Go environment used for parsing
+	Resources   []TerraformResource   `json:"resources" protobuf:"4"`    // Ordered by Terraform type
+	DataSources []TerraformDataSource `json:"data_sources" protobuf:"5"` // Ordered by Terraform type
+	Ephemeral   []TerraformEphemeral  `json:"ephemeral" protobuf:"6"`    // Ordered by Terraform type
+	// Framework list resources and actions, ordered by Terraform type
+	ListResources []TerraformListResource `json:"list_resources" protobuf:"7"`
+	Actions       []TerraformAction       `json:"actions" protobuf:"8"`
+}
+
+// BuildProtoIndex collects the documents of the index into a ProtoIndex
+func (index *TerraformProviderIndex) BuildProtoIndex() ProtoIndex {
+	protoIndex := ProtoIndex{Version: index.Version, Statistics: index.Statistics, Toolchain: index.Toolchain}
+	for _, document := range index.Documents() {
+		switch content := document.Content.(type) {
+		case TerraformResource:
+			protoIndex.Resources = append(protoIndex.Resources, content)
+		case TerraformDataSource:
+			protoIndex.DataSources = append(protoIndex.DataSources, content)
+		case TerraformEphemeral:
+			protoIndex.Ephemeral = append(protoIndex.Ephemeral, content)
+		case TerraformListResource:
+			protoIndex.ListResources = append(protoIndex.ListResources, content)
+		case TerraformAction:
+			protoIndex.Actions = append(protoIndex.Actions, content)
+		}
+	}
+	return protoIndex
+}
+
+// writeProtoFile writes the index as a single Index message in the Protocol Buffers binary format
+func (index *TerraformProviderIndex) writeProtoFile(outputDir string, progressCallback ProgressCallback) error {
+	progressTracker := NewProgressTracker("encoding", 1, progressCallback)
+	if err := index.Output.fs().MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", outputDir, err)
+	}
+
+	content, err := marshalProto(index.BuildProtoIndex())
+	if err != nil {
+		return fmt.Errorf("failed to encode proto index: %w", err)
+	}
+	filePath := filepath.Join(outputDir, ProtoFileName)
+	if err := afero.WriteFile(index.Output.fs(), filePath, content, 0644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", filePath, err)
+	}
+
+	progressTracker.UpdateProgress(ProtoFileName)
+	progressTracker.Complete()
+	return nil
+}
+
+// ReadProtoFile decodes a file written by the proto output format
+func ReadProtoFile(filePath string) (*ProtoIndex, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read proto index %s: %w", filePath, err)
+	}
+	protoIndex := &ProtoIndex{}
+	if err := unmarshalProto(content, protoIndex); err != nil {
+		return nil, fmt.Errorf("failed to decode proto index %s: %w", filePath, err)
+	}
+	return protoIndex, nil
+}
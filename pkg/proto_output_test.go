@@ -0,0 +1,129 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const protoDefinitionFile = "schemas/terraform_provider_index.proto"
+
+func TestProtoDefinition_UpToDate(t *testing.T) {
+	generated, err := generateProtoDefinition(reflect.TypeOf(ProtoIndex{}))
+	require.NoError(t, err)
+	if *updateSchemas {
+		require.NoError(t, os.WriteFile(protoDefinitionFile, []byte(generated), 0644))
+		return
+	}
+	checkedIn, err := os.ReadFile(protoDefinitionFile)
+	require.NoError(t, err)
+	assert.Equal(t, generated, string(checkedIn), "%s is outdated, run go generate ./pkg", protoDefinitionFile)
+}
+
+func TestTerraformProviderIndex_WriteProtoFile(t *testing.T) {
+	index, err := ScanTerraformProviderServices(filepath.Join("testharness", "internal", "services"), "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+	require.NoError(t, err)
+	index.ApplyAnnotations(Annotations{"azurerm_key_vault": {"cost_tier": "high", "owners": []interface{}{"team-a"}}})
+	index.Output.Format = OutputFormatProto
+
+	outputDir := t.TempDir()
+	require.NoError(t, index.WriteIndexFiles(outputDir, nil))
+
+	decoded, err := ReadProtoFile(filepath.Join(outputDir, ProtoFileName))
+	require.NoError(t, err)
+	expected := index.BuildProtoIndex()
+	assert.NotEmpty(t, expected.Resources)
+	// Empty and nil slices encode the same way
+	expected.Toolchain.BuildTags = nil
+	assert.Equal(t, expected, *decoded)
+}
+
+func TestUnmarshalProto_SkipsUnknownFields(t *testing.T) {
+	data := protowire.AppendTag(nil, 99, protowire.BytesType)
+	data = protowire.AppendString(data, "added by a later version")
+	data = protowire.AppendTag(data, 1, protowire.BytesType)
+	data = protowire.AppendString(data, "v4.20.0")
+
+	var decoded ProtoIndex
+	require.NoError(t, unmarshalProto(data, &decoded))
+	assert.Equal(t, "v4.20.0", decoded.Version)
+
+	assert.Error(t, unmarshalProto([]byte{0x0a, 0x05, 'v'}, &decoded))
+}
+
+// releasedProtoFieldNumbers are the field numbers of the released proto format, consumers decode index.pb with them
+var releasedProtoFieldNumbers = map[string]map[string]int{
+	"Index":                 {"version": 1, "statistics": 2, "toolchain": 3, "resources": 4, "data_sources": 5, "ephemeral": 6, "list_resources": 7, "actions": 8},
+	"ProviderStatistics":    {"service_count": 1, "total_data_sources": 2, "total_resources": 3, "legacy_resources": 4, "modern_resources": 5, "ephemeral_resources": 6, "deprecated_resources": 7, "terraform_type_strategies": 8, "services": 9, "list_resources": 10, "actions": 11},
+	"ToolchainInfo":         {"go_version": 1, "goos": 2, "goarch": 3, "build_tags": 4},
+	"TerraformResource":     {"id": 1, "terraform_type": 2, "struct_type": 3, "namespace": 4, "registration_method": 5, "sdk_type": 6, "schema_index": 7, "create_index": 8, "read_index": 9, "update_index": 10, "delete_index": 11, "attribute_index": 12, "terraform_type_strategy": 13, "schema_version": 14, "state_upgraders": 15, "customize_diff": 16, "azure_resource_type": 17, "id_parser": 18, "sdk_packages": 19, "api_operations": 20, "timeouts": 21, "capabilities": 22, "documentation": 23, "schema": 24, "deprecated": 25, "deprecation_message": 26, "conditional": 27, "display_name": 28, "website_categories": 29, "github_label": 30, "x_annotations": 31, "immutable": 32, "feature_flag": 33, "write_only_attributes": 34, "doc": 35, "source_file": 36, "line": 37, "crud_sources": 38, "model": 39, "api_versions": 40, "common_ids": 41, "force_new_attributes": 42, "constraints": 43, "sensitive_attributes": 44},
+	"TerraformDataSource":   {"id": 1, "terraform_type": 2, "struct_type": 3, "namespace": 4, "registration_method": 5, "sdk_type": 6, "schema_index": 7, "read_index": 8, "attribute_index": 9, "terraform_type_strategy": 10, "deprecated": 11, "deprecation_message": 12, "documentation": 13, "conditional": 14, "display_name": 15, "website_categories": 16, "github_label": 17, "x_annotations": 18, "feature_flag": 19, "schema": 20, "doc": 21, "source_file": 22, "line": 23, "crud_sources": 24, "model": 25},
+	"TerraformEphemeral":    {"id": 1, "terraform_type": 2, "struct_type": 3, "namespace": 4, "registration_method": 5, "sdk_type": 6, "schema_index": 7, "open_index": 8, "renew_index": 9, "close_index": 10, "terraform_type_strategy": 11, "display_name": 12, "website_categories": 13, "github_label": 14, "x_annotations": 15, "constructor_function": 16, "feature_flag": 17},
+	"TerraformListResource": {"id": 1, "terraform_type": 2, "struct_type": 3, "namespace": 4, "registration_method": 5, "sdk_type": 6, "schema_index": 7, "list_index": 8, "terraform_type_strategy": 9, "display_name": 10, "website_categories": 11, "github_label": 12, "x_annotations": 13, "constructor_function": 14},
+	"TerraformAction":       {"id": 1, "terraform_type": 2, "struct_type": 3, "namespace": 4, "registration_method": 5, "sdk_type": 6, "schema_index": 7, "invoke_index": 8, "terraform_type_strategy": 9, "display_name": 10, "website_categories": 11, "github_label": 12, "x_annotations": 13, "constructor_function": 14},
+	"ServiceStatistics":     {"legacy_resources": 1, "modern_resources": 2, "data_sources": 3, "legacy_data_sources": 4, "modern_data_sources": 5, "ephemeral_resources": 6, "deprecated_resources": 7, "list_resources": 8, "actions": 9},
+	"APIOperation":          {"sdk_package": 1, "operation": 2, "http_method": 3, "path": 4},
+	"DocumentationLink":     {"doc_file": 1, "registry_slug": 2, "registry_url": 3},
+	"SchemaAttribute":       {"name": 1, "type": 2, "schema_func": 3, "validate_funcs": 4, "required": 5, "optional": 6, "computed": 7, "deprecated": 8, "depth": 9, "block": 10, "write_only": 11, "default": 12, "default_func": 13, "force_new": 14, "constraints": 15, "sensitive": 16},
+	"GoDoc":                 {"registration": 1, "struct": 2, "create": 3, "read": 4, "update": 5, "delete": 6},
+	"SourceLocation":        {"source_file": 1, "line": 2},
+	"ResourceModel":         {"struct_type": 1, "fields": 2},
+	"AttributeConstraint":   {"attribute": 1, "kind": 2, "attributes": 3},
+	"SchemaConstraint":      {"kind": 1, "attributes": 2},
+	"ModelField":            {"field": 1, "type": 2, "attribute": 3, "options": 4, "model": 5},
+}
+
+func TestProtoFields_ReleasedNumbersAreStable(t *testing.T) {
+	messages := make(map[string]map[string]int)
+	queue := []reflect.Type{reflect.TypeOf(ProtoIndex{})}
+	for len(queue) > 0 {
+		message := queue[0]
+		queue = queue[1:]
+		if _, seen := messages[protoMessageName(message)]; seen {
+			continue
+		}
+		fields, err := protoFields(message)
+		require.NoError(t, err)
+		numbers := make(map[string]int)
+		for _, field := range fields {
+			numbers[field.name] = field.number
+			_, references, err := protoFieldType(message.Field(field.index).Type)
+			require.NoError(t, err)
+			queue = append(queue, references...)
+		}
+		messages[protoMessageName(message)] = numbers
+	}
+
+	for message, released := range releasedProtoFieldNumbers {
+		require.Contains(t, messages, message)
+		for field, number := range released {
+			assert.Equal(t, number, messages[message][field], "field number of %s.%s", message, field)
+		}
+	}
+}
+
+func TestProtoFields_Errors(t *testing.T) {
+	_, err := protoFields(reflect.TypeOf(struct {
+		Name string `json:"name"`
+	}{}))
+	assert.ErrorContains(t, err, "Name has no valid protobuf field number tag")
+
+	_, err = protoFields(reflect.TypeOf(struct {
+		Name  string `json:"name" protobuf:"1"`
+		Title string `json:"title" protobuf:"1"`
+	}{}))
+	assert.ErrorContains(t, err, "share the protobuf field number 1")
+
+	fields, err := protoFields(reflect.TypeOf(struct {
+		Title string `json:"title" protobuf:"2"`
+		Name  string `json:"name" protobuf:"1"`
+		Skip  string `json:"-"`
+	}{}))
+	require.NoError(t, err)
+	assert.Equal(t, []protoField{{index: 0, number: 2, name: "title"}, {index: 1, number: 1, name: "name"}}, fields, "numbers don't depend on the field order")
+}
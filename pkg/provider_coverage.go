@@ -0,0 +1,170 @@
+package pkg
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// providerServiceLists are the functions of the provider package returning the registrations of the typed and
+// untyped services the provider serves
+const (
+	typedServicesFunc   = "SupportedTypedServices"
+	untypedServicesFunc = "SupportedUntypedServices"
+)
+
+// ProviderCoverageReport compares the services the provider registers with the services of the index, written to
+// audit/provider-coverage.json
+type ProviderCoverageReport struct {
+	ProviderPath     string   `json:"provider_path"`      // "internal/provider"
+	Typed            []string `json:"typed"`              // Sorted services registered by SupportedTypedServices, ["keyvault"]
+	Untyped          []string `json:"untyped"`            // Sorted services registered by SupportedUntypedServices, ["keyvault"]
+	MissingFromIndex []string `json:"missing_from_index"` // Sorted services registered by the provider but not in the index
+	NotRegistered    []string `json:"not_registered"`     // Sorted services of the index the provider doesn't register
+}
+
+// CheckProviderCoverage reads the service registrations returned by SupportedTypedServices and
+// SupportedUntypedServices in the provider package at providerPath, such as internal/provider, and compares them with
+// the services of the index, so an incomplete scan is noticed. Registrations are found in the functions these call
+// within the package too. The report is also written to audit/provider-coverage.json.
+func (index *TerraformProviderIndex) CheckProviderCoverage(providerPath string) (*ProviderCoverageReport, error) {
+	if info, err := os.Stat(providerPath); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("provider path %s is not a directory", providerPath)
+	}
+	funcs, err := parseProviderFuncs(providerPath)
+	if err != nil {
+		return nil, err
+	}
+	if funcs[typedServicesFunc] == nil && funcs[untypedServicesFunc] == nil {
+		return nil, fmt.Errorf("neither %s nor %s found in %s", typedServicesFunc, untypedServicesFunc, providerPath)
+	}
+
+	report := &ProviderCoverageReport{
+		ProviderPath: filepath.ToSlash(providerPath),
+		Typed:        registeredServices(funcs, typedServicesFunc),
+		Untyped:      registeredServices(funcs, untypedServicesFunc),
+	}
+	index.compareProviderCoverage(report)
+	index.ProviderCoverage = report
+	return report, nil
+}
+
+// compareProviderCoverage fills the services missing from either side of a report
+func (index *TerraformProviderIndex) compareProviderCoverage(report *ProviderCoverageReport) {
+	registered := make(map[string]bool)
+	for _, service := range append(append([]string(nil), report.Typed...), report.Untyped...) {
+		registered[service] = true
+	}
+	indexed := make(map[string]bool)
+	report.NotRegistered = []string{}
+	for _, service := range index.Services {
+		indexed[service.ServiceName] = true
+		if !registered[service.ServiceName] {
+			report.NotRegistered = append(report.NotRegistered, service.ServiceName)
+		}
+	}
+	report.MissingFromIndex = []string{}
+	for service := range registered {
+		if !indexed[service] {
+			report.MissingFromIndex = append(report.MissingFromIndex, service)
+		}
+	}
+	sort.Strings(report.NotRegistered)
+	sort.Strings(report.MissingFromIndex)
+}
+
+// providerFunc is a package level function of the provider package with the imports of its file
+type providerFunc struct {
+	decl    *ast.FuncDecl
+	imports map[string]string // Import alias -> import path
+}
+
+// parseProviderFuncs parses the package level functions of the non-test files of the provider package, keyed by name
+func parseProviderFuncs(providerPath string) (map[string]*providerFunc, error) {
+	files, err := filepath.Glob(filepath.Join(providerPath, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	funcs := make(map[string]*providerFunc)
+	for _, fileName := range files {
+		if strings.HasSuffix(fileName, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), fileName, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", fileName, err)
+		}
+		imports := make(map[string]string)
+		for _, spec := range file.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				continue
+			}
+			alias := path.Base(importPath)
+			if spec.Name != nil {
+				alias = spec.Name.Name
+			}
+			imports[alias] = importPath
+		}
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Body != nil {
+				funcs[fn.Name.Name] = &providerFunc{decl: fn, imports: imports}
+			}
+		}
+	}
+	return funcs, nil
+}
+
+// registeredServices returns the sorted services whose registration, such as keyvault.Registration{}, is built by
+// the function root or the package level functions it calls. The service is the last element of the import path of
+// the registration's package, the name of its directory under internal/services.
+func registeredServices(funcs map[string]*providerFunc, root string) []string {
+	services := []string{}
+	seen := make(map[string]bool)
+	visited := make(map[string]bool)
+	var visit func(name string)
+	visit = func(name string) {
+		fn := funcs[name]
+		if fn == nil || visited[name] {
+			return
+		}
+		visited[name] = true
+		ast.Inspect(fn.decl.Body, func(n ast.Node) bool {
+			switch e := n.(type) {
+			case *ast.CompositeLit:
+				selector, ok := e.Type.(*ast.SelectorExpr)
+				if !ok || selector.Sel.Name != "Registration" {
+					return true
+				}
+				pkgIdent, ok := selector.X.(*ast.Ident)
+				if !ok {
+					return true
+				}
+				if importPath, exists := fn.imports[pkgIdent.Name]; exists && !seen[path.Base(importPath)] {
+					seen[path.Base(importPath)] = true
+					services = append(services, path.Base(importPath))
+				}
+			case *ast.CallExpr:
+				if ident, ok := e.Fun.(*ast.Ident); ok {
+					visit(ident.Name)
+				}
+			}
+			return true
+		})
+	}
+	visit(root)
+	sort.Strings(services)
+	return services
+}
+
+// writeProviderCoverageReportFile writes audit/provider-coverage.json
+func (index *TerraformProviderIndex) writeProviderCoverageReportFile(outputDir string) error {
+	return index.writeJSONFile(filepath.Join(outputDir, "audit", "provider-coverage.json"), index.ProviderCoverage)
+}
@@ -0,0 +1,126 @@
+package pkg
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testProviderServices = `package provider
+
+import (
+	"github.com/hashicorp/terraform-provider-azurerm/internal/sdk"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/services/compute"
+	kv "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/services/resource"
+)
+
+func SupportedTypedServices() []sdk.TypedServiceRegistration {
+	services := []sdk.TypedServiceRegistration{
+		compute.Registration{},
+		kv.Registration{},
+	}
+	services = append(services, autoRegisteredTypedServices()...)
+	return services
+}
+
+func SupportedUntypedServices() []sdk.UntypedServiceRegistration {
+	return func() []sdk.UntypedServiceRegistration {
+		out := []sdk.UntypedServiceRegistration{
+			kv.Registration{},
+			resource.Registration{},
+		}
+		return out
+	}()
+}
+`
+
+const testProviderAutoRegistered = `package provider
+
+import (
+	"github.com/hashicorp/terraform-provider-azurerm/internal/sdk"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/services/network"
+)
+
+func autoRegisteredTypedServices() []sdk.TypedServiceRegistration {
+	return []sdk.TypedServiceRegistration{
+		network.Registration{},
+	}
+}
+`
+
+func writeTestProviderPackage(t *testing.T) string {
+	providerPath := t.TempDir()
+	for fileName, content := range map[string]string{
+		"services.go":                testProviderServices,
+		"services_autoregistered.go": testProviderAutoRegistered,
+		"services_test.go":           "package provider\n\nfunc SupportedTypedServices() {}\n",
+		"provider.go":                "package provider\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(providerPath, fileName), []byte(content), 0644))
+	}
+	return providerPath
+}
+
+func newProviderCoverageTestIndex() *TerraformProviderIndex {
+	return &TerraformProviderIndex{
+		Services: []ServiceRegistration{
+			{ServiceName: "compute"},
+			{ServiceName: "keyvault"},
+			{ServiceName: "legacy"},
+			{ServiceName: "resource"},
+		},
+	}
+}
+
+func TestTerraformProviderIndex_CheckProviderCoverage(t *testing.T) {
+	providerPath := writeTestProviderPackage(t)
+	index := newProviderCoverageTestIndex()
+
+	report, err := index.CheckProviderCoverage(providerPath)
+	require.NoError(t, err)
+
+	assert.Same(t, report, index.ProviderCoverage)
+	assert.Equal(t, &ProviderCoverageReport{
+		ProviderPath:     filepath.ToSlash(providerPath),
+		Typed:            []string{"compute", "keyvault", "network"},
+		Untyped:          []string{"keyvault", "resource"},
+		MissingFromIndex: []string{"network"},
+		NotRegistered:    []string{"legacy"},
+	}, report)
+}
+
+func TestTerraformProviderIndex_CheckProviderCoverage_NoServiceLists(t *testing.T) {
+	providerPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(providerPath, "provider.go"), []byte("package provider\n"), 0644))
+
+	_, err := newProviderCoverageTestIndex().CheckProviderCoverage(providerPath)
+	assert.ErrorContains(t, err, "neither SupportedTypedServices nor SupportedUntypedServices found")
+}
+
+func TestTerraformProviderIndex_CheckProviderCoverage_NotADirectory(t *testing.T) {
+	_, err := newProviderCoverageTestIndex().CheckProviderCoverage(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorContains(t, err, "is not a directory")
+}
+
+func TestTerraformProviderIndex_WriteProviderCoverageReportFile(t *testing.T) {
+	providerPath := writeTestProviderPackage(t)
+	index := newProviderCoverageTestIndex()
+	_, err := index.CheckProviderCoverage(providerPath)
+	require.NoError(t, err)
+
+	fs := afero.NewMemMapFs()
+	index.Output.Fs = fs
+	require.NoError(t, index.writeProviderCoverageReportFile("/index"))
+
+	data, err := afero.ReadFile(fs, filepath.Join("/index", "audit", "provider-coverage.json"))
+	require.NoError(t, err)
+	var report ProviderCoverageReport
+	require.NoError(t, json.Unmarshal(data, &report))
+	assert.Equal(t, *index.ProviderCoverage, report)
+}
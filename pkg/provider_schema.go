@@ -0,0 +1,147 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ProviderSchemaReport compares the Terraform types of the compiled provider schema, as printed by
+// `terraform providers schema -json`, with the types of the index, written to audit/provider-schema.json
+type ProviderSchemaReport struct {
+	SchemaFile  string               `json:"schema_file"` // "schema.json"
+	Provider    string               `json:"provider"`    // "registry.terraform.io/hashicorp/azurerm"
+	Resources   SchemaReconciliation `json:"resources"`
+	DataSources SchemaReconciliation `json:"data_sources"`
+	Ephemeral   SchemaReconciliation `json:"ephemeral"`
+}
+
+// SchemaReconciliation lists the Terraform types of one kind found on a single side of the comparison
+type SchemaReconciliation struct {
+	MissingFromIndex  []string `json:"missing_from_index"`  // Sorted types of the provider schema the index lacks, registrations the parser didn't understand
+	MissingFromSchema []string `json:"missing_from_schema"` // Sorted types of the index the provider schema lacks, such as unresolved struct types
+}
+
+// Missing returns the number of Terraform types found on a single side of the comparison, across all kinds
+func (r *ProviderSchemaReport) Missing() int {
+	missing := 0
+	for _, reconciliation := range []SchemaReconciliation{r.Resources, r.DataSources, r.Ephemeral} {
+		missing += len(reconciliation.MissingFromIndex) + len(reconciliation.MissingFromSchema)
+	}
+	return missing
+}
+
+// terraformProviderSchemas is the part of the `terraform providers schema -json` output the reconciliation reads
+type terraformProviderSchemas struct {
+	ProviderSchemas map[string]struct {
+		ResourceSchemas          map[string]json.RawMessage `json:"resource_schemas"`
+		DataSourceSchemas        map[string]json.RawMessage `json:"data_source_schemas"`
+		EphemeralResourceSchemas map[string]json.RawMessage `json:"ephemeral_resource_schemas"`
+	} `json:"provider_schemas"`
+}
+
+// ReconcileProviderSchema compares the resources, data sources and ephemeral resources of the provider schema in
+// schemaFile, the output of `terraform providers schema -json` for a configuration using the provider, with the
+// Terraform types of the index. Types only the compiled provider knows point to registration patterns the parser
+// doesn't understand yet. The provider is picked by the provider name of the output settings when the schema holds
+// several. The report is also written to audit/provider-schema.json.
+func (index *TerraformProviderIndex) ReconcileProviderSchema(schemaFile string) (*ProviderSchemaReport, error) {
+	data, err := os.ReadFile(schemaFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read provider schema %s: %w", schemaFile, err)
+	}
+	var schemas terraformProviderSchemas
+	if err := json.Unmarshal(data, &schemas); err != nil {
+		return nil, fmt.Errorf("failed to parse provider schema %s: %w", schemaFile, err)
+	}
+
+	providerName := index.Output.ProviderName
+	if providerName == "" {
+		providerName = DefaultProviderName
+	}
+	var address string
+	for candidate := range schemas.ProviderSchemas {
+		if candidate == providerName || strings.HasSuffix(candidate, "/"+providerName) {
+			address = candidate
+		}
+	}
+	if address == "" {
+		return nil, fmt.Errorf("provider %s not found in provider schema %s", providerName, schemaFile)
+	}
+	schema := schemas.ProviderSchemas[address]
+
+	mappings := index.BuildGlobalMappings()
+	report := &ProviderSchemaReport{
+		SchemaFile:  filepath.ToSlash(schemaFile),
+		Provider:    address,
+		Resources:   reconcileTerraformTypes(schema.ResourceSchemas, mappings.AllResources),
+		DataSources: reconcileTerraformTypes(schema.DataSourceSchemas, mappings.AllDataSources),
+		Ephemeral:   reconcileTerraformTypes(schema.EphemeralResourceSchemas, mappings.AllEphemeral),
+	}
+	index.ProviderSchema = report
+	return report, nil
+}
+
+// reconcileTerraformTypes compares the types of one kind of the provider schema with the registrations of the index
+func reconcileTerraformTypes(schemaTypes map[string]json.RawMessage, indexed map[string]GlobalMappingEntry) SchemaReconciliation {
+	reconciliation := SchemaReconciliation{MissingFromIndex: []string{}, MissingFromSchema: []string{}}
+	for terraformType := range schemaTypes {
+		if _, ok := indexed[terraformType]; !ok {
+			reconciliation.MissingFromIndex = append(reconciliation.MissingFromIndex, terraformType)
+		}
+	}
+	for terraformType := range indexed {
+		if _, ok := schemaTypes[terraformType]; !ok {
+			reconciliation.MissingFromSchema = append(reconciliation.MissingFromSchema, terraformType)
+		}
+	}
+	sort.Strings(reconciliation.MissingFromIndex)
+	sort.Strings(reconciliation.MissingFromSchema)
+	return reconciliation
+}
+
+// mergeProviderSchemaReports combines the reports of shards reconciling the same provider schema with their own
+// services. A type of the schema is missing from the merged index when every shard lacks it, a type of a shard is
+// missing from the schema regardless of the other shards.
+func mergeProviderSchemaReports(reports []ProviderSchemaReport) *ProviderSchemaReport {
+	if len(reports) == 0 {
+		return nil
+	}
+	merged := &ProviderSchemaReport{SchemaFile: reports[0].SchemaFile, Provider: reports[0].Provider}
+	for _, kind := range []func(*ProviderSchemaReport) *SchemaReconciliation{
+		func(r *ProviderSchemaReport) *SchemaReconciliation { return &r.Resources },
+		func(r *ProviderSchemaReport) *SchemaReconciliation { return &r.DataSources },
+		func(r *ProviderSchemaReport) *SchemaReconciliation { return &r.Ephemeral },
+	} {
+		missingShards := make(map[string]int)
+		var missingFromSchema []string
+		for i := range reports {
+			reconciliation := kind(&reports[i])
+			for _, terraformType := range reconciliation.MissingFromIndex {
+				missingShards[terraformType]++
+			}
+			missingFromSchema = append(missingFromSchema, reconciliation.MissingFromSchema...)
+		}
+		result := kind(merged)
+		result.MissingFromIndex = []string{}
+		for terraformType, shards := range missingShards {
+			if shards == len(reports) {
+				result.MissingFromIndex = append(result.MissingFromIndex, terraformType)
+			}
+		}
+		sort.Strings(result.MissingFromIndex)
+		result.MissingFromSchema = uniqueSortedStrings(missingFromSchema)
+		if result.MissingFromSchema == nil {
+			result.MissingFromSchema = []string{}
+		}
+	}
+	return merged
+}
+
+// writeProviderSchemaReportFile writes audit/provider-schema.json
+func (index *TerraformProviderIndex) writeProviderSchemaReportFile(outputDir string) error {
+	return index.writeJSONFile(filepath.Join(outputDir, "audit", "provider-schema.json"), index.ProviderSchema)
+}
@@ -0,0 +1,120 @@
+package pkg
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testProviderSchema = `{
+  "format_version": "1.0",
+  "provider_schemas": {
+    "registry.terraform.io/hashicorp/random": {
+      "resource_schemas": {"random_string": {}}
+    },
+    "registry.terraform.io/hashicorp/azurerm": {
+      "provider": {"version": 0},
+      "resource_schemas": {
+        "azurerm_key_vault": {"version": 2},
+        "azurerm_key_vault_certificate": {"version": 0},
+        "azurerm_key_vault_new_pattern": {"version": 0}
+      },
+      "data_source_schemas": {
+        "azurerm_key_vault": {"version": 0}
+      }
+    }
+  }
+}`
+
+func writeTestProviderSchema(t *testing.T, content string) string {
+	schemaFile := filepath.Join(t.TempDir(), "schema.json")
+	require.NoError(t, os.WriteFile(schemaFile, []byte(content), 0644))
+	return schemaFile
+}
+
+func TestTerraformProviderIndex_ReconcileProviderSchema(t *testing.T) {
+	index := createTestTerraformProviderIndex()
+	schemaFile := writeTestProviderSchema(t, testProviderSchema)
+
+	report, err := index.ReconcileProviderSchema(schemaFile)
+
+	require.NoError(t, err)
+	assert.Same(t, report, index.ProviderSchema)
+	assert.Equal(t, filepath.ToSlash(schemaFile), report.SchemaFile)
+	assert.Equal(t, "registry.terraform.io/hashicorp/azurerm", report.Provider)
+	assert.Equal(t, []string{"azurerm_key_vault_new_pattern"}, report.Resources.MissingFromIndex)
+	assert.NotContains(t, report.Resources.MissingFromSchema, "azurerm_key_vault")
+	assert.NotContains(t, report.Resources.MissingFromSchema, "azurerm_key_vault_certificate")
+	assert.Empty(t, report.DataSources.MissingFromIndex)
+	assert.Equal(t, []string{}, report.Ephemeral.MissingFromIndex)
+
+	mappings := index.BuildGlobalMappings()
+	assert.Len(t, report.Resources.MissingFromSchema, len(mappings.AllResources)-2)
+	assert.Len(t, report.DataSources.MissingFromSchema, len(mappings.AllDataSources)-1)
+	assert.Len(t, report.Ephemeral.MissingFromSchema, len(mappings.AllEphemeral))
+	assert.Equal(t, 1+len(mappings.AllResources)-2+len(mappings.AllDataSources)-1+len(mappings.AllEphemeral), report.Missing())
+}
+
+func TestTerraformProviderIndex_ReconcileProviderSchema_Errors(t *testing.T) {
+	index := createTestTerraformProviderIndex()
+
+	_, err := index.ReconcileProviderSchema(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorContains(t, err, "failed to read provider schema")
+
+	_, err = index.ReconcileProviderSchema(writeTestProviderSchema(t, "not json"))
+	assert.ErrorContains(t, err, "failed to parse provider schema")
+
+	index.Output.ProviderName = "azapi"
+	_, err = index.ReconcileProviderSchema(writeTestProviderSchema(t, testProviderSchema))
+	assert.ErrorContains(t, err, "provider azapi not found")
+	assert.Nil(t, index.ProviderSchema)
+}
+
+func TestMergeProviderSchemaReports(t *testing.T) {
+	assert.Nil(t, mergeProviderSchemaReports(nil))
+
+	shard := func(missingFromIndex, missingFromSchema []string) ProviderSchemaReport {
+		return ProviderSchemaReport{
+			SchemaFile: "schema.json",
+			Provider:   "registry.terraform.io/hashicorp/azurerm",
+			Resources:  SchemaReconciliation{MissingFromIndex: missingFromIndex, MissingFromSchema: missingFromSchema},
+		}
+	}
+	merged := mergeProviderSchemaReports([]ProviderSchemaReport{
+		shard([]string{"azurerm_key_vault", "azurerm_unparsed"}, []string{"KeyVaultUnresolvedResource"}),
+		shard([]string{"azurerm_resource_group", "azurerm_unparsed"}, []string{}),
+	})
+
+	assert.Equal(t, &ProviderSchemaReport{
+		SchemaFile:  "schema.json",
+		Provider:    "registry.terraform.io/hashicorp/azurerm",
+		Resources:   SchemaReconciliation{MissingFromIndex: []string{"azurerm_unparsed"}, MissingFromSchema: []string{"KeyVaultUnresolvedResource"}},
+		DataSources: SchemaReconciliation{MissingFromIndex: []string{}, MissingFromSchema: []string{}},
+		Ephemeral:   SchemaReconciliation{MissingFromIndex: []string{}, MissingFromSchema: []string{}},
+	}, merged)
+}
+
+func TestTerraformProviderIndex_WriteIndexFiles_ProviderSchemaReport(t *testing.T) {
+	index := createTestTerraformProviderIndex()
+	_, err := index.ReconcileProviderSchema(writeTestProviderSchema(t, testProviderSchema))
+	require.NoError(t, err)
+
+	outputDir := t.TempDir()
+	require.NoError(t, index.WriteIndexFiles(outputDir, nil))
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "audit", "provider-schema.json"))
+	require.NoError(t, err)
+	var report ProviderSchemaReport
+	require.NoError(t, json.Unmarshal(data, &report))
+	assert.Equal(t, *index.ProviderSchema, report)
+
+	validation, err := ValidateIndexDir(outputDir, "")
+	require.NoError(t, err)
+	for _, problem := range validation.Problems {
+		assert.NotEqual(t, "audit/provider-schema.json", problem.File, problem.Message)
+	}
+}
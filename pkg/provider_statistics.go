@@ -1,11 +1,212 @@
 package pkg
 
+import "strings"
+
 // ProviderStatistics represents summary statistics for the provider
 type ProviderStatistics struct {
-	ServiceCount       int `json:"service_count"`
-	TotalDataSources   int `json:"total_data_sources"`
-	TotalResources     int `json:"total_resources"`
-	LegacyResources    int `json:"legacy_resources"`
-	ModernResources    int `json:"modern_resources"`
-	EphemeralResources int `json:"ephemeral_resources"`
+	ServiceCount        int `json:"service_count" protobuf:"1"`
+	TotalDataSources    int `json:"total_data_sources" protobuf:"2"`
+	TotalResources      int `json:"total_resources" protobuf:"3"`
+	LegacyResources     int `json:"legacy_resources" protobuf:"4"`
+	ModernResources     int `json:"modern_resources" protobuf:"5"`
+	EphemeralResources  int `json:"ephemeral_resources" protobuf:"6"`
+	DeprecatedResources int `json:"deprecated_resources" protobuf:"7"`
+	// Number of typed resources, data sources and ephemeral resources each Terraform type strategy resolved
+	TerraformTypeStrategies map[string]int `json:"terraform_type_strategies,omitempty" protobuf:"8"`
+	// Service name -> registration counts of the service
+	Services map[string]ServiceStatistics `json:"services,omitempty" protobuf:"9"`
+	// Framework list resources and actions, not part of TotalResources
+	ListResources int `json:"list_resources" protobuf:"10"`
+	Actions       int `json:"actions" protobuf:"11"`
+}
+
+// ServiceStatistics counts the registrations of one service
+type ServiceStatistics struct {
+	LegacyResources     int `json:"legacy_resources" protobuf:"1"`
+	ModernResources     int `json:"modern_resources" protobuf:"2"`
+	DataSources         int `json:"data_sources" protobuf:"3"`
+	LegacyDataSources   int `json:"legacy_data_sources" protobuf:"4"`
+	ModernDataSources   int `json:"modern_data_sources" protobuf:"5"`
+	EphemeralResources  int `json:"ephemeral_resources" protobuf:"6"`
+	DeprecatedResources int `json:"deprecated_resources" protobuf:"7"`
+	ListResources       int `json:"list_resources" protobuf:"8"`
+	Actions             int `json:"actions" protobuf:"9"`
+}
+
+// statisticsBuilder accumulates ProviderStatistics from registrations. Each category counts distinct registrations
+// of a service rather than increments, so adding the same registration twice, or in any order, gives the same
+// statistics. It isn't safe for concurrent use, registrations are added where scan results are collected.
+type statisticsBuilder struct {
+	services            map[string]bool
+	legacyResources     map[string]bool            // "keyvault/azurerm_key_vault"
+	modernResources     map[string]bool            // "keyvault/KeyVaultResource"
+	dataSources         map[string]bool            // "keyvault/azurerm_key_vault" or "keyvault/KeyVaultDataSource"
+	legacyDataSources   map[string]bool            // "keyvault/azurerm_key_vault"
+	ephemeralResources  map[string]bool            // "keyvault/NewKeyVaultSecretEphemeralResource"
+	deprecatedResources map[string]bool            // "keyvault/azurerm_key_vault"
+	listResources       map[string]bool            // "resource/ResourceGroupListResource"
+	actions             map[string]bool            // "compute/VirtualMachinePowerAction"
+	strategies          map[string]map[string]bool // "metadata" -> "keyvault/KeyVaultResource"
+}
+
+// newStatisticsBuilder creates an empty statisticsBuilder
+func newStatisticsBuilder() *statisticsBuilder {
+	return &statisticsBuilder{
+		services:            make(map[string]bool),
+		legacyResources:     make(map[string]bool),
+		modernResources:     make(map[string]bool),
+		dataSources:         make(map[string]bool),
+		legacyDataSources:   make(map[string]bool),
+		ephemeralResources:  make(map[string]bool),
+		deprecatedResources: make(map[string]bool),
+		listResources:       make(map[string]bool),
+		actions:             make(map[string]bool),
+		strategies:          make(map[string]map[string]bool),
+	}
+}
+
+// addService counts a service
+func (b *statisticsBuilder) addService(service string) {
+	b.services[service] = true
+}
+
+// addLegacyResource counts a resource registered in SupportedResources
+func (b *statisticsBuilder) addLegacyResource(service, terraformType string) {
+	b.legacyResources[service+"/"+terraformType] = true
+}
+
+// addModernResource counts a typed resource registered in Resources
+func (b *statisticsBuilder) addModernResource(service, structType string) {
+	b.modernResources[service+"/"+structType] = true
+}
+
+// addDataSource counts a data source, by Terraform type when registered in SupportedDataSources or by struct type
+// when registered in DataSources
+func (b *statisticsBuilder) addDataSource(service, name string) {
+	b.dataSources[service+"/"+name] = true
+}
+
+// addLegacyDataSource counts a data source registered in SupportedDataSources, both as a data source and as a legacy
+// one
+func (b *statisticsBuilder) addLegacyDataSource(service, terraformType string) {
+	b.addDataSource(service, terraformType)
+	b.legacyDataSources[service+"/"+terraformType] = true
+}
+
+// addEphemeralResource counts an ephemeral resource registered in EphemeralResources
+func (b *statisticsBuilder) addEphemeralResource(service, function string) {
+	b.ephemeralResources[service+"/"+function] = true
+}
+
+// addListResource counts a list resource registered in ListResources
+func (b *statisticsBuilder) addListResource(service, structType string) {
+	b.listResources[service+"/"+structType] = true
+}
+
+// addAction counts an action registered in Actions
+func (b *statisticsBuilder) addAction(service, structType string) {
+	b.actions[service+"/"+structType] = true
+}
+
+// addDeprecatedResource counts a deprecated resource
+func (b *statisticsBuilder) addDeprecatedResource(service, terraformType string) {
+	b.deprecatedResources[service+"/"+terraformType] = true
+}
+
+// addTerraformTypeStrategy counts a typed registration whose Terraform type the strategy resolved
+func (b *statisticsBuilder) addTerraformTypeStrategy(service, structType, strategy string) {
+	if b.strategies[strategy] == nil {
+		b.strategies[strategy] = make(map[string]bool)
+	}
+	b.strategies[strategy][service+"/"+structType] = true
+}
+
+// addServiceRegistration counts a service and all its registrations
+func (b *statisticsBuilder) addServiceRegistration(service ServiceRegistration) {
+	b.addService(service.ServiceName)
+	for terraformType := range service.SupportedResources {
+		b.addLegacyResource(service.ServiceName, terraformType)
+	}
+	for _, structType := range service.Resources {
+		b.addModernResource(service.ServiceName, structType)
+	}
+	for terraformType := range service.SupportedDataSources {
+		b.addLegacyDataSource(service.ServiceName, terraformType)
+	}
+	for _, structType := range service.DataSources {
+		b.addDataSource(service.ServiceName, structType)
+	}
+	for _, function := range service.EphemeralFunctions {
+		b.addEphemeralResource(service.ServiceName, function)
+	}
+	for _, structType := range service.ListResources {
+		b.addListResource(service.ServiceName, structType)
+	}
+	for _, structType := range service.Actions {
+		b.addAction(service.ServiceName, structType)
+	}
+	for terraformType := range service.ResourceDeprecations {
+		b.addDeprecatedResource(service.ServiceName, terraformType)
+	}
+	for structType, strategy := range service.TerraformTypeStrategies {
+		b.addTerraformTypeStrategy(service.ServiceName, structType, strategy)
+	}
+}
+
+// build returns the statistics of the registrations added so far
+func (b *statisticsBuilder) build() ProviderStatistics {
+	stats := ProviderStatistics{
+		ServiceCount:            len(b.services),
+		TotalDataSources:        len(b.dataSources),
+		LegacyResources:         len(b.legacyResources),
+		ModernResources:         len(b.modernResources),
+		EphemeralResources:      len(b.ephemeralResources),
+		DeprecatedResources:     len(b.deprecatedResources),
+		TerraformTypeStrategies: make(map[string]int),
+		ListResources:           len(b.listResources),
+		Actions:                 len(b.actions),
+	}
+	stats.TotalResources = stats.LegacyResources + stats.ModernResources + stats.EphemeralResources
+	for strategy, registrations := range b.strategies {
+		stats.TerraformTypeStrategies[strategy] = len(registrations)
+	}
+
+	stats.Services = make(map[string]ServiceStatistics)
+	for service := range b.services {
+		stats.Services[service] = ServiceStatistics{}
+	}
+	for _, category := range []struct {
+		registrations map[string]bool
+		count         func(serviceStats *ServiceStatistics)
+	}{
+		{b.legacyResources, func(s *ServiceStatistics) { s.LegacyResources++ }},
+		{b.modernResources, func(s *ServiceStatistics) { s.ModernResources++ }},
+		{b.dataSources, func(s *ServiceStatistics) { s.DataSources++ }},
+		{b.legacyDataSources, func(s *ServiceStatistics) { s.LegacyDataSources++ }},
+		{b.ephemeralResources, func(s *ServiceStatistics) { s.EphemeralResources++ }},
+		{b.deprecatedResources, func(s *ServiceStatistics) { s.DeprecatedResources++ }},
+		{b.listResources, func(s *ServiceStatistics) { s.ListResources++ }},
+		{b.actions, func(s *ServiceStatistics) { s.Actions++ }},
+	} {
+		for registration := range category.registrations {
+			service, _, _ := strings.Cut(registration, "/")
+			serviceStats := stats.Services[service]
+			category.count(&serviceStats)
+			stats.Services[service] = serviceStats
+		}
+	}
+	for service, serviceStats := range stats.Services {
+		serviceStats.ModernDataSources = serviceStats.DataSources - serviceStats.LegacyDataSources
+		stats.Services[service] = serviceStats
+	}
+	return stats
+}
+
+// buildProviderStatistics summarizes the registrations of the scanned services
+func buildProviderStatistics(services []ServiceRegistration) ProviderStatistics {
+	builder := newStatisticsBuilder()
+	for _, service := range services {
+		builder.addServiceRegistration(service)
+	}
+	return builder.build()
 }
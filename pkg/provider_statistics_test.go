@@ -0,0 +1,128 @@
+package pkg
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatisticsBuilder_Categories(t *testing.T) {
+	cases := []struct {
+		name     string
+		add      func(b *statisticsBuilder)
+		expected ProviderStatistics
+	}{
+		{
+			name:     "services",
+			add:      func(b *statisticsBuilder) { b.addService("keyvault"); b.addService("storage") },
+			expected: ProviderStatistics{ServiceCount: 2, Services: map[string]ServiceStatistics{"keyvault": {}, "storage": {}}},
+		},
+		{
+			name:     "legacy resources",
+			add:      func(b *statisticsBuilder) { b.addLegacyResource("keyvault", "azurerm_key_vault") },
+			expected: ProviderStatistics{LegacyResources: 1, TotalResources: 1, Services: map[string]ServiceStatistics{"keyvault": {LegacyResources: 1}}},
+		},
+		{
+			name:     "modern resources",
+			add:      func(b *statisticsBuilder) { b.addModernResource("storage", "AccountResource") },
+			expected: ProviderStatistics{ModernResources: 1, TotalResources: 1, Services: map[string]ServiceStatistics{"storage": {ModernResources: 1}}},
+		},
+		{
+			name: "data sources",
+			add: func(b *statisticsBuilder) {
+				b.addLegacyDataSource("keyvault", "azurerm_key_vault")
+				b.addDataSource("storage", "BlobDataSource")
+			},
+			expected: ProviderStatistics{TotalDataSources: 2, Services: map[string]ServiceStatistics{
+				"keyvault": {DataSources: 1, LegacyDataSources: 1},
+				"storage":  {DataSources: 1, ModernDataSources: 1},
+			}},
+		},
+		{
+			name:     "ephemeral resources",
+			add:      func(b *statisticsBuilder) { b.addEphemeralResource("keyvault", "NewKeyVaultSecretEphemeralResource") },
+			expected: ProviderStatistics{EphemeralResources: 1, TotalResources: 1, Services: map[string]ServiceStatistics{"keyvault": {EphemeralResources: 1}}},
+		},
+		{
+			name:     "deprecated resources",
+			add:      func(b *statisticsBuilder) { b.addDeprecatedResource("keyvault", "azurerm_key_vault") },
+			expected: ProviderStatistics{DeprecatedResources: 1, Services: map[string]ServiceStatistics{"keyvault": {DeprecatedResources: 1}}},
+		},
+		{
+			name: "terraform type strategies",
+			add: func(b *statisticsBuilder) {
+				b.addTerraformTypeStrategy("storage", "AccountResource", "metadata")
+				b.addTerraformTypeStrategy("storage", "BlobDataSource", "metadata")
+				b.addTerraformTypeStrategy("compute", "VirtualMachineResource", "resource_type_literal")
+			},
+			expected: ProviderStatistics{TerraformTypeStrategies: map[string]int{"metadata": 2, "resource_type_literal": 1}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			builder := newStatisticsBuilder()
+			c.add(builder)
+			// Adding the same registrations again doesn't double count them
+			c.add(builder)
+
+			if c.expected.TerraformTypeStrategies == nil {
+				c.expected.TerraformTypeStrategies = map[string]int{}
+			}
+			if c.expected.Services == nil {
+				c.expected.Services = map[string]ServiceStatistics{}
+			}
+			assert.Equal(t, c.expected, builder.build())
+		})
+	}
+}
+
+func TestStatisticsBuilder_AddServiceRegistration(t *testing.T) {
+	service := ServiceRegistration{
+		ServiceName:          "keyvault",
+		SupportedResources:   map[string]string{"azurerm_key_vault": "resourceKeyVault", "azurerm_key_vault_key": "resourceKeyVaultKey"},
+		SupportedDataSources: map[string]string{"azurerm_key_vault": "dataSourceKeyVault"},
+		Resources:            []string{"KeyVaultCertificateContactsResource", "KeyVaultCertificateContactsResource"},
+		DataSources:          []string{"EncryptedValueDataSource"},
+		EphemeralFunctions:   []string{"NewKeyVaultSecretEphemeralResource"},
+		ResourceDeprecations: map[string]string{"azurerm_key_vault_key": "deprecated"},
+		TerraformTypeStrategies: map[string]string{
+			"KeyVaultCertificateContactsResource": "resource_type_literal",
+			"EncryptedValueDataSource":            "metadata",
+		},
+	}
+
+	builder := newStatisticsBuilder()
+	builder.addServiceRegistration(service)
+
+	assert.Equal(t, ProviderStatistics{
+		ServiceCount:            1,
+		TotalDataSources:        2,
+		TotalResources:          4,
+		LegacyResources:         2,
+		ModernResources:         1,
+		EphemeralResources:      1,
+		DeprecatedResources:     1,
+		TerraformTypeStrategies: map[string]int{"resource_type_literal": 1, "metadata": 1},
+		Services: map[string]ServiceStatistics{
+			"keyvault": {
+				LegacyResources:     2,
+				ModernResources:     1,
+				DataSources:         2,
+				LegacyDataSources:   1,
+				ModernDataSources:   1,
+				EphemeralResources:  1,
+				DeprecatedResources: 1,
+			},
+		},
+	}, builder.build())
+	assert.Equal(t, builder.build(), buildProviderStatistics([]ServiceRegistration{service}))
+}
+
+func TestScan_AccumulatedStatisticsMatchServices(t *testing.T) {
+	index, err := ScanTerraformProviderServices(filepath.Join("testharness", "internal", "services"), "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, buildProviderStatistics(index.Services), index.Statistics, "statistics accumulated as scan results arrive match the statistics of the scanned services")
+}
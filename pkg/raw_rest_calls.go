@@ -0,0 +1,81 @@
+package pkg
+
+import (
+	"go/ast"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// CapabilityRawRESTCalls labels resources whose CRUD functions bypass the typed go-azure-sdk clients, the resources
+// most likely to break on API changes
+const CapabilityRawRESTCalls = "raw_rest_calls"
+
+// rawRESTIdentifiers are the identifiers of packages sending HTTP requests without a typed SDK client, by import path.
+// A nil set matches any identifier of the package.
+var rawRESTIdentifiers = map[string]map[string]bool{
+	"github.com/Azure/go-autorest/autorest": nil,
+	"net/http": {
+		"NewRequest": true, "NewRequestWithContext": true, "DefaultClient": true, "Client": true,
+		"Get": true, "Head": true, "Post": true, "PostForm": true,
+	},
+	"github.com/hashicorp/go-azure-sdk/sdk/client": {"RequestOptions": true},
+}
+
+// extractLegacyRawRESTCapabilityFromPackage detects legacy resources whose CRUD functions send raw REST requests
+func extractLegacyRawRESTCapabilityFromPackage(crudMethods *LegacyResourceCRUDFunctions, packageInfo *gophon.PackageInfo) string {
+	if crudMethods == nil {
+		return ""
+	}
+	var functions []*ast.FuncDecl
+	for _, name := range []string{crudMethods.CreateMethod, crudMethods.ReadMethod, crudMethods.UpdateMethod, crudMethods.DeleteMethod} {
+		if name != "" {
+			functions = append(functions, findFunctionDecl(packageInfo, name))
+		}
+	}
+	if !sendsRawRESTRequests(packageInfo, functions...) {
+		return ""
+	}
+	return CapabilityRawRESTCalls
+}
+
+// extractTypedRawRESTCapabilityFromPackage detects typed resources whose CRUD methods send raw REST requests
+func extractTypedRawRESTCapabilityFromPackage(structName string, packageInfo *gophon.PackageInfo) string {
+	var functions []*ast.FuncDecl
+	for _, name := range []string{"Create", "Read", "Update", "Delete"} {
+		functions = append(functions, findMethodDecl(packageInfo, structName, name))
+	}
+	if !sendsRawRESTRequests(packageInfo, functions...) {
+		return ""
+	}
+	return CapabilityRawRESTCalls
+}
+
+// sendsRawRESTRequests reports whether the given functions use autorest, net/http clients or the request options of
+// the base go-azure-sdk client instead of a typed SDK client
+func sendsRawRESTRequests(packageInfo *gophon.PackageInfo, functions ...*ast.FuncDecl) bool {
+	for _, fn := range functions {
+		file := findFunctionFile(packageInfo, fn)
+		if file == nil || fn.Body == nil {
+			continue
+		}
+		found := false
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if found {
+				return false
+			}
+			selector, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if alias, ok := selector.X.(*ast.Ident); ok {
+				identifiers, exists := rawRESTIdentifiers[importPathOfAlias(file, alias.Name)]
+				found = exists && (identifiers == nil || identifiers[selector.Sel.Name])
+			}
+			return !found
+		})
+		if found {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractLegacyRawRESTCapabilityFromPackage(t *testing.T) {
+	source := `package web
+
+import (
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+func resourceAppCreate(d *pluginsdk.ResourceData, meta interface{}) error {
+	req, err := autorest.Prepare(&http.Request{}, autorest.AsPut())
+	return err
+}
+
+func resourceAppRead(d *pluginsdk.ResourceData, meta interface{}) error {
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return nil
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+
+	assert.Equal(t, CapabilityRawRESTCalls, extractLegacyRawRESTCapabilityFromPackage(&LegacyResourceCRUDFunctions{
+		CreateMethod: "resourceAppCreate",
+		ReadMethod:   "resourceAppRead",
+	}, packageInfo))
+	assert.Empty(t, extractLegacyRawRESTCapabilityFromPackage(&LegacyResourceCRUDFunctions{ReadMethod: "resourceAppRead"}, packageInfo),
+		"constants of net/http don't send requests")
+	assert.Empty(t, extractLegacyRawRESTCapabilityFromPackage(nil, packageInfo))
+}
+
+func TestExtractTypedRawRESTCapabilityFromPackage(t *testing.T) {
+	source := `package containerapps
+
+import (
+	"net/http"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/client"
+)
+
+type ContainerAppResource struct{}
+
+type ManagedEnvironmentResource struct{}
+
+func (r ContainerAppResource) Delete() sdk.ResourceFunc {
+	return sdk.ResourceFunc{
+		Func: func(ctx context.Context, metadata sdk.ResourceMetaData) error {
+			opts := client.RequestOptions{HttpMethod: http.MethodDelete}
+			return nil
+		},
+	}
+}
+
+func (r ManagedEnvironmentResource) Delete() sdk.ResourceFunc {
+	return sdk.ResourceFunc{
+		Func: func(ctx context.Context, metadata sdk.ResourceMetaData) error {
+			return metadata.Client.ContainerApps.ManagedEnvironmentClient.DeleteThenPoll(ctx, *id)
+		},
+	}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+
+	assert.Equal(t, CapabilityRawRESTCalls, extractTypedRawRESTCapabilityFromPackage("ContainerAppResource", packageInfo))
+	assert.Empty(t, extractTypedRawRESTCapabilityFromPackage("ManagedEnvironmentResource", packageInfo))
+}
@@ -0,0 +1,239 @@
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// servicesDir is the directory of the service packages, relative to the provider checkout root
+var servicesDir = filepath.Join("internal", "services")
+
+// RegistrationIssue is a registration problem found by the check-pr mode
+type RegistrationIssue struct {
+	Service string `json:"service"`        // "keyvault"
+	File    string `json:"file,omitempty"` // "internal/services/keyvault/key_vault_resource.go", relative to the checkout root
+	Line    int    `json:"line,omitempty"` // 42
+	Message string `json:"message"`        // "resource azurerm_key_vault is registered 2 times, by services keyvault, managedhsm"
+}
+
+// Annotation formats the issue as a GitHub Actions workflow command, shown inline on the pull request diff
+func (i RegistrationIssue) Annotation() string {
+	location := ""
+	if i.File != "" {
+		location = " file=" + filepath.ToSlash(i.File)
+		if i.Line > 0 {
+			location += fmt.Sprintf(",line=%d", i.Line)
+		}
+	}
+	// Workflow command values escape newlines, the message can't span lines
+	message := strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(i.Message)
+	return fmt.Sprintf("::error%s::%s", location, message)
+}
+
+// ChangedServices lists the services with files changed between baseRef and HEAD of the provider checkout
+func ChangedServices(providerPath, baseRef string) ([]string, error) {
+	cmd := exec.Command("git", "diff", "--name-only", baseRef+"...HEAD")
+	cmd.Dir = providerPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff against %s failed: %w", baseRef, err)
+	}
+
+	services := make(map[string]bool)
+	prefix := filepath.ToSlash(servicesDir) + "/"
+	for _, line := range strings.Split(string(output), "\n") {
+		rest, found := strings.CutPrefix(strings.TrimSpace(line), prefix)
+		if !found {
+			continue
+		}
+		// Only files inside a service directory belong to a service
+		if service, _, found := strings.Cut(rest, "/"); found {
+			services[service] = true
+		}
+	}
+
+	result := make([]string, 0, len(services))
+	for service := range services {
+		result = append(result, service)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// RunRegistrationCheck scans the given services of a provider checkout and checks their registrations, see
+// checkRegistrations. The provider services are expected under <providerPath>/internal/services.
+func RunRegistrationCheck(providerPath, basePkgUrl string, services []string, progressCallback ProgressCallback) ([]RegistrationIssue, error) {
+	rootDir, err := filepath.Abs(providerPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve provider checkout %s: %w", providerPath, err)
+	}
+
+	// gophon loads packages relative to the working directory, so the scan must run from the checkout root
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	if err := os.Chdir(rootDir); err != nil {
+		return nil, fmt.Errorf("failed to enter provider checkout %s: %w", providerPath, err)
+	}
+	defer func() {
+		_ = os.Chdir(wd)
+	}()
+
+	selected := make(map[string]bool)
+	for _, service := range services {
+		selected[service] = true
+	}
+	index, err := scanTerraformProviderServices(servicesDir, basePkgUrl, "check-pr", func(serviceName string) bool {
+		return selected[serviceName]
+	}, Scanner{}, progressCallback)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan provider checkout: %w", err)
+	}
+	return checkRegistrations(index, DefaultProviderName, rootDir), nil
+}
+
+// checkRegistrations validates the registrations of the scanned services: Terraform type names must be lower snake
+// case prefixed with the provider name, a type must be registered only once per kind among the scanned services, and
+// typed resources and data sources must declare their type with a ResourceType method returning a string literal or
+// constant. File locations are relative to rootDir.
+func checkRegistrations(index *TerraformProviderIndex, providerName, rootDir string) []RegistrationIssue {
+	typeNamePattern := regexp.MustCompile(`^` + regexp.QuoteMeta(providerName) + `(_[a-z0-9]+)+$`)
+
+	var issues []RegistrationIssue
+	registrations := make(map[string][]RegistrationIssue) // "resource azurerm_key_vault" -> locations of its registrations
+	var registrationKeys []string
+
+	register := func(service ServiceRegistration, kind, terraformType, declaration string) {
+		location := declarationLocation(service, declaration, rootDir)
+		if !typeNamePattern.MatchString(terraformType) {
+			location.Message = fmt.Sprintf("%s type %q must be lower snake case prefixed with %s_", kind, terraformType, providerName)
+			issues = append(issues, location)
+		}
+		key := kind + " " + terraformType
+		if registrations[key] == nil {
+			registrationKeys = append(registrationKeys, key)
+		}
+		registrations[key] = append(registrations[key], location)
+	}
+	checkResourceType := func(service ServiceRegistration, kind, structType string) {
+		method := methodRange(service.Package, structType, "ResourceType")
+		if method == nil {
+			location := declarationLocation(service, structType, rootDir)
+			location.Message = fmt.Sprintf("typed %s %s has no ResourceType method", kind, structType)
+			issues = append(issues, location)
+			return
+		}
+		if !isResourceTypeStrategy(service.TerraformTypeStrategies[structType]) {
+			location := rangeLocation(service, method, rootDir)
+			location.Message = fmt.Sprintf("ResourceType method of typed %s %s must return a string literal or constant", kind, structType)
+			issues = append(issues, location)
+		}
+	}
+
+	for _, service := range index.Services {
+		for _, terraformType := range sortedKeys(service.SupportedResources) {
+			register(service, "resource", terraformType, service.SupportedResources[terraformType])
+		}
+		for _, structType := range service.Resources {
+			checkResourceType(service, "resource", structType)
+			if terraformType, exists := service.ResourceTerraformTypes[structType]; exists {
+				register(service, "resource", terraformType, structType)
+			}
+		}
+		for _, terraformType := range sortedKeys(service.SupportedDataSources) {
+			register(service, "data source", terraformType, service.SupportedDataSources[terraformType])
+		}
+		for _, structType := range service.DataSources {
+			checkResourceType(service, "data source", structType)
+			if terraformType, exists := service.DataSourceTerraformTypes[structType]; exists {
+				register(service, "data source", terraformType, structType)
+			}
+		}
+	}
+
+	for _, key := range registrationKeys {
+		locations := registrations[key]
+		if len(locations) < 2 {
+			continue
+		}
+		var services []string
+		for _, location := range locations {
+			services = append(services, location.Service)
+		}
+		for _, location := range locations {
+			location.Message = fmt.Sprintf("%s is registered %d times, by services %s", key, len(locations), strings.Join(services, ", "))
+			issues = append(issues, location)
+		}
+	}
+	return issues
+}
+
+// isResourceTypeStrategy reports whether a Terraform type was read from a ResourceType method
+func isResourceTypeStrategy(strategy string) bool {
+	return strategy == TerraformTypeStrategyResourceTypeLiteral || strategy == TerraformTypeStrategyResourceTypeConstant
+}
+
+// declarationLocation returns an issue located at the declaration of a registration function or struct type,
+// falling back to the service without a file when the declaration can't be found
+func declarationLocation(service ServiceRegistration, name, rootDir string) RegistrationIssue {
+	var declaration *gophon.Range
+	if service.Package != nil {
+		for _, typeInfo := range service.Package.Types {
+			if typeInfo.Name == name && typeInfo.Range != nil {
+				declaration = typeInfo.Range
+				break
+			}
+		}
+		for _, funcInfo := range service.Package.Functions {
+			if declaration == nil && funcInfo.Name == name && funcInfo.Range != nil && funcInfo.FuncDecl != nil && funcInfo.Recv == nil {
+				declaration = funcInfo.Range
+			}
+		}
+	}
+	return rangeLocation(service, declaration, rootDir)
+}
+
+// methodRange returns the source range of a method declared on a struct, nil when the struct has no such method
+func methodRange(packageInfo *gophon.PackageInfo, structName, methodName string) *gophon.Range {
+	if packageInfo == nil {
+		return nil
+	}
+	for _, funcInfo := range packageInfo.Functions {
+		if funcInfo.Name == methodName && funcInfo.FuncDecl != nil && receiverTypeName(funcInfo.FuncDecl) == structName {
+			return funcInfo.Range
+		}
+	}
+	return nil
+}
+
+// rangeLocation returns an issue of the service located at a source range, without a file when the range is unknown
+func rangeLocation(service ServiceRegistration, declaration *gophon.Range, rootDir string) RegistrationIssue {
+	issue := RegistrationIssue{Service: service.ServiceName}
+	if declaration == nil || declaration.FileInfo == nil {
+		return issue
+	}
+	issue.File = declaration.FileName
+	if rel, err := filepath.Rel(rootDir, declaration.FileName); err == nil {
+		issue.File = rel
+	}
+	issue.Line = declaration.StartLine
+	return issue
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
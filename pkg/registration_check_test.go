@@ -0,0 +1,121 @@
+package pkg
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRegistrations(t *testing.T) {
+	keyVaultPackage := parsePackageInfo(t, `package keyvault
+
+func resourceKeyVault() *pluginsdk.Resource {
+	return &pluginsdk.Resource{}
+}
+
+type KeyVaultSecretResource struct{}
+
+func (r KeyVaultSecretResource) ResourceType() string {
+	return "azurerm_key_vault_secret"
+}
+
+type KeyVaultKeyResource struct{}
+
+func (r KeyVaultKeyResource) ResourceType() string {
+	return r.name
+}`)
+	managedHSMPackage := parsePackageInfo(t, `package managedhsm
+
+func resourceManagedHSMKeyVault() *pluginsdk.Resource {
+	return &pluginsdk.Resource{}
+}
+
+func resourceBadName() *pluginsdk.Resource {
+	return &pluginsdk.Resource{}
+}`)
+
+	index := &TerraformProviderIndex{Services: []ServiceRegistration{
+		{
+			ServiceName:        "keyvault",
+			Package:            keyVaultPackage,
+			SupportedResources: map[string]string{"azurerm_key_vault": "resourceKeyVault"},
+			Resources:          []string{"KeyVaultKeyResource", "KeyVaultSecretResource"},
+			ResourceTerraformTypes: map[string]string{
+				"KeyVaultKeyResource":    "azurerm_key_vault_key",
+				"KeyVaultSecretResource": "azurerm_key_vault_secret",
+			},
+			TerraformTypeStrategies: map[string]string{
+				"KeyVaultKeyResource":    TerraformTypeStrategyNamingConvention,
+				"KeyVaultSecretResource": TerraformTypeStrategyResourceTypeLiteral,
+			},
+		},
+		{
+			ServiceName: "managedhsm",
+			Package:     managedHSMPackage,
+			SupportedResources: map[string]string{
+				"azurerm_key_vault": "resourceManagedHSMKeyVault",
+				"azurerm_BadName":   "resourceBadName",
+			},
+		},
+	}}
+
+	issues := checkRegistrations(index, "azurerm", ".")
+
+	assert.Equal(t, []RegistrationIssue{
+		{Service: "keyvault", File: "file0.go", Line: 15, Message: "ResourceType method of typed resource KeyVaultKeyResource must return a string literal or constant"},
+		{Service: "managedhsm", File: "file0.go", Line: 7, Message: `resource type "azurerm_BadName" must be lower snake case prefixed with azurerm_`},
+		{Service: "keyvault", File: "file0.go", Line: 3, Message: "resource azurerm_key_vault is registered 2 times, by services keyvault, managedhsm"},
+		{Service: "managedhsm", File: "file0.go", Line: 3, Message: "resource azurerm_key_vault is registered 2 times, by services keyvault, managedhsm"},
+	}, issues)
+}
+
+func TestRegistrationIssue_Annotation(t *testing.T) {
+	assert.Equal(t, "::error file=internal/services/keyvault/registration.go,line=12::resource type \"azurerm_Bad\" must be lower snake case%0Aprefixed",
+		RegistrationIssue{File: "internal/services/keyvault/registration.go", Line: 12, Message: "resource type \"azurerm_Bad\" must be lower snake case\nprefixed"}.Annotation())
+	assert.Equal(t, "::error::100%25 broken", RegistrationIssue{Message: "100% broken"}.Annotation())
+}
+
+func TestRunRegistrationCheck_TestHarness(t *testing.T) {
+	issues, err := RunRegistrationCheck("testharness", "github.com/lonegunmanb/terraform-provider-azurerm-index/testharness", []string{"keyvault"}, nil)
+	require.NoError(t, err)
+
+	for _, issue := range issues {
+		assert.Equal(t, "keyvault", issue.Service)
+		assert.Equal(t, "internal/services/keyvault/registration.go", issue.File)
+		assert.Positive(t, issue.Line)
+	}
+}
+
+func TestChangedServices(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	git("init", "--quiet")
+	writeTestPackage(t, repo, "internal/services/keyvault", "registration.go", "package keyvault\n")
+	git("add", "-A")
+	git("commit", "--quiet", "-m", "base")
+	git("tag", "base")
+	writeTestPackage(t, repo, "internal/services/keyvault", "key_vault_resource.go", "package keyvault\n")
+	writeTestPackage(t, repo, "internal/services/storage/parse", "account.go", "package parse\n")
+	writeTestPackage(t, repo, "internal/services", "README.md", "services\n")
+	writeTestPackage(t, repo, "website/docs/r", "key_vault.html.markdown", "docs\n")
+	git("add", "-A")
+	git("commit", "--quiet", "-m", "change")
+
+	services, err := ChangedServices(repo, "base")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"keyvault", "storage"}, services)
+
+	_, err = ChangedServices(repo, "missing-ref")
+	assert.Error(t, err)
+}
@@ -0,0 +1,355 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/spf13/afero"
+)
+
+// Schemes of the remote output locations OpenRemoteOutput writes to
+const (
+	RemoteOutputAzureBlob = "az" // az://container/prefix, Azure Blob Storage
+	RemoteOutputS3        = "s3" // s3://bucket/prefix, Amazon S3 or an S3 compatible store
+)
+
+// remoteOutputTimeout bounds the upload or removal of a single file
+const remoteOutputTimeout = 5 * time.Minute
+
+// IsRemoteOutput reports whether an output location is the URL of a remote store, az://container/prefix or
+// s3://bucket/prefix, rather than a directory
+func IsRemoteOutput(output string) bool {
+	return strings.HasPrefix(output, RemoteOutputAzureBlob+"://") || strings.HasPrefix(output, RemoteOutputS3+"://")
+}
+
+// OpenRemoteOutput returns the output directory to write the index to, output without a trailing slash, and the file
+// system writing the index files under output, an az://container/prefix or s3://bucket/prefix URL, to Azure Blob
+// Storage or S3, each file uploaded once it is written. The file system is handed to the writers of the index,
+// OutputConfig.Fs and StreamWriter.Fs, local paths, such as the statistics history, are written to the local file
+// system and everything else keeps reading the local file system.
+//
+// Azure Blob Storage is accessed with a SAS token of the AZURE_STORAGE_SAS_TOKEN environment variable, on the
+// account of AZURE_STORAGE_ACCOUNT or at the AZURE_STORAGE_BLOB_ENDPOINT endpoint. S3 is accessed with the
+// credentials and region of the AWS SDK's default configuration, us-east-1 when no region is set, or at the
+// AWS_ENDPOINT_URL_S3 endpoint of an S3 compatible store.
+func OpenRemoteOutput(output string) (string, afero.Fs, error) {
+	location, err := url.Parse(output)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid remote output %s: %w", output, err)
+	}
+	if location.Host == "" {
+		return "", nil, fmt.Errorf("remote output %s has no container or bucket", output)
+	}
+
+	var store objectStore
+	switch location.Scheme {
+	case RemoteOutputAzureBlob:
+		store, err = newAzureBlobStore(location.Host)
+	case RemoteOutputS3:
+		store, err = newS3Store(location.Host)
+	default:
+		return "", nil, fmt.Errorf("unsupported remote output %s, expected az://container/prefix or s3://bucket/prefix", output)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	prefix := strings.Trim(path.Clean("/"+location.Path), "/")
+	outputDir := location.Scheme + "://" + location.Host
+	if prefix != "" {
+		outputDir += "/" + prefix
+	}
+	return outputDir, &remoteOutputFs{
+		Fs:        afero.NewOsFs(),
+		root:      outputDir,
+		scheme:    location.Scheme,
+		container: location.Host,
+		prefix:    prefix,
+		store:     store,
+		staging:   afero.NewMemMapFs(),
+	}, nil
+}
+
+// objectStore uploads and removes the objects of a remote output location
+type objectStore interface {
+	put(key string, content []byte) error
+	// remove removes an object, returning os.ErrNotExist when there is none
+	remove(key string) error
+}
+
+// remoteOutputFs uploads the files written under root to a remote store, the files are staged in memory by object
+// key until they are closed. Local paths are handled by the wrapped file system.
+type remoteOutputFs struct {
+	afero.Fs
+	root      string // "az://container/prefix", the output location
+	scheme    string // "az"
+	container string // "container", the container or bucket
+	prefix    string // "prefix", the key prefix of the uploaded objects
+	store     objectStore
+	staging   afero.Fs
+}
+
+// key returns the object key of a path under root and true, or false for local paths. Paths with an az: or s3:
+// scheme are remote, filepath.Join cleans az://container/prefix/file into az:/container/prefix/file, and are an
+// error unless they are under root.
+func (fs *remoteOutputFs) key(name string) (string, bool, error) {
+	slashed := filepath.ToSlash(name)
+	var rest string
+	remote := false
+	for _, scheme := range []string{RemoteOutputAzureBlob, RemoteOutputS3} {
+		if after, ok := strings.CutPrefix(slashed, scheme+":"); ok {
+			if scheme != fs.scheme {
+				return "", true, fmt.Errorf("%s is outside of the remote output %s", name, fs.root)
+			}
+			rest, remote = after, true
+		}
+	}
+	if !remote {
+		return "", false, nil
+	}
+	container, objectPath, _ := strings.Cut(strings.TrimLeft(rest, "/"), "/")
+	key := strings.Trim(path.Clean("/"+objectPath), "/")
+	if container != fs.container || (fs.prefix != "" && key != fs.prefix && !strings.HasPrefix(key, fs.prefix+"/")) {
+		return "", true, fmt.Errorf("%s is outside of the remote output %s", name, fs.root)
+	}
+	return key, true, nil
+}
+
+// stagingPath returns the path an object is staged at
+func stagingPath(key string) string {
+	return "/" + key
+}
+
+func (fs *remoteOutputFs) Create(name string) (afero.File, error) {
+	return fs.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+}
+
+func (fs *remoteOutputFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	key, remote, err := fs.key(name)
+	if !remote {
+		return fs.Fs.OpenFile(name, flag, perm)
+	}
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: name, Err: err}
+	}
+	if flag&(os.O_WRONLY|os.O_RDWR) == 0 {
+		return nil, &os.PathError{Op: "open", Path: name, Err: fmt.Errorf("remote output is write-only")}
+	}
+	if err := fs.staging.MkdirAll(path.Dir(stagingPath(key)), 0755); err != nil {
+		return nil, err
+	}
+	file, err := fs.staging.OpenFile(stagingPath(key), flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteOutputFile{File: file, fs: fs, key: key}, nil
+}
+
+// Mkdir creates the directory in the staging file system only, object stores have no directories
+func (fs *remoteOutputFs) Mkdir(name string, perm os.FileMode) error {
+	key, remote, err := fs.key(name)
+	if !remote {
+		return fs.Fs.Mkdir(name, perm)
+	}
+	if err != nil {
+		return &os.PathError{Op: "mkdir", Path: name, Err: err}
+	}
+	return fs.staging.MkdirAll(stagingPath(key), perm)
+}
+
+// MkdirAll creates the directories in the staging file system only, object stores have no directories
+func (fs *remoteOutputFs) MkdirAll(name string, perm os.FileMode) error {
+	key, remote, err := fs.key(name)
+	if !remote {
+		return fs.Fs.MkdirAll(name, perm)
+	}
+	if err != nil {
+		return &os.PathError{Op: "mkdir", Path: name, Err: err}
+	}
+	return fs.staging.MkdirAll(stagingPath(key), perm)
+}
+
+// Stat describes the directories and the files being written under root, uploaded objects can't be read back
+func (fs *remoteOutputFs) Stat(name string) (os.FileInfo, error) {
+	key, remote, err := fs.key(name)
+	if !remote {
+		return fs.Fs.Stat(name)
+	}
+	if err != nil {
+		return nil, &os.PathError{Op: "stat", Path: name, Err: err}
+	}
+	return fs.staging.Stat(stagingPath(key))
+}
+
+func (fs *remoteOutputFs) Remove(name string) error {
+	key, remote, err := fs.key(name)
+	if !remote {
+		return fs.Fs.Remove(name)
+	}
+	if err != nil {
+		return &os.PathError{Op: "remove", Path: name, Err: err}
+	}
+	if err := fs.store.remove(key); err != nil {
+		return &os.PathError{Op: "remove", Path: name, Err: err}
+	}
+	return nil
+}
+
+// remoteOutputFile uploads its content when it is closed and drops it from the staging file system
+type remoteOutputFile struct {
+	afero.File
+	fs  *remoteOutputFs
+	key string
+}
+
+func (f *remoteOutputFile) Close() error {
+	name := f.File.Name()
+	if err := f.File.Close(); err != nil {
+		return err
+	}
+	content, err := afero.ReadFile(f.fs.staging, name)
+	if err != nil {
+		return err
+	}
+	_ = f.fs.staging.Remove(name)
+	if err := f.fs.store.put(f.key, content); err != nil {
+		return fmt.Errorf("failed to upload %s: %w", name, err)
+	}
+	return nil
+}
+
+// azureBlobStore writes block blobs to a container of Azure Blob Storage authorized by a SAS token
+type azureBlobStore struct {
+	client    *azblob.Client
+	container string
+}
+
+func newAzureBlobStore(container string) (*azureBlobStore, error) {
+	endpoint := os.Getenv("AZURE_STORAGE_BLOB_ENDPOINT")
+	if endpoint == "" {
+		account := os.Getenv("AZURE_STORAGE_ACCOUNT")
+		if account == "" {
+			return nil, fmt.Errorf("AZURE_STORAGE_ACCOUNT or AZURE_STORAGE_BLOB_ENDPOINT must be set to write to az://%s", container)
+		}
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", account)
+	}
+	sasToken := strings.TrimPrefix(os.Getenv("AZURE_STORAGE_SAS_TOKEN"), "?")
+	if sasToken == "" {
+		return nil, fmt.Errorf("AZURE_STORAGE_SAS_TOKEN must be set to write to az://%s", container)
+	}
+	client, err := azblob.NewClientWithNoCredential(strings.TrimSuffix(endpoint, "/")+"/?"+sasToken, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the Azure Blob Storage client for az://%s: %w", container, err)
+	}
+	return &azureBlobStore{client: client, container: container}, nil
+}
+
+func (s *azureBlobStore) put(key string, content []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteOutputTimeout)
+	defer cancel()
+	_, err := s.client.UploadBuffer(ctx, s.container, key, content, &azblob.UploadBufferOptions{
+		HTTPHeaders: &blob.HTTPHeaders{BlobContentType: to.Ptr(objectContentType(key))},
+	})
+	return err
+}
+
+func (s *azureBlobStore) remove(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteOutputTimeout)
+	defer cancel()
+	_, err := s.client.DeleteBlob(ctx, s.container, key, nil)
+	var responseError *azcore.ResponseError
+	if errors.As(err, &responseError) && responseError.StatusCode == http.StatusNotFound {
+		return os.ErrNotExist
+	}
+	return err
+}
+
+// s3Store writes objects to an S3 bucket
+type s3Store struct {
+	client *s3.Client
+	bucket string
+}
+
+func newS3Store(bucket string) (*s3Store, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteOutputTimeout)
+	defer cancel()
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the AWS configuration to write to s3://%s: %w", bucket, err)
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if _, err := cfg.Credentials.Retrieve(ctx); err != nil {
+		return nil, fmt.Errorf("no AWS credentials found to write to s3://%s: %w", bucket, err)
+	}
+	client := s3.NewFromConfig(cfg, func(options *s3.Options) {
+		// S3 compatible stores are addressed with path-style URLs and may not support the checksums the SDK
+		// otherwise adds to every upload
+		if os.Getenv("AWS_ENDPOINT_URL_S3") != "" {
+			options.UsePathStyle = true
+		}
+		options.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
+	})
+	return &s3Store{client: client, bucket: bucket}, nil
+}
+
+func (s *s3Store) put(key string, content []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteOutputTimeout)
+	defer cancel()
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(content),
+		ContentType: aws.String(objectContentType(key)),
+	})
+	return err
+}
+
+func (s *s3Store) remove(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteOutputTimeout)
+	defer cancel()
+	// S3 answers the removal of a missing object with 204 as well, check it exists so os.IsNotExist holds
+	_, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: aws.String(s.bucket), Key: aws.String(key)})
+	var responseError *awshttp.ResponseError
+	if errors.As(err, &responseError) && responseError.HTTPStatusCode() == http.StatusNotFound {
+		return os.ErrNotExist
+	}
+	if err != nil {
+		return err
+	}
+	_, err = s.client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String(s.bucket), Key: aws.String(key)})
+	return err
+}
+
+// objectContentType returns the content type of an uploaded index file
+func objectContentType(key string) string {
+	switch path.Ext(key) {
+	case ".json":
+		return "application/json"
+	case ".ndjson":
+		return "application/x-ndjson"
+	case ".md":
+		return "text/markdown; charset=utf-8"
+	case ".csv":
+		return "text/csv; charset=utf-8"
+	}
+	return "application/octet-stream"
+}
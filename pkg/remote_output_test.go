@@ -0,0 +1,153 @@
+package pkg
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/lonegunmanb/terraform-provider-azurerm-index/pkg/internal/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// objectStoreServer records the objects uploaded to it by path
+type objectStoreServer struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	headers map[string]http.Header
+	queries map[string]string
+}
+
+func newObjectStoreServer(t *testing.T) (*objectStoreServer, *httptest.Server) {
+	store := &objectStoreServer{objects: make(map[string][]byte), headers: make(map[string]http.Header), queries: make(map[string]string)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			content, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			store.objects[r.URL.Path] = content
+			store.headers[r.URL.Path] = r.Header
+			store.queries[r.URL.Path] = r.URL.RawQuery
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodHead, http.MethodDelete:
+			if _, ok := store.objects[r.URL.Path]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if r.Method == http.MethodDelete {
+				delete(store.objects, r.URL.Path)
+			}
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return store, server
+}
+
+func TestOpenRemoteOutput_AzureBlob(t *testing.T) {
+	store, server := newObjectStoreServer(t)
+	t.Setenv("AZURE_STORAGE_BLOB_ENDPOINT", server.URL)
+	t.Setenv("AZURE_STORAGE_SAS_TOKEN", "?sv=2022-11-02&sig=signature")
+
+	outputDir, remote, err := OpenRemoteOutput("az://index/azurerm/v4.20.0")
+	require.NoError(t, err)
+	assert.Equal(t, "az://index/azurerm/v4.20.0", outputDir)
+
+	index, err := ScanTerraformProviderServices(filepath.Join("testharness", "internal", "services"), "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+	require.NoError(t, err)
+	index.Output.Fs = remote
+	require.NoError(t, index.WriteIndexFiles(outputDir, nil))
+	historyPath := filepath.Join(t.TempDir(), "history", "stats.json")
+	require.NoError(t, output.WriteJSONFile(remote, historyPath, index.Statistics))
+
+	content, ok := store.objects["/index/azurerm/v4.20.0/resources/azurerm_key_vault.json"]
+	require.True(t, ok)
+	resource, found := index.LookupResource("azurerm_key_vault")
+	require.True(t, found)
+	assert.Contains(t, string(content), resource.ID)
+	assert.Contains(t, store.objects, "/index/azurerm/v4.20.0/terraform-provider-azurerm-index.json")
+	headers := store.headers["/index/azurerm/v4.20.0/resources/azurerm_key_vault.json"]
+	assert.Equal(t, "BlockBlob", headers.Get("x-ms-blob-type"))
+	assert.Equal(t, "application/json", headers.Get("x-ms-blob-content-type"))
+	assert.Contains(t, store.queries["/index/azurerm/v4.20.0/resources/azurerm_key_vault.json"], "sig=signature")
+
+	history, err := os.ReadFile(historyPath)
+	require.NoError(t, err, "paths outside of the remote output are written locally and read back")
+	assert.Contains(t, string(history), "total_resources")
+
+	info, err := remote.Stat(filepath.Join(outputDir, "resources"))
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+	_, err = remote.Stat(filepath.Join(outputDir, "resources", "azurerm_key_vault.json"))
+	assert.True(t, os.IsNotExist(err), "uploaded objects can't be read back: %v", err)
+	err = output.WriteJSONFile(remote, filepath.Join("az://index/azurerm/v4.19.0", "resources", "azurerm_key_vault.json"), resource)
+	assert.ErrorContains(t, err, "is outside of the remote output az://index/azurerm/v4.20.0")
+	err = output.WriteJSONFile(remote, "s3://index/azurerm/v4.20.0/resources/azurerm_key_vault.json", resource)
+	assert.ErrorContains(t, err, "is outside of the remote output az://index/azurerm/v4.20.0")
+	assert.NoDirExists(t, "az:", "remote paths never reach the local file system")
+
+	require.NoError(t, remote.Remove(filepath.Join(outputDir, "resources", "azurerm_key_vault.json")))
+	assert.NotContains(t, store.objects, "/index/azurerm/v4.20.0/resources/azurerm_key_vault.json")
+	err = remote.Remove(filepath.Join(outputDir, "resources", "azurerm_key_vault.json"))
+	assert.True(t, os.IsNotExist(err), "%v", err)
+}
+
+func TestOpenRemoteOutput_S3(t *testing.T) {
+	store, server := newObjectStoreServer(t)
+	t.Setenv("AWS_ENDPOINT_URL_S3", server.URL)
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "session")
+	t.Setenv("AWS_REGION", "eu-west-1")
+
+	outputDir, remote, err := OpenRemoteOutput("s3://bucket/")
+	require.NoError(t, err)
+	assert.Equal(t, "s3://bucket", outputDir)
+	require.NoError(t, output.WriteJSONFile(remote, filepath.Join(outputDir, "audit", "orphans.json"), []OrphanedImplementation{}))
+
+	content, ok := store.objects["/bucket/audit/orphans.json"]
+	require.True(t, ok)
+	assert.Equal(t, "[]", string(content))
+	headers := store.headers["/bucket/audit/orphans.json"]
+	assert.Equal(t, "application/json", headers.Get("Content-Type"))
+	assert.Equal(t, "session", headers.Get("X-Amz-Security-Token"))
+	assert.Regexp(t, `^AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/\d{8}/eu-west-1/s3/aws4_request, SignedHeaders=\S+, Signature=[0-9a-f]{64}$`, headers.Get("Authorization"))
+
+	require.NoError(t, remote.Remove(filepath.Join(outputDir, "audit", "orphans.json")))
+	assert.NotContains(t, store.objects, "/bucket/audit/orphans.json")
+	err = remote.Remove(filepath.Join(outputDir, "audit", "orphans.json"))
+	assert.True(t, os.IsNotExist(err), "%v", err)
+}
+
+func TestOpenRemoteOutput_Invalid(t *testing.T) {
+	t.Setenv("AZURE_STORAGE_BLOB_ENDPOINT", "")
+	t.Setenv("AZURE_STORAGE_ACCOUNT", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+	t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", "")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", "")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	_, _, err := OpenRemoteOutput("gs://bucket/prefix")
+	assert.ErrorContains(t, err, "unsupported remote output gs://bucket/prefix")
+	_, _, err = OpenRemoteOutput("az:///prefix")
+	assert.ErrorContains(t, err, "has no container or bucket")
+	_, _, err = OpenRemoteOutput("az://index")
+	assert.ErrorContains(t, err, "AZURE_STORAGE_ACCOUNT or AZURE_STORAGE_BLOB_ENDPOINT must be set")
+	_, _, err = OpenRemoteOutput("s3://bucket")
+	assert.ErrorContains(t, err, "no AWS credentials found to write to s3://bucket")
+
+	assert.True(t, IsRemoteOutput("az://index/prefix"))
+	assert.True(t, IsRemoteOutput("s3://bucket"))
+	assert.False(t, IsRemoteOutput("./index"))
+}
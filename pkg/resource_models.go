@@ -0,0 +1,116 @@
+package pkg
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// ResourceModel is the struct a typed resource or data source decodes its configuration and state into, returned by
+// its ModelObject method
+type ResourceModel struct {
+	StructType string       `json:"struct_type" protobuf:"1"` // "KeyVaultResourceModel"
+	Fields     []ModelField `json:"fields" protobuf:"2"`      // Fields with a tfschema tag, in declaration order
+}
+
+// ModelField maps a field of a model struct to the schema attribute named by its tfschema tag
+type ModelField struct {
+	Field     string         `json:"field" protobuf:"1"`             // "NetworkAcls"
+	Type      string         `json:"type" protobuf:"2"`              // "[]NetworkAclsModel"
+	Attribute string         `json:"attribute" protobuf:"3"`         // "network_acls"
+	Options   []string       `json:"options,omitempty" protobuf:"4"` // ["removedInNextMajorVersion"], the tag options after the attribute name
+	Model     *ResourceModel `json:"model,omitempty" protobuf:"5"`   // Model of the nested block when the field holds a model struct of the package
+}
+
+// extractTypedModelFromPackage resolves the struct type returned by the ModelObject method of a typed resource or
+// data source and maps its tagged fields to attributes, nil when ModelObject returns nil or an unknown type
+func extractTypedModelFromPackage(structName string, packageInfo *gophon.PackageInfo) *ResourceModel {
+	method := findMethodDecl(packageInfo, structName, "ModelObject")
+	if method == nil {
+		return nil
+	}
+	modelType := resolveConstructorStructType(method, packageFunctionDecls(packageInfo), make(map[string]bool))
+	if modelType == "" {
+		return nil
+	}
+	return extractModelStruct(modelType, packageInfo, make(map[string]bool))
+}
+
+// extractModelStruct maps the tagged fields of a model struct declared in the package, following the fields holding
+// other model structs of the package, nil when the package doesn't declare the struct or it has no tagged fields
+func extractModelStruct(structName string, packageInfo *gophon.PackageInfo, visited map[string]bool) *ResourceModel {
+	structType := findStructType(packageInfo, structName)
+	if structType == nil || visited[structName] {
+		return nil
+	}
+	visited[structName] = true
+	defer delete(visited, structName)
+
+	model := &ResourceModel{StructType: structName, Fields: []ModelField{}}
+	for _, field := range structType.Fields.List {
+		if field.Tag == nil || len(field.Names) == 0 {
+			continue
+		}
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+		value, ok := reflect.StructTag(tag).Lookup("tfschema")
+		if !ok {
+			continue
+		}
+		parts := strings.Split(value, ",")
+		for _, name := range field.Names {
+			modelField := ModelField{Field: name.Name, Type: types.ExprString(field.Type), Attribute: parts[0]}
+			if len(parts) > 1 {
+				modelField.Options = parts[1:]
+			}
+			if nested := modelElementTypeName(field.Type); nested != "" {
+				modelField.Model = extractModelStruct(nested, packageInfo, visited)
+			}
+			model.Fields = append(model.Fields, modelField)
+		}
+	}
+	if len(model.Fields) == 0 {
+		return nil
+	}
+	return model
+}
+
+// modelElementTypeName returns the local type a field holds, looking through pointers, slices and maps, "" for
+// types of other packages and builtin types
+func modelElementTypeName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return modelElementTypeName(e.X)
+	case *ast.ArrayType:
+		return modelElementTypeName(e.Elt)
+	case *ast.MapType:
+		return modelElementTypeName(e.Value)
+	}
+	return typeName(expr)
+}
+
+// findStructType returns the struct type of a type declared in the package, nil when the package doesn't declare it
+// or it isn't a struct
+func findStructType(packageInfo *gophon.PackageInfo, typeName string) *ast.StructType {
+	if packageInfo == nil {
+		return nil
+	}
+	for _, typeInfo := range packageInfo.Types {
+		if typeInfo.Name != typeName || typeInfo.GenDecl == nil {
+			continue
+		}
+		for _, spec := range typeInfo.GenDecl.Specs {
+			if typeSpec, ok := spec.(*ast.TypeSpec); ok && typeSpec.Name.Name == typeName {
+				structType, _ := typeSpec.Type.(*ast.StructType)
+				return structType
+			}
+		}
+	}
+	return nil
+}
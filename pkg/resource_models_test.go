@@ -0,0 +1,113 @@
+package pkg
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+const resourceModelSource = `package keyvault
+
+type KeyVaultResource struct{}
+
+type KeyVaultResourceModel struct {
+	Name        string              ` + "`tfschema:\"name\"`" + `
+	Tags        map[string]string   ` + "`tfschema:\"tags\"`" + `
+	NetworkAcls []NetworkAclsModel  ` + "`tfschema:\"network_acls\"`" + `
+	Legacy      *string             ` + "`tfschema:\"legacy,removedInNextMajorVersion\"`" + `
+	internal    string
+}
+
+type NetworkAclsModel struct {
+	Bypass   string            ` + "`tfschema:\"bypass\"`" + `
+	Parent   *KeyVaultResourceModel ` + "`tfschema:\"parent\"`" + `
+}
+
+func (r KeyVaultResource) ModelObject() interface{} {
+	return &KeyVaultResourceModel{}
+}
+
+type KeyVaultDataSource struct{}
+
+func (KeyVaultDataSource) ModelObject() interface{} {
+	return newDataSourceModel()
+}
+
+func newDataSourceModel() *KeyVaultDataSourceModel {
+	return new(KeyVaultDataSourceModel)
+}
+
+type KeyVaultDataSourceModel struct {
+	VaultUri string ` + "`tfschema:\"vault_uri\"`" + `
+}
+
+type KeyVaultUntypedResource struct{}
+
+func (KeyVaultUntypedResource) ModelObject() interface{} {
+	return nil
+}
+`
+
+// withTypeInfos adds the type declarations of the parsed files to a package parsed with parsePackageInfo
+func withTypeInfos(packageInfo *gophon.PackageInfo) *gophon.PackageInfo {
+	for _, fileInfo := range packageInfo.Files {
+		for _, decl := range fileInfo.File.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				packageInfo.Types = append(packageInfo.Types, &gophon.TypeInfo{GenDecl: genDecl, Name: spec.(*ast.TypeSpec).Name.Name})
+			}
+		}
+	}
+	return packageInfo
+}
+
+func TestExtractTypedModelFromPackage(t *testing.T) {
+	packageInfo := withTypeInfos(parsePackageInfo(t, resourceModelSource))
+
+	model := extractTypedModelFromPackage("KeyVaultResource", packageInfo)
+
+	networkAcls := &ResourceModel{StructType: "NetworkAclsModel", Fields: []ModelField{
+		{Field: "Bypass", Type: "string", Attribute: "bypass"},
+		// Recursive models are only expanded once
+		{Field: "Parent", Type: "*KeyVaultResourceModel", Attribute: "parent"},
+	}}
+	assert.Equal(t, &ResourceModel{StructType: "KeyVaultResourceModel", Fields: []ModelField{
+		{Field: "Name", Type: "string", Attribute: "name"},
+		{Field: "Tags", Type: "map[string]string", Attribute: "tags"},
+		{Field: "NetworkAcls", Type: "[]NetworkAclsModel", Attribute: "network_acls", Model: networkAcls},
+		{Field: "Legacy", Type: "*string", Attribute: "legacy", Options: []string{"removedInNextMajorVersion"}},
+	}}, model)
+}
+
+func TestExtractTypedModelFromPackage_Constructor(t *testing.T) {
+	packageInfo := withTypeInfos(parsePackageInfo(t, resourceModelSource))
+
+	assert.Equal(t, &ResourceModel{StructType: "KeyVaultDataSourceModel", Fields: []ModelField{
+		{Field: "VaultUri", Type: "string", Attribute: "vault_uri"},
+	}}, extractTypedModelFromPackage("KeyVaultDataSource", packageInfo))
+}
+
+func TestExtractTypedModelFromPackage_Unresolved(t *testing.T) {
+	packageInfo := withTypeInfos(parsePackageInfo(t, resourceModelSource))
+
+	assert.Nil(t, extractTypedModelFromPackage("KeyVaultUntypedResource", packageInfo))
+	assert.Nil(t, extractTypedModelFromPackage("MissingResource", packageInfo))
+}
+
+func TestNewTerraformResourceInfo_Model(t *testing.T) {
+	model := &ResourceModel{StructType: "KeyVaultResourceModel", Fields: []ModelField{{Field: "Name", Type: "string", Attribute: "name"}}}
+	serviceReg := ServiceRegistration{
+		ResourceTerraformTypes: map[string]string{"KeyVaultResource": "azurerm_key_vault"},
+		ResourceModels:         map[string]*ResourceModel{"azurerm_key_vault": model},
+	}
+
+	resource := newTerraformResourceInfo("azurerm_key_vault", "KeyVaultResource", "Resources", "modern_sdk", serviceReg)
+
+	assert.Same(t, model, resource.Model)
+}
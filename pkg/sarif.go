@@ -0,0 +1,232 @@
+package pkg
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// SARIFVersion is the version of the SARIF logs BuildSARIFLog produces
+const SARIFVersion = "2.1.0"
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// Rules of the findings reported in SARIF logs
+const (
+	SARIFRuleScanPanic              = "scan-panic"              // An extraction panicked on unexpected source
+	SARIFRuleParseError             = "parse-error"             // A service package couldn't be loaded
+	SARIFRuleEmptyPackage           = "empty-package"           // A service has no Go files or no registrations
+	SARIFRuleUnresolvedRegistration = "unresolved-registration" // A registration whose type or functions couldn't be resolved
+	SARIFRuleDuplicateRegistration  = "duplicate-registration"  // A Terraform type registered more than once
+	SARIFRuleOrphanedImplementation = "orphaned-implementation" // A resource or data source no registration references
+	SARIFRuleMissingDocumentation   = "missing-documentation"   // A resource or data source without website docs
+	SARIFRuleExtractorError         = "extractor-error"         // A custom Extractor returned an error
+)
+
+var sarifRules = []SARIFRule{
+	{ID: SARIFRuleScanPanic, ShortDescription: SARIFMessage{Text: "Extraction panicked on unexpected source"}, DefaultConfiguration: SARIFRuleConfiguration{Level: "error"}},
+	{ID: SARIFRuleParseError, ShortDescription: SARIFMessage{Text: "Service package couldn't be loaded"}, DefaultConfiguration: SARIFRuleConfiguration{Level: "error"}},
+	{ID: SARIFRuleEmptyPackage, ShortDescription: SARIFMessage{Text: "Service package has no registrations"}, DefaultConfiguration: SARIFRuleConfiguration{Level: "note"}},
+	{ID: SARIFRuleUnresolvedRegistration, ShortDescription: SARIFMessage{Text: "Registration couldn't be resolved"}, DefaultConfiguration: SARIFRuleConfiguration{Level: "warning"}},
+	{ID: SARIFRuleDuplicateRegistration, ShortDescription: SARIFMessage{Text: "Terraform type registered more than once"}, DefaultConfiguration: SARIFRuleConfiguration{Level: "error"}},
+	{ID: SARIFRuleOrphanedImplementation, ShortDescription: SARIFMessage{Text: "Resource or data source implemented but never registered"}, DefaultConfiguration: SARIFRuleConfiguration{Level: "warning"}},
+	{ID: SARIFRuleMissingDocumentation, ShortDescription: SARIFMessage{Text: "Resource or data source without website documentation"}, DefaultConfiguration: SARIFRuleConfiguration{Level: "warning"}},
+	{ID: SARIFRuleExtractorError, ShortDescription: SARIFMessage{Text: "Custom extractor failed"}, DefaultConfiguration: SARIFRuleConfiguration{Level: "warning"}},
+}
+
+// sarifWarningRules maps the kinds of scan warnings to their rules
+var sarifWarningRules = map[string]string{
+	ScanWarningPanic:                  SARIFRuleScanPanic,
+	ScanWarningParseError:             SARIFRuleParseError,
+	ScanWarningEmptyPackage:           SARIFRuleEmptyPackage,
+	ScanWarningUnresolvedRegistration: SARIFRuleUnresolvedRegistration,
+	ScanWarningExtractorError:         SARIFRuleExtractorError,
+}
+
+// SARIFLog is a Static Analysis Results Interchange Format log of the findings of a scan, which code scanning
+// services such as GitHub's show as annotations on the files of pull requests
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"` // "2.1.0"
+	Runs    []SARIFRun `json:"runs"`    // A single run
+}
+
+// SARIFRun is the run of the indexer producing the findings
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes the indexer and the rules of its findings
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver is the indexer
+type SARIFDriver struct {
+	Name           string      `json:"name"`           // "terraform-provider-azurerm-index"
+	InformationURI string      `json:"informationUri"` // "https://github.com/lonegunmanb/terraform-provider-azurerm-index"
+	Rules          []SARIFRule `json:"rules"`
+}
+
+// SARIFRule is a kind of finding
+type SARIFRule struct {
+	ID                   string                 `json:"id"` // "duplicate-registration"
+	ShortDescription     SARIFMessage           `json:"shortDescription"`
+	DefaultConfiguration SARIFRuleConfiguration `json:"defaultConfiguration"`
+}
+
+// SARIFRuleConfiguration holds the level of the findings of a rule
+type SARIFRuleConfiguration struct {
+	Level string `json:"level"` // "error", "warning" or "note"
+}
+
+// SARIFMessage is the text of a finding or rule
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFResult is a finding
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"` // "duplicate-registration"
+	Level     string          `json:"level"`  // "error", "warning" or "note"
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations,omitempty"` // Every registration of duplicates, a single file otherwise
+}
+
+// SARIFLocation locates a finding in the provider repository
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+// SARIFPhysicalLocation is a file, and the line in the file when known
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+}
+
+// SARIFArtifactLocation is the path of a file relative to the root of the provider repository
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"` // "internal/services/keyvault/key_vault_resource.go"
+}
+
+// SARIFRegion is the line of a finding
+type SARIFRegion struct {
+	StartLine int `json:"startLine"` // 42
+}
+
+// BuildSARIFLog reports the scan warnings, the duplicate registrations, the orphaned implementations and, when
+// documentation was linked, the undocumented resources and data sources as a SARIF log. Files are located under
+// scanPath like the source locations of documents, scan from the root of the provider repository with a relative scan
+// path such as "internal/services" for them to match the files of the repository.
+func (index *TerraformProviderIndex) BuildSARIFLog(scanPath string) SARIFLog {
+	root := filepath.ToSlash(filepath.Clean(scanPath))
+	location := func(service, fileName string, line int) SARIFLocation {
+		return sarifLocation(path.Join(root, service, fileName), line)
+	}
+
+	results := []SARIFResult{}
+	for _, warning := range index.Warnings {
+		rule := sarifWarningRules[warning.Kind]
+		if rule == "" {
+			continue
+		}
+		results = append(results, SARIFResult{
+			RuleID:    rule,
+			Level:     sarifRuleLevel(rule),
+			Message:   SARIFMessage{Text: fmt.Sprintf("%s: %s", warning.Service, warning.Message)},
+			Locations: []SARIFLocation{location(warning.Service, warning.File, 0)},
+		})
+	}
+	for _, duplicate := range index.BuildDuplicatesReport() {
+		var locations []SARIFLocation
+		var registrations []string
+		for _, registration := range duplicate.Registrations {
+			locations = append(locations, location(registration.Service, registration.FileName, 0))
+			registrations = append(registrations, path.Join(registration.Service, registration.FileName))
+		}
+		results = append(results, SARIFResult{
+			RuleID:    SARIFRuleDuplicateRegistration,
+			Level:     sarifRuleLevel(SARIFRuleDuplicateRegistration),
+			Message:   SARIFMessage{Text: fmt.Sprintf("%s is registered more than once (%s), only the last registration is indexed", duplicate.TerraformType, strings.Join(registrations, ", "))},
+			Locations: locations,
+		})
+	}
+	for _, orphan := range index.BuildOrphansReport() {
+		results = append(results, SARIFResult{
+			RuleID:    SARIFRuleOrphanedImplementation,
+			Level:     sarifRuleLevel(SARIFRuleOrphanedImplementation),
+			Message:   SARIFMessage{Text: fmt.Sprintf("%s is implemented but no registration method of the %s service registers it", orphan.Name, orphan.Service)},
+			Locations: []SARIFLocation{location(orphan.Service, orphan.FileName, orphan.Line)},
+		})
+	}
+	if index.Documentation != nil {
+		for _, entry := range index.Documentation.Undocumented {
+			result := SARIFResult{
+				RuleID:  SARIFRuleMissingDocumentation,
+				Level:   sarifRuleLevel(SARIFRuleMissingDocumentation),
+				Message: SARIFMessage{Text: fmt.Sprintf("%s has no documentation in %s", entry.TerraformType, index.Documentation.DocsPath)},
+			}
+			if declaration := index.declarationOf(entry.Kind, entry.TerraformType); declaration.SourceFile != "" {
+				result.Locations = []SARIFLocation{sarifLocation(declaration.SourceFile, declaration.Line)}
+			}
+			results = append(results, result)
+		}
+	}
+
+	return SARIFLog{
+		Schema:  sarifSchema,
+		Version: SARIFVersion,
+		Runs: []SARIFRun{{
+			Tool: SARIFTool{Driver: SARIFDriver{
+				Name:           "terraform-provider-azurerm-index",
+				InformationURI: "https://github.com/lonegunmanb/terraform-provider-azurerm-index",
+				Rules:          sarifRules,
+			}},
+			Results: results,
+		}},
+	}
+}
+
+// WriteSARIFFile writes the SARIF log of the findings to filePath, see BuildSARIFLog
+func (index *TerraformProviderIndex) WriteSARIFFile(filePath, scanPath string) error {
+	return index.writeJSONFile(filePath, index.BuildSARIFLog(scanPath))
+}
+
+// sarifLocation locates a finding in a file, at a line unless the line is 0
+func sarifLocation(uri string, line int) SARIFLocation {
+	physical := SARIFPhysicalLocation{ArtifactLocation: SARIFArtifactLocation{URI: uri}}
+	if line > 0 {
+		physical.Region = &SARIFRegion{StartLine: line}
+	}
+	return SARIFLocation{PhysicalLocation: physical}
+}
+
+// sarifRuleLevel returns the default level of a rule
+func sarifRuleLevel(ruleID string) string {
+	for _, rule := range sarifRules {
+		if rule.ID == ruleID {
+			return rule.DefaultConfiguration.Level
+		}
+	}
+	return "warning"
+}
+
+// declarationOf returns the source location of the declaration of a resource or data source, an empty location when
+// unknown
+func (index *TerraformProviderIndex) declarationOf(kind, terraformType string) SourceLocation {
+	for _, service := range index.Services {
+		switch kind {
+		case DocumentKindResource:
+			if sources, ok := service.ResourceSources[terraformType]; ok {
+				return sources.declaration()
+			}
+		case DocumentKindDataSource:
+			if sources, ok := service.DataSourceSources[terraformType]; ok {
+				return sources.declaration()
+			}
+		}
+	}
+	return SourceLocation{}
+}
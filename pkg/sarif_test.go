@@ -0,0 +1,73 @@
+package pkg
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sarifTestIndex(t *testing.T) *TerraformProviderIndex {
+	index := orphansTestIndex(t)
+	index.Services[0].RegistrationFiles = map[string]string{"resourceKeyVault": "registration.go", "KeyVaultCertificateContactsResource": "registration.go"}
+	index.Services[0].ResourceSources = map[string]*SourceLocations{
+		"azurerm_key_vault": {Declaration: &SourceLocation{SourceFile: "internal/services/keyvault/key_vault_resource.go", Line: 15}},
+	}
+	index.Services = append(index.Services, ServiceRegistration{
+		ServiceName:        "managedhsm",
+		SupportedResources: map[string]string{"azurerm_key_vault": "resourceManagedHSMKeyVault"},
+		RegistrationFiles:  map[string]string{"resourceManagedHSMKeyVault": "registration.go"},
+	})
+	index.Warnings = []ScanWarning{{Service: "storage", File: "registration.go", Kind: ScanWarningUnresolvedRegistration, Message: "Terraform type of StorageAccountResource couldn't be resolved"}}
+	index.Documentation = &DocumentationReport{DocsPath: "website/docs", Undocumented: []UndocumentedEntry{{Kind: DocumentKindResource, TerraformType: "azurerm_key_vault"}}}
+	return index
+}
+
+func TestBuildSARIFLog(t *testing.T) {
+	log := sarifTestIndex(t).BuildSARIFLog(filepath.Join(".", "internal", "services"))
+
+	assert.Equal(t, SARIFVersion, log.Version)
+	require.Len(t, log.Runs, 1)
+	assert.Len(t, log.Runs[0].Tool.Driver.Rules, 8)
+
+	results := make(map[string][]SARIFResult)
+	for _, result := range log.Runs[0].Results {
+		results[result.RuleID] = append(results[result.RuleID], result)
+	}
+
+	require.Len(t, results[SARIFRuleUnresolvedRegistration], 1)
+	assert.Equal(t, "warning", results[SARIFRuleUnresolvedRegistration][0].Level)
+	assert.Equal(t, []SARIFLocation{sarifLocation("internal/services/storage/registration.go", 0)}, results[SARIFRuleUnresolvedRegistration][0].Locations)
+
+	require.Len(t, results[SARIFRuleDuplicateRegistration], 1)
+	assert.Equal(t, "error", results[SARIFRuleDuplicateRegistration][0].Level)
+	assert.Equal(t, []SARIFLocation{
+		sarifLocation("internal/services/keyvault/registration.go", 0),
+		sarifLocation("internal/services/managedhsm/registration.go", 0),
+	}, results[SARIFRuleDuplicateRegistration][0].Locations)
+
+	require.Len(t, results[SARIFRuleOrphanedImplementation], 4)
+	assert.Equal(t, []SARIFLocation{sarifLocation("internal/services/keyvault/key_vault_resource.go", 50)}, results[SARIFRuleOrphanedImplementation][0].Locations)
+
+	require.Len(t, results[SARIFRuleMissingDocumentation], 1)
+	assert.Equal(t, []SARIFLocation{sarifLocation("internal/services/keyvault/key_vault_resource.go", 15)}, results[SARIFRuleMissingDocumentation][0].Locations)
+}
+
+func TestWriteSARIFFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	require.NoError(t, (&TerraformProviderIndex{Output: OutputConfig{Fs: fs}}).WriteSARIFFile("/test/findings.sarif", "internal/services"))
+
+	data, err := afero.ReadFile(fs, "/test/findings.sarif")
+	require.NoError(t, err)
+	var log map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &log))
+	assert.Equal(t, "2.1.0", log["version"])
+	assert.Contains(t, log, "$schema")
+	runs := log["runs"].([]interface{})
+	require.Len(t, runs, 1)
+	assert.Equal(t, []interface{}{}, runs[0].(map[string]interface{})["results"])
+}
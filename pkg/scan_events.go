@@ -0,0 +1,122 @@
+package pkg
+
+import (
+	"sync"
+	"time"
+)
+
+// ScanEventType is the kind of a ScanEvent
+type ScanEventType string
+
+// Kinds of scan events
+const (
+	ScanEventServiceStarted   ScanEventType = "service_started"   // A worker started scanning a service
+	ScanEventServiceCompleted ScanEventType = "service_completed" // A service was scanned, with or without warnings
+	ScanEventWarning          ScanEventType = "warning"           // A problem found while scanning that didn't stop the scan
+	ScanEventDocumentWritten  ScanEventType = "document_written"  // A per-resource document was written
+)
+
+// ScanEvent is a structured event of a scan, for embedding applications driving UIs, logging and metrics
+type ScanEvent struct {
+	Type          ScanEventType // "service_completed"
+	Time          time.Time     // When the event happened
+	Service       string        // "keyvault", set for every event type
+	Warning       *ScanWarning  // Set for ScanEventWarning
+	Kind          string        // "resources", "datasources" or "ephemeral", set for ScanEventDocumentWritten
+	TerraformType string        // "azurerm_key_vault", set for ScanEventDocumentWritten
+	Path          string        // "index/resources/azurerm_key_vault.json", set for ScanEventDocumentWritten
+}
+
+// EventBus delivers scan events to channel subscribers. Events are delivered in publishing order per subscriber,
+// and publishing blocks until every subscriber has room for the event, so subscribers must keep draining their
+// channel until it is closed or they unsubscribe. A nil *EventBus publishes nothing.
+type EventBus struct {
+	// Publishers hold the read lock while delivering, closing a channel takes the write lock
+	mu          sync.RWMutex
+	subscribers map[int]*eventSubscriber
+	nextID      int
+	closed      bool
+}
+
+// eventSubscriber is a subscription of an EventBus
+type eventSubscriber struct {
+	events chan ScanEvent
+	// done is closed first when unsubscribing, releasing publishers blocked on a subscriber that stopped reading
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+func (s *eventSubscriber) stop() {
+	s.doneOnce.Do(func() {
+		close(s.done)
+	})
+}
+
+// NewEventBus creates an event bus without subscribers
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: make(map[int]*eventSubscriber)}
+}
+
+// Subscribe returns a channel receiving every event published from now on, buffering up to buffer events, and a
+// function unsubscribing and closing the channel. The channel is also closed when the bus is closed.
+func (b *EventBus) Subscribe(buffer int) (<-chan ScanEvent, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subscriber := &eventSubscriber{events: make(chan ScanEvent, buffer), done: make(chan struct{})}
+	if b.closed {
+		close(subscriber.events)
+		return subscriber.events, func() {}
+	}
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = subscriber
+	return subscriber.events, func() {
+		subscriber.stop()
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[id]; ok {
+			delete(b.subscribers, id)
+			close(subscriber.events)
+		}
+	}
+}
+
+// Publish delivers an event to every subscriber, stamping its time when it has none
+func (b *EventBus) Publish(event ScanEvent) {
+	if b == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, subscriber := range b.subscribers {
+		select {
+		case subscriber.events <- event:
+		case <-subscriber.done:
+		}
+	}
+}
+
+// Close closes the channels of all subscribers, later subscriptions receive a closed channel
+func (b *EventBus) Close() {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	for _, subscriber := range b.subscribers {
+		subscriber.stop()
+	}
+	b.mu.RUnlock()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for id, subscriber := range b.subscribers {
+		delete(b.subscribers, id)
+		close(subscriber.events)
+	}
+}
@@ -0,0 +1,120 @@
+package pkg
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// collectEvents drains a subscription in the background, the returned function waits for the channel to close
+func collectEvents(events <-chan ScanEvent) func() []ScanEvent {
+	var collected []ScanEvent
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for event := range events {
+			collected = append(collected, event)
+		}
+	}()
+	return func() []ScanEvent {
+		wg.Wait()
+		return collected
+	}
+}
+
+func TestEventBus_PublishSubscribe(t *testing.T) {
+	bus := NewEventBus()
+	first, _ := bus.Subscribe(1)
+	second, _ := bus.Subscribe(1)
+	firstEvents, secondEvents := collectEvents(first), collectEvents(second)
+
+	bus.Publish(ScanEvent{Type: ScanEventServiceStarted, Service: "keyvault"})
+	bus.Publish(ScanEvent{Type: ScanEventServiceCompleted, Service: "keyvault"})
+	bus.Close()
+
+	for _, events := range [][]ScanEvent{firstEvents(), secondEvents()} {
+		require.Len(t, events, 2)
+		assert.Equal(t, ScanEventServiceStarted, events[0].Type)
+		assert.Equal(t, ScanEventServiceCompleted, events[1].Type)
+		assert.False(t, events[0].Time.IsZero())
+	}
+}
+
+func TestEventBus_Unsubscribe(t *testing.T) {
+	bus := NewEventBus()
+	events, unsubscribe := bus.Subscribe(0)
+
+	// Unsubscribing releases a publisher blocked on a subscriber that stopped reading
+	published := make(chan struct{})
+	go func() {
+		bus.Publish(ScanEvent{Type: ScanEventServiceStarted, Service: "keyvault"})
+		close(published)
+	}()
+	unsubscribe()
+	<-published
+
+	_, open := <-events
+	assert.False(t, open)
+	unsubscribe()
+	bus.Close()
+}
+
+func TestEventBus_SubscribeAfterClose(t *testing.T) {
+	bus := NewEventBus()
+	bus.Close()
+
+	events, _ := bus.Subscribe(1)
+	_, open := <-events
+	assert.False(t, open)
+}
+
+func TestEventBus_Nil(t *testing.T) {
+	var bus *EventBus
+	bus.Publish(ScanEvent{Type: ScanEventWarning})
+	bus.Close()
+}
+
+func TestScanner_Events(t *testing.T) {
+	bus := NewEventBus()
+	events, _ := bus.Subscribe(16)
+	collected := collectEvents(events)
+	testHarnessPath := filepath.Join("testharness", "internal", "services")
+
+	index, err := Scanner{Services: []string{"keyvault", "storage"}, Events: bus}.Scan(testHarnessPath, "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+	require.NoError(t, err)
+	index.Output.Fs = afero.NewMemMapFs()
+	require.NoError(t, index.WriteIndexFiles("/index", nil))
+	bus.Close()
+
+	started := make(map[string]bool)
+	completed := make(map[string]bool)
+	written := make(map[string]bool)
+	warnings := 0
+	for _, event := range collected() {
+		switch event.Type {
+		case ScanEventServiceStarted:
+			assert.False(t, completed[event.Service], "service %s started after it completed", event.Service)
+			started[event.Service] = true
+		case ScanEventServiceCompleted:
+			assert.True(t, started[event.Service], "service %s completed before it started", event.Service)
+			completed[event.Service] = true
+		case ScanEventWarning:
+			require.NotNil(t, event.Warning)
+			assert.Equal(t, event.Service, event.Warning.Service)
+			warnings++
+		case ScanEventDocumentWritten:
+			assert.Equal(t, filepath.Join("/index", event.Kind, event.TerraformType+".json"), event.Path)
+			written[event.Kind+"/"+event.TerraformType] = true
+		}
+	}
+
+	assert.Equal(t, map[string]bool{"keyvault": true, "storage": true}, started)
+	assert.Equal(t, started, completed)
+	assert.Equal(t, len(index.Warnings), warnings)
+	assert.Len(t, written, len(index.Documents()))
+}
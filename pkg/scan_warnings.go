@@ -0,0 +1,138 @@
+package pkg
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Kinds of scan warnings
+const (
+	ScanWarningPanic                  = "panic"                   // An extraction panicked on unexpected source
+	ScanWarningParseError             = "parse_error"             // The service package couldn't be loaded
+	ScanWarningEmptyPackage           = "empty_package"           // The service has no Go files or no registrations
+	ScanWarningUnresolvedRegistration = "unresolved_registration" // A registration whose type or functions couldn't be resolved
+	ScanWarningExtractorError         = "extractor_error"         // A custom Extractor returned an error
+)
+
+// ScanWarning is a problem found while scanning a service that didn't stop the scan
+type ScanWarning struct {
+	Service string `json:"service"`        // "keyvault"
+	File    string `json:"file,omitempty"` // "key_vault_resource.go", empty when the problem isn't tied to a single file
+	Kind    string `json:"kind"`           // "panic", "parse_error", "empty_package" or "unresolved_registration"
+	Message string `json:"message"`        // "panic during schema extraction: runtime error: index out of range [1] with length 1"
+}
+
+// ScanReport lists the problems found while scanning, grouped by service
+type ScanReport struct {
+	WarningCount int                      `json:"warning_count"` // 3
+	Services     map[string][]ScanWarning `json:"services"`      // {"keyvault": [{"kind": "unresolved_registration", ...}]}
+}
+
+// scanWarnings collects warnings of the scanning workers
+type scanWarnings struct {
+	mu       sync.Mutex
+	warnings []ScanWarning
+	events   *EventBus // Each warning is also published as a ScanEventWarning, may be nil
+}
+
+func (w *scanWarnings) add(warning ScanWarning) {
+	w.mu.Lock()
+	w.warnings = append(w.warnings, warning)
+	w.mu.Unlock()
+	w.events.Publish(ScanEvent{Type: ScanEventWarning, Service: warning.Service, Warning: &warning})
+}
+
+// sorted returns the collected warnings ordered by service, file and message
+func (w *scanWarnings) sorted() []ScanWarning {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	warnings := append([]ScanWarning(nil), w.warnings...)
+	sort.SliceStable(warnings, func(i, j int) bool {
+		if warnings[i].Service != warnings[j].Service {
+			return warnings[i].Service < warnings[j].Service
+		}
+		if warnings[i].File != warnings[j].File {
+			return warnings[i].File < warnings[j].File
+		}
+		return warnings[i].Message < warnings[j].Message
+	})
+	return warnings
+}
+
+// extractionGuard runs the extractions of a service, turning panics of AST walkers on unexpected source into warnings
+type extractionGuard struct {
+	service  string
+	warnings *scanWarnings
+}
+
+// run runs a single extraction step and reports whether it completed, a panic is recorded with the service, file
+// and step it happened in
+func (g extractionGuard) run(file, step string, extract func()) (completed bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			g.warn(ScanWarningPanic, file, fmt.Sprintf("panic during %s extraction: %v", step, r))
+		}
+	}()
+	extract()
+	return true
+}
+
+// warn records a warning of the guarded service
+func (g extractionGuard) warn(kind, file, message string) {
+	g.warnings.add(ScanWarning{
+		Service: g.service,
+		File:    file,
+		Kind:    kind,
+		Message: message,
+	})
+}
+
+// reportUnresolvedRegistrations records registrations the extractions couldn't resolve, their documents lack the
+// Terraform type or the indexes of their functions
+func (g extractionGuard) reportUnresolvedRegistrations(serviceReg *ServiceRegistration) {
+	for terraformType, registrationMethod := range serviceReg.SupportedResources {
+		if serviceReg.ResourceCRUDMethods[terraformType] == nil {
+			g.warn(ScanWarningUnresolvedRegistration, "", fmt.Sprintf("CRUD functions of resource %s (%s) not found", terraformType, registrationMethod))
+		}
+	}
+	for terraformType, registrationMethod := range serviceReg.SupportedDataSources {
+		if serviceReg.DataSourceMethods[terraformType] == nil {
+			g.warn(ScanWarningUnresolvedRegistration, "", fmt.Sprintf("read function of data source %s (%s) not found", terraformType, registrationMethod))
+		}
+	}
+	for _, structType := range serviceReg.Resources {
+		if _, exists := serviceReg.ResourceTerraformTypes[structType]; !exists {
+			g.warn(ScanWarningUnresolvedRegistration, "", fmt.Sprintf("terraform type of resource %s not resolved", structType))
+		}
+	}
+	for _, structType := range serviceReg.DataSources {
+		if _, exists := serviceReg.DataSourceTerraformTypes[structType]; !exists {
+			g.warn(ScanWarningUnresolvedRegistration, "", fmt.Sprintf("terraform type of data source %s not resolved", structType))
+		}
+	}
+	for _, structType := range convertFunctionNamesToStructNames(serviceReg.EphemeralFunctions, serviceReg.Package) {
+		if _, exists := serviceReg.EphemeralTerraformTypes[structType]; !exists {
+			g.warn(ScanWarningUnresolvedRegistration, "", fmt.Sprintf("terraform type of ephemeral resource %s not resolved", structType))
+		}
+	}
+}
+
+// BuildScanReport groups the warnings of the scan by service
+func (index *TerraformProviderIndex) BuildScanReport() ScanReport {
+	report := ScanReport{
+		WarningCount: len(index.Warnings),
+		Services:     make(map[string][]ScanWarning),
+	}
+	for _, warning := range index.Warnings {
+		report.Services[warning.Service] = append(report.Services[warning.Service], warning)
+	}
+	return report
+}
+
+// writeScanReportFile writes scan-report.json
+func (index *TerraformProviderIndex) writeScanReportFile(outputDir string) error {
+	return index.writeJSONFile(filepath.Join(outputDir, "scan-report.json"), index.BuildScanReport())
+}
@@ -0,0 +1,93 @@
+package pkg
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractionGuard_RecoversPanics(t *testing.T) {
+	warnings := &scanWarnings{}
+	guard := extractionGuard{service: "network", warnings: warnings}
+
+	var steps []string
+	guard.run("virtual_network_resource.go", "registration", func() {
+		steps = append(steps, "registration")
+		var file *struct{ Name string }
+		_ = file.Name
+	})
+	guard.run("", "schema", func() {
+		steps = append(steps, "schema")
+		panic("unexpected schema literal")
+	})
+	guard.run("", "timeouts", func() {
+		steps = append(steps, "timeouts")
+	})
+
+	assert.Equal(t, []string{"registration", "schema", "timeouts"}, steps)
+	recorded := warnings.sorted()
+	if assert.Len(t, recorded, 2) {
+		assert.Equal(t, ScanWarning{Service: "network", Kind: ScanWarningPanic, Message: "panic during schema extraction: unexpected schema literal"}, recorded[0])
+		assert.Equal(t, "network", recorded[1].Service)
+		assert.Equal(t, "virtual_network_resource.go", recorded[1].File)
+		assert.Contains(t, recorded[1].Message, "panic during registration extraction: runtime error")
+	}
+}
+
+func TestScanWarnings_Sorted(t *testing.T) {
+	warnings := &scanWarnings{}
+	warnings.add(ScanWarning{Service: "storage", File: "b.go"})
+	warnings.add(ScanWarning{Service: "keyvault", File: "b.go"})
+	warnings.add(ScanWarning{Service: "storage", File: "a.go"})
+
+	assert.Equal(t, []ScanWarning{
+		{Service: "keyvault", File: "b.go"},
+		{Service: "storage", File: "a.go"},
+		{Service: "storage", File: "b.go"},
+	}, warnings.sorted())
+}
+
+func TestExtractionGuard_ReportUnresolvedRegistrations(t *testing.T) {
+	warnings := &scanWarnings{}
+	guard := extractionGuard{service: "keyvault", warnings: warnings}
+	serviceReg := ServiceRegistration{
+		SupportedResources:   map[string]string{"azurerm_key_vault": "resourceKeyVault", "azurerm_key_vault_key": "resourceKeyVaultKey"},
+		SupportedDataSources: map[string]string{"azurerm_key_vault": "dataSourceKeyVault"},
+		Resources:            []string{"KeyVaultResource", "unconventional"},
+		ResourceCRUDMethods:  map[string]*LegacyResourceCRUDFunctions{"azurerm_key_vault": {CreateMethod: "resourceKeyVaultCreate"}},
+		DataSourceMethods:    map[string]*LegacyDataSourceMethods{"azurerm_key_vault": {ReadMethod: "dataSourceKeyVaultRead"}},
+		ResourceTerraformTypes: map[string]string{
+			"KeyVaultResource": "azurerm_key_vault_modern",
+		},
+	}
+
+	guard.reportUnresolvedRegistrations(&serviceReg)
+
+	assert.Equal(t, []ScanWarning{
+		{Service: "keyvault", Kind: ScanWarningUnresolvedRegistration, Message: "CRUD functions of resource azurerm_key_vault_key (resourceKeyVaultKey) not found"},
+		{Service: "keyvault", Kind: ScanWarningUnresolvedRegistration, Message: "terraform type of resource unconventional not resolved"},
+	}, warnings.sorted())
+}
+
+func TestWriteScanReportFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	index := createTestTerraformProviderIndex()
+	index.Output.Fs = fs
+	index.Warnings = []ScanWarning{
+		{Service: "keyvault", Kind: ScanWarningUnresolvedRegistration, Message: "terraform type of resource unconventional not resolved"},
+		{Service: "legacy", Kind: ScanWarningEmptyPackage, Message: "no registrations found"},
+		{Service: "legacy", File: "broken.go", Kind: ScanWarningPanic, Message: "panic during registration extraction: boom"},
+	}
+	require.NoError(t, index.writeScanReportFile("/output"))
+
+	content, err := afero.ReadFile(fs, "/output/scan-report.json")
+	require.NoError(t, err)
+	var report ScanReport
+	require.NoError(t, json.Unmarshal(content, &report))
+	assert.Equal(t, 3, report.WarningCount)
+	assert.Len(t, report.Services["keyvault"], 1)
+	assert.Equal(t, []ScanWarning{index.Warnings[1], index.Warnings[2]}, report.Services["legacy"])
+}
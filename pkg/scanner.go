@@ -0,0 +1,57 @@
+package pkg
+
+// Scanner scans the service packages of a Terraform provider into an index
+type Scanner struct {
+	Workers int // 4, number of services scanned and files written in parallel, one per CPU when zero
+	// Strategies inferring the Terraform types of typed structs, tried in order, DefaultTerraformTypeStrategies when empty
+	TerraformTypeStrategies []TerraformTypeStrategy
+	// Names of the services to scan, all services when empty, e.g. to generate a shard merged by MergeIndexDirs
+	Services []string
+	// Bus the scan and the writing of the index publish structured events to, none are published when nil
+	Events *EventBus
+	// Maps the service directories to the import paths recorded as their package paths, and import paths of provider
+	// packages back to directories. When nil, or for directories it doesn't cover, the package path is the base
+	// package path joined with the scanned directory and the package name, which assumes a standard module layout.
+	PackagePaths PackagePathMapper
+	// Type-checks the service packages with go/packages to resolve registrations through aliased imports, dot-imports
+	// and local variables, slower than the AST scan and needs the dependencies of the provider module downloaded
+	Typed bool
+	// Extractors run on every service after the extractors registered with RegisterExtractor
+	Extractors []Extractor
+	// Hooks enriching or vetoing the documents of the scanned services, also called when the index is written
+	Hooks DocumentHooks
+	// Writes the per-resource files of each service as soon as it is scanned and drops its parsed package, the index is
+	// kept in memory whole when nil
+	Stream *StreamWriter
+	// Build tags and target platform selecting the files scanned, so that the index matches what compiles into a given
+	// provider release, the go command's defaults for the host when zero
+	Build BuildConstraints
+	// Files left out of the scanned service packages, such as test harnesses and generated files
+	Exclude FileExclusions
+	// Number of files of a service package parsed in parallel, one per CPU when zero, so that the largest services
+	// don't dominate the scan time
+	ParseWorkers int
+}
+
+// Scan scans the service directories under dir, the returned index writes its files with the same parallelism
+func (s Scanner) Scan(dir, basePkgUrl, version string, progressCallback ProgressCallback) (*TerraformProviderIndex, error) {
+	var serviceFilter func(serviceName string) bool
+	if len(s.Services) > 0 {
+		selected := make(map[string]bool)
+		for _, service := range s.Services {
+			selected[service] = true
+		}
+		serviceFilter = func(serviceName string) bool {
+			return selected[serviceName]
+		}
+	}
+	index, err := scanTerraformProviderServices(dir, basePkgUrl, version, serviceFilter, s, progressCallback)
+	if err != nil {
+		return nil, err
+	}
+	index.Output.Workers = s.Workers
+	index.Events = s.Events
+	index.Hooks = s.Hooks
+	index.streamed = s.Stream
+	return index, nil
+}
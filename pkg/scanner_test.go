@@ -0,0 +1,37 @@
+package pkg
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanner_SingleWorker(t *testing.T) {
+	testHarnessPath := filepath.Join("testharness", "internal", "services")
+
+	index, err := Scanner{Workers: 1}.Scan(testHarnessPath, "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+	require.NoError(t, err)
+	defaultIndex, err := ScanTerraformProviderServices(testHarnessPath, "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, index.Output.Workers)
+	assert.Equal(t, defaultIndex.Statistics, index.Statistics)
+}
+
+func TestScanner_Services(t *testing.T) {
+	testHarnessPath := filepath.Join("testharness", "internal", "services")
+
+	index, err := Scanner{Services: []string{"keyvault", "storage"}}.Scan(testHarnessPath, "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+	require.NoError(t, err)
+
+	var services []string
+	for _, service := range index.Services {
+		services = append(services, service.ServiceName)
+	}
+	assert.Equal(t, []string{"keyvault", "storage"}, services)
+	assert.Equal(t, 2, index.Statistics.ServiceCount)
+	assert.Equal(t, 2, index.ScannedServices)
+	assert.Positive(t, index.ScanDuration)
+}
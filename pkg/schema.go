@@ -0,0 +1,501 @@
+package pkg
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// maxSchemaResolveDepth limits how many helper function calls are followed when resolving a schema map
+const maxSchemaResolveDepth = 5
+
+// maxSchemaBlockDepth limits how deeply nested blocks are followed
+const maxSchemaBlockDepth = 10
+
+// SchemaAttribute represents a single attribute declared in a resource schema
+type SchemaAttribute struct {
+	Name          string   `json:"name" protobuf:"1"`                     // "lock_level"
+	Type          string   `json:"type,omitempty" protobuf:"2"`           // "TypeString"
+	SchemaFunc    string   `json:"schema_func,omitempty" protobuf:"3"`    // "commonschema.ResourceGroupName", set when the attribute is built by a helper
+	ValidateFuncs []string `json:"validate_funcs,omitempty" protobuf:"4"` // ["validation.StringInSlice"]
+	// Behaviour flags of attributes declared with a schema literal or a helper function flagging them by its name, such as
+	// commonschema.LocationOptional. Attributes of the Attributes method of typed resources are always Computed.
+	Required   bool   `json:"required,omitempty" protobuf:"5"`   // true
+	Optional   bool   `json:"optional,omitempty" protobuf:"6"`   // true
+	Computed   bool   `json:"computed,omitempty" protobuf:"7"`   // true
+	Deprecated string `json:"deprecated,omitempty" protobuf:"8"` // "`lock_level` has been deprecated in favour of `level`"
+	// Nesting depth of the attribute, 0 for top level attributes and 1 for attributes of their blocks
+	Depth int `json:"depth,omitempty" protobuf:"9"` // 1
+	// Attributes of the nested block declared with Elem: &pluginsdk.Resource{Schema: ...}
+	Block []SchemaAttribute `json:"block,omitempty" protobuf:"10"` // [{"name": "key_permissions", "type": "TypeList", "depth": 1}]
+	// Set for attributes declared with WriteOnly: true, which Terraform never persists to the plan or state
+	WriteOnly bool `json:"write_only,omitempty" protobuf:"11"` // true
+	// Value the provider fills in when the attribute isn't set, only known for literal defaults such as Default: 30 or
+	// the framework's Default: stringdefault.StaticString("Standard"), and the function computing it otherwise
+	Default     interface{} `json:"default,omitempty" protobuf:"12"`      // "Standard", 30 or false
+	DefaultFunc string      `json:"default_func,omitempty" protobuf:"13"` // "pluginsdk.EnvDefaultFunc"
+	// Set for attributes whose change replaces the resource: ForceNew: true, a RequiresReplace plan modifier of framework
+	// attributes or a schema helper forcing a new resource, such as commonschema.Location
+	ForceNew bool `json:"force_new,omitempty" protobuf:"14"` // true
+	// Constraints on other attributes declared by ConflictsWith, ExactlyOneOf, AtLeastOneOf and RequiredWith
+	Constraints []SchemaConstraint `json:"constraints,omitempty" protobuf:"15"` // [{"kind": "conflicts_with", "attributes": ["key_vault_secret_id"]}]
+	// Set for attributes declared with Sensitive: true, whose values Terraform redacts from its output
+	Sensitive bool `json:"sensitive,omitempty" protobuf:"16"` // true
+}
+
+// schemaEntry is a key/value pair of a schema map, with the function declaring it to resolve nested blocks
+type schemaEntry struct {
+	name  string
+	value ast.Expr
+	scope *ast.FuncDecl
+}
+
+// extractLegacyResourceSchemaFromPackage extracts the attributes of the Schema field of the pluginsdk.Resource
+// returned by a legacy registration function
+func extractLegacyResourceSchemaFromPackage(registrationMethod string, packageInfo *gophon.PackageInfo) []SchemaAttribute {
+	return schemaAttributesFromEntries(legacyResourceSchemaEntries(registrationMethod, packageInfo), packageInfo, 0)
+}
+
+// legacyResourceSchemaEntries resolves the entries of the Schema field of the pluginsdk.Resource returned by a legacy registration function
+func legacyResourceSchemaEntries(registrationMethod string, packageInfo *gophon.PackageInfo) []schemaEntry {
+	fn := findFunctionDecl(packageInfo, registrationMethod)
+	for _, compLit := range findResourceLiterals(fn) {
+		if schema := compositeLitField(compLit, "Schema"); schema != nil {
+			return resolveSchemaEntries(schema, fn, packageInfo, 0)
+		}
+	}
+	return nil
+}
+
+// extractTypedResourceSchemaFromPackage extracts the attributes returned by the Arguments and Attributes methods of a
+// typed resource, or the Attributes of the schema.Schema built by the Schema method of a framework resource
+func extractTypedResourceSchemaFromPackage(structName string, packageInfo *gophon.PackageInfo) []SchemaAttribute {
+	var entries []schemaEntry
+	exported := make(map[string]bool)
+	for _, methodName := range []string{"Arguments", "Attributes"} {
+		method := findMethodDecl(packageInfo, structName, methodName)
+		methodEntries := resolveSchemaEntries(firstReturnedExpr(method), method, packageInfo, 0)
+		for _, entry := range methodEntries {
+			exported[entry.name] = methodName == "Attributes"
+		}
+		entries = append(entries, methodEntries...)
+	}
+	if len(entries) == 0 {
+		entries = frameworkSchemaEntries(findMethodDecl(packageInfo, structName, "Schema"), packageInfo)
+	}
+
+	attributes := schemaAttributesFromEntries(entries, packageInfo, 0)
+	for i := range attributes {
+		// The Attributes method declares the exported attributes, which the resource computes
+		if exported[attributes[i].Name] {
+			attributes[i].Computed = true
+		}
+	}
+	return attributes
+}
+
+// frameworkSchemaEntries resolves the Attributes of the first schema.Schema literal of the Schema method of a
+// framework resource: resp.Schema = schema.Schema{Attributes: map[string]schema.Attribute{...}}
+func frameworkSchemaEntries(method *ast.FuncDecl, packageInfo *gophon.PackageInfo) []schemaEntry {
+	if method == nil || method.Body == nil {
+		return nil
+	}
+
+	var entries []schemaEntry
+	ast.Inspect(method.Body, func(n ast.Node) bool {
+		if entries != nil {
+			return false
+		}
+		compLit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		if attributes := compositeLitField(compLit, "Attributes"); attributes != nil {
+			entries = resolveSchemaEntries(attributes, method, packageInfo, 0)
+			return false
+		}
+		return true
+	})
+	return entries
+}
+
+// resolveSchemaEntries resolves the key/value pairs of a schema map expression, following:
+// - map literals: map[string]*pluginsdk.Schema{...}
+// - local variables, including entries added later with schema["name"] = ...
+// - calls to helper functions declared in the same package: resourceFooSchema()
+func resolveSchemaEntries(expr ast.Expr, scope *ast.FuncDecl, packageInfo *gophon.PackageInfo, depth int) []schemaEntry {
+	if expr == nil || depth > maxSchemaResolveDepth {
+		return nil
+	}
+
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		var entries []schemaEntry
+		for _, elt := range e.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			if name := stringLiteralValue(kv.Key); name != "" {
+				entries = append(entries, schemaEntry{name: name, value: kv.Value, scope: scope})
+			}
+		}
+		return entries
+	case *ast.Ident:
+		return resolveSchemaVariable(e.Name, scope, packageInfo, depth)
+	case *ast.CallExpr:
+		ident, ok := unwrapTypeArguments(e.Fun).(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		helper := findFunctionDecl(packageInfo, ident.Name)
+		return resolveSchemaEntries(firstReturnedExpr(helper), helper, packageInfo, depth+1)
+	}
+	return nil
+}
+
+// resolveSchemaVariable resolves a local schema map variable declared in the scope function
+func resolveSchemaVariable(name string, scope *ast.FuncDecl, packageInfo *gophon.PackageInfo, depth int) []schemaEntry {
+	if scope == nil || scope.Body == nil {
+		return nil
+	}
+
+	var entries []schemaEntry
+	ast.Inspect(scope.Body, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			for i, lhs := range s.Lhs {
+				if i >= len(s.Rhs) {
+					break
+				}
+				switch l := lhs.(type) {
+				case *ast.Ident:
+					// schema := map[string]*pluginsdk.Schema{...}
+					if l.Name == name {
+						entries = append(entries, resolveSchemaEntries(s.Rhs[i], scope, packageInfo, depth+1)...)
+					}
+				case *ast.IndexExpr:
+					// schema["name"] = &pluginsdk.Schema{...}
+					if ident, ok := l.X.(*ast.Ident); ok && ident.Name == name {
+						if key := stringLiteralValue(l.Index); key != "" {
+							entries = append(entries, schemaEntry{name: key, value: s.Rhs[i], scope: scope})
+						}
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for i, ident := range s.Names {
+				if ident.Name == name && i < len(s.Values) {
+					entries = append(entries, resolveSchemaEntries(s.Values[i], scope, packageInfo, depth+1)...)
+				}
+			}
+		}
+		return true
+	})
+	return entries
+}
+
+// schemaAttributesFromEntries converts schema map entries at the given nesting depth into attributes, later entries
+// override earlier ones
+func schemaAttributesFromEntries(entries []schemaEntry, packageInfo *gophon.PackageInfo, depth int) []SchemaAttribute {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var attributes []SchemaAttribute
+	positions := make(map[string]int)
+	for _, entry := range entries {
+		attribute := newSchemaAttribute(entry, packageInfo, depth)
+		if pos, exists := positions[entry.name]; exists {
+			attributes[pos] = attribute
+			continue
+		}
+		positions[entry.name] = len(attributes)
+		attributes = append(attributes, attribute)
+	}
+	return attributes
+}
+
+// newSchemaAttribute builds a SchemaAttribute from the value of a schema map entry, with the attributes of its nested block
+func newSchemaAttribute(entry schemaEntry, packageInfo *gophon.PackageInfo, depth int) SchemaAttribute {
+	attribute := SchemaAttribute{Name: entry.name, Depth: depth}
+	value := entry.value
+
+	if unaryExpr, ok := value.(*ast.UnaryExpr); ok && unaryExpr.Op == token.AND {
+		value = unaryExpr.X
+	}
+
+	switch v := value.(type) {
+	case *ast.CallExpr:
+		attribute.SchemaFunc = types.ExprString(unwrapTypeArguments(v.Fun))
+		attribute.Required, attribute.Optional, attribute.Computed = schemaFuncFlags(attribute.SchemaFunc)
+		attribute.ForceNew = isForceNewSchemaFunc(attribute.SchemaFunc)
+	case *ast.CompositeLit:
+		if typeExpr := compositeLitField(v, "Type"); typeExpr != nil {
+			attribute.Type = extractFunctionReference(typeExpr)
+		}
+		for _, field := range []string{"ValidateFunc", "ValidateDiagFunc"} {
+			attribute.ValidateFuncs = append(attribute.ValidateFuncs, extractValidateFunctions(compositeLitField(v, field))...)
+		}
+		attribute.Required = isTrueLiteral(compositeLitField(v, "Required"))
+		attribute.Optional = isTrueLiteral(compositeLitField(v, "Optional"))
+		attribute.Computed = isTrueLiteral(compositeLitField(v, "Computed"))
+		attribute.Deprecated = stringExprValue(compositeLitField(v, "Deprecated"))
+		attribute.WriteOnly = isTrueLiteral(compositeLitField(v, "WriteOnly"))
+		attribute.Sensitive = isTrueLiteral(compositeLitField(v, "Sensitive"))
+		attribute.ForceNew = isTrueLiteral(compositeLitField(v, "ForceNew")) || requiresReplace(compositeLitField(v, "PlanModifiers"))
+		attribute.Default = schemaDefaultValue(compositeLitField(v, "Default"))
+		attribute.Constraints = extractSchemaConstraints(v)
+		if defaultFunc := compositeLitField(v, "DefaultFunc"); defaultFunc != nil {
+			if call, ok := defaultFunc.(*ast.CallExpr); ok {
+				defaultFunc = call.Fun
+			}
+			attribute.DefaultFunc = types.ExprString(unwrapTypeArguments(defaultFunc))
+		}
+		if depth < maxSchemaBlockDepth {
+			blockEntries := resolveBlockEntries(compositeLitField(v, "Elem"), entry.scope, packageInfo, 0)
+			attribute.Block = schemaAttributesFromEntries(blockEntries, packageInfo, depth+1)
+		}
+	}
+
+	return attribute
+}
+
+// schemaFuncFlagsByName are the behaviour flags of schema helpers whose names don't tell them
+var schemaFuncFlagsByName = map[string][3]bool{
+	"commonschema.ResourceGroupName":              {true, false, false},
+	"commonschema.ResourceGroupNameForDataSource": {true, false, false},
+	"commonschema.Location":                       {true, false, false},
+	"commonschema.LocationWithoutForceNew":        {true, false, false},
+	"commonschema.Tags":                           {false, true, false},
+	"commonschema.TagsForceNew":                   {false, true, false},
+	"commonschema.TagsDataSource":                 {false, false, true},
+	"tags.Schema":                                 {false, true, false},
+	"tags.ForceNewSchema":                         {false, true, false},
+	"tags.SchemaDataSource":                       {false, false, true},
+}
+
+// schemaFuncFlags returns the Required, Optional and Computed flags of an attribute built by a schema helper, from
+// schemaFuncFlagsByName or the suffix of the helper name: commonschema.ZonesMultipleOptionalForceNew is Optional and
+// commonschema.LocationComputed is Computed. Other helpers result in no flags.
+func schemaFuncFlags(schemaFunc string) (required, optional, computed bool) {
+	if flags, known := schemaFuncFlagsByName[schemaFunc]; known {
+		return flags[0], flags[1], flags[2]
+	}
+	name := strings.TrimSuffix(schemaFunc[strings.LastIndex(schemaFunc, ".")+1:], "ForceNew")
+	switch {
+	case strings.HasSuffix(name, "OptionalComputed"):
+		return false, true, true
+	case strings.HasSuffix(name, "Required"):
+		return true, false, false
+	case strings.HasSuffix(name, "Optional"):
+		return false, true, false
+	case strings.HasSuffix(name, "Computed"):
+		return false, false, true
+	}
+	return false, false, false
+}
+
+// forceNewSchemaFuncs are the schema helpers forcing a new resource whose names don't tell it
+var forceNewSchemaFuncs = map[string]bool{
+	"commonschema.ResourceGroupName": true,
+	"commonschema.Location":          true,
+	"tags.ForceNewSchema":            true,
+}
+
+// isForceNewSchemaFunc reports whether a schema helper builds an attribute forcing a new resource, from
+// forceNewSchemaFuncs or the ForceNew suffix of its name, such as commonschema.ZonesMultipleOptionalForceNew
+func isForceNewSchemaFunc(schemaFunc string) bool {
+	return forceNewSchemaFuncs[schemaFunc] || (strings.HasSuffix(schemaFunc, "ForceNew") && !strings.HasSuffix(schemaFunc, "WithoutForceNew"))
+}
+
+// requiresReplace reports whether the PlanModifiers of a framework attribute hold a RequiresReplace modifier, such as
+// []planmodifier.String{stringplanmodifier.RequiresReplace()} or stringplanmodifier.RequiresReplaceIfConfigured()
+func requiresReplace(planModifiers ast.Expr) bool {
+	if planModifiers == nil {
+		return false
+	}
+	found := false
+	ast.Inspect(planModifiers, func(n ast.Node) bool {
+		if selector, ok := n.(*ast.SelectorExpr); ok && strings.HasPrefix(selector.Sel.Name, "RequiresReplace") {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// resolveBlockEntries resolves the schema map entries of a nested block, the Schema field of the pluginsdk.Resource
+// of an Elem expression, following calls to helper functions returning the resource. Elem: &pluginsdk.Schema{...}
+// declares the element type of a primitive list or set and has none.
+func resolveBlockEntries(elem ast.Expr, scope *ast.FuncDecl, packageInfo *gophon.PackageInfo, depth int) []schemaEntry {
+	if elem == nil || depth > maxSchemaResolveDepth {
+		return nil
+	}
+	if unaryExpr, ok := elem.(*ast.UnaryExpr); ok && unaryExpr.Op == token.AND {
+		elem = unaryExpr.X
+	}
+
+	switch e := elem.(type) {
+	case *ast.CompositeLit:
+		if schema := compositeLitField(e, "Schema"); schema != nil {
+			return resolveSchemaEntries(schema, scope, packageInfo, 0)
+		}
+	case *ast.CallExpr:
+		ident, ok := unwrapTypeArguments(e.Fun).(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		helper := findFunctionDecl(packageInfo, ident.Name)
+		return resolveBlockEntries(firstReturnedExpr(helper), helper, packageInfo, depth+1)
+	}
+	return nil
+}
+
+// validateFuncCompositions are validation helpers combining or adapting other validation functions,
+// their arguments are followed instead of recording the helper itself
+var validateFuncCompositions = map[string]bool{
+	"All":        true,
+	"Any":        true,
+	"AllDiag":    true,
+	"AnyDiag":    true,
+	"ToDiagFunc": true,
+}
+
+// extractValidateFunctions flattens a ValidateFunc expression into the validation functions it references, for example
+// validation.All(validation.StringIsNotEmpty, validation.StringLenBetween(1, 64)) results in
+// ["validation.StringIsNotEmpty", "validation.StringLenBetween"]
+func extractValidateFunctions(expr ast.Expr) []string {
+	switch e := unwrapTypeArguments(expr).(type) {
+	case *ast.CallExpr:
+		if validateFuncCompositions[extractFunctionReference(e.Fun)] {
+			var functions []string
+			for _, arg := range e.Args {
+				functions = append(functions, extractValidateFunctions(arg)...)
+			}
+			return functions
+		}
+		return []string{types.ExprString(unwrapTypeArguments(e.Fun))}
+	case *ast.Ident, *ast.SelectorExpr:
+		return []string{types.ExprString(e)}
+	}
+	return nil
+}
+
+// firstReturnedExpr returns the first result of the first top level return statement of a function
+func firstReturnedExpr(fn *ast.FuncDecl) ast.Expr {
+	if fn == nil || fn.Body == nil {
+		return nil
+	}
+	for _, stmt := range fn.Body.List {
+		if retStmt, ok := stmt.(*ast.ReturnStmt); ok && len(retStmt.Results) > 0 {
+			return retStmt.Results[0]
+		}
+	}
+	return nil
+}
+
+// stringLiteralValue returns the unquoted value of a string literal expression, or "" for any other expression
+func stringLiteralValue(expr ast.Expr) string {
+	basicLit, ok := expr.(*ast.BasicLit)
+	if !ok || basicLit.Kind != token.STRING {
+		return ""
+	}
+	value, err := strconv.Unquote(basicLit.Value)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+// schemaDefaultValue returns the value of a literal Default expression: strings, numbers, booleans and their
+// framework counterparts such as int64default.StaticInt64(30). Any other expression results in nil.
+func schemaDefaultValue(expr ast.Expr) interface{} {
+	if call, ok := expr.(*ast.CallExpr); ok {
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !strings.HasPrefix(selector.Sel.Name, "Static") || len(call.Args) != 1 {
+			return nil
+		}
+		expr = call.Args[0]
+	}
+
+	negative := false
+	if unaryExpr, ok := expr.(*ast.UnaryExpr); ok && unaryExpr.Op == token.SUB {
+		negative = true
+		expr = unaryExpr.X
+	}
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		switch e.Kind {
+		case token.STRING:
+			return stringLiteralValue(e)
+		case token.INT:
+			if value, err := strconv.ParseInt(e.Value, 0, 64); err == nil {
+				if negative {
+					return -value
+				}
+				return value
+			}
+		case token.FLOAT:
+			if value, err := strconv.ParseFloat(e.Value, 64); err == nil {
+				if negative {
+					return -value
+				}
+				return value
+			}
+		}
+	case *ast.Ident:
+		if e.Name == "true" || e.Name == "false" {
+			return e.Name == "true"
+		}
+	}
+	return nil
+}
+
+// ValidationReference identifies a schema attribute using a validation function
+type ValidationReference struct {
+	ID            string `json:"id"`             // "azurerm/resources/azurerm_management_lock/legacy_pluginsdk", see EntryID
+	TerraformType string `json:"terraform_type"` // "azurerm_management_lock"
+	Attribute     string `json:"attribute"`      // "lock_level"
+}
+
+// BuildValidationIndex maps each validation function to the resource attributes using it, sorted by Terraform type and attribute
+func (index *TerraformProviderIndex) BuildValidationIndex() map[string][]ValidationReference {
+	validations := make(map[string][]ValidationReference)
+	for _, service := range index.Services {
+		for terraformType, attributes := range service.ResourceSchemas {
+			for _, attribute := range attributes {
+				for _, validateFunc := range attribute.ValidateFuncs {
+					validations[validateFunc] = append(validations[validateFunc], ValidationReference{
+						ID:            EntryID(DocumentKindResource, terraformType, service.resourceSDKType(terraformType)),
+						TerraformType: terraformType,
+						Attribute:     attribute.Name,
+					})
+				}
+			}
+		}
+	}
+
+	for _, references := range validations {
+		sort.Slice(references, func(i, j int) bool {
+			if references[i].TerraformType != references[j].TerraformType {
+				return references[i].TerraformType < references[j].TerraformType
+			}
+			return references[i].Attribute < references[j].Attribute
+		})
+	}
+	return validations
+}
+
+// writeValidationIndexFile writes validations.json, the cross-reference between validation functions and resource attributes
+func (index *TerraformProviderIndex) writeValidationIndexFile(outputDir string) error {
+	return index.writeJSONFile(filepath.Join(outputDir, "validations.json"), index.BuildValidationIndex())
+}
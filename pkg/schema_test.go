@@ -0,0 +1,374 @@
+package pkg
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractLegacyResourceSchemaFromPackage_InlineSchema(t *testing.T) {
+	source := `package resource
+
+func resourceManagementLock() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Create: resourceManagementLockCreate,
+
+		Schema: map[string]*pluginsdk.Schema{
+			"name": {
+				Type:         pluginsdk.TypeString,
+				Required:     true,
+				ValidateFunc: validate.ManagementLockName,
+			},
+
+			"resource_group_name": commonschema.ResourceGroupName(),
+
+			"lock_level": {
+				Type:     pluginsdk.TypeString,
+				Required: true,
+				ValidateFunc: validation.All(
+					validation.StringIsNotEmpty,
+					validation.StringInSlice([]string{"CanNotDelete", "ReadOnly"}, false),
+				),
+			},
+
+			"notes": {
+				Type:             pluginsdk.TypeString,
+				Optional:         true,
+				Deprecated:       "` + "`notes`" + ` will be removed in version 5.0",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringLenBetween(0, 512)),
+			},
+		},
+	}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+	result := extractLegacyResourceSchemaFromPackage("resourceManagementLock", packageInfo)
+
+	assert.Equal(t, []SchemaAttribute{
+		{Name: "name", Type: "TypeString", ValidateFuncs: []string{"validate.ManagementLockName"}, Required: true},
+		{Name: "resource_group_name", SchemaFunc: "commonschema.ResourceGroupName", Required: true, ForceNew: true},
+		{Name: "lock_level", Type: "TypeString", ValidateFuncs: []string{"validation.StringIsNotEmpty", "validation.StringInSlice"}, Required: true},
+		{Name: "notes", Type: "TypeString", ValidateFuncs: []string{"validation.StringLenBetween"}, Optional: true, Deprecated: "`notes` will be removed in version 5.0"},
+	}, result)
+}
+
+func TestExtractLegacyResourceSchemaFromPackage_HelperFunctionAndIndexAssignment(t *testing.T) {
+	source := `package storage
+
+func resourceStorageAccount() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Schema: resourceStorageAccountSchema(),
+	}
+}
+
+func resourceStorageAccountSchema() map[string]*pluginsdk.Schema {
+	s := map[string]*pluginsdk.Schema{
+		"name": {
+			Type:         pluginsdk.TypeString,
+			ValidateFunc: validate.StorageAccountName,
+		},
+	}
+
+	s["access_tier"] = &pluginsdk.Schema{
+		Type:         pluginsdk.TypeString,
+		ValidateFunc: validation.StringInSlice([]string{"Hot", "Cool"}, false),
+	}
+
+	return s
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+	result := extractLegacyResourceSchemaFromPackage("resourceStorageAccount", packageInfo)
+
+	assert.Equal(t, []SchemaAttribute{
+		{Name: "name", Type: "TypeString", ValidateFuncs: []string{"validate.StorageAccountName"}},
+		{Name: "access_tier", Type: "TypeString", ValidateFuncs: []string{"validation.StringInSlice"}},
+	}, result)
+}
+
+func TestExtractLegacyResourceSchemaFromPackage_NoSchema(t *testing.T) {
+	source := `package resource
+
+func resourceResourceGroup() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Create: resourceResourceGroupCreate,
+	}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+	assert.Nil(t, extractLegacyResourceSchemaFromPackage("resourceResourceGroup", packageInfo))
+	assert.Nil(t, extractLegacyResourceSchemaFromPackage("resourceNotFound", packageInfo))
+}
+
+func TestExtractLegacyResourceSchemaFromPackage_NestedBlocks(t *testing.T) {
+	source := `package keyvault
+
+func resourceKeyVault() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Schema: map[string]*pluginsdk.Schema{
+			"access_policy": {
+				Type:     pluginsdk.TypeList,
+				Optional: true,
+				Elem: &pluginsdk.Resource{
+					Schema: map[string]*pluginsdk.Schema{
+						"object_id": {
+							Type:     pluginsdk.TypeString,
+							Required: true,
+						},
+						"key_permissions": {
+							Type: pluginsdk.TypeList,
+							Elem: &pluginsdk.Schema{
+								Type: pluginsdk.TypeString,
+							},
+						},
+					},
+				},
+			},
+
+			"network_acls": {
+				Type:     pluginsdk.TypeList,
+				Optional: true,
+				Elem:     networkAclsBlock(),
+			},
+		},
+	}
+}
+
+func networkAclsBlock() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Schema: map[string]*pluginsdk.Schema{
+			"virtual_network_rule": {
+				Type: pluginsdk.TypeSet,
+				Elem: &pluginsdk.Resource{
+					Schema: map[string]*pluginsdk.Schema{
+						"subnet_id": {
+							Type:     pluginsdk.TypeString,
+							Required: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+	result := extractLegacyResourceSchemaFromPackage("resourceKeyVault", packageInfo)
+
+	assert.Equal(t, []SchemaAttribute{
+		{Name: "access_policy", Type: "TypeList", Optional: true, Block: []SchemaAttribute{
+			{Name: "object_id", Type: "TypeString", Required: true, Depth: 1},
+			{Name: "key_permissions", Type: "TypeList", Depth: 1},
+		}},
+		{Name: "network_acls", Type: "TypeList", Optional: true, Block: []SchemaAttribute{
+			{Name: "virtual_network_rule", Type: "TypeSet", Depth: 1, Block: []SchemaAttribute{
+				{Name: "subnet_id", Type: "TypeString", Required: true, Depth: 2},
+			}},
+		}},
+	}, result)
+}
+
+func TestExtractTypedResourceSchemaFromPackage(t *testing.T) {
+	source := `package managedapplications
+
+type ApplicationDefinitionResource struct{}
+
+func (r ApplicationDefinitionResource) Arguments() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{
+		"name": {
+			Type:         pluginsdk.TypeString,
+			Required:     true,
+			ValidateFunc: validate.ApplicationDefinitionName,
+		},
+
+		"package_file_uri": {
+			Type:         pluginsdk.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.Any(validation.IsURLWithHTTPS, validation.StringIsEmpty),
+		},
+	}
+}
+
+func (r ApplicationDefinitionResource) Attributes() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{
+		"principal_id": {
+			Type:     pluginsdk.TypeString,
+			Computed: true,
+		},
+	}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+	result := extractTypedResourceSchemaFromPackage("ApplicationDefinitionResource", packageInfo)
+
+	assert.Equal(t, []SchemaAttribute{
+		{Name: "name", Type: "TypeString", ValidateFuncs: []string{"validate.ApplicationDefinitionName"}, Required: true},
+		{Name: "package_file_uri", Type: "TypeString", ValidateFuncs: []string{"validation.IsURLWithHTTPS", "validation.StringIsEmpty"}, Optional: true},
+		{Name: "principal_id", Type: "TypeString", Computed: true},
+	}, result)
+}
+
+func TestExtractTypedResourceSchemaFromPackage_BehaviourFlags(t *testing.T) {
+	source := `package network
+
+type NetworkManagerResource struct{}
+
+func (r NetworkManagerResource) Arguments() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{
+		"resource_group_name": commonschema.ResourceGroupName(),
+		"location":            commonschema.Location(),
+		"zones":               commonschema.ZonesMultipleOptionalForceNew(),
+		"identity":            commonschema.SystemAssignedUserAssignedIdentityOptionalComputed(),
+		"tags":                commonschema.Tags(),
+		"scope":               networkManagerScopeSchema(),
+	}
+}
+
+func (r NetworkManagerResource) Attributes() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{
+		"cross_tenant_scopes": {
+			Type: pluginsdk.TypeList,
+		},
+	}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+	result := extractTypedResourceSchemaFromPackage("NetworkManagerResource", packageInfo)
+
+	assert.Equal(t, []SchemaAttribute{
+		{Name: "resource_group_name", SchemaFunc: "commonschema.ResourceGroupName", Required: true, ForceNew: true},
+		{Name: "location", SchemaFunc: "commonschema.Location", Required: true, ForceNew: true},
+		{Name: "zones", SchemaFunc: "commonschema.ZonesMultipleOptionalForceNew", Optional: true, ForceNew: true},
+		{Name: "identity", SchemaFunc: "commonschema.SystemAssignedUserAssignedIdentityOptionalComputed", Optional: true, Computed: true},
+		{Name: "tags", SchemaFunc: "commonschema.Tags", Optional: true},
+		{Name: "scope", SchemaFunc: "networkManagerScopeSchema"},
+		{Name: "cross_tenant_scopes", Type: "TypeList", Computed: true},
+	}, result)
+}
+
+func TestExtractLegacyResourceSchemaFromPackage_Defaults(t *testing.T) {
+	source := `package storage
+
+func resourceStorageAccount() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Schema: map[string]*pluginsdk.Schema{
+			"access_tier": {
+				Type:     pluginsdk.TypeString,
+				Optional: true,
+				Default:  "Hot",
+			},
+			"retention_days": {
+				Type:     pluginsdk.TypeInt,
+				Optional: true,
+				Default:  -1,
+			},
+			"https_traffic_only_enabled": {
+				Type:     pluginsdk.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"sku": {
+				Type:     pluginsdk.TypeString,
+				Optional: true,
+				Default:  string(storageaccounts.SkuNameStandardLRS),
+			},
+			"subscription_id": {
+				Type:        pluginsdk.TypeString,
+				Optional:    true,
+				DefaultFunc: pluginsdk.EnvDefaultFunc("ARM_SUBSCRIPTION_ID", nil),
+			},
+		},
+	}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+	result := extractLegacyResourceSchemaFromPackage("resourceStorageAccount", packageInfo)
+
+	assert.Equal(t, []SchemaAttribute{
+		{Name: "access_tier", Type: "TypeString", Optional: true, Default: "Hot"},
+		{Name: "retention_days", Type: "TypeInt", Optional: true, Default: int64(-1)},
+		{Name: "https_traffic_only_enabled", Type: "TypeBool", Optional: true, Default: false},
+		{Name: "sku", Type: "TypeString", Optional: true},
+		{Name: "subscription_id", Type: "TypeString", Optional: true, DefaultFunc: "pluginsdk.EnvDefaultFunc"},
+	}, result)
+}
+
+func TestExtractTypedResourceSchemaFromPackage_FrameworkDefaults(t *testing.T) {
+	source := `package resource
+
+type ResourceGroupFrameworkResource struct{}
+
+func (r ResourceGroupFrameworkResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"timeout_minutes": schema.Int64Attribute{
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(30),
+			},
+		},
+	}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+	result := extractTypedResourceSchemaFromPackage("ResourceGroupFrameworkResource", packageInfo)
+
+	assert.Equal(t, []SchemaAttribute{
+		{Name: "timeout_minutes", Optional: true, Computed: true, Default: int64(30)},
+	}, result)
+}
+
+func TestTerraformProviderIndex_WriteValidationIndexFile(t *testing.T) {
+	// Setup
+	index := &TerraformProviderIndex{
+		Services: []ServiceRegistration{
+			{
+				ResourceSchemas: map[string][]SchemaAttribute{
+					"azurerm_storage_account": {
+						{Name: "name", ValidateFuncs: []string{"validate.StorageAccountName"}},
+						{Name: "tags"},
+					},
+				},
+			},
+			{
+				ResourceSchemas: map[string][]SchemaAttribute{
+					"azurerm_key_vault": {
+						{Name: "tenant_id", ValidateFuncs: []string{"validation.IsUUID"}},
+					},
+					"azurerm_application_insights": {
+						{Name: "application_id", ValidateFuncs: []string{"validation.IsUUID"}},
+					},
+				},
+			},
+		},
+	}
+	fs := afero.NewMemMapFs()
+	index.Output.Fs = fs
+	outputDir := "/test/output"
+
+	// Execute
+	err := index.writeValidationIndexFile(outputDir)
+
+	// Verify
+	require.NoError(t, err)
+
+	data, err := afero.ReadFile(fs, filepath.Join(outputDir, "validations.json"))
+	require.NoError(t, err)
+
+	var validations map[string][]ValidationReference
+	require.NoError(t, json.Unmarshal(data, &validations))
+
+	assert.Equal(t, map[string][]ValidationReference{
+		"validate.StorageAccountName": {
+			{ID: "azurerm/resources/azurerm_storage_account/modern_sdk", TerraformType: "azurerm_storage_account", Attribute: "name"},
+		},
+		"validation.IsUUID": {
+			{ID: "azurerm/resources/azurerm_application_insights/modern_sdk", TerraformType: "azurerm_application_insights", Attribute: "application_id"},
+			{ID: "azurerm/resources/azurerm_key_vault/modern_sdk", TerraformType: "azurerm_key_vault", Attribute: "tenant_id"},
+		},
+	}, validations)
+}
@@ -0,0 +1,117 @@
+package pkg
+
+import (
+	"go/ast"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// azureSDKImportPrefix is the import path prefix of the go-azure-sdk packages
+const azureSDKImportPrefix = "github.com/hashicorp/go-azure-sdk/"
+
+// apiVersionSegmentPattern matches API version path segments such as 2023-07-01 or 2023-05-01-preview
+var apiVersionSegmentPattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(-[a-z]+)?$`)
+
+// extractLegacySDKPackagesFromPackage collects the go-azure-sdk packages referenced by the CRUD functions of a legacy resource
+func extractLegacySDKPackagesFromPackage(crudMethods *LegacyResourceCRUDFunctions, packageInfo *gophon.PackageInfo) []string {
+	if crudMethods == nil {
+		return nil
+	}
+	var functions []*ast.FuncDecl
+	for _, name := range []string{crudMethods.CreateMethod, crudMethods.ReadMethod, crudMethods.UpdateMethod, crudMethods.DeleteMethod} {
+		if name != "" {
+			functions = append(functions, findFunctionDecl(packageInfo, name))
+		}
+	}
+	return sdkPackagesReferencedBy(packageInfo, functions...)
+}
+
+// extractTypedSDKPackagesFromPackage collects the go-azure-sdk packages referenced by the CRUD methods of a typed resource
+func extractTypedSDKPackagesFromPackage(structName string, packageInfo *gophon.PackageInfo) []string {
+	var functions []*ast.FuncDecl
+	for _, name := range []string{"Create", "Read", "Update", "Delete"} {
+		functions = append(functions, findMethodDecl(packageInfo, structName, name))
+	}
+	return sdkPackagesReferencedBy(packageInfo, functions...)
+}
+
+// sdkPackagesReferencedBy returns the sorted go-azure-sdk import paths whose identifiers are used in the given functions
+func sdkPackagesReferencedBy(packageInfo *gophon.PackageInfo, functions ...*ast.FuncDecl) []string {
+	packages := make(map[string]bool)
+	for _, fn := range functions {
+		file := findFunctionFile(packageInfo, fn)
+		if file == nil || fn.Body == nil {
+			continue
+		}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			selector, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if alias, ok := selector.X.(*ast.Ident); ok {
+				if importPath := importPathOfAlias(file, alias.Name); strings.HasPrefix(importPath, azureSDKImportPrefix) {
+					packages[importPath] = true
+				}
+			}
+			return true
+		})
+	}
+	if len(packages) == 0 {
+		return nil
+	}
+
+	result := make([]string, 0, len(packages))
+	for importPath := range packages {
+		result = append(result, importPath)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// sdkAPIVersion returns the API version an SDK package belongs to, relative to the go-azure-sdk root, e.g.
+// "github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2023-07-01/vaults" results in "resource-manager/keyvault/2023-07-01".
+// Packages without an API version segment are returned as is.
+func sdkAPIVersion(importPath string) string {
+	relPath := strings.TrimPrefix(importPath, azureSDKImportPrefix)
+	segments := strings.Split(relPath, "/")
+	for i, segment := range segments {
+		if apiVersionSegmentPattern.MatchString(segment) {
+			return strings.Join(segments[:i+1], "/")
+		}
+	}
+	return relPath
+}
+
+// BuildSDKIndex maps each go-azure-sdk API version to the sorted Terraform resources consuming it
+func (index *TerraformProviderIndex) BuildSDKIndex() map[string][]string {
+	consumers := make(map[string]map[string]bool)
+	for _, service := range index.Services {
+		for terraformType, packages := range service.ResourceSDKPackages {
+			for _, importPath := range packages {
+				apiVersion := sdkAPIVersion(importPath)
+				if consumers[apiVersion] == nil {
+					consumers[apiVersion] = make(map[string]bool)
+				}
+				consumers[apiVersion][terraformType] = true
+			}
+		}
+	}
+
+	result := make(map[string][]string, len(consumers))
+	for apiVersion, terraformTypes := range consumers {
+		for terraformType := range terraformTypes {
+			result[apiVersion] = append(result[apiVersion], terraformType)
+		}
+		sort.Strings(result[apiVersion])
+	}
+	return result
+}
+
+// writeSDKIndexFile writes sdk_api_versions.json, the reverse map from go-azure-sdk API versions to Terraform resources
+func (index *TerraformProviderIndex) writeSDKIndexFile(outputDir string) error {
+	return index.writeJSONFile(filepath.Join(outputDir, "sdk_api_versions.json"), index.BuildSDKIndex())
+}
@@ -0,0 +1,118 @@
+package pkg
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractLegacySDKPackagesFromPackage(t *testing.T) {
+	source := `package keyvault
+
+import (
+	"github.com/hashicorp/go-azure-helpers/resourcemanager/commonids"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2023-07-01/vaults"
+	dnsZones "github.com/hashicorp/go-azure-sdk/resource-manager/dns/2018-05-01/zones"
+)
+
+func resourceKeyVaultCreate(d *pluginsdk.ResourceData, meta interface{}) error {
+	id := vaults.NewVaultID(subscriptionId, d.Get("resource_group_name").(string), d.Get("name").(string))
+	parameters := vaults.VaultCreateOrUpdateParameters{}
+	return nil
+}
+
+func resourceKeyVaultRead(d *pluginsdk.ResourceData, meta interface{}) error {
+	id, err := commonids.ParseKeyVaultID(d.Id())
+	return err
+}
+
+func resourceKeyVaultDelete(d *pluginsdk.ResourceData, meta interface{}) error {
+	_, err := dnsZones.ParseDnsZoneID(d.Get("zone_id").(string))
+	return err
+}
+
+func resourceKeyVaultFlatten(input *vaults.Vault) {}`
+
+	packageInfo := parsePackageInfo(t, source)
+	result := extractLegacySDKPackagesFromPackage(&LegacyResourceCRUDFunctions{
+		CreateMethod: "resourceKeyVaultCreate",
+		ReadMethod:   "resourceKeyVaultRead",
+		DeleteMethod: "resourceKeyVaultDelete",
+	}, packageInfo)
+
+	assert.Equal(t, []string{
+		"github.com/hashicorp/go-azure-sdk/resource-manager/dns/2018-05-01/zones",
+		"github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2023-07-01/vaults",
+	}, result)
+	assert.Nil(t, extractLegacySDKPackagesFromPackage(nil, packageInfo))
+}
+
+func TestExtractTypedSDKPackagesFromPackage(t *testing.T) {
+	source := `package containerapps
+
+import "github.com/hashicorp/go-azure-sdk/resource-manager/containerapps/2023-05-01/containerapps"
+
+type ContainerAppResource struct{}
+
+func (r ContainerAppResource) Read() sdk.ResourceFunc {
+	return sdk.ResourceFunc{
+		Func: func(ctx context.Context, metadata sdk.ResourceMetaData) error {
+			id, err := containerapps.ParseContainerAppID(metadata.ResourceData.Id())
+			return err
+		},
+	}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+
+	assert.Equal(t, []string{"github.com/hashicorp/go-azure-sdk/resource-manager/containerapps/2023-05-01/containerapps"},
+		extractTypedSDKPackagesFromPackage("ContainerAppResource", packageInfo))
+	assert.Nil(t, extractTypedSDKPackagesFromPackage("UnknownResource", packageInfo))
+}
+
+func TestSDKAPIVersion(t *testing.T) {
+	assert.Equal(t, "resource-manager/keyvault/2023-07-01", sdkAPIVersion("github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2023-07-01/vaults"))
+	assert.Equal(t, "resource-manager/web/2023-12-01-preview", sdkAPIVersion("github.com/hashicorp/go-azure-sdk/resource-manager/web/2023-12-01-preview/webapps"))
+	assert.Equal(t, "sdk/client/pollers", sdkAPIVersion("github.com/hashicorp/go-azure-sdk/sdk/client/pollers"))
+}
+
+func TestTerraformProviderIndex_WriteSDKIndexFile(t *testing.T) {
+	// Setup
+	index := &TerraformProviderIndex{
+		Services: []ServiceRegistration{
+			{
+				ResourceSDKPackages: map[string][]string{
+					"azurerm_key_vault": {
+						"github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2023-07-01/vaults",
+						"github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2023-07-01/managedhsms",
+					},
+					"azurerm_key_vault_access_policy": {
+						"github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2023-07-01/vaults",
+					},
+				},
+			},
+		},
+	}
+	fs := afero.NewMemMapFs()
+	index.Output.Fs = fs
+	outputDir := "/test/output"
+
+	// Execute
+	err := index.writeSDKIndexFile(outputDir)
+
+	// Verify
+	require.NoError(t, err)
+
+	data, err := afero.ReadFile(fs, filepath.Join(outputDir, "sdk_api_versions.json"))
+	require.NoError(t, err)
+
+	var sdkIndex map[string][]string
+	require.NoError(t, json.Unmarshal(data, &sdkIndex))
+	assert.Equal(t, map[string][]string{
+		"resource-manager/keyvault/2023-07-01": {"azurerm_key_vault", "azurerm_key_vault_access_policy"},
+	}, sdkIndex)
+}
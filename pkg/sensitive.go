@@ -0,0 +1,56 @@
+package pkg
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// SensitiveReference lists the sensitive attributes of a resource, the attributes Terraform redacts from its output
+type SensitiveReference struct {
+	ID            string   `json:"id"`             // "azurerm/resources/azurerm_storage_account/legacy_pluginsdk", see EntryID
+	TerraformType string   `json:"terraform_type"` // "azurerm_storage_account"
+	Attributes    []string `json:"attributes"`     // ["primary_access_key", "primary_connection_string"]
+}
+
+// sensitiveAttributePaths returns the paths of the sensitive attributes of a schema, attributes of nested blocks are
+// prefixed with the names of their blocks: "site_config.application_stack.docker_registry_password"
+func sensitiveAttributePaths(attributes []SchemaAttribute) []string {
+	var paths []string
+	for _, attribute := range attributes {
+		if attribute.Sensitive {
+			paths = append(paths, attribute.Name)
+		}
+		for _, path := range sensitiveAttributePaths(attribute.Block) {
+			paths = append(paths, attribute.Name+"."+path)
+		}
+	}
+	return paths
+}
+
+// BuildSensitiveIndex lists the resources declaring sensitive attributes, sorted by Terraform type
+func (index *TerraformProviderIndex) BuildSensitiveIndex() []SensitiveReference {
+	references := []SensitiveReference{}
+	for _, service := range index.Services {
+		for terraformType, attributes := range service.ResourceSchemas {
+			paths := sensitiveAttributePaths(attributes)
+			if len(paths) == 0 {
+				continue
+			}
+			references = append(references, SensitiveReference{
+				ID:            EntryID(DocumentKindResource, terraformType, service.resourceSDKType(terraformType)),
+				TerraformType: terraformType,
+				Attributes:    paths,
+			})
+		}
+	}
+
+	sort.Slice(references, func(i, j int) bool {
+		return references[i].TerraformType < references[j].TerraformType
+	})
+	return references
+}
+
+// writeSensitiveIndexFile writes sensitive_attributes.json, the sensitive attributes of all resources
+func (index *TerraformProviderIndex) writeSensitiveIndexFile(outputDir string) error {
+	return index.writeJSONFile(filepath.Join(outputDir, "sensitive_attributes.json"), index.BuildSensitiveIndex())
+}
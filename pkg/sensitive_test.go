@@ -0,0 +1,82 @@
+package pkg
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractLegacyResourceSchemaFromPackage_Sensitive(t *testing.T) {
+	source := `package storage
+
+func resourceStorageAccount() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Schema: map[string]*pluginsdk.Schema{
+			"name": {
+				Type:     pluginsdk.TypeString,
+				Required: true,
+			},
+			"primary_access_key": {
+				Type:      pluginsdk.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"customer_managed_key": {
+				Type:     pluginsdk.TypeList,
+				Optional: true,
+				Elem: &pluginsdk.Resource{
+					Schema: map[string]*pluginsdk.Schema{
+						"key_vault_key_id": {
+							Type:      pluginsdk.TypeString,
+							Required:  true,
+							Sensitive: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+	result := extractLegacyResourceSchemaFromPackage("resourceStorageAccount", packageInfo)
+
+	assert.Equal(t, []string{"primary_access_key", "customer_managed_key.key_vault_key_id"}, sensitiveAttributePaths(result))
+}
+
+func TestTerraformProviderIndex_WriteSensitiveIndexFile(t *testing.T) {
+	index := &TerraformProviderIndex{
+		Services: []ServiceRegistration{
+			{
+				SupportedResources: map[string]string{"azurerm_storage_account": "resourceStorageAccount"},
+				ResourceSchemas: map[string][]SchemaAttribute{
+					"azurerm_storage_account": {
+						{Name: "name"},
+						{Name: "primary_access_key", Sensitive: true},
+					},
+					"azurerm_storage_container": {
+						{Name: "name"},
+					},
+				},
+			},
+		},
+	}
+	fs := afero.NewMemMapFs()
+	index.Output.Fs = fs
+	outputDir := "/test/output"
+
+	require.NoError(t, index.writeSensitiveIndexFile(outputDir))
+
+	data, err := afero.ReadFile(fs, filepath.Join(outputDir, "sensitive_attributes.json"))
+	require.NoError(t, err)
+	var references []SensitiveReference
+	require.NoError(t, json.Unmarshal(data, &references))
+
+	assert.Equal(t, []SensitiveReference{
+		{ID: "azurerm/resources/azurerm_storage_account/legacy_pluginsdk", TerraformType: "azurerm_storage_account", Attributes: []string{"primary_access_key"}},
+	}, references)
+}
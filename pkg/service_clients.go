@@ -0,0 +1,153 @@
+package pkg
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+)
+
+// serviceClientDir is the directory of a service package holding the Client struct of the service
+const serviceClientDir = "client"
+
+// ServiceClient is a field of the Client struct a service's client package builds, usually an Azure SDK client
+type ServiceClient struct {
+	Field      string `json:"field"`                 // "VaultsClient", the type name for embedded fields
+	Type       string `json:"type"`                  // "*vaults.VaultsClient"
+	SDKPackage string `json:"sdk_package,omitempty"` // "github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2023-07-01/vaults"
+	APIVersion string `json:"api_version,omitempty"` // "resource-manager/keyvault/2023-07-01" for go-azure-sdk packages, see sdkAPIVersion
+}
+
+// TerraformServiceClients is the content of the clients/ output files
+type TerraformServiceClients struct {
+	ServiceName string          `json:"service_name"` // "keyvault"
+	PackagePath string          `json:"package_path"` // "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault/client"
+	FilePath    string          `json:"file_path"`    // "internal/services/keyvault/client/client.go"
+	Clients     []ServiceClient `json:"clients"`      // In field order
+}
+
+// extractServiceClients parses the client package of a service directory and returns the fields of its Client
+// struct with the packages of their types, and the file declaring the struct. Services without a client package
+// return no clients.
+func extractServiceClients(serviceDir string) (clients []ServiceClient, filePath string) {
+	sourceFiles, _ := filepath.Glob(filepath.Join(serviceDir, serviceClientDir, "*.go"))
+	for _, sourceFile := range sourceFiles {
+		if strings.HasSuffix(sourceFile, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), sourceFile, nil, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		structType := findClientStruct(file)
+		if structType == nil {
+			continue
+		}
+		clients = []ServiceClient{}
+		for _, field := range structType.Fields.List {
+			client := ServiceClient{Type: types.ExprString(field.Type)}
+			if alias := typePackageAlias(field.Type); alias != "" {
+				client.SDKPackage = importPathOfAlias(file, alias)
+			}
+			if strings.HasPrefix(client.SDKPackage, azureSDKImportPrefix) {
+				client.APIVersion = sdkAPIVersion(client.SDKPackage)
+			}
+			if len(field.Names) == 0 {
+				client.Field = embeddedFieldName(field.Type)
+				clients = append(clients, client)
+				continue
+			}
+			for _, name := range field.Names {
+				client.Field = name.Name
+				clients = append(clients, client)
+			}
+		}
+		return clients, filepath.ToSlash(sourceFile)
+	}
+	return nil, ""
+}
+
+// findClientStruct returns the struct type of the Client type declared in the file, nil when the file doesn't declare it
+func findClientStruct(file *ast.File) *ast.StructType {
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok || typeSpec.Name.Name != "Client" {
+				continue
+			}
+			if structType, ok := typeSpec.Type.(*ast.StructType); ok {
+				return structType
+			}
+		}
+	}
+	return nil
+}
+
+// typePackageAlias returns the package alias of the type a field holds, looking through pointers, slices and maps,
+// "" for types declared in the client package and builtin types
+func typePackageAlias(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return typePackageAlias(e.X)
+	case *ast.ArrayType:
+		return typePackageAlias(e.Elt)
+	case *ast.MapType:
+		return typePackageAlias(e.Value)
+	case *ast.IndexExpr:
+		return typePackageAlias(e.X)
+	case *ast.SelectorExpr:
+		if ident, ok := e.X.(*ast.Ident); ok {
+			return ident.Name
+		}
+	}
+	return ""
+}
+
+// embeddedFieldName returns the name of an embedded field, the name of its type
+func embeddedFieldName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedFieldName(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.Ident:
+		return e.Name
+	}
+	return types.ExprString(expr)
+}
+
+// BuildServiceClients returns the Client structs of the services with a client package, in service order
+func (index *TerraformProviderIndex) BuildServiceClients() []TerraformServiceClients {
+	var result []TerraformServiceClients
+	for _, service := range index.Services {
+		if service.ClientFile == "" {
+			continue
+		}
+		result = append(result, TerraformServiceClients{
+			ServiceName: service.ServiceName,
+			PackagePath: service.PackagePath + "/" + serviceClientDir,
+			FilePath:    service.ClientFile,
+			Clients:     service.Clients,
+		})
+	}
+	return result
+}
+
+// writeServiceClientFiles writes clients/<service name>.json for every service with a client package
+func (index *TerraformProviderIndex) writeServiceClientFiles(outputDir string, progressTracker *ProgressTracker) error {
+	for _, clients := range index.BuildServiceClients() {
+		fileName := clients.ServiceName + ".json"
+		if err := index.writeJSONFile(filepath.Join(outputDir, "clients", fileName), clients); err != nil {
+			return fmt.Errorf("failed to write service clients file %s: %w", fileName, err)
+		}
+		progressTracker.UpdateProgress(fmt.Sprintf("clients %s", clients.ServiceName))
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package pkg
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const serviceClientSource = `package client
+
+import (
+	"github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2023-07-01/vaults"
+	dataplane "github.com/tombuildsstuff/kermit/sdk/keyvault/7.4/keyvault"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/common"
+)
+
+type Client struct {
+	VaultsClient        *vaults.VaultsClient
+	ManagementClient    *dataplane.BaseClient
+	ReaderA, ReaderB    vaults.VaultsClient
+	*common.ClientOptions
+	cache               map[string]*vaults.Vault
+}
+
+type Other struct {
+	Ignored *vaults.VaultsClient
+}
+`
+
+func TestExtractServiceClients(t *testing.T) {
+	serviceDir := t.TempDir()
+	clientDir := filepath.Join(serviceDir, "client")
+	require.NoError(t, os.Mkdir(clientDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(clientDir, "client.go"), []byte(serviceClientSource), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(clientDir, "client_test.go"), []byte("package client\n\ntype Client struct{ Test int }\n"), 0644))
+
+	clients, filePath := extractServiceClients(serviceDir)
+
+	vaultsPackage := "github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2023-07-01/vaults"
+	assert.Equal(t, filepath.ToSlash(filepath.Join(clientDir, "client.go")), filePath)
+	assert.Equal(t, []ServiceClient{
+		{Field: "VaultsClient", Type: "*vaults.VaultsClient", SDKPackage: vaultsPackage, APIVersion: "resource-manager/keyvault/2023-07-01"},
+		{Field: "ManagementClient", Type: "*dataplane.BaseClient", SDKPackage: "github.com/tombuildsstuff/kermit/sdk/keyvault/7.4/keyvault"},
+		{Field: "ReaderA", Type: "vaults.VaultsClient", SDKPackage: vaultsPackage, APIVersion: "resource-manager/keyvault/2023-07-01"},
+		{Field: "ReaderB", Type: "vaults.VaultsClient", SDKPackage: vaultsPackage, APIVersion: "resource-manager/keyvault/2023-07-01"},
+		{Field: "ClientOptions", Type: "*common.ClientOptions", SDKPackage: "github.com/hashicorp/terraform-provider-azurerm/internal/common"},
+		{Field: "cache", Type: "map[string]*vaults.Vault", SDKPackage: vaultsPackage, APIVersion: "resource-manager/keyvault/2023-07-01"},
+	}, clients)
+}
+
+func TestExtractServiceClients_NoClientPackage(t *testing.T) {
+	clients, filePath := extractServiceClients(t.TempDir())
+
+	assert.Nil(t, clients)
+	assert.Empty(t, filePath)
+}
+
+func TestWriteServiceClientFiles(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	index := &TerraformProviderIndex{Output: OutputConfig{Fs: fs}, Services: []ServiceRegistration{
+		{
+			ServiceName: "keyvault",
+			PackagePath: "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault",
+			ClientFile:  "internal/services/keyvault/client/client.go",
+			Clients:     []ServiceClient{{Field: "VaultsClient", Type: "*vaults.VaultsClient"}},
+		},
+		{ServiceName: "resource"},
+	}}
+	outputDir := "/test/output"
+	require.NoError(t, index.writeServiceClientFiles(outputDir, NewProgressTracker("test", 1, nil)))
+
+	data, err := afero.ReadFile(fs, filepath.Join(outputDir, "clients", "keyvault.json"))
+	require.NoError(t, err)
+	var clients TerraformServiceClients
+	require.NoError(t, json.Unmarshal(data, &clients))
+	assert.Equal(t, TerraformServiceClients{
+		ServiceName: "keyvault",
+		PackagePath: "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault/client",
+		FilePath:    "internal/services/keyvault/client/client.go",
+		Clients:     []ServiceClient{{Field: "VaultsClient", Type: "*vaults.VaultsClient"}},
+	}, clients)
+
+	exists, err := afero.Exists(fs, filepath.Join(outputDir, "clients", "resource.json"))
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
@@ -3,6 +3,7 @@ package pkg
 import (
 	gophon "github.com/lonegunmanb/gophon/pkg"
 	"os"
+	"sort"
 )
 
 // ServiceRegistration represents all registration methods found in a single service package
@@ -10,6 +11,9 @@ type ServiceRegistration struct {
 	Package              *gophon.PackageInfo                     `json:"-"`
 	ServiceName          string                                  `json:"service_name"`           // "keyvault", "resource", etc.
 	PackagePath          string                                  `json:"package_path"`           // "internal/services/keyvault"
+	DisplayName          string                                  `json:"display_name,omitempty"` // "Key Vault", returned by the Name method of the registration
+	ProductName          string                                  `json:"product_name,omitempty"` // "Azure Key Vault", see DefaultProductNames
+	GitHubLabel          string                                  `json:"github_label,omitempty"` // "service/key-vault", returned by the AssociatedGitHubLabel method
 	SupportedResources   map[string]string                       `json:"supported_resources"`    // Legacy map-based resources
 	SupportedDataSources map[string]string                       `json:"supported_data_sources"` // Legacy map-based data sources
 	Resources            []string                                `json:"resources"`              // Modern slice-based resources
@@ -17,10 +21,72 @@ type ServiceRegistration struct {
 	EphemeralFunctions   []string                                `json:"ephemeral_functions"`    // Function-based ephemeral resources
 	ResourceCRUDMethods  map[string]*LegacyResourceCRUDFunctions `json:"resource_crud_methods"`  // CRUD methods for legacy resources
 	DataSourceMethods    map[string]*LegacyDataSourceMethods     `json:"data_source_methods"`    // Methods for legacy data sources
+	// Website documentation categories returned by the WebsiteCategories method of the registration
+	WebsiteCategories []string `json:"website_categories,omitempty"` // ["Key Vault"]
+	// Modern registrations only made under a condition, such as a feature flag
+	ConditionalResources   []string `json:"conditional_resources,omitempty"`    // ["KeyVaultManagedHSMResource"]
+	ConditionalDataSources []string `json:"conditional_data_sources,omitempty"` // ["KeyVaultManagedHSMDataSource"]
 	// New mappings between Terraform types and struct types
 	ResourceTerraformTypes   map[string]string `json:"resource_terraform_types"`    // StructType -> TerraformType for modern resources
 	DataSourceTerraformTypes map[string]string `json:"data_source_terraform_types"` // StructType -> TerraformType for modern data sources
 	EphemeralTerraformTypes  map[string]string `json:"ephemeral_terraform_types"`   // StructType -> TerraformType for ephemeral resources
+	EphemeralConstructors    map[string]string `json:"ephemeral_constructors"`      // StructType -> constructor function registering the ephemeral resource
+	TerraformTypeStrategies  map[string]string `json:"terraform_type_strategies"`   // StructType -> name of the strategy that inferred its Terraform type
+	// Framework list resources and actions, registered by ListResources and Actions as struct literals or constructors
+	ListResources              []string          `json:"list_resources,omitempty"`                // ["ResourceGroupListResource"], including the struct types of constructors
+	ListResourceFunctions      []string          `json:"list_resource_functions,omitempty"`       // ["NewResourceGroupListResource"]
+	ListResourceTerraformTypes map[string]string `json:"list_resource_terraform_types,omitempty"` // StructType -> TerraformType for list resources
+	ListResourceConstructors   map[string]string `json:"list_resource_constructors,omitempty"`    // StructType -> constructor function registering the list resource
+	Actions                    []string          `json:"actions,omitempty"`                       // ["VirtualMachinePowerAction"], including the struct types of constructors
+	ActionFunctions            []string          `json:"action_functions,omitempty"`              // ["NewVirtualMachinePowerAction"]
+	ActionTerraformTypes       map[string]string `json:"action_terraform_types,omitempty"`        // StructType -> TerraformType for actions
+	ActionConstructors         map[string]string `json:"action_constructors,omitempty"`           // StructType -> constructor function registering the action
+	// Per-resource extraction results keyed by Terraform type (falling back to struct type for unresolved modern resources)
+	ResourceStateUpgrades  map[string]*StateUpgradeInfo `json:"resource_state_upgrades"`  // Schema version and state upgraders
+	ResourceCustomizeDiff  map[string][]string          `json:"resource_customize_diff"`  // CustomizeDiff function references
+	ResourceDeprecations   map[string]string            `json:"resource_deprecations"`    // Deprecation messages of deprecated resources
+	DataSourceDeprecations map[string]string            `json:"data_source_deprecations"` // Deprecation messages of deprecated data sources
+	ResourceArmTypes       map[string]string            `json:"resource_arm_types"`       // ARM resource types managed by resources, e.g. "Microsoft.KeyVault/vaults"
+	ResourceSDKPackages    map[string][]string          `json:"resource_sdk_packages"`    // go-azure-sdk packages referenced by CRUD functions
+	ResourceIDParsers      map[string]string            `json:"resource_id_parsers"`      // Resource ID parsers used by Read functions, e.g. "commonids.ParseKeyVaultID"
+	ResourceCapabilities   map[string][]string          `json:"resource_capabilities"`    // Capabilities, e.g. ["update_reuses_create", "raw_rest_calls"]
+	ResourceImmutable      map[string]bool              `json:"resource_immutable"`       // Resources without Update, replaced on every change
+	// Feature flags guarding registrations, "!features.FivePointOh" when only registered while the flag is disabled
+	ResourceFeatureFlags   map[string]string `json:"resource_feature_flags"`    // {"azurerm_key_vault_access_policy": "!features.FivePointOh"}
+	DataSourceFeatureFlags map[string]string `json:"data_source_feature_flags"` // Keyed like ResourceFeatureFlags
+	EphemeralFeatureFlags  map[string]string `json:"ephemeral_feature_flags"`   // Keyed like ResourceFeatureFlags
+	// Emitted in separate files only, to keep the main index small
+	ResourceSchemas           map[string][]SchemaAttribute `json:"-"` // Written to the resource files and validations.json
+	ResourceAcceptanceTests   map[string][]AcceptanceTest  `json:"-"` // Written to tests/resources/
+	DataSourceAcceptanceTests map[string][]AcceptanceTest  `json:"-"` // Written to tests/datasources/
+	ResourceTimeouts          map[string]map[string]int    `json:"-"` // Written to the resource files and heatmap.json
+	ResourceAPIOperations     map[string][]APIOperation    `json:"-"` // Written to the resource files
+	ResourceGoDocs            map[string]*GoDoc            `json:"-"` // Written to the resource files
+	DataSourceGoDocs          map[string]*GoDoc            `json:"-"` // Written to the data source files
+	ResourceSources           map[string]*SourceLocations  `json:"-"` // Written to the resource files
+	ResourceModels            map[string]*ResourceModel    `json:"-"` // Written to the resource files
+	ResourceCommonIDs         map[string][]string          `json:"-"` // Written to the resource files
+	DataSourceModels          map[string]*ResourceModel    `json:"-"` // Written to the data source files
+	DataSourceSources         map[string]*SourceLocations  `json:"-"` // Written to the data source files
+	// Fields of the Client struct of the service's client package, written to clients/
+	Clients    []ServiceClient `json:"-"`
+	ClientFile string          `json:"-"` // "internal/services/keyvault/client/client.go", empty without a client package
+	// Registration function, struct type or constructor -> file of the registration method registering it
+	RegistrationFiles map[string]string `json:"-"`
+	// Legacy Terraform types registered by several files of the service, written to audit/duplicates.json
+	DuplicateRegistrations []DuplicateRegistration `json:"-"`
+	// Website documentation links, only set when documentation was linked
+	ResourceDocs   map[string]*DocumentationLink `json:"-"`
+	DataSourceDocs map[string]*DocumentationLink `json:"-"`
+	// User-maintained annotations by entry ID, only set when annotations were applied
+	Annotations map[string]map[string]interface{} `json:"-"`
+	// Corrected goindex references, empty when broken, only set when references were verified
+	GoIndexReferences map[string]string `json:"-"`
+	// Findings of custom extractors keyed by extractor name, see Extractor
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+	// What the orphans and unreferenced functions reports need from Package, kept when a StreamWriter drops it
+	orphans    []OrphanedImplementation
+	references *serviceReferences
 }
 
 func newServiceRegistration(packageInfo *gophon.PackageInfo, entry os.DirEntry) ServiceRegistration {
@@ -28,6 +94,7 @@ func newServiceRegistration(packageInfo *gophon.PackageInfo, entry os.DirEntry)
 		Package:                  packageInfo,
 		ServiceName:              entry.Name(),
 		PackagePath:              packageInfo.Files[0].Package,
+		ProductName:              DefaultProductNames[entry.Name()],
 		SupportedResources:       make(map[string]string),
 		SupportedDataSources:     make(map[string]string),
 		Resources:                []string{},
@@ -38,5 +105,131 @@ func newServiceRegistration(packageInfo *gophon.PackageInfo, entry os.DirEntry)
 		ResourceTerraformTypes:   make(map[string]string),
 		DataSourceTerraformTypes: make(map[string]string),
 		EphemeralTerraformTypes:  make(map[string]string),
+		EphemeralConstructors:    make(map[string]string),
+		TerraformTypeStrategies:  make(map[string]string),
+		ResourceStateUpgrades:    make(map[string]*StateUpgradeInfo),
+		ResourceCustomizeDiff:    make(map[string][]string),
+		ResourceDeprecations:     make(map[string]string),
+		DataSourceDeprecations:   make(map[string]string),
+		ResourceArmTypes:         make(map[string]string),
+		ResourceSDKPackages:      make(map[string][]string),
+		ResourceIDParsers:        make(map[string]string),
+		ResourceCapabilities:     make(map[string][]string),
+		ResourceImmutable:        make(map[string]bool),
+		ResourceFeatureFlags:     make(map[string]string),
+		DataSourceFeatureFlags:   make(map[string]string),
+		EphemeralFeatureFlags:    make(map[string]string),
+		ResourceSchemas:          make(map[string][]SchemaAttribute),
+		ResourceTimeouts:         make(map[string]map[string]int),
+		ResourceAPIOperations:    make(map[string][]APIOperation),
+		ResourceGoDocs:           make(map[string]*GoDoc),
+		DataSourceGoDocs:         make(map[string]*GoDoc),
+		ResourceSources:          make(map[string]*SourceLocations),
+		DataSourceSources:        make(map[string]*SourceLocations),
+		ResourceModels:           make(map[string]*ResourceModel),
+		ResourceCommonIDs:        make(map[string][]string),
+		DataSourceModels:         make(map[string]*ResourceModel),
+		Extensions:               make(map[string]interface{}),
 	}
 }
+
+// sortRegistrations sorts the slice-based registrations, which are collected in file order
+func (s *ServiceRegistration) sortRegistrations() {
+	sort.Strings(s.Resources)
+	sort.Strings(s.DataSources)
+	sort.Strings(s.EphemeralFunctions)
+	sort.Strings(s.ListResources)
+	sort.Strings(s.ListResourceFunctions)
+	sort.Strings(s.Actions)
+	sort.Strings(s.ActionFunctions)
+	sort.Strings(s.ConditionalResources)
+	sort.Strings(s.ConditionalDataSources)
+}
+
+// isConditional reports whether a modern registration is only made under a condition
+func isConditional(conditionalStructTypes []string, structType string) bool {
+	for _, conditional := range conditionalStructTypes {
+		if conditional == structType {
+			return true
+		}
+	}
+	return false
+}
+
+// listResourceTerraformType returns the Terraform type of a list resource struct, falling back to the struct name
+// when the type couldn't be resolved
+func (s ServiceRegistration) listResourceTerraformType(structType string) string {
+	if terraformType, exists := s.ListResourceTerraformTypes[structType]; exists {
+		return terraformType
+	}
+	return structType
+}
+
+// actionTerraformType returns the Terraform type of an action struct, falling back to the struct name when the type
+// couldn't be resolved
+func (s ServiceRegistration) actionTerraformType(structType string) string {
+	if terraformType, exists := s.ActionTerraformTypes[structType]; exists {
+		return terraformType
+	}
+	return structType
+}
+
+// resourceTerraformType returns the Terraform type of a modern resource struct, falling back to the struct name
+// when the type couldn't be resolved
+func (s ServiceRegistration) resourceTerraformType(structType string) string {
+	if terraformType, exists := s.ResourceTerraformTypes[structType]; exists {
+		return terraformType
+	}
+	return structType
+}
+
+// dataSourceTerraformType returns the Terraform type of a modern data source struct, falling back to the struct name
+// when the type couldn't be resolved
+func (s ServiceRegistration) dataSourceTerraformType(structType string) string {
+	if terraformType, exists := s.DataSourceTerraformTypes[structType]; exists {
+		return terraformType
+	}
+	return structType
+}
+
+// resourceSDKType returns the SDK type of a resource registered by the service, "legacy_pluginsdk" or "modern_sdk"
+func (s ServiceRegistration) resourceSDKType(terraformType string) string {
+	if _, exists := s.SupportedResources[terraformType]; exists {
+		return "legacy_pluginsdk"
+	}
+	return "modern_sdk"
+}
+
+// dataSourceSDKType returns the SDK type of a data source registered by the service, "legacy_pluginsdk" or "modern_sdk"
+func (s ServiceRegistration) dataSourceSDKType(terraformType string) string {
+	if _, exists := s.SupportedDataSources[terraformType]; exists {
+		return "legacy_pluginsdk"
+	}
+	return "modern_sdk"
+}
+
+// hasResource reports whether the service registers the Terraform resource type, either legacy or modern
+func (s ServiceRegistration) hasResource(terraformType string) bool {
+	if _, exists := s.SupportedResources[terraformType]; exists {
+		return true
+	}
+	for _, structType := range s.Resources {
+		if s.resourceTerraformType(structType) == terraformType {
+			return true
+		}
+	}
+	return false
+}
+
+// hasDataSource reports whether the service registers the Terraform data source type, either legacy or modern
+func (s ServiceRegistration) hasDataSource(terraformType string) bool {
+	if _, exists := s.SupportedDataSources[terraformType]; exists {
+		return true
+	}
+	for _, structType := range s.DataSources {
+		if s.dataSourceTerraformType(structType) == terraformType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package pkg
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// ServiceSummary summarizes one service, written to services/<service name>.json with OutputConfig.ServiceSummaries
+type ServiceSummary struct {
+	ServiceName       string            `json:"service_name"`                 // "keyvault"
+	PackagePath       string            `json:"package_path"`                 // "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault"
+	DisplayName       string            `json:"display_name,omitempty"`       // "Key Vault"
+	WebsiteCategories []string          `json:"website_categories,omitempty"` // ["Key Vault"]
+	GitHubLabel       string            `json:"github_label,omitempty"`       // "service/key-vault"
+	Statistics        ServiceStatistics `json:"statistics"`                   // Registration counts of the service
+	Resources         []string          `json:"resources"`                    // Sorted Terraform types, ["azurerm_key_vault"]
+	DataSources       []string          `json:"data_sources"`                 // Sorted Terraform types, ["azurerm_key_vault"]
+	Ephemeral         []string          `json:"ephemeral"`                    // Sorted Terraform types, ["azurerm_key_vault_secret"]
+}
+
+// BuildServiceSummaries summarizes every service of the index, in service order
+func (index *TerraformProviderIndex) BuildServiceSummaries() []ServiceSummary {
+	statistics := buildProviderStatistics(index.Services).Services
+	summaries := make([]ServiceSummary, 0, len(index.Services))
+	for _, service := range index.Services {
+		summary := ServiceSummary{
+			ServiceName:       service.ServiceName,
+			PackagePath:       service.PackagePath,
+			DisplayName:       service.DisplayName,
+			WebsiteCategories: service.WebsiteCategories,
+			GitHubLabel:       service.GitHubLabel,
+			Statistics:        statistics[service.ServiceName],
+			Resources:         []string{},
+			DataSources:       []string{},
+			Ephemeral:         []string{},
+		}
+		for terraformType := range service.SupportedResources {
+			summary.Resources = append(summary.Resources, terraformType)
+		}
+		for _, structType := range service.Resources {
+			summary.Resources = append(summary.Resources, service.resourceTerraformType(structType))
+		}
+		for terraformType := range service.SupportedDataSources {
+			summary.DataSources = append(summary.DataSources, terraformType)
+		}
+		for _, structType := range service.DataSources {
+			summary.DataSources = append(summary.DataSources, service.dataSourceTerraformType(structType))
+		}
+		for _, terraformType := range service.EphemeralTerraformTypes {
+			summary.Ephemeral = append(summary.Ephemeral, terraformType)
+		}
+		sort.Strings(summary.Resources)
+		sort.Strings(summary.DataSources)
+		sort.Strings(summary.Ephemeral)
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// writeServiceSummaryFiles writes services/<service name>.json for every service
+func (index *TerraformProviderIndex) writeServiceSummaryFiles(outputDir string, progressTracker *ProgressTracker) error {
+	for _, summary := range index.BuildServiceSummaries() {
+		fileName := summary.ServiceName + ".json"
+		if err := index.writeJSONFile(filepath.Join(outputDir, "services", fileName), summary); err != nil {
+			return fmt.Errorf("failed to write service summary file %s: %w", fileName, err)
+		}
+		progressTracker.UpdateProgress(fmt.Sprintf("service %s", summary.ServiceName))
+	}
+	return nil
+}
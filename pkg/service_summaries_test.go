@@ -0,0 +1,50 @@
+package pkg
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformProviderIndex_BuildServiceSummaries(t *testing.T) {
+	index := createTestTerraformProviderIndex()
+
+	summaries := index.BuildServiceSummaries()
+
+	require.Len(t, summaries, 1)
+	summary := summaries[0]
+	assert.Equal(t, "keyvault", summary.ServiceName)
+	assert.Equal(t, []string{
+		"azurerm_key_vault",
+		"azurerm_key_vault_certificate",
+		"azurerm_key_vault_certificate_modern",
+		"azurerm_key_vault_modern",
+	}, summary.Resources)
+	assert.Equal(t, []string{"azurerm_key_vault", "azurerm_key_vault_data_modern", "azurerm_key_vault_key"}, summary.DataSources)
+	assert.Equal(t, []string{"azurerm_key_vault_certificate_ephemeral"}, summary.Ephemeral)
+	assert.Equal(t, ServiceStatistics{
+		LegacyResources:    2,
+		ModernResources:    2,
+		DataSources:        3,
+		LegacyDataSources:  2,
+		ModernDataSources:  1,
+		EphemeralResources: 1,
+	}, summary.Statistics)
+}
+
+func TestTerraformProviderIndex_WriteServiceSummaryFiles(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	index := createTestTerraformProviderIndex()
+	index.Output.Fs = fs
+
+	require.NoError(t, index.writeServiceSummaryFiles("/index", nil))
+
+	data, err := afero.ReadFile(fs, "/index/services/keyvault.json")
+	require.NoError(t, err)
+	var summary ServiceSummary
+	require.NoError(t, json.Unmarshal(data, &summary))
+	assert.Equal(t, index.BuildServiceSummaries()[0], summary)
+}
@@ -0,0 +1,73 @@
+package pkg
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// SingleFileIndex is the content of the main index file written in single-file mode: the main index with every
+// document embedded, for consumers preferring one artifact over a file per document
+type SingleFileIndex struct {
+	TerraformProviderIndex
+	Documents SingleFileDocuments `json:"documents"` // The documents otherwise written to their own files
+}
+
+// SingleFileDocuments are the documents of a single-file index keyed by Terraform type, each kind under the name of
+// the directory its files are otherwise written to
+type SingleFileDocuments struct {
+	Resources     map[string]TerraformResource     `json:"resources"`     // Documents of resources/
+	DataSources   map[string]TerraformDataSource   `json:"datasources"`   // Documents of datasources/
+	Ephemeral     map[string]TerraformEphemeral    `json:"ephemeral"`     // Documents of ephemeral/
+	ListResources map[string]TerraformListResource `json:"listresources"` // Documents of listresources/
+	Actions       map[string]TerraformAction       `json:"actions"`       // Documents of actions/
+}
+
+// BuildSingleFileIndex embeds the documents of the index into a SingleFileIndex
+func (index *TerraformProviderIndex) BuildSingleFileIndex() SingleFileIndex {
+	singleFile := SingleFileIndex{
+		TerraformProviderIndex: *index,
+		Documents: SingleFileDocuments{
+			Resources:     make(map[string]TerraformResource),
+			DataSources:   make(map[string]TerraformDataSource),
+			Ephemeral:     make(map[string]TerraformEphemeral),
+			ListResources: make(map[string]TerraformListResource),
+			Actions:       make(map[string]TerraformAction),
+		},
+	}
+	for _, document := range index.Documents() {
+		switch content := document.Content.(type) {
+		case TerraformResource:
+			singleFile.Documents.Resources[document.TerraformType] = content
+		case TerraformDataSource:
+			singleFile.Documents.DataSources[document.TerraformType] = content
+		case TerraformEphemeral:
+			singleFile.Documents.Ephemeral[document.TerraformType] = content
+		case TerraformListResource:
+			singleFile.Documents.ListResources[document.TerraformType] = content
+		case TerraformAction:
+			singleFile.Documents.Actions[document.TerraformType] = content
+		}
+	}
+	return singleFile
+}
+
+// writeSingleFile writes the main index file with every document embedded, see SingleFileIndex
+func (index *TerraformProviderIndex) writeSingleFile(outputDir string, progressCallback ProgressCallback) error {
+	progressTracker := NewProgressTracker("writing", 1, progressCallback)
+	fileName := index.Output.MainIndexFileName()
+	if err := index.writeJSONFile(filepath.Join(outputDir, fileName), index.BuildSingleFileIndex()); err != nil {
+		return fmt.Errorf("failed to write single-file index: %w", err)
+	}
+	progressTracker.UpdateProgress(fileName)
+	progressTracker.Complete()
+	return nil
+}
+
+// LoadSingleFileIndex reads a main index file written in single-file mode
+func LoadSingleFileIndex(filePath string) (*SingleFileIndex, error) {
+	singleFile := &SingleFileIndex{}
+	if err := readIndexJSONFile(filePath, singleFile); err != nil {
+		return nil, err
+	}
+	return singleFile, nil
+}
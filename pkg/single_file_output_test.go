@@ -0,0 +1,58 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteIndexFiles_SingleFile(t *testing.T) {
+	index, err := ScanTerraformProviderServices(filepath.Join("testharness", "internal", "services"), "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+	require.NoError(t, err)
+	index.Output.SingleFile = true
+
+	fs := afero.NewMemMapFs()
+	index.Output.Fs = fs
+	require.NoError(t, index.WriteIndexFiles("/index", nil))
+
+	assert.Equal(t, []string{"/index/terraform-provider-azurerm-index.json"}, mapKeys(readAllFiles(t, fs)))
+
+	data, err := afero.ReadFile(fs, "/index/terraform-provider-azurerm-index.json")
+	require.NoError(t, err)
+	schema, err := JSONSchema(JSONSchemaSingleFile)
+	require.NoError(t, err)
+	violations, err := validateJSONSchema(schema, data)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+
+	filePath := filepath.Join(t.TempDir(), "terraform-provider-azurerm-index.json")
+	require.NoError(t, os.WriteFile(filePath, data, 0644))
+	singleFile, err := LoadSingleFileIndex(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, index.Version, singleFile.Version)
+	assert.Equal(t, index.Statistics, singleFile.Statistics)
+	documents := singleFile.Documents
+	assert.Len(t, index.Documents(), len(documents.Resources)+len(documents.DataSources)+len(documents.Ephemeral)+len(documents.ListResources)+len(documents.Actions))
+
+	resource, ok := index.LookupResource("azurerm_key_vault")
+	require.True(t, ok)
+	assert.Equal(t, resource.ID, documents.Resources["azurerm_key_vault"].ID)
+	assert.Equal(t, resource.Schema, documents.Resources["azurerm_key_vault"].Schema)
+	assert.Contains(t, documents.DataSources, "azurerm_key_vault")
+	assert.Contains(t, documents.Ephemeral, "azurerm_key_vault_certificate")
+}
+
+// mapKeys returns the sorted keys of a map
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,117 @@
+package pkg
+
+import (
+	"path/filepath"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// SourceLocation locates a Go declaration in the provider source, for deep links to the defining code
+type SourceLocation struct {
+	SourceFile string `json:"source_file" protobuf:"1"` // "internal/services/keyvault/key_vault_resource.go", relative to the scan root
+	Line       int    `json:"line" protobuf:"2"`        // 42
+}
+
+// SourceLocations locates the declarations implementing a resource or data source
+type SourceLocations struct {
+	Declaration *SourceLocation           // Registration function of legacy registrations, struct of typed ones
+	CRUD        map[string]SourceLocation // "create", "read", "update" or "delete" -> CRUD function or method
+}
+
+// extractLegacySourceLocations locates a legacy registration function and the CRUD functions it references
+func extractLegacySourceLocations(registrationMethod string, crud map[string]string, packageInfo *gophon.PackageInfo, servicePath string) *SourceLocations {
+	locations := &SourceLocations{Declaration: sourceLocationOf(functionRange(packageInfo, registrationMethod), servicePath)}
+	for operation, function := range crud {
+		if location := sourceLocationOf(functionRange(packageInfo, function), servicePath); location != nil {
+			locations.addCRUD(operation, *location)
+		}
+	}
+	return locations.nonEmpty()
+}
+
+// extractTypedSourceLocations locates a typed resource or data source struct and its CRUD methods
+func extractTypedSourceLocations(structType string, packageInfo *gophon.PackageInfo, servicePath string) *SourceLocations {
+	locations := &SourceLocations{Declaration: sourceLocationOf(typeRange(packageInfo, structType), servicePath)}
+	for operation, method := range map[string]string{"create": "Create", "read": "Read", "update": "Update", "delete": "Delete"} {
+		if location := sourceLocationOf(methodRange(packageInfo, structType, method), servicePath); location != nil {
+			locations.addCRUD(operation, *location)
+		}
+	}
+	return locations.nonEmpty()
+}
+
+// legacyCRUDFunctions maps the operations of legacy CRUD functions to the names of the functions
+func legacyCRUDFunctions(crud *LegacyResourceCRUDFunctions) map[string]string {
+	if crud == nil {
+		return nil
+	}
+	return map[string]string{"create": crud.CreateMethod, "read": crud.ReadMethod, "update": crud.UpdateMethod, "delete": crud.DeleteMethod}
+}
+
+func (l *SourceLocations) addCRUD(operation string, location SourceLocation) {
+	if l.CRUD == nil {
+		l.CRUD = make(map[string]SourceLocation)
+	}
+	l.CRUD[operation] = location
+}
+
+func (l *SourceLocations) nonEmpty() *SourceLocations {
+	if l.Declaration == nil && len(l.CRUD) == 0 {
+		return nil
+	}
+	return l
+}
+
+// crudSources returns the CRUD locations of possibly missing source locations
+func (l *SourceLocations) crudSources() map[string]SourceLocation {
+	if l == nil {
+		return nil
+	}
+	return l.CRUD
+}
+
+// declaration returns the declaration of possibly missing source locations, an empty location when unknown
+func (l *SourceLocations) declaration() SourceLocation {
+	if l == nil || l.Declaration == nil {
+		return SourceLocation{}
+	}
+	return *l.Declaration
+}
+
+// sourceLocationOf converts the source range gophon recorded from the token.FileSet into a location in the service
+// directory, nil when the range is unknown
+func sourceLocationOf(declaration *gophon.Range, servicePath string) *SourceLocation {
+	if declaration == nil || declaration.FileInfo == nil || declaration.FileName == "" {
+		return nil
+	}
+	return &SourceLocation{
+		SourceFile: filepath.ToSlash(filepath.Join(servicePath, filepath.Base(declaration.FileName))),
+		Line:       declaration.StartLine,
+	}
+}
+
+// functionRange returns the source range of a package level function, nil when the package declares no such function
+func functionRange(packageInfo *gophon.PackageInfo, funcName string) *gophon.Range {
+	if packageInfo == nil || funcName == "" {
+		return nil
+	}
+	for _, funcInfo := range packageInfo.Functions {
+		if funcInfo.Name == funcName && funcInfo.FuncDecl != nil && funcInfo.FuncDecl.Recv == nil {
+			return funcInfo.Range
+		}
+	}
+	return nil
+}
+
+// typeRange returns the source range of a type declared in the package, nil when the package declares no such type
+func typeRange(packageInfo *gophon.PackageInfo, typeName string) *gophon.Range {
+	if packageInfo == nil {
+		return nil
+	}
+	for _, typeInfo := range packageInfo.Types {
+		if typeInfo.Name == typeName {
+			return typeInfo.Range
+		}
+	}
+	return nil
+}
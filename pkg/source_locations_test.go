@@ -0,0 +1,49 @@
+package pkg
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanTerraformProviderServices_SourceLocations(t *testing.T) {
+	index, err := ScanTerraformProviderServices(filepath.Join("testharness", "internal", "services"), "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+	require.NoError(t, err)
+
+	var service ServiceRegistration
+	for _, s := range index.Services {
+		if s.ServiceName == "keyvault" {
+			service = s
+		}
+	}
+	sourceFile := "testharness/internal/services/keyvault/registration.go"
+
+	legacy := newTerraformResourceInfo("azurerm_key_vault", "", "resourceKeyVault", "legacy_pluginsdk", service)
+	assert.Equal(t, sourceFile, legacy.SourceFile)
+	assert.Equal(t, 122, legacy.Line)
+
+	terraformType := service.resourceTerraformType("KeyVaultCertificateContactsResource")
+	typed := newTerraformResourceInfo(terraformType, "KeyVaultCertificateContactsResource", "", "modern_sdk", service)
+	assert.Equal(t, sourceFile, typed.SourceFile)
+	assert.Equal(t, 32, typed.Line)
+	assert.Equal(t, map[string]SourceLocation{
+		"create": {SourceFile: sourceFile, Line: 44},
+		"read":   {SourceFile: sourceFile, Line: 49},
+		"update": {SourceFile: sourceFile, Line: 54},
+		"delete": {SourceFile: sourceFile, Line: 59},
+	}, typed.CRUDSources)
+
+	dataSource := newTerraformDataSourceInfo(service.dataSourceTerraformType("EncryptedValueDataSource"), "EncryptedValueDataSource", "", "modern_sdk", service)
+	assert.Equal(t, 15, dataSource.Line)
+	assert.Equal(t, map[string]SourceLocation{"read": {SourceFile: sourceFile, Line: 27}}, dataSource.CRUDSources)
+}
+
+func TestSourceLocations_Missing(t *testing.T) {
+	var sources *SourceLocations
+
+	assert.Equal(t, SourceLocation{}, sources.declaration())
+	assert.Nil(t, sources.crudSources())
+	assert.Nil(t, extractTypedSourceLocations("KeyVaultResource", nil, "internal/services/keyvault"))
+}
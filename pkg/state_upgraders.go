@@ -0,0 +1,119 @@
+package pkg
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// StateUpgradeInfo represents the schema version and state migrations declared by a resource
+type StateUpgradeInfo struct {
+	SchemaVersion int      `json:"schema_version"`      // 2
+	Upgraders     []string `json:"upgraders,omitempty"` // ["migration.KeyVaultV0ToV1", "migration.KeyVaultV1ToV2"], ordered by source version
+}
+
+// extractLegacyStateUpgradersFromPackage extracts SchemaVersion and StateUpgraders from the pluginsdk.Resource
+// returned by a legacy registration function
+func extractLegacyStateUpgradersFromPackage(registrationMethod string, packageInfo *gophon.PackageInfo) *StateUpgradeInfo {
+	for _, compLit := range findResourceLiterals(findFunctionDecl(packageInfo, registrationMethod)) {
+		if info := extractStateUpgradeInfo(compLit, "StateUpgraders"); info != nil {
+			return info
+		}
+	}
+	return nil
+}
+
+// extractTypedStateUpgradersFromPackage extracts the sdk.StateUpgradeData returned by the StateUpgraders method of a typed resource
+func extractTypedStateUpgradersFromPackage(structName string, packageInfo *gophon.PackageInfo) *StateUpgradeInfo {
+	compLit := returnedCompositeLit(findMethodDecl(packageInfo, structName, "StateUpgraders"))
+	return extractStateUpgradeInfo(compLit, "Upgraders")
+}
+
+// extractStateUpgradeInfo reads the SchemaVersion field and the named upgraders field from a composite literal
+func extractStateUpgradeInfo(compLit *ast.CompositeLit, upgradersField string) *StateUpgradeInfo {
+	if compLit == nil {
+		return nil
+	}
+
+	info := &StateUpgradeInfo{}
+	if basicLit, ok := compositeLitField(compLit, "SchemaVersion").(*ast.BasicLit); ok && basicLit.Kind == token.INT {
+		info.SchemaVersion, _ = strconv.Atoi(basicLit.Value)
+	}
+	info.Upgraders = extractStateUpgraders(compositeLitField(compLit, upgradersField))
+
+	if info.SchemaVersion == 0 && len(info.Upgraders) == 0 {
+		return nil
+	}
+	return info
+}
+
+// extractStateUpgraders extracts the migration references from the supported upgrader declarations:
+// - pluginsdk.StateUpgrades(map[int]pluginsdk.StateUpgrade{0: migration.KeyVaultV0ToV1{}})
+// - map[int]pluginsdk.StateUpgrade{0: migration.KeyVaultV0ToV1{}}
+// - []pluginsdk.StateUpgrader{{Version: 0, Upgrade: resourceKeyVaultStateUpgradeV0}}
+func extractStateUpgraders(expr ast.Expr) []string {
+	if callExpr, ok := expr.(*ast.CallExpr); ok && len(callExpr.Args) == 1 {
+		expr = callExpr.Args[0]
+	}
+	compLit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+
+	type versionedUpgrader struct {
+		version  int
+		upgrader string
+	}
+	var upgraders []versionedUpgrader
+
+	for i, elt := range compLit.Elts {
+		switch e := elt.(type) {
+		case *ast.KeyValueExpr:
+			// Map form keyed by the source schema version
+			version := i
+			if basicLit, ok := e.Key.(*ast.BasicLit); ok && basicLit.Kind == token.INT {
+				version, _ = strconv.Atoi(basicLit.Value)
+			}
+			if upgrader := stateUpgraderReference(e.Value); upgrader != "" {
+				upgraders = append(upgraders, versionedUpgrader{version: version, upgrader: upgrader})
+			}
+		case *ast.CompositeLit:
+			// Slice form with explicit Version and Upgrade fields
+			version := i
+			if basicLit, ok := compositeLitField(e, "Version").(*ast.BasicLit); ok && basicLit.Kind == token.INT {
+				version, _ = strconv.Atoi(basicLit.Value)
+			}
+			if upgrade := compositeLitField(e, "Upgrade"); upgrade != nil {
+				upgraders = append(upgraders, versionedUpgrader{version: version, upgrader: types.ExprString(upgrade)})
+			}
+		}
+	}
+
+	sort.SliceStable(upgraders, func(i, j int) bool {
+		return upgraders[i].version < upgraders[j].version
+	})
+
+	result := make([]string, 0, len(upgraders))
+	for _, u := range upgraders {
+		result = append(result, u.upgrader)
+	}
+	return result
+}
+
+// stateUpgraderReference renders a migration value such as migration.KeyVaultV0ToV1{} as "migration.KeyVaultV0ToV1"
+func stateUpgraderReference(expr ast.Expr) string {
+	if unaryExpr, ok := expr.(*ast.UnaryExpr); ok && unaryExpr.Op == token.AND {
+		expr = unaryExpr.X
+	}
+	if compLit, ok := expr.(*ast.CompositeLit); ok {
+		if compLit.Type == nil {
+			return ""
+		}
+		return types.ExprString(compLit.Type)
+	}
+	return types.ExprString(expr)
+}
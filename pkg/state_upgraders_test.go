@@ -0,0 +1,127 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractLegacyStateUpgradersFromPackage_StateUpgradesMap(t *testing.T) {
+	source := `package keyvault
+
+func resourceKeyVault() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Create: resourceKeyVaultCreate,
+		Read:   resourceKeyVaultRead,
+
+		SchemaVersion: 2,
+		StateUpgraders: pluginsdk.StateUpgrades(map[int]pluginsdk.StateUpgrade{
+			1: migration.KeyVaultV1ToV2{},
+			0: migration.KeyVaultV0ToV1{},
+		}),
+	}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+	result := extractLegacyStateUpgradersFromPackage("resourceKeyVault", packageInfo)
+
+	require.NotNil(t, result)
+	assert.Equal(t, &StateUpgradeInfo{
+		SchemaVersion: 2,
+		Upgraders:     []string{"migration.KeyVaultV0ToV1", "migration.KeyVaultV1ToV2"},
+	}, result)
+}
+
+func TestExtractLegacyStateUpgradersFromPackage_VariableAssignment(t *testing.T) {
+	source := `package storage
+
+func resourceStorageAccount() *pluginsdk.Resource {
+	resource := &pluginsdk.Resource{
+		SchemaVersion: 1,
+		StateUpgraders: []pluginsdk.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceStorageAccountV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceStorageAccountStateUpgradeV0,
+			},
+		},
+	}
+	return resource
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+	result := extractLegacyStateUpgradersFromPackage("resourceStorageAccount", packageInfo)
+
+	require.NotNil(t, result)
+	assert.Equal(t, 1, result.SchemaVersion)
+	assert.Equal(t, []string{"resourceStorageAccountStateUpgradeV0"}, result.Upgraders)
+}
+
+func TestExtractLegacyStateUpgradersFromPackage_NoUpgraders(t *testing.T) {
+	source := `package resource
+
+func resourceResourceGroup() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Create: resourceResourceGroupCreate,
+	}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+	assert.Nil(t, extractLegacyStateUpgradersFromPackage("resourceResourceGroup", packageInfo))
+	assert.Nil(t, extractLegacyStateUpgradersFromPackage("resourceNotFound", packageInfo))
+	assert.Nil(t, extractLegacyStateUpgradersFromPackage("resourceResourceGroup", nil))
+}
+
+func TestExtractTypedStateUpgradersFromPackage(t *testing.T) {
+	source := `package containerapps
+
+type ContainerAppResource struct{}
+
+func (r ContainerAppResource) ResourceType() string {
+	return "azurerm_container_app"
+}
+
+func (r *ContainerAppResource) StateUpgraders() sdk.StateUpgradeData {
+	return sdk.StateUpgradeData{
+		SchemaVersion: 1,
+		Upgraders: map[int]pluginsdk.StateUpgrade{
+			0: migration.ContainerAppV0toV1{},
+		},
+	}
+}
+
+type ContainerAppEnvironmentResource struct{}
+`
+
+	packageInfo := parsePackageInfo(t, source)
+
+	result := extractTypedStateUpgradersFromPackage("ContainerAppResource", packageInfo)
+	require.NotNil(t, result)
+	assert.Equal(t, &StateUpgradeInfo{
+		SchemaVersion: 1,
+		Upgraders:     []string{"migration.ContainerAppV0toV1"},
+	}, result)
+
+	assert.Nil(t, extractTypedStateUpgradersFromPackage("ContainerAppEnvironmentResource", packageInfo))
+}
+
+func TestNewTerraformResourceInfo_StateUpgrades(t *testing.T) {
+	serviceReg := ServiceRegistration{
+		PackagePath: "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault",
+		ResourceStateUpgrades: map[string]*StateUpgradeInfo{
+			"azurerm_key_vault": {
+				SchemaVersion: 2,
+				Upgraders:     []string{"migration.KeyVaultV0ToV1", "migration.KeyVaultV1ToV2"},
+			},
+		},
+	}
+
+	result := newTerraformResourceInfo("azurerm_key_vault", "", "resourceKeyVault", "legacy_pluginsdk", serviceReg)
+	assert.Equal(t, 2, result.SchemaVersion)
+	assert.Equal(t, []string{"migration.KeyVaultV0ToV1", "migration.KeyVaultV1ToV2"}, result.StateUpgraders)
+
+	result = newTerraformResourceInfo("azurerm_key_vault_secret", "", "resourceKeyVaultSecret", "legacy_pluginsdk", serviceReg)
+	assert.Zero(t, result.SchemaVersion)
+	assert.Empty(t, result.StateUpgraders)
+}
@@ -0,0 +1,110 @@
+package pkg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// StatsHistoryFileName is the default file name of the statistics history, kept next to the index outputs
+const StatsHistoryFileName = "stats-history.jsonl"
+
+// StatsHistoryEntry is one line of the statistics history, summarizing one indexed provider version
+type StatsHistoryEntry struct {
+	Version    string                       `json:"version"`    // "v4.20.0"
+	Statistics ProviderStatistics           `json:"statistics"` // Provider totals
+	Services   map[string]ServiceStatistics `json:"services"`   // Service name -> registration counts
+}
+
+// StatsHistoryEntry summarizes the statistics of the index for the statistics history, the per-service breakdown is
+// kept once under Services rather than repeated in Statistics
+func (index *TerraformProviderIndex) StatsHistoryEntry() StatsHistoryEntry {
+	entry := StatsHistoryEntry{
+		Version:    index.Version,
+		Statistics: index.Statistics,
+		Services:   buildProviderStatistics(index.Services).Services,
+	}
+	entry.Statistics.Services = nil
+	return entry
+}
+
+// ReadStatsHistory reads the entries of a statistics history in file order, a missing file is an empty history
+func ReadStatsHistory(filePath string) ([]StatsHistoryEntry, error) {
+	content, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read statistics history %s: %w", filePath, err)
+	}
+
+	var entries []StatsHistoryEntry
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	// An entry carries every service of the provider, longer than the default line limit
+	scanner.Buffer(nil, 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var entry StatsHistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse statistics history %s line %d: %w", filePath, line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read statistics history %s: %w", filePath, err)
+	}
+	return entries, nil
+}
+
+// PreviousStatsHistoryEntry returns the last entry of the history of a version other than version, nil when there is
+// none, the version a re-indexed or newly indexed version is compared with
+func PreviousStatsHistoryEntry(entries []StatsHistoryEntry, version string) *StatsHistoryEntry {
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Version != version {
+			return &entries[i]
+		}
+	}
+	return nil
+}
+
+// AppendStatsHistory appends the entry to the statistics history at filePath. Re-indexing a version replaces its
+// existing line in place, so the history holds one line per version in the order versions were first indexed.
+func AppendStatsHistory(filePath string, entry StatsHistoryEntry) error {
+	entries, err := ReadStatsHistory(filePath)
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i := range entries {
+		if entries[i].Version == entry.Version {
+			entries[i] = entry
+			replaced = true
+		}
+	}
+	if !replaced {
+		entries = append(entries, entry)
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	for _, e := range entries {
+		if err := encoder.Encode(e); err != nil {
+			return fmt.Errorf("failed to marshal statistics of %s: %w", e.Version, err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create parent directory of %s: %w", filePath, err)
+	}
+	if err := os.WriteFile(filePath, buffer.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", filePath, err)
+	}
+	return nil
+}
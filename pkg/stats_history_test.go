@@ -0,0 +1,74 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformProviderIndex_StatsHistoryEntry(t *testing.T) {
+	index := createTestTerraformProviderIndex()
+
+	entry := index.StatsHistoryEntry()
+
+	assert.Equal(t, index.Version, entry.Version)
+	statistics := index.Statistics
+	statistics.Services = nil
+	assert.Equal(t, statistics, entry.Statistics)
+	require.Len(t, entry.Services, len(index.Services))
+	service := index.Services[0]
+	assert.Equal(t, ServiceStatistics{
+		LegacyResources:    len(service.SupportedResources),
+		ModernResources:    len(service.Resources),
+		DataSources:        len(service.SupportedDataSources) + len(service.DataSources),
+		LegacyDataSources:  len(service.SupportedDataSources),
+		ModernDataSources:  len(service.DataSources),
+		EphemeralResources: len(service.EphemeralFunctions),
+	}, entry.Services[service.ServiceName])
+}
+
+func TestAppendStatsHistory(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "history", StatsHistoryFileName)
+
+	entries, err := ReadStatsHistory(filePath)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	v1 := StatsHistoryEntry{Version: "v1.0.0", Statistics: ProviderStatistics{ServiceCount: 1}, Services: map[string]ServiceStatistics{"keyvault": {LegacyResources: 2}}}
+	v2 := StatsHistoryEntry{Version: "v2.0.0", Statistics: ProviderStatistics{ServiceCount: 2}, Services: map[string]ServiceStatistics{"keyvault": {LegacyResources: 3}}}
+	require.NoError(t, AppendStatsHistory(filePath, v1))
+	require.NoError(t, AppendStatsHistory(filePath, v2))
+
+	content, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSuffix(string(content), "\n"), "\n"), 2)
+
+	// Re-indexing a version replaces its line instead of appending a duplicate
+	v1.Statistics.ServiceCount = 5
+	require.NoError(t, AppendStatsHistory(filePath, v1))
+
+	entries, err = ReadStatsHistory(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, []StatsHistoryEntry{v1, v2}, entries)
+}
+
+func TestReadStatsHistory_InvalidLine(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), StatsHistoryFileName)
+	require.NoError(t, os.WriteFile(filePath, []byte("{\"version\":\"v1.0.0\"}\nnot json\n"), 0644))
+
+	_, err := ReadStatsHistory(filePath)
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestPreviousStatsHistoryEntry(t *testing.T) {
+	entries := []StatsHistoryEntry{{Version: "v4.19.0"}, {Version: "v4.20.0"}, {Version: "v4.21.0"}}
+
+	assert.Equal(t, "v4.20.0", PreviousStatsHistoryEntry(entries, "v4.21.0").Version)
+	assert.Equal(t, "v4.21.0", PreviousStatsHistoryEntry(entries, "v4.22.0").Version)
+	assert.Nil(t, PreviousStatsHistoryEntry(entries[:1], "v4.19.0"))
+	assert.Nil(t, PreviousStatsHistoryEntry(nil, "v4.19.0"))
+}
@@ -0,0 +1,47 @@
+package pkg
+
+import (
+	"fmt"
+
+	"github.com/spf13/afero"
+)
+
+// StreamWriter writes the per-resource files of the json format while the scan is still running: the resource, data
+// source, ephemeral resource, list resource and action documents and the acceptance tests of a service are written
+// as soon as the service is scanned, and its parsed package is dropped afterwards. Memory no longer grows with the
+// syntax trees of every service, WriteIndexFiles to the same directory then only writes the main index, the summary
+// and the audit files.
+//
+// Documents are written as they were scanned, so enrichments of the documents applied to the index after the scan,
+// LinkDocumentation, ApplyAnnotations and VerifyGoIndexReferences, don't reach them. Enrich documents with
+// DocumentHooks instead.
+type StreamWriter struct {
+	OutputDir string   // "./index", the directory WriteIndexFiles writes the rest of the index to
+	Fs        afero.Fs // The file system the files are written to, see OutputConfig.Fs, the local one when nil
+}
+
+// writeService writes the files of a scanned service, vetoed documents left out, and drops its parsed package after
+// keeping what the orphans and unreferenced functions reports need from it
+func (w *StreamWriter) writeService(serviceReg *ServiceRegistration, scanner Scanner) error {
+	serviceReg.sortRegistrations()
+	service := &TerraformProviderIndex{
+		Services: []ServiceRegistration{*serviceReg},
+		Output:   OutputConfig{Workers: scanner.Workers, Fs: w.Fs},
+		Events:   scanner.Events,
+		Hooks:    scanner.Hooks,
+	}
+	service.removeVetoedDocuments(scanner.Hooks)
+	*serviceReg = service.Services[0]
+
+	if err := service.WriteDocumentFiles(w.OutputDir, nil); err != nil {
+		return err
+	}
+	if err := service.writeAcceptanceTestFiles(w.OutputDir, nil); err != nil {
+		return fmt.Errorf("failed to write acceptance test files: %w", err)
+	}
+
+	serviceReg.orphans = serviceReg.orphanedImplementations()
+	serviceReg.references = serviceReg.collectServiceReferences()
+	serviceReg.Package = nil
+	return nil
+}
@@ -0,0 +1,60 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanner_Stream(t *testing.T) {
+	servicesDir := filepath.Join("testharness", "internal", "services")
+	basePkgUrl := "github.com/lonegunmanb/terraform-provider-azurerm-index"
+	hooks := DocumentHooks{OnResourceDiscovered: func(document *IndexDocument) bool {
+		return document.TerraformType != "azurerm_account"
+	}}
+
+	written := afero.NewMemMapFs()
+	index, err := Scanner{Hooks: hooks}.Scan(servicesDir, basePkgUrl, "test-version", nil)
+	require.NoError(t, err)
+	index.Output.Fs = written
+	require.NoError(t, index.WriteIndexFiles("/index", nil))
+
+	streamed := afero.NewMemMapFs()
+	streamedIndex, err := Scanner{Hooks: hooks, Stream: &StreamWriter{OutputDir: "/index", Fs: streamed}}.Scan(servicesDir, basePkgUrl, "test-version", nil)
+	require.NoError(t, err)
+
+	for _, service := range streamedIndex.Services {
+		assert.Nil(t, service.Package, service.ServiceName)
+	}
+	exists, err := afero.Exists(streamed, "/index/resources/azurerm_key_vault.json")
+	require.NoError(t, err)
+	assert.True(t, exists, "documents are written while scanning")
+	exists, err = afero.Exists(streamed, "/index/resources/azurerm_account.json")
+	require.NoError(t, err)
+	assert.False(t, exists, "vetoed documents aren't streamed")
+
+	assert.Equal(t, index.BuildOrphansReport(), streamedIndex.BuildOrphansReport())
+	assert.Equal(t, index.BuildUnreferencedFunctionsReport(), streamedIndex.BuildUnreferencedFunctionsReport())
+
+	streamedIndex.Output.Fs = streamed
+	require.NoError(t, streamedIndex.WriteIndexFiles("/index", nil))
+	assert.Equal(t, readAllFiles(t, written), readAllFiles(t, streamed))
+}
+
+// readAllFiles returns the content of every file of fs by path
+func readAllFiles(t *testing.T, fs afero.Fs) map[string]string {
+	files := make(map[string]string)
+	require.NoError(t, afero.Walk(fs, "/", func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := afero.ReadFile(fs, path)
+		files[path] = string(data)
+		return err
+	}))
+	return files
+}
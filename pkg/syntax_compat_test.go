@@ -0,0 +1,91 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadSyntaxCompatCorpus parses the syntax compatibility corpus, source files using generics, any type parameters
+// and range-over-func the way newer provider code does
+func loadSyntaxCompatCorpus(t *testing.T) []string {
+	paths, err := filepath.Glob(filepath.Join("testdata", "syntaxcompat", "*.go"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	var sources []string
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		sources = append(sources, string(content))
+	}
+	return sources
+}
+
+func TestSyntaxCompat_Registrations(t *testing.T) {
+	sources := loadSyntaxCompatCorpus(t)
+	packageInfo := parsePackageInfo(t, sources...)
+
+	var supportedResources map[string]string
+	var resources []string
+	for _, fileInfo := range packageInfo.Files {
+		supportedResources = mergeMap(supportedResources, extractSupportedResourcesMappings(fileInfo.File))
+		resources = append(resources, extractResourcesStructTypes(fileInfo.File)...)
+	}
+
+	assert.Equal(t, map[string]string{"azurerm_generic_legacy": "resourceGenericLegacy"}, supportedResources)
+	assert.Equal(t, []string{"GenericResource", "PlainResource"}, resources)
+	winners := make(map[string]string)
+	assert.Equal(t, map[string]string{
+		"GenericResource": "azurerm_generic_widget",
+		"PlainResource":   "azurerm_plain_widget",
+	}, newTerraformTypeResolver(nil).resolve(packageInfo, resources, winners))
+	assert.Equal(t, map[string]string{
+		"GenericResource": TerraformTypeStrategyResourceTypeLiteral,
+		"PlainResource":   TerraformTypeStrategyResourceTypeLiteral,
+	}, winners)
+}
+
+func TestSyntaxCompat_GenericReceivers(t *testing.T) {
+	sources := loadSyntaxCompatCorpus(t)
+	packageInfo := parsePackageInfo(t, sources...)
+
+	read := findMethodDecl(packageInfo, "GenericResource", "Read")
+	require.NotNil(t, read)
+	assert.NotNil(t, read.Recv)
+
+	read = findFunctionDecl(packageInfo, "Read")
+	require.NotNil(t, read)
+	assert.Nil(t, read.Recv)
+
+	assert.Equal(t, "use azurerm_widget instead", extractTypedDeprecationFromPackage("GenericResource", packageInfo))
+	assert.Equal(t, []string{"github.com/hashicorp/go-azure-sdk/resource-manager/widgets/2024-01-01/widgets"},
+		extractTypedSDKPackagesFromPackage("GenericResource", packageInfo))
+}
+
+func TestSyntaxCompat_Schemas(t *testing.T) {
+	sources := loadSyntaxCompatCorpus(t)
+	packageInfo := parsePackageInfo(t, sources...)
+
+	assert.Equal(t, []SchemaAttribute{
+		{Name: "name", Type: "TypeString", ValidateFuncs: []string{"validate.NameOf", "validation.StringIsNotEmpty"}, Required: true},
+		{Name: "tags", SchemaFunc: "commonschema.TagsOf"},
+	}, extractTypedResourceSchemaFromPackage("GenericResource", packageInfo))
+
+	assert.Equal(t, []SchemaAttribute{
+		{Name: "count", Type: "TypeInt", ValidateFuncs: []string{"validation.IntBetween"}},
+	}, extractLegacyResourceSchemaFromPackage("resourceGenericLegacy", packageInfo))
+}
+
+func TestSyntaxCompat_LegacyCRUD(t *testing.T) {
+	sources := loadSyntaxCompatCorpus(t)
+	packageInfo := parsePackageInfo(t, sources...)
+
+	crudMethods := extractCRUDFromPackage("resourceGenericLegacy", packageInfo)
+	require.NotNil(t, crudMethods)
+	assert.Equal(t, "resourceGenericLegacyCreate", crudMethods.CreateMethod)
+	assert.Equal(t, "resourceGenericLegacyRead", crudMethods.ReadMethod)
+}
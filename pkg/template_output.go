@@ -0,0 +1,123 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/lonegunmanb/terraform-provider-azurerm-index/pkg/internal/parallel"
+	"github.com/spf13/afero"
+)
+
+// Output formats
+const (
+	OutputFormatJSON     = "json"
+	OutputFormatTemplate = "template"
+	OutputFormatESBulk   = "esbulk"
+	OutputFormatProto    = "proto"
+	OutputFormatParquet  = "parquet"
+	OutputFormatCSV      = "csv"
+)
+
+// TemplateData is the data a user supplied template is executed with, once per document
+type TemplateData struct {
+	Kind          string      // "resources", "datasources" or "ephemeral"
+	TerraformType string      // "azurerm_key_vault"
+	Service       string      // "keyvault"
+	Version       string      // Provider version
+	Document      interface{} // TerraformResource, TerraformDataSource or TerraformEphemeral
+}
+
+// templateFuncs are the helper functions available to user supplied templates
+var templateFuncs = template.FuncMap{
+	"join":    strings.Join,
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+	"replace": strings.ReplaceAll,
+	"json": func(v interface{}) (string, error) {
+		data, err := json.MarshalIndent(v, "", "  ")
+		return string(data), err
+	},
+}
+
+// LoadOutputTemplate parses a Go template file used with the template output format
+func LoadOutputTemplate(templatePath string) (*template.Template, error) {
+	content, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template %s: %w", templatePath, err)
+	}
+	tmpl, err := template.New(filepath.Base(templatePath)).Funcs(templateFuncs).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", templatePath, err)
+	}
+	return tmpl, nil
+}
+
+// writeTemplateFiles renders the configured template for each document of the index, writing the results to the
+// resources/, datasources/ and ephemeral/ directories in place of the JSON files
+func (index *TerraformProviderIndex) writeTemplateFiles(outputDir string, progressCallback ProgressCallback) error {
+	tmpl, err := LoadOutputTemplate(index.Output.TemplatePath)
+	if err != nil {
+		return err
+	}
+	extension := templateOutputExtension(index.Output.TemplatePath)
+
+	documents := index.Documents()
+	progressTracker := NewProgressTracker("rendering", len(documents), progressCallback)
+
+	if err := index.createDirectoryStructure(outputDir); err != nil {
+		return fmt.Errorf("failed to create directory structure: %w", err)
+	}
+
+	var tasks []func() error
+	for _, document := range documents {
+		// Capture variables for closure
+		doc := document
+
+		tasks = append(tasks, func() error {
+			var buf bytes.Buffer
+			err := tmpl.Execute(&buf, TemplateData{
+				Kind:          doc.Kind,
+				TerraformType: doc.TerraformType,
+				Service:       doc.Service,
+				Version:       index.Version,
+				Document:      doc.Content,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to render template for %s %s: %w", doc.Kind, doc.TerraformType, err)
+			}
+
+			fileName := doc.TerraformType + extension
+			if err := afero.WriteFile(index.Output.fs(), filepath.Join(outputDir, doc.Kind, fileName), buf.Bytes(), 0644); err != nil {
+				return fmt.Errorf("failed to write file %s: %w", fileName, err)
+			}
+
+			progressTracker.UpdateProgress(fmt.Sprintf("%s %s", doc.Kind, doc.TerraformType))
+			return nil
+		})
+	}
+
+	if err := parallel.Run(tasks, index.Output.Workers); err != nil {
+		return err
+	}
+
+	progressTracker.Complete()
+	return nil
+}
+
+// templateOutputExtension derives the extension of rendered files from the template file name, "page.md.tmpl"
+// results in ".md", templates without an inner extension such as "mytemplate.tmpl" render to ".txt" files
+func templateOutputExtension(templatePath string) string {
+	name := filepath.Base(templatePath)
+	for _, suffix := range []string{".tmpl", ".tpl", ".gotmpl"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	if extension := filepath.Ext(name); extension != "" {
+		return extension
+	}
+	return ".txt"
+}
@@ -0,0 +1,59 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateOutputExtension(t *testing.T) {
+	assert.Equal(t, ".md", templateOutputExtension("templates/page.md.tmpl"))
+	assert.Equal(t, ".txt", templateOutputExtension("mytemplate.tmpl"))
+	assert.Equal(t, ".html", templateOutputExtension("wiki.html"))
+}
+
+func TestTerraformProviderIndex_WriteIndexFiles_TemplateFormat(t *testing.T) {
+	// Setup
+	templatePath := filepath.Join(t.TempDir(), "page.md.tmpl")
+	require.NoError(t, os.WriteFile(templatePath, []byte(`# {{ .TerraformType }} ({{ .Kind }}, {{ .Version }})
+Namespace: {{ .Document.Namespace }}
+`), 0644))
+
+	index := createTestTerraformProviderIndex()
+	index.Output = OutputConfig{Format: OutputFormatTemplate, TemplatePath: templatePath}
+	fs := afero.NewMemMapFs()
+	index.Output.Fs = fs
+	outputDir := "/test/output"
+
+	// Execute
+	err := index.WriteIndexFiles(outputDir, nil)
+
+	// Verify
+	require.NoError(t, err)
+
+	for _, document := range index.Documents() {
+		data, err := afero.ReadFile(fs, filepath.Join(outputDir, document.Kind, document.TerraformType+".md"))
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "# "+document.TerraformType+" ("+document.Kind+", "+index.Version+")")
+		assert.Contains(t, string(data), "Namespace: github.com/")
+	}
+
+	exists, err := afero.Exists(fs, filepath.Join(outputDir, index.Output.MainIndexFileName()))
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestLoadOutputTemplate_Invalid(t *testing.T) {
+	templatePath := filepath.Join(t.TempDir(), "broken.tmpl")
+	require.NoError(t, os.WriteFile(templatePath, []byte(`{{ .TerraformType `), 0644))
+
+	_, err := LoadOutputTemplate(templatePath)
+	assert.ErrorContains(t, err, "failed to parse template")
+
+	_, err = LoadOutputTemplate(filepath.Join(t.TempDir(), "missing.tmpl"))
+	assert.ErrorContains(t, err, "failed to read template")
+}
@@ -0,0 +1,50 @@
+package pkg
+
+import "fmt"
+
+// TerraformAction represents information about a Terraform action, an operation Terraform invokes outside of the
+// create, read, update and delete lifecycle of a resource
+type TerraformAction struct {
+	ID                 string `json:"id" protobuf:"1"`                     // "azurerm/actions/azurerm_virtual_machine_power/action", see EntryID
+	TerraformType      string `json:"terraform_type" protobuf:"2"`         // "azurerm_virtual_machine_power"
+	StructType         string `json:"struct_type" protobuf:"3"`            // "VirtualMachinePowerAction"
+	Namespace          string `json:"namespace" protobuf:"4"`              // "github.com/hashicorp/terraform-provider-azurerm/internal/services/compute"
+	RegistrationMethod string `json:"registration_method" protobuf:"5"`    // "Actions"
+	SDKType            string `json:"sdk_type" protobuf:"6"`               // "action"
+	SchemaIndex        string `json:"schema_index,omitempty" protobuf:"7"` // "method.VirtualMachinePowerAction.Schema.goindex" (optional)
+	InvokeIndex        string `json:"invoke_index,omitempty" protobuf:"8"` // "method.VirtualMachinePowerAction.Invoke.goindex" (optional)
+	// Strategy that inferred the Terraform type, for debugging extraction quality
+	TerraformTypeStrategy string `json:"terraform_type_strategy,omitempty" protobuf:"9"` // "metadata" (optional)
+	// Service grouping declared by the registration, for category-based grouping of documentation
+	DisplayName       string   `json:"display_name,omitempty" protobuf:"10"`       // "Compute" (optional)
+	WebsiteCategories []string `json:"website_categories,omitempty" protobuf:"11"` // ["Compute"] (optional)
+	// Issue triage label declared by the registration, for routing questions about the action
+	GitHubLabel string `json:"github_label,omitempty" protobuf:"12"` // "service/virtual-machine" (optional)
+	// User-maintained metadata, only set when annotations were loaded with -annotations
+	XAnnotations map[string]interface{} `json:"x_annotations,omitempty" protobuf:"13"` // {"cost_tier": "high", "approval": "approved"} (optional)
+	// Constructor function registered by Actions, which StructType was resolved from
+	ConstructorFunction string `json:"constructor_function,omitempty" protobuf:"14"` // "NewVirtualMachinePowerAction" (optional)
+}
+
+// newTerraformActionInfo creates a TerraformAction struct
+func newTerraformActionInfo(structType string, service ServiceRegistration) TerraformAction {
+	terraformType := service.actionTerraformType(structType)
+	result := TerraformAction{
+		ID:                    EntryID(DocumentKindAction, terraformType, "action"),
+		TerraformType:         terraformType,
+		StructType:            structType,
+		Namespace:             service.PackagePath,
+		RegistrationMethod:    "Actions",
+		SDKType:               "action",
+		SchemaIndex:           fmt.Sprintf("method.%s.Schema.goindex", structType),
+		InvokeIndex:           fmt.Sprintf("method.%s.Invoke.goindex", structType),
+		TerraformTypeStrategy: service.TerraformTypeStrategies[structType],
+		ConstructorFunction:   service.ActionConstructors[structType],
+	}
+	result.DisplayName = service.DisplayName
+	result.WebsiteCategories = service.WebsiteCategories
+	result.GitHubLabel = service.GitHubLabel
+	result.XAnnotations = service.Annotations[result.ID]
+	service.resolveGoIndexReferences(result.goIndexReferences())
+	return result
+}
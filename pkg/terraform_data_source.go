@@ -4,18 +4,79 @@ import "fmt"
 
 // TerraformDataSource represents information about a Terraform data source
 type TerraformDataSource struct {
-	TerraformType      string `json:"terraform_type"`            // "azurerm_client_config"
-	StructType         string `json:"struct_type"`               // "ClientConfigDataSource"
-	Namespace          string `json:"namespace"`                 // "github.com/hashicorp/terraform-provider-azurerm/internal/services/client"
-	RegistrationMethod string `json:"registration_method"`       // "func.SupportedDataSources", "DataSources", etc.
-	SDKType            string `json:"sdk_type"`                  // "legacy_pluginsdk", "modern_sdk"
-	SchemaIndex        string `json:"schema_index,omitempty"`    // "func.dataSourceArmClientConfig.goindex" or "method.ContainerAppDataSource.Arguments.goindex"(optional)
-	ReadIndex          string `json:"read_index,omitempty"`      // "func.dataSourceArmClientConfigRead.goindex" or "method.ContainerAppDataSource.Read.goindex"(optional)
-	AttributeIndex     string `json:"attribute_index,omitempty"` // "func.dataSourceArmClientConfig.goindex" or "method.ContainerAppDataSource.Attributes.goindex"(optional)
+	ID                 string `json:"id" protobuf:"1"`                        // "azurerm/datasources/azurerm_client_config/legacy_pluginsdk", see EntryID
+	TerraformType      string `json:"terraform_type" protobuf:"2"`            // "azurerm_client_config"
+	StructType         string `json:"struct_type" protobuf:"3"`               // "ClientConfigDataSource"
+	Namespace          string `json:"namespace" protobuf:"4"`                 // "github.com/hashicorp/terraform-provider-azurerm/internal/services/client"
+	RegistrationMethod string `json:"registration_method" protobuf:"5"`       // "func.SupportedDataSources", "DataSources", etc.
+	SDKType            string `json:"sdk_type" protobuf:"6"`                  // "legacy_pluginsdk", "modern_sdk"
+	SchemaIndex        string `json:"schema_index,omitempty" protobuf:"7"`    // "func.dataSourceArmClientConfig.goindex" or "method.ContainerAppDataSource.Arguments.goindex"(optional)
+	ReadIndex          string `json:"read_index,omitempty" protobuf:"8"`      // "func.dataSourceArmClientConfigRead.goindex" or "method.ContainerAppDataSource.Read.goindex"(optional)
+	AttributeIndex     string `json:"attribute_index,omitempty" protobuf:"9"` // "func.dataSourceArmClientConfig.goindex" or "method.ContainerAppDataSource.Attributes.goindex"(optional)
+	// Strategy that inferred the Terraform type of a typed data source, for debugging extraction quality
+	TerraformTypeStrategy string `json:"terraform_type_strategy,omitempty" protobuf:"10"` // "resource_type_literal" (optional)
+	// Deprecation details, only set for deprecated data sources
+	Deprecated         bool   `json:"deprecated,omitempty" protobuf:"11"`          // true
+	DeprecationMessage string `json:"deprecation_message,omitempty" protobuf:"12"` // "This data source has been deprecated in favour of `azurerm_bar`"
+	// Website documentation, only set when documentation was linked with -docs-path
+	Documentation *DocumentationLink `json:"documentation,omitempty" protobuf:"13"` // {"doc_file": "website/docs/d/key_vault.html.markdown", ...} (optional)
+	// Set for typed data sources the service only registers under a condition, such as a feature flag
+	Conditional bool `json:"conditional,omitempty" protobuf:"14"` // true
+	// Service grouping declared by the registration, for category-based grouping of documentation
+	DisplayName       string   `json:"display_name,omitempty" protobuf:"15"`       // "Key Vault" (optional)
+	WebsiteCategories []string `json:"website_categories,omitempty" protobuf:"16"` // ["Key Vault"] (optional)
+	// Issue triage label declared by the registration, for routing questions about the resource
+	GitHubLabel string `json:"github_label,omitempty" protobuf:"17"` // "service/key-vault" (optional)
+	// User-maintained metadata, only set when annotations were loaded with -annotations
+	XAnnotations map[string]interface{} `json:"x_annotations,omitempty" protobuf:"18"` // {"cost_tier": "high", "approval": "approved"} (optional)
+	// Feature flag the registration is gated by, with a leading ! when only registered while the flag is disabled
+	FeatureFlag string `json:"feature_flag,omitempty" protobuf:"19"` // "features.FivePointOh" (optional)
+	// Arguments and exported attributes of legacy data sources, declared together in the Schema map
+	Schema []SchemaAttribute `json:"schema,omitempty" protobuf:"20"` // [{"name": "name", "type": "TypeString", "required": true}] (optional)
+	// Doc comments of the registration function or struct and the Read function
+	Doc *GoDoc `json:"doc,omitempty" protobuf:"21"` // {"registration": "...", "read": "..."} (optional)
+	// Location of the registration function or struct, for deep links to the defining code
+	SourceFile string `json:"source_file,omitempty" protobuf:"22"` // "internal/services/keyvault/key_vault_data_source.go" (optional)
+	Line       int    `json:"line,omitempty" protobuf:"23"`        // 24 (optional)
+	// Location of the Read function or method
+	CRUDSources map[string]SourceLocation `json:"crud_sources,omitempty" protobuf:"24"` // {"read": {"source_file": "...", "line": 120}} (optional)
+	// Fields of the model struct of typed data sources with the attributes their tfschema tags map them to
+	Model *ResourceModel `json:"model,omitempty" protobuf:"25"` // {"struct_type": "KeyVaultDataSourceModel", "fields": [{"field": "Name", "attribute": "name", ...}]} (optional)
 }
 
-// NewTerraformDataSourceInfo creates a TerraformDataSource struct
-func NewTerraformDataSourceInfo(terraformType, structType, registrationMethod, sdkType string, serviceReg ServiceRegistration) TerraformDataSource {
+// newTerraformDataSourceInfo creates a TerraformDataSource struct
+func newTerraformDataSourceInfo(terraformType, structType, registrationMethod, sdkType string, serviceReg ServiceRegistration) TerraformDataSource {
+	result := baseTerraformDataSourceInfo(terraformType, structType, registrationMethod, sdkType, serviceReg)
+	result.ID = EntryID(DocumentKindDataSource, terraformType, sdkType)
+	if structType != "" {
+		result.TerraformTypeStrategy = serviceReg.TerraformTypeStrategies[structType]
+		result.Conditional = isConditional(serviceReg.ConditionalDataSources, structType)
+	}
+	result.Documentation = serviceReg.DataSourceDocs[terraformType]
+	result.FeatureFlag = serviceReg.DataSourceFeatureFlags[terraformType]
+	result.Model = serviceReg.DataSourceModels[terraformType]
+	result.Doc = serviceReg.DataSourceGoDocs[terraformType]
+	sources := serviceReg.DataSourceSources[terraformType]
+	declaration := sources.declaration()
+	result.SourceFile = declaration.SourceFile
+	result.Line = declaration.Line
+	result.CRUDSources = sources.crudSources()
+	if methods := serviceReg.DataSourceMethods[terraformType]; methods != nil && sdkType == "legacy_pluginsdk" {
+		result.Schema = methods.Schema
+	}
+	if message, exists := serviceReg.DataSourceDeprecations[terraformType]; exists {
+		result.Deprecated = true
+		result.DeprecationMessage = message
+	}
+	result.DisplayName = serviceReg.DisplayName
+	result.WebsiteCategories = serviceReg.WebsiteCategories
+	result.GitHubLabel = serviceReg.GitHubLabel
+	result.XAnnotations = serviceReg.Annotations[result.ID]
+	serviceReg.resolveGoIndexReferences(result.goIndexReferences())
+	return result
+}
+
+func baseTerraformDataSourceInfo(terraformType, structType, registrationMethod, sdkType string, serviceReg ServiceRegistration) TerraformDataSource {
 	if sdkType == "legacy_pluginsdk" {
 		return TerraformDataSource{
 			TerraformType:      terraformType,
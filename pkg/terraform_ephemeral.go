@@ -4,20 +4,35 @@ import "fmt"
 
 // TerraformEphemeral represents information about a Terraform ephemeral resource
 type TerraformEphemeral struct {
-	TerraformType      string `json:"terraform_type"`         // "azurerm_key_vault_certificate"
-	StructType         string `json:"struct_type"`            // "KeyVaultCertificateEphemeralResource"
-	Namespace          string `json:"namespace"`              // "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault"
-	RegistrationMethod string `json:"registration_method"`    // "EphemeralResources"
-	SDKType            string `json:"sdk_type"`               // "ephemeral"
-	SchemaIndex        string `json:"schema_index,omitempty"` // "method.KeyVaultSecretEphemeralResource.Schema.goindex" (optional)
-	OpenIndex          string `json:"open_index,omitempty"`   // "method.KeyVaultSecretEphemeralResource.Open.goindex" (optional)
-	RenewIndex         string `json:"renew_index,omitempty"`  // "method.KeyVaultSecretEphemeralResource.Renew.goindex" (optional)
-	CloseIndex         string `json:"close_index,omitempty"`  // "method.KeyVaultSecretEphemeralResource.Close.goindex" (optional)
+	ID                 string `json:"id" protobuf:"1"`                     // "azurerm/ephemeral/azurerm_key_vault_certificate/ephemeral", see EntryID
+	TerraformType      string `json:"terraform_type" protobuf:"2"`         // "azurerm_key_vault_certificate"
+	StructType         string `json:"struct_type" protobuf:"3"`            // "KeyVaultCertificateEphemeralResource"
+	Namespace          string `json:"namespace" protobuf:"4"`              // "github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault"
+	RegistrationMethod string `json:"registration_method" protobuf:"5"`    // "EphemeralResources"
+	SDKType            string `json:"sdk_type" protobuf:"6"`               // "ephemeral"
+	SchemaIndex        string `json:"schema_index,omitempty" protobuf:"7"` // "method.KeyVaultSecretEphemeralResource.Schema.goindex" (optional)
+	OpenIndex          string `json:"open_index,omitempty" protobuf:"8"`   // "method.KeyVaultSecretEphemeralResource.Open.goindex" (optional)
+	RenewIndex         string `json:"renew_index,omitempty" protobuf:"9"`  // "method.KeyVaultSecretEphemeralResource.Renew.goindex" (optional)
+	CloseIndex         string `json:"close_index,omitempty" protobuf:"10"` // "method.KeyVaultSecretEphemeralResource.Close.goindex" (optional)
+	// Strategy that inferred the Terraform type, for debugging extraction quality
+	TerraformTypeStrategy string `json:"terraform_type_strategy,omitempty" protobuf:"11"` // "metadata" (optional)
+	// Service grouping declared by the registration, for category-based grouping of documentation
+	DisplayName       string   `json:"display_name,omitempty" protobuf:"12"`       // "Key Vault" (optional)
+	WebsiteCategories []string `json:"website_categories,omitempty" protobuf:"13"` // ["Key Vault"] (optional)
+	// Issue triage label declared by the registration, for routing questions about the resource
+	GitHubLabel string `json:"github_label,omitempty" protobuf:"14"` // "service/key-vault" (optional)
+	// User-maintained metadata, only set when annotations were loaded with -annotations
+	XAnnotations map[string]interface{} `json:"x_annotations,omitempty" protobuf:"15"` // {"cost_tier": "high", "approval": "approved"} (optional)
+	// Constructor function registered by EphemeralResources, which StructType was resolved from
+	ConstructorFunction string `json:"constructor_function,omitempty" protobuf:"16"` // "NewKeyVaultSecretEphemeralResource" (optional)
+	// Feature flag the registration is gated by, with a leading ! when only registered while the flag is disabled
+	FeatureFlag string `json:"feature_flag,omitempty" protobuf:"17"` // "features.FivePointOh" (optional)
 }
 
-// NewTerraformEphemeralInfo creates a TerraformEphemeral struct
-func NewTerraformEphemeralInfo(structType string, service ServiceRegistration) TerraformEphemeral {
-	return TerraformEphemeral{
+// newTerraformEphemeralInfo creates a TerraformEphemeral struct
+func newTerraformEphemeralInfo(structType string, service ServiceRegistration) TerraformEphemeral {
+	result := TerraformEphemeral{
+		ID:                 EntryID(DocumentKindEphemeral, service.EphemeralTerraformTypes[structType], "ephemeral"),
 		TerraformType:      service.EphemeralTerraformTypes[structType],
 		StructType:         structType,
 		Namespace:          service.PackagePath,
@@ -28,5 +43,15 @@ func NewTerraformEphemeralInfo(structType string, service ServiceRegistration) T
 		OpenIndex:   fmt.Sprintf("method.%s.Open.goindex", structType),
 		RenewIndex:  fmt.Sprintf("method.%s.Renew.goindex", structType),
 		CloseIndex:  fmt.Sprintf("method.%s.Close.goindex", structType),
+		// Winning strategy of the Terraform type inference
+		TerraformTypeStrategy: service.TerraformTypeStrategies[structType],
+		ConstructorFunction:   service.EphemeralConstructors[structType],
+		FeatureFlag:           service.EphemeralFeatureFlags[ephemeralTerraformType(service, structType)],
 	}
+	result.DisplayName = service.DisplayName
+	result.WebsiteCategories = service.WebsiteCategories
+	result.GitHubLabel = service.GitHubLabel
+	result.XAnnotations = service.Annotations[result.ID]
+	service.resolveGoIndexReferences(result.goIndexReferences())
+	return result
 }
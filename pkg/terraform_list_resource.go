@@ -0,0 +1,50 @@
+package pkg
+
+import "fmt"
+
+// TerraformListResource represents information about a Terraform list resource, which enumerates existing
+// infrastructure of a resource type for terraform query
+type TerraformListResource struct {
+	ID                 string `json:"id" protobuf:"1"`                     // "azurerm/listresources/azurerm_resource_group/list_resource", see EntryID
+	TerraformType      string `json:"terraform_type" protobuf:"2"`         // "azurerm_resource_group"
+	StructType         string `json:"struct_type" protobuf:"3"`            // "ResourceGroupListResource"
+	Namespace          string `json:"namespace" protobuf:"4"`              // "github.com/hashicorp/terraform-provider-azurerm/internal/services/resource"
+	RegistrationMethod string `json:"registration_method" protobuf:"5"`    // "ListResources"
+	SDKType            string `json:"sdk_type" protobuf:"6"`               // "list_resource"
+	SchemaIndex        string `json:"schema_index,omitempty" protobuf:"7"` // "method.ResourceGroupListResource.ListResourceConfigSchema.goindex" (optional)
+	ListIndex          string `json:"list_index,omitempty" protobuf:"8"`   // "method.ResourceGroupListResource.List.goindex" (optional)
+	// Strategy that inferred the Terraform type, for debugging extraction quality
+	TerraformTypeStrategy string `json:"terraform_type_strategy,omitempty" protobuf:"9"` // "metadata" (optional)
+	// Service grouping declared by the registration, for category-based grouping of documentation
+	DisplayName       string   `json:"display_name,omitempty" protobuf:"10"`       // "Base" (optional)
+	WebsiteCategories []string `json:"website_categories,omitempty" protobuf:"11"` // ["Base"] (optional)
+	// Issue triage label declared by the registration, for routing questions about the resource
+	GitHubLabel string `json:"github_label,omitempty" protobuf:"12"` // "service/resources" (optional)
+	// User-maintained metadata, only set when annotations were loaded with -annotations
+	XAnnotations map[string]interface{} `json:"x_annotations,omitempty" protobuf:"13"` // {"cost_tier": "high", "approval": "approved"} (optional)
+	// Constructor function registered by ListResources, which StructType was resolved from
+	ConstructorFunction string `json:"constructor_function,omitempty" protobuf:"14"` // "NewResourceGroupListResource" (optional)
+}
+
+// newTerraformListResourceInfo creates a TerraformListResource struct
+func newTerraformListResourceInfo(structType string, service ServiceRegistration) TerraformListResource {
+	terraformType := service.listResourceTerraformType(structType)
+	result := TerraformListResource{
+		ID:                    EntryID(DocumentKindListResource, terraformType, "list_resource"),
+		TerraformType:         terraformType,
+		StructType:            structType,
+		Namespace:             service.PackagePath,
+		RegistrationMethod:    "ListResources",
+		SDKType:               "list_resource",
+		SchemaIndex:           fmt.Sprintf("method.%s.ListResourceConfigSchema.goindex", structType),
+		ListIndex:             fmt.Sprintf("method.%s.List.goindex", structType),
+		TerraformTypeStrategy: service.TerraformTypeStrategies[structType],
+		ConstructorFunction:   service.ListResourceConstructors[structType],
+	}
+	result.DisplayName = service.DisplayName
+	result.WebsiteCategories = service.WebsiteCategories
+	result.GitHubLabel = service.GitHubLabel
+	result.XAnnotations = service.Annotations[result.ID]
+	service.resolveGoIndexReferences(result.goIndexReferences())
+	return result
+}
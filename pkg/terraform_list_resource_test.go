@@ -0,0 +1,78 @@
+package pkg
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractListResourcesAndActionsRegistrations(t *testing.T) {
+	source := `package compute
+
+func (r Registration) ListResources() []sdk.FrameworkListWrappedResource {
+	return []sdk.FrameworkListWrappedResource{
+		VirtualMachineListResource{},
+	}
+}
+
+func (r Registration) Actions() []func() action.Action {
+	return []func() action.Action{
+		NewVirtualMachinePowerAction,
+	}
+}`
+
+	node, err := parseSource(source)
+	require.NoError(t, err)
+
+	listResources, listResourceFunctions := extractListResourcesRegistrations(node)
+	assert.Equal(t, []string{"VirtualMachineListResource"}, listResources)
+	assert.Empty(t, listResourceFunctions)
+
+	actions, actionFunctions := extractActionsRegistrations(node)
+	assert.Empty(t, actions)
+	assert.Equal(t, []string{"NewVirtualMachinePowerAction"}, actionFunctions)
+}
+
+func TestScanTerraformProviderServices_ListResourcesAndActions(t *testing.T) {
+	index, err := ScanTerraformProviderServices(filepath.Join("testharness", "internal", "services"), "github.com/lonegunmanb/terraform-provider-azurerm-index", "test-version", nil)
+	require.NoError(t, err)
+
+	var service ServiceRegistration
+	for _, s := range index.Services {
+		if s.ServiceName == "compute" {
+			service = s
+		}
+	}
+	assert.Equal(t, []string{"VirtualMachineListResource"}, service.ListResources)
+	assert.Equal(t, map[string]string{"VirtualMachineListResource": "azurerm_virtual_machine"}, service.ListResourceTerraformTypes)
+	assert.Equal(t, []string{"VirtualMachinePowerAction"}, service.Actions)
+	assert.Equal(t, map[string]string{"VirtualMachinePowerAction": "azurerm_virtual_machine_power"}, service.ActionTerraformTypes)
+	assert.Equal(t, map[string]string{"VirtualMachinePowerAction": "NewVirtualMachinePowerAction"}, service.ActionConstructors)
+
+	assert.Equal(t, 1, index.Statistics.ListResources)
+	assert.Equal(t, 1, index.Statistics.Actions)
+	assert.Equal(t, 1, index.Statistics.Services["compute"].Actions)
+	assert.Equal(t, "azurerm/listresources/azurerm_virtual_machine/list_resource", index.GlobalMaps.AllListResources["azurerm_virtual_machine"].ID)
+	assert.Equal(t, "azurerm/actions/azurerm_virtual_machine_power/action", index.GlobalMaps.AllActions["azurerm_virtual_machine_power"].ID)
+
+	listResource := newTerraformListResourceInfo("VirtualMachineListResource", service)
+	assert.Equal(t, "azurerm_virtual_machine", listResource.TerraformType)
+	assert.Equal(t, "method.VirtualMachineListResource.List.goindex", listResource.ListIndex)
+	action := newTerraformActionInfo("VirtualMachinePowerAction", service)
+	assert.Equal(t, "method.VirtualMachinePowerAction.Invoke.goindex", action.InvokeIndex)
+	assert.Equal(t, "NewVirtualMachinePowerAction", action.ConstructorFunction)
+
+	outputDir := t.TempDir()
+	require.NoError(t, index.WriteIndexFiles(outputDir, nil))
+	for _, file := range []string{
+		filepath.Join(DocumentKindListResource, "azurerm_virtual_machine.json"),
+		filepath.Join(DocumentKindAction, "azurerm_virtual_machine_power.json"),
+	} {
+		assert.FileExists(t, filepath.Join(outputDir, file))
+	}
+	report, err := ValidateIndexDir(outputDir, "")
+	require.NoError(t, err)
+	assert.Empty(t, report.Problems)
+}
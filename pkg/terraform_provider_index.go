@@ -5,27 +5,79 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"io"
 	"os"
 	"path/filepath"
-	"runtime"
+	"sort"
 	"sync"
+	"time"
 
 	gophon "github.com/lonegunmanb/gophon/pkg"
-	"github.com/spf13/afero"
+	"github.com/lonegunmanb/terraform-provider-azurerm-index/pkg/internal/goparse"
+	"github.com/lonegunmanb/terraform-provider-azurerm-index/pkg/internal/output"
+	"github.com/lonegunmanb/terraform-provider-azurerm-index/pkg/internal/parallel"
 )
 
-var outputFs = afero.NewOsFs()
-
 // TerraformProviderIndex represents the complete index of a Terraform provider
 type TerraformProviderIndex struct {
 	Version    string                `json:"version"`    // Provider version
 	Services   []ServiceRegistration `json:"services"`   // All service registrations
 	Statistics ProviderStatistics    `json:"statistics"` // Summary statistics
+	// Terraform type -> registration across all services
+	GlobalMaps GlobalMappings `json:"global_maps"`
+	// Go environment used for parsing
+	Toolchain ToolchainInfo `json:"toolchain"`
+	// Registrations slated for removal in the next major version, for migration tooling
+	RemovedInNextMajor []RemovedEntry `json:"removed_in_next_major"`
+	// Problems found while scanning, such as extractions that panicked on unexpected source
+	Warnings []ScanWarning `json:"-"`
+	// Documentation linking report, written to audit/undocumented.json when documentation was linked
+	Documentation *DocumentationReport `json:"-"`
+	// Annotations applied to the documents, reapplied when services are rescanned
+	Annotations Annotations `json:"-"`
+	// Product names loaded with LoadProductNames, reapplied when services are rescanned
+	ProductNames ProductNames `json:"-"`
+	// Verification report of the goindex references, written to audit/goindex-references.json when verified
+	GoIndex *GoIndexReport `json:"-"`
+	// Schema drift from the Azure REST API specs, written to audit/api-drift.json when detected
+	APIDrift *APIDriftReport `json:"-"`
+	// Services registered by the provider compared with the index, written to audit/provider-coverage.json when checked
+	ProviderCoverage *ProviderCoverageReport `json:"-"`
+	// Terraform types of the compiled provider schema compared with the index, written to audit/provider-schema.json
+	// when reconciled
+	ProviderSchema *ProviderSchemaReport `json:"-"`
+	// Previously indexed version REPORT.md compares the index with, may be nil
+	ReportBaseline *StatsHistoryEntry `json:"-"`
+	// Output settings are not part of the index content
+	Output OutputConfig `json:"-"`
+	// Bus the writing of documents publishes ScanEventDocumentWritten events to, may be nil
+	Events *EventBus `json:"-"`
+	// Hooks called with the documents built from the index, see DocumentHooks
+	Hooks DocumentHooks `json:"-"`
+	// Time spent scanning and number of service directories scanned, reported in the metrics file
+	ScanDuration    time.Duration `json:"-"`
+	ScannedServices int           `json:"-"`
+	// Maps of LookupResource, LookupByStruct and ResourcesByService, built lazily when the index wasn't scanned
+	lookup *indexLookup
+	// Writer that wrote the per-resource files while scanning, nil when the scan wasn't streamed
+	streamed *StreamWriter
 }
 
 // ScanTerraformProviderServices scans the specified directory for Terraform provider services
 // and extracts all registration information into a structured index
 func ScanTerraformProviderServices(dir, basePkgUrl string, version string, progressCallback ProgressCallback) (*TerraformProviderIndex, error) {
+	return Scanner{}.Scan(dir, basePkgUrl, version, progressCallback)
+}
+
+// scanTerraformProviderServices scans the service directories accepted by serviceFilter with up to workers services
+// in parallel, a nil filter accepts all services and zero workers uses one worker per CPU
+func scanTerraformProviderServices(dir, basePkgUrl string, version string, serviceFilter func(serviceName string) bool, scanner Scanner, progressCallback ProgressCallback) (*TerraformProviderIndex, error) {
+	start := time.Now()
+
+	if err := scanner.Exclude.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Read the services directory to get all service subdirectories
 	entries, err := os.ReadDir(dir)
 	if err != nil {
@@ -35,7 +87,7 @@ func ScanTerraformProviderServices(dir, basePkgUrl string, version string, progr
 	// Filter entries to only include directories
 	var dirEntries []os.DirEntry
 	for _, entry := range entries {
-		if entry.IsDir() {
+		if entry.IsDir() && (serviceFilter == nil || serviceFilter(entry.Name())) {
 			dirEntries = append(dirEntries, entry)
 		}
 	}
@@ -43,21 +95,44 @@ func ScanTerraformProviderServices(dir, basePkgUrl string, version string, progr
 	totalServices := len(dirEntries)
 	if totalServices == 0 {
 		return &TerraformProviderIndex{
-			Version:    version,
-			Services:   []ServiceRegistration{},
-			Statistics: ProviderStatistics{},
+			Version:            version,
+			Services:           []ServiceRegistration{},
+			GlobalMaps:         newGlobalMappings(),
+			Statistics:         ProviderStatistics{},
+			Toolchain:          scanner.Build.toolchainInfo(),
+			RemovedInNextMajor: []RemovedEntry{},
 		}, nil
 	}
 
 	// Create progress tracker
 	progressTracker := NewProgressTracker("scanning", totalServices, progressCallback)
 
-	// Set up parallel processing
-	numWorkers := runtime.NumCPU()
-	if numWorkers > len(dirEntries) {
-		numWorkers = len(dirEntries)
+	// Terraform types of typed structs are inferred by the configured strategies, in order
+	typeResolver := newTerraformTypeResolver(scanner.TerraformTypeStrategies)
+
+	// Resource ID packages are resolved relative to the working directory, the same root gophon scans packages from
+	armTypeResolver := newArmResourceTypeResolver(".", basePkgUrl)
+	armTypeResolver.packagePaths = scanner.PackagePaths
+	operationResolver := newSDKOperationResolver(".")
+
+	// Panics of extractions on unexpected source are recorded as warnings instead of aborting the scan
+	warnings := &scanWarnings{events: scanner.Events}
+
+	// Custom extractors run after the built-in extractions of each service
+	extractors := append(RegisteredExtractors(), scanner.Extractors...)
+
+	// Services are streamed to the output directory as they are scanned, the first failed write fails the scan
+	var streamErr error
+	var streamErrOnce sync.Once
+	if scanner.Stream != nil {
+		if err := (&TerraformProviderIndex{Output: OutputConfig{Fs: scanner.Stream.Fs}}).createDirectoryStructure(scanner.Stream.OutputDir); err != nil {
+			return nil, fmt.Errorf("failed to create directory structure: %w", err)
+		}
 	}
 
+	// Set up parallel processing
+	numWorkers := parallel.Workers(scanner.Workers, len(dirEntries))
+
 	// Channels for work distribution and result collection
 	entryChan := make(chan os.DirEntry, len(dirEntries))
 	resultChan := make(chan ServiceRegistration, len(dirEntries))
@@ -75,69 +150,127 @@ func ScanTerraformProviderServices(dir, basePkgUrl string, version string, progr
 		go func() {
 			defer wg.Done()
 			for entry := range entryChan {
-				servicePath := filepath.Join(dir, entry.Name())
+				scanner.Events.Publish(ScanEvent{Type: ScanEventServiceStarted, Service: entry.Name()})
+				func() {
+					defer scanner.Events.Publish(ScanEvent{Type: ScanEventServiceCompleted, Service: entry.Name()})
 
-				// Scan the individual service package
-				packageInfo, err := gophon.ScanSinglePackage(servicePath, basePkgUrl)
+					servicePath := filepath.Join(dir, entry.Name())
 
-				// Update progress
-				progressTracker.UpdateProgress(entry.Name())
+					guard := extractionGuard{service: entry.Name(), warnings: warnings}
 
-				if err != nil || packageInfo == nil || len(packageInfo.Files) == 0 {
-					// Skip services that can't be scanned (might not be valid Go packages)
-					continue
-				}
+					// Scan the individual service package
+					var packageInfo *gophon.PackageInfo
+					var err error
+					scanned := guard.run("", "package scan", func() {
+						packageInfo, err = goparse.ParsePackage(servicePath, basePkgUrl, scanner.Build.buildContext(), scanner.ParseWorkers)
+					})
 
-				serviceReg := newServiceRegistration(packageInfo, entry)
+					// Update progress
+					progressTracker.UpdateProgress(entry.Name())
 
-				// Process each file in the package
-				for _, fileInfo := range packageInfo.Files {
-					if fileInfo.File == nil {
-						continue
+					// Skip services that can't be scanned (might not be valid Go packages)
+					if !scanned {
+						return
+					}
+					if err != nil {
+						guard.warn(ScanWarningParseError, "", err.Error())
+						return
+					}
+					if packageInfo != nil {
+						packageInfo = scanner.Exclude.filterPackage(packageInfo)
+					}
+					if packageInfo == nil || len(packageInfo.Files) == 0 {
+						guard.warn(ScanWarningEmptyPackage, "", "no Go files found")
+						return
 					}
 
-					// Extract all registration methods from this file
-					supportedResources := extractSupportedResourcesMappings(fileInfo.File)
-					supportedDataSources := extractSupportedDataSourcesMappings(fileInfo.File)
-					resources := extractResourcesStructTypes(fileInfo.File)
-					dataSources := extractDataSourcesStructTypes(fileInfo.File)
-					ephemeralFunctions := extractEphemeralResourcesFunctions(fileInfo.File)
-
-					// Merge results into service registration
-					serviceReg.SupportedResources = mergeMap(serviceReg.SupportedResources, supportedResources)
-					serviceReg.SupportedDataSources = mergeMap(serviceReg.SupportedDataSources, supportedDataSources)
-					serviceReg.Resources = append(serviceReg.Resources, resources...)
-					serviceReg.DataSources = append(serviceReg.DataSources, dataSources...)
-					serviceReg.EphemeralFunctions = append(serviceReg.EphemeralFunctions, ephemeralFunctions...)
-				}
-
-				// After processing all files, extract Terraform types for modern resources and data sources
-				serviceReg.ResourceTerraformTypes = extractResourceTerraformTypes(packageInfo, serviceReg.Resources)
-				serviceReg.DataSourceTerraformTypes = extractDataSourceTerraformTypes(packageInfo, serviceReg.DataSources)
+					serviceReg := newServiceRegistration(packageInfo, entry)
+					if scanner.PackagePaths != nil {
+						if packagePath := scanner.PackagePaths.PackagePath(servicePath); packagePath != "" {
+							serviceReg.PackagePath = packagePath
+						}
+					}
 
-				// Convert ephemeral function names to struct names for Terraform type extraction
-				ephemeralStructs := convertFunctionNamesToStructNames(serviceReg.EphemeralFunctions, packageInfo)
-				serviceReg.EphemeralTerraformTypes = extractEphemeralTerraformTypes(packageInfo, ephemeralStructs)
+					// Process each file in the package
+					for _, fileInfo := range packageInfo.Files {
+						if fileInfo.File == nil {
+							continue
+						}
 
-				// Extract CRUD methods for legacy resources using gophon function data
-				for terraformType, registrationMethod := range serviceReg.SupportedResources {
-					if crudMethods := extractCRUDFromPackage(registrationMethod, packageInfo); crudMethods != nil {
-						serviceReg.ResourceCRUDMethods[terraformType] = crudMethods
+						// Extract all registration methods from this file
+						fileName := filepath.Base(fileInfo.FileName)
+						guard.run(fileName, "registration", func() {
+							supportedResources := extractSupportedResourcesMappings(fileInfo.File)
+							supportedDataSources := extractSupportedDataSourcesMappings(fileInfo.File)
+							resources := extractResourcesStructTypes(fileInfo.File)
+							dataSources := extractDataSourcesStructTypes(fileInfo.File)
+							conditionalResources := extractConditionalResourcesStructTypes(fileInfo.File)
+							conditionalDataSources := extractConditionalDataSourcesStructTypes(fileInfo.File)
+							ephemeralFunctions := extractEphemeralResourcesFunctions(fileInfo.File)
+							listResources, listResourceFunctions := extractListResourcesRegistrations(fileInfo.File)
+							actions, actionFunctions := extractActionsRegistrations(fileInfo.File)
+
+							// Merge results into service registration
+							serviceReg.SupportedResources = serviceReg.mergeLegacyRegistrations(DocumentKindResource, serviceReg.SupportedResources, supportedResources, fileName)
+							serviceReg.SupportedDataSources = serviceReg.mergeLegacyRegistrations(DocumentKindDataSource, serviceReg.SupportedDataSources, supportedDataSources, fileName)
+							for _, registrations := range [][]string{resources, dataSources, conditionalResources, conditionalDataSources, ephemeralFunctions, listResources, listResourceFunctions, actions, actionFunctions} {
+								for _, registration := range registrations {
+									serviceReg.recordRegistrationFile(registration, fileName)
+								}
+							}
+							serviceReg.Resources = append(serviceReg.Resources, resources...)
+							serviceReg.DataSources = append(serviceReg.DataSources, dataSources...)
+							serviceReg.ConditionalResources = append(serviceReg.ConditionalResources, conditionalResources...)
+							serviceReg.ConditionalDataSources = append(serviceReg.ConditionalDataSources, conditionalDataSources...)
+							if displayName := extractServiceDisplayName(fileInfo.File); displayName != "" {
+								serviceReg.DisplayName = displayName
+							}
+							if gitHubLabel := extractServiceGitHubLabel(fileInfo.File); gitHubLabel != "" {
+								serviceReg.GitHubLabel = gitHubLabel
+							}
+							serviceReg.WebsiteCategories = append(serviceReg.WebsiteCategories, extractServiceWebsiteCategories(fileInfo.File)...)
+							serviceReg.EphemeralFunctions = append(serviceReg.EphemeralFunctions, ephemeralFunctions...)
+							serviceReg.ListResources = append(serviceReg.ListResources, listResources...)
+							serviceReg.ListResourceFunctions = append(serviceReg.ListResourceFunctions, listResourceFunctions...)
+							serviceReg.Actions = append(serviceReg.Actions, actions...)
+							serviceReg.ActionFunctions = append(serviceReg.ActionFunctions, actionFunctions...)
+						})
 					}
-				}
 
-				// Extract methods for legacy data sources
-				for terraformType, registrationMethod := range serviceReg.SupportedDataSources {
-					if methods := extractDataSourceMethodsFromPackage(registrationMethod, packageInfo); methods != nil {
-						serviceReg.DataSourceMethods[terraformType] = methods
+					// Add the registrations only type information resolves
+					if scanner.Typed {
+						guard.run("", "typed registration", func() {
+							registrations, err := loadTypedRegistrations(servicePath, scanner.Build, scanner.Exclude)
+							if err != nil {
+								guard.warn(ScanWarningParseError, "", err.Error())
+								return
+							}
+							serviceReg.mergeTypedRegistrations(registrations)
+						})
 					}
-				}
 
-				// Only include services that have at least one registration method
-				if len(serviceReg.SupportedResources) > 0 || len(serviceReg.SupportedDataSources) > 0 ||
-					len(serviceReg.Resources) > 0 || len(serviceReg.DataSources) > 0 || len(serviceReg.EphemeralFunctions) > 0 {
-					resultChan <- serviceReg
-				}
+					// After processing all files, extract the details of each resource and data source
+					extractServiceDetails(&serviceReg, packageInfo, servicePath, scanner.Exclude, typeResolver, armTypeResolver, operationResolver, guard)
+					runExtractors(&serviceReg, packageInfo, extractors, guard)
+
+					// Only include services that have at least one registration method
+					if len(serviceReg.SupportedResources) > 0 || len(serviceReg.SupportedDataSources) > 0 ||
+						len(serviceReg.Resources) > 0 || len(serviceReg.DataSources) > 0 || len(serviceReg.EphemeralFunctions) > 0 ||
+						len(serviceReg.ListResources) > 0 || len(serviceReg.ListResourceFunctions) > 0 ||
+						len(serviceReg.Actions) > 0 || len(serviceReg.ActionFunctions) > 0 {
+						guard.reportUnresolvedRegistrations(&serviceReg)
+						if scanner.Stream != nil {
+							if err := scanner.Stream.writeService(&serviceReg, scanner); err != nil {
+								streamErrOnce.Do(func() {
+									streamErr = fmt.Errorf("failed to stream service %s: %w", entry.Name(), err)
+								})
+							}
+						}
+						resultChan <- serviceReg
+					} else {
+						guard.warn(ScanWarningEmptyPackage, "", "no registrations found")
+					}
+				}()
 			}
 		}()
 	}
@@ -150,78 +283,463 @@ func ScanTerraformProviderServices(dir, basePkgUrl string, version string, progr
 
 	// Collect results and build final data structures
 	var services []ServiceRegistration
-	globalResources := make(map[string]string)
-	globalDataSources := make(map[string]string)
-	stats := ProviderStatistics{}
-
+	statistics := newStatisticsBuilder()
 	for serviceReg := range resultChan {
 		services = append(services, serviceReg)
-		stats.ServiceCount++
-
-		// Add to global maps
-		globalResources = mergeMap(globalResources, serviceReg.SupportedResources)
-		globalDataSources = mergeMap(globalDataSources, serviceReg.SupportedDataSources)
-
-		// Update statistics
-		stats.LegacyResources += len(serviceReg.SupportedResources)
-		stats.TotalDataSources += len(serviceReg.SupportedDataSources)
-		stats.ModernResources += len(serviceReg.Resources)
-		stats.TotalDataSources += len(serviceReg.DataSources)
-		stats.EphemeralResources += len(serviceReg.EphemeralFunctions)
+		statistics.addServiceRegistration(serviceReg)
+	}
+	if streamErr != nil {
+		return nil, streamErr
 	}
 
-	stats.TotalResources = stats.LegacyResources + stats.ModernResources + stats.EphemeralResources
+	// Services arrive in completion order, sort them so identical input produces identical output.
+	// Map keys need no sorting, encoding/json writes them in sorted order.
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].ServiceName < services[j].ServiceName
+	})
+	for i := range services {
+		services[i].sortRegistrations()
+	}
 
 	// Report scanning completion
 	progressTracker.Complete()
 
-	return &TerraformProviderIndex{
+	index := &TerraformProviderIndex{
 		Version:    version,
 		Services:   services,
-		Statistics: stats,
-	}, nil
+		Statistics: statistics.build(),
+		Toolchain:  scanner.Build.toolchainInfo(),
+		Warnings:   warnings.sorted(),
+	}
+	if index.removeVetoedDocuments(scanner.Hooks) > 0 {
+		index.Statistics = buildProviderStatistics(index.Services)
+	}
+	index.GlobalMaps = index.BuildGlobalMappings()
+	index.RemovedInNextMajor = index.BuildRemovedInNextMajor()
+	index.buildLookup()
+	index.ScannedServices = totalServices
+	index.ScanDuration = time.Since(start)
+	return index, nil
+}
+
+// extractServiceDetails runs the per-resource extractions of a scanned service. Each extraction recovers from panics
+// on unexpected source, so a failure only loses the details it was extracting.
+func extractServiceDetails(serviceReg *ServiceRegistration, packageInfo *gophon.PackageInfo, servicePath string, exclusions FileExclusions, typeResolver terraformTypeResolver, armTypeResolver *armResourceTypeResolver, operationResolver *sdkOperationResolver, guard extractionGuard) {
+	// Extract Terraform types for modern resources and data sources
+	guard.run("", "terraform types", func() {
+		serviceReg.ResourceTerraformTypes = typeResolver.resolve(packageInfo, serviceReg.Resources, serviceReg.TerraformTypeStrategies)
+		serviceReg.DataSourceTerraformTypes = typeResolver.resolve(packageInfo, serviceReg.DataSources, serviceReg.TerraformTypeStrategies)
+	})
+
+	// Convert ephemeral function names to struct names for Terraform type extraction
+	guard.run("", "ephemeral terraform types", func() {
+		ephemeralStructs := convertFunctionNamesToStructNames(serviceReg.EphemeralFunctions, packageInfo)
+		serviceReg.EphemeralConstructors = registeredConstructors(serviceReg.EphemeralFunctions, ephemeralStructs)
+		serviceReg.EphemeralTerraformTypes = typeResolver.resolve(packageInfo, ephemeralStructs, serviceReg.TerraformTypeStrategies)
+	})
+
+	// Resolve the constructors of list resources and actions, then the Terraform types of all their struct types
+	guard.run("", "list resource and action terraform types", func() {
+		listResourceStructs := convertFunctionNamesToStructNames(serviceReg.ListResourceFunctions, packageInfo)
+		serviceReg.ListResourceConstructors = registeredConstructors(serviceReg.ListResourceFunctions, listResourceStructs)
+		serviceReg.ListResources = uniqueSortedStrings(append(serviceReg.ListResources, listResourceStructs...))
+		serviceReg.ListResourceTerraformTypes = typeResolver.resolve(packageInfo, serviceReg.ListResources, serviceReg.TerraformTypeStrategies)
+
+		actionStructs := convertFunctionNamesToStructNames(serviceReg.ActionFunctions, packageInfo)
+		serviceReg.ActionConstructors = registeredConstructors(serviceReg.ActionFunctions, actionStructs)
+		serviceReg.Actions = uniqueSortedStrings(append(serviceReg.Actions, actionStructs...))
+		serviceReg.ActionTerraformTypes = typeResolver.resolve(packageInfo, serviceReg.Actions, serviceReg.TerraformTypeStrategies)
+	})
+
+	// Extract CRUD methods for legacy resources using gophon function data
+	guard.run("", "CRUD methods", func() {
+		for terraformType, registrationMethod := range serviceReg.SupportedResources {
+			if crudMethods := extractCRUDFromPackage(registrationMethod, packageInfo); crudMethods != nil {
+				serviceReg.ResourceCRUDMethods[terraformType] = crudMethods
+			}
+		}
+	})
+
+	// Extract schema versions and state upgraders for legacy and modern resources
+	guard.run("", "state upgraders", func() {
+		for terraformType, registrationMethod := range serviceReg.SupportedResources {
+			if stateUpgrades := extractLegacyStateUpgradersFromPackage(registrationMethod, packageInfo); stateUpgrades != nil {
+				serviceReg.ResourceStateUpgrades[terraformType] = stateUpgrades
+			}
+		}
+		for _, structType := range serviceReg.Resources {
+			if stateUpgrades := extractTypedStateUpgradersFromPackage(structType, packageInfo); stateUpgrades != nil {
+				serviceReg.ResourceStateUpgrades[serviceReg.resourceTerraformType(structType)] = stateUpgrades
+			}
+		}
+	})
+
+	// Extract CustomizeDiff functions for legacy and modern resources
+	guard.run("", "CustomizeDiff", func() {
+		for terraformType, registrationMethod := range serviceReg.SupportedResources {
+			if customizeDiff := extractLegacyCustomizeDiffFromPackage(registrationMethod, packageInfo); len(customizeDiff) > 0 {
+				serviceReg.ResourceCustomizeDiff[terraformType] = customizeDiff
+			}
+		}
+		for _, structType := range serviceReg.Resources {
+			if customizeDiff := extractTypedCustomizeDiffFromPackage(structType, packageInfo); len(customizeDiff) > 0 {
+				serviceReg.ResourceCustomizeDiff[serviceReg.resourceTerraformType(structType)] = customizeDiff
+			}
+		}
+	})
+
+	// Detect deprecated resources and data sources
+	guard.run("", "deprecations", func() {
+		for terraformType, registrationMethod := range serviceReg.SupportedResources {
+			if message := extractLegacyDeprecationFromPackage(registrationMethod, packageInfo); message != "" {
+				serviceReg.ResourceDeprecations[terraformType] = message
+			}
+		}
+		for _, structType := range serviceReg.Resources {
+			if message := extractTypedDeprecationFromPackage(structType, packageInfo); message != "" {
+				serviceReg.ResourceDeprecations[serviceReg.resourceTerraformType(structType)] = message
+			}
+		}
+		for terraformType, registrationMethod := range serviceReg.SupportedDataSources {
+			if message := extractLegacyDeprecationFromPackage(registrationMethod, packageInfo); message != "" {
+				serviceReg.DataSourceDeprecations[terraformType] = message
+			}
+		}
+		for _, structType := range serviceReg.DataSources {
+			if message := extractTypedDeprecationFromPackage(structType, packageInfo); message != "" {
+				serviceReg.DataSourceDeprecations[serviceReg.dataSourceTerraformType(structType)] = message
+			}
+		}
+	})
+
+	// Extract schema attributes for legacy and modern resources
+	guard.run("", "schema", func() {
+		for terraformType, registrationMethod := range serviceReg.SupportedResources {
+			if schema := extractLegacyResourceSchemaFromPackage(registrationMethod, packageInfo); len(schema) > 0 {
+				serviceReg.ResourceSchemas[terraformType] = schema
+			}
+		}
+		for _, structType := range serviceReg.Resources {
+			if schema := extractTypedResourceSchemaFromPackage(structType, packageInfo); len(schema) > 0 {
+				serviceReg.ResourceSchemas[serviceReg.resourceTerraformType(structType)] = schema
+			}
+		}
+	})
+
+	// Map the model struct fields of modern resources and data sources to their attributes
+	guard.run("", "models", func() {
+		for _, structType := range serviceReg.Resources {
+			if model := extractTypedModelFromPackage(structType, packageInfo); model != nil {
+				serviceReg.ResourceModels[serviceReg.resourceTerraformType(structType)] = model
+			}
+		}
+		for _, structType := range serviceReg.DataSources {
+			if model := extractTypedModelFromPackage(structType, packageInfo); model != nil {
+				serviceReg.DataSourceModels[serviceReg.dataSourceTerraformType(structType)] = model
+			}
+		}
+	})
+
+	// Detect the ARM resource types managed by legacy and modern resources
+	guard.run("", "ARM resource types", func() {
+		for terraformType, registrationMethod := range serviceReg.SupportedResources {
+			if armType := armTypeResolver.extractLegacyArmResourceTypeFromPackage(registrationMethod, serviceReg.ResourceCRUDMethods[terraformType], packageInfo); armType != "" {
+				serviceReg.ResourceArmTypes[terraformType] = armType
+			}
+		}
+		for _, structType := range serviceReg.Resources {
+			if armType := armTypeResolver.extractTypedArmResourceTypeFromPackage(structType, packageInfo); armType != "" {
+				serviceReg.ResourceArmTypes[serviceReg.resourceTerraformType(structType)] = armType
+			}
+		}
+	})
+
+	// Detect the resource ID parsers used by Read functions of legacy and modern resources
+	guard.run("", "resource ID parsers", func() {
+		for terraformType := range serviceReg.SupportedResources {
+			if idParser := extractLegacyIDParserFromPackage(serviceReg.ResourceCRUDMethods[terraformType], packageInfo); idParser != "" {
+				serviceReg.ResourceIDParsers[terraformType] = idParser
+			}
+		}
+		for _, structType := range serviceReg.Resources {
+			if idParser := extractTypedIDParserFromPackage(structType, packageInfo); idParser != "" {
+				serviceReg.ResourceIDParsers[serviceReg.resourceTerraformType(structType)] = idParser
+			}
+		}
+	})
+
+	// Collect the go-azure-sdk packages referenced by CRUD functions of legacy and modern resources
+	guard.run("", "SDK packages", func() {
+		for terraformType := range serviceReg.SupportedResources {
+			if packages := extractLegacySDKPackagesFromPackage(serviceReg.ResourceCRUDMethods[terraformType], packageInfo); len(packages) > 0 {
+				serviceReg.ResourceSDKPackages[terraformType] = packages
+			}
+		}
+		for _, structType := range serviceReg.Resources {
+			if packages := extractTypedSDKPackagesFromPackage(structType, packageInfo); len(packages) > 0 {
+				serviceReg.ResourceSDKPackages[serviceReg.resourceTerraformType(structType)] = packages
+			}
+		}
+	})
+
+	// Collect the commonids ID types parsed or built by CRUD functions of legacy and modern resources
+	guard.run("", "commonids usage", func() {
+		for terraformType := range serviceReg.SupportedResources {
+			if commonIDs := extractLegacyCommonIDsFromPackage(serviceReg.ResourceCRUDMethods[terraformType], packageInfo); len(commonIDs) > 0 {
+				serviceReg.ResourceCommonIDs[terraformType] = commonIDs
+			}
+		}
+		for _, structType := range serviceReg.Resources {
+			if commonIDs := extractTypedCommonIDsFromPackage(structType, packageInfo); len(commonIDs) > 0 {
+				serviceReg.ResourceCommonIDs[serviceReg.resourceTerraformType(structType)] = commonIDs
+			}
+		}
+	})
+
+	// Map the SDK client calls of CRUD functions to the HTTP operations of the vendored go-azure-sdk
+	guard.run("", "API operations", func() {
+		for terraformType := range serviceReg.SupportedResources {
+			if operations := operationResolver.extractLegacyAPIOperationsFromPackage(serviceReg.ResourceCRUDMethods[terraformType], serviceReg.ResourceSDKPackages[terraformType], packageInfo); len(operations) > 0 {
+				serviceReg.ResourceAPIOperations[terraformType] = operations
+			}
+		}
+		for _, structType := range serviceReg.Resources {
+			terraformType := serviceReg.resourceTerraformType(structType)
+			if operations := operationResolver.extractTypedAPIOperationsFromPackage(structType, serviceReg.ResourceSDKPackages[terraformType], packageInfo); len(operations) > 0 {
+				serviceReg.ResourceAPIOperations[terraformType] = operations
+			}
+		}
+	})
+
+	// Extract operation timeouts of legacy and modern resources
+	guard.run("", "timeouts", func() {
+		for terraformType, registrationMethod := range serviceReg.SupportedResources {
+			if timeouts := extractLegacyTimeoutsFromPackage(registrationMethod, packageInfo); timeouts != nil {
+				serviceReg.ResourceTimeouts[terraformType] = timeouts
+			}
+		}
+		for _, structType := range serviceReg.Resources {
+			if timeouts := extractTypedTimeoutsFromPackage(structType, packageInfo); timeouts != nil {
+				serviceReg.ResourceTimeouts[serviceReg.resourceTerraformType(structType)] = timeouts
+			}
+		}
+	})
+
+	// Label resources that can't be updated in place or bypass the typed SDK clients
+	guard.run("", "update capabilities", func() {
+		for terraformType, registrationMethod := range serviceReg.SupportedResources {
+			if capability := extractLegacyUpdateCapabilityFromPackage(registrationMethod, serviceReg.ResourceCRUDMethods[terraformType], packageInfo); capability != "" {
+				serviceReg.ResourceCapabilities[terraformType] = append(serviceReg.ResourceCapabilities[terraformType], capability)
+			}
+			if capability := extractLegacyRawRESTCapabilityFromPackage(serviceReg.ResourceCRUDMethods[terraformType], packageInfo); capability != "" {
+				serviceReg.ResourceCapabilities[terraformType] = append(serviceReg.ResourceCapabilities[terraformType], capability)
+			}
+			if isLegacyResourceImmutable(serviceReg.ResourceCRUDMethods[terraformType]) {
+				serviceReg.ResourceImmutable[terraformType] = true
+			}
+		}
+		for _, structType := range serviceReg.Resources {
+			terraformType := serviceReg.resourceTerraformType(structType)
+			if capability := extractTypedUpdateCapabilityFromPackage(structType, packageInfo); capability != "" {
+				serviceReg.ResourceCapabilities[terraformType] = append(serviceReg.ResourceCapabilities[terraformType], capability)
+			}
+			if capability := extractTypedRawRESTCapabilityFromPackage(structType, packageInfo); capability != "" {
+				serviceReg.ResourceCapabilities[terraformType] = append(serviceReg.ResourceCapabilities[terraformType], capability)
+			}
+			if isTypedResourceImmutable(structType, packageInfo) {
+				serviceReg.ResourceImmutable[terraformType] = true
+			}
+		}
+	})
+
+	// Annotate registrations gated by feature flags, after the Terraform types were resolved
+	guard.run("", "feature flags", func() {
+		extractFeatureFlags(serviceReg, packageInfo)
+	})
+
+	// Collect the SDK clients the service constructs
+	guard.run("", "service clients", func() {
+		serviceReg.Clients, serviceReg.ClientFile = extractServiceClients(servicePath)
+	})
+
+	// Map Terraform types to the acceptance tests of the service, unless test files are excluded from the scan
+	guard.run("", "acceptance tests", func() {
+		serviceReg.ResourceAcceptanceTests, serviceReg.DataSourceAcceptanceTests = extractAcceptanceTests(servicePath, exclusions)
+	})
+
+	// Extract methods for legacy data sources
+	guard.run("", "data source methods", func() {
+		for terraformType, registrationMethod := range serviceReg.SupportedDataSources {
+			if methods := extractDataSourceMethodsFromPackage(registrationMethod, packageInfo); methods != nil {
+				serviceReg.DataSourceMethods[terraformType] = methods
+			}
+		}
+	})
+
+	// Collect the doc comments of registrations, structs and CRUD functions, after the CRUD and data source methods
+	guard.run("", "doc comments", func() {
+		for terraformType, registrationMethod := range serviceReg.SupportedResources {
+			if doc := extractLegacyResourceGoDoc(registrationMethod, serviceReg.ResourceCRUDMethods[terraformType], packageInfo); doc != nil {
+				serviceReg.ResourceGoDocs[terraformType] = doc
+			}
+		}
+		for _, structType := range serviceReg.Resources {
+			if doc := extractTypedGoDoc(structType, packageInfo); doc != nil {
+				serviceReg.ResourceGoDocs[serviceReg.resourceTerraformType(structType)] = doc
+			}
+		}
+		for terraformType, registrationMethod := range serviceReg.SupportedDataSources {
+			if doc := extractLegacyDataSourceGoDoc(registrationMethod, serviceReg.DataSourceMethods[terraformType], packageInfo); doc != nil {
+				serviceReg.DataSourceGoDocs[terraformType] = doc
+			}
+		}
+		for _, structType := range serviceReg.DataSources {
+			if doc := extractTypedGoDoc(structType, packageInfo); doc != nil {
+				serviceReg.DataSourceGoDocs[serviceReg.dataSourceTerraformType(structType)] = doc
+			}
+		}
+	})
+
+	// Locate the registrations, structs and CRUD functions in the service directory
+	guard.run("", "source locations", func() {
+		for terraformType, registrationMethod := range serviceReg.SupportedResources {
+			crud := legacyCRUDFunctions(serviceReg.ResourceCRUDMethods[terraformType])
+			if locations := extractLegacySourceLocations(registrationMethod, crud, packageInfo, servicePath); locations != nil {
+				serviceReg.ResourceSources[terraformType] = locations
+			}
+		}
+		for _, structType := range serviceReg.Resources {
+			if locations := extractTypedSourceLocations(structType, packageInfo, servicePath); locations != nil {
+				serviceReg.ResourceSources[serviceReg.resourceTerraformType(structType)] = locations
+			}
+		}
+		for terraformType, registrationMethod := range serviceReg.SupportedDataSources {
+			var crud map[string]string
+			if methods := serviceReg.DataSourceMethods[terraformType]; methods != nil {
+				crud = map[string]string{"read": methods.ReadMethod}
+			}
+			if locations := extractLegacySourceLocations(registrationMethod, crud, packageInfo, servicePath); locations != nil {
+				serviceReg.DataSourceSources[terraformType] = locations
+			}
+		}
+		for _, structType := range serviceReg.DataSources {
+			if locations := extractTypedSourceLocations(structType, packageInfo, servicePath); locations != nil {
+				serviceReg.DataSourceSources[serviceReg.dataSourceTerraformType(structType)] = locations
+			}
+		}
+	})
 }
 
 // WriteIndexFiles writes all index files to the specified output directory
 // This is the main method that orchestrates writing all index files
 func (index *TerraformProviderIndex) WriteIndexFiles(outputDir string, progressCallback ProgressCallback) error {
+	if index.Output.Metrics == "" {
+		return index.writeIndexFiles(outputDir, progressCallback)
+	}
+
+	// The metrics file is written last, measuring the writing of every other file
+	start := time.Now()
+	// Files are counted through a copy of the index writing to a counting file system, the index itself is untouched
+	counting := &countingFs{Fs: index.Output.fs()}
+	counted := *index
+	counted.Output.Fs = counting
+	err := counted.writeIndexFiles(outputDir, progressCallback)
+	files, bytes := counting.files.Load(), counting.bytes.Load()
+	if err != nil {
+		return err
+	}
+	if err := index.writeMetricsFile(outputDir, index.BuildMetrics(time.Since(start), files, bytes)); err != nil {
+		return fmt.Errorf("failed to write metrics file: %w", err)
+	}
+	return nil
+}
+
+// writeIndexFiles writes the index files in the configured output format
+func (index *TerraformProviderIndex) writeIndexFiles(outputDir string, progressCallback ProgressCallback) error {
+	switch index.Output.Format {
+	case OutputFormatTemplate:
+		return index.writeTemplateFiles(outputDir, progressCallback)
+	case OutputFormatESBulk:
+		return index.writeESBulkFile(outputDir, progressCallback)
+	case OutputFormatProto:
+		return index.writeProtoFile(outputDir, progressCallback)
+	case OutputFormatParquet:
+		return index.writeParquetFiles(outputDir, progressCallback)
+	case OutputFormatCSV:
+		return index.writeCSVFiles(outputDir, progressCallback)
+	}
+	if index.Output.SingleFile {
+		return index.writeSingleFile(outputDir, progressCallback)
+	}
+
+	// Per-resource files a StreamWriter wrote to the output directory while scanning aren't written again
+	streamed := index.streamed != nil && filepath.Clean(index.streamed.OutputDir) == filepath.Clean(outputDir)
+
 	// Calculate total number of files to write
-	totalFiles := 1 // main index file
+	totalFiles := 13 // main index file, validation function index, write-only, ForceNew and sensitive attribute indexes, SDK API version index, API version usage report, feature heatmap, unreferenced functions, orphans and duplicates audits, scan report and file list
 	for _, service := range index.Services {
-		totalFiles += len(service.SupportedResources)   // legacy resources
-		totalFiles += len(service.Resources)            // modern resources
-		totalFiles += len(service.SupportedDataSources) // legacy data sources
-		totalFiles += len(service.DataSources)          // modern data sources
-		totalFiles += len(service.EphemeralFunctions)   // ephemeral resources
+		if !streamed {
+			totalFiles += len(service.SupportedResources)   // legacy resources
+			totalFiles += len(service.Resources)            // modern resources
+			totalFiles += len(service.SupportedDataSources) // legacy data sources
+			totalFiles += len(service.DataSources)          // modern data sources
+			totalFiles += len(service.EphemeralFunctions)   // ephemeral resources
+			totalFiles += len(service.ListResources)        // list resources
+			totalFiles += len(service.Actions)              // actions
+
+			// acceptance tests
+			totalFiles += len(service.ResourceAcceptanceTests) + len(service.DataSourceAcceptanceTests)
+		}
+
+		if service.ClientFile != "" {
+			totalFiles++ // service clients
+		}
+	}
+	if index.Documentation != nil {
+		totalFiles++ // undocumented resources report
+	}
+	if index.GoIndex != nil {
+		totalFiles++ // goindex references report
+	}
+	if index.APIDrift != nil {
+		totalFiles++ // API drift report
+	}
+	if index.ProviderCoverage != nil {
+		totalFiles++ // provider coverage report
+	}
+	if index.ProviderSchema != nil {
+		totalFiles++ // provider schema report
+	}
+	if index.Output.ServiceSummaries {
+		totalFiles += len(index.Services) // service summaries
+	}
+	if index.Output.MarkdownReport {
+		totalFiles++ // markdown report
 	}
 
 	// Create progress tracker
 	progressTracker := NewProgressTracker("indexing", totalFiles, progressCallback)
 
 	// Create directory structure
-	if err := index.CreateDirectoryStructure(outputDir); err != nil {
+	if err := index.createDirectoryStructure(outputDir); err != nil {
 		return fmt.Errorf("failed to create directory structure: %w", err)
 	}
 
-	// Write main index file
-	if err := index.WriteMainIndexFile(outputDir); err != nil {
-		return fmt.Errorf("failed to write main index file: %w", err)
-	}
-	progressTracker.UpdateProgress("main index file")
-
-	// Write individual resource files
-	if err := index.WriteResourceFiles(outputDir, progressTracker); err != nil {
-		return fmt.Errorf("failed to write resource files: %w", err)
+	// Write the main index and the files summarizing all services
+	if err := index.writeSummaryFiles(outputDir, progressTracker); err != nil {
+		return err
 	}
 
-	// Write individual data source files
-	if err := index.WriteDataSourceFiles(outputDir, progressTracker); err != nil {
-		return fmt.Errorf("failed to write data source files: %w", err)
-	}
+	if !streamed {
+		// Write individual resource, data source and ephemeral resource files
+		if err := index.WriteDocumentFiles(outputDir, progressTracker); err != nil {
+			return err
+		}
 
-	// Write individual ephemeral resource files
-	if err := index.WriteEphemeralFiles(outputDir, progressTracker); err != nil {
-		return fmt.Errorf("failed to write ephemeral files: %w", err)
+		// Write acceptance test files
+		if err := index.writeAcceptanceTestFiles(outputDir, progressTracker); err != nil {
+			return fmt.Errorf("failed to write acceptance test files: %w", err)
+		}
 	}
 
 	// Report completion
@@ -230,221 +748,202 @@ func (index *TerraformProviderIndex) WriteIndexFiles(outputDir string, progressC
 	return nil
 }
 
-// WriteMainIndexFile writes the main terraform-provider-azurerm-index.json file
-func (index *TerraformProviderIndex) WriteMainIndexFile(outputDir string) error {
-	mainIndexPath := filepath.Join(outputDir, "terraform-provider-azurerm-index.json")
-	return index.WriteJSONFile(mainIndexPath, index)
-}
-
-// processCallbacksParallel runs a slice of callbacks in parallel
-func processCallbacksParallel(tasks []func() error) error {
-	if len(tasks) == 0 {
-		return nil
+// writeSummaryFiles writes the main index and the cross-reference, audit and report files derived from all services
+func (index *TerraformProviderIndex) writeSummaryFiles(outputDir string, progressTracker *ProgressTracker) error {
+	// Write main index file
+	if err := index.writeMainIndexFile(outputDir); err != nil {
+		return fmt.Errorf("failed to write main index file: %w", err)
 	}
+	progressTracker.UpdateProgress("main index file")
 
-	numWorkers := runtime.NumCPU()
-	if numWorkers > len(tasks) {
-		numWorkers = len(tasks)
+	// Write validation function cross-reference file
+	if err := index.writeValidationIndexFile(outputDir); err != nil {
+		return fmt.Errorf("failed to write validation index file: %w", err)
 	}
+	progressTracker.UpdateProgress("validation index file")
 
-	callbackChan := make(chan func() error, len(tasks))
-	errorChan := make(chan error, len(tasks))
-	var wg sync.WaitGroup
-
-	// Send all callbacks to the channel
-	for _, callback := range tasks {
-		callbackChan <- callback
+	// Write write-only attributes index
+	if err := index.writeWriteOnlyIndexFile(outputDir); err != nil {
+		return fmt.Errorf("failed to write write-only attribute index file: %w", err)
 	}
-	close(callbackChan)
+	progressTracker.UpdateProgress("write-only attribute index file")
 
-	// Start worker goroutines
-	for i := 0; i < numWorkers; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for callback := range callbackChan {
-				if err := callback(); err != nil {
-					errorChan <- err
-					return
-				}
-			}
-		}()
+	// Write ForceNew attributes index
+	if err := index.writeForceNewIndexFile(outputDir); err != nil {
+		return fmt.Errorf("failed to write ForceNew attribute index file: %w", err)
 	}
+	progressTracker.UpdateProgress("ForceNew attribute index file")
 
-	// Wait for all workers to complete
-	go func() {
-		wg.Wait()
-		close(errorChan)
-	}()
-
-	// Check for errors
-	for err := range errorChan {
-		if err != nil {
-			return err
-		}
+	// Write sensitive attributes index
+	if err := index.writeSensitiveIndexFile(outputDir); err != nil {
+		return fmt.Errorf("failed to write sensitive attribute index file: %w", err)
 	}
+	progressTracker.UpdateProgress("sensitive attribute index file")
 
-	return nil
-}
+	// Write go-azure-sdk API version to resources reverse map
+	if err := index.writeSDKIndexFile(outputDir); err != nil {
+		return fmt.Errorf("failed to write SDK index file: %w", err)
+	}
+	progressTracker.UpdateProgress("SDK index file")
 
-// WriteResourceFiles writes individual JSON files for each resource
-func (index *TerraformProviderIndex) WriteResourceFiles(outputDir string, progressTracker *ProgressTracker) error {
-	resourcesDir := filepath.Join(outputDir, "resources")
-	var tasks []func() error
+	// Write Azure API version usage report
+	if err := index.writeAPIVersionReportFile(outputDir); err != nil {
+		return fmt.Errorf("failed to write API version usage report: %w", err)
+	}
+	progressTracker.UpdateProgress("API version usage report")
 
-	for _, service := range index.Services {
-		// Process legacy resources
-		for terraformType, registrationMethod := range service.SupportedResources {
-			// Capture variables for closure
-			tfType := terraformType
-			regMethod := registrationMethod
-			svc := service
-
-			tasks = append(tasks, func() error {
-				resourceInfo := NewTerraformResourceInfo(tfType, "", regMethod, "legacy_pluginsdk", svc)
-				fileName := fmt.Sprintf("%s.json", tfType)
-				filePath := filepath.Join(resourcesDir, fileName)
-
-				if err := index.WriteJSONFile(filePath, resourceInfo); err != nil {
-					return fmt.Errorf("failed to write legacy resource file %s: %w", fileName, err)
-				}
+	// Write SDK feature usage heatmap
+	if err := index.writeFeatureHeatmapFile(outputDir); err != nil {
+		return fmt.Errorf("failed to write feature heatmap file: %w", err)
+	}
+	progressTracker.UpdateProgress("feature heatmap file")
 
-				progressTracker.UpdateProgress(fmt.Sprintf("resource %s", tfType))
-				return nil
-			})
-		}
+	// Write likely dead exported helpers report
+	if err := index.writeUnreferencedFunctionsFile(outputDir); err != nil {
+		return fmt.Errorf("failed to write unreferenced functions file: %w", err)
+	}
+	progressTracker.UpdateProgress("unreferenced functions file")
 
-		// Process modern resources
-		for _, structType := range service.Resources {
-			// Capture variables for closure
-			structT := structType
-			svc := service
+	// Write resources and data sources no registration method references
+	if err := index.writeOrphansFile(outputDir); err != nil {
+		return fmt.Errorf("failed to write orphans file: %w", err)
+	}
+	progressTracker.UpdateProgress("orphans file")
 
-			tasks = append(tasks, func() error {
-				// Get the actual Terraform type from the mapping
-				terraformType, exists := svc.ResourceTerraformTypes[structT]
-				if !exists {
-					// Fallback to struct type if mapping doesn't exist
-					terraformType = structT
-				}
+	// Write Terraform types registered more than once
+	if err := index.writeDuplicatesFile(outputDir); err != nil {
+		return fmt.Errorf("failed to write duplicates file: %w", err)
+	}
+	progressTracker.UpdateProgress("duplicates file")
 
-				resourceInfo := NewTerraformResourceInfo(terraformType, structT, "", "modern_sdk", svc)
-				fileName := fmt.Sprintf("%s.json", terraformType)
-				filePath := filepath.Join(resourcesDir, fileName)
+	// Write problems found while scanning
+	if err := index.writeScanReportFile(outputDir); err != nil {
+		return fmt.Errorf("failed to write scan report file: %w", err)
+	}
+	progressTracker.UpdateProgress("scan report file")
 
-				if err := index.WriteJSONFile(filePath, resourceInfo); err != nil {
-					return fmt.Errorf("failed to write modern resource file %s: %w", fileName, err)
-				}
+	// Write the list of documents for hosts without directory listings
+	if err := index.writeFileListFile(outputDir); err != nil {
+		return fmt.Errorf("failed to write file list file: %w", err)
+	}
+	progressTracker.UpdateProgress("file list file")
 
-				progressTracker.UpdateProgress(fmt.Sprintf("resource %s", terraformType))
-				return nil
-			})
+	// Write undocumented resources report when documentation was linked
+	if index.Documentation != nil {
+		if err := index.writeDocumentationReportFile(outputDir); err != nil {
+			return fmt.Errorf("failed to write documentation report file: %w", err)
 		}
+		progressTracker.UpdateProgress("documentation report file")
 	}
 
-	return processCallbacksParallel(tasks)
-}
-
-// WriteDataSourceFiles writes individual JSON files for each data source
-func (index *TerraformProviderIndex) WriteDataSourceFiles(outputDir string, progressTracker *ProgressTracker) error {
-	dataSourcesDir := filepath.Join(outputDir, "datasources")
-	var tasks []func() error
-
-	for _, service := range index.Services {
-		// Process legacy data sources
-		for terraformType, registrationMethod := range service.SupportedDataSources {
-			// Capture variables for closure
-			tfType := terraformType
-			regMethod := registrationMethod
-			svc := service
-
-			tasks = append(tasks, func() error {
-				dataSourceInfo := NewTerraformDataSourceInfo(tfType, "", regMethod, "legacy_pluginsdk", svc)
-				fileName := fmt.Sprintf("%s.json", tfType)
-				filePath := filepath.Join(dataSourcesDir, fileName)
-
-				if err := index.WriteJSONFile(filePath, dataSourceInfo); err != nil {
-					return fmt.Errorf("failed to write legacy data source file %s: %w", fileName, err)
-				}
+	// Write corrected and broken goindex references when they were verified
+	if index.GoIndex != nil {
+		if err := index.writeGoIndexReportFile(outputDir); err != nil {
+			return fmt.Errorf("failed to write goindex report file: %w", err)
+		}
+		progressTracker.UpdateProgress("goindex report file")
+	}
 
-				progressTracker.UpdateProgress(fmt.Sprintf("data source %s", tfType))
-				return nil
-			})
+	// Write schema drift from the API specs when it was detected
+	if index.APIDrift != nil {
+		if err := index.writeAPIDriftReportFile(outputDir); err != nil {
+			return fmt.Errorf("failed to write API drift report file: %w", err)
 		}
+		progressTracker.UpdateProgress("API drift report file")
+	}
 
-		// Process modern data sources
-		for _, structType := range service.DataSources {
-			// Capture variables for closure
-			structT := structType
-			svc := service
+	// Write the services missing from the index or the provider when the coverage was checked
+	if index.ProviderCoverage != nil {
+		if err := index.writeProviderCoverageReportFile(outputDir); err != nil {
+			return fmt.Errorf("failed to write provider coverage report file: %w", err)
+		}
+		progressTracker.UpdateProgress("provider coverage report file")
+	}
 
-			tasks = append(tasks, func() error {
-				// Get the actual Terraform type from the mapping
-				terraformType, exists := svc.DataSourceTerraformTypes[structT]
-				if !exists {
-					// Fallback to struct type if mapping doesn't exist
-					terraformType = structT
-				}
+	// Write the Terraform types missing from the index or the provider schema when the schema was reconciled
+	if index.ProviderSchema != nil {
+		if err := index.writeProviderSchemaReportFile(outputDir); err != nil {
+			return fmt.Errorf("failed to write provider schema report file: %w", err)
+		}
+		progressTracker.UpdateProgress("provider schema report file")
+	}
 
-				dataSourceInfo := NewTerraformDataSourceInfo(terraformType, structT, "", "modern_sdk", svc)
-				fileName := fmt.Sprintf("%s.json", terraformType)
-				filePath := filepath.Join(dataSourcesDir, fileName)
+	// Write the SDK clients of every service with a client package
+	if err := index.writeServiceClientFiles(outputDir, progressTracker); err != nil {
+		return err
+	}
 
-				if err := index.WriteJSONFile(filePath, dataSourceInfo); err != nil {
-					return fmt.Errorf("failed to write modern data source file %s: %w", fileName, err)
-				}
+	// Write a summary of every service when requested
+	if index.Output.ServiceSummaries {
+		if err := index.writeServiceSummaryFiles(outputDir, progressTracker); err != nil {
+			return err
+		}
+	}
 
-				progressTracker.UpdateProgress(fmt.Sprintf("data source %s", terraformType))
-				return nil
-			})
+	// Write the human-readable report when requested
+	if index.Output.MarkdownReport {
+		if err := index.writeMarkdownReportFile(outputDir); err != nil {
+			return fmt.Errorf("failed to write markdown report file: %w", err)
 		}
+		progressTracker.UpdateProgress("markdown report file")
 	}
 
-	return processCallbacksParallel(tasks)
+	return nil
 }
 
-// WriteEphemeralFiles writes individual JSON files for each ephemeral resource
-func (index *TerraformProviderIndex) WriteEphemeralFiles(outputDir string, progressTracker *ProgressTracker) error {
-	ephemeralDir := filepath.Join(outputDir, "ephemeral")
-	var tasks []func() error
-
-	for _, service := range index.Services {
-		for structType, tfType := range service.EphemeralTerraformTypes {
-			// Capture variables for closure
-			structT := structType
-			svc := service
-			terraformType := tfType
+// writeMainIndexFile writes the main index file, terraform-provider-azurerm-index.json unless configured otherwise
+func (index *TerraformProviderIndex) writeMainIndexFile(outputDir string) error {
+	mainIndexPath := filepath.Join(outputDir, index.Output.MainIndexFileName())
+	return index.writeJSONFile(mainIndexPath, index)
+}
 
-			tasks = append(tasks, func() error {
+// WriteMainIndex writes the content of the main index file to w, the main index with every document embedded when the
+// output is a single file, for pipelines reading the index from standard output
+func (index *TerraformProviderIndex) WriteMainIndex(w io.Writer) error {
+	var data interface{} = index
+	if index.Output.SingleFile {
+		data = index.BuildSingleFileIndex()
+	}
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal data to JSON: %w", err)
+	}
+	if _, err := w.Write(append(jsonData, '\n')); err != nil {
+		return fmt.Errorf("failed to write main index: %w", err)
+	}
+	return nil
+}
 
-				ephemeralInfo := NewTerraformEphemeralInfo(structT, svc)
-				fileName := fmt.Sprintf("%s.json", terraformType)
-				filePath := filepath.Join(ephemeralDir, fileName)
+// writeResourceFiles writes individual JSON files for each resource
+func (index *TerraformProviderIndex) writeResourceFiles(outputDir string, progressTracker *ProgressTracker) error {
+	return index.writeDocumentKindFiles(outputDir, DocumentKindResource, progressTracker)
+}
 
-				if err := index.WriteJSONFile(filePath, ephemeralInfo); err != nil {
-					return fmt.Errorf("failed to write ephemeral resource file %s: %w", fileName, err)
-				}
+// writeDataSourceFiles writes individual JSON files for each data source
+func (index *TerraformProviderIndex) writeDataSourceFiles(outputDir string, progressTracker *ProgressTracker) error {
+	return index.writeDocumentKindFiles(outputDir, DocumentKindDataSource, progressTracker)
+}
 
-				progressTracker.UpdateProgress(fmt.Sprintf("ephemeral %s", terraformType))
-				return nil
-			})
-		}
-	}
+// writeEphemeralFiles writes individual JSON files for each ephemeral resource
+func (index *TerraformProviderIndex) writeEphemeralFiles(outputDir string, progressTracker *ProgressTracker) error {
+	return index.writeDocumentKindFiles(outputDir, DocumentKindEphemeral, progressTracker)
+}
 
-	return processCallbacksParallel(tasks)
+// writeDocumentKindFiles writes the JSON files of the documents of one kind
+func (index *TerraformProviderIndex) writeDocumentKindFiles(outputDir, kind string, progressTracker *ProgressTracker) error {
+	emitter := JSONDocumentEmitter{DocumentKind: kind, OutputDir: outputDir, Workers: index.Output.Workers, Progress: progressTracker, Events: index.Events, Fs: index.Output.fs()}
+	return emitter.Emit(index.DocumentsOfKind(kind))
 }
 
-// CreateDirectoryStructure creates the required directory structure for index files
-func (index *TerraformProviderIndex) CreateDirectoryStructure(outputDir string) error {
-	dirs := []string{
-		outputDir,
-		filepath.Join(outputDir, "resources"),
-		filepath.Join(outputDir, "datasources"),
-		filepath.Join(outputDir, "ephemeral"),
+// createDirectoryStructure creates the required directory structure for index files
+func (index *TerraformProviderIndex) createDirectoryStructure(outputDir string) error {
+	dirs := []string{outputDir}
+	for _, kind := range documentKinds {
+		dirs = append(dirs, filepath.Join(outputDir, kind.kind))
 	}
 
 	for _, dir := range dirs {
-		if err := outputFs.MkdirAll(dir, 0755); err != nil {
+		if err := index.Output.fs().MkdirAll(dir, 0755); err != nil {
 			return fmt.Errorf("failed to create directory %s: %w", dir, err)
 		}
 	}
@@ -452,49 +951,28 @@ func (index *TerraformProviderIndex) CreateDirectoryStructure(outputDir string)
 	return nil
 }
 
-// WriteJSONFile writes data as JSON to the specified file path
-func (index *TerraformProviderIndex) WriteJSONFile(filePath string, data interface{}) error {
-	// Ensure parent directory exists
-	parentDir := filepath.Dir(filePath)
-	if err := outputFs.MkdirAll(parentDir, 0755); err != nil {
-		return fmt.Errorf("failed to create parent directory %s: %w", parentDir, err)
-	}
-
-	// Marshal data to JSON with indentation
-	jsonData, err := json.MarshalIndent(data, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal data to JSON: %w", err)
-	}
-
-	// Write to file
-	if err := afero.WriteFile(outputFs, filePath, jsonData, 0644); err != nil {
-		return fmt.Errorf("failed to write file %s: %w", filePath, err)
-	}
-
-	return nil
+// writeJSONFile writes data as JSON to the specified file path on the file system of the output
+func (index *TerraformProviderIndex) writeJSONFile(filePath string, data interface{}) error {
+	return output.WriteJSONFile(index.Output.fs(), filePath, data)
 }
 
 // convertFunctionNamesToStructNames converts ephemeral resource function names to struct names
-// by looking up the function declarations in PackageInfo and parsing their return statements
+// by looking up the function declarations in PackageInfo and following their return statements
 // For example: "NewKeyVaultSecretEphemeralResource" -> "KeyVaultSecretEphemeralResource"
 func convertFunctionNamesToStructNames(functionNames []string, packageInfo *gophon.PackageInfo) []string {
 	if packageInfo == nil || packageInfo.Functions == nil {
 		return functionNames // Return as-is if no package info available
 	}
 
+	// Constructors may delegate to functions declared in other files of the package
+	functions := packageFunctionDecls(packageInfo)
+
 	structNames := make([]string, 0, len(functionNames))
 
 	for _, funcName := range functionNames {
-		// Find the function in the gophon function data
 		structName := ""
-		for _, funcInfo := range packageInfo.Functions {
-			if funcInfo.Name == funcName && funcInfo.FuncDecl != nil {
-				// Extract struct type from the function's return statement
-				if extracted := extractStructTypeFromEphemeralFunction(funcInfo.FuncDecl); extracted != "" {
-					structName = extracted
-					break
-				}
-			}
+		if funcDecl := functions[funcName]; funcDecl != nil {
+			structName = resolveConstructorStructType(funcDecl, functions, make(map[string]bool))
 		}
 
 		// If we couldn't extract from AST, fall back to string manipulation
@@ -512,35 +990,126 @@ func convertFunctionNamesToStructNames(functionNames []string, packageInfo *goph
 	return structNames
 }
 
+// registeredConstructors maps the struct types of ephemeral resources, list resources or actions to the constructor
+// functions registering them, the function names and struct names are in the order convertFunctionNamesToStructNames
+// returns them
+func registeredConstructors(functionNames, structNames []string) map[string]string {
+	constructors := make(map[string]string, len(structNames))
+	for i, structName := range structNames {
+		constructors[structName] = functionNames[i]
+	}
+	return constructors
+}
+
 // extractStructTypeFromEphemeralFunction extracts the struct type name from an ephemeral resource function
 // For example, from: func NewKeyVaultSecretEphemeralResource() ephemeral.EphemeralResource { return &KeyVaultSecretEphemeralResource{} }
 // It extracts: "KeyVaultSecretEphemeralResource"
 func extractStructTypeFromEphemeralFunction(funcDecl *ast.FuncDecl) string {
-	if funcDecl == nil || funcDecl.Body == nil {
-		return ""
+	return resolveConstructorStructType(funcDecl, nil, make(map[string]bool))
+}
+
+// packageFunctionDecls maps the names of the package level functions of a package to their declarations
+func packageFunctionDecls(packageInfo *gophon.PackageInfo) map[string]*ast.FuncDecl {
+	functions := make(map[string]*ast.FuncDecl)
+	for _, funcInfo := range packageInfo.Functions {
+		if funcInfo.FuncDecl != nil && funcInfo.FuncDecl.Recv == nil {
+			if _, exists := functions[funcInfo.Name]; !exists {
+				functions[funcInfo.Name] = funcInfo.FuncDecl
+			}
+		}
 	}
+	return functions
+}
 
-	// Look for return statements in the function body
-	for _, stmt := range funcDecl.Body.List {
-		if returnStmt, ok := stmt.(*ast.ReturnStmt); ok {
-			for _, result := range returnStmt.Results {
-				// Handle &StructName{} pattern
-				if unaryExpr, ok := result.(*ast.UnaryExpr); ok && unaryExpr.Op == token.AND {
-					if compLit, ok := unaryExpr.X.(*ast.CompositeLit); ok {
-						if ident, ok := compLit.Type.(*ast.Ident); ok {
-							return ident.Name
+// resolveConstructorStructType follows the return statements of a constructor to the struct type it builds:
+// composite literals and new() calls, local variables holding them and calls of other package functions, looked up
+// in functions. visited guards against constructors calling each other.
+func resolveConstructorStructType(funcDecl *ast.FuncDecl, functions map[string]*ast.FuncDecl, visited map[string]bool) string {
+	if funcDecl == nil || funcDecl.Body == nil || visited[funcDecl.Name.Name] {
+		return ""
+	}
+	visited[funcDecl.Name.Name] = true
+
+	// Types of the local variables, "r := &KeyVaultSecretEphemeralResource{}" or "var r KeyVaultSecretEphemeralResource"
+	locals := make(map[string]string)
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			if len(stmt.Lhs) == len(stmt.Rhs) {
+				for i, lhs := range stmt.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok {
+						if name := constructedTypeName(stmt.Rhs[i], locals, functions, visited); name != "" {
+							locals[ident.Name] = name
 						}
 					}
 				}
-				// Handle StructName{} pattern (without &)
-				if compLit, ok := result.(*ast.CompositeLit); ok {
-					if ident, ok := compLit.Type.(*ast.Ident); ok {
-						return ident.Name
+			}
+		case *ast.ValueSpec:
+			for i, ident := range stmt.Names {
+				if stmt.Type != nil {
+					if name := typeName(stmt.Type); name != "" {
+						locals[ident.Name] = name
+					}
+				} else if i < len(stmt.Values) {
+					if name := constructedTypeName(stmt.Values[i], locals, functions, visited); name != "" {
+						locals[ident.Name] = name
 					}
 				}
 			}
 		}
-	}
+		return true
+	})
+
+	// Look for return statements in the function body, nested ones included
+	result := ""
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		if result != "" {
+			return false
+		}
+		switch stmt := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			for _, expr := range stmt.Results {
+				if name := constructedTypeName(expr, locals, functions, visited); name != "" {
+					result = name
+					return false
+				}
+			}
+		}
+		return true
+	})
+	return result
+}
 
+// constructedTypeName returns the struct type an expression of a constructor evaluates to, "" when unknown
+func constructedTypeName(expr ast.Expr, locals map[string]string, functions map[string]*ast.FuncDecl, visited map[string]bool) string {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return constructedTypeName(e.X, locals, functions, visited)
+	case *ast.UnaryExpr:
+		// Handle &StructName{} pattern
+		if e.Op == token.AND {
+			return constructedTypeName(e.X, locals, functions, visited)
+		}
+	case *ast.CompositeLit:
+		// Handle StructName{} pattern (without &)
+		return typeName(e.Type)
+	case *ast.Ident:
+		return locals[e.Name]
+	case *ast.CallExpr:
+		ident, ok := e.Fun.(*ast.Ident)
+		if !ok {
+			return ""
+		}
+		if ident.Name == "new" && len(e.Args) == 1 {
+			return typeName(e.Args[0])
+		}
+		if funcDecl := functions[ident.Name]; funcDecl != nil {
+			return resolveConstructorStructType(funcDecl, functions, visited)
+		}
+	}
 	return ""
 }
@@ -1,6 +1,7 @@
 package pkg
 
 import (
+	"bytes"
 	"encoding/json"
 	gophon "github.com/lonegunmanb/gophon/pkg"
 	"go/ast"
@@ -9,7 +10,6 @@ import (
 	"path/filepath"
 	"testing"
 
-	"github.com/prashantv/gostub"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -83,8 +83,7 @@ func TestTerraformProviderIndex_WriteIndexFiles(t *testing.T) {
 	// Setup
 	sut := createTestTerraformProviderIndex()
 	fs := afero.NewMemMapFs()
-	stub := gostub.Stub(&outputFs, fs)
-	defer stub.Reset()
+	sut.Output.Fs = fs
 	outputDir := "/test/output"
 
 	// Execute
@@ -114,12 +113,11 @@ func TestTerraformProviderIndex_WriteResourceFiles(t *testing.T) {
 	// Setup
 	sut := createTestTerraformProviderIndex()
 	fs := afero.NewMemMapFs()
-	stub := gostub.Stub(&outputFs, fs)
-	defer stub.Reset()
+	sut.Output.Fs = fs
 	outputDir := "/test/output"
 
 	// Execute
-	err := sut.WriteResourceFiles(outputDir, nil)
+	err := sut.writeResourceFiles(outputDir, nil)
 
 	// Verify
 	require.NoError(t, err)
@@ -170,12 +168,11 @@ func TestTerraformProviderIndex_WriteDataSourceFiles(t *testing.T) {
 	// Setup
 	index := createTestTerraformProviderIndex()
 	fs := afero.NewMemMapFs()
-	stub := gostub.Stub(&outputFs, fs)
-	defer stub.Reset()
+	index.Output.Fs = fs
 	outputDir := "/test/output"
 
 	// Execute
-	err := index.WriteDataSourceFiles(outputDir, nil)
+	err := index.writeDataSourceFiles(outputDir, nil)
 
 	// Verify
 	require.NoError(t, err)
@@ -226,12 +223,11 @@ func TestTerraformProviderIndex_WriteEphemeralFiles(t *testing.T) {
 	// Setup
 	index := createTestTerraformProviderIndex()
 	fs := afero.NewMemMapFs()
-	stub := gostub.Stub(&outputFs, fs)
-	defer stub.Reset()
+	index.Output.Fs = fs
 	outputDir := "/test/output"
 
 	// Execute
-	err := index.WriteEphemeralFiles(outputDir, nil)
+	err := index.writeEphemeralFiles(outputDir, nil)
 
 	// Verify
 	require.NoError(t, err)
@@ -264,12 +260,11 @@ func TestTerraformProviderIndex_WriteMainIndexFile(t *testing.T) {
 	// Setup
 	index := createTestTerraformProviderIndex()
 	fs := afero.NewMemMapFs()
-	stub := gostub.Stub(&outputFs, fs)
-	defer stub.Reset()
+	index.Output.Fs = fs
 	outputDir := "/test/output"
 
 	// Execute
-	err := index.WriteMainIndexFile(outputDir)
+	err := index.writeMainIndexFile(outputDir)
 
 	// Verify
 	require.NoError(t, err)
@@ -292,16 +287,59 @@ func TestTerraformProviderIndex_WriteMainIndexFile(t *testing.T) {
 	assert.Equal(t, index.Statistics, readIndex.Statistics)
 }
 
+func TestTerraformProviderIndex_WriteMainIndex(t *testing.T) {
+	index := createTestTerraformProviderIndex()
+	fs := afero.NewMemMapFs()
+	index.Output.Fs = fs
+	require.NoError(t, index.writeMainIndexFile("/test/output"))
+	mainIndex, err := afero.ReadFile(fs, "/test/output/terraform-provider-azurerm-index.json")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, index.WriteMainIndex(&buf))
+	assert.Equal(t, string(mainIndex)+"\n", buf.String())
+
+	index.Output.SingleFile = true
+	buf.Reset()
+	require.NoError(t, index.WriteMainIndex(&buf))
+	var singleFile SingleFileIndex
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &singleFile))
+	assert.Equal(t, index.Version, singleFile.Version)
+	assert.Contains(t, singleFile.Documents.Resources, "azurerm_key_vault")
+}
+
+func TestTerraformProviderIndex_WriteMainIndexFile_CustomName(t *testing.T) {
+	// Setup
+	index := createTestTerraformProviderIndex()
+	index.Output = OutputConfig{ProviderName: "azapi"}
+	fs := afero.NewMemMapFs()
+	index.Output.Fs = fs
+	outputDir := "/test/output"
+
+	// Execute
+	err := index.writeMainIndexFile(outputDir)
+
+	// Verify
+	require.NoError(t, err)
+
+	exists, err := afero.Exists(fs, filepath.Join(outputDir, "terraform-provider-azapi-index.json"))
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = afero.Exists(fs, filepath.Join(outputDir, "terraform-provider-azurerm-index.json"))
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
 func TestTerraformProviderIndex_CreateDirectoryStructure(t *testing.T) {
 	// Setup
 	index := createTestTerraformProviderIndex()
 	fs := afero.NewMemMapFs()
-	stub := gostub.Stub(&outputFs, fs)
-	defer stub.Reset()
+	index.Output.Fs = fs
 	outputDir := "/test/output"
 
 	// Execute
-	err := index.CreateDirectoryStructure(outputDir)
+	err := index.createDirectoryStructure(outputDir)
 
 	// Verify
 	require.NoError(t, err)
@@ -321,42 +359,6 @@ func TestTerraformProviderIndex_CreateDirectoryStructure(t *testing.T) {
 	}
 }
 
-func TestTerraformProviderIndex_WriteJSONFile(t *testing.T) {
-	// Setup
-	index := createTestTerraformProviderIndex()
-	fs := afero.NewMemMapFs()
-	stub := gostub.Stub(&outputFs, fs)
-	defer stub.Reset()
-	filePath := "/test/data.json"
-	testData := map[string]interface{}{
-		"key1": "value1",
-		"key2": 42,
-		"key3": []string{"a", "b", "c"},
-	}
-
-	// Execute
-	err := index.WriteJSONFile(filePath, testData)
-
-	// Verify
-	require.NoError(t, err)
-
-	exists, err := afero.Exists(fs, filePath)
-	require.NoError(t, err)
-	assert.True(t, exists)
-
-	// Read and verify content
-	data, err := afero.ReadFile(fs, filePath)
-	require.NoError(t, err)
-
-	var readData map[string]interface{}
-	err = json.Unmarshal(data, &readData)
-	require.NoError(t, err)
-
-	assert.Equal(t, "value1", readData["key1"])
-	assert.Equal(t, float64(42), readData["key2"]) // JSON numbers are float64
-	assert.Len(t, readData["key3"], 3)
-}
-
 func TestTerraformProviderIndex_WriteIndexFiles_EmptyIndex(t *testing.T) {
 	// Setup - empty index
 	index := &TerraformProviderIndex{
@@ -365,8 +367,7 @@ func TestTerraformProviderIndex_WriteIndexFiles_EmptyIndex(t *testing.T) {
 		Statistics: ProviderStatistics{},
 	}
 	fs := afero.NewMemMapFs()
-	stub := gostub.Stub(&outputFs, fs)
-	defer stub.Reset()
+	index.Output.Fs = fs
 	outputDir := "/test/output"
 
 	// Execute
@@ -399,8 +400,7 @@ func TestTerraformProviderIndex_WriteIndexFiles_FileSystemError(t *testing.T) {
 	// Setup - read-only filesystem to trigger errors
 	index := createTestTerraformProviderIndex()
 	fs := afero.NewReadOnlyFs(afero.NewMemMapFs())
-	stub := gostub.Stub(&outputFs, fs)
-	defer stub.Reset()
+	index.Output.Fs = fs
 	outputDir := "/test/output"
 
 	// Execute
@@ -426,14 +426,13 @@ func TestTerraformProviderIndex_WriteResourceFiles_NoResources(t *testing.T) {
 		},
 	}
 	fs := afero.NewMemMapFs()
-	stub := gostub.Stub(&outputFs, fs)
-	defer stub.Reset()
+	index.Output.Fs = fs
 	outputDir := "/test/output"
 
 	// Execute
-	err := index.CreateDirectoryStructure(outputDir)
+	err := index.createDirectoryStructure(outputDir)
 	require.NoError(t, err)
-	err = index.WriteResourceFiles(outputDir, nil)
+	err = index.writeResourceFiles(outputDir, nil)
 
 	// Verify - should succeed even with no resources
 	require.NoError(t, err)
@@ -469,6 +468,39 @@ func NewKeyVaultCertificateEphemeralResource() ephemeral.EphemeralResource {
 }`,
 			expected: "KeyVaultCertificateEphemeralResource",
 		},
+		{
+			name: "Ephemeral function returning a local variable",
+			funcCode: `
+package test
+func NewKeyVaultSecretEphemeralResource() ephemeral.EphemeralResource {
+	r := &KeyVaultSecretEphemeralResource{}
+	r.cache = newCache()
+	return r
+}`,
+			expected: "KeyVaultSecretEphemeralResource",
+		},
+		{
+			name: "Ephemeral function with declared variable and nested return",
+			funcCode: `
+package test
+func NewKeyVaultSecretEphemeralResource() ephemeral.EphemeralResource {
+	var r KeyVaultSecretEphemeralResource
+	if features.Enabled() {
+		return &r
+	}
+	return new(KeyVaultSecretEphemeralResource)
+}`,
+			expected: "KeyVaultSecretEphemeralResource",
+		},
+		{
+			name: "Ephemeral function returning an unknown value",
+			funcCode: `
+package test
+func NewKeyVaultSecretEphemeralResource() ephemeral.EphemeralResource {
+	return sdk.Wrap(nil)
+}`,
+			expected: "",
+		},
 	}
 
 	for _, tt := range tests {
@@ -537,6 +569,25 @@ func CreateSomething() ephemeral.EphemeralResource {
 }`,
 			expected: []string{"SomethingEphemeralResource"}, // Should extract from AST
 		},
+		{
+			name:          "Constructor delegating to a function of another file",
+			functionNames: []string{"NewKeyVaultSecretEphemeralResource", "NewLoop"},
+			packageCode: `
+package test
+func NewKeyVaultSecretEphemeralResource() ephemeral.EphemeralResource {
+	return newSecretEphemeral(defaultTimeout)
+}
+func newSecretEphemeral(timeout time.Duration) *KeyVaultSecretEphemeralResource {
+	return &KeyVaultSecretEphemeralResource{timeout: timeout}
+}
+func NewLoop() ephemeral.EphemeralResource {
+	return newLoop()
+}
+func newLoop() ephemeral.EphemeralResource {
+	return NewLoop()
+}`,
+			expected: []string{"KeyVaultSecretEphemeralResource", "Loop"}, // Cycles fall back to string manipulation
+		},
 	}
 
 	for _, tt := range tests {
@@ -4,20 +4,126 @@ import "fmt"
 
 // TerraformResource represents information about a Terraform resource
 type TerraformResource struct {
-	TerraformType      string `json:"terraform_type"`            // "azurerm_resource_group"
-	StructType         string `json:"struct_type"`               // "ResourceGroupResource"
-	Namespace          string `json:"namespace"`                 // "github.com/hashicorp/terraform-provider-azurerm/internal/services/resource"
-	RegistrationMethod string `json:"registration_method"`       // "SupportedResources", "Resources", etc.
-	SDKType            string `json:"sdk_type"`                  // "legacy_pluginsdk", "modern_sdk"
-	SchemaIndex        string `json:"schema_index,omitempty"`    // "func.resourceGroup.goindex" or "method.ContainerAppResource.Arguments.goindex" (optional)
-	CreateIndex        string `json:"create_index,omitempty"`    // "func.resourceGroupCreateFunc.goindex" or "method.ContainerAppResource.Create.goindex (optional)
-	ReadIndex          string `json:"read_index,omitempty"`      // "func.resourceGroupReadFunc.goindex" or "method.ContainerAppResource.Read.goindex" (optional)
-	UpdateIndex        string `json:"update_index,omitempty"`    // "func.resourceGroupUpdateFunc.goindex" or "method.ContainerAppResource.Update.goindex" (optional)
-	DeleteIndex        string `json:"delete_index,omitempty"`    // "func.resourceGroupDeleteFunc.goindex" or "method.ContainerAppResource.Delete.goindex" (optional)
-	AttributeIndex     string `json:"attribute_index,omitempty"` // "func.resourceGroup.goindex" "method.ContainerAppResource.Attributes.goindex"(optional)
+	ID                 string `json:"id" protobuf:"1"`                         // "azurerm/resources/azurerm_resource_group/legacy_pluginsdk", see EntryID
+	TerraformType      string `json:"terraform_type" protobuf:"2"`             // "azurerm_resource_group"
+	StructType         string `json:"struct_type" protobuf:"3"`                // "ResourceGroupResource"
+	Namespace          string `json:"namespace" protobuf:"4"`                  // "github.com/hashicorp/terraform-provider-azurerm/internal/services/resource"
+	RegistrationMethod string `json:"registration_method" protobuf:"5"`        // "SupportedResources", "Resources", etc.
+	SDKType            string `json:"sdk_type" protobuf:"6"`                   // "legacy_pluginsdk", "modern_sdk"
+	SchemaIndex        string `json:"schema_index,omitempty" protobuf:"7"`     // "func.resourceGroup.goindex" or "method.ContainerAppResource.Arguments.goindex" (optional)
+	CreateIndex        string `json:"create_index,omitempty" protobuf:"8"`     // "func.resourceGroupCreateFunc.goindex" or "method.ContainerAppResource.Create.goindex (optional)
+	ReadIndex          string `json:"read_index,omitempty" protobuf:"9"`       // "func.resourceGroupReadFunc.goindex" or "method.ContainerAppResource.Read.goindex" (optional)
+	UpdateIndex        string `json:"update_index,omitempty" protobuf:"10"`    // "func.resourceGroupUpdateFunc.goindex" or "method.ContainerAppResource.Update.goindex" (optional)
+	DeleteIndex        string `json:"delete_index,omitempty" protobuf:"11"`    // "func.resourceGroupDeleteFunc.goindex" or "method.ContainerAppResource.Delete.goindex" (optional)
+	AttributeIndex     string `json:"attribute_index,omitempty" protobuf:"12"` // "func.resourceGroup.goindex" "method.ContainerAppResource.Attributes.goindex"(optional)
+	// Strategy that inferred the Terraform type of a typed resource, for debugging extraction quality
+	TerraformTypeStrategy string `json:"terraform_type_strategy,omitempty" protobuf:"13"` // "resource_type_literal" (optional)
+	// Details extracted from the resource implementation
+	SchemaVersion  int      `json:"schema_version,omitempty" protobuf:"14"`  // 2 (optional)
+	StateUpgraders []string `json:"state_upgraders,omitempty" protobuf:"15"` // ["migration.KeyVaultV0ToV1", "migration.KeyVaultV1ToV2"] (optional)
+	CustomizeDiff  []string `json:"customize_diff,omitempty" protobuf:"16"`  // ["resourceKubernetesClusterCustomizeDiff", "pluginsdk.ForceNewIfChange"] or ["KubernetesClusterResource.CustomizeDiff"] (optional)
+	// Azure Resource Manager resource type managed by the resource and the parser of its ID
+	AzureResourceType string `json:"azure_resource_type,omitempty" protobuf:"17"` // "Microsoft.KeyVault/vaults" (optional)
+	IDParser          string `json:"id_parser,omitempty" protobuf:"18"`           // "commonids.ParseKeyVaultID" or "parse.VaultID" (optional)
+	// go-azure-sdk packages referenced by the CRUD functions
+	SDKPackages []string `json:"sdk_packages,omitempty" protobuf:"19"` // ["github.com/hashicorp/go-azure-sdk/resource-manager/keyvault/2023-07-01/vaults"] (optional)
+	// Azure API operations called through go-azure-sdk clients
+	APIOperations []APIOperation `json:"api_operations,omitempty" protobuf:"20"` // [{"operation": "VaultsClient.CreateOrUpdate", "http_method": "PUT", ...}] (optional)
+	// Operation timeouts in minutes
+	Timeouts map[string]int `json:"timeouts,omitempty" protobuf:"21"` // {"create": 30, "read": 5, "update": 30, "delete": 30} (optional)
+	// Capabilities detected from the CRUD implementation
+	Capabilities []string `json:"capabilities,omitempty" protobuf:"22"` // ["update_reuses_create"], ["update_unsupported"] or ["raw_rest_calls"] (optional)
+	// Website documentation, only set when documentation was linked with -docs-path
+	Documentation *DocumentationLink `json:"documentation,omitempty" protobuf:"23"` // {"doc_file": "website/docs/r/key_vault.html.markdown", ...} (optional)
+	// Top level schema attributes with their validation function references
+	Schema []SchemaAttribute `json:"schema,omitempty" protobuf:"24"` // [{"name": "name", "type": "TypeString", "validate_funcs": ["validate.ResourceGroupName"]}] (optional)
+	// Deprecation details, only set for deprecated resources
+	Deprecated         bool   `json:"deprecated,omitempty" protobuf:"25"`          // true
+	DeprecationMessage string `json:"deprecation_message,omitempty" protobuf:"26"` // "The `azurerm_foo` resource has been superseded by the `azurerm_bar` resource"
+	// Set for typed resources the service only registers under a condition, such as a feature flag
+	Conditional bool `json:"conditional,omitempty" protobuf:"27"` // true
+	// Service grouping declared by the registration, for category-based grouping of documentation
+	DisplayName       string   `json:"display_name,omitempty" protobuf:"28"`       // "Key Vault" (optional)
+	WebsiteCategories []string `json:"website_categories,omitempty" protobuf:"29"` // ["Key Vault"] (optional)
+	// Issue triage label declared by the registration, for routing questions about the resource
+	GitHubLabel string `json:"github_label,omitempty" protobuf:"30"` // "service/key-vault" (optional)
+	// User-maintained metadata, only set when annotations were loaded with -annotations
+	XAnnotations map[string]interface{} `json:"x_annotations,omitempty" protobuf:"31"` // {"cost_tier": "high", "approval": "approved"} (optional)
+	// Set for resources without an Update function or sdk.ResourceWithUpdate implementation, every change replaces them
+	Immutable bool `json:"immutable,omitempty" protobuf:"32"` // true
+	// Feature flag the registration is gated by, with a leading ! when only registered while the flag is disabled
+	FeatureFlag string `json:"feature_flag,omitempty" protobuf:"33"` // "!features.FivePointOh" (optional)
+	// Paths of the write-only attributes of the schema, nested attributes are prefixed with their blocks
+	WriteOnlyAttributes []string `json:"write_only_attributes,omitempty" protobuf:"34"` // ["value_wo"] (optional)
+	// Doc comments of the registration function or struct and the CRUD functions
+	Doc *GoDoc `json:"doc,omitempty" protobuf:"35"` // {"registration": "...", "create": "..."} (optional)
+	// Location of the registration function or struct, for deep links to the defining code
+	SourceFile string `json:"source_file,omitempty" protobuf:"36"` // "internal/services/keyvault/key_vault_resource.go" (optional)
+	Line       int    `json:"line,omitempty" protobuf:"37"`        // 31 (optional)
+	// Locations of the CRUD functions or methods
+	CRUDSources map[string]SourceLocation `json:"crud_sources,omitempty" protobuf:"38"` // {"create": {"source_file": "...", "line": 310}} (optional)
+	// Fields of the model struct of typed resources with the attributes their tfschema tags map them to
+	Model *ResourceModel `json:"model,omitempty" protobuf:"39"` // {"struct_type": "KeyVaultResourceModel", "fields": [{"field": "Name", "attribute": "name", ...}]} (optional)
+	// Azure API versions of the go-azure-sdk packages referenced by the CRUD functions
+	APIVersions []string `json:"api_versions,omitempty" protobuf:"40"` // ["2023-07-01"] (optional)
+	// go-azure-helpers commonids ID types parsed or built by the CRUD functions, the canonical Azure resource ID formats
+	CommonIDs []string `json:"common_ids,omitempty" protobuf:"41"` // ["KeyVaultId", "SubnetId"] (optional)
+	// Paths of the attributes whose change replaces the resource, nested attributes are prefixed with their blocks
+	ForceNewAttributes []string `json:"force_new_attributes,omitempty" protobuf:"42"` // ["location", "name", "resource_group_name"] (optional)
+	// Inter-attribute constraints of the schema, for static config linting
+	Constraints []AttributeConstraint `json:"constraints,omitempty" protobuf:"43"` // [{"attribute": "key_vault_key_id", "kind": "conflicts_with", "attributes": ["key_vault_secret_id"]}] (optional)
+	// Paths of the sensitive attributes of the schema, nested attributes are prefixed with their blocks
+	SensitiveAttributes []string `json:"sensitive_attributes,omitempty" protobuf:"44"` // ["primary_access_key"] (optional)
 }
 
-func NewTerraformResourceInfo(terraformType, structType, registrationMethod, sdkType string, serviceReg ServiceRegistration) TerraformResource {
+func newTerraformResourceInfo(terraformType, structType, registrationMethod, sdkType string, serviceReg ServiceRegistration) TerraformResource {
+	result := baseTerraformResourceInfo(terraformType, structType, registrationMethod, sdkType, serviceReg)
+	result.ID = EntryID(DocumentKindResource, terraformType, sdkType)
+	if stateUpgrades, exists := serviceReg.ResourceStateUpgrades[terraformType]; exists && stateUpgrades != nil {
+		result.SchemaVersion = stateUpgrades.SchemaVersion
+		result.StateUpgraders = stateUpgrades.Upgraders
+	}
+	if structType != "" {
+		result.TerraformTypeStrategy = serviceReg.TerraformTypeStrategies[structType]
+		result.Conditional = isConditional(serviceReg.ConditionalResources, structType)
+	}
+	result.CustomizeDiff = serviceReg.ResourceCustomizeDiff[terraformType]
+	result.AzureResourceType = serviceReg.ResourceArmTypes[terraformType]
+	result.IDParser = serviceReg.ResourceIDParsers[terraformType]
+	result.SDKPackages = serviceReg.ResourceSDKPackages[terraformType]
+	result.APIVersions = apiVersionsOf(result.SDKPackages)
+	result.APIOperations = serviceReg.ResourceAPIOperations[terraformType]
+	result.Timeouts = serviceReg.ResourceTimeouts[terraformType]
+	result.Capabilities = serviceReg.ResourceCapabilities[terraformType]
+	result.Immutable = serviceReg.ResourceImmutable[terraformType]
+	result.FeatureFlag = serviceReg.ResourceFeatureFlags[terraformType]
+	result.Documentation = serviceReg.ResourceDocs[terraformType]
+	result.Schema = serviceReg.ResourceSchemas[terraformType]
+	result.WriteOnlyAttributes = writeOnlyAttributePaths(result.Schema)
+	result.ForceNewAttributes = forceNewAttributePaths(result.Schema)
+	result.SensitiveAttributes = sensitiveAttributePaths(result.Schema)
+	result.Constraints = attributeConstraints(result.Schema)
+	result.Model = serviceReg.ResourceModels[terraformType]
+	result.CommonIDs = serviceReg.ResourceCommonIDs[terraformType]
+	result.Doc = serviceReg.ResourceGoDocs[terraformType]
+	sources := serviceReg.ResourceSources[terraformType]
+	declaration := sources.declaration()
+	result.SourceFile = declaration.SourceFile
+	result.Line = declaration.Line
+	result.CRUDSources = sources.crudSources()
+	if message, exists := serviceReg.ResourceDeprecations[terraformType]; exists {
+		result.Deprecated = true
+		result.DeprecationMessage = message
+	}
+	result.DisplayName = serviceReg.DisplayName
+	result.WebsiteCategories = serviceReg.WebsiteCategories
+	result.GitHubLabel = serviceReg.GitHubLabel
+	result.XAnnotations = serviceReg.Annotations[result.ID]
+	serviceReg.resolveGoIndexReferences(result.goIndexReferences())
+	return result
+}
+
+func baseTerraformResourceInfo(terraformType, structType, registrationMethod, sdkType string, serviceReg ServiceRegistration) TerraformResource {
 	if sdkType == "legacy_pluginsdk" {
 		result := TerraformResource{
 			TerraformType:      terraformType,
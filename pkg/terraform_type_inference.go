@@ -0,0 +1,181 @@
+package pkg
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+	"unicode"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// TerraformTypeStrategy infers the Terraform type of a typed resource, data source or ephemeral resource struct
+type TerraformTypeStrategy interface {
+	// Name identifies the strategy in the index, the statistics and the -type-strategies flag
+	Name() string
+	// Resolve returns the Terraform type of the struct, or an empty string when the strategy doesn't apply
+	Resolve(packageInfo *gophon.PackageInfo, structName string) string
+}
+
+// Names of the built-in Terraform type inference strategies
+const (
+	TerraformTypeStrategyResourceTypeLiteral  = "resource_type_literal"  // ResourceType() returns a string literal
+	TerraformTypeStrategyResourceTypeConstant = "resource_type_constant" // ResourceType() returns a package level constant
+	TerraformTypeStrategyMetadata             = "metadata"               // Metadata() assigns resp.TypeName
+	TerraformTypeStrategyNamingConvention     = "naming_convention"      // Derived from the struct name
+)
+
+// DefaultTerraformTypeStrategies returns the built-in strategies in the order they are tried by default
+func DefaultTerraformTypeStrategies() []TerraformTypeStrategy {
+	return []TerraformTypeStrategy{
+		resourceTypeLiteralStrategy{},
+		resourceTypeConstantStrategy{},
+		metadataStrategy{},
+		namingConventionStrategy{},
+	}
+}
+
+// TerraformTypeStrategiesByName returns the built-in strategies with the given names, in the given order
+func TerraformTypeStrategiesByName(names []string) ([]TerraformTypeStrategy, error) {
+	available := make(map[string]TerraformTypeStrategy)
+	var availableNames []string
+	for _, strategy := range DefaultTerraformTypeStrategies() {
+		available[strategy.Name()] = strategy
+		availableNames = append(availableNames, strategy.Name())
+	}
+
+	var strategies []TerraformTypeStrategy
+	for _, name := range names {
+		strategy, exists := available[strings.TrimSpace(name)]
+		if !exists {
+			return nil, fmt.Errorf("unknown terraform type strategy %q, available strategies: %s", name, strings.Join(availableNames, ", "))
+		}
+		strategies = append(strategies, strategy)
+	}
+	if len(strategies) == 0 {
+		return nil, fmt.Errorf("no terraform type strategy given, available strategies: %s", strings.Join(availableNames, ", "))
+	}
+	return strategies, nil
+}
+
+// terraformTypeResolver tries its strategies in order, the first one returning a type wins
+type terraformTypeResolver struct {
+	strategies []TerraformTypeStrategy
+}
+
+// newTerraformTypeResolver creates a resolver, the default strategies are used when none are given
+func newTerraformTypeResolver(strategies []TerraformTypeStrategy) terraformTypeResolver {
+	if len(strategies) == 0 {
+		strategies = DefaultTerraformTypeStrategies()
+	}
+	return terraformTypeResolver{strategies: strategies}
+}
+
+// resolve returns StructType -> TerraformType for the structs any strategy resolved, recording the name of the winning
+// strategy of each struct in winners
+func (r terraformTypeResolver) resolve(packageInfo *gophon.PackageInfo, structNames []string, winners map[string]string) map[string]string {
+	terraformTypes := make(map[string]string)
+	for _, structName := range structNames {
+		for _, strategy := range r.strategies {
+			if terraformType := strategy.Resolve(packageInfo, structName); terraformType != "" {
+				terraformTypes[structName] = terraformType
+				winners[structName] = strategy.Name()
+				break
+			}
+		}
+	}
+	return terraformTypes
+}
+
+type resourceTypeLiteralStrategy struct{}
+
+func (resourceTypeLiteralStrategy) Name() string { return TerraformTypeStrategyResourceTypeLiteral }
+
+func (resourceTypeLiteralStrategy) Resolve(packageInfo *gophon.PackageInfo, structName string) string {
+	return extractTerraformTypeFromResourceTypeMethod(packageInfo, structName)
+}
+
+type resourceTypeConstantStrategy struct{}
+
+func (resourceTypeConstantStrategy) Name() string { return TerraformTypeStrategyResourceTypeConstant }
+
+// Resolve resolves return resourceType statements against string constants declared in the package
+func (resourceTypeConstantStrategy) Resolve(packageInfo *gophon.PackageInfo, structName string) string {
+	ident, ok := firstReturnedExpr(findMethodDecl(packageInfo, structName, "ResourceType")).(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	return findStringConstant(packageInfo, ident.Name)
+}
+
+type metadataStrategy struct{}
+
+func (metadataStrategy) Name() string { return TerraformTypeStrategyMetadata }
+
+func (metadataStrategy) Resolve(packageInfo *gophon.PackageInfo, structName string) string {
+	return extractTerraformTypeFromMetadataMethod(packageInfo, structName)
+}
+
+type namingConventionStrategy struct{}
+
+func (namingConventionStrategy) Name() string { return TerraformTypeStrategyNamingConvention }
+
+// Resolve derives the type from the struct name the way the provider names its structs, for example
+// KeyVaultSecretEphemeralResource -> azurerm_key_vault_secret or VirtualMachinePowerAction -> azurerm_virtual_machine_power
+func (namingConventionStrategy) Resolve(_ *gophon.PackageInfo, structName string) string {
+	for _, suffix := range []string{"EphemeralResource", "DataSource", "Resource", "Action"} {
+		if name, found := strings.CutSuffix(structName, suffix); found && name != "" {
+			return DefaultProviderName + "_" + toSnakeCase(name)
+		}
+	}
+	return ""
+}
+
+// findStringConstant returns the value of a package level string constant
+func findStringConstant(packageInfo *gophon.PackageInfo, name string) string {
+	if packageInfo == nil {
+		return ""
+	}
+	for _, fileInfo := range packageInfo.Files {
+		if fileInfo.File == nil {
+			continue
+		}
+		for _, decl := range fileInfo.File.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				valueSpec, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				for i, ident := range valueSpec.Names {
+					if ident.Name != name || i >= len(valueSpec.Values) {
+						continue
+					}
+					return stringLiteralValue(valueSpec.Values[i])
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// toSnakeCase converts a CamelCase name to snake_case, keeping acronyms together, e.g. "MsSqlVMGroup" -> "ms_sql_vm_group"
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var builder strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			previousLower := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			acronymEnd := i > 0 && unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if previousLower || acronymEnd {
+				builder.WriteRune('_')
+			}
+		}
+		builder.WriteRune(unicode.ToLower(r))
+	}
+	return builder.String()
+}
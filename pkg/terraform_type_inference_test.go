@@ -0,0 +1,86 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const terraformTypeInferenceSource = `package example
+
+const storageMoverResourceType = "azurerm_storage_mover"
+
+type LiteralResource struct{}
+
+func (r LiteralResource) ResourceType() string {
+	return "azurerm_literal_widget"
+}
+
+type StorageMoverResource struct{}
+
+func (r StorageMoverResource) ResourceType() string {
+	return storageMoverResourceType
+}
+
+type KeyVaultSecretEphemeralResource struct{}
+
+func (e *KeyVaultSecretEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_key_vault_secret"
+}
+
+type MsSqlVMGroupDataSource struct{}
+`
+
+func TestTerraformTypeResolver_DefaultStrategies(t *testing.T) {
+	packageInfo := parsePackageInfo(t, terraformTypeInferenceSource)
+	winners := make(map[string]string)
+
+	terraformTypes := newTerraformTypeResolver(nil).resolve(packageInfo, []string{
+		"LiteralResource",
+		"StorageMoverResource",
+		"KeyVaultSecretEphemeralResource",
+		"MsSqlVMGroupDataSource",
+		"Unconventional",
+	}, winners)
+
+	assert.Equal(t, map[string]string{
+		"LiteralResource":                 "azurerm_literal_widget",
+		"StorageMoverResource":            "azurerm_storage_mover",
+		"KeyVaultSecretEphemeralResource": "azurerm_key_vault_secret",
+		"MsSqlVMGroupDataSource":          "azurerm_ms_sql_vm_group",
+	}, terraformTypes)
+	assert.Equal(t, map[string]string{
+		"LiteralResource":                 TerraformTypeStrategyResourceTypeLiteral,
+		"StorageMoverResource":            TerraformTypeStrategyResourceTypeConstant,
+		"KeyVaultSecretEphemeralResource": TerraformTypeStrategyMetadata,
+		"MsSqlVMGroupDataSource":          TerraformTypeStrategyNamingConvention,
+	}, winners)
+}
+
+func TestTerraformTypeResolver_ConfiguredOrder(t *testing.T) {
+	packageInfo := parsePackageInfo(t, terraformTypeInferenceSource)
+	strategies, err := TerraformTypeStrategiesByName([]string{"naming_convention", " resource_type_literal"})
+	require.NoError(t, err)
+	winners := make(map[string]string)
+
+	terraformTypes := newTerraformTypeResolver(strategies).resolve(packageInfo, []string{"LiteralResource"}, winners)
+
+	assert.Equal(t, map[string]string{"LiteralResource": "azurerm_literal"}, terraformTypes)
+	assert.Equal(t, map[string]string{"LiteralResource": TerraformTypeStrategyNamingConvention}, winners)
+}
+
+func TestTerraformTypeStrategiesByName_Unknown(t *testing.T) {
+	_, err := TerraformTypeStrategiesByName([]string{"metadata", "guess"})
+	assert.ErrorContains(t, err, `unknown terraform type strategy "guess"`)
+
+	_, err = TerraformTypeStrategiesByName(nil)
+	assert.Error(t, err)
+}
+
+func TestToSnakeCase(t *testing.T) {
+	assert.Equal(t, "key_vault", toSnakeCase("KeyVault"))
+	assert.Equal(t, "ms_sql_vm_group", toSnakeCase("MsSqlVMGroup"))
+	assert.Equal(t, "api_management", toSnakeCase("APIManagement"))
+	assert.Equal(t, "storage_v2_account", toSnakeCase("StorageV2Account"))
+}
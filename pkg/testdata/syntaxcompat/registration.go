@@ -0,0 +1,22 @@
+package syntaxcompat
+
+// Registration mixes legacy and typed resources implemented with generics
+type Registration struct{}
+
+func (r Registration) SupportedResources() map[string]*pluginsdk.Resource {
+	return map[string]*pluginsdk.Resource{
+		"azurerm_generic_legacy": resourceGenericLegacy[string](),
+	}
+}
+
+func (r Registration) Resources() []sdk.Resource {
+	return []sdk.Resource{
+		GenericResource[WidgetModel]{},
+		PlainResource{},
+	}
+}
+
+// Read is a package level function sharing its name with methods, it must not be mistaken for one of them
+func Read[T any](input T) T {
+	return input
+}
@@ -0,0 +1,96 @@
+package syntaxcompat
+
+import (
+	"iter"
+
+	"github.com/hashicorp/go-azure-sdk/resource-manager/widgets/2024-01-01/widgets"
+)
+
+type WidgetModel struct {
+	Name string `tfschema:"name"`
+}
+
+type GenericResource[T any] struct{}
+
+func (r GenericResource[T]) ResourceType() string {
+	return "azurerm_generic_widget"
+}
+
+func (r GenericResource[T]) Arguments() map[string]*pluginsdk.Schema {
+	return schemaFor[T]()
+}
+
+func (r GenericResource[T]) Attributes() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{}
+}
+
+func (r *GenericResource[T]) Read() sdk.ResourceFunc {
+	return sdk.ResourceFunc{
+		Func: func(ctx context.Context, metadata sdk.ResourceMetaData) error {
+			id, err := widgets.ParseWidgetID(metadata.ResourceData.Id())
+			if err != nil {
+				return err
+			}
+			for item := range each[any](metadata.Items) {
+				_ = item
+			}
+			return nil
+		},
+	}
+}
+
+func (r GenericResource[T]) DeprecationMessage() string {
+	return "use azurerm_widget instead"
+}
+
+type PlainResource struct{}
+
+func (r PlainResource) ResourceType() string {
+	return "azurerm_plain_widget"
+}
+
+func schemaFor[T any]() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{
+		"name": {
+			Type:         pluginsdk.TypeString,
+			Required:     true,
+			ValidateFunc: validation.All(validate.NameOf[T], validation.StringIsNotEmpty),
+		},
+		"tags": commonschema.TagsOf[map[string]any](),
+	}
+}
+
+func each[T any](items []T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, item := range items {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+func resourceGenericLegacy[K comparable]() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Create: resourceGenericLegacyCreate[K],
+		Read:   resourceGenericLegacyRead,
+
+		Schema: map[string]*pluginsdk.Schema{
+			"count": {
+				Type:         pluginsdk.TypeInt,
+				ValidateFunc: validation.IntBetween[int](1, 10),
+			},
+		},
+	}
+}
+
+func resourceGenericLegacyCreate[K comparable](d *pluginsdk.ResourceData, meta any) error {
+	for i := range 3 {
+		_ = i
+	}
+	return nil
+}
+
+func resourceGenericLegacyRead(d *pluginsdk.ResourceData, meta any) error {
+	return nil
+}
@@ -123,3 +123,30 @@ func (r Registration) Resources() []resource.Resource {
 		VirtualMachineScaleSetResource{},
 	}
 }
+
+// Dummy structs for list resources and actions
+type VirtualMachineListResource struct{}
+
+func (v VirtualMachineListResource) Metadata(ctx context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
+	response.TypeName = "azurerm_virtual_machine"
+}
+
+type VirtualMachinePowerAction struct{}
+
+func NewVirtualMachinePowerAction() interface{} {
+	return &VirtualMachinePowerAction{}
+}
+
+// ListResources returns a list of List Resources supported by this Service
+func (r Registration) ListResources() []interface{} {
+	return []interface{}{
+		VirtualMachineListResource{},
+	}
+}
+
+// Actions returns a list of Actions supported by this Service
+func (r Registration) Actions() []func() interface{} {
+	return []func() interface{}{
+		NewVirtualMachinePowerAction,
+	}
+}
@@ -61,6 +61,23 @@ func (k KeyVaultCertificateContactsResource) Delete(ctx context.Context, request
 	panic("implement me")
 }
 
+// Name is the name of this Service
+func (r Registration) Name() string {
+	return "KeyVault"
+}
+
+// AssociatedGitHubLabel is the label used for issues and pull requests of this Service
+func (r Registration) AssociatedGitHubLabel() string {
+	return "service/key-vault"
+}
+
+// WebsiteCategories returns a list of categories which can be used for the sidebar
+func (r Registration) WebsiteCategories() []string {
+	return []string{
+		"Key Vault",
+	}
+}
+
 // SupportedResources returns the supported Resources supported by this Service
 func (r Registration) SupportedResources() map[string]*pluginsdk.Resource {
 	return map[string]*pluginsdk.Resource{
@@ -0,0 +1,132 @@
+package pkg
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// timeoutOperations maps the CRUD fields of pluginsdk.ResourceTimeout and the CRUD methods of typed resources to the
+// operation names used in the index
+var timeoutOperations = map[string]string{
+	"Create": "create",
+	"Read":   "read",
+	"Update": "update",
+	"Delete": "delete",
+}
+
+// durationUnitMinutes are the time package units a timeout can be expressed in, in minutes
+var durationUnitMinutes = map[string]float64{
+	"Second": 1.0 / 60,
+	"Minute": 1,
+	"Hour":   60,
+}
+
+// extractLegacyTimeoutsFromPackage extracts the Timeouts field of the pluginsdk.Resource returned by a legacy
+// registration function, for example Create: pluginsdk.DefaultTimeout(30 * time.Minute) results in {"create": 30}
+func extractLegacyTimeoutsFromPackage(registrationMethod string, packageInfo *gophon.PackageInfo) map[string]int {
+	for _, compLit := range findResourceLiterals(findFunctionDecl(packageInfo, registrationMethod)) {
+		timeouts := compositeLitField(compLit, "Timeouts")
+		if timeouts == nil {
+			continue
+		}
+		if unaryExpr, ok := timeouts.(*ast.UnaryExpr); ok && unaryExpr.Op == token.AND {
+			timeouts = unaryExpr.X
+		}
+		timeoutLit, ok := timeouts.(*ast.CompositeLit)
+		if !ok {
+			return nil
+		}
+
+		result := make(map[string]int)
+		for field, operation := range timeoutOperations {
+			if minutes, ok := timeoutMinutes(compositeLitField(timeoutLit, field)); ok {
+				result[operation] = minutes
+			}
+		}
+		if len(result) == 0 {
+			return nil
+		}
+		return result
+	}
+	return nil
+}
+
+// extractTypedTimeoutsFromPackage extracts the Timeout field of the sdk.ResourceFunc returned by each CRUD method of a typed resource
+func extractTypedTimeoutsFromPackage(structName string, packageInfo *gophon.PackageInfo) map[string]int {
+	result := make(map[string]int)
+	for method, operation := range timeoutOperations {
+		resourceFunc, ok := firstReturnedExpr(findMethodDecl(packageInfo, structName, method)).(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		if minutes, ok := timeoutMinutes(compositeLitField(resourceFunc, "Timeout")); ok {
+			result[operation] = minutes
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// timeoutMinutes evaluates a constant duration expression such as 30 * time.Minute, time.Hour or
+// pluginsdk.DefaultTimeout(90 * time.Minute) in whole minutes
+func timeoutMinutes(expr ast.Expr) (int, bool) {
+	minutes, ok := durationMinutes(expr)
+	if !ok || !hasDurationUnit(expr) {
+		return 0, false
+	}
+	return int(minutes + 0.5), true
+}
+
+// hasDurationUnit reports whether an expression refers to a time unit, a bare integer duration is in nanoseconds
+func hasDurationUnit(expr ast.Expr) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if selector, ok := n.(*ast.SelectorExpr); ok {
+			if pkgIdent, ok := selector.X.(*ast.Ident); ok && pkgIdent.Name == "time" && durationUnitMinutes[selector.Sel.Name] != 0 {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// durationMinutes evaluates products of integer literals and time units in minutes
+func durationMinutes(expr ast.Expr) (float64, bool) {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return durationMinutes(e.X)
+	case *ast.CallExpr:
+		// pluginsdk.DefaultTimeout(30 * time.Minute) and time.Duration(30) * time.Minute conversions
+		if len(e.Args) == 1 {
+			return durationMinutes(e.Args[0])
+		}
+	case *ast.SelectorExpr:
+		if pkgIdent, ok := e.X.(*ast.Ident); ok && pkgIdent.Name == "time" {
+			minutes, exists := durationUnitMinutes[e.Sel.Name]
+			return minutes, exists
+		}
+	case *ast.BasicLit:
+		if e.Kind == token.INT {
+			value, err := strconv.Atoi(strings.ReplaceAll(e.Value, "_", ""))
+			return float64(value), err == nil
+		}
+	case *ast.BinaryExpr:
+		if e.Op != token.MUL {
+			return 0, false
+		}
+		x, ok := durationMinutes(e.X)
+		if !ok {
+			return 0, false
+		}
+		y, ok := durationMinutes(e.Y)
+		return x * y, ok
+	}
+	return 0, false
+}
@@ -0,0 +1,63 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractLegacyTimeoutsFromPackage(t *testing.T) {
+	source := `package keyvault
+
+func resourceKeyVault() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Timeouts: &pluginsdk.ResourceTimeout{
+			Create: pluginsdk.DefaultTimeout(30 * time.Minute),
+			Read:   pluginsdk.DefaultTimeout(5 * time.Minute),
+			Update: pluginsdk.DefaultTimeout(time.Hour),
+			Delete: pluginsdk.DefaultTimeout(90 * time.Second),
+		},
+	}
+}
+
+func resourceKeyVaultKey() *pluginsdk.Resource {
+	return &pluginsdk.Resource{}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+
+	assert.Equal(t, map[string]int{"create": 30, "read": 5, "update": 60, "delete": 2}, extractLegacyTimeoutsFromPackage("resourceKeyVault", packageInfo))
+	assert.Nil(t, extractLegacyTimeoutsFromPackage("resourceKeyVaultKey", packageInfo))
+}
+
+func TestExtractTypedTimeoutsFromPackage(t *testing.T) {
+	source := `package containerapps
+
+type ContainerAppResource struct{}
+
+func (r ContainerAppResource) Create() sdk.ResourceFunc {
+	return sdk.ResourceFunc{
+		Timeout: 2 * time.Hour,
+		Func: func(ctx context.Context, metadata sdk.ResourceMetaData) error {
+			return nil
+		},
+	}
+}
+
+func (r ContainerAppResource) Read() sdk.ResourceFunc {
+	return sdk.ResourceFunc{
+		Timeout: time.Minute * 5,
+	}
+}
+
+func (r ContainerAppResource) Delete() sdk.ResourceFunc {
+	return sdk.ResourceFunc{
+		Timeout: defaultTimeout,
+	}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+
+	assert.Equal(t, map[string]int{"create": 120, "read": 5}, extractTypedTimeoutsFromPackage("ContainerAppResource", packageInfo))
+	assert.Nil(t, extractTypedTimeoutsFromPackage("UnknownResource", packageInfo))
+}
@@ -0,0 +1,74 @@
+package pkg
+
+import (
+	"fmt"
+	"go/build"
+	"go/version"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// ToolchainInfo records the Go environment of an indexing run, parse results can differ across Go versions
+// when new syntax appears in the provider source
+type ToolchainInfo struct {
+	GoVersion string   `json:"go_version" protobuf:"1"`           // "go1.24.5", the Go version the indexer was built with
+	GOOS      string   `json:"goos" protobuf:"2"`                 // "linux"
+	GOARCH    string   `json:"goarch" protobuf:"3"`               // "amd64"
+	BuildTags []string `json:"build_tags,omitempty" protobuf:"4"` // ["integration"], from the build context and -tags in GOFLAGS
+}
+
+// currentToolchainInfo returns the toolchain information of the running process
+func currentToolchainInfo() ToolchainInfo {
+	return ToolchainInfo{
+		GoVersion: runtime.Version(),
+		GOOS:      build.Default.GOOS,
+		GOARCH:    build.Default.GOARCH,
+		BuildTags: append(append([]string{}, build.Default.BuildTags...), goFlagsBuildTags(os.Getenv("GOFLAGS"))...),
+	}
+}
+
+// goFlagsBuildTags extracts the build tags passed through GOFLAGS, e.g. "-mod=mod -tags=foo,bar" results in ["foo", "bar"]
+func goFlagsBuildTags(goFlags string) []string {
+	var tags []string
+	fields := strings.Fields(goFlags)
+	for i, field := range fields {
+		var value string
+		switch {
+		case strings.HasPrefix(field, "-tags="), strings.HasPrefix(field, "--tags="):
+			value = field[strings.Index(field, "=")+1:]
+		case (field == "-tags" || field == "--tags") && i+1 < len(fields):
+			value = fields[i+1]
+		default:
+			continue
+		}
+		for _, tag := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' }) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// CheckGoVersionSupported fails fast when the provider source requires a newer Go version than the indexer was built
+// with, since go/parser would reject or mis-parse syntax it doesn't know about. required is a Go version such as "1.24" or "go1.24.5".
+func CheckGoVersionSupported(required string) error {
+	return checkGoVersionSupported(required, runtime.Version())
+}
+
+func checkGoVersionSupported(required, toolchain string) error {
+	if !strings.HasPrefix(required, "go") {
+		required = "go" + required
+	}
+	if !version.IsValid(required) {
+		return fmt.Errorf("invalid Go version %q", strings.TrimPrefix(required, "go"))
+	}
+	// Development toolchains can't be compared, assume they are recent enough
+	if !version.IsValid(toolchain) {
+		return nil
+	}
+	if version.Compare(version.Lang(toolchain), version.Lang(required)) < 0 {
+		return fmt.Errorf("parsing Go %s source is not supported by an indexer built with %s, rebuild the indexer with Go %s or newer",
+			strings.TrimPrefix(required, "go"), toolchain, strings.TrimPrefix(version.Lang(required), "go"))
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package pkg
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoFlagsBuildTags(t *testing.T) {
+	assert.Nil(t, goFlagsBuildTags(""))
+	assert.Nil(t, goFlagsBuildTags("-mod=mod"))
+	assert.Equal(t, []string{"foo", "bar"}, goFlagsBuildTags("-mod=mod -tags=foo,bar"))
+	assert.Equal(t, []string{"foo"}, goFlagsBuildTags("-tags foo"))
+}
+
+func TestCurrentToolchainInfo(t *testing.T) {
+	t.Setenv("GOFLAGS", "-tags=integration")
+
+	info := currentToolchainInfo()
+
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+	assert.NotEmpty(t, info.GOOS)
+	assert.NotEmpty(t, info.GOARCH)
+	assert.Contains(t, info.BuildTags, "integration")
+}
+
+func TestCheckGoVersionSupported(t *testing.T) {
+	assert.NoError(t, checkGoVersionSupported("1.22", "go1.24.5"))
+	assert.NoError(t, checkGoVersionSupported("go1.24.9", "go1.24.5"))
+	assert.NoError(t, checkGoVersionSupported("1.30", "devel go1.30-abcdef"))
+	assert.ErrorContains(t, checkGoVersionSupported("1.25", "go1.24.5"), "rebuild the indexer with Go 1.25 or newer")
+	assert.ErrorContains(t, checkGoVersionSupported("latest", "go1.24.5"), `invalid Go version "latest"`)
+	assert.NoError(t, CheckGoVersionSupported(strings.TrimPrefix(runtime.Version(), "go")))
+}
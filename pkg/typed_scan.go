@@ -0,0 +1,280 @@
+package pkg
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// typedRegistrations are the registrations of a service package resolved with type information. Types identify the
+// registered structs and functions whatever name their package is imported under, including dot-imports, and
+// through local variables the AST extraction can't follow.
+type typedRegistrations struct {
+	SupportedResources    map[string]string
+	SupportedDataSources  map[string]string
+	Resources             []string
+	DataSources           []string
+	EphemeralFunctions    []string
+	ListResources         []string
+	ListResourceFunctions []string
+	Actions               []string
+	ActionFunctions       []string
+}
+
+// typedPackageLoadMode loads the syntax and type information of the service package and the types of its dependencies
+const typedPackageLoadMode = packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedSyntax |
+	packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps
+
+// loadTypedRegistrations type-checks the package in servicePath with go/packages and resolves its registrations,
+// which needs the module of the provider and its dependencies to be available to the go command. Registrations of
+// files left out by the exclusions are ignored, the files of the package are selected by the build constraints.
+func loadTypedRegistrations(servicePath string, constraints BuildConstraints, exclusions FileExclusions) (*typedRegistrations, error) {
+	config := &packages.Config{Mode: typedPackageLoadMode, Dir: servicePath}
+	if !constraints.IsZero() {
+		config.Env = constraints.environment()
+	}
+	loaded, err := packages.Load(config, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load typed package: %w", err)
+	}
+	if len(loaded) != 1 {
+		return nil, fmt.Errorf("expected one typed package in %s, found %d", servicePath, len(loaded))
+	}
+	typedPackage := loaded[0]
+	if len(typedPackage.Errors) > 0 {
+		return nil, fmt.Errorf("failed to type-check package: %v", typedPackage.Errors[0])
+	}
+	var files []*ast.File
+	for _, file := range typedPackage.Syntax {
+		if !exclusions.excludes(typedPackage.Fset.Position(file.Pos()).Filename) {
+			files = append(files, file)
+		}
+	}
+	return extractTypedRegistrations(typedPackage.Types, typedPackage.TypesInfo, files), nil
+}
+
+// extractTypedRegistrations resolves the values added to the maps and slices returned by the registration methods
+// of a type-checked package
+func extractTypedRegistrations(typesPackage *types.Package, info *types.Info, files []*ast.File) *typedRegistrations {
+	registrations := &typedRegistrations{
+		SupportedResources:   make(map[string]string),
+		SupportedDataSources: make(map[string]string),
+	}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || fn.Body == nil || !registrationMethodNames[fn.Name.Name] {
+				continue
+			}
+			resolver := typedRegistrationResolver{typesPackage: typesPackage, info: info}
+			if !resolver.setResultType(fn) {
+				continue
+			}
+			switch fn.Name.Name {
+			case "SupportedResources":
+				registrations.SupportedResources = mergeMap(registrations.SupportedResources, resolver.mappings(fn.Body))
+			case "SupportedDataSources":
+				registrations.SupportedDataSources = mergeMap(registrations.SupportedDataSources, resolver.mappings(fn.Body))
+			case "Resources":
+				registrations.Resources = append(registrations.Resources, resolver.structTypes(fn.Body)...)
+			case "DataSources":
+				registrations.DataSources = append(registrations.DataSources, resolver.structTypes(fn.Body)...)
+			case "EphemeralResources":
+				registrations.EphemeralFunctions = append(registrations.EphemeralFunctions, resolver.functions(fn.Body)...)
+			case "ListResources":
+				registrations.ListResources = append(registrations.ListResources, resolver.structTypes(fn.Body)...)
+				registrations.ListResourceFunctions = append(registrations.ListResourceFunctions, resolver.functions(fn.Body)...)
+			case "Actions":
+				registrations.Actions = append(registrations.Actions, resolver.structTypes(fn.Body)...)
+				registrations.ActionFunctions = append(registrations.ActionFunctions, resolver.functions(fn.Body)...)
+			}
+		}
+	}
+	return registrations
+}
+
+// typedRegistrationResolver resolves the values a registration method adds to the map or slice it returns, any
+// literal, append or index assignment of the result type counts, whichever variables hold it
+type typedRegistrationResolver struct {
+	typesPackage *types.Package
+	info         *types.Info
+	resultType   types.Type
+}
+
+// setResultType records the type of the single result of the registration method, false when it has another
+// signature
+func (r *typedRegistrationResolver) setResultType(fn *ast.FuncDecl) bool {
+	object, ok := r.info.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return false
+	}
+	signature, ok := object.Type().(*types.Signature)
+	if !ok || signature.Results().Len() != 1 {
+		return false
+	}
+	r.resultType = signature.Results().At(0).Type()
+	return true
+}
+
+// isResult reports whether an expression has the result type of the registration method
+func (r *typedRegistrationResolver) isResult(expr ast.Expr) bool {
+	exprType := r.info.TypeOf(expr)
+	return exprType != nil && types.Identical(exprType, r.resultType)
+}
+
+// elements returns the expressions added to slices of the result type by literals and appends in body
+func (r *typedRegistrationResolver) elements(body *ast.BlockStmt) []ast.Expr {
+	var elements []ast.Expr
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CompositeLit:
+			if r.isResult(e) {
+				elements = append(elements, e.Elts...)
+			}
+		case *ast.CallExpr:
+			if builtin, ok := r.info.Uses[calledIdent(e)].(*types.Builtin); ok && builtin.Name() == "append" && r.isResult(e) && !e.Ellipsis.IsValid() {
+				elements = append(elements, e.Args[1:]...)
+			}
+		}
+		return true
+	})
+	return elements
+}
+
+// structTypes resolves the struct types of the slice elements, pointers and values of a local variable included
+func (r *typedRegistrationResolver) structTypes(body *ast.BlockStmt) []string {
+	var structTypes []string
+	for _, element := range r.elements(body) {
+		elementType := r.info.TypeOf(element)
+		if pointer, ok := elementType.(*types.Pointer); ok {
+			elementType = pointer.Elem()
+		}
+		named, ok := elementType.(*types.Named)
+		if !ok || named.Obj().Pkg() != r.typesPackage {
+			continue
+		}
+		if _, ok := named.Underlying().(*types.Struct); ok {
+			structTypes = append(structTypes, named.Obj().Name())
+		}
+	}
+	return structTypes
+}
+
+// functions resolves the package functions of the slice elements, such as constructors of ephemeral resources
+func (r *typedRegistrationResolver) functions(body *ast.BlockStmt) []string {
+	var functions []string
+	for _, element := range r.elements(body) {
+		if function := r.packageFunction(element); function != "" {
+			functions = append(functions, function)
+		}
+	}
+	return functions
+}
+
+// mappings resolves the Terraform types and registration functions of the map entries, constant keys included
+func (r *typedRegistrationResolver) mappings(body *ast.BlockStmt) map[string]string {
+	mappings := make(map[string]string)
+	add := func(key, value ast.Expr) {
+		call, ok := value.(*ast.CallExpr)
+		if !ok {
+			return
+		}
+		keyValue := r.info.Types[key].Value
+		if keyValue == nil || keyValue.Kind() != constant.String {
+			return
+		}
+		if function := r.packageFunction(call.Fun); function != "" {
+			mappings[constant.StringVal(keyValue)] = function
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CompositeLit:
+			if r.isResult(e) {
+				for _, elt := range e.Elts {
+					if kv, ok := elt.(*ast.KeyValueExpr); ok {
+						add(kv.Key, kv.Value)
+					}
+				}
+			}
+		case *ast.AssignStmt:
+			for i, lhs := range e.Lhs {
+				if index, ok := lhs.(*ast.IndexExpr); ok && i < len(e.Rhs) && r.isResult(index.X) {
+					add(index.Index, e.Rhs[i])
+				}
+			}
+		}
+		return true
+	})
+	return mappings
+}
+
+// packageFunction returns the name of the function of the scanned package an expression refers to, "" otherwise
+func (r *typedRegistrationResolver) packageFunction(expr ast.Expr) string {
+	var ident *ast.Ident
+	switch e := unwrapTypeArguments(expr).(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	default:
+		return ""
+	}
+	function, ok := r.info.Uses[ident].(*types.Func)
+	if !ok || function.Pkg() != r.typesPackage {
+		return ""
+	}
+	if signature, ok := function.Type().(*types.Signature); ok && signature.Recv() != nil {
+		return ""
+	}
+	return function.Name()
+}
+
+// calledIdent returns the identifier of the function a call invokes by name, nil for other calls
+func calledIdent(call *ast.CallExpr) *ast.Ident {
+	ident, _ := call.Fun.(*ast.Ident)
+	return ident
+}
+
+// mergeTypedRegistrations adds the registrations resolved with type information the AST extraction missed
+func (s *ServiceRegistration) mergeTypedRegistrations(registrations *typedRegistrations) {
+	for terraformType, registrationMethod := range registrations.SupportedResources {
+		if _, exists := s.SupportedResources[terraformType]; !exists {
+			s.SupportedResources[terraformType] = registrationMethod
+		}
+	}
+	for terraformType, registrationMethod := range registrations.SupportedDataSources {
+		if _, exists := s.SupportedDataSources[terraformType]; !exists {
+			s.SupportedDataSources[terraformType] = registrationMethod
+		}
+	}
+	s.Resources = appendMissing(s.Resources, registrations.Resources)
+	s.DataSources = appendMissing(s.DataSources, registrations.DataSources)
+	s.EphemeralFunctions = appendMissing(s.EphemeralFunctions, registrations.EphemeralFunctions)
+	s.ListResources = appendMissing(s.ListResources, registrations.ListResources)
+	s.ListResourceFunctions = appendMissing(s.ListResourceFunctions, registrations.ListResourceFunctions)
+	s.Actions = appendMissing(s.Actions, registrations.Actions)
+	s.ActionFunctions = appendMissing(s.ActionFunctions, registrations.ActionFunctions)
+}
+
+// appendMissing appends the values not in the slice yet
+func appendMissing(values, additions []string) []string {
+	present := make(map[string]bool, len(values))
+	for _, value := range values {
+		present[value] = true
+	}
+	for _, addition := range additions {
+		if !present[addition] {
+			present[addition] = true
+			values = append(values, addition)
+		}
+	}
+	return values
+}
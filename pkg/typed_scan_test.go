@@ -0,0 +1,141 @@
+package pkg
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const typedRegistrationSource = `package widgets
+
+type Resource interface{ ResourceType() string }
+
+type Resource2 = Resource
+
+type WidgetResource struct{}
+
+func (WidgetResource) ResourceType() string { return "azurerm_widget" }
+
+type GadgetResource struct{}
+
+func (*GadgetResource) ResourceType() string { return "azurerm_gadget" }
+
+type SprocketResource struct{}
+
+func (SprocketResource) ResourceType() string { return "azurerm_sprocket" }
+
+type Ephemeral interface{}
+
+func NewSecretEphemeral() Ephemeral { return nil }
+
+type Legacy struct{}
+
+func resourceLegacyWidget() *Legacy { return nil }
+func resourceLegacyGadget() *Legacy { return nil }
+
+const legacyGadget = "azurerm_legacy_gadget"
+
+type Registration struct{}
+
+func (r Registration) SupportedResources() map[string]*Legacy {
+	resources := map[string]*Legacy{
+		"azurerm_legacy_widget": resourceLegacyWidget(),
+	}
+	resources[legacyGadget] = resourceLegacyGadget()
+	return resources
+}
+
+func (r Registration) Resources() []Resource2 {
+	widget := WidgetResource{}
+	resources := []Resource{widget, &GadgetResource{}}
+	return append(resources, newSprocket())
+}
+
+func newSprocket() SprocketResource { return SprocketResource{} }
+
+func (r Registration) EphemeralResources() []func() Ephemeral {
+	constructor := NewSecretEphemeral
+	return []func() Ephemeral{NewSecretEphemeral, constructor}
+}
+`
+
+func typeCheck(t *testing.T, source string) (*types.Package, *types.Info, []*ast.File) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "registration.go", source, parser.ParseComments)
+	require.NoError(t, err)
+
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+	typesPackage, err := (&types.Config{Importer: importer.Default()}).Check("widgets", fset, []*ast.File{file}, info)
+	require.NoError(t, err)
+	return typesPackage, info, []*ast.File{file}
+}
+
+func TestExtractTypedRegistrations(t *testing.T) {
+	registrations := extractTypedRegistrations(typeCheck(t, typedRegistrationSource))
+
+	assert.Equal(t, map[string]string{
+		"azurerm_legacy_widget": "resourceLegacyWidget",
+		"azurerm_legacy_gadget": "resourceLegacyGadget",
+	}, registrations.SupportedResources)
+	assert.Equal(t, []string{"WidgetResource", "GadgetResource", "SprocketResource"}, registrations.Resources)
+	assert.Equal(t, []string{"NewSecretEphemeral"}, registrations.EphemeralFunctions)
+	assert.Empty(t, registrations.DataSources)
+}
+
+func TestExtractTypedRegistrations_ASTMisses(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "registration.go", typedRegistrationSource, parser.ParseComments)
+	require.NoError(t, err)
+
+	// Pointers, local variables and constant keys are beyond the AST extraction
+	assert.Equal(t, map[string]string{"azurerm_legacy_widget": "resourceLegacyWidget"}, extractSupportedResourcesMappings(file))
+	assert.Empty(t, extractResourcesStructTypes(file))
+}
+
+func TestServiceRegistration_MergeTypedRegistrations(t *testing.T) {
+	serviceReg := ServiceRegistration{
+		SupportedResources:   map[string]string{"azurerm_legacy_widget": "resourceLegacyWidget"},
+		SupportedDataSources: map[string]string{},
+		Resources:            []string{"WidgetResource"},
+	}
+	serviceReg.mergeTypedRegistrations(&typedRegistrations{
+		SupportedResources: map[string]string{"azurerm_legacy_widget": "other", "azurerm_legacy_gadget": "resourceLegacyGadget"},
+		Resources:          []string{"WidgetResource", "GadgetResource"},
+		EphemeralFunctions: []string{"NewSecretEphemeral"},
+	})
+
+	assert.Equal(t, map[string]string{"azurerm_legacy_widget": "resourceLegacyWidget", "azurerm_legacy_gadget": "resourceLegacyGadget"}, serviceReg.SupportedResources)
+	assert.Equal(t, []string{"WidgetResource", "GadgetResource"}, serviceReg.Resources)
+	assert.Equal(t, []string{"NewSecretEphemeral"}, serviceReg.EphemeralFunctions)
+}
+
+func TestScanner_Typed(t *testing.T) {
+	servicesDir := filepath.Join("testharness", "internal", "services")
+	basePkgUrl := "github.com/lonegunmanb/terraform-provider-azurerm-index"
+
+	untyped, err := Scanner{Services: []string{"keyvault"}}.Scan(servicesDir, basePkgUrl, "test-version", nil)
+	require.NoError(t, err)
+	typed, err := Scanner{Services: []string{"keyvault"}, Typed: true}.Scan(servicesDir, basePkgUrl, "test-version", nil)
+	require.NoError(t, err)
+
+	require.Len(t, typed.Services, 1)
+	for _, warning := range typed.Warnings {
+		assert.NotEqual(t, ScanWarningParseError, warning.Kind, warning.Message)
+	}
+	// The harness registers everything with plain literals, the type-checked scan finds the same registrations
+	assert.Equal(t, untyped.Services[0].SupportedResources, typed.Services[0].SupportedResources)
+	assert.Equal(t, untyped.Services[0].Resources, typed.Services[0].Resources)
+	assert.Equal(t, untyped.Services[0].DataSources, typed.Services[0].DataSources)
+	assert.Equal(t, untyped.Services[0].EphemeralFunctions, typed.Services[0].EphemeralFunctions)
+}
@@ -0,0 +1,73 @@
+package pkg
+
+import (
+	"go/ast"
+	"go/token"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// Capabilities describing how a resource handles updates, drift remediation can't rely on in-place updates for them
+const (
+	CapabilityUpdateReusesCreate = "update_reuses_create" // The Update function is the Create function
+	CapabilityUpdateUnsupported  = "update_unsupported"   // No Update function, changes force a replacement or are ignored
+)
+
+// extractLegacyUpdateCapabilityFromPackage detects legacy resources whose Update points at the Create function, and
+// resources without Update although their schema has optional attributes that don't force a new resource
+func extractLegacyUpdateCapabilityFromPackage(registrationMethod string, crudMethods *LegacyResourceCRUDFunctions, packageInfo *gophon.PackageInfo) string {
+	if crudMethods == nil {
+		return ""
+	}
+	if crudMethods.UpdateMethod != "" {
+		if crudMethods.UpdateMethod == crudMethods.CreateMethod {
+			return CapabilityUpdateReusesCreate
+		}
+		return ""
+	}
+	for _, entry := range legacyResourceSchemaEntries(registrationMethod, packageInfo) {
+		if isUpdatableOptionalAttribute(entry.value) {
+			return CapabilityUpdateUnsupported
+		}
+	}
+	return ""
+}
+
+// extractTypedUpdateCapabilityFromPackage detects typed resources not implementing sdk.ResourceWithUpdate
+func extractTypedUpdateCapabilityFromPackage(structName string, packageInfo *gophon.PackageInfo) string {
+	if findMethodDecl(packageInfo, structName, "Update") == nil {
+		return CapabilityUpdateUnsupported
+	}
+	return ""
+}
+
+// isLegacyResourceImmutable reports whether a legacy resource registers no Update function, so every change replaces
+// the resource
+func isLegacyResourceImmutable(crudMethods *LegacyResourceCRUDFunctions) bool {
+	return crudMethods != nil && crudMethods.UpdateMethod == ""
+}
+
+// isTypedResourceImmutable reports whether a typed resource doesn't implement sdk.ResourceWithUpdate, so every change
+// replaces the resource
+func isTypedResourceImmutable(structName string, packageInfo *gophon.PackageInfo) bool {
+	return findMethodDecl(packageInfo, structName, "Update") == nil
+}
+
+// isUpdatableOptionalAttribute reports whether a schema literal declares an Optional attribute without ForceNew.
+// Attributes built by helper functions are unknown and don't count.
+func isUpdatableOptionalAttribute(value ast.Expr) bool {
+	if unaryExpr, ok := value.(*ast.UnaryExpr); ok && unaryExpr.Op == token.AND {
+		value = unaryExpr.X
+	}
+	compLit, ok := value.(*ast.CompositeLit)
+	if !ok {
+		return false
+	}
+	return isTrueLiteral(compositeLitField(compLit, "Optional")) && !isTrueLiteral(compositeLitField(compLit, "ForceNew"))
+}
+
+// isTrueLiteral reports whether the expression is the true identifier
+func isTrueLiteral(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "true"
+}
@@ -0,0 +1,100 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractLegacyUpdateCapabilityFromPackage(t *testing.T) {
+	source := `package network
+
+func resourceNetworkProfile() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Create: resourceNetworkProfileCreateUpdate,
+		Update: resourceNetworkProfileCreateUpdate,
+	}
+}
+
+func resourceSubnetAssociation() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Schema: map[string]*pluginsdk.Schema{
+			"subnet_id": {
+				Type:     pluginsdk.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"description": {
+				Type:     pluginsdk.TypeString,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func resourceNetworkLock() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Schema: map[string]*pluginsdk.Schema{
+			"notes": {
+				Type:     pluginsdk.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"tags": commonschema.Tags(),
+		},
+	}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+
+	assert.Equal(t, CapabilityUpdateReusesCreate, extractLegacyUpdateCapabilityFromPackage("resourceNetworkProfile",
+		&LegacyResourceCRUDFunctions{CreateMethod: "resourceNetworkProfileCreateUpdate", UpdateMethod: "resourceNetworkProfileCreateUpdate"}, packageInfo))
+	assert.Equal(t, CapabilityUpdateUnsupported, extractLegacyUpdateCapabilityFromPackage("resourceSubnetAssociation",
+		&LegacyResourceCRUDFunctions{CreateMethod: "resourceSubnetAssociationCreate"}, packageInfo))
+	assert.Empty(t, extractLegacyUpdateCapabilityFromPackage("resourceNetworkLock",
+		&LegacyResourceCRUDFunctions{CreateMethod: "resourceNetworkLockCreate"}, packageInfo))
+	assert.Empty(t, extractLegacyUpdateCapabilityFromPackage("resourceNetworkProfile",
+		&LegacyResourceCRUDFunctions{CreateMethod: "resourceNetworkProfileCreate", UpdateMethod: "resourceNetworkProfileUpdate"}, packageInfo))
+	assert.Empty(t, extractLegacyUpdateCapabilityFromPackage("resourceNetworkProfile", nil, packageInfo))
+}
+
+func TestExtractTypedUpdateCapabilityFromPackage(t *testing.T) {
+	source := `package containerapps
+
+type ContainerAppResource struct{}
+
+func (r ContainerAppResource) Update() sdk.ResourceFunc {
+	return sdk.ResourceFunc{}
+}
+
+type ContainerAppEnvironmentCertificateResource struct{}
+
+func (r ContainerAppEnvironmentCertificateResource) Create() sdk.ResourceFunc {
+	return sdk.ResourceFunc{}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+
+	assert.Empty(t, extractTypedUpdateCapabilityFromPackage("ContainerAppResource", packageInfo))
+	assert.Equal(t, CapabilityUpdateUnsupported, extractTypedUpdateCapabilityFromPackage("ContainerAppEnvironmentCertificateResource", packageInfo))
+
+	assert.False(t, isTypedResourceImmutable("ContainerAppResource", packageInfo))
+	assert.True(t, isTypedResourceImmutable("ContainerAppEnvironmentCertificateResource", packageInfo))
+}
+
+func TestIsLegacyResourceImmutable(t *testing.T) {
+	assert.True(t, isLegacyResourceImmutable(&LegacyResourceCRUDFunctions{CreateMethod: "resourceNetworkLockCreate", DeleteMethod: "resourceNetworkLockDelete"}))
+	assert.False(t, isLegacyResourceImmutable(&LegacyResourceCRUDFunctions{CreateMethod: "resourceNetworkProfileCreateUpdate", UpdateMethod: "resourceNetworkProfileCreateUpdate"}))
+	// Resources whose CRUD functions couldn't be extracted are unknown
+	assert.False(t, isLegacyResourceImmutable(nil))
+}
+
+func TestNewTerraformResourceInfo_Immutable(t *testing.T) {
+	service := ServiceRegistration{
+		ServiceName:       "network",
+		ResourceImmutable: map[string]bool{"azurerm_network_lock": true},
+	}
+
+	assert.True(t, newTerraformResourceInfo("azurerm_network_lock", "", "resourceNetworkLock", "legacy_pluginsdk", service).Immutable)
+	assert.False(t, newTerraformResourceInfo("azurerm_network_profile", "", "resourceNetworkProfile", "legacy_pluginsdk", service).Immutable)
+}
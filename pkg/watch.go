@@ -0,0 +1,251 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultWatchInterval is how often the watch mode checks the service packages for changes
+const DefaultWatchInterval = time.Second
+
+// Watcher keeps an index directory up to date with a provider checkout during provider development. Only the service
+// packages with changed files are rescanned, and only their files and the summary files are rewritten.
+type Watcher struct {
+	ScanPath   string        // "internal/services"
+	BasePkgUrl string        // "github.com/hashicorp/terraform-provider-azurerm"
+	OutputDir  string        // "./index"
+	Interval   time.Duration // DefaultWatchInterval when zero
+	Scanner    Scanner
+	// Called after each refresh with the changed services and the error of the refresh, if any
+	OnRefresh func(services []string, err error)
+}
+
+// Watch polls the service packages of the scanned index until ctx is done. A failed refresh is reported to OnRefresh
+// and retried on the next change, so a file saved mid-edit doesn't end the watch.
+func (w Watcher) Watch(ctx context.Context, index *TerraformProviderIndex) error {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	fingerprints, err := serviceFingerprints(w.ScanPath)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		current, err := serviceFingerprints(w.ScanPath)
+		if err != nil {
+			return err
+		}
+		changed := changedServices(fingerprints, current)
+		if len(changed) == 0 {
+			continue
+		}
+		fingerprints = current
+
+		err = index.RefreshServices(w.ScanPath, w.BasePkgUrl, changed, w.Scanner, w.OutputDir)
+		if w.OnRefresh != nil {
+			w.OnRefresh(changed, err)
+		}
+	}
+}
+
+// RefreshServices rescans the given services under dir, replaces them in the index and rewrites their files and the
+// summary files in outputDir. Files of Terraform types the services no longer register are removed, and services
+// whose directory was removed are dropped from the index. Output formats other than JSON and single-file indexes are
+// rewritten in full.
+func (index *TerraformProviderIndex) RefreshServices(dir, basePkgUrl string, services []string, scanner Scanner, outputDir string) error {
+	selected := make(map[string]bool)
+	for _, service := range services {
+		selected[service] = true
+	}
+	// Rescanned services are written below, together with the files of the services they replace
+	scanner.Stream = nil
+	rescanned, err := scanTerraformProviderServices(dir, basePkgUrl, index.Version, func(serviceName string) bool {
+		return selected[serviceName]
+	}, scanner, nil)
+	if err != nil {
+		return fmt.Errorf("failed to rescan services %s: %w", strings.Join(services, ", "), err)
+	}
+
+	previous := &TerraformProviderIndex{Output: index.Output}
+	var kept []ServiceRegistration
+	for _, service := range index.Services {
+		if selected[service.ServiceName] {
+			previous.Services = append(previous.Services, service)
+		} else {
+			kept = append(kept, service)
+		}
+	}
+	var warnings []ScanWarning
+	for _, warning := range index.Warnings {
+		if !selected[warning.Service] {
+			warnings = append(warnings, warning)
+		}
+	}
+
+	index.Services = append(kept, rescanned.Services...)
+	sort.Slice(index.Services, func(i, j int) bool {
+		return index.Services[i].ServiceName < index.Services[j].ServiceName
+	})
+	index.Warnings = (&scanWarnings{warnings: append(warnings, rescanned.Warnings...)}).sorted()
+	index.Statistics = buildProviderStatistics(index.Services)
+	index.GlobalMaps = index.BuildGlobalMappings()
+	index.RemovedInNextMajor = index.BuildRemovedInNextMajor()
+	index.buildLookup()
+	if index.Documentation != nil {
+		if _, err := index.LinkDocumentation(filepath.FromSlash(index.Documentation.DocsPath)); err != nil {
+			return err
+		}
+	}
+	if index.Annotations != nil {
+		index.ApplyAnnotations(index.Annotations)
+	}
+	if index.ProductNames != nil {
+		index.ApplyProductNames(index.ProductNames)
+	}
+	if index.GoIndex != nil {
+		if _, err := index.VerifyGoIndexReferences(filepath.FromSlash(index.GoIndex.GoIndexDir), basePkgUrl); err != nil {
+			return err
+		}
+	}
+	if index.APIDrift != nil {
+		if _, err := index.DetectAPIDrift(filepath.FromSlash(index.APIDrift.SpecsDir)); err != nil {
+			return err
+		}
+	}
+	if index.ProviderCoverage != nil {
+		if _, err := index.CheckProviderCoverage(filepath.FromSlash(index.ProviderCoverage.ProviderPath)); err != nil {
+			return err
+		}
+	}
+	if index.ProviderSchema != nil {
+		if _, err := index.ReconcileProviderSchema(filepath.FromSlash(index.ProviderSchema.SchemaFile)); err != nil {
+			return err
+		}
+	}
+
+	if (index.Output.Format != "" && index.Output.Format != OutputFormatJSON) || index.Output.SingleFile {
+		return index.WriteIndexFiles(outputDir, nil)
+	}
+
+	// Rescanned services are written through an index holding only them, documentation links included
+	refreshed := &TerraformProviderIndex{Version: index.Version, Output: index.Output, Events: index.Events, Hooks: index.Hooks}
+	for _, service := range index.Services {
+		if selected[service.ServiceName] {
+			refreshed.Services = append(refreshed.Services, service)
+		}
+	}
+	if err := index.createDirectoryStructure(outputDir); err != nil {
+		return fmt.Errorf("failed to create directory structure: %w", err)
+	}
+	if err := refreshed.WriteDocumentFiles(outputDir, nil); err != nil {
+		return err
+	}
+	if err := refreshed.writeAcceptanceTestFiles(outputDir, nil); err != nil {
+		return fmt.Errorf("failed to write acceptance test files: %w", err)
+	}
+
+	written := refreshed.serviceFilePaths(outputDir)
+	for filePath := range previous.serviceFilePaths(outputDir) {
+		if written[filePath] {
+			continue
+		}
+		if err := index.Output.fs().Remove(filePath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove stale file %s: %w", filePath, err)
+		}
+	}
+
+	return index.writeSummaryFiles(outputDir, nil)
+}
+
+// serviceFilePaths returns the paths of the per-resource document and acceptance test files of the index services
+func (index *TerraformProviderIndex) serviceFilePaths(outputDir string) map[string]bool {
+	paths := make(map[string]bool)
+	for _, document := range index.Documents() {
+		paths[filepath.Join(outputDir, document.Kind, document.TerraformType+".json")] = true
+	}
+	for _, service := range index.Services {
+		for terraformType := range service.ResourceAcceptanceTests {
+			paths[filepath.Join(outputDir, "tests", DocumentKindResource, terraformType+".json")] = true
+		}
+		for terraformType := range service.DataSourceAcceptanceTests {
+			paths[filepath.Join(outputDir, "tests", DocumentKindDataSource, terraformType+".json")] = true
+		}
+		if index.Output.ServiceSummaries {
+			paths[filepath.Join(outputDir, "services", service.ServiceName+".json")] = true
+		}
+	}
+	return paths
+}
+
+// serviceFingerprints fingerprints the Go files of each service directory under dir by name, size and modification
+// time, so edits, new files and removed files all change the fingerprint of their service
+func serviceFingerprints(dir string) (map[string]uint64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read services directory: %w", err)
+	}
+
+	fingerprints := make(map[string]uint64)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		hash := fnv.New64a()
+		serviceDir := filepath.Join(dir, entry.Name())
+		err := filepath.WalkDir(serviceDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				// Files removed while walking show up as changes on the next poll
+				return nil
+			}
+			if d.IsDir() || !strings.HasSuffix(path, ".go") {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			_, _ = fmt.Fprintf(hash, "%s %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk service %s: %w", entry.Name(), err)
+		}
+		fingerprints[entry.Name()] = hash.Sum64()
+	}
+	return fingerprints, nil
+}
+
+// changedServices returns the services added, removed or changed between two fingerprints, sorted by name
+func changedServices(before, after map[string]uint64) []string {
+	var changed []string
+	for service, fingerprint := range after {
+		if previous, exists := before[service]; !exists || previous != fingerprint {
+			changed = append(changed, service)
+		}
+	}
+	for service := range before {
+		if _, exists := after[service]; !exists {
+			changed = append(changed, service)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -0,0 +1,144 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceFingerprints(t *testing.T) {
+	dir := t.TempDir()
+	writeTestPackage(t, dir, "keyvault", "registration.go", "package keyvault\n")
+	writeTestPackage(t, dir, "storage", "registration.go", "package storage\n")
+	writeTestPackage(t, dir, "storage", "README.md", "storage\n")
+
+	before, err := serviceFingerprints(dir)
+	require.NoError(t, err)
+	assert.Len(t, before, 2)
+
+	// Non-Go files don't change the fingerprint
+	writeTestPackage(t, dir, "storage", "README.md", "storage service\n")
+	unchanged, err := serviceFingerprints(dir)
+	require.NoError(t, err)
+	assert.Empty(t, changedServices(before, unchanged))
+
+	writeTestPackage(t, dir, "keyvault/validate", "name.go", "package validate\n")
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "storage")))
+	writeTestPackage(t, dir, "network", "registration.go", "package network\n")
+
+	after, err := serviceFingerprints(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"keyvault", "network", "storage"}, changedServices(before, after))
+}
+
+func TestTerraformProviderIndex_RefreshServices(t *testing.T) {
+	testHarnessPath := filepath.Join("testharness", "internal", "services")
+	basePkgUrl := "github.com/lonegunmanb/terraform-provider-azurerm-index"
+	index, err := ScanTerraformProviderServices(testHarnessPath, basePkgUrl, "test-version", nil)
+	require.NoError(t, err)
+
+	fs := afero.NewMemMapFs()
+	index.Output.Fs = fs
+	outputDir := "/test/output"
+
+	// A resource keyvault no longer registers, as if it was removed from the source since the last scan
+	for i := range index.Services {
+		if index.Services[i].ServiceName == "keyvault" {
+			index.Services[i].SupportedResources["azurerm_removed_vault"] = "resourceRemovedVault"
+		}
+	}
+	index.Statistics = buildProviderStatistics(index.Services)
+	require.NoError(t, index.WriteIndexFiles(outputDir, nil))
+	stalePath := filepath.Join(outputDir, "resources", "azurerm_removed_vault.json")
+	exists, err := afero.Exists(fs, stalePath)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	// Files of other services are left alone
+	storagePath := filepath.Join(outputDir, "resources", "azurerm_storage_account.json")
+	require.NoError(t, afero.WriteFile(fs, storagePath, []byte("untouched"), 0644))
+	keyVaultPath := filepath.Join(outputDir, "resources", "azurerm_key_vault.json")
+	require.NoError(t, afero.WriteFile(fs, keyVaultPath, []byte("outdated"), 0644))
+	totalResources := index.Statistics.TotalResources
+
+	require.NoError(t, index.RefreshServices(testHarnessPath, basePkgUrl, []string{"keyvault"}, Scanner{}, outputDir))
+
+	exists, err = afero.Exists(fs, stalePath)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	content, err := afero.ReadFile(fs, storagePath)
+	require.NoError(t, err)
+	assert.Equal(t, "untouched", string(content))
+	content, err = afero.ReadFile(fs, keyVaultPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"terraform_type": "azurerm_key_vault"`)
+
+	assert.Equal(t, totalResources-1, index.Statistics.TotalResources)
+	_, stillMapped := index.GlobalMaps.AllResources["azurerm_removed_vault"]
+	assert.False(t, stillMapped)
+	mainIndex, err := afero.ReadFile(fs, filepath.Join(outputDir, index.Output.MainIndexFileName()))
+	require.NoError(t, err)
+	assert.NotContains(t, string(mainIndex), "azurerm_removed_vault")
+}
+
+func TestTerraformProviderIndex_RefreshServices_Hooks(t *testing.T) {
+	testHarnessPath := filepath.Join("testharness", "internal", "services")
+	basePkgUrl := "github.com/lonegunmanb/terraform-provider-azurerm-index"
+	var mu sync.Mutex
+	written := make(map[string]bool)
+	scanner := Scanner{Hooks: DocumentHooks{
+		OnResourceDiscovered: func(document *IndexDocument) bool {
+			if resource, ok := document.Content.(TerraformResource); ok {
+				resource.XAnnotations = map[string]interface{}{"price": "per hour"}
+				document.Content = resource
+			}
+			return true
+		},
+		OnResourceWritten: func(document IndexDocument, path string) {
+			mu.Lock()
+			defer mu.Unlock()
+			written[path] = true
+		},
+	}}
+	index, err := scanner.Scan(testHarnessPath, basePkgUrl, "test-version", nil)
+	require.NoError(t, err)
+
+	fs := afero.NewMemMapFs()
+	index.Output.Fs = fs
+	outputDir := "/test/output"
+	require.NoError(t, index.WriteIndexFiles(outputDir, nil))
+	keyVaultPath := filepath.Join(outputDir, "resources", "azurerm_key_vault.json")
+	require.NoError(t, afero.WriteFile(fs, keyVaultPath, []byte("outdated"), 0644))
+	written = make(map[string]bool)
+
+	require.NoError(t, index.RefreshServices(testHarnessPath, basePkgUrl, []string{"keyvault"}, scanner, outputDir))
+
+	assert.True(t, written[keyVaultPath], "OnResourceWritten is called with the rewritten documents")
+	assert.False(t, written[filepath.Join(outputDir, "resources", "azurerm_storage_account.json")], "documents of other services aren't rewritten")
+	content, err := afero.ReadFile(fs, keyVaultPath)
+	require.NoError(t, err)
+	var document TerraformResource
+	require.NoError(t, json.Unmarshal(content, &document))
+	assert.Equal(t, map[string]interface{}{"price": "per hour"}, document.XAnnotations, "rewritten documents are enriched by OnResourceDiscovered")
+}
+
+func TestWatcher_Watch_StopsWithContext(t *testing.T) {
+	dir := t.TempDir()
+	writeTestPackage(t, dir, "keyvault", "registration.go", "package keyvault\n")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	refreshed := false
+	watcher := Watcher{ScanPath: dir, Interval: 10 * time.Millisecond, OnRefresh: func([]string, error) { refreshed = true }}
+
+	require.NoError(t, watcher.Watch(ctx, &TerraformProviderIndex{}))
+	assert.False(t, refreshed)
+}
@@ -0,0 +1,57 @@
+package pkg
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// WriteOnlyReference lists the write-only attributes of a resource, the attributes holding secrets Terraform never
+// persists to the plan or state
+type WriteOnlyReference struct {
+	ID            string   `json:"id"`             // "azurerm/resources/azurerm_key_vault_secret/legacy_pluginsdk", see EntryID
+	TerraformType string   `json:"terraform_type"` // "azurerm_key_vault_secret"
+	Attributes    []string `json:"attributes"`     // ["value_wo"]
+}
+
+// writeOnlyAttributePaths returns the paths of the write-only attributes of a schema, attributes of nested blocks are
+// prefixed with the names of their blocks: "site_config.application_stack.docker_registry_password_wo"
+func writeOnlyAttributePaths(attributes []SchemaAttribute) []string {
+	var paths []string
+	for _, attribute := range attributes {
+		if attribute.WriteOnly {
+			paths = append(paths, attribute.Name)
+		}
+		for _, path := range writeOnlyAttributePaths(attribute.Block) {
+			paths = append(paths, attribute.Name+"."+path)
+		}
+	}
+	return paths
+}
+
+// BuildWriteOnlyIndex lists the resources declaring write-only attributes, sorted by Terraform type
+func (index *TerraformProviderIndex) BuildWriteOnlyIndex() []WriteOnlyReference {
+	references := []WriteOnlyReference{}
+	for _, service := range index.Services {
+		for terraformType, attributes := range service.ResourceSchemas {
+			paths := writeOnlyAttributePaths(attributes)
+			if len(paths) == 0 {
+				continue
+			}
+			references = append(references, WriteOnlyReference{
+				ID:            EntryID(DocumentKindResource, terraformType, service.resourceSDKType(terraformType)),
+				TerraformType: terraformType,
+				Attributes:    paths,
+			})
+		}
+	}
+
+	sort.Slice(references, func(i, j int) bool {
+		return references[i].TerraformType < references[j].TerraformType
+	})
+	return references
+}
+
+// writeWriteOnlyIndexFile writes write_only_attributes.json, the write-only attributes of all resources
+func (index *TerraformProviderIndex) writeWriteOnlyIndexFile(outputDir string) error {
+	return index.writeJSONFile(filepath.Join(outputDir, "write_only_attributes.json"), index.BuildWriteOnlyIndex())
+}
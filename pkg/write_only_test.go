@@ -0,0 +1,130 @@
+package pkg
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractLegacyResourceSchemaFromPackage_WriteOnly(t *testing.T) {
+	source := `package keyvault
+
+func resourceKeyVaultSecret() *pluginsdk.Resource {
+	return &pluginsdk.Resource{
+		Schema: map[string]*pluginsdk.Schema{
+			"value": {
+				Type:      pluginsdk.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+
+			"value_wo": {
+				Type:      pluginsdk.TypeString,
+				Optional:  true,
+				WriteOnly: true,
+			},
+
+			"registry": {
+				Type:     pluginsdk.TypeList,
+				Optional: true,
+				Elem: &pluginsdk.Resource{
+					Schema: map[string]*pluginsdk.Schema{
+						"password_wo": {
+							Type:      pluginsdk.TypeString,
+							Optional:  true,
+							WriteOnly: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+	result := extractLegacyResourceSchemaFromPackage("resourceKeyVaultSecret", packageInfo)
+
+	assert.Equal(t, []SchemaAttribute{
+		{Name: "value", Type: "TypeString", Optional: true, Sensitive: true},
+		{Name: "value_wo", Type: "TypeString", Optional: true, WriteOnly: true},
+		{Name: "registry", Type: "TypeList", Optional: true, Block: []SchemaAttribute{
+			{Name: "password_wo", Type: "TypeString", Optional: true, Depth: 1, WriteOnly: true},
+		}},
+	}, result)
+	assert.Equal(t, []string{"value_wo", "registry.password_wo"}, writeOnlyAttributePaths(result))
+}
+
+func TestExtractTypedResourceSchemaFromPackage_FrameworkWriteOnly(t *testing.T) {
+	source := `package keyvault
+
+type KeyVaultSecretResource struct{}
+
+func (r *KeyVaultSecretResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required: true,
+			},
+			"value_wo": schema.StringAttribute{
+				Optional:  true,
+				WriteOnly: true,
+			},
+		},
+	}
+}`
+
+	packageInfo := parsePackageInfo(t, source)
+	result := extractTypedResourceSchemaFromPackage("KeyVaultSecretResource", packageInfo)
+
+	assert.Equal(t, []SchemaAttribute{
+		{Name: "name", Required: true},
+		{Name: "value_wo", Optional: true, WriteOnly: true},
+	}, result)
+}
+
+func TestTerraformProviderIndex_WriteWriteOnlyIndexFile(t *testing.T) {
+	index := &TerraformProviderIndex{
+		Services: []ServiceRegistration{
+			{
+				SupportedResources: map[string]string{"azurerm_key_vault_secret": "resourceKeyVaultSecret"},
+				ResourceSchemas: map[string][]SchemaAttribute{
+					"azurerm_key_vault_secret": {
+						{Name: "name"},
+						{Name: "value_wo", WriteOnly: true},
+					},
+					"azurerm_key_vault": {
+						{Name: "name"},
+					},
+				},
+			},
+			{
+				ResourceSchemas: map[string][]SchemaAttribute{
+					"azurerm_container_registry_task": {
+						{Name: "docker_step", Block: []SchemaAttribute{
+							{Name: "password_wo", Depth: 1, WriteOnly: true},
+						}},
+					},
+				},
+			},
+		},
+	}
+	fs := afero.NewMemMapFs()
+	index.Output.Fs = fs
+	outputDir := "/test/output"
+
+	require.NoError(t, index.writeWriteOnlyIndexFile(outputDir))
+
+	data, err := afero.ReadFile(fs, filepath.Join(outputDir, "write_only_attributes.json"))
+	require.NoError(t, err)
+	var references []WriteOnlyReference
+	require.NoError(t, json.Unmarshal(data, &references))
+
+	assert.Equal(t, []WriteOnlyReference{
+		{ID: "azurerm/resources/azurerm_container_registry_task/modern_sdk", TerraformType: "azurerm_container_registry_task", Attributes: []string{"docker_step.password_wo"}},
+		{ID: "azurerm/resources/azurerm_key_vault_secret/legacy_pluginsdk", TerraformType: "azurerm_key_vault_secret", Attributes: []string{"value_wo"}},
+	}, references)
+}